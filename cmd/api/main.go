@@ -1,19 +1,43 @@
 package main
 
 import (
+	"context"
+	"encoding/base64"
 	"log"
+	"maps"
 	"net/http"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/acoustid"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/deezer"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/hooks"
 	handler "github.com/jpp0ca/MusicMigration-API/internal/adapters/http"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/httpdebug"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/lastfm"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/localfiles"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/m3u"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/musicbrainz"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/plugin"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/postgres"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/sandbox"
 	"github.com/jpp0ca/MusicMigration-API/internal/adapters/spotify"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/sqlite"
 	"github.com/jpp0ca/MusicMigration-API/internal/adapters/youtube"
 	"github.com/jpp0ca/MusicMigration-API/internal/app"
+	"github.com/jpp0ca/MusicMigration-API/internal/cleaning"
 	"github.com/jpp0ca/MusicMigration-API/internal/config"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
 
 	_ "github.com/jpp0ca/MusicMigration-API/docs"
 )
@@ -33,25 +57,418 @@ import (
 // @in							header
 // @name						Authorization
 // @description				Bearer token for the streaming provider (e.g. "Bearer your_token_here")
+
+// @securityDefinitions.apikey	APIKeyAuth
+// @in							header
+// @name						X-API-Key
+// @description				Account API key, required on /api/v1 routes when AUTH_ENABLED=true
+
+// @securityDefinitions.apikey	AdminKeyAuth
+// @in							header
+// @name						X-Admin-Key
+// @description				Admin key for /admin routes, set with ADMIN_API_KEY
 func main() {
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 
-	// Create provider adapters
-	httpClient := &http.Client{}
-	spotifyProvider := spotify.NewProvider(httpClient)
-	youtubeProvider := youtube.NewProvider(httpClient)
+	// Storage backend
+	var (
+		migrationStore ports.MigrationStore      = memory.NewMigrationStore()
+		accountStore   ports.AccountStore        = memory.NewAccountStore()
+		tokenStore     ports.TokenStore          = memory.NewTokenStore()
+		mappingStore   ports.TrackMappingStore   = memory.NewTrackMappingStore()
+		feedbackStore  ports.MatchFeedbackStore  = memory.NewMatchFeedbackStore()
+		jobQueue       ports.JobQueue            = memory.NewJobQueue()
+		locker         ports.Locker              = memory.NewLocker()
+		searchCache    ports.SearchCache         = memory.NewSearchCache()
+		profileStore   ports.ProfileStore        = memory.NewProfileStore()
+		auditLog       ports.AuditLog            = memory.NewAuditLog()
+		goldenStore    ports.GoldenMatchStore    = memory.NewGoldenMatchStore()
+		startStore     ports.MigrationStartStore = memory.NewMigrationStartStore()
+	)
+	switch cfg.StorageDriver {
+	case "memory":
+	case "sqlite":
+		db, err := sqlite.Open(cfg.SQLitePath)
+		if err != nil {
+			log.Fatalf("Failed to open SQLite database: %v", err)
+		}
+		defer db.Close()
+		migrationStore = sqlite.NewMigrationStore(db)
+		accountStore = sqlite.NewAccountStore(db)
+		tokenStore = sqlite.NewTokenStore(db)
+		mappingStore = sqlite.NewTrackMappingStore(db)
+		feedbackStore = sqlite.NewMatchFeedbackStore(db)
+		goldenStore = sqlite.NewGoldenMatchStore(db)
+		jobQueue = sqlite.NewJobQueue(db)
+		locker = sqlite.NewLocker(db)
+		searchCache = sqlite.NewSearchCache(db)
+		profileStore = sqlite.NewProfileStore(db)
+		auditLog = sqlite.NewAuditLog(db)
+		startStore = sqlite.NewMigrationStartStore(db)
+	case "postgres":
+		if cfg.PostgresDSN == "" {
+			log.Fatal("STORAGE_DRIVER=postgres requires POSTGRES_DSN")
+		}
+		db, err := postgres.Open(cfg.PostgresDSN)
+		if err != nil {
+			log.Fatalf("Failed to open PostgreSQL database: %v", err)
+		}
+		defer db.Close()
+		migrationStore = postgres.NewMigrationStore(db)
+		accountStore = postgres.NewAccountStore(db)
+		tokenStore = postgres.NewTokenStore(db)
+		mappingStore = postgres.NewTrackMappingStore(db)
+		feedbackStore = postgres.NewMatchFeedbackStore(db)
+		goldenStore = postgres.NewGoldenMatchStore(db)
+		jobQueue = postgres.NewJobQueue(db)
+		locker = postgres.NewLocker(db)
+		searchCache = postgres.NewSearchCache(db)
+		profileStore = postgres.NewProfileStore(db)
+		auditLog = postgres.NewAuditLog(db)
+		startStore = postgres.NewMigrationStartStore(db)
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q (expected memory, sqlite or postgres)", cfg.StorageDriver)
+	}
+
+	// Create provider adapters, each with an HTTP client and connection pool
+	// of its own. Provider traffic of debug jobs is recorded by the transport.
+	providerHTTPClients := make(map[string]adapters.HTTPClientConfig, len(cfg.ProviderHTTPClients))
+	for name, c := range cfg.ProviderHTTPClients {
+		providerHTTPClients[name] = adapters.HTTPClientConfig(c)
+	}
+	clients, err := adapters.NewHTTPClientFactory(adapters.HTTPClientConfig(cfg.HTTPClient), providerHTTPClients)
+	if err != nil {
+		log.Fatalf("Invalid HTTP client configuration: %v", err)
+	}
+	if cfg.HTTPClient.InsecureSkipVerify {
+		log.Println("WARNING: TLS certificates of provider APIs are not verified (HTTP_INSECURE_SKIP_VERIFY)")
+	}
+	if cfg.HTTPClient.Proxy != "" {
+		log.Printf("Provider traffic goes through proxy %s", sanitizeProxy(cfg.HTTPClient.Proxy))
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.ProviderHTTPClients)) {
+		c := cfg.ProviderHTTPClients[name]
+		if c.InsecureSkipVerify && !cfg.HTTPClient.InsecureSkipVerify {
+			log.Printf("WARNING: TLS certificates of the %s API are not verified (http_client.providers.%s.insecure_skip_verify)", name, name)
+		}
+		if c.Proxy != "" {
+			log.Printf("%s traffic goes through proxy %s", name, sanitizeProxy(c.Proxy))
+		}
+	}
+	httpClient := func(provider string) *http.Client {
+		client := clients.Client(provider)
+		client.Transport = httpdebug.NewTransport(client.Transport)
+		return client
+	}
+	spotifyOpts := []spotify.Option{
+		spotify.WithPageConcurrency(cfg.SpotifyPageConcurrency),
+		spotify.WithAddInterval(cfg.SpotifyAddInterval),
+	}
+	if cfg.SpotifyClientID != "" && cfg.SpotifyClientSecret != "" {
+		spotifyOpts = append(spotifyOpts, spotify.WithClientCredentials(cfg.SpotifyClientID, cfg.SpotifyClientSecret))
+		log.Println("Spotify searches use client-credentials token")
+	}
+	if cfg.SpotifyRedirectURL != "" {
+		spotifyOpts = append(spotifyOpts, spotify.WithRedirectURL(cfg.SpotifyRedirectURL))
+	}
+	spotifyProvider := spotify.NewProvider(httpClient("spotify"), spotifyOpts...)
+	titleCleaner := cleaning.Default()
+	if cfg.TitleRulesFile != "" {
+		var err error
+		if titleCleaner, err = cleaning.LoadFile(cfg.TitleRulesFile); err != nil {
+			log.Fatalf("Failed to load title rules: %v", err)
+		}
+	}
+	youtubeOpts := []youtube.Option{
+		youtube.WithTitleCleaner(titleCleaner),
+		youtube.WithAddInterval(cfg.YouTubeAddInterval),
+	}
+	if cfg.GoogleClientID != "" {
+		youtubeOpts = append(youtubeOpts, youtube.WithOAuthClient(cfg.GoogleClientID, cfg.GoogleClientSecret))
+	}
+	if cfg.GoogleRedirectURL != "" {
+		youtubeOpts = append(youtubeOpts, youtube.WithRedirectURL(cfg.GoogleRedirectURL))
+	}
+	if cfg.YouTubeSearchCacheTTL > 0 {
+		youtubeOpts = append(youtubeOpts, youtube.WithSearchCache(searchCache, cfg.YouTubeSearchCacheTTL))
+		if cfg.YouTubeVerifyCachedSearches {
+			youtubeOpts = append(youtubeOpts, youtube.WithOEmbedVerification())
+		}
+		log.Printf("YouTube searches are cached for %s", cfg.YouTubeSearchCacheTTL)
+	}
+	switch cfg.YouTubeBlocklistMode {
+	case "off":
+		youtubeOpts = append(youtubeOpts, youtube.WithBlocklist(nil, ""))
+	default:
+		terms := cfg.YouTubeBlocklist
+		if len(terms) == 0 {
+			terms = youtube.DefaultBlocklist
+		}
+		youtubeOpts = append(youtubeOpts, youtube.WithBlocklist(terms, youtube.BlockMode(cfg.YouTubeBlocklistMode)))
+	}
+	youtubeProvider := youtube.NewProvider(httpClient("youtube"), youtubeOpts...)
 
-	// Register providers
+	// Register providers, skipping those left out of ENABLED_PROVIDERS
 	registry := adapters.NewProviderRegistry()
-	registry.Register(spotifyProvider)
-	registry.Register(youtubeProvider)
+	register := func(p ports.MusicProvider) bool {
+		if !cfg.ProviderEnabled(p.Name()) {
+			log.Printf("Provider %q is not in ENABLED_PROVIDERS; skipping", p.Name())
+			return false
+		}
+		registry.Register(p)
+		return true
+	}
+	register(spotifyProvider)
+	register(youtubeProvider)
+
+	if cfg.SandboxProvider && register(sandbox.NewProvider(sandbox.WithFailureRate(cfg.SandboxFailureRate))) {
+		log.Printf("Registered sandbox provider (failure rate %.2f)", cfg.SandboxFailureRate)
+	}
+
+	if cfg.LocalLibraryDir != "" && register(localfiles.NewProvider(cfg.LocalLibraryDir)) {
+		log.Printf("Registered localfiles provider for %s", cfg.LocalLibraryDir)
+	}
+
+	if cfg.LastFMAPIKey != "" && register(lastfm.NewProvider(httpClient(lastfm.ProviderName), cfg.LastFMAPIKey)) {
+		log.Println("Registered lastfm provider")
+	}
+
+	// The M3U importer only serves uploads while the m3u provider they are
+	// migrated from is registered.
+	var m3uProvider *m3u.Provider
+	if cfg.ProviderEnabled(m3u.ProviderName) {
+		var m3uOpts []m3u.Option
+		if cfg.M3UDir != "" {
+			m3uOpts = append(m3uOpts, m3u.WithDirectory(cfg.M3UDir))
+			log.Printf("Serving M3U playlists from %s", cfg.M3UDir)
+		}
+		m3uProvider = m3u.NewProvider(m3uOpts...)
+		register(m3uProvider)
+	} else {
+		log.Printf("Provider %q is not in ENABLED_PROVIDERS; skipping", m3u.ProviderName)
+	}
+
+	// Provider plugins (external processes). A plugin only tells its name
+	// once started, so one left out of ENABLED_PROVIDERS is stopped again
+	// right after the handshake.
+	for _, path := range cfg.Plugins {
+		p, err := plugin.Start(path)
+		if err != nil {
+			log.Fatalf("Failed to start plugin: %v", err)
+		}
+		if !cfg.ProviderEnabled(p.Name()) {
+			log.Printf("Plugin provider %q from %s is not in ENABLED_PROVIDERS; stopping it", p.Name(), path)
+			p.Close()
+			continue
+		}
+		defer p.Close()
+		if _, err := registry.Get(p.Name()); err == nil {
+			log.Printf("Plugin %s replaces built-in provider %q", path, p.Name())
+		}
+		register(p)
+		log.Printf("Registered plugin provider %q from %s", p.Name(), path)
+	}
+	for _, name := range cfg.EnabledProviders {
+		if _, err := registry.Get(strings.ToLower(name)); err != nil {
+			log.Printf("ENABLED_PROVIDERS lists %q, which is not configured", name)
+		}
+	}
+
+	// Quota budgets for providers with unit-based API quotas
+	quota := app.NewQuotaTracker(map[string]int{
+		youtubeProvider.Name(): cfg.YouTubeDailyQuota,
+	}, cfg.QuotaEnforce)
+	serviceOpts := []app.Option{
+		app.WithQuotaTracker(quota),
+		app.WithMigrationStore(migrationStore),
+		app.WithLocker(locker),
+		app.WithAuditLog(auditLog),
+		app.WithTimeouts(app.Timeouts{
+			Search:    cfg.SearchTimeout,
+			Fetch:     cfg.FetchTimeout,
+			Create:    cfg.CreateTimeout,
+			Add:       cfg.AddTimeout,
+			Migration: cfg.MigrationTimeout,
+		}),
+	}
+
+	// Metadata enrichment of source tracks (optional)
+	if len(cfg.MetadataSources) > 0 {
+		enrichment := app.MetadataEnrichment{Timeout: cfg.MetadataTimeout}
+		for _, name := range cfg.MetadataSources {
+			switch strings.ToLower(name) {
+			case musicbrainz.SourceName:
+				enrichment.Sources = append(enrichment.Sources, musicbrainz.NewSource(httpClient(musicbrainz.SourceName)))
+			case deezer.SourceName:
+				enrichment.Sources = append(enrichment.Sources, deezer.NewSource(httpClient(deezer.SourceName)))
+			}
+		}
+		if cfg.MetadataCacheTTL > 0 {
+			enrichment.Cache, enrichment.CacheTTL = searchCache, cfg.MetadataCacheTTL
+		}
+		serviceOpts = append(serviceOpts, app.WithMetadataEnrichment(enrichment))
+		log.Printf("Source track metadata is filled in from %s", strings.Join(cfg.MetadataSources, ", "))
+	}
+
+	// Fingerprint fallback for tracks not found (optional)
+	if cfg.AcoustIDAPIKey != "" {
+		fpcalc, err := exec.LookPath(cfg.FpcalcPath)
+		if err != nil {
+			log.Fatalf("ACOUSTID_API_KEY is set but fpcalc was not found: %v", err)
+		}
+		serviceOpts = append(serviceOpts, app.WithFingerprinter(acoustid.NewIdentifier(httpClient("acoustid"), cfg.AcoustIDAPIKey, fpcalc)))
+		log.Printf("Tracks not found are identified by AcoustID fingerprint using %s", fpcalc)
+	}
+
+	// Users' verdicts on matches adjust later migrations. Matches confirmed
+	// by enough accounts are reused as track mappings when those are
+	// enabled.
+	serviceOpts = append(serviceOpts, app.WithMatchFeedback(feedbackStore))
+	var feedbackMappings ports.TrackMappingStore
+	if cfg.TrackMappings {
+		serviceOpts = append(serviceOpts, app.WithTrackMappings(mappingStore))
+		feedbackMappings = mappingStore
+	}
+
+	// Post-migration hooks (optional)
+	var hooksList []ports.MigrationHook
+	if cfg.HookWebhookURL != "" {
+		hooksList = append(hooksList, hooks.NewWebhook(clients.Client("hooks"), cfg.HookWebhookURL, cfg.HookWebhookSecret))
+	}
+	if cfg.HookNotifyURL != "" {
+		hooksList = append(hooksList, hooks.NewNotifier(clients.Client("hooks"), cfg.HookNotifyURL))
+	}
+	if cfg.HookSlackURL != "" {
+		hooksList = append(hooksList, hooks.NewSlack(clients.Client("hooks"), cfg.HookSlackURL, cfg.PublicURL))
+	}
+	if cfg.HookDiscordURL != "" {
+		hooksList = append(hooksList, hooks.NewDiscord(clients.Client("hooks"), cfg.HookDiscordURL, cfg.PublicURL))
+	}
+	if cfg.HookListenBrainzToken != "" {
+		hooksList = append(hooksList, hooks.NewListenBrainz(clients.Client("hooks"), cfg.HookListenBrainzToken))
+	}
+	for _, h := range hooksList {
+		log.Printf("Enabled %s post-migration hook", h.Name())
+	}
+	serviceOpts = append(serviceOpts, app.WithHooks(hooksList...))
+
+	// Accounts and token vault (optional)
+	handlerOpts := []handler.Option{
+		handler.WithProviders(registry.Available()),
+		handler.WithMatchFeedbackService(app.NewMatchFeedbackService(feedbackStore, migrationStore, feedbackMappings, goldenStore, cfg.TrackMappingQuorum)),
+	}
+	if m3uProvider != nil {
+		handlerOpts = append(handlerOpts, handler.WithPlaylistImporter(m3uProvider))
+	}
+	// Provider tokens stored in the vault and with queued jobs are
+	// encrypted with TOKEN_ENCRYPTION_KEY.
+	var vault *app.TokenVault
+	var tokenKey []byte
+	if cfg.TokenEncryptionKey != "" {
+		var err error
+		if tokenKey, err = base64.StdEncoding.DecodeString(cfg.TokenEncryptionKey); err != nil {
+			log.Fatalf("Invalid TOKEN_ENCRYPTION_KEY: %v", err)
+		}
+		if vault, err = app.NewTokenVault(tokenStore, tokenKey); err != nil {
+			log.Fatalf("Failed to create token vault: %v", err)
+		}
+	}
+
+	var limitService *app.LimitService
+	if cfg.AuthEnabled {
+		accountService := app.NewAccountService(accountStore)
+		handlerOpts = append(handlerOpts, handler.WithAccountService(accountService))
+
+		limitService = app.NewLimitService(accountStore, startStore, domain.AccountLimits{
+			MigrationsPerDay:      cfg.AccountMigrationsPerDay,
+			MaxTracksPerMigration: cfg.AccountMaxTracks,
+			ConcurrentJobs:        cfg.AccountConcurrentJobs,
+		})
+		serviceOpts = append(serviceOpts, app.WithLimits(limitService))
+
+		if vault != nil {
+			serviceOpts = append(serviceOpts, app.WithTokenVault(vault))
+			handlerOpts = append(handlerOpts, handler.WithTokenVault(vault),
+				handler.WithConnectionService(app.NewConnectionService(registry, vault)))
+
+			// Stored tokens are issued and refreshed with the server's
+			// OAuth clients, so every enabled provider needs one.
+			for _, c := range []struct{ provider, env, clientID string }{
+				{spotifyProvider.Name(), "SPOTIFY", cfg.SpotifyClientID},
+				{youtubeProvider.Name(), "GOOGLE", cfg.GoogleClientID},
+			} {
+				if cfg.ProviderEnabled(c.provider) && c.clientID == "" {
+					log.Fatalf("%s_CLIENT_ID and %s_CLIENT_SECRET are required to refresh %s tokens in the vault; set them or leave %s out of ENABLED_PROVIDERS",
+						c.env, c.env, c.provider, c.provider)
+				}
+			}
+			if cfg.SpotifyRedirectURL != "" || cfg.GoogleRedirectURL != "" {
+				handlerOpts = append(handlerOpts, handler.WithOAuthService(app.NewOAuthService(registry, vault, tokenKey)))
+			}
+		}
+	}
+
+	if cfg.RateLimitRPS > 0 {
+		handlerOpts = append(handlerOpts, handler.WithRateLimiter(handler.NewRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst)))
+	}
+	if cfg.HealthCheckProviders {
+		handlerOpts = append(handlerOpts, handler.WithHealthChecker(app.NewHealthChecker(registry, 5*time.Second)))
+	}
 
 	// Create application service
-	migrationService := app.NewService(registry, cfg.MigrationWorkers)
+	migrationService := app.NewService(registry, cfg.MigrationWorkers, serviceOpts...)
+
+	handlerOpts = append(handlerOpts, handler.WithProfileService(app.NewProfileService(profileStore, migrationService)))
+
+	// Background migrations. Jobs interrupted by a restart are picked up
+	// again once their lease expires.
+	var jobOpts []app.JobOption
+	switch {
+	case vault != nil:
+		jobOpts = append(jobOpts, app.WithJobTokenVault(vault))
+	case cfg.StorageDriver != "memory":
+		log.Fatalf("STORAGE_DRIVER=%s stores queued jobs: set TOKEN_ENCRYPTION_KEY to encrypt their provider tokens", cfg.StorageDriver)
+	}
+	if cfg.SMTPHost != "" {
+		jobOpts = append(jobOpts, app.WithJobNotifiers(hooks.NewEmail(hooks.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+
+			AllowedDomains: cfg.SMTPAllowedDomains,
+		}, cfg.PublicURL)))
+		log.Printf("Emailing job notifications through %s", cfg.SMTPHost)
+	}
+	jobService := app.NewJobService(migrationService, jobQueue, jobOpts...)
+	handlerOpts = append(handlerOpts, handler.WithJobService(jobService))
+	if cfg.JobWorkers > 0 {
+		go jobService.Run(context.Background(), cfg.JobWorkers)
+	}
 
 	// Setup HTTP server
-	r := gin.Default()
-	h := handler.NewHandler(migrationService)
+
+	// Gin's default logger and recovery print URLs and headers as sent,
+	// which can carry API keys and tokens.
+	r := gin.New()
+	r.Use(handler.AccessLog(nil), handler.Recovery(nil))
+	if cfg.GzipResponses {
+		r.Use(handler.Gzip)
+	}
+	if cfg.AdminAPIKey != "" {
+		handlerOpts = append(handlerOpts, handler.WithProviderAdmin(registry, cfg.AdminAPIKey),
+			handler.WithAuditLog(auditLog),
+			handler.WithGoldenDatasetService(app.NewGoldenDatasetService(goldenStore)))
+		if limitService != nil {
+			handlerOpts = append(handlerOpts, handler.WithLimitService(limitService))
+		}
+	}
+
+	h := handler.NewHandler(migrationService, handlerOpts...)
 	h.RegisterRoutes(r)
 
 	// Swagger UI
@@ -60,6 +477,11 @@ func main() {
 	addr := ":" + cfg.Port
 	log.Printf("Starting MusicMigration API on %s", addr)
 	log.Printf("Workers: %d", cfg.MigrationWorkers)
+	log.Printf("Job workers: %d", cfg.JobWorkers)
+	log.Printf("API key authentication: %t", cfg.AuthEnabled)
+	if cfg.RateLimitRPS > 0 {
+		log.Printf("Rate limit: %.2f req/s per client (burst %d)", cfg.RateLimitRPS, cfg.RateLimitBurst)
+	}
 	log.Printf("Registered providers: %v", registry.Available())
 	log.Printf("Swagger UI: http://localhost%s/swagger/index.html", addr)
 
@@ -67,3 +489,13 @@ func main() {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// sanitizeProxy returns the proxy URL raw without the credentials it may
+// carry, for logging. The URL was validated by the HTTP client factory.
+func sanitizeProxy(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "(invalid URL)"
+	}
+	return httpdebug.SanitizeURL(u)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/cleaning"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/pkg/matching"
+)
+
+// matcher replays the choice a migration makes among the candidates of a
+// search: it scores them, takes the best and accepts it if it reaches the
+// threshold and the strategy confirms it.
+type matcher struct {
+	track, episode matching.Scorer
+	threshold      float64
+	strategy       domain.MatchingStrategy
+
+	// cleaner, if set, cleans candidate names before scoring, as the
+	// providers scoring free-text video titles do.
+	cleaner *cleaning.Cleaner
+}
+
+// choose returns the candidate m matches source to and its score, or nil if
+// none is accepted.
+func (m matcher) choose(source domain.Track, candidates []domain.Track) (*domain.Track, float64) {
+	scorer := m.track
+	if source.IsEpisode() {
+		scorer = m.episode
+	}
+	scored := make([]matching.Track, len(candidates))
+	for i, c := range candidates {
+		if m.cleaner != nil {
+			c.Name = m.cleaner.Clean(c.Name)
+		}
+		scored[i] = adapters.MatchTrack(c)
+	}
+	best, score := matching.BestCandidate(scorer, adapters.MatchTrack(source), scored)
+	if best < 0 || score < m.threshold || !m.strategy.Confirms(source, candidates[best]) {
+		return nil, score
+	}
+	return &candidates[best], score
+}
+
+// outcome classifies the match the benchmark chose for a case against its
+// expected answer.
+type outcome int
+
+const (
+	correctMatch   outcome = iota // the expected candidate
+	correctNoMatch                // no candidate, and none was expected
+	wrongMatch                    // another candidate than the expected one
+	falseMatch                    // a candidate, though none was expected
+	missedMatch                   // no candidate, though one was expected
+)
+
+// miss is a case the benchmark got wrong.
+type miss struct {
+	Source   domain.Track  `json:"source"`
+	Expected string        `json:"expected_id"`
+	Chosen   *domain.Track `json:"chosen,omitempty"`
+	Score    float64       `json:"score"`
+	Outcome  string        `json:"outcome"`
+}
+
+var outcomeNames = map[outcome]string{
+	wrongMatch:  "wrong_match",
+	falseMatch:  "false_match",
+	missedMatch: "missed_match",
+}
+
+// report sums up a benchmark run.
+type report struct {
+	Cases   int `json:"cases"`
+	Skipped int `json:"skipped"`
+
+	CorrectMatches   int `json:"correct_matches"`
+	CorrectNoMatches int `json:"correct_no_matches"`
+	WrongMatches     int `json:"wrong_matches"`
+	FalseMatches     int `json:"false_matches"`
+	MissedMatches    int `json:"missed_matches"`
+
+	// Accuracy is the share of cases answered right. Precision is the
+	// share of chosen matches that are right, and Recall the share of
+	// expected matches that were chosen.
+	Accuracy  float64 `json:"accuracy"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+
+	Misses []miss `json:"misses,omitempty"`
+}
+
+// run replays every case through m. Cases without an expected answer or
+// without candidates to choose from are skipped.
+func run(m matcher, cases []benchCase) report {
+	var r report
+	for _, c := range cases {
+		expected, ok := c.expected()
+		candidates := c.candidates()
+		if !ok || len(candidates) == 0 {
+			r.Skipped++
+			continue
+		}
+		r.Cases++
+
+		chosen, score := m.choose(c.SourceTrack, candidates)
+		var o outcome
+		switch {
+		case chosen == nil && expected == "":
+			o = correctNoMatch
+		case chosen == nil:
+			o = missedMatch
+		case expected == "":
+			o = falseMatch
+		case chosen.ExternalID == expected:
+			o = correctMatch
+		default:
+			o = wrongMatch
+		}
+		r.add(o)
+		if name, failed := outcomeNames[o]; failed {
+			r.Misses = append(r.Misses, miss{Source: c.SourceTrack, Expected: expected, Chosen: chosen, Score: score, Outcome: name})
+		}
+	}
+
+	chosen := r.CorrectMatches + r.WrongMatches + r.FalseMatches
+	expected := r.CorrectMatches + r.WrongMatches + r.MissedMatches
+	r.Accuracy = ratio(r.CorrectMatches+r.CorrectNoMatches, r.Cases)
+	r.Precision = ratio(r.CorrectMatches, chosen)
+	r.Recall = ratio(r.CorrectMatches, expected)
+	return r
+}
+
+func (r *report) add(o outcome) {
+	switch o {
+	case correctMatch:
+		r.CorrectMatches++
+	case correctNoMatch:
+		r.CorrectNoMatches++
+	case wrongMatch:
+		r.WrongMatches++
+	case falseMatch:
+		r.FalseMatches++
+	case missedMatch:
+		r.MissedMatches++
+	}
+}
+
+// ratio returns n/total, or 0 if total is 0.
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
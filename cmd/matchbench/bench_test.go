@@ -0,0 +1,147 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dataset holds one case per outcome, one case without an answer and one
+// without candidates, as streamed track results and a migration result.
+const dataset = `
+{"source":{"name":"Bohemian Rhapsody","artists":["Queen"]},"status":"matched","matched":{"name":"Bohemian Rhapsody","artists":["Queen"],"external_id":"right"}}
+{"source":{"name":"Bohemian Rhapsody","artists":["Queen"]},"status":"not_found","candidates":[{"track":{"name":"Toxic","artists":["Britney Spears"],"external_id":"toxic"}}]}
+{"source":{"name":"Yesterday","artists":["The Beatles"]},"status":"matched","matched":{"name":"Yesterday","artists":["The Beatles"],"external_id":"exact"},"candidates":[{"track":{"name":"Yesterday (Live)","artists":["The Beatles"],"external_id":"live"}}],"expected_id":"live"}
+{"id":"m1","track_results":[
+  {"source":{"name":"Hey Jude","artists":["The Beatles"]},"status":"matched","matched":{"name":"Hey Jude","artists":["The Beatles"],"external_id":"jude"},"expected_id":""},
+  {"source":{"name":"Hey Jude","artists":["The Beatles"]},"status":"matched","matched":{"name":"Toxic","artists":["Britney Spears"],"external_id":"toxic"}},
+  {"source":{"name":"Let It Be","artists":["The Beatles"]},"status":"error","matched":{"name":"Let It Be","artists":["The Beatles"],"external_id":"lib"}},
+  {"source":{"name":"Let It Be","artists":["The Beatles"]},"status":"not_found"}
+]}
+`
+
+func TestRun(t *testing.T) {
+	cases, err := readCases(strings.NewReader(dataset), nil)
+	require.NoError(t, err)
+	require.Len(t, cases, 7)
+
+	m, err := newMatcher("catalog", domain.MatchOptions{MinScore: 0.7}, "")
+	require.NoError(t, err)
+	r := run(m, cases)
+
+	assert.Equal(t, 5, r.Cases)
+	assert.Equal(t, 2, r.Skipped)
+	assert.Equal(t, 1, r.CorrectMatches)
+	assert.Equal(t, 1, r.CorrectNoMatches)
+	assert.Equal(t, 1, r.WrongMatches)
+	assert.Equal(t, 1, r.FalseMatches)
+	assert.Equal(t, 1, r.MissedMatches)
+
+	// 2 of 5 cases right; 1 of the 3 chosen and 1 of the 3 expected matches.
+	assert.InDelta(t, 0.4, r.Accuracy, 1e-9)
+	assert.InDelta(t, 1.0/3, r.Precision, 1e-9)
+	assert.InDelta(t, 1.0/3, r.Recall, 1e-9)
+
+	var outcomes []string
+	for _, miss := range r.Misses {
+		outcomes = append(outcomes, miss.Outcome)
+	}
+	assert.Equal(t, []string{"wrong_match", "false_match", "missed_match"}, outcomes)
+	assert.Equal(t, "exact", r.Misses[0].Chosen.ExternalID)
+	assert.Equal(t, "live", r.Misses[0].Expected)
+	assert.Nil(t, r.Misses[2].Chosen)
+}
+
+func TestRun_Empty(t *testing.T) {
+	r := run(matcher{}, nil)
+	assert.Zero(t, r.Cases)
+	assert.Zero(t, r.Accuracy, "no cases divide by nothing")
+	assert.Zero(t, r.Precision)
+	assert.Zero(t, r.Recall)
+}
+
+func TestMatcher_Choose(t *testing.T) {
+	catalog, err := newMatcher("catalog", domain.MatchOptions{MinScore: 0.7}, "")
+	require.NoError(t, err)
+	isrcOnly, err := newMatcher("catalog", domain.MatchOptions{Strategy: domain.MatchingISRCOnly, MinScore: 0.7}, "")
+	require.NoError(t, err)
+	title, err := newMatcher("title", domain.MatchOptions{MinScore: 0.7}, "")
+	require.NoError(t, err)
+
+	source := domain.Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, ISRC: "GBUM71029604"}
+	exact := domain.Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, ExternalID: "exact"}
+	video := domain.Track{Name: "Bohemian Rhapsody (Official Video)", Artists: []string{"Queen"}, ExternalID: "video"}
+	other := domain.Track{Name: "Toxic", Artists: []string{"Britney Spears"}, ExternalID: "other"}
+
+	tests := []struct {
+		name       string
+		m          matcher
+		candidates []domain.Track
+		want       string
+	}{
+		{"best candidate", catalog, []domain.Track{other, exact}, "exact"},
+		{"below threshold", catalog, []domain.Track{other}, ""},
+		{"no candidates", catalog, nil, ""},
+		{"not confirmed by the strategy", isrcOnly, []domain.Track{exact}, ""},
+		{"cleaned title", title, []domain.Track{other, video}, "video"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chosen, _ := tt.m.choose(source, tt.candidates)
+			if tt.want == "" {
+				assert.Nil(t, chosen)
+				return
+			}
+			require.NotNil(t, chosen)
+			assert.Equal(t, tt.want, chosen.ExternalID)
+		})
+	}
+}
+
+func TestBenchCase_Expected(t *testing.T) {
+	empty, other := "", "other"
+	matched := &domain.Track{ExternalID: "m"}
+
+	tests := []struct {
+		name   string
+		c      benchCase
+		want   string
+		wantOK bool
+	}{
+		{"matched", benchCase{TrackResult: domain.TrackResult{Status: domain.TrackStatusMatched, MatchedTrack: matched}}, "m", true},
+		{"not found", benchCase{TrackResult: domain.TrackResult{Status: domain.TrackStatusNotFound}}, "", true},
+		{"override", benchCase{TrackResult: domain.TrackResult{Status: domain.TrackStatusMatched, MatchedTrack: matched}, ExpectedID: &other}, "other", true},
+		{"override with none", benchCase{TrackResult: domain.TrackResult{Status: domain.TrackStatusMatched, MatchedTrack: matched}, ExpectedID: &empty}, "", true},
+		{"failed", benchCase{TrackResult: domain.TrackResult{Status: domain.TrackStatusError, MatchedTrack: matched}}, "", false},
+		{"awaiting review", benchCase{TrackResult: domain.TrackResult{Status: domain.TrackStatusNeedsReview, MatchedTrack: matched}}, "", false},
+		{"matched without track", benchCase{TrackResult: domain.TrackResult{Status: domain.TrackStatusMatched}}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.c.expected()
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBenchCase_Candidates(t *testing.T) {
+	c := benchCase{TrackResult: domain.TrackResult{
+		MatchedTrack: &domain.Track{ExternalID: "a"},
+		Candidates: []domain.TrackCandidate{
+			{Track: domain.Track{ExternalID: "b"}},
+			{Track: domain.Track{ExternalID: "a"}},
+			{Track: domain.Track{ExternalID: "c"}},
+		},
+	}}
+
+	var ids []string
+	for _, track := range c.candidates() {
+		ids = append(ids, track.ExternalID)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, ids, "the chosen match first, each once")
+	assert.Empty(t, benchCase{}.candidates())
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// benchCase is a track result replayed by the benchmark: the source track,
+// the match chosen for it and the runner-up candidates of its search.
+type benchCase struct {
+	domain.TrackResult
+
+	// ExpectedID overrides the chosen match as the right answer: the ID of
+	// the right candidate, or "" if none of them is.
+	ExpectedID *string `json:"expected_id,omitempty"`
+}
+
+// expected returns the ID of the right candidate of c, "" if none is, and
+// false if c says nothing about its right answer: tracks that failed,
+// were filtered or await review.
+func (c benchCase) expected() (string, bool) {
+	switch {
+	case c.ExpectedID != nil:
+		return *c.ExpectedID, true
+	case c.Status == domain.TrackStatusMatched && c.MatchedTrack != nil:
+		return c.MatchedTrack.ExternalID, true
+	case c.Status == domain.TrackStatusNotFound:
+		return "", true
+	}
+	return "", false
+}
+
+// candidates returns the chosen match and the runner-ups of c, each once.
+func (c benchCase) candidates() []domain.Track {
+	var tracks []domain.Track
+	seen := make(map[string]bool)
+	add := func(t domain.Track) {
+		if seen[t.ExternalID] {
+			return
+		}
+		seen[t.ExternalID] = true
+		tracks = append(tracks, t)
+	}
+	if c.MatchedTrack != nil {
+		add(*c.MatchedTrack)
+	}
+	for _, candidate := range c.Candidates {
+		add(candidate.Track)
+	}
+	return tracks
+}
+
+// loadDataset reads the cases of the dataset files at paths. A file holds
+// JSON values one after another: track results, as streamed by
+// GET /api/v1/migrations/{id}/results.ndjson, or whole migration results,
+// as returned by GET /api/v1/migrations/{id}, whose track results are read.
+// Values that are neither, such as the first line of a migration streamed
+// as NDJSON, are skipped.
+func loadDataset(paths []string) ([]benchCase, error) {
+	var cases []benchCase
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		cases, err = readCases(f, cases)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return cases, nil
+}
+
+// readCases appends the cases read from r to cases.
+func readCases(r io.Reader, cases []benchCase) ([]benchCase, error) {
+	dec := json.NewDecoder(r)
+	for {
+		var value struct {
+			benchCase
+			TrackResults []benchCase `json:"track_results"`
+		}
+		err := dec.Decode(&value)
+		if errors.Is(err, io.EOF) {
+			return cases, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if value.SourceTrack.Name != "" {
+			cases = append(cases, value.benchCase)
+		}
+		cases = append(cases, value.TrackResults...)
+	}
+}
@@ -0,0 +1,162 @@
+// Command matchbench replays stored track results through the current
+// matching engine and reports how often it picks the right candidate, so
+// scoring changes can be measured before they are deployed.
+//
+// A dataset is a file of track results, as streamed by
+// GET /api/v1/migrations/{id}/results.ndjson, or of whole migration
+// results. Each result carries a source track, the match chosen for it and
+// the runner-up candidates of its search; the chosen match is taken for the
+// right answer unless the result sets expected_id, which can name another
+// candidate or, if empty, say that none is right. The golden dataset
+// exported by GET /admin/golden/export.ndjson is in this format.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/cleaning"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/pkg/matching"
+)
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	var (
+		opts        domain.MatchOptions
+		scorer      string
+		strategy    string
+		rerecord    string
+		titleRules  string
+		asJSON      bool
+		showMisses  bool
+		minAccuracy float64
+	)
+
+	cmd := &cobra.Command{
+		Use:           "matchbench [flags] DATASET...",
+		Short:         "Measure the matching engine against stored track results",
+		Args:          cobra.MinimumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			opts.Strategy = domain.MatchingStrategy(strategy)
+			opts.Rerecordings = domain.RerecordingPreference(rerecord)
+			if !opts.Strategy.Valid() {
+				return fmt.Errorf("unknown matching strategy %q", strategy)
+			}
+			if !opts.Rerecordings.Valid() {
+				return fmt.Errorf("unknown rerecordings preference %q", rerecord)
+			}
+			m, err := newMatcher(scorer, opts, titleRules)
+			if err != nil {
+				return err
+			}
+
+			cases, err := loadDataset(args)
+			if err != nil {
+				return err
+			}
+			r := run(m, cases)
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(r); err != nil {
+					return err
+				}
+			} else if err := printReport(r, showMisses); err != nil {
+				return err
+			}
+			if r.Accuracy < minAccuracy {
+				return fmt.Errorf("accuracy %.3f is below %.3f", r.Accuracy, minAccuracy)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&scorer, "scorer", "catalog", "catalog, for structured catalogs like Spotify, or title, for video titles like YouTube")
+	cmd.Flags().StringVar(&titleRules, "title-rules", "", "title-cleaning rules file for the title scorer (built-in rules if empty)")
+	cmd.Flags().StringVar(&strategy, "matching-strategy", "", "isrc_only, strict, relaxed or duration_weighted (default scoring if empty)")
+	cmd.Flags().Float64Var(&opts.MinScore, "min-score", 0, "minimum confidence of a match, if higher than the strategy's")
+	cmd.Flags().BoolVar(&opts.Classical, "classical", false, "match classical works by composer, work and movement")
+	cmd.Flags().BoolVar(&opts.StrictVersions, "strict-versions", false, "never match a track to a live, remix, acoustic or cover version")
+	cmd.Flags().StringVar(&rerecord, "rerecordings", "", "prefer or avoid re-recorded versions such as \"Taylor's Version\" when both exist, or any to not prefer candidates released in the same year as the source track")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print the report, with every miss, as JSON")
+	cmd.Flags().BoolVar(&showMisses, "show-misses", false, "list the cases answered wrong")
+	cmd.Flags().Float64Var(&minAccuracy, "min-accuracy", 0, "exit with an error if the accuracy is below this share, e.g. in CI")
+
+	return cmd
+}
+
+// newMatcher configures the scorer a provider would use for a migration
+// with opts: the catalog scorer of Spotify or the title scorer of YouTube.
+func newMatcher(scorer string, opts domain.MatchOptions, titleRules string) (matcher, error) {
+	ctx := domain.ContextWithMatchOptions(context.Background(), opts)
+	m := matcher{threshold: opts.Threshold(), strategy: opts.Strategy}
+	switch scorer {
+	case "catalog":
+		m.track = adapters.Scorer(ctx, matching.Catalog)
+		m.episode = matching.CatalogEpisode
+	case "title":
+		m.track = adapters.Scorer(ctx, matching.Title)
+		m.episode = matching.TitleEpisode
+		m.cleaner = cleaning.Default()
+		if titleRules != "" {
+			var err error
+			if m.cleaner, err = cleaning.LoadFile(titleRules); err != nil {
+				return matcher{}, err
+			}
+		}
+	default:
+		return matcher{}, fmt.Errorf("unknown scorer %q, expected catalog or title", scorer)
+	}
+	return m, nil
+}
+
+func printReport(r report, showMisses bool) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	if showMisses && len(r.Misses) > 0 {
+		fmt.Fprintln(w, "OUTCOME\tSCORE\tSOURCE\tCHOSEN\tEXPECTED")
+		for _, m := range r.Misses {
+			chosen := "-"
+			if m.Chosen != nil {
+				chosen = fmt.Sprintf("%s - %s (%s)", m.Chosen.Artist(), m.Chosen.Name, m.Chosen.ExternalID)
+			}
+			expected := m.Expected
+			if expected == "" {
+				expected = "-"
+			}
+			fmt.Fprintf(w, "%s\t%.2f\t%s - %s\t%s\t%s\n",
+				m.Outcome, m.Score, m.Source.Artist(), m.Source.Name, chosen, expected)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "Cases\t%d\n", r.Cases)
+	if r.Skipped > 0 {
+		fmt.Fprintf(w, "Skipped\t%d\n", r.Skipped)
+	}
+	fmt.Fprintf(w, "Correct matches\t%d\n", r.CorrectMatches)
+	fmt.Fprintf(w, "Correct no-matches\t%d\n", r.CorrectNoMatches)
+	fmt.Fprintf(w, "Wrong matches\t%d\n", r.WrongMatches)
+	fmt.Fprintf(w, "False matches\t%d\n", r.FalseMatches)
+	fmt.Fprintf(w, "Missed matches\t%d\n", r.MissedMatches)
+	fmt.Fprintf(w, "Accuracy\t%.3f\n", r.Accuracy)
+	fmt.Fprintf(w, "Precision\t%.3f\n", r.Precision)
+	fmt.Fprintf(w, "Recall\t%.3f\n", r.Recall)
+	return w.Flush()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/fixture"
+)
+
+func newRecordFixtureCmd() *cobra.Command {
+	var provider, token, playlistID, out string
+	var searches int
+
+	cmd := &cobra.Command{
+		Use:   "record-fixture",
+		Short: "Record the API responses of reading a playlist as an adapter test fixture",
+		Long: "Reads a playlist and its tracks from the real provider API, searches for the\n" +
+			"first tracks and saves every request and response to a fixture file for\n" +
+			"fixture.Client. Credentials are redacted; review the file before committing it.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			token, err := resolveToken(token, provider)
+			if err != nil {
+				return err
+			}
+
+			recorder := fixture.NewRecorder(nil)
+			p, err := newRegistry(&http.Client{Transport: recorder}).Get(provider)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if _, err := p.GetPlaylist(ctx, token, playlistID); err != nil {
+				return err
+			}
+			tracks, err := p.GetPlaylistTracks(ctx, token, playlistID)
+			if err != nil {
+				return err
+			}
+			for _, track := range tracks[:min(searches, len(tracks))] {
+				if track.IsEpisode() {
+					continue
+				}
+				if _, _, err := p.SearchTrack(ctx, token, track); err != nil {
+					return err
+				}
+			}
+
+			if err := recorder.Save(out); err != nil {
+				return err
+			}
+			fmt.Printf("Recorded %d exchanges to %s\n", len(recorder.Exchanges()), out)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&provider, "provider", "", "streaming provider (spotify, youtube)")
+	cmd.Flags().StringVar(&token, "token", "", "provider access token (defaults to $<PROVIDER>_TOKEN)")
+	cmd.Flags().StringVar(&playlistID, "playlist", "", "ID of the playlist to read")
+	cmd.Flags().IntVar(&searches, "searches", 3, "number of playlist tracks to search for")
+	cmd.Flags().StringVar(&out, "out", "", "fixture file to write, e.g. internal/adapters/spotify/testdata/playlist.json")
+	_ = cmd.MarkFlagRequired("provider")
+	_ = cmd.MarkFlagRequired("playlist")
+	_ = cmd.MarkFlagRequired("out")
+
+	return cmd
+}
@@ -0,0 +1,89 @@
+// Command migrate-cli runs playlist migrations from the terminal, talking to
+// the streaming providers directly instead of going through the HTTP API.
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/lastfm"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/localfiles"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/m3u"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/spotify"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/youtube"
+	"github.com/jpp0ca/MusicMigration-API/internal/app"
+)
+
+var verbose bool
+
+func main() {
+	root := &cobra.Command{
+		Use:           "migrate-cli",
+		Short:         "Transfer playlists between streaming services",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRun: func(_ *cobra.Command, _ []string) {
+			if !verbose {
+				log.SetOutput(io.Discard)
+			}
+		},
+	}
+	root.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "print worker logs")
+
+	root.AddCommand(newPlaylistsCmd(), newMigrateCmd(), newRecordFixtureCmd())
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// newRegistry registers every provider the CLI can talk to, sending their
+// requests through httpClient.
+func newRegistry(httpClient *http.Client) *adapters.ProviderRegistry {
+	registry := adapters.NewProviderRegistry()
+	var spotifyOpts []spotify.Option
+	if id, secret := os.Getenv("SPOTIFY_CLIENT_ID"), os.Getenv("SPOTIFY_CLIENT_SECRET"); id != "" && secret != "" {
+		spotifyOpts = append(spotifyOpts, spotify.WithClientCredentials(id, secret))
+	}
+	registry.Register(spotify.NewProvider(httpClient, spotifyOpts...))
+	registry.Register(youtube.NewProvider(httpClient))
+	if dir := os.Getenv("LOCAL_LIBRARY_DIR"); dir != "" {
+		registry.Register(localfiles.NewProvider(dir))
+	}
+	if key := os.Getenv("LASTFM_API_KEY"); key != "" {
+		registry.Register(lastfm.NewProvider(httpClient, key))
+	}
+	if dir := os.Getenv("M3U_DIR"); dir != "" {
+		registry.Register(m3u.NewProvider(m3u.WithDirectory(dir)))
+	}
+	return registry
+}
+
+// newService creates a migration service over newRegistry that keeps its
+// results in memory for the lifetime of the command.
+func newService(workers int, opts ...app.Option) *app.Service {
+	opts = append([]app.Option{app.WithMigrationStore(memory.NewMigrationStore()), app.WithLocker(memory.NewLocker())}, opts...)
+	return app.NewService(newRegistry(&http.Client{}), workers, opts...)
+}
+
+// resolveToken returns the flag value if set, otherwise the
+// <PROVIDER>_TOKEN environment variable (e.g. SPOTIFY_TOKEN).
+func resolveToken(flagValue, provider string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	envKey := strings.ToUpper(provider) + "_TOKEN"
+	if token := os.Getenv(envKey); token != "" {
+		return token, nil
+	}
+	return "", fmt.Errorf("no token for %s: pass a token flag or set %s", provider, envKey)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/app"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+func newMigrateCmd() *cobra.Command {
+	var (
+		req        domain.MigrationRequest
+		exclude    domain.TrackFilter
+		strategy   string
+		conflict   string
+		rerecord   string
+		workers    int
+		showTracks bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate a playlist from one provider to another",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			req.MatchingStrategy = domain.MatchingStrategy(strategy)
+			req.ConflictPolicy = domain.ConflictPolicy(conflict)
+			req.Rerecordings = domain.RerecordingPreference(rerecord)
+			req.Exclude = &exclude
+			var err error
+			if req.SourceToken, err = resolveToken(req.SourceToken, req.SourceProvider); err != nil {
+				return err
+			}
+			if req.DestToken, err = resolveToken(req.DestToken, req.DestProvider); err != nil {
+				return err
+			}
+
+			bar := newProgressBar(os.Stderr)
+			svc := newService(workers, app.WithProgress(bar.Update))
+
+			result, err := svc.MigratePlaylist(cmd.Context(), req)
+			bar.Finish()
+			if err != nil {
+				return err
+			}
+
+			printSummary(result, showTracks)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&req.SourceProvider, "from", "", "source provider (spotify, youtube)")
+	cmd.Flags().StringVar(&req.DestProvider, "to", "", "destination provider (spotify, youtube)")
+	cmd.Flags().StringVar(&req.PlaylistID, "playlist", "", "source playlist ID")
+	cmd.Flags().StringVar(&req.SourceToken, "from-token", "", "source provider token (defaults to $<PROVIDER>_TOKEN)")
+	cmd.Flags().StringVar(&req.DestToken, "to-token", "", "destination provider token (defaults to $<PROVIDER>_TOKEN)")
+	cmd.Flags().BoolVar(&req.DryRun, "dry-run", false, "match tracks without creating the destination playlist")
+	cmd.Flags().BoolVar(&req.PreserveOrder, "preserve-order", false, "report source positions missing from the destination as gaps")
+	cmd.Flags().BoolVar(&req.Classical, "classical", false, "match classical works by composer, work and movement")
+	cmd.Flags().BoolVar(&req.StrictVersions, "strict-versions", false, "never match a track to a live, remix, acoustic or cover version")
+	cmd.Flags().StringVar(&rerecord, "rerecordings", "", "prefer or avoid re-recorded versions such as \"Taylor's Version\" when both exist, or any to not prefer candidates released in the same year as the source track")
+	cmd.Flags().StringVar(&strategy, "matching-strategy", "", "isrc_only, strict, relaxed or duration_weighted (default scoring if empty)")
+	cmd.Flags().StringVar(&conflict, "conflict-policy", "", "reuse, skip or suffix an existing destination playlist of the same name (always create if empty)")
+	cmd.Flags().Float64Var(&req.MinScore, "min-score", 0, "minimum confidence of a match, if higher than the strategy's")
+	cmd.Flags().Float64Var(&req.ReviewThreshold, "review-threshold", 0, "put matches scoring below this confidence into a separate review playlist")
+	cmd.Flags().BoolVar(&req.Dedupe, "dedupe", false, "migrate tracks repeated in the source playlist once")
+	cmd.Flags().StringArrayVar(&exclude.Artists, "exclude-artist", nil, "skip the tracks of this artist (repeatable)")
+	cmd.Flags().StringArrayVar(&exclude.TitlePatterns, "exclude-title", nil, "skip tracks whose title matches this regular expression (repeatable)")
+	cmd.Flags().BoolVar(&exclude.Explicit, "exclude-explicit", false, "skip tracks the source marks as explicit")
+	cmd.Flags().StringArrayVar(&exclude.Genres, "exclude-genre", nil, "skip tracks of this genre (repeatable)")
+	cmd.Flags().StringArrayVar(&req.Genres, "genre", nil, "migrate only tracks of this genre (repeatable)")
+	cmd.Flags().StringVar(&req.NamePattern, "name", "", "destination playlist name; {source}, {dest}, {playlist} and {date} are replaced")
+	cmd.Flags().BoolVar(&req.CopySharing, "copy-sharing", false, "make the destination playlist public or collaborative like the source")
+	cmd.Flags().StringVar(&req.Market, "market", "", "ISO 3166-1 alpha-2 market to search the destination in")
+	cmd.Flags().IntVar(&workers, "workers", 5, "concurrent track searches")
+	cmd.Flags().BoolVar(&showTracks, "tracks", false, "print a per-track result table")
+	for _, name := range []string{"from", "to", "playlist"} {
+		_ = cmd.MarkFlagRequired(name)
+	}
+
+	return cmd
+}
+
+func printSummary(result *domain.MigrationResult, showTracks bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	if showTracks {
+		fmt.Fprintln(w, "STATUS\tSCORE\tSOURCE\tMATCH")
+		for _, tr := range result.TrackResults {
+			match := tr.Error
+			if tr.MatchedTrack != nil {
+				match = fmt.Sprintf("%s - %s", tr.MatchedTrack.Artist(), tr.MatchedTrack.Name)
+			}
+			fmt.Fprintf(w, "%s\t%.2f\t%s - %s\t%s\n",
+				tr.Status, tr.ConfidenceScore, tr.SourceTrack.Artist(), tr.SourceTrack.Name, match)
+		}
+		fmt.Fprintln(w)
+	}
+
+	destination := result.DestPlaylistID
+	if len(result.DestPlaylistIDs) > 0 {
+		destination = strings.Join(result.DestPlaylistIDs, ", ")
+	}
+	if result.DryRun {
+		destination = "(dry run)"
+	}
+
+	fmt.Fprintf(w, "Migration\t%s\n", result.ID)
+	fmt.Fprintf(w, "Route\t%s -> %s\n", result.SourceProvider, result.DestProvider)
+	fmt.Fprintf(w, "Destination playlist\t%s\n", destination)
+	fmt.Fprintf(w, "Total tracks\t%d\n", result.TotalTracks)
+	fmt.Fprintf(w, "Matched\t%d\n", result.MatchedTracks)
+	fmt.Fprintf(w, "Failed\t%d\n", result.FailedTracks)
+	for _, cause := range slices.Sorted(maps.Keys(result.ErrorBreakdown)) {
+		fmt.Fprintf(w, "  %s\t%d\n", cause, result.ErrorBreakdown[cause])
+	}
+	if result.FilteredTracks > 0 {
+		fmt.Fprintf(w, "Filtered\t%d\n", result.FilteredTracks)
+	}
+	if result.ReviewTracks > 0 {
+		review := result.ReviewPlaylistID
+		if result.DryRun {
+			review = "(dry run)"
+		}
+		fmt.Fprintf(w, "For review\t%d in %s\n", result.ReviewTracks, review)
+	}
+	if len(result.Gaps) > 0 {
+		positions := make([]string, len(result.Gaps))
+		for i, pos := range result.Gaps {
+			positions[i] = strconv.Itoa(pos + 1)
+		}
+		fmt.Fprintf(w, "Gaps at positions\t%s\n", strings.Join(positions, ", "))
+	}
+	_ = w.Flush()
+}
+
+// progressBar renders a single-line progress bar that is redrawn in place.
+type progressBar struct {
+	out   *os.File
+	drawn bool
+}
+
+func newProgressBar(out *os.File) *progressBar {
+	return &progressBar{out: out}
+}
+
+const progressWidth = 30
+
+func (b *progressBar) Update(done, total int) {
+	filled := progressWidth * done / total
+	fmt.Fprintf(b.out, "\r[%s%s] %d/%d tracks",
+		strings.Repeat("#", filled), strings.Repeat("-", progressWidth-filled), done, total)
+	b.drawn = true
+}
+
+// Finish moves the cursor past the bar so subsequent output starts on a new line.
+func (b *progressBar) Finish() {
+	if b.drawn {
+		fmt.Fprintln(b.out)
+	}
+}
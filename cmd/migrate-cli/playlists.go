@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func newPlaylistsCmd() *cobra.Command {
+	var provider, token string
+
+	cmd := &cobra.Command{
+		Use:   "playlists",
+		Short: "List the playlists of the authenticated user",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			token, err := resolveToken(token, provider)
+			if err != nil {
+				return err
+			}
+
+			svc := newService(1)
+			playlists, err := svc.ListPlaylists(cmd.Context(), provider, token)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tTRACKS\tOWNER")
+			for _, p := range playlists {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", p.ID, p.Name, p.TrackCount, p.OwnerName)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&provider, "provider", "", "streaming provider (spotify, youtube)")
+	cmd.Flags().StringVar(&token, "token", "", "provider access token (defaults to $<PROVIDER>_TOKEN)")
+	_ = cmd.MarkFlagRequired("provider")
+
+	return cmd
+}
@@ -20,9 +20,64 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/migrate": {
-            "post": {
-                "description": "Transfers a playlist from one streaming provider to another using concurrent workers.\nFetches tracks from the source, matches them on the destination via ISRC or name+artist,\nand creates a new playlist with the matched tracks. Returns detailed results with confidence scores.",
+        "/admin/accounts/{id}/limits": {
+            "get": {
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ],
+                "description": "Returns the limits that apply to an account: those set for it, or the configured defaults.\nZero means unlimited.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get account limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AccountLimits"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the limits of an account; limits left out of the body are unlimited. They apply\nfrom the account's next request, to migrations and jobs already counted too.",
                 "consumes": [
                     "application/json"
                 ],
@@ -30,17 +85,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "migration"
+                    "admin"
                 ],
-                "summary": "Migrate playlist",
+                "summary": "Set account limits",
                 "parameters": [
                     {
-                        "description": "Migration request with source/dest providers, tokens, and playlist ID",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Limits of the account",
+                        "name": "limits",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationRequest"
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AccountLimits"
                         }
                     }
                 ],
@@ -48,7 +110,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationResult"
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AccountLimits"
                         }
                     },
                     "400": {
@@ -57,6 +119,68 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ],
+                "description": "Removes the limits set for an account, so the configured defaults apply again, and returns them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset account limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AccountLimits"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -66,39 +190,64 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/playlists": {
+        "/admin/audit": {
             "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
                 ],
-                "description": "Returns all playlists for the authenticated user on the specified streaming provider.\nSupported providers: spotify, youtube.",
+                "description": "Returns the playlist writes made to providers (creating and deleting playlists, adding\nand removing tracks, updating playlist details) with the account, provider, affected IDs,\nstart and finish times and the error of failed writes, most recent first. Filters combine;\nsince and until bound the start time and are RFC 3339 timestamps.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "playlists"
+                    "admin"
                 ],
-                "summary": "List user playlists",
+                "summary": "Query audit log",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account that made the writes",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provider written to",
+                        "name": "provider",
+                        "in": "query"
+                    },
                     {
                         "enum": [
-                            "spotify",
-                            "youtube"
+                            "create_playlist",
+                            "add_tracks",
+                            "remove_tracks",
+                            "update_playlist",
+                            "delete_playlist"
                         ],
                         "type": "string",
-                        "description": "Streaming provider",
-                        "name": "provider",
-                        "in": "query",
-                        "required": true
+                        "description": "Kind of write",
+                        "name": "action",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Bearer token for the streaming provider",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "Earliest start time, inclusive",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest start time, exclusive",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -107,7 +256,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Playlist"
+                                "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AuditEntry"
                             }
                         }
                     },
@@ -132,107 +281,4803 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
+        "/admin/golden": {
             "get": {
-                "description": "Returns the health status of the API",
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ],
+                "description": "Returns the labeled matches of the golden dataset, least recently updated first. Entries\nare drawn from match feedback, following the verdict of most accounts, until they are\ncurated.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "admin"
+                ],
+                "summary": "List golden dataset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source provider of the entries",
+                        "name": "source_provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Destination provider of the entries",
+                        "name": "dest_provider",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Health check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.GoldenMatch"
                             }
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ],
+                "description": "Saves a labeled match as curated, replacing the entry of the same source track and\ndestination provider. Set expected to the right track, rejected to known wrong\ncandidates, or both; an entry without expected says none of the rejected tracks is right.\nCurated entries are no longer changed by match feedback.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Curate golden match",
+                "parameters": [
+                    {
+                        "description": "Labeled match",
+                        "name": "match",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.GoldenMatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.GoldenMatch"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/golden/export.ndjson": {
+            "get": {
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ],
+                "description": "Streams the golden dataset as newline-delimited JSON in the format read by the matchbench\ncommand, one track result per entry: the expected track is the matched track, the\nrejected tracks are its candidates and expected_id names the right one, or is empty if\nnone is. Filter by dest_provider to benchmark the scorer of one provider.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Export golden dataset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source provider of the entries",
+                        "name": "source_provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Destination provider of the entries",
+                        "name": "dest_provider",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/golden/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ],
+                "description": "Removes an entry from the golden dataset. Feedback given later on the same source track\nstarts a new, uncurated entry.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete golden match",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Golden match ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/jobs/{id}/debug": {
+            "get": {
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ],
+                "description": "Returns the provider requests made by a job queued with POST /api/v1/jobs?debug=true: their\nURLs with credentials removed, status codes, durations and the first 2 KiB of each response\nbody. The log is stored when the job finishes and keeps the first 200 requests; dropped counts\nthe rest.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get job debug log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.DebugLog"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/providers": {
+            "get": {
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ],
+                "description": "Lists every registered provider and whether it is currently enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderStatus"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/providers/{name}/disable": {
+            "post": {
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ],
+                "description": "Disables a provider without restarting the server, e.g. while its API quota is exhausted.\nRequests using it fail with 503 and the given reason until it is re-enabled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Disable provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason shown to clients",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.DisableProviderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/providers/{name}/enable": {
+            "post": {
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ],
+                "description": "Re-enables a provider disabled with the disable endpoint.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Enable provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/accounts": {
+            "post": {
+                "description": "Creates a new account and returns its API key. The key is only shown once;\nsend it in the X-API-Key header on all other /api/v1 requests.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accounts"
+                ],
+                "summary": "Register account",
+                "parameters": [
+                    {
+                        "description": "Account details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.RegisterAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.RegisterAccountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/export/account": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every playlist of the authenticated user with its tracks and, on providers with a\nlibrary (Spotify), their liked songs, saved albums and shows and followed artists, as one\ndownloadable JSON file. Sections a provider does not have are empty. Reading the Spotify\nlibrary needs the user-library-read and user-follow-read scopes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Export account",
+                "parameters": [
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token for the streaming provider",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AccountExport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/imports/m3u": {
+            "post": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Parses an M3U/M3U8 playlist file, e.g. exported from VLC or foobar2000, and keeps it\nfor the caller's account. The file is sent as the request body or as the \"file\" field of\na multipart form, up to 5 MiB. Migrate it with source_provider \"m3u\" and the returned ID.",
+                "consumes": [
+                    "text/plain",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Import M3U playlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist name, if the file has no #PLAYLIST directive",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs": {
+            "post": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Validates a migration request and queues it, returning immediately. Poll the job until its\nstatus is \"succeeded\" (its migration_id then names the stored migration) or \"failed\".\nQueued and running jobs survive restarts when a persistent storage driver is used. Tokens in\nthe request are stored with the job, encrypted, until it finished; omit them to use tokens from\nthe vault instead. Queuing again with the same Idempotency-Key returns the first job.\nJobs queued with priority=bulk, such as those of a large batch, run after interactive ones, and\naccounts take turns so one account's jobs do not hold up everyone else's.\nWith debug=true and the admin key in X-Admin-Key, the job records its provider requests for\nGET /admin/jobs/{id}/debug.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Queue migration",
+                "parameters": [
+                    {
+                        "description": "Migration request with source/dest providers, tokens, and playlist ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-generated key; a repeated key returns the job first queued with it",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "interactive",
+                            "bulk"
+                        ],
+                        "type": "string",
+                        "description": "Queue priority; bulk jobs wait for interactive ones",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Record provider requests (requires X-Admin-Key)",
+                        "name": "debug",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin key, required for debug",
+                        "name": "X-Admin-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Returns the status of a migration queued with POST /api/v1/jobs. While the job searches for\ntracks, progress reports the tracks processed, percent complete, throughput and projected\ncompletion time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Get job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Job"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/matches/feedback": {
+            "post": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Records whether source_track on source_provider and matched_track on dest_provider, identified\nby their external_id, are the same recording. The match, or a candidate ranked below it, must\nbe reported in the track results of one of the caller's migrations, whose tracks are recorded.\nVerdicts are shared by everyone on the instance, one per account and match; a new verdict\nreplaces the caller's earlier one. Once most of enough accounts confirm a match, later\nmigrations reuse it without searching, until most no longer do; once most reject it,\nsearches no longer accept it. Returns the verdicts of all accounts on the match.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Confirm or reject a match",
+                "parameters": [
+                    {
+                        "description": "Verdict on a match",
+                        "name": "feedback",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MatchFeedback"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MatchFeedbackTally"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/connections": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Returns every provider the authenticated account has stored a token for, with the token's\nexpiry, whether it has expired, whether it can be refreshed and the OAuth scopes granted to\nit. Tokens themselves are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "List linked providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderConnection"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/connections/{provider}": {
+            "delete": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Revokes the stored token with the provider, where the provider supports it (YouTube), and\nremoves it from the vault. If the provider fails to revoke it, the token is kept and 502 is\nreturned; DELETE /api/v1/tokens/{provider} removes it without revoking.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Revoke linked provider",
+                "parameters": [
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merge": {
+            "post": {
+                "description": "Fetches two or more source playlists, possibly of different providers, and migrates their\ntracks into one new destination playlist. Tracks found in several sources are added once:\nthose sharing an ID or ISRC are merged before matching, those matched to the same destination\ntrack after it. The tracks are ordered by source, interleaved or by release date.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Merge playlists",
+                "parameters": [
+                    {
+                        "description": "Source playlists, destination and ordering",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MergeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/migrate": {
+            "post": {
+                "description": "Transfers a playlist from one streaming provider to another using concurrent workers.\nFetches tracks from the source, matches them on the destination via ISRC or name+artist,\nand creates a new playlist with the matched tracks. Returns detailed results with confidence scores.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Migrate playlist",
+                "parameters": [
+                    {
+                        "description": "Migration request with source/dest providers, tokens, and playlist ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-generated key; repeated requests with the same key return the original migration",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/migrate/account": {
+            "post": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Queues one bulk job that copies the account of the user from the source provider to the\ndestination provider, returning immediately. The job runs the stages library (liked songs),\nalbums, artists and playlists in this order, with the options of the request applied to\nevery playlist. Stages a provider does not support are skipped. Poll the job for the\nprogress of each stage; the playlists stage lists the migration of each playlist. The job\ncounts once against the limit of concurrent jobs. Queuing again with the same\nIdempotency-Key returns the job first queued with it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Migrate account",
+                "parameters": [
+                    {
+                        "description": "Source/dest providers, tokens and migration options",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AccountMigrationRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-generated key; a repeated key returns the job first queued with it",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/migrate/preview": {
+            "post": {
+                "description": "Fetches the source playlist and reports its track count, how many tracks carry an ISRC or a known match,\nthe estimated duration and the API quota units per provider that migrating it would take.\nNothing is searched on or written to the destination.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Preview migration",
+                "parameters": [
+                    {
+                        "description": "Migration request to preview",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationPreview"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/migrations": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Returns all migrations run by the authenticated account, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "List migrations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationResult"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/migrations/compare": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Compares the stored results of two migrations of the same source playlist to the same destination\nprovider, for example before and after a change to matching. Tracks are paired by their source\ntrack; those whose status or matched track changed, and those only one migration has, are listed\nin the order of migration a, with a summary of the changes by kind.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Compare migrations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the earlier migration",
+                        "name": "a",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the later migration",
+                        "name": "b",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationComparison"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/migrations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Returns the stored result of a previous migration. With \"Accept: application/x-ndjson\" the result\nis streamed as newline-delimited JSON: the result without track_results, then one line per track result.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Get migration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Migration ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/migrations/{id}/report": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Returns every track of a stored migration with its status, matched counterpart and\nconfidence score as a downloadable file. Only the \"csv\" format is supported.\nWith unmatched=true, only the tracks left without a match are listed, at their original\npositions; filtered tracks are left out.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Download migration report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Migration ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Report format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only list tracks that were not matched",
+                        "name": "unmatched",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/migrations/{id}/results.ndjson": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Streams the track results of a stored migration as newline-delimited JSON, one TrackResult\nper line in source order, for processing large migrations with line-oriented tools.\nResults can be filtered by status and confidence score.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Stream migration track results",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Migration ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated statuses to include, e.g. not_found,error",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum confidence score (0-1)",
+                        "name": "min_score",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum confidence score (0-1)",
+                        "name": "max_score",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/migrations/{id}/retry-failed": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Searches again for tracks of a stored migration whose status is \"not_found\", or \"error\" with a\nretryable error code, merges the new results and appends newly matched tracks to the destination\nplaylist. Retryable \"add_failed\" tracks are added again without searching.\nThe Authorization header must carry a token for the destination provider.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Retry failed tracks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Migration ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token for the destination provider",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/migrations/{id}/reverse": {
+            "post": {
+                "description": "Runs the mirror-image migration of a stored one: the playlist it created on the destination\nprovider is migrated back to the source provider. Tracks the original migration matched are\nmapped back to their source IDs without searching; only tracks added since are searched.\nsource_token is for the original destination provider and dest_token for the original source.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Reverse migration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Migration ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tokens for the reversed direction",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ReverseMigrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/migrations/{id}/rollback": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the playlist created on the destination provider by a previous migration.\nThe Authorization header must carry a token for the destination provider.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Roll back migration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Migration ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token for the destination provider",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth/{provider}/authorize": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Returns the provider's consent page for the authenticated account. Once the user grants\naccess, the provider redirects to the configured redirect URL, which must point at\n/api/v1/oauth/{provider}/callback, and the issued token is stored in the vault. The link\nmust be opened within ten minutes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Start linking a provider",
+                "parameters": [
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.AuthorizationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth/{provider}/callback": {
+            "get": {
+                "description": "Redirect target of the provider's consent page. The state names the account the link\nwas started for, so no API key is needed. The authorization code is exchanged for a token,\nwhich is stored in the vault.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Complete linking a provider",
+                "parameters": [
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State issued by the authorize endpoint",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason the user did not grant access",
+                        "name": "error",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/playlists": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns all playlists for the authenticated user on the specified streaming provider.\nSupported providers: spotify, youtube.\nWhen 'limit' or 'cursor' is given, only a single page is returned; the cursor for the\nnext page is sent in the X-Next-Cursor response header (absent on the last page).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "List user playlists",
+                "parameters": [
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous X-Next-Cursor header",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token for the streaming provider",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Playlist"
+                            }
+                        },
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor for the next page"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of playlists, when known"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/playlists/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a single playlist with its full track list from the specified streaming provider.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Get playlist details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token for the streaming provider",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a playlist from the authenticated user's library on the specified provider.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Delete playlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token for the streaming provider",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renames a playlist or changes its description, visibility or collaborative setting. Omitted fields are left unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Update playlist details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token for the streaming provider",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.PlaylistUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/playlists/{id}/tracks": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the given tracks (by their provider-specific IDs) from a playlist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Remove tracks from playlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token for the streaming provider",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Track IDs to remove",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.RemoveTracksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/profiles": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Returns the migration profiles of the authenticated account, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "List migration profiles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationProfile"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Saves the providers and options of a migration under a name, so playlists can be migrated\nwith POST /api/v1/profiles/{id}/migrate by playlist ID alone. id, account_id, created_at and\nupdated_at are set by the server.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Create migration profile",
+                "parameters": [
+                    {
+                        "description": "Profile settings",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/profiles/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Get migration profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationProfile"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Replaces every setting of the profile; settings left out of the body are reset to their defaults.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Update migration profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile settings",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the profile. Migrations run with it are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Delete migration profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/profiles/{id}/migrate": {
+            "post": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Migrates a playlist like POST /api/v1/migrate, taking the providers and options from the\nprofile. Only the playlist ID, the tokens (unless they are in the vault) and dry_run are sent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Migrate playlist with profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Playlist ID and tokens",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ProfileMigrationRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-generated key; repeated requests with the same key return the original migration",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Searches for a track on the specified provider and returns every candidate with its\nconfidence score, in the order ranked by the provider. Useful for manual matching and\nfor debugging confidence scoring.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search track",
+                "parameters": [
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Track name (required unless isrc is given)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Artist name, repeated for each artist",
+                        "name": "artist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Album name",
+                        "name": "album",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISRC code",
+                        "name": "isrc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Release date (YYYY, YYYY-MM or YYYY-MM-DD); among similar candidates the one released closest wins",
+                        "name": "release_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 market to search in",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Score as a classical work (composer, work, movement)",
+                        "name": "classical",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Score different versions (live, remix, acoustic, cover) 0 instead of penalizing them",
+                        "name": "strict_versions",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "isrc_only",
+                            "strict",
+                            "relaxed",
+                            "duration_weighted"
+                        ],
+                        "type": "string",
+                        "description": "Scorer configuration",
+                        "name": "matching_strategy",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "prefer",
+                            "avoid"
+                        ],
+                        "type": "string",
+                        "description": "Prefer or avoid re-recordings of the track, or with any do not prefer candidates released close to release_date",
+                        "name": "rerecordings",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token for the streaming provider",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackCandidate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tokens/{provider}": {
+            "put": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Encrypts and stores an OAuth token for a streaming provider. Once stored, requests for\nthis provider may omit the Authorization header or source/dest tokens.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Store provider token",
+                "parameters": [
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Provider token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Removes the stored OAuth token for a streaming provider.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Delete provider token",
+                "parameters": [
+                    {
+                        "enum": [
+                            "spotify",
+                            "youtube"
+                        ],
+                        "type": "string",
+                        "description": "Streaming provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Returns the health status of the API. When provider checks are enabled, each\nprovider's API is pinged and the overall status is ok, degraded (some providers\nunreachable) or down (all unreachable). Down responds with 503 so load balancers\ncan take the instance out of rotation.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.HealthReport"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.HealthReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/migrations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Upgrades to a WebSocket that pushes JSON job events: \"status\" events carry the job whenever\nits status changes, starting with the current one, and \"track\" events carry each track\nsearch result with counters and an estimated completion time. Clients may send\n{\"type\":\"cancel\"} to cancel the job; a failed command is answered with an \"error\" event.\nThe server closes the socket once the job finished. Browsers may pass the API key in the\napi_key query parameter instead of the X-API-Key header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "migration"
+                ],
+                "summary": "Watch job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account API key, for clients that cannot set headers",
+                        "name": "api_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.JobEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.Account": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "limits": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AccountLimits"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.AccountExport": {
+            "type": "object",
+            "properties": {
+                "exported_at": {
+                    "type": "string"
+                },
+                "followed_artists": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Artist"
+                    }
+                },
+                "liked_tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
+                    }
+                },
+                "playlists": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Playlist"
+                    }
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "saved_albums": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Album"
+                    }
+                },
+                "saved_shows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Show"
+                    }
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.AccountLimits": {
+            "type": "object",
+            "properties": {
+                "concurrent_jobs": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "max_tracks_per_migration": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "migrations_per_day": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.AccountMigrationRequest": {
+            "type": "object",
+            "required": [
+                "dest_provider",
+                "source_provider"
+            ],
+            "properties": {
+                "classical": {
+                    "type": "boolean"
+                },
+                "conflict_policy": {
+                    "enum": [
+                        "reuse",
+                        "skip",
+                        "suffix"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ConflictPolicy"
+                        }
+                    ]
+                },
+                "copy_sharing": {
+                    "type": "boolean"
+                },
+                "dedupe": {
+                    "type": "boolean"
+                },
+                "dest_provider": {
+                    "type": "string"
+                },
+                "dest_token": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "description": "The options below apply to every stage that searches tracks, and the\nones about playlists to the migration of every playlist; see\nMigrationRequest."
+                },
+                "exclude": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackFilter"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "market": {
+                    "type": "string"
+                },
+                "matching_strategy": {
+                    "enum": [
+                        "isrc_only",
+                        "strict",
+                        "relaxed",
+                        "duration_weighted"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MatchingStrategy"
+                        }
+                    ]
+                },
+                "min_score": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "name_pattern": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "notify_email": {
+                    "type": "string"
+                },
+                "preserve_order": {
+                    "type": "boolean"
+                },
+                "rerecordings": {
+                    "enum": [
+                        "any",
+                        "prefer",
+                        "avoid"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.RerecordingPreference"
+                        }
+                    ]
+                },
+                "review_threshold": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "source_provider": {
+                    "type": "string"
+                },
+                "source_token": {
+                    "type": "string"
+                },
+                "strict_versions": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.AccountStage": {
+            "type": "string",
+            "enum": [
+                "library",
+                "albums",
+                "artists",
+                "playlists"
+            ],
+            "x-enum-comments": {
+                "AccountStageAlbums": "AccountStageAlbums saves the saved albums of the source on the\ndestination.",
+                "AccountStageArtists": "AccountStageArtists follows the followed artists of the source on the\ndestination.",
+                "AccountStageLibrary": "AccountStageLibrary adds the liked songs of the source to those of\nthe destination.",
+                "AccountStagePlaylists": "AccountStagePlaylists migrates every playlist of the source, one\nafter the other."
+            },
+            "x-enum-descriptions": [
+                "AccountStageLibrary adds the liked songs of the source to those of\nthe destination.",
+                "AccountStageAlbums saves the saved albums of the source on the\ndestination.",
+                "AccountStageArtists follows the followed artists of the source on the\ndestination.",
+                "AccountStagePlaylists migrates every playlist of the source, one\nafter the other."
+            ],
+            "x-enum-varnames": [
+                "AccountStageLibrary",
+                "AccountStageAlbums",
+                "AccountStageArtists",
+                "AccountStagePlaylists"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.AccountStageProgress": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error tells why the stage failed or was skipped.",
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "migration_ids": {
+                    "description": "MigrationIDs are the migrations of the playlists the playlists stage\nmigrated, in the order they ran.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "processed": {
+                    "description": "Processed counts the items of the stage done so far out of Total:\nliked songs, albums, artists or playlists. Matched counts those found\non the destination, and written to it unless the job is a dry run;\nFailed counts the others.",
+                    "type": "integer"
+                },
+                "stage": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AccountStage"
+                },
+                "status": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AccountStageStatus"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.AccountStageStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "succeeded",
+                "failed",
+                "skipped"
+            ],
+            "x-enum-comments": {
+                "AccountStageSkipped": "AccountStageSkipped is the status of a stage one of the providers\ndoes not support, such as the library stages for providers without a\nlibrary."
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "AccountStageSkipped is the status of a stage one of the providers\ndoes not support, such as the library stages for providers without a\nlibrary."
+            ],
+            "x-enum-varnames": [
+                "AccountStagePending",
+                "AccountStageRunning",
+                "AccountStageSucceeded",
+                "AccountStageFailed",
+                "AccountStageSkipped"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.Album": {
+            "type": "object",
+            "properties": {
+                "artists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "type": "string"
+                },
+                "track_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.Artist": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.AuditAction": {
+            "type": "string",
+            "enum": [
+                "create_playlist",
+                "add_tracks",
+                "remove_tracks",
+                "update_playlist",
+                "delete_playlist"
+            ],
+            "x-enum-varnames": [
+                "AuditCreatePlaylist",
+                "AuditAddTracks",
+                "AuditRemoveTracks",
+                "AuditUpdatePlaylist",
+                "AuditDeletePlaylist"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "action": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AuditAction"
+                },
+                "error": {
+                    "description": "Error is set if the write failed.",
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "playlist_id": {
+                    "description": "PlaylistID is the playlist written to; for AuditCreatePlaylist it is\nempty if the playlist could not be created. TrackIDs are the tracks\nadded or removed.",
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "track_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.ConcurrencyStats": {
+            "type": "object",
+            "properties": {
+                "deferred": {
+                    "type": "integer"
+                },
+                "final": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                },
+                "rate_limited": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.ConflictPolicy": {
+            "type": "string",
+            "enum": [
+                "",
+                "reuse",
+                "skip",
+                "suffix"
+            ],
+            "x-enum-comments": {
+                "ConflictIgnore": "ConflictIgnore creates a new playlist regardless.",
+                "ConflictReuse": "ConflictReuse adds the matched tracks to the existing playlist,\nleaving out those it already holds.",
+                "ConflictSkip": "ConflictSkip fails the migration with ErrPlaylistExists before any\ntrack is searched.",
+                "ConflictSuffix": "ConflictSuffix creates a new playlist under the first free name of\nthe form \"<name> (2)\", \"<name> (3)\" and so on."
+            },
+            "x-enum-varnames": [
+                "ConflictIgnore",
+                "ConflictReuse",
+                "ConflictSkip",
+                "ConflictSuffix"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.DebugLog": {
+            "type": "object",
+            "properties": {
+                "dropped": {
+                    "description": "Dropped counts the exchanges left out once the log was full.",
+                    "type": "integer"
+                },
+                "exchanges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderExchange"
+                    }
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.GoldenMatch": {
+            "type": "object",
+            "required": [
+                "dest_provider",
+                "source_provider"
+            ],
+            "properties": {
+                "curated": {
+                    "type": "boolean"
+                },
+                "dest_provider": {
+                    "type": "string"
+                },
+                "expected": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rejected": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
+                    }
+                },
+                "source_provider": {
+                    "type": "string"
+                },
+                "source_track": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.HealthReport": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderHealth"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.HealthStatus"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.HealthStatus": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "degraded",
+                "down"
+            ],
+            "x-enum-varnames": [
+                "HealthOK",
+                "HealthDegraded",
+                "HealthDown"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.ItemType": {
+            "type": "string",
+            "enum": [
+                "track",
+                "episode"
+            ],
+            "x-enum-varnames": [
+                "ItemTypeTrack",
+                "ItemTypeEpisode"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.Job": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "attempts": {
+                    "description": "Attempts counts how many times the job was leased.",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "debug": {
+                    "description": "Debug records the provider HTTP traffic of the job's migration in\nDebugLog once it finished. Only administrators can queue debug jobs\nand read their logs, so the log is never returned with the job.",
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.JobKind"
+                },
+                "migration_id": {
+                    "description": "MigrationID is the ID of the migration result once a playlist job\nsucceeded. Account jobs list the migrations of their playlists in\nthe progress of their playlists stage.",
+                    "type": "string"
+                },
+                "priority": {
+                    "enum": [
+                        "interactive",
+                        "bulk"
+                    ],
+                    "description": "Priority orders the job among the queued jobs. Among jobs of the\nsame priority, the queue takes turns between accounts: the next job\nis the oldest of the account with the fewest running jobs, ties going\nto the account whose last job was leased longest ago, so a batch of\none account does not starve the jobs of others.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.JobPriority"
+                        }
+                    ]
+                },
+                "progress": {
+                    "description": "Progress reports the search pass of the job's migration, or the\nstages of an account job, once it started. It is updated while the\njob runs and kept when it finished.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.JobProgress"
+                        }
+                    ]
+                },
+                "status": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.JobStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.JobEvent": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "job": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Job"
+                },
+                "progress": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.JobProgress"
+                },
+                "track": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackResult"
+                },
+                "type": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.JobEventType"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.JobEventType": {
+            "type": "string",
+            "enum": [
+                "status",
+                "track",
+                "progress",
+                "error"
+            ],
+            "x-enum-comments": {
+                "JobEventError": "JobEventError reports a client command that could not be carried out.",
+                "JobEventProgress": "JobEventProgress carries the progress of an account job after an\nitem of one of its stages was done.",
+                "JobEventStatus": "JobEventStatus carries the job after its status changed.",
+                "JobEventTrack": "JobEventTrack carries the result of one track search and the\nprogress of the search pass."
+            },
+            "x-enum-descriptions": [
+                "JobEventStatus carries the job after its status changed.",
+                "JobEventTrack carries the result of one track search and the\nprogress of the search pass.",
+                "JobEventProgress carries the progress of an account job after an\nitem of one of its stages was done.",
+                "JobEventError reports a client command that could not be carried out."
+            ],
+            "x-enum-varnames": [
+                "JobEventStatus",
+                "JobEventTrack",
+                "JobEventProgress",
+                "JobEventError"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.JobKind": {
+            "type": "string",
+            "enum": [
+                "playlist",
+                "account"
+            ],
+            "x-enum-comments": {
+                "JobAccount": "JobAccount migrates the library, saved albums, followed artists and\nevery playlist of the user on the source provider of the job's\nrequest, in that order; see AccountStage.",
+                "JobPlaylist": "JobPlaylist migrates the playlist of the job's request."
+            },
+            "x-enum-descriptions": [
+                "JobPlaylist migrates the playlist of the job's request.",
+                "JobAccount migrates the library, saved albums, followed artists and\nevery playlist of the user on the source provider of the job's\nrequest, in that order; see AccountStage."
+            ],
+            "x-enum-varnames": [
+                "JobPlaylist",
+                "JobAccount"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.JobPriority": {
+            "type": "string",
+            "enum": [
+                "interactive",
+                "bulk"
+            ],
+            "x-enum-varnames": [
+                "JobInteractive",
+                "JobBulk"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.JobProgress": {
+            "type": "object",
+            "properties": {
+                "estimated_completion": {
+                    "description": "EstimatedCompletion projects when searching ends from the throughput\nso far. It is unset until the first track was searched.",
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "stages": {
+                    "description": "Stages reports each stage of an account job. The counts above then\nadd up the items of every stage.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.AccountStageProgress"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "tracks_per_second": {
+                    "description": "TracksPerSecond is the throughput of the search workers since the\nsearch pass started.",
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.JobStatus": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "succeeded",
+                "failed",
+                "canceled"
+            ],
+            "x-enum-varnames": [
+                "JobQueued",
+                "JobRunning",
+                "JobSucceeded",
+                "JobFailed",
+                "JobCanceled"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MatchFeedback": {
+            "type": "object",
+            "required": [
+                "dest_provider",
+                "source_provider",
+                "verdict"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dest_provider": {
+                    "type": "string"
+                },
+                "matched_track": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
+                },
+                "source_provider": {
+                    "type": "string"
+                },
+                "source_track": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
+                },
+                "verdict": {
+                    "enum": [
+                        "confirmed",
+                        "rejected"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MatchVerdict"
+                        }
+                    ]
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MatchFeedbackTally": {
+            "type": "object",
+            "properties": {
+                "confirmations": {
+                    "type": "integer"
+                },
+                "rejections": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MatchVerdict": {
+            "type": "string",
+            "enum": [
+                "confirmed",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "MatchConfirmed",
+                "MatchRejected"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MatchingStrategy": {
+            "type": "string",
+            "enum": [
+                "",
+                "isrc_only",
+                "strict",
+                "relaxed",
+                "duration_weighted"
+            ],
+            "x-enum-varnames": [
+                "MatchingDefault",
+                "MatchingISRCOnly",
+                "MatchingStrict",
+                "MatchingRelaxed",
+                "MatchingDurationWeighted"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MergeOrder": {
+            "type": "string",
+            "enum": [
+                "by_source",
+                "interleave",
+                "release_date"
+            ],
+            "x-enum-comments": {
+                "MergeBySource": "MergeBySource keeps the tracks of each source together, in the order\nthe sources are given.",
+                "MergeInterleave": "MergeInterleave takes one track of each source in turn.",
+                "MergeReleaseDate": "MergeReleaseDate orders the tracks by release date, oldest first.\nTracks without one come last, in source order."
+            },
+            "x-enum-varnames": [
+                "MergeBySource",
+                "MergeInterleave",
+                "MergeReleaseDate"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MergeRequest": {
+            "type": "object",
+            "required": [
+                "dest_provider",
+                "sources"
+            ],
+            "properties": {
+                "dest_provider": {
+                    "type": "string"
+                },
+                "dest_token": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "matching_strategy": {
+                    "enum": [
+                        "isrc_only",
+                        "strict",
+                        "relaxed",
+                        "duration_weighted"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MatchingStrategy"
+                        }
+                    ]
+                },
+                "min_score": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "name": {
+                    "description": "Name is the name of the merged playlist. Empty uses \"Merged\nplaylist\".",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "order": {
+                    "description": "Order is the order of the merged tracks; see MergeOrder.",
+                    "enum": [
+                        "by_source",
+                        "interleave",
+                        "release_date"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MergeOrder"
+                        }
+                    ]
+                },
+                "source_tokens": {
+                    "description": "SourceTokens holds a token per source provider. Providers without\none use the token vault.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "sources": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 2,
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MergeSource"
+                    }
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MergeSource": {
+            "type": "object",
+            "required": [
+                "playlist_id",
+                "provider"
+            ],
+            "properties": {
+                "playlist_id": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationComparison": {
+            "type": "object",
+            "properties": {
+                "a": {
+                    "type": "string"
+                },
+                "b": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackChange"
+                    }
+                },
+                "dest_provider": {
+                    "type": "string"
+                },
+                "source_playlist": {
+                    "type": "string"
+                },
+                "source_provider": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "unchanged": {
+                    "description": "Unchanged counts the tracks with the same status and matched track in\nboth results, and Summary counts the changes of each kind.",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationPreview": {
+            "type": "object",
+            "properties": {
+                "dest_provider": {
+                    "type": "string"
+                },
+                "estimated_duration_ms": {
+                    "description": "EstimatedDurationMS projects the run time of the migration from the\nmeasured fetch, the search concurrency and typical call latencies.",
+                    "type": "integer"
+                },
+                "known_matches": {
+                    "type": "integer"
+                },
+                "playlist_id": {
+                    "type": "string"
+                },
+                "quota_units": {
+                    "description": "QuotaUnits estimates the API quota units the migration would consume\nper provider, for providers with unit-based quotas (e.g. YouTube).",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "source_provider": {
+                    "type": "string"
+                },
+                "total_episodes": {
+                    "type": "integer"
+                },
+                "total_tracks": {
+                    "type": "integer"
+                },
+                "tracks_with_isrc": {
+                    "description": "TracksWithISRC counts tracks that carry an ISRC, which usually match\nreliably. KnownMatches counts tracks with a known destination\ncounterpart that would not be searched.",
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationProfile": {
+            "type": "object",
+            "required": [
+                "dest_provider",
+                "name",
+                "source_provider"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "classical": {
+                    "type": "boolean"
+                },
+                "conflict_policy": {
+                    "enum": [
+                        "reuse",
+                        "skip",
+                        "suffix"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ConflictPolicy"
+                        }
+                    ]
+                },
+                "copy_sharing": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dedupe": {
+                    "type": "boolean"
+                },
+                "dest_provider": {
+                    "type": "string"
+                },
+                "exclude": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackFilter"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "matching_strategy": {
+                    "enum": [
+                        "isrc_only",
+                        "strict",
+                        "relaxed",
+                        "duration_weighted"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MatchingStrategy"
+                        }
+                    ]
+                },
+                "min_score": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "name_pattern": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "preserve_order": {
+                    "type": "boolean"
+                },
+                "rerecordings": {
+                    "enum": [
+                        "any",
+                        "prefer",
+                        "avoid"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.RerecordingPreference"
+                        }
+                    ]
+                },
+                "review_threshold": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "source_provider": {
+                    "type": "string"
+                },
+                "strict_versions": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationRequest": {
+            "type": "object",
+            "required": [
+                "dest_provider",
+                "playlist_id",
+                "source_provider"
+            ],
+            "properties": {
+                "classical": {
+                    "description": "Classical matches classical works by composer, work and movement\nrather than by literal title, and weights performers less.",
+                    "type": "boolean"
+                },
+                "conflict_policy": {
+                    "description": "ConflictPolicy decides what happens when the destination already has\nthe playlist; see ConflictPolicy. Empty always creates a new one.",
+                    "enum": [
+                        "reuse",
+                        "skip",
+                        "suffix"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ConflictPolicy"
+                        }
+                    ]
+                },
+                "copy_sharing": {
+                    "description": "CopySharing makes the destination playlist public or collaborative\nwhen the source playlist is, as far as the destination supports it.\nBy default migrated playlists are private.",
+                    "type": "boolean"
+                },
+                "dedupe": {
+                    "description": "Dedupe migrates tracks that appear more than once in the source\nplaylist only once.",
+                    "type": "boolean"
+                },
+                "dest_provider": {
+                    "type": "string"
+                },
+                "dest_token": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "description": "DryRun matches tracks without creating or modifying the destination playlist.",
+                    "type": "boolean"
+                },
+                "exclude": {
+                    "description": "Exclude skips the source tracks it matches; they are reported with\nTrackStatusFiltered and not searched for.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackFilter"
+                        }
+                    ]
+                },
+                "genres": {
+                    "description": "Genres, if set, migrates only the source tracks of any of these\ngenres, e.g. [\"jazz\"] to take the jazz tracks out of a mixed\nplaylist. A genre matches whole words of a track's genres, so \"jazz\"\nmatches \"vocal jazz\". Other tracks, and tracks without a known genre,\nare reported with TrackStatusFiltered.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "market": {
+                    "description": "Market is an ISO 3166-1 alpha-2 country code used when searching the\ndestination provider. Empty uses the provider's default for the token.",
+                    "type": "string"
+                },
+                "matching_strategy": {
+                    "description": "MatchingStrategy trades match quality against coverage; see\nMatchingStrategy. Empty uses the default scoring.",
+                    "enum": [
+                        "isrc_only",
+                        "strict",
+                        "relaxed",
+                        "duration_weighted"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MatchingStrategy"
+                        }
+                    ]
+                },
+                "min_score": {
+                    "description": "MinScore is the confidence a match needs, if it is higher than the\nminimum of the matching strategy.",
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "name_pattern": {
+                    "description": "NamePattern is the name of the destination playlist. The placeholders\n{source}, {dest}, {playlist} (the source playlist's name) and {date}\n(YYYY-MM-DD) are replaced. Empty uses \"Migrated from {source}\".",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "notify_email": {
+                    "description": "NotifyEmail is sent a summary of a queued migration once its job\nfinished or failed, if the server has email notifications enabled\nand the address is in one of its allowed domains.",
+                    "type": "string"
+                },
+                "playlist_id": {
+                    "type": "string"
+                },
+                "preserve_order": {
+                    "description": "PreserveOrder reports source positions left without a destination\ntrack as gaps, and makes retries insert late matches at their original\nrelative position instead of appending them.",
+                    "type": "boolean"
+                },
+                "rerecordings": {
+                    "description": "Rerecordings picks between the original recording of a track and a\nre-recording of it when the destination has both; see\nRerecordingPreference.",
+                    "enum": [
+                        "any",
+                        "prefer",
+                        "avoid"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.RerecordingPreference"
+                        }
+                    ]
+                },
+                "review_threshold": {
+                    "description": "ReviewThreshold, if set, splits the matches by confidence: matches\nscoring below it go into a second destination playlist named\n\"<name> (Review)\" instead of the main one, so they can be checked by\nhand without mixing doubtful tracks into the migrated playlist.",
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "source_provider": {
+                    "type": "string"
+                },
+                "source_token": {
+                    "type": "string"
+                },
+                "strict_versions": {
+                    "description": "StrictVersions refuses to match a track to a different version of\nit, such as a studio track to a live recording or a remix. By default\nsuch candidates are only penalized.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationResult": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "classical": {
+                    "type": "boolean"
+                },
+                "concurrency": {
+                    "description": "Concurrency reports how search parallelism adapted to rate limiting\nduring the latest search pass.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ConcurrencyStats"
+                        }
+                    ]
+                },
+                "copy_sharing": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dest_playlist_description": {
+                    "type": "string"
+                },
+                "dest_playlist_id": {
+                    "type": "string"
+                },
+                "dest_playlist_ids": {
+                    "description": "DestPlaylistIDs lists every destination playlist, in order, when the\nmatched tracks exceeded the destination's playlist size limit and were\nsplit into parts named \"<name> (1/3)\" and so on. DestPlaylistID is the\nfirst part; DestPosition of a track counts across all parts.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dest_playlist_name": {
+                    "description": "DestPlaylistName and DestPlaylistDescription are the texts the\ndestination playlist was created with, after sanitizing them for the\ndestination provider.",
+                    "type": "string"
+                },
+                "dest_provider": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed_tracks": {
+                    "type": "integer"
+                },
+                "filtered_tracks": {
+                    "type": "integer"
+                },
+                "gaps": {
+                    "description": "Gaps lists the source positions that have no track in the destination\nplaylist. It is only reported when PreserveOrder is set.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "idempotency_key": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "matched_tracks": {
+                    "type": "integer"
+                },
+                "matching_strategy": {
+                    "description": "MatchingStrategy is the strategy the tracks were matched with, and\nMinScore the minimum confidence the request asked for.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MatchingStrategy"
+                        }
+                    ]
+                },
+                "merged_from": {
+                    "description": "MergedFrom lists the source playlists of a merge, in request order.\nSourceProvider and SourcePlaylist are empty for merges.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MergeSource"
+                    }
+                },
+                "min_score": {
+                    "type": "number"
+                },
+                "preserve_order": {
+                    "type": "boolean"
+                },
+                "quota_units_used": {
+                    "description": "QuotaUnitsUsed reports API quota units consumed per provider, for\nproviders with unit-based quotas (e.g. YouTube).",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "rerecordings": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.RerecordingPreference"
+                },
+                "reused_playlist": {
+                    "description": "ReusedPlaylist is true if the tracks were added to a destination\nplaylist that already existed, under the \"reuse\" conflict policy.\nRolling back such a migration removes the added tracks instead of\ndeleting the playlist.",
+                    "type": "boolean"
+                },
+                "reversed_from": {
+                    "type": "string"
+                },
+                "review_playlist_id": {
+                    "type": "string"
+                },
+                "review_threshold": {
+                    "description": "ReviewThreshold is the review threshold the request asked for.\nReviewPlaylistID is the review playlist holding the ReviewTracks\nmatches that scored below it, if there were any; those matches are\ncounted in MatchedTracks too.",
+                    "type": "number"
+                },
+                "review_tracks": {
+                    "type": "integer"
+                },
+                "rolled_back": {
+                    "type": "boolean"
+                },
+                "source_playlist": {
+                    "type": "string"
+                },
+                "source_provider": {
+                    "type": "string"
+                },
+                "strict_versions": {
+                    "type": "boolean"
+                },
+                "timing": {
+                    "description": "Timing reports how long the latest run, the migration or a retry,\nspent in each stage.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationTiming"
+                        }
+                    ]
+                },
+                "total_episodes": {
+                    "type": "integer"
+                },
+                "total_tracks": {
+                    "type": "integer"
+                },
+                "track_results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackResult"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "error_breakdown": {
+                    "description": "ErrorBreakdown counts the failed tracks by cause: the error code of\nclassified failures, such as rate_limited or unavailable_in_market,\nor else their status, such as not_found. Its counts add up to\nFailedTracks.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationTiming": {
+            "type": "object",
+            "properties": {
+                "add_ms": {
+                    "type": "integer"
+                },
+                "create_ms": {
+                    "type": "integer"
+                },
+                "fetch_ms": {
+                    "type": "integer"
+                },
+                "search_ms": {
+                    "type": "integer"
+                },
+                "total_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.Playlist": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_collaborative": {
+                    "type": "boolean"
+                },
+                "is_owner": {
+                    "description": "IsOwner is false for playlists the user follows or collaborates on\nbut does not own. IsCollaborative playlists can be edited by every\nfollower the owner invited.",
+                    "type": "boolean"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_name": {
+                    "type": "string"
+                },
+                "track_count": {
+                    "type": "integer"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
+                    }
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.PlaylistUpdate": {
+            "type": "object",
+            "properties": {
+                "collaborative": {
+                    "description": "Collaborative lets followers the owner invited edit the playlist. Only\nproviders implementing ports.CollaborativePlaylists support it;\ncollaborative playlists are always private.",
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "public": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.ProfileMigrationRequest": {
+            "type": "object",
+            "required": [
+                "playlist_id"
+            ],
+            "properties": {
+                "dest_token": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "playlist_id": {
+                    "type": "string"
+                },
+                "source_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderConnection": {
+            "type": "object",
+            "properties": {
+                "expired": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "refreshable": {
+                    "description": "Refreshable is true if a refresh token is stored and the provider can\nrenew expired tokens with it, so an expired token still works.",
+                    "type": "boolean"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationRequest": {
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderExchange": {
             "type": "object",
-            "required": [
-                "dest_provider",
-                "dest_token",
-                "playlist_id",
-                "source_provider",
-                "source_token"
-            ],
             "properties": {
-                "dest_provider": {
+                "body": {
                     "type": "string"
                 },
-                "dest_token": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "Error is set instead of Status if no response was received.",
                     "type": "string"
                 },
-                "playlist_id": {
+                "method": {
                     "type": "string"
                 },
-                "source_provider": {
+                "status": {
+                    "type": "integer"
+                },
+                "time": {
                     "type": "string"
                 },
-                "source_token": {
+                "truncated": {
+                    "type": "boolean"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "github_com_jpp0ca_MusicMigration-API_internal_domain.MigrationResult": {
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderHealth": {
             "type": "object",
             "properties": {
-                "dest_playlist_id": {
+                "error": {
                     "type": "string"
                 },
-                "failed_tracks": {
+                "latency_ms": {
                     "type": "integer"
                 },
-                "matched_tracks": {
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 },
-                "source_playlist": {
+                "status": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.HealthStatus"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderStatus": {
+            "type": "object",
+            "properties": {
+                "disabled_reason": {
                     "type": "string"
                 },
-                "total_tracks": {
-                    "type": "integer"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "track_results": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.ProviderToken": {
+            "type": "object",
+            "required": [
+                "access_token"
+            ],
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Scopes lists the OAuth scopes granted to the token, as reported by\nthe provider's token response.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackResult"
+                        "type": "string"
                     }
                 }
             }
         },
-        "github_com_jpp0ca_MusicMigration-API_internal_domain.Playlist": {
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.RegisterAccountRequest": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "description": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.RegisterAccountResponse": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Account"
+                },
+                "api_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.RemoveTracksRequest": {
+            "type": "object",
+            "required": [
+                "track_ids"
+            ],
+            "properties": {
+                "track_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.RerecordingPreference": {
+            "type": "string",
+            "enum": [
+                "",
+                "any",
+                "prefer",
+                "avoid"
+            ],
+            "x-enum-comments": {
+                "RerecordingsAny": "RerecordingsAny leaves the choice to the score alone, without the\nrelease year tie-breaker.",
+                "RerecordingsAvoid": "RerecordingsAvoid picks the original recording.",
+                "RerecordingsDefault": "RerecordingsDefault leaves the choice to the score, in which the\nrelease year breaks ties, so that a re-recording released years later\ndoes not replace the original.",
+                "RerecordingsPrefer": "RerecordingsPrefer picks the re-recording."
+            },
+            "x-enum-varnames": [
+                "RerecordingsDefault",
+                "RerecordingsAny",
+                "RerecordingsPrefer",
+                "RerecordingsAvoid"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.ReverseMigrationRequest": {
+            "type": "object",
+            "properties": {
+                "dest_token": {
                     "type": "string"
                 },
+                "dry_run": {
+                    "description": "DryRun matches tracks without creating the playlist.",
+                    "type": "boolean"
+                },
+                "source_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.Show": {
+            "type": "object",
+            "properties": {
                 "id": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "owner_name": {
+                "publisher": {
                     "type": "string"
-                },
-                "track_count": {
-                    "type": "integer"
-                },
-                "tracks": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
-                    }
                 }
             }
         },
@@ -242,35 +5087,254 @@ const docTemplate = `{
                 "album": {
                     "type": "string"
                 },
+                "album_art_url": {
+                    "description": "AlbumArtURL and PreviewURL let clients show artwork and play a short\naudio preview, e.g. when reviewing low-confidence matches. Providers\nleave them empty when unavailable.",
+                    "type": "string"
+                },
                 "artist": {
+                    "description": "Artist is the artists joined with \", \", for clients that read a single\nartist. Requests may send it instead of artists; it is then taken as one name.",
                     "type": "string"
                 },
+                "artists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "duration_ms": {
+                    "description": "DurationMS is the length of the track, or 0 if the provider does not\nreport it.",
+                    "type": "integer"
+                },
+                "explicit": {
+                    "description": "Explicit is true if the provider marks the track as explicit.\nProviders that do not rate content leave it false.",
+                    "type": "boolean"
+                },
                 "external_id": {
                     "type": "string"
                 },
+                "genres": {
+                    "description": "Genres are the genres of the track as the provider reports them: of\nits artists on Spotify, from the file tags of local files. Spotify\ntracks only carry them in migrations that filter by genre.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "isrc": {
                     "type": "string"
                 },
+                "musicbrainz_id": {
+                    "description": "MusicBrainzID is the MusicBrainz recording ID, when the provider\nknows it.",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
+                },
+                "popularity": {
+                    "type": "integer"
+                },
+                "preview_url": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "description": "ReleaseDate is the release date of the track's album as the provider\nreports it: YYYY, YYYY-MM or YYYY-MM-DD. Popularity is the provider's\nrating of how much the track is played, from 0 to 100. Providers leave\nthem empty when unavailable.",
+                    "type": "string"
+                },
+                "show": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Show"
+                },
+                "type": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.ItemType"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.TrackCandidate": {
+            "type": "object",
+            "properties": {
+                "confidence_score": {
+                    "type": "number"
+                },
+                "track": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.TrackChange": {
+            "type": "object",
+            "properties": {
+                "a": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackResult"
+                },
+                "b": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackResult"
+                },
+                "kind": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackChangeKind"
+                },
+                "source": {
+                    "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
+                }
+            }
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.TrackChangeKind": {
+            "type": "string",
+            "enum": [
+                "matched",
+                "unmatched",
+                "rematched",
+                "status_changed",
+                "added",
+                "removed"
+            ],
+            "x-enum-comments": {
+                "TrackChangeAdded": "TrackChangeAdded and TrackChangeRemoved are tracks the source\nplaylist only had when B, respectively A, ran.",
+                "TrackChangeMatched": "TrackChangeMatched is a track matched only in B.",
+                "TrackChangeRematched": "TrackChangeRematched is a track matched to different tracks.",
+                "TrackChangeStatus": "TrackChangeStatus is a track matched in neither, with another status.",
+                "TrackChangeUnmatched": "TrackChangeUnmatched is a track matched only in A."
+            },
+            "x-enum-varnames": [
+                "TrackChangeMatched",
+                "TrackChangeUnmatched",
+                "TrackChangeRematched",
+                "TrackChangeStatus",
+                "TrackChangeAdded",
+                "TrackChangeRemoved"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.TrackErrorCode": {
+            "type": "string",
+            "enum": [
+                "rate_limited",
+                "timeout",
+                "cancelled",
+                "quota_exceeded",
+                "invalid_token",
+                "provider_error",
+                "unavailable_in_market",
+                "unavailable",
+                "unsupported",
+                "rejected"
+            ],
+            "x-enum-comments": {
+                "ErrorCodeRateLimited": "Transient failures, which may succeed when retried. An invalid token\nsucceeds with a new one; an exceeded quota once it resets.",
+                "ErrorCodeUnavailableInMarket": "Permanent failures, which fail again when retried. ErrorCodeRejected\nmeans the destination refused the track itself, e.g. because the\nvideo was deleted."
+            },
+            "x-enum-varnames": [
+                "ErrorCodeRateLimited",
+                "ErrorCodeTimeout",
+                "ErrorCodeCancelled",
+                "ErrorCodeQuotaExceeded",
+                "ErrorCodeInvalidToken",
+                "ErrorCodeProviderError",
+                "ErrorCodeUnavailableInMarket",
+                "ErrorCodeUnavailable",
+                "ErrorCodeUnsupported",
+                "ErrorCodeRejected"
+            ]
+        },
+        "github_com_jpp0ca_MusicMigration-API_internal_domain.TrackFilter": {
+            "type": "object",
+            "properties": {
+                "artists": {
+                    "description": "Artists excludes the tracks of any of these artists, compared without\nregard to case.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "explicit": {
+                    "description": "Explicit excludes tracks the source provider marks as explicit.",
+                    "type": "boolean"
+                },
+                "genres": {
+                    "description": "Genres excludes tracks of any of these genres, matched like\nMigrationRequest.Genres.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title_patterns": {
+                    "description": "TitlePatterns excludes tracks whose title matches any of these\nregular expressions (RE2 syntax), without regard to case, e.g.\n\"sped up\" or \"^interlude\".",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
         "github_com_jpp0ca_MusicMigration-API_internal_domain.TrackResult": {
             "type": "object",
             "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "cached": {
+                    "description": "Cached is true if the destination answered the search from its search\ncache, so it cost no API quota.",
+                    "type": "boolean"
+                },
+                "candidates": {
+                    "description": "Candidates are the best search results the destination ranked below\nthe match, best first, for picking another one by hand.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackCandidate"
+                    }
+                },
                 "confidence_score": {
                     "type": "number"
                 },
+                "deferrals": {
+                    "type": "integer"
+                },
+                "dest_position": {
+                    "type": "integer"
+                },
                 "error": {
                     "type": "string"
                 },
+                "error_code": {
+                    "description": "ErrorCode classifies the failure of a track with status error,\nadd_failed, unavailable_in_market, unavailable or unsupported.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackErrorCode"
+                        }
+                    ]
+                },
+                "existing": {
+                    "description": "Existing is true if the track was already in the reused destination\nplaylist, so it was not added again.",
+                    "type": "boolean"
+                },
+                "fingerprinted": {
+                    "description": "Fingerprinted is true if the track was matched by the recording the\nacoustic fingerprint of its audio identified, after a search by its\nown metadata found nothing.",
+                    "type": "boolean"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
                 "matched": {
                     "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
                 },
+                "retries": {
+                    "type": "integer"
+                },
+                "retryable": {
+                    "description": "Retryable tells whether retrying the failure may succeed; it is false\nfor tracks that did not fail. Retrying failed tracks skips those that\nare not retryable.",
+                    "type": "boolean"
+                },
+                "review": {
+                    "description": "Review is true if the match scored below the review threshold and\nwent into the review playlist; DestPosition is then its index there.",
+                    "type": "boolean"
+                },
+                "search_ms": {
+                    "description": "SearchMS is how long the track was searched for, including retries\nand the waits between them; LatencyMS is the duration of the last\nprovider call. Attempts counts the searches made: searches rate\nlimited by the provider are retried, and Retries counts those.\nSearches still rate limited after their retries are put back into the\nmigration's retry queue until the provider's Retry-After window has\npassed; Deferrals counts those. All are zero for tracks that were not\nsearched.",
+                    "type": "integer"
+                },
                 "source": {
                     "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.Track"
                 },
+                "source_position": {
+                    "description": "SourcePosition is the 0-based index of the track in the source\nplaylist; DestPosition is its index in the destination playlist, or nil\nif it was not added. In dry runs DestPosition is where it would go.",
+                    "type": "integer"
+                },
                 "status": {
                     "$ref": "#/definitions/github_com_jpp0ca_MusicMigration-API_internal_domain.TrackStatus"
                 }
@@ -281,20 +5345,79 @@ const docTemplate = `{
             "enum": [
                 "matched",
                 "not_found",
-                "error"
+                "error",
+                "unavailable_in_market",
+                "unsupported",
+                "add_failed",
+                "unavailable",
+                "needs_review",
+                "filtered"
             ],
+            "x-enum-comments": {
+                "TrackStatusFiltered": "TrackStatusFiltered marks a track the request's exclusion filter left\nout; Error names the rule that matched. It is neither searched for\nnor counted as failed.",
+                "TrackStatusNeedsReview": "TrackStatusNeedsReview marks a track whose best candidate was not\nconfirmed by ISRC under the isrc_only strategy. The candidate is kept\nas a suggestion but not added to the destination playlist.",
+                "TrackStatusUnavailable": "TrackStatusUnavailable marks a track the destination has but cannot\nplay in any market, unlike TrackStatusUnavailableInMarket, which\nanother market may play, and TrackStatusNotFound, which the\ndestination does not have. MatchedTrack is the unavailable track."
+            },
             "x-enum-varnames": [
                 "TrackStatusMatched",
                 "TrackStatusNotFound",
-                "TrackStatusError"
+                "TrackStatusError",
+                "TrackStatusUnavailableInMarket",
+                "TrackStatusUnsupported",
+                "TrackStatusAddFailed",
+                "TrackStatusUnavailable",
+                "TrackStatusNeedsReview",
+                "TrackStatusFiltered"
             ]
         },
+        "internal_adapters_http.AuthorizationResponse": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_adapters_http.DisableProviderRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
         "internal_adapters_http.ErrorResponse": {
             "type": "object",
             "properties": {
                 "error": {
                     "type": "string"
                 },
+                "fields": {
+                    "description": "Fields details each rejected field when Error is \"validation_failed\".",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_adapters_http.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "resets_at": {
+                    "description": "ResetsAt is when an account limit allows the request again, if\nknown, when Error is \"limit_exceeded\".",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_adapters_http.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is a stable, machine-readable reason such as \"required\",\n\"invalid_type\" or \"unknown_provider\".",
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
                 "message": {
                     "type": "string"
                 }
@@ -302,6 +5425,18 @@ const docTemplate = `{
         }
     },
     "securityDefinitions": {
+        "APIKeyAuth": {
+            "description": "Account API key, required on /api/v1 routes when AUTH_ENABLED=true",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "AdminKeyAuth": {
+            "description": "Admin key for /admin routes, set with ADMIN_API_KEY",
+            "type": "apiKey",
+            "name": "X-Admin-Key",
+            "in": "header"
+        },
         "BearerAuth": {
             "description": "Bearer token for the streaming provider (e.g. \"Bearer your_token_here\")",
             "type": "apiKey",
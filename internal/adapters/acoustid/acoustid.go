@@ -0,0 +1,266 @@
+// Package acoustid identifies recordings by their acoustic fingerprint,
+// computed with Chromaprint's fpcalc tool and looked up with the AcoustID
+// web service.
+package acoustid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+const (
+	lookupURL = "https://api.acoustid.org/v2/lookup"
+
+	// requestInterval spaces lookups to the three requests per second
+	// AcoustID allows a client.
+	requestInterval = 334 * time.Millisecond
+
+	// fingerprintSeconds is how much of the audio fpcalc fingerprints.
+	fingerprintSeconds = 120
+)
+
+// Identifier implements ports.Fingerprinter with fpcalc and AcoustID.
+type Identifier struct {
+	client *http.Client
+	apiKey string
+	url    string
+
+	// fingerprint computes the fingerprint and duration in seconds of the
+	// audio file at path.
+	fingerprint func(ctx context.Context, path string) (string, int, error)
+
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// NewIdentifier creates an identifier looking fingerprints up with an
+// AcoustID application API key, computing them with the fpcalc executable
+// at fpcalc, found in PATH if it has no directory. If client is nil,
+// http.DefaultClient is used.
+func NewIdentifier(client *http.Client, apiKey, fpcalc string) *Identifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Identifier{
+		client:      client,
+		apiKey:      apiKey,
+		url:         lookupURL,
+		fingerprint: fpcalcFingerprint(fpcalc),
+		interval:    requestInterval,
+	}
+}
+
+// -- API response types (internal) ------------------------------------------
+
+type lookupResponse struct {
+	Status string `json:"status"`
+	Error  struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	Results []struct {
+		Score      float64     `json:"score"`
+		Recordings []recording `json:"recordings"`
+	} `json:"results"`
+}
+
+type recording struct {
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Duration float64 `json:"duration"` // seconds
+	Artists  []struct {
+		Name string `json:"name"`
+	} `json:"artists"`
+	ReleaseGroups []struct {
+		Title string `json:"title"`
+	} `json:"releasegroups"`
+}
+
+func (r recording) toDomain() domain.Track {
+	t := domain.Track{
+		Name:          r.Title,
+		DurationMS:    int(r.Duration * 1000),
+		MusicBrainzID: r.ID,
+		Type:          domain.ItemTypeTrack,
+	}
+	for _, a := range r.Artists {
+		t.Artists = append(t.Artists, a.Name)
+	}
+	if len(r.ReleaseGroups) > 0 {
+		t.Album = r.ReleaseGroups[0].Title
+	}
+	return t
+}
+
+// -- Port implementation ----------------------------------------------------
+
+// IdentifyAudio fingerprints audio and returns the first titled recording
+// of the best-scored AcoustID result, with the score of that result.
+func (id *Identifier) IdentifyAudio(ctx context.Context, audio io.Reader) (*domain.Track, float64, error) {
+	f, err := os.CreateTemp("", "acoustid-*")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer os.Remove(f.Name())
+	_, err = io.Copy(f, audio)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("acoustid: failed to read audio: %w", err)
+	}
+
+	fingerprint, duration, err := id.fingerprint(ctx, f.Name())
+	if err != nil {
+		return nil, 0, fmt.Errorf("acoustid: fingerprinting failed: %w", err)
+	}
+	if fingerprint == "" {
+		return nil, 0, nil
+	}
+
+	resp, err := id.lookup(ctx, fingerprint, duration)
+	if err != nil {
+		return nil, 0, fmt.Errorf("acoustid: lookup failed: %w", err)
+	}
+	var best *domain.Track
+	bestScore := 0.0
+	for _, result := range resp.Results {
+		if result.Score <= bestScore {
+			continue
+		}
+		for _, rec := range result.Recordings {
+			if rec.Title != "" && len(rec.Artists) > 0 {
+				track := rec.toDomain()
+				best, bestScore = &track, result.Score
+				break
+			}
+		}
+	}
+	return best, bestScore, nil
+}
+
+// -- HTTP helpers ------------------------------------------------------------
+
+// apiError is returned by lookup when AcoustID responds with an error.
+type apiError struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("acoustid API returned status %d, error %d: %s", e.StatusCode, e.Code, adapters.ErrorBody(e.Message))
+}
+
+// Is reports throttled requests as domain.ErrRateLimited.
+func (e *apiError) Is(target error) bool {
+	return target == domain.ErrRateLimited &&
+		(e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusServiceUnavailable)
+}
+
+// lookup posts a fingerprint to AcoustID, once the request interval has
+// passed. Fingerprints are long, so they are sent as a form.
+func (id *Identifier) lookup(ctx context.Context, fingerprint string, duration int) (*lookupResponse, error) {
+	if err := id.wait(ctx); err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"client":      {id.apiKey},
+		"format":      {"json"},
+		"meta":        {"recordings releasegroups"},
+		"duration":    {strconv.Itoa(duration)},
+		"fingerprint": {fingerprint},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, id.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := id.client.Do(req)
+	if err != nil {
+		return nil, adapters.SanitizeError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var out lookupResponse
+	if err := json.Unmarshal(body, &out); err != nil || out.Status != "ok" {
+		if out.Error.Message == "" {
+			out.Error.Message = string(body)
+		}
+		return nil, &apiError{StatusCode: resp.StatusCode, Code: out.Error.Code, Message: out.Error.Message}
+	}
+	return &out, nil
+}
+
+// wait blocks until the request interval since the previous request has
+// passed, or ctx is done.
+func (id *Identifier) wait(ctx context.Context) error {
+	id.mu.Lock()
+	delay := max(time.Until(id.next), 0)
+	id.next = time.Now().Add(delay + id.interval)
+	id.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// -- Fingerprinting ------------------------------------------------------------
+
+// fpcalcFingerprint returns a fingerprint function running the fpcalc
+// executable at path.
+func fpcalcFingerprint(path string) func(ctx context.Context, file string) (string, int, error) {
+	return func(ctx context.Context, file string) (string, int, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, path, "-json", "-length", strconv.Itoa(fingerprintSeconds), file)
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", 0, fmt.Errorf("%w: %s", err, msg)
+			}
+			return "", 0, err
+		}
+		return parseFpcalc(stdout.Bytes())
+	}
+}
+
+// parseFpcalc reads the fingerprint and duration of fpcalc's JSON output.
+func parseFpcalc(out []byte) (string, int, error) {
+	var result struct {
+		Duration    float64 `json:"duration"`
+		Fingerprint string  `json:"fingerprint"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return "", 0, errors.New("unexpected fpcalc output")
+	}
+	return result.Fingerprint, int(result.Duration), nil
+}
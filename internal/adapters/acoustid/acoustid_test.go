@@ -0,0 +1,91 @@
+package acoustid
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIdentifier(t *testing.T, handler http.HandlerFunc) *Identifier {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	id := NewIdentifier(srv.Client(), "app-key", "fpcalc")
+	id.url = srv.URL
+	id.interval = 0
+	id.fingerprint = func(_ context.Context, path string) (string, int, error) {
+		audio, err := os.ReadFile(path)
+		return "FP:" + string(audio), 215, err
+	}
+	return id
+}
+
+func TestIdentifyAudio_BestScoredRecording(t *testing.T) {
+	id := newTestIdentifier(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "app-key", r.PostForm.Get("client"))
+		assert.Equal(t, "FP:audio", r.PostForm.Get("fingerprint"))
+		assert.Equal(t, "215", r.PostForm.Get("duration"))
+		assert.Equal(t, "recordings releasegroups", r.PostForm.Get("meta"))
+		fmt.Fprint(w, `{"status":"ok","results":[
+			{"score":0.6,"recordings":[{"id":"mb-other","title":"Other","artists":[{"name":"Someone"}]}]},
+			{"score":0.93,"recordings":[
+				{"id":"mb-untitled"},
+				{"id":"mb-1","title":"One","duration":215.4,"artists":[{"name":"Band"},{"name":"Guest"}],"releasegroups":[{"title":"First"}]}
+			]}
+		]}`)
+	})
+
+	track, score, err := id.IdentifyAudio(context.Background(), strings.NewReader("audio"))
+	require.NoError(t, err)
+	assert.Equal(t, 0.93, score)
+	assert.Equal(t, &domain.Track{
+		Name: "One", Artists: []string{"Band", "Guest"}, Album: "First",
+		DurationMS: 215400, MusicBrainzID: "mb-1", Type: domain.ItemTypeTrack,
+	}, track)
+}
+
+func TestIdentifyAudio_Unknown(t *testing.T) {
+	id := newTestIdentifier(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"ok","results":[{"score":0.9,"recordings":[{"id":"mb-untitled"}]}]}`)
+	})
+
+	track, score, err := id.IdentifyAudio(context.Background(), strings.NewReader("audio"))
+	require.NoError(t, err)
+	assert.Nil(t, track)
+	assert.Zero(t, score)
+}
+
+func TestIdentifyAudio_Errors(t *testing.T) {
+	id := newTestIdentifier(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"status":"error","error":{"code":14,"message":"rate limit exceeded"}}`)
+	})
+	_, _, err := id.IdentifyAudio(context.Background(), strings.NewReader("audio"))
+	assert.ErrorIs(t, err, domain.ErrRateLimited)
+	assert.ErrorContains(t, err, "rate limit exceeded")
+
+	id = newTestIdentifier(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"error","error":{"code":4,"message":"invalid API key"}}`)
+	})
+	_, _, err = id.IdentifyAudio(context.Background(), strings.NewReader("audio"))
+	assert.NotErrorIs(t, err, domain.ErrRateLimited)
+	assert.ErrorContains(t, err, "invalid API key")
+}
+
+func TestParseFpcalc(t *testing.T) {
+	fp, duration, err := parseFpcalc([]byte(`{"duration": 215.87, "fingerprint": "AQAAT0mUaEkSRZEGAA"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "AQAAT0mUaEkSRZEGAA", fp)
+	assert.Equal(t, 215, duration)
+
+	_, _, err = parseFpcalc([]byte("ERROR: Could not open the input file"))
+	assert.Error(t, err)
+}
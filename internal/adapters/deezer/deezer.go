@@ -0,0 +1,200 @@
+// Package deezer provides a metadata source that looks tracks up in the
+// Deezer catalog through its public API, which needs no credentials.
+package deezer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/pkg/matching"
+)
+
+// SourceName is the name the Deezer metadata source goes by.
+const SourceName = "deezer"
+
+const (
+	baseURL = "https://api.deezer.com/"
+
+	// searchLimit is the number of tracks a search considers.
+	searchLimit = 5
+
+	// minScore is the score a searched track needs to be taken for the
+	// track looked up.
+	minScore = 0.8
+)
+
+// Source implements ports.MetadataSource using the public Deezer API.
+type Source struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewSource creates a Deezer metadata source. If client is nil,
+// http.DefaultClient is used.
+func NewSource(client *http.Client) *Source {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Source{client: client, baseURL: baseURL}
+}
+
+func (s *Source) Name() string {
+	return SourceName
+}
+
+// -- API response types (internal) ------------------------------------------
+
+type trackResource struct {
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	ISRC     string `json:"isrc"`
+	Duration int    `json:"duration"` // seconds
+	Artist   struct {
+		Name string `json:"name"`
+	} `json:"artist"`
+	Album struct {
+		Title string `json:"title"`
+	} `json:"album"`
+}
+
+func (t trackResource) toMetadata() *domain.TrackMetadata {
+	return &domain.TrackMetadata{ISRC: t.ISRC, DurationMS: t.Duration * 1000, Album: t.Album.Title}
+}
+
+func (t trackResource) toMatch() matching.Track {
+	return matching.Track{
+		Name:     t.Title,
+		Artists:  []string{t.Artist.Name},
+		Album:    t.Album.Title,
+		Duration: time.Duration(t.Duration) * time.Second,
+	}
+}
+
+// -- Port implementation ----------------------------------------------------
+
+// LookupMetadata looks the track up by its ISRC if it has one, or else
+// searches tracks by name and artist and takes the best-scored one if it
+// scores at least minScore. Search results carry no ISRC, so the chosen
+// track is then fetched by ID.
+func (s *Source) LookupMetadata(ctx context.Context, track domain.Track) (*domain.TrackMetadata, error) {
+	if track.ISRC != "" {
+		var t trackResource
+		if err := s.get(ctx, "track/isrc:"+url.PathEscape(track.ISRC), nil, &t); err != nil {
+			return nil, fmt.Errorf("deezer: ISRC lookup failed: %w", err)
+		}
+		if t.ID == 0 {
+			return nil, nil
+		}
+		return t.toMetadata(), nil
+	}
+	if track.Name == "" || len(track.Artists) == 0 {
+		return nil, nil
+	}
+
+	var resp struct {
+		Data []trackResource `json:"data"`
+	}
+	query := fmt.Sprintf(`artist:"%s" track:"%s"`, escape(track.Artists[0]), escape(track.Name))
+	err := s.get(ctx, "search/track", url.Values{"q": {query}, "limit": {fmt.Sprint(searchLimit)}}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("deezer: track search failed: %w", err)
+	}
+	candidates := make([]matching.Track, len(resp.Data))
+	for i, t := range resp.Data {
+		candidates[i] = t.toMatch()
+	}
+	best, score := matching.BestCandidate(matching.Catalog, adapters.MatchTrack(track), candidates)
+	if best < 0 || score < minScore {
+		return nil, nil
+	}
+
+	found := resp.Data[best]
+	var full trackResource
+	if err := s.get(ctx, fmt.Sprintf("track/%d", found.ID), nil, &full); err != nil {
+		return nil, fmt.Errorf("deezer: track lookup failed: %w", err)
+	}
+	if full.ID == 0 {
+		return found.toMetadata(), nil
+	}
+	return full.toMetadata(), nil
+}
+
+// escape removes the quotes of s, which would end a quoted term of an
+// advanced search query.
+func escape(s string) string {
+	return strings.ReplaceAll(s, `"`, "")
+}
+
+// -- HTTP helpers ------------------------------------------------------------
+
+// Deezer API error codes, see https://developers.deezer.com/api/errors.
+const (
+	errQuotaExceeded = 4
+	errDataNotFound  = 800
+)
+
+// apiError is returned by get when Deezer responds with an error.
+type apiError struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("deezer API returned status %d, error %d: %s", e.StatusCode, e.Code, adapters.ErrorBody(e.Message))
+}
+
+// Is reports quota errors as domain.ErrRateLimited.
+func (e *apiError) Is(target error) bool {
+	return target == domain.ErrRateLimited &&
+		(e.Code == errQuotaExceeded || e.StatusCode == http.StatusTooManyRequests)
+}
+
+// get calls an API endpoint and decodes its JSON response into out. Unknown
+// tracks leave out untouched.
+func (s *Source) get(ctx context.Context, path string, params url.Values, out any) error {
+	target := s.baseURL + path
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return adapters.SanitizeError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &apiError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	// Errors come with a 200 status and an error object.
+	var apiErr struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != nil {
+		if apiErr.Error.Code == errDataNotFound {
+			return nil
+		}
+		return &apiError{StatusCode: resp.StatusCode, Code: apiErr.Error.Code, Message: apiErr.Error.Message}
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,71 @@
+package deezer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSource(t *testing.T, handler http.HandlerFunc) *Source {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	s := NewSource(srv.Client())
+	s.baseURL = srv.URL + "/"
+	return s
+}
+
+func TestLookupMetadata_ByISRC(t *testing.T) {
+	s := newTestSource(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/track/isrc:GBAAA0000001", r.URL.Path)
+		fmt.Fprint(w, `{"id":1,"title":"One","isrc":"GBAAA0000001","duration":215,"album":{"title":"First"},"artist":{"name":"Band"}}`)
+	})
+
+	m, err := s.LookupMetadata(context.Background(), domain.Track{Name: "One", ISRC: "GBAAA0000001"})
+	require.NoError(t, err)
+	assert.Equal(t, &domain.TrackMetadata{ISRC: "GBAAA0000001", DurationMS: 215000, Album: "First"}, m)
+}
+
+func TestLookupMetadata_SearchThenFetch(t *testing.T) {
+	s := newTestSource(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search/track":
+			assert.Equal(t, `artist:"Band" track:"One"`, r.URL.Query().Get("q"))
+			fmt.Fprint(w, `{"data":[
+				{"id":7,"title":"One (Karaoke)","duration":200,"artist":{"name":"Karaoke Hits"},"album":{"title":"Hits"}},
+				{"id":8,"title":"One","duration":215,"artist":{"name":"Band"},"album":{"title":"First"}}
+			]}`)
+		case "/track/8":
+			fmt.Fprint(w, `{"id":8,"title":"One","isrc":"GBAAA0000001","duration":215,"artist":{"name":"Band"},"album":{"title":"First"}}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	m, err := s.LookupMetadata(context.Background(), domain.Track{Name: "One", Artists: []string{"Band"}})
+	require.NoError(t, err)
+	assert.Equal(t, &domain.TrackMetadata{ISRC: "GBAAA0000001", DurationMS: 215000, Album: "First"}, m)
+}
+
+func TestLookupMetadata_Errors(t *testing.T) {
+	s := newTestSource(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/track/isrc:UNKNOWN" {
+			fmt.Fprint(w, `{"error":{"type":"DataException","message":"no data","code":800}}`)
+			return
+		}
+		fmt.Fprint(w, `{"error":{"type":"Exception","message":"Quota limit exceeded","code":4}}`)
+	})
+
+	m, err := s.LookupMetadata(context.Background(), domain.Track{ISRC: "UNKNOWN"})
+	require.NoError(t, err)
+	assert.Nil(t, m)
+
+	_, err = s.LookupMetadata(context.Background(), domain.Track{ISRC: "GBAAA0000001"})
+	assert.ErrorIs(t, err, domain.ErrRateLimited)
+	assert.ErrorContains(t, err, "Quota limit exceeded")
+}
@@ -0,0 +1,45 @@
+package adapters
+
+import (
+	"strings"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/httpdebug"
+)
+
+// maxErrorBody is how much of a provider response body an error message
+// keeps.
+const maxErrorBody = 512
+
+// ErrorBody returns a provider response body for use in an error message:
+// with credentials redacted and cut to maxErrorBody bytes, so errors
+// returned by adapters, which end up in track results, API responses and
+// logs, carry neither tokens nor whole HTML error pages.
+func ErrorBody(body string) string {
+	body = strings.TrimSpace(httpdebug.SanitizeText(body))
+	if len(body) <= maxErrorBody {
+		return body
+	}
+	return strings.ToValidUTF8(body[:maxErrorBody], "") + "..."
+}
+
+// SanitizeError returns err with the credentials in its message redacted,
+// such as an API key in the URL of a request that failed. errors.Is and
+// errors.As still see err.
+func SanitizeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := httpdebug.SanitizeText(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+	return &sanitizedError{msg: msg, err: err}
+}
+
+type sanitizedError struct {
+	msg string
+	err error
+}
+
+func (e *sanitizedError) Error() string { return e.msg }
+func (e *sanitizedError) Unwrap() error { return e.err }
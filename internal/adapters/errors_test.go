@@ -0,0 +1,35 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorBody(t *testing.T) {
+	assert.Equal(t, `{"error": "invalid_grant", "refresh_token": "REDACTED"}`,
+		ErrorBody(`{"error": "invalid_grant", "refresh_token": "rt-1"}`+"\n"))
+
+	page := "<html>" + strings.Repeat("é", maxErrorBody) + "</html>"
+	body := ErrorBody(page)
+	assert.LessOrEqual(t, len(body), maxErrorBody+len("..."))
+	assert.True(t, strings.HasSuffix(body, "..."))
+	assert.NotContains(t, body, "�", "multi-byte characters are not split")
+}
+
+func TestSanitizeError(t *testing.T) {
+	assert.NoError(t, SanitizeError(nil))
+
+	plain := errors.New("connection refused")
+	assert.Same(t, plain, SanitizeError(plain))
+
+	err := SanitizeError(&url.Error{Op: "Get", URL: "https://api.example.com/search?q=x&key=AIza-1", Err: context.DeadlineExceeded})
+	assert.Equal(t, `Get "https://api.example.com/search?q=x&key=REDACTED": context deadline exceeded`, err.Error())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var urlErr *url.Error
+	assert.ErrorAs(t, err, &urlErr)
+}
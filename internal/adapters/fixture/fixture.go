@@ -0,0 +1,134 @@
+// Package fixture replays recorded provider HTTP responses in adapter tests
+// and records them from the real APIs, so adapter changes can be checked
+// against what the providers actually answer without manual testing.
+//
+// A fixture file is a JSON array of exchanges, as written by a Recorder:
+//
+//	client := fixture.Client(t, "testdata/playlist.json")
+//	p := spotify.NewProvider(client)
+package fixture
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/httpdebug"
+)
+
+// Exchange is one recorded request and the response to it. Credentials in
+// the URL and in token responses are redacted.
+type Exchange struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Status int    `json:"status"`
+
+	// Body holds a JSON response body, Text any other response body.
+	Body json.RawMessage `json:"body,omitempty"`
+	Text string          `json:"text,omitempty"`
+}
+
+// key identifies the requests an exchange answers: the method, path and
+// query, without host and with credentials redacted.
+func key(method string, u *url.URL) string {
+	clean, err := url.Parse(httpdebug.SanitizeURL(u))
+	if err != nil {
+		return method + " " + u.Path
+	}
+	return method + " " + clean.Path + "?" + clean.RawQuery
+}
+
+// Load reads the exchanges of the fixture file at path.
+func Load(path string) ([]Exchange, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fixture: %w", err)
+	}
+	var exchanges []Exchange
+	if err := json.Unmarshal(data, &exchanges); err != nil {
+		return nil, fmt.Errorf("fixture: failed to parse %s: %w", path, err)
+	}
+	return exchanges, nil
+}
+
+// Save writes exchanges to the fixture file at path.
+func Save(path string, exchanges []Exchange) error {
+	data, err := json.MarshalIndent(exchanges, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fixture: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("fixture: %w", err)
+	}
+	return nil
+}
+
+// Client returns an HTTP client whose requests, to any host, are answered
+// by an httptest server with the exchanges of the fixture file at path.
+// Requests are matched by method, path and query. Repeated requests get
+// their recorded responses in order, the last one repeating. A request
+// without a recorded response fails t.
+func Client(t testing.TB, path string) *http.Client {
+	t.Helper()
+	exchanges, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	recorded := make(map[string][]Exchange)
+	for _, ex := range exchanges {
+		u, err := url.Parse(ex.URL)
+		if err != nil {
+			t.Fatalf("fixture: %s: bad URL %q: %v", path, ex.URL, err)
+		}
+		k := key(ex.Method, u)
+		recorded[k] = append(recorded[k], ex)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		k := key(r.Method, r.URL)
+		mu.Lock()
+		queue := recorded[k]
+		if len(queue) > 1 {
+			recorded[k] = queue[1:]
+		}
+		mu.Unlock()
+		if len(queue) == 0 {
+			t.Errorf("fixture: %s has no response for %s", path, k)
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+
+		ex := queue[0]
+		if ex.Body != nil {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(ex.Status)
+		if ex.Body != nil {
+			w.Write(ex.Body)
+		} else {
+			w.Write([]byte(ex.Text))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	return &http.Client{Transport: redirect{target: target}}
+}
+
+// redirect sends every request to target, keeping its path and query.
+type redirect struct {
+	target *url.URL
+}
+
+func (t redirect) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
@@ -0,0 +1,69 @@
+package fixture
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/token":
+			fmt.Fprint(w, `{"access_token":"secret","expires_in":3600}`)
+		case "/page":
+			fmt.Fprintf(w, `{"page":%d}`, calls)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "not found")
+		}
+	}))
+	defer srv.Close()
+
+	recorder := NewRecorder(nil)
+	live := &http.Client{Transport: recorder}
+	get := func(client *http.Client, path string) (int, string) {
+		resp, err := client.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+	get(live, "/token?key=abc")
+	get(live, "/page?b=2&a=1")
+	get(live, "/page?a=1&b=2")
+	get(live, "/gone")
+
+	exchanges := recorder.Exchanges()
+	require.Len(t, exchanges, 4)
+	assert.Contains(t, exchanges[0].URL, "key=REDACTED")
+	assert.JSONEq(t, `{"access_token":"REDACTED","expires_in":3600}`, string(exchanges[0].Body))
+	assert.Equal(t, "not found", exchanges[3].Text)
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, recorder.Save(path))
+
+	// The replay answers any host, ignores the order of query parameters
+	// and serves repeated requests in order, repeating the last response.
+	replay := Client(t, path)
+	status, body := get(replay, "/token?key=other")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"access_token":"REDACTED","expires_in":3600}`, body)
+	for _, want := range []string{`{"page":2}`, `{"page":3}`, `{"page":3}`} {
+		_, body = get(replay, "/page?a=1&b=2")
+		assert.JSONEq(t, want, body)
+	}
+	status, body = get(replay, "/gone")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "not found", body)
+	assert.Equal(t, 4, calls, "the replay does not reach the recorded server")
+}
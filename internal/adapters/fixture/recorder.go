@@ -0,0 +1,71 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/httpdebug"
+)
+
+// Recorder is an http.RoundTripper that records every exchange it passes
+// on, for saving as a fixture file. It is safe for concurrent use.
+type Recorder struct {
+	base http.RoundTripper
+
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// NewRecorder wraps base, or http.DefaultTransport if base is nil.
+func NewRecorder(base http.RoundTripper) *Recorder {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Recorder{base: base}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	ex := Exchange{
+		Method: req.Method,
+		URL:    httpdebug.SanitizeURL(req.URL),
+		Status: resp.StatusCode,
+	}
+	switch clean := httpdebug.SanitizeBody(string(body)); {
+	case len(body) == 0:
+	case json.Valid([]byte(clean)):
+		ex.Body = json.RawMessage(clean)
+	default:
+		ex.Text = clean
+	}
+
+	r.mu.Lock()
+	r.exchanges = append(r.exchanges, ex)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Exchanges returns the exchanges recorded so far, in order.
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Exchange(nil), r.exchanges...)
+}
+
+// Save writes the exchanges recorded so far to the fixture file at path.
+func (r *Recorder) Save(path string) error {
+	return Save(path, r.Exchanges())
+}
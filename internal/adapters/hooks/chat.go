@@ -0,0 +1,147 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// UnmatchedReportURL returns the URL of the CSV report of the tracks of a
+// migration that were not matched.
+func UnmatchedReportURL(baseURL, migrationID string) string {
+	return ReportURL(baseURL, migrationID) + "?unmatched=true"
+}
+
+// chatTitle names a migration in chat messages.
+func chatTitle(result *domain.MigrationResult) string {
+	name := result.SourcePlaylist
+	if name == "" {
+		name = result.ID
+	}
+	return fmt.Sprintf("Migration of %s from %s to %s finished", name, result.SourceProvider, result.DestProvider)
+}
+
+// Slack posts a summary of every completed migration to a Slack incoming
+// webhook, linking to the report of its unmatched tracks when there are
+// any.
+type Slack struct {
+	client  *http.Client
+	url     string
+	baseURL string
+}
+
+// NewSlack creates a Slack hook posting to an incoming webhook URL. baseURL
+// is the URL clients reach the API at; without it, messages carry no report
+// link. If client is nil, http.DefaultClient is used.
+func NewSlack(client *http.Client, url, baseURL string) *Slack {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Slack{client: client, url: url, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+func (s *Slack) Name() string {
+	return "slack"
+}
+
+func (s *Slack) Run(ctx context.Context, result *domain.MigrationResult) error {
+	lines := []string{
+		"*" + slackEscape(chatTitle(result)) + "*",
+		fmt.Sprintf("Matched: %d of %d tracks · Failed: %d", result.MatchedTracks, result.TotalTracks, result.FailedTracks),
+	}
+	if result.FailedTracks > 0 && s.baseURL != "" {
+		lines = append(lines, fmt.Sprintf("<%s|Unmatched tracks report>", UnmatchedReportURL(s.baseURL, result.ID)))
+	}
+	text := strings.Join(lines, "\n")
+
+	body, err := json.Marshal(map[string]any{
+		"text": Summary(result),
+		"blocks": []any{map[string]any{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": text},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.client, s.url, body, nil)
+}
+
+// slackEscape escapes the characters Slack treats as markup in text.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// Discord posts a summary of every completed migration to a Discord
+// webhook as an embed, linking to the report of its unmatched tracks when
+// there are any.
+type Discord struct {
+	client  *http.Client
+	url     string
+	baseURL string
+}
+
+// NewDiscord creates a Discord hook posting to a channel webhook URL.
+// baseURL is the URL clients reach the API at; without it, messages carry
+// no report link. If client is nil, http.DefaultClient is used.
+func NewDiscord(client *http.Client, url, baseURL string) *Discord {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Discord{client: client, url: url, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+func (d *Discord) Name() string {
+	return "discord"
+}
+
+// discordEmbed is the part of a Discord embed object the hook fills in.
+type discordEmbed struct {
+	Title  string         `json:"title"`
+	URL    string         `json:"url,omitempty"`
+	Color  int            `json:"color"`
+	Fields []discordField `json:"fields"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// Embed colors: green when every track was matched, orange otherwise.
+const (
+	discordGreen  = 0x2ecc71
+	discordOrange = 0xe67e22
+)
+
+func (d *Discord) Run(ctx context.Context, result *domain.MigrationResult) error {
+	embed := discordEmbed{
+		Title: chatTitle(result),
+		Color: discordGreen,
+		Fields: []discordField{
+			{Name: "Matched", Value: fmt.Sprintf("%d of %d", result.MatchedTracks, result.TotalTracks), Inline: true},
+			{Name: "Failed", Value: fmt.Sprintf("%d", result.FailedTracks), Inline: true},
+		},
+	}
+	if result.FailedTracks > 0 {
+		embed.Color = discordOrange
+	}
+	if result.FailedTracks > 0 && d.baseURL != "" {
+		embed.URL = UnmatchedReportURL(d.baseURL, result.ID)
+		embed.Fields = append(embed.Fields, discordField{
+			Name:  "Unmatched tracks",
+			Value: fmt.Sprintf("[Report](%s)", embed.URL),
+		})
+	}
+
+	body, err := json.Marshal(map[string]any{"embeds": []discordEmbed{embed}})
+	if err != nil {
+		return err
+	}
+	return post(ctx, d.client, d.url, body, nil)
+}
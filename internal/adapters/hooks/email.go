@@ -0,0 +1,212 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// SMTPConfig is the mail server an Email notifier sends through.
+type SMTPConfig struct {
+	Host string
+	Port int
+
+	// Username and Password authenticate with PLAIN auth when Username is
+	// set, which net/smtp only allows over TLS or to localhost.
+	Username string
+	Password string
+
+	// From is the sender address of the emails.
+	From string
+
+	// AllowedDomains are the domains of the addresses emails are sent to;
+	// a notify_email in any other domain, or any address if the list is
+	// empty, is refused, so the server cannot be used to mail strangers.
+	AllowedDomains []string
+}
+
+// Email emails the requester of a queued migration, at the address in its
+// notify_email, a summary once the job finished or failed. Jobs queued
+// without an address are skipped, and addresses outside the allowed domains
+// refused.
+type Email struct {
+	smtp SMTPConfig
+
+	// baseURL is the public URL of the API, for the link to the report.
+	baseURL string
+
+	now func() time.Time
+}
+
+// NewEmail creates an email notifier sending through the server of cfg.
+// baseURL is the URL clients reach the API at; if empty, emails give the
+// path of the migration report without a host.
+func NewEmail(cfg SMTPConfig, baseURL string) *Email {
+	return &Email{smtp: cfg, baseURL: strings.TrimSuffix(baseURL, "/"), now: time.Now}
+}
+
+func (e *Email) Name() string {
+	return "email"
+}
+
+func (e *Email) NotifyJob(ctx context.Context, job *domain.Job, result *domain.MigrationResult) error {
+	to := job.Request.NotifyEmail
+	if to == "" {
+		return nil
+	}
+	if !e.allowed(to) {
+		return fmt.Errorf("email: %s is not in an allowed domain", to)
+	}
+	subject, body := e.message(job, result)
+	return e.send(ctx, to, subject, body)
+}
+
+// allowed reports whether the domain of address is one of the allowed
+// domains.
+func (e *Email) allowed(address string) bool {
+	at := strings.LastIndexByte(address, '@')
+	if at < 0 {
+		return false
+	}
+	domain := address[at+1:]
+	return slices.ContainsFunc(e.smtp.AllowedDomains, func(d string) bool {
+		return strings.EqualFold(d, domain)
+	})
+}
+
+// message returns the subject and text of the email about job.
+func (e *Email) message(job *domain.Job, result *domain.MigrationResult) (string, string) {
+	if job.Kind == domain.JobAccount {
+		return e.accountMessage(job)
+	}
+	var b strings.Builder
+	if result == nil {
+		fmt.Fprintf(&b, "Your migration of playlist %s from %s to %s failed.\n\n",
+			job.Request.PlaylistID, job.Request.SourceProvider, job.Request.DestProvider)
+		fmt.Fprintf(&b, "Error: %s\n", job.Error)
+		fmt.Fprintf(&b, "Job: %s\n", job.ID)
+		return "Migration failed: " + job.Request.PlaylistID, b.String()
+	}
+
+	name := result.SourcePlaylist
+	if name == "" {
+		name = job.Request.PlaylistID
+	}
+	fmt.Fprintf(&b, "Your migration of %q from %s to %s finished.\n\n", name, result.SourceProvider, result.DestProvider)
+	fmt.Fprintf(&b, "Matched: %d of %d tracks\n", result.MatchedTracks, result.TotalTracks)
+	fmt.Fprintf(&b, "Failed: %d\n", result.FailedTracks)
+	if result.DestPlaylistID != "" {
+		fmt.Fprintf(&b, "Playlist: %s\n", result.DestPlaylistID)
+	}
+	fmt.Fprintf(&b, "\nReport: %s\n", ReportURL(e.baseURL, result.ID))
+	return "Migration finished: " + name, b.String()
+}
+
+// accountMessage returns the subject and text of the email about an
+// account job, with a line per stage.
+func (e *Email) accountMessage(job *domain.Job) (string, string) {
+	var b strings.Builder
+	outcome := "finished"
+	if job.Status == domain.JobFailed {
+		outcome = "failed"
+	}
+	fmt.Fprintf(&b, "Your migration of your account from %s to %s %s.\n\n",
+		job.Request.SourceProvider, job.Request.DestProvider, outcome)
+	if job.Progress != nil {
+		for _, stage := range job.Progress.Stages {
+			fmt.Fprintf(&b, "%s: %s", stage.Stage, stage.Status)
+			if stage.Total > 0 {
+				fmt.Fprintf(&b, ", %d of %d matched", stage.Matched, stage.Total)
+			}
+			if stage.Error != "" {
+				fmt.Fprintf(&b, " (%s)", stage.Error)
+			}
+			b.WriteString("\n")
+			for _, id := range stage.MigrationIDs {
+				fmt.Fprintf(&b, "  Report: %s\n", ReportURL(e.baseURL, id))
+			}
+		}
+		b.WriteString("\n")
+	}
+	if job.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", job.Error)
+	}
+	fmt.Fprintf(&b, "Job: %s\n", job.ID)
+	return "Account migration " + outcome, b.String()
+}
+
+// ReportURL returns the URL of the CSV report of a migration, relative to
+// the server if baseURL is empty.
+func ReportURL(baseURL, migrationID string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/api/v1/migrations/" + migrationID + "/report"
+}
+
+// send delivers one plain text email to to. The connection is upgraded to
+// TLS when the server offers STARTTLS.
+func (e *Email) send(ctx context.Context, to, subject, body string) error {
+	addr := net.JoinHostPort(e.smtp.Host, strconv.Itoa(e.smtp.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, e.smtp.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: e.smtp.Host}); err != nil {
+			return err
+		}
+	}
+	if e.smtp.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.smtp.Username, e.smtp.Password, e.smtp.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(e.smtp.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(e.compose(to, subject, body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// compose formats an email with CRLF line endings, as SMTP requires.
+func (e *Email) compose(to, subject, body string) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.smtp.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", e.now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return msg.Bytes()
+}
@@ -0,0 +1,266 @@
+package hooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testResult = &domain.MigrationResult{
+	ID:             "mig-1",
+	SourceProvider: "spotify",
+	DestProvider:   "youtube",
+	DestPlaylistID: "pl-2",
+	TotalTracks:    3,
+	MatchedTracks:  2,
+	FailedTracks:   1,
+	CreatedAt:      time.Unix(1700000000, 0),
+	TrackResults: []domain.TrackResult{
+		{SourceTrack: domain.Track{Name: "One", Artists: []string{"A", "B"}, Album: "X", ISRC: "US1"}, Status: domain.TrackStatusMatched},
+		{SourceTrack: domain.Track{Name: "Two", Artists: []string{"C"}}, Status: domain.TrackStatusNotFound},
+		{SourceTrack: domain.Track{Name: "Three", Artists: []string{"D"}}, Status: domain.TrackStatusMatched},
+	},
+}
+
+// recordServer captures the last request body and headers.
+func recordServer(t *testing.T, status int) (*httptest.Server, *[]byte, *http.Header) {
+	var body []byte
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header.Clone()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &body, &header
+}
+
+func TestWebhook_SignsPayload(t *testing.T) {
+	srv, body, header := recordServer(t, http.StatusNoContent)
+
+	err := NewWebhook(srv.Client(), srv.URL, "secret").Run(context.Background(), testResult)
+	require.NoError(t, err)
+
+	var payload WebhookPayload
+	require.NoError(t, json.Unmarshal(*body, &payload))
+	assert.Equal(t, EventMigrationCompleted, payload.Event)
+	assert.Equal(t, "mig-1", payload.Migration.ID)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(*body)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), header.Get(SignatureHeader))
+}
+
+func TestWebhook_ErrorStatus(t *testing.T) {
+	srv, _, header := recordServer(t, http.StatusInternalServerError)
+
+	err := NewWebhook(srv.Client(), srv.URL, "").Run(context.Background(), testResult)
+	assert.ErrorContains(t, err, "status 500")
+	assert.Empty(t, header.Get(SignatureHeader))
+}
+
+func TestNotifier(t *testing.T) {
+	srv, body, _ := recordServer(t, http.StatusOK)
+
+	require.NoError(t, NewNotifier(srv.Client(), srv.URL).Run(context.Background(), testResult))
+
+	var msg map[string]string
+	require.NoError(t, json.Unmarshal(*body, &msg))
+	assert.Equal(t, "Migration mig-1 from spotify to youtube finished: 2 of 3 tracks matched, 1 failed (playlist pl-2)", msg["text"])
+	assert.Equal(t, msg["text"], msg["content"])
+}
+
+func TestListenBrainz_SubmitsMatchedTracks(t *testing.T) {
+	srv, body, header := recordServer(t, http.StatusOK)
+	lb := NewListenBrainz(srv.Client(), "lb-token")
+	lb.url = srv.URL
+
+	require.NoError(t, lb.Run(context.Background(), testResult))
+	assert.Equal(t, "Token lb-token", header.Get("Authorization"))
+
+	var req struct {
+		ListenType string   `json:"listen_type"`
+		Payload    []listen `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(*body, &req))
+	assert.Equal(t, "import", req.ListenType)
+	require.Len(t, req.Payload, 2)
+	assert.Equal(t, "One", req.Payload[0].TrackMetadata.TrackName)
+	assert.Equal(t, "A", req.Payload[0].TrackMetadata.ArtistName)
+	assert.Equal(t, []string{"A", "B"}, req.Payload[0].TrackMetadata.AdditionalInfo.ArtistNames)
+	assert.Equal(t, "US1", req.Payload[0].TrackMetadata.AdditionalInfo.ISRC)
+	assert.Equal(t, "Three", req.Payload[1].TrackMetadata.TrackName)
+	assert.Equal(t, int64(1699999998), req.Payload[0].ListenedAt)
+	assert.Equal(t, int64(1699999999), req.Payload[1].ListenedAt)
+}
+
+// smtpServer accepts one SMTP session and returns the envelope and message
+// it received.
+func smtpServer(t *testing.T) (SMTPConfig, <-chan []string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := textproto.NewConn(conn)
+		r.PrintfLine("220 localhost ready")
+		var session []string
+		for {
+			line, err := r.ReadLine()
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+			case "EHLO", "HELO":
+				r.PrintfLine("250 localhost")
+			case "MAIL", "RCPT":
+				session = append(session, line)
+				r.PrintfLine("250 OK")
+			case "DATA":
+				r.PrintfLine("354 go ahead")
+				data, _ := r.ReadDotBytes()
+				session = append(session, string(data))
+				r.PrintfLine("250 OK")
+			case "QUIT":
+				r.PrintfLine("221 bye")
+				received <- session
+				return
+			default:
+				r.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return SMTPConfig{Host: host, Port: portNum, From: "migrations@example.com", AllowedDomains: []string{"example.com"}}, received
+}
+
+func TestEmail_SendsSummary(t *testing.T) {
+	cfg, received := smtpServer(t)
+	email := NewEmail(cfg, "https://music.example.com/")
+
+	job := &domain.Job{ID: "job-1", Request: domain.MigrationRequest{
+		SourceProvider: "spotify", DestProvider: "youtube", PlaylistID: "p1", NotifyEmail: "listener@example.com",
+	}}
+	result := *testResult
+	result.SourcePlaylist = "Road Trip"
+	require.NoError(t, email.NotifyJob(context.Background(), job, &result))
+
+	session := <-received
+	require.Len(t, session, 3)
+	assert.Equal(t, "MAIL FROM:<migrations@example.com>", session[0])
+	assert.Equal(t, "RCPT TO:<listener@example.com>", session[1])
+	assert.Contains(t, session[2], "Subject: Migration finished: Road Trip\n")
+	assert.Contains(t, session[2], "Matched: 2 of 3 tracks\n")
+	assert.Contains(t, session[2], "Report: https://music.example.com/api/v1/migrations/mig-1/report\n")
+}
+
+func TestEmail_RefusesOtherDomains(t *testing.T) {
+	email := NewEmail(SMTPConfig{AllowedDomains: []string{"example.com"}}, "")
+	for _, to := range []string{"victim@example.org", "listener@mail.example.com", "listener@example.com.evil"} {
+		job := &domain.Job{ID: "job-1", Request: domain.MigrationRequest{PlaylistID: "p1", NotifyEmail: to}}
+		assert.ErrorContains(t, email.NotifyJob(context.Background(), job, testResult), "not in an allowed domain", to)
+	}
+	assert.True(t, email.allowed("Listener@EXAMPLE.com"))
+	assert.False(t, NewEmail(SMTPConfig{}, "").allowed("listener@example.com"), "no domains allow no address")
+}
+
+func TestEmail_Failure(t *testing.T) {
+	email := NewEmail(SMTPConfig{}, "")
+	job := &domain.Job{ID: "job-1", Error: "source provider error: playlist not found", Request: domain.MigrationRequest{
+		SourceProvider: "spotify", DestProvider: "youtube", PlaylistID: "p1",
+	}}
+
+	subject, body := email.message(job, nil)
+	assert.Equal(t, "Migration failed: p1", subject)
+	assert.Contains(t, body, "Error: source provider error: playlist not found\n")
+
+	// Jobs queued without an address are skipped without connecting.
+	require.NoError(t, email.NotifyJob(context.Background(), job, nil))
+}
+
+func TestEmail_AccountJob(t *testing.T) {
+	email := NewEmail(SMTPConfig{}, "https://music.example.com")
+	job := &domain.Job{
+		ID: "job-1", Kind: domain.JobAccount, Status: domain.JobFailed, Error: "albums stage failed: boom",
+		Request: domain.MigrationRequest{SourceProvider: "spotify", DestProvider: "youtube"},
+		Progress: &domain.JobProgress{Stages: []domain.AccountStageProgress{
+			{Stage: domain.AccountStageAlbums, Status: domain.AccountStageFailed, Error: "boom"},
+			{Stage: domain.AccountStagePlaylists, Status: domain.AccountStageSucceeded, Total: 2, Matched: 2, MigrationIDs: []string{"m-1"}},
+		}},
+	}
+
+	subject, body := email.message(job, nil)
+	assert.Equal(t, "Account migration failed", subject)
+	assert.Contains(t, body, "albums: failed (boom)\n")
+	assert.Contains(t, body, "playlists: succeeded, 2 of 2 matched\n")
+	assert.Contains(t, body, "https://music.example.com/api/v1/migrations/m-1/report")
+	assert.NotContains(t, body, "playlist  from", "account jobs have no playlist")
+}
+
+func TestSlack(t *testing.T) {
+	srv, body, _ := recordServer(t, http.StatusOK)
+
+	require.NoError(t, NewSlack(srv.Client(), srv.URL, "https://music.example.com").Run(context.Background(), testResult))
+
+	var msg struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	require.NoError(t, json.Unmarshal(*body, &msg))
+	assert.Equal(t, Summary(testResult), msg.Text)
+	require.Len(t, msg.Blocks, 1)
+	assert.Contains(t, msg.Blocks[0].Text.Text, "Matched: 2 of 3 tracks")
+	assert.Contains(t, msg.Blocks[0].Text.Text, "<https://music.example.com/api/v1/migrations/mig-1/report?unmatched=true|Unmatched tracks report>")
+}
+
+func TestDiscord(t *testing.T) {
+	srv, body, _ := recordServer(t, http.StatusNoContent)
+
+	require.NoError(t, NewDiscord(srv.Client(), srv.URL, "https://music.example.com/").Run(context.Background(), testResult))
+
+	var msg struct {
+		Embeds []discordEmbed `json:"embeds"`
+	}
+	require.NoError(t, json.Unmarshal(*body, &msg))
+	require.Len(t, msg.Embeds, 1)
+	embed := msg.Embeds[0]
+	assert.Equal(t, "Migration of mig-1 from spotify to youtube finished", embed.Title)
+	assert.Equal(t, "https://music.example.com/api/v1/migrations/mig-1/report?unmatched=true", embed.URL)
+	assert.Equal(t, discordOrange, embed.Color)
+	require.Len(t, embed.Fields, 3)
+	assert.Equal(t, "2 of 3", embed.Fields[0].Value)
+
+	// Without a public URL there is nothing to link to.
+	require.NoError(t, NewDiscord(srv.Client(), srv.URL, "").Run(context.Background(), testResult))
+	msg.Embeds = nil
+	require.NoError(t, json.Unmarshal(*body, &msg))
+	assert.Empty(t, msg.Embeds[0].URL)
+	assert.Len(t, msg.Embeds[0].Fields, 2)
+}
@@ -0,0 +1,107 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+const (
+	listenBrainzURL = "https://api.listenbrainz.org/1/submit-listens"
+
+	// listenBatchSize is the number of listens submitted per request.
+	listenBatchSize = 100
+)
+
+// ListenBrainz submits the matched tracks of every completed migration to a
+// ListenBrainz account as imported listens, so the migrated playlist shows up
+// in the listening history used for statistics and recommendations.
+type ListenBrainz struct {
+	client *http.Client
+	token  string
+	url    string
+}
+
+// NewListenBrainz creates a hook submitting listens with a ListenBrainz user
+// token. If client is nil, http.DefaultClient is used.
+func NewListenBrainz(client *http.Client, token string) *ListenBrainz {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &ListenBrainz{client: client, token: token, url: listenBrainzURL}
+}
+
+func (l *ListenBrainz) Name() string {
+	return "listenbrainz"
+}
+
+type listen struct {
+	ListenedAt    int64         `json:"listened_at"`
+	TrackMetadata trackMetadata `json:"track_metadata"`
+}
+
+type trackMetadata struct {
+	ArtistName     string         `json:"artist_name"`
+	TrackName      string         `json:"track_name"`
+	ReleaseName    string         `json:"release_name,omitempty"`
+	AdditionalInfo additionalInfo `json:"additional_info"`
+}
+
+type additionalInfo struct {
+	RecordingMBID    string   `json:"recording_mbid,omitempty"`
+	ISRC             string   `json:"isrc,omitempty"`
+	ArtistNames      []string `json:"artist_names,omitempty"`
+	MusicService     string   `json:"music_service,omitempty"`
+	SubmissionClient string   `json:"submission_client"`
+}
+
+// Run submits one listen per matched track, in playlist order, timestamped
+// one second apart ending at the migration time. Tracks without an artist
+// are skipped, since ListenBrainz rejects them.
+func (l *ListenBrainz) Run(ctx context.Context, result *domain.MigrationResult) error {
+	var listens []listen
+	for _, tr := range result.TrackResults {
+		track := tr.SourceTrack
+		if tr.Status != domain.TrackStatusMatched || track.Type == domain.ItemTypeEpisode || len(track.Artists) == 0 {
+			continue
+		}
+		listens = append(listens, listen{
+			TrackMetadata: trackMetadata{
+				ArtistName:  track.Artists[0],
+				TrackName:   track.Name,
+				ReleaseName: track.Album,
+				AdditionalInfo: additionalInfo{
+					RecordingMBID:    track.MusicBrainzID,
+					ISRC:             track.ISRC,
+					ArtistNames:      track.Artists,
+					MusicService:     result.DestProvider,
+					SubmissionClient: "MusicMigration-API",
+				},
+			},
+		})
+	}
+
+	end := result.CreatedAt
+	if end.IsZero() {
+		end = time.Now()
+	}
+	for i := range listens {
+		listens[i].ListenedAt = end.Add(time.Duration(i-len(listens)) * time.Second).Unix()
+	}
+
+	for start := 0; start < len(listens); start += listenBatchSize {
+		batch := listens[start:min(start+listenBatchSize, len(listens))]
+		body, err := json.Marshal(map[string]any{"listen_type": "import", "payload": batch})
+		if err != nil {
+			return err
+		}
+		if err := post(ctx, l.client, l.url, body, map[string]string{"Authorization": "Token " + l.token}); err != nil {
+			return fmt.Errorf("failed to submit listens: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// Notifier posts a one-line summary of every completed migration to a chat
+// incoming webhook. The message is sent as both "text" (Slack, Mattermost)
+// and "content" (Discord).
+type Notifier struct {
+	client *http.Client
+	url    string
+}
+
+// NewNotifier creates a notification hook posting to an incoming webhook
+// URL. If client is nil, http.DefaultClient is used.
+func NewNotifier(client *http.Client, url string) *Notifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Notifier{client: client, url: url}
+}
+
+func (n *Notifier) Name() string {
+	return "notify"
+}
+
+func (n *Notifier) Run(ctx context.Context, result *domain.MigrationResult) error {
+	text := Summary(result)
+	body, err := json.Marshal(map[string]string{"text": text, "content": text})
+	if err != nil {
+		return err
+	}
+	return post(ctx, n.client, n.url, body, nil)
+}
+
+// Summary describes a migration in one line.
+func Summary(result *domain.MigrationResult) string {
+	return fmt.Sprintf("Migration %s from %s to %s finished: %d of %d tracks matched, %d failed (playlist %s)",
+		result.ID, result.SourceProvider, result.DestProvider,
+		result.MatchedTracks, result.TotalTracks, result.FailedTracks, result.DestPlaylistID)
+}
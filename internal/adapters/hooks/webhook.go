@@ -0,0 +1,91 @@
+// Package hooks provides post-migration actions implementing
+// ports.MigrationHook, and job notifications implementing
+// ports.JobNotifier.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// EventMigrationCompleted is the event sent by Webhook.
+const EventMigrationCompleted = "migration.completed"
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook body, as
+// "sha256=<hex>", when a secret is configured.
+const SignatureHeader = "X-Signature-256"
+
+// WebhookPayload is the JSON body posted by Webhook.
+type WebhookPayload struct {
+	Event     string                  `json:"event"`
+	Migration *domain.MigrationResult `json:"migration"`
+}
+
+// Webhook posts every completed migration as JSON to a URL.
+type Webhook struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+// NewWebhook creates a webhook hook posting to url. If secret is not empty,
+// each request is signed with it in SignatureHeader. If client is nil,
+// http.DefaultClient is used.
+func NewWebhook(client *http.Client, url string, secret string) *Webhook {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Webhook{client: client, url: url, secret: secret}
+}
+
+func (w *Webhook) Name() string {
+	return "webhook"
+}
+
+func (w *Webhook) Run(ctx context.Context, result *domain.MigrationResult) error {
+	body, err := json.Marshal(WebhookPayload{Event: EventMigrationCompleted, Migration: result})
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{}
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(body)
+		headers[SignatureHeader] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	return post(ctx, w.client, w.url, body, headers)
+}
+
+// post sends a JSON body and fails on any non-2xx response.
+func post(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned status %d: %s", req.URL.Host, resp.StatusCode, msg)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+func TestAccessLog_RedactsCredentials(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(AccessLog(logger), Recovery(logger))
+	r.GET("/ws/migrations/:id", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(domain.ContextWithAccount(c.Request.Context(), &domain.Account{ID: "acc-1"}))
+		c.Error(errors.New(`spotify: Authorization: Bearer tok-123 rejected, body {"access_token":"tok-456"}`))
+		c.Status(http.StatusBadGateway)
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ws/migrations/j1?api_key=key-789", nil)
+	req.Header.Set("Authorization", "Bearer tok-123")
+	req.Header.Set(apiKeyHeader, "key-789")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := buf.String()
+	assert.Contains(t, line, "[http] GET /ws/migrations/j1?api_key=REDACTED 502 ")
+	assert.Contains(t, line, "account=acc-1")
+	assert.Contains(t, line, "Bearer REDACTED")
+	for _, secret := range []string{"tok-123", "tok-456", "key-789"} {
+		assert.NotContains(t, line, secret)
+	}
+
+	buf.Reset()
+	req = httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(apiKeyHeader, "key-789")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, buf.String(), "panic in GET /panic: boom")
+	assert.Contains(t, buf.String(), "[http] GET /panic 500 ")
+	assert.NotContains(t, buf.String(), "key-789")
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// apiKeyHeader carries the account API key. The Authorization header is
+// reserved for streaming provider tokens.
+const apiKeyHeader = "X-API-Key"
+
+// RegisterAccount creates a new account and returns its API key.
+//
+//	@Summary		Register account
+//	@Description	Creates a new account and returns its API key. The key is only shown once;
+//	@Description	send it in the X-API-Key header on all other /api/v1 requests.
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		domain.RegisterAccountRequest	true	"Account details"
+//	@Success		201		{object}	domain.RegisterAccountResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/accounts [post]
+func (h *Handler) RegisterAccount(c *gin.Context) {
+	var req domain.RegisterAccountRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, validationFailed([]FieldError{{
+			Code:    "required",
+			Field:   "name",
+			Message: "is required",
+		}}))
+		return
+	}
+
+	account, apiKey, err := h.accounts.Register(c.Request.Context(), req.Name)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAccountName) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "validation_failed",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.RegisterAccountResponse{
+		Account: *account,
+		APIKey:  apiKey,
+	})
+}
+
+// RequireAPIKey is a middleware that authenticates the X-API-Key header and
+// stores the resolved account in the request context.
+func (h *Handler) RequireAPIKey(c *gin.Context) {
+	apiKey := c.GetHeader(apiKeyHeader)
+	if apiKey == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: apiKeyHeader + " header is required",
+		})
+		return
+	}
+
+	account, err := h.accounts.Authenticate(c.Request.Context(), apiKey)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "unauthorized",
+				Message: "invalid API key",
+			})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Request = c.Request.WithContext(domain.ContextWithAccount(c.Request.Context(), account))
+	c.Next()
+}
@@ -0,0 +1,179 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// -- Mock account service ----------------------------------------------------
+
+type mockAccountService struct {
+	validKey string
+
+	// lookups counts the keys Authenticate was asked about.
+	lookups int
+}
+
+func (m *mockAccountService) Register(_ context.Context, name string) (*domain.Account, string, error) {
+	return &domain.Account{ID: "acc-1", Name: name}, m.validKey, nil
+}
+
+func (m *mockAccountService) Authenticate(_ context.Context, apiKey string) (*domain.Account, error) {
+	m.lookups++
+	if apiKey != m.validKey {
+		return nil, domain.ErrAccountNotFound
+	}
+	return &domain.Account{ID: "acc-1"}, nil
+}
+
+func setupAuthRouter(svc *mockMigrationService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewHandler(svc, WithAccountService(&mockAccountService{validKey: "mm_valid"}))
+	h.RegisterRoutes(r)
+	return r
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestRegisterAccount(t *testing.T) {
+	r := setupAuthRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/accounts", bytes.NewReader([]byte(`{"name":"my-app"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp domain.RegisterAccountResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, "mm_valid", resp.APIKey)
+	assert.Equal(t, "my-app", resp.Account.Name)
+}
+
+func TestRegisterAccount_BlankName(t *testing.T) {
+	r := setupAuthRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/accounts", bytes.NewReader([]byte(`{"name":"   "}`)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "validation_failed", resp.Error)
+	require.Len(t, resp.Fields, 1)
+	assert.Equal(t, "name", resp.Fields[0].Field)
+}
+
+func TestRequireAPIKey_Missing(t *testing.T) {
+	r := setupAuthRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/migrations", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireAPIKey_Invalid(t *testing.T) {
+	r := setupAuthRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/migrations", nil)
+	req.Header.Set("X-API-Key", "mm_wrong")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireAPIKey_Valid(t *testing.T) {
+	r := setupAuthRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/migrations", nil)
+	req.Header.Set("X-API-Key", "mm_valid")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHealth_NoAPIKeyRequired(t *testing.T) {
+	r := setupAuthRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// -- Mock token vault --------------------------------------------------------
+
+type mockTokenVault struct {
+	stored map[string]domain.ProviderToken
+}
+
+func (m *mockTokenVault) StoreToken(_ context.Context, provider string, token domain.ProviderToken) error {
+	m.stored[provider] = token
+	return nil
+}
+
+func (m *mockTokenVault) GetToken(_ context.Context, provider string) (*domain.ProviderToken, error) {
+	token, ok := m.stored[provider]
+	if !ok {
+		return nil, domain.ErrTokenNotFound
+	}
+	return &token, nil
+}
+
+func (m *mockTokenVault) DeleteToken(_ context.Context, provider string) error {
+	delete(m.stored, provider)
+	return nil
+}
+
+func (m *mockTokenVault) ListProviders(context.Context) ([]string, error) {
+	return slices.Sorted(maps.Keys(m.stored)), nil
+}
+
+func TestStoreToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	vault := &mockTokenVault{stored: map[string]domain.ProviderToken{}}
+	r := gin.New()
+	NewHandler(&mockMigrationService{},
+		WithAccountService(&mockAccountService{validKey: "mm_valid"}),
+		WithTokenVault(vault),
+	).RegisterRoutes(r)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/tokens/spotify", bytes.NewReader([]byte(`{"access_token":"abc"}`)))
+	req.Header.Set("X-API-Key", "mm_valid")
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "abc", vault.stored["spotify"].AccessToken)
+
+	// With the vault enabled, provider requests no longer need a Bearer token.
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/playlists?provider=spotify", nil)
+	req.Header.Set("X-API-Key", "mm_valid")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
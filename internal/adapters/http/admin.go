@@ -0,0 +1,152 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// adminKeyHeader carries the key that authorizes /admin requests.
+const adminKeyHeader = "X-Admin-Key"
+
+// DisableProviderRequest optionally explains why a provider is disabled.
+type DisableProviderRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RequireAdminKey is a middleware that rejects requests whose X-Admin-Key
+// header does not match the configured admin key.
+func (h *Handler) RequireAdminKey(c *gin.Context) {
+	if !h.isAdmin(c) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "valid " + adminKeyHeader + " header is required",
+		})
+		return
+	}
+	c.Next()
+}
+
+// isAdmin reports whether the request carries the configured admin key.
+func (h *Handler) isAdmin(c *gin.Context) bool {
+	key := c.GetHeader(adminKeyHeader)
+	return h.adminKey != "" && key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(h.adminKey)) == 1
+}
+
+// ListProviders reports every registered provider and whether it is enabled.
+//
+//	@Summary		List providers
+//	@Description	Lists every registered provider and whether it is currently enabled.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{array}		domain.ProviderStatus
+//	@Failure		401	{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/providers [get]
+func (h *Handler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, h.admin.Statuses())
+}
+
+// DisableProvider makes a provider reject requests until it is re-enabled.
+//
+//	@Summary		Disable provider
+//	@Description	Disables a provider without restarting the server, e.g. while its API quota is exhausted.
+//	@Description	Requests using it fail with 503 and the given reason until it is re-enabled.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string					true	"Provider name"
+//	@Param			request	body		DisableProviderRequest	false	"Reason shown to clients"
+//	@Success		200		{object}	domain.ProviderStatus
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/providers/{name}/disable [post]
+func (h *Handler) DisableProvider(c *gin.Context) {
+	var req DisableProviderRequest
+	if c.Request.ContentLength != 0 && !h.bindJSON(c, &req) {
+		return
+	}
+
+	name := c.Param("name")
+	if err := h.admin.Disable(name, req.Reason); err != nil {
+		h.writeAdminError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.ProviderStatus{Name: name, Enabled: false, DisabledReason: req.Reason})
+}
+
+// EnableProvider re-enables a disabled provider.
+//
+//	@Summary		Enable provider
+//	@Description	Re-enables a provider disabled with the disable endpoint.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			name	path		string	true	"Provider name"
+//	@Success		200		{object}	domain.ProviderStatus
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/providers/{name}/enable [post]
+func (h *Handler) EnableProvider(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.admin.Enable(name); err != nil {
+		h.writeAdminError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.ProviderStatus{Name: name, Enabled: true})
+}
+
+// GetJobDebugLog returns the provider traffic recorded by a debug job.
+//
+//	@Summary		Get job debug log
+//	@Description	Returns the provider requests made by a job queued with POST /api/v1/jobs?debug=true: their
+//	@Description	URLs with credentials removed, status codes, durations and the first 2 KiB of each response
+//	@Description	body. The log is stored when the job finishes and keeps the first 200 requests; dropped counts
+//	@Description	the rest.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string	true	"Job ID"
+//	@Success		200	{object}	domain.DebugLog
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/jobs/{id}/debug [get]
+func (h *Handler) GetJobDebugLog(c *gin.Context) {
+	log, err := h.jobs.GetJobDebugLog(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrJobNotFound) || errors.Is(err, domain.ErrNoDebugLog) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, log)
+}
+
+func (h *Handler) writeAdminError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrProviderNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "internal_error",
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// -- Mock ProviderAdmin ------------------------------------------------------
+
+type mockProviderAdmin struct {
+	disabled map[string]string
+}
+
+func (m *mockProviderAdmin) Statuses() []domain.ProviderStatus {
+	statuses := []domain.ProviderStatus{{Name: "spotify", Enabled: true}}
+	if reason, ok := m.disabled["youtube"]; ok {
+		return append(statuses, domain.ProviderStatus{Name: "youtube", DisabledReason: reason})
+	}
+	return append(statuses, domain.ProviderStatus{Name: "youtube", Enabled: true})
+}
+
+func (m *mockProviderAdmin) Disable(name string, reason string) error {
+	if name != "spotify" && name != "youtube" {
+		return fmt.Errorf("%w: %s", domain.ErrProviderNotFound, name)
+	}
+	m.disabled[name] = reason
+	return nil
+}
+
+func (m *mockProviderAdmin) Enable(name string) error {
+	delete(m.disabled, name)
+	return nil
+}
+
+func setupAdminRouter(svc *mockMigrationService, admin *mockProviderAdmin) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(svc, WithProviderAdmin(admin, "secret")).RegisterRoutes(r)
+	return r
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestAdmin_RequiresKey(t *testing.T) {
+	r := setupAdminRouter(&mockMigrationService{}, &mockProviderAdmin{disabled: map[string]string{}})
+
+	for _, key := range []string{"", "wrong"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/admin/providers", nil)
+		req.Header.Set("X-Admin-Key", key)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestAdmin_DisableAndListProviders(t *testing.T) {
+	admin := &mockProviderAdmin{disabled: map[string]string{}}
+	r := setupAdminRouter(&mockMigrationService{}, admin)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/providers/youtube/disable",
+		bytes.NewReader([]byte(`{"reason":"quota exhausted"}`)))
+	req.Header.Set("X-Admin-Key", "secret")
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "quota exhausted", admin.disabled["youtube"])
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/admin/providers", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var statuses []domain.ProviderStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &statuses))
+	assert.False(t, statuses[1].Enabled)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/admin/providers/youtube/enable", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, admin.disabled)
+}
+
+func TestAdmin_DisableUnknownProvider(t *testing.T) {
+	r := setupAdminRouter(&mockMigrationService{}, &mockProviderAdmin{disabled: map[string]string{}})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/providers/deezer/disable", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMigratePlaylist_ProviderDisabled(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: fmt.Errorf("destination provider error: %w", domain.ErrProviderDisabled)})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate",
+		bytes.NewReader([]byte(`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"pl-1"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestMigratePlaylist_SourceOnlyProvider(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: fmt.Errorf("destination provider error: %w", domain.ErrSourceOnlyProvider)})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate",
+		bytes.NewReader([]byte(`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"pl-1"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "source_only_provider")
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// Page sizes of GET /admin/audit.
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// WithAuditLog enables the /admin/audit endpoint, which queries the log of
+// playlist writes. It requires WithProviderAdmin for the admin key.
+func WithAuditLog(auditLog ports.AuditLog) Option {
+	return func(h *Handler) {
+		h.audit = auditLog
+	}
+}
+
+// QueryAuditLog returns the playlist writes recorded in the audit log.
+//
+//	@Summary		Query audit log
+//	@Description	Returns the playlist writes made to providers (creating and deleting playlists, adding
+//	@Description	and removing tracks, updating playlist details) with the account, provider, affected IDs,
+//	@Description	start and finish times and the error of failed writes, most recent first. Filters combine;
+//	@Description	since and until bound the start time and are RFC 3339 timestamps.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			account_id	query		string	false	"Account that made the writes"
+//	@Param			provider	query		string	false	"Provider written to"
+//	@Param			action		query		string	false	"Kind of write"	Enums(create_playlist, add_tracks, remove_tracks, update_playlist, delete_playlist)
+//	@Param			since		query		string	false	"Earliest start time, inclusive"
+//	@Param			until		query		string	false	"Latest start time, exclusive"
+//	@Param			limit		query		int		false	"Maximum number of entries (default 100, max 1000)"
+//	@Success		200			{array}		domain.AuditEntry
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/audit [get]
+func (h *Handler) QueryAuditLog(c *gin.Context) {
+	q := domain.AuditQuery{
+		AccountID: c.Query("account_id"),
+		Provider:  c.Query("provider"),
+		Action:    domain.AuditAction(c.Query("action")),
+		Limit:     defaultAuditLimit,
+	}
+	if q.Action != "" && !q.Action.Valid() {
+		badAuditQuery(c, fmt.Sprintf("unknown action %q", q.Action))
+		return
+	}
+	for _, bound := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &q.Since}, {"until", &q.Until}} {
+		value := c.Query(bound.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			badAuditQuery(c, fmt.Sprintf("query parameter '%s' must be an RFC 3339 timestamp", bound.name))
+			return
+		}
+		*bound.dst = t
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			badAuditQuery(c, fmt.Sprintf("query parameter 'limit' must be between 1 and %d", maxAuditLimit))
+			return
+		}
+		q.Limit = limit
+	}
+
+	entries, err := h.audit.Query(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+func badAuditQuery(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   "bad_request",
+		Message: message,
+	})
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// -- Mock AuditLog -----------------------------------------------------------
+
+type mockAuditLog struct {
+	lastQuery domain.AuditQuery
+}
+
+func (m *mockAuditLog) Append(_ context.Context, _ *domain.AuditEntry) error {
+	return nil
+}
+
+func (m *mockAuditLog) Query(_ context.Context, q domain.AuditQuery) ([]domain.AuditEntry, error) {
+	m.lastQuery = q
+	return []domain.AuditEntry{{ID: "a1", AccountID: "acc", Provider: "spotify", Action: domain.AuditAddTracks, TrackIDs: []string{"t1"}}}, nil
+}
+
+func setupAuditRouter(auditLog *mockAuditLog) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{},
+		WithProviderAdmin(&mockProviderAdmin{disabled: map[string]string{}}, "secret"),
+		WithAuditLog(auditLog)).RegisterRoutes(r)
+	return r
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestQueryAuditLog(t *testing.T) {
+	auditLog := &mockAuditLog{}
+	r := setupAuditRouter(auditLog)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet,
+		"/admin/audit?account_id=acc&provider=spotify&action=add_tracks&since=2026-01-01T13:00:00Z&until=2026-01-02T00:00:00Z&limit=10", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, domain.AuditQuery{
+		AccountID: "acc",
+		Provider:  "spotify",
+		Action:    domain.AuditAddTracks,
+		Since:     time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC),
+		Until:     time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		Limit:     10,
+	}, auditLog.lastQuery)
+
+	var entries []domain.AuditEntry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	require.Len(t, entries, 1)
+	assert.Equal(t, []string{"t1"}, entries[0].TrackIDs)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/admin/audit", nil)
+	req.Header.Set("X-Admin-Key", "secret")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, domain.AuditQuery{Limit: defaultAuditLimit}, auditLog.lastQuery)
+}
+
+func TestQueryAuditLog_Errors(t *testing.T) {
+	r := setupAuditRouter(&mockAuditLog{})
+
+	tests := []struct {
+		name   string
+		params string
+		key    string
+		code   int
+	}{
+		{"missing key", "", "", http.StatusUnauthorized},
+		{"unknown action", "?action=play", "secret", http.StatusBadRequest},
+		{"bad since", "?since=yesterday", "secret", http.StatusBadRequest},
+		{"limit too large", "?limit=5000", "secret", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/admin/audit"+tt.params, nil)
+			req.Header.Set("X-Admin-Key", tt.key)
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// CompareMigrations diffs two stored migrations of the same playlist.
+//
+//	@Summary		Compare migrations
+//	@Description	Compares the stored results of two migrations of the same source playlist to the same destination
+//	@Description	provider, for example before and after a change to matching. Tracks are paired by their source
+//	@Description	track; those whose status or matched track changed, and those only one migration has, are listed
+//	@Description	in the order of migration a, with a summary of the changes by kind.
+//	@Tags			migration
+//	@Produce		json
+//	@Param			a	query		string	true	"ID of the earlier migration"
+//	@Param			b	query		string	true	"ID of the later migration"
+//	@Success		200	{object}	domain.MigrationComparison
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		422	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/migrations/compare [get]
+func (h *Handler) CompareMigrations(c *gin.Context) {
+	a, b := c.Query("a"), c.Query("b")
+	if a == "" || b == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: "query parameters a and b must name the migrations to compare",
+		})
+		return
+	}
+
+	comparison, err := h.service.CompareMigrations(c.Request.Context(), a, b)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrMigrationNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrMigrationsNotComparable):
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "not_comparable",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "internal_error",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+func TestCompareMigrations(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/migrations/compare?a=mig-1&b=mig-2", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var cmp domain.MigrationComparison
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cmp))
+	assert.Equal(t, "mig-1", cmp.A)
+	assert.Equal(t, "mig-2", cmp.B)
+}
+
+func TestCompareMigrations_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+	}{
+		{"missing id", "?a=mig-1", nil, http.StatusBadRequest},
+		{"unknown migration", "?a=mig-1&b=mig-2", domain.ErrMigrationNotFound, http.StatusNotFound},
+		{"other playlist", "?a=mig-1&b=mig-2", fmt.Errorf("compare: %w", domain.ErrMigrationsNotComparable), http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupRouter(&mockMigrationService{err: tt.err})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/migrations/compare"+tt.query, nil))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
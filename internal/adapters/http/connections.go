@@ -0,0 +1,86 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// WithConnectionService enables the /api/v1/me/connections endpoints
+// describing and revoking the provider tokens in the vault.
+func WithConnectionService(connections ports.ConnectionService) Option {
+	return func(h *Handler) {
+		h.connections = connections
+	}
+}
+
+// ListConnections returns the providers the caller has linked.
+//
+//	@Summary		List linked providers
+//	@Description	Returns every provider the authenticated account has stored a token for, with the token's
+//	@Description	expiry, whether it has expired, whether it can be refreshed and the OAuth scopes granted to
+//	@Description	it. Tokens themselves are never returned.
+//	@Tags			tokens
+//	@Produce		json
+//	@Success		200	{array}		domain.ProviderConnection
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/me/connections [get]
+func (h *Handler) ListConnections(c *gin.Context) {
+	connections, err := h.connections.ListConnections(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, connections)
+}
+
+// RevokeConnection revokes the caller's token for a provider.
+//
+//	@Summary		Revoke linked provider
+//	@Description	Revokes the stored token with the provider, where the provider supports it (YouTube), and
+//	@Description	removes it from the vault. If the provider fails to revoke it, the token is kept and 502 is
+//	@Description	returned; DELETE /api/v1/tokens/{provider} removes it without revoking.
+//	@Tags			tokens
+//	@Produce		json
+//	@Param			provider	path	string	true	"Streaming provider"	Enums(spotify, youtube)
+//	@Success		204
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Failure		502	{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/me/connections/{provider} [delete]
+func (h *Handler) RevokeConnection(c *gin.Context) {
+	if err := h.connections.RevokeConnection(c.Request.Context(), c.Param("provider")); err != nil {
+		if errors.Is(err, domain.ErrTokenNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, domain.ErrRevocationFailed) {
+			c.JSON(http.StatusBadGateway, ErrorResponse{
+				Error:   "revocation_failed",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// -- Mock connection service -------------------------------------------------
+
+type mockConnectionService struct {
+	connections []domain.ProviderConnection
+	revokeErr   error
+	revoked     []string
+}
+
+func (m *mockConnectionService) ListConnections(context.Context) ([]domain.ProviderConnection, error) {
+	return m.connections, nil
+}
+
+func (m *mockConnectionService) RevokeConnection(_ context.Context, provider string) error {
+	if m.revokeErr != nil {
+		return m.revokeErr
+	}
+	m.revoked = append(m.revoked, provider)
+	return nil
+}
+
+func setupConnectionRouter(connections *mockConnectionService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{}, WithConnectionService(connections)).RegisterRoutes(r)
+	return r
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestListConnections(t *testing.T) {
+	expires := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := setupConnectionRouter(&mockConnectionService{connections: []domain.ProviderConnection{
+		{Provider: "youtube", ExpiresAt: &expires, Refreshable: true, Scopes: []string{"https://www.googleapis.com/auth/youtube"}},
+	}})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/me/connections", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var connections []domain.ProviderConnection
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &connections))
+	require.Len(t, connections, 1)
+	assert.Equal(t, "youtube", connections[0].Provider)
+	assert.True(t, connections[0].Refreshable)
+	assert.NotContains(t, w.Body.String(), "token\"")
+}
+
+func TestRevokeConnection(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"revoked", nil, http.StatusNoContent},
+		{"not linked", domain.ErrTokenNotFound, http.StatusNotFound},
+		{"provider failure", fmt.Errorf("%w: youtube: status 503", domain.ErrRevocationFailed), http.StatusBadGateway},
+		{"vault failure", fmt.Errorf("token vault requires an authenticated account"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connections := &mockConnectionService{revokeErr: tt.err}
+			r := setupConnectionRouter(connections)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/me/connections/youtube", nil))
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.err == nil {
+				assert.Equal(t, []string{"youtube"}, connections.revoked)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Pagination headers set by paged listings. Envelope also reports them in
+// the response meta.
+const (
+	nextCursorHeader = "X-Next-Cursor"
+	totalCountHeader = "X-Total-Count"
+)
+
+// EnvelopeResponse wraps every JSON response of the /api/v2 routes. Exactly
+// one of Data and Error is set.
+type EnvelopeResponse struct {
+	Data  json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+	Meta  *EnvelopeMeta   `json:"meta,omitempty"`
+	Error *EnvelopeError  `json:"error,omitempty"`
+}
+
+// EnvelopeMeta carries pagination details of a paged listing.
+type EnvelopeMeta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int    `json:"total,omitempty"`
+}
+
+// EnvelopeError is a failed response. Code is the machine-readable error
+// code also sent as "error" by /api/v1.
+type EnvelopeError struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// envelopeWriter buffers a JSON response so Envelope can wrap it once the
+// handler finished. Other responses are passed through as they are written.
+type envelopeWriter struct {
+	gin.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	passthrough bool
+}
+
+func (w *envelopeWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *envelopeWriter) WriteHeaderNow() {}
+
+func (w *envelopeWriter) Write(b []byte) (int, error) {
+	if w.body.Len() == 0 && !w.passthrough && !isJSON(w.Header()) {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush only flushes passed through responses; wrapped ones are written at
+// once when the handler finished.
+func (w *envelopeWriter) Flush() {
+	if w.passthrough {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func (w *envelopeWriter) Status() int {
+	return w.status
+}
+
+func (w *envelopeWriter) Size() int {
+	if w.passthrough {
+		return w.ResponseWriter.Size()
+	}
+	return w.body.Len()
+}
+
+func (w *envelopeWriter) Written() bool {
+	return w.passthrough || w.body.Len() > 0
+}
+
+// Envelope is a middleware that wraps JSON responses in an EnvelopeResponse:
+// successful bodies become its data, with pagination headers repeated in
+// its meta, and ErrorResponse bodies become its error. Other responses,
+// such as CSV reports, NDJSON streams or empty bodies, are passed through
+// unchanged.
+func Envelope(c *gin.Context) {
+	w := &envelopeWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+	c.Writer = w
+	// Restored on panics too, so recovery middleware writes the response.
+	defer func() { c.Writer = w.ResponseWriter }()
+	c.Next()
+	if w.passthrough {
+		return
+	}
+
+	body := w.body.Bytes()
+	if len(body) > 0 {
+		body = wrapResponse(w.status, w.Header(), body)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	_, _ = w.ResponseWriter.Write(body)
+}
+
+// isJSON reports whether header declares a JSON body.
+func isJSON(header http.Header) bool {
+	return strings.HasPrefix(header.Get("Content-Type"), "application/json")
+}
+
+// wrapResponse wraps the JSON body of a response with the given status and
+// header in an EnvelopeResponse.
+func wrapResponse(status int, header http.Header, body []byte) []byte {
+	var env EnvelopeResponse
+	if status >= http.StatusBadRequest {
+		var resp ErrorResponse
+		if err := json.Unmarshal(body, &resp); err != nil || resp.Error == "" {
+			resp.Error = strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+		}
+		env.Error = &EnvelopeError{Code: resp.Error, Message: resp.Message, Fields: resp.Fields}
+	} else {
+		env.Data = body
+		env.Meta = envelopeMeta(header)
+	}
+
+	wrapped, err := json.Marshal(env)
+	if err != nil {
+		return body
+	}
+	return wrapped
+}
+
+// envelopeMeta reads the pagination headers of a response, or returns nil
+// if there are none.
+func envelopeMeta(header http.Header) *EnvelopeMeta {
+	var meta EnvelopeMeta
+	meta.NextCursor = header.Get(nextCursorHeader)
+	meta.Total, _ = strconv.Atoi(header.Get(totalCountHeader))
+	if meta == (EnvelopeMeta{}) {
+		return nil
+	}
+	return &meta
+}
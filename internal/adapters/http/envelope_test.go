@@ -0,0 +1,115 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeEnvelope(t *testing.T, w *httptest.ResponseRecorder) EnvelopeResponse {
+	t.Helper()
+	var env EnvelopeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &env))
+	return env
+}
+
+func TestEnvelope_WrapsData(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/migrations/mig-1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	env := decodeEnvelope(t, w)
+	assert.Nil(t, env.Error)
+	assert.Nil(t, env.Meta)
+	var result domain.MigrationResult
+	require.NoError(t, json.Unmarshal(env.Data, &result))
+	assert.Equal(t, "mig-1", result.ID)
+
+	// v1 keeps the bare body.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/migrations/mig-1", nil))
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, "mig-1", result.ID)
+	assert.NotContains(t, w.Body.String(), `"data"`)
+}
+
+func TestEnvelope_PaginationMeta(t *testing.T) {
+	r := setupRouter(&mockMigrationService{playlists: []domain.Playlist{{ID: "1"}, {ID: "2"}, {ID: "3"}}})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/playlists?provider=spotify&limit=2", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	env := decodeEnvelope(t, w)
+	require.NotNil(t, env.Meta)
+	assert.Equal(t, "next", env.Meta.NextCursor)
+	assert.Equal(t, 3, env.Meta.Total)
+	var playlists []domain.Playlist
+	require.NoError(t, json.Unmarshal(env.Data, &playlists))
+	assert.Len(t, playlists, 2)
+}
+
+func TestEnvelope_WrapsErrors(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: domain.ErrMigrationNotFound})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/migrations/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	env := decodeEnvelope(t, w)
+	assert.Nil(t, env.Data)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "not_found", env.Error.Code)
+	assert.Equal(t, domain.ErrMigrationNotFound.Error(), env.Error.Message)
+}
+
+func TestEnvelope_WrapsValidationErrors(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/migrate", strings.NewReader(`{"source_provider":"spotify"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	env := decodeEnvelope(t, w)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "validation_failed", env.Error.Code)
+	assert.NotEmpty(t, env.Error.Fields)
+}
+
+func TestEnvelope_PassesThroughOtherContent(t *testing.T) {
+	r := setupRouter(&mockMigrationService{migrationResult: &domain.MigrationResult{ID: "mig-1"}})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/migrations/mig-1/report", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Body.String(), "position,"))
+}
+
+func TestEnvelope_UnknownErrorBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/fail", Envelope, func(c *gin.Context) {
+		c.JSON(http.StatusBadGateway, gin.H{"reason": "upstream"})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+
+	env := decodeEnvelope(t, w)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "bad_gateway", env.Error.Code)
+}
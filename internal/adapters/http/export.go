@@ -0,0 +1,59 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// ExportAccount downloads a backup of the user's account on a provider.
+//
+//	@Summary		Export account
+//	@Description	Returns every playlist of the authenticated user with its tracks and, on providers with a
+//	@Description	library (Spotify), their liked songs, saved albums and shows and followed artists, as one
+//	@Description	downloadable JSON file. Sections a provider does not have are empty. Reading the Spotify
+//	@Description	library needs the user-library-read and user-follow-read scopes.
+//	@Tags			playlists
+//	@Produce		json
+//	@Param			provider		query		string	true	"Streaming provider"	Enums(spotify, youtube)
+//	@Param			Authorization	header		string	true	"Bearer token for the streaming provider"
+//	@Success		200				{object}	domain.AccountExport
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		429				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/api/v1/export/account [get]
+func (h *Handler) ExportAccount(c *gin.Context) {
+	provider, token, ok := h.requireProviderAndToken(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.service.ExportAccount(c.Request.Context(), provider, token)
+	if err != nil {
+		if providerError(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrProviderNotFound) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "bad_request",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-account-%s.json"`,
+		export.Provider, export.ExportedAt.Format("2006-01-02")))
+	c.JSON(http.StatusOK, export)
+}
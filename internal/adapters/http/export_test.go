@@ -0,0 +1,55 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAccount(t *testing.T) {
+	r := setupRouter(&mockMigrationService{playlists: []domain.Playlist{{ID: "pl-1", Name: "Mix"}}})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/export/account?provider=spotify", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `attachment; filename="spotify-account-0001-01-01.json"`, w.Header().Get("Content-Disposition"))
+
+	var export domain.AccountExport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &export))
+	assert.Equal(t, "spotify", export.Provider)
+	require.Len(t, export.Playlists, 1)
+	assert.Equal(t, "Mix", export.Playlists[0].Name)
+}
+
+func TestExportAccount_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantStatus int
+	}{
+		{"missing provider", "", nil, http.StatusBadRequest},
+		{"unknown provider", "?provider=tidal", fmt.Errorf("%w: tidal", domain.ErrProviderNotFound), http.StatusBadRequest},
+		{"missing scope", "?provider=spotify", fmt.Errorf("failed to export saved albums: %w", domain.ErrInsufficientScope), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupRouter(&mockMigrationService{err: tt.err})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/export/account"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer token")
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
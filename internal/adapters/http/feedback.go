@@ -0,0 +1,63 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// WithMatchFeedbackService enables the /api/v1/matches/feedback endpoint.
+func WithMatchFeedbackService(feedback ports.MatchFeedbackService) Option {
+	return func(h *Handler) {
+		h.feedback = feedback
+	}
+}
+
+// SubmitMatchFeedback records the caller's verdict on a match.
+//
+//	@Summary		Confirm or reject a match
+//	@Description	Records whether source_track on source_provider and matched_track on dest_provider, identified
+//	@Description	by their external_id, are the same recording. The match, or a candidate ranked below it, must
+//	@Description	be reported in the track results of one of the caller's migrations, whose tracks are recorded.
+//	@Description	Verdicts are shared by everyone on the instance, one per account and match; a new verdict
+//	@Description	replaces the caller's earlier one. Once most of enough accounts confirm a match, later
+//	@Description	migrations reuse it without searching, until most no longer do; once most reject it,
+//	@Description	searches no longer accept it. Returns the verdicts of all accounts on the match.
+//	@Tags			matches
+//	@Accept			json
+//	@Produce		json
+//	@Param			feedback	body		domain.MatchFeedback	true	"Verdict on a match"
+//	@Success		200			{object}	domain.MatchFeedbackTally
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		422			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/matches/feedback [post]
+func (h *Handler) SubmitMatchFeedback(c *gin.Context) {
+	var feedback domain.MatchFeedback
+	if !h.bindJSON(c, &feedback) {
+		return
+	}
+
+	tally, err := h.feedback.SubmitFeedback(c.Request.Context(), feedback)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidFeedback) {
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation_failed",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tally)
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockFeedbackService struct {
+	submitted *domain.MatchFeedback
+}
+
+func (m *mockFeedbackService) SubmitFeedback(_ context.Context, feedback domain.MatchFeedback) (*domain.MatchFeedbackTally, error) {
+	if feedback.MatchedTrack.ExternalID == "" {
+		return nil, domain.ErrInvalidFeedback
+	}
+	m.submitted = &feedback
+	return &domain.MatchFeedbackTally{Confirmations: 1}, nil
+}
+
+func TestSubmitMatchFeedback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	feedback := &mockFeedbackService{}
+	r := gin.New()
+	NewHandler(&mockMigrationService{}, WithMatchFeedbackService(feedback), WithProviders([]string{"spotify", "youtube"})).RegisterRoutes(r)
+
+	w := doProfileRequest(r, http.MethodPost, "/api/v1/matches/feedback", `{
+		"source_provider": "spotify", "source_track": {"name": "Song", "external_id": "sp-1"},
+		"dest_provider": "youtube", "matched_track": {"name": "Song (Live)", "external_id": "vid-1"},
+		"verdict": "confirmed"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"confirmations": 1, "rejections": 0}`, w.Body.String())
+	require.NotNil(t, feedback.submitted)
+	assert.Equal(t, "vid-1", feedback.submitted.MatchedTrack.ExternalID)
+	assert.Equal(t, domain.MatchConfirmed, feedback.submitted.Verdict)
+
+	w = doProfileRequest(r, http.MethodPost, "/api/v1/matches/feedback",
+		`{"source_provider": "spotify", "dest_provider": "youtube", "verdict": "maybe"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"verdict"`)
+
+	w = doProfileRequest(r, http.MethodPost, "/api/v1/matches/feedback",
+		`{"source_provider": "spotify", "dest_provider": "deezer", "verdict": "rejected"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown_provider")
+
+	w = doProfileRequest(r, http.MethodPost, "/api/v1/matches/feedback",
+		`{"source_provider": "spotify", "source_track": {"external_id": "sp-1"}, "dest_provider": "youtube", "verdict": "rejected"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid match feedback")
+}
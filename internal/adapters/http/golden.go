@@ -0,0 +1,197 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// WithGoldenDatasetService enables the /admin/golden endpoints for curating
+// and exporting the golden dataset. It requires WithProviderAdmin for the
+// admin key.
+func WithGoldenDatasetService(golden ports.GoldenDatasetService) Option {
+	return func(h *Handler) {
+		h.golden = golden
+	}
+}
+
+// goldenCase is a golden dataset entry in the format read by the matchbench
+// command: a track result whose match and runner-up candidates are the
+// known candidates, and whose expected_id names the right one, or is empty
+// if none is.
+type goldenCase struct {
+	domain.TrackResult
+	ExpectedID string `json:"expected_id"`
+}
+
+func newGoldenCase(m domain.GoldenMatch) goldenCase {
+	gc := goldenCase{TrackResult: domain.TrackResult{
+		SourceTrack: m.SourceTrack,
+		Status:      domain.TrackStatusNotFound,
+	}}
+	if m.Expected != nil {
+		gc.Status = domain.TrackStatusMatched
+		gc.MatchedTrack = m.Expected
+		gc.ExpectedID = m.Expected.ExternalID
+	}
+	for _, t := range m.Rejected {
+		gc.Candidates = append(gc.Candidates, domain.TrackCandidate{Track: t})
+	}
+	return gc
+}
+
+// goldenFilter reads the source_provider and dest_provider query parameters.
+func goldenFilter(c *gin.Context) domain.GoldenMatchFilter {
+	return domain.GoldenMatchFilter{
+		SourceProvider: c.Query("source_provider"),
+		DestProvider:   c.Query("dest_provider"),
+	}
+}
+
+// ListGoldenMatches returns the entries of the golden dataset.
+//
+//	@Summary		List golden dataset
+//	@Description	Returns the labeled matches of the golden dataset, least recently updated first. Entries
+//	@Description	are drawn from match feedback, following the verdict of most accounts, until they are
+//	@Description	curated.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			source_provider	query		string	false	"Source provider of the entries"
+//	@Param			dest_provider	query		string	false	"Destination provider of the entries"
+//	@Success		200				{array}		domain.GoldenMatch
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/golden [get]
+func (h *Handler) ListGoldenMatches(c *gin.Context) {
+	matches, err := h.golden.ListMatches(c.Request.Context(), goldenFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if matches == nil {
+		matches = []domain.GoldenMatch{}
+	}
+	c.JSON(http.StatusOK, matches)
+}
+
+// CurateGoldenMatch adds a curated entry to the golden dataset.
+//
+//	@Summary		Curate golden match
+//	@Description	Saves a labeled match as curated, replacing the entry of the same source track and
+//	@Description	destination provider. Set expected to the right track, rejected to known wrong
+//	@Description	candidates, or both; an entry without expected says none of the rejected tracks is right.
+//	@Description	Curated entries are no longer changed by match feedback.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			match	body		domain.GoldenMatch	true	"Labeled match"
+//	@Success		200		{object}	domain.GoldenMatch
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		422		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/golden [post]
+func (h *Handler) CurateGoldenMatch(c *gin.Context) {
+	var match domain.GoldenMatch
+	if !h.bindJSON(c, &match) {
+		return
+	}
+
+	saved, err := h.golden.CurateMatch(c.Request.Context(), match)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidGoldenMatch) {
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation_failed",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+// DeleteGoldenMatch removes an entry from the golden dataset.
+//
+//	@Summary		Delete golden match
+//	@Description	Removes an entry from the golden dataset. Feedback given later on the same source track
+//	@Description	starts a new, uncurated entry.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path	string	true	"Golden match ID"
+//	@Success		204
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/golden/{id} [delete]
+func (h *Handler) DeleteGoldenMatch(c *gin.Context) {
+	if err := h.golden.DeleteMatch(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, domain.ErrGoldenMatchNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ExportGoldenMatches streams the golden dataset for the matchbench command.
+//
+//	@Summary		Export golden dataset
+//	@Description	Streams the golden dataset as newline-delimited JSON in the format read by the matchbench
+//	@Description	command, one track result per entry: the expected track is the matched track, the
+//	@Description	rejected tracks are its candidates and expected_id names the right one, or is empty if
+//	@Description	none is. Filter by dest_provider to benchmark the scorer of one provider.
+//	@Tags			admin
+//	@Produce		application/x-ndjson
+//	@Param			source_provider	query		string	false	"Source provider of the entries"
+//	@Param			dest_provider	query		string	false	"Destination provider of the entries"
+//	@Success		200				{object}	domain.TrackResult
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/golden/export.ndjson [get]
+func (h *Handler) ExportGoldenMatches(c *gin.Context) {
+	matches, err := h.golden.ListMatches(c.Request.Context(), goldenFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+	enc := json.NewEncoder(c.Writer)
+	for i, m := range matches {
+		if err := enc.Encode(newGoldenCase(m)); err != nil {
+			c.Error(err)
+			return
+		}
+		if (i+1)%ndjsonFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// -- Mock GoldenDatasetService -----------------------------------------------
+
+type mockGoldenDataset struct {
+	matches    []domain.GoldenMatch
+	lastFilter domain.GoldenMatchFilter
+	deleted    string
+}
+
+func (m *mockGoldenDataset) CurateMatch(_ context.Context, match domain.GoldenMatch) (*domain.GoldenMatch, error) {
+	if match.SourceTrack.ExternalID == "" {
+		return nil, domain.ErrInvalidGoldenMatch
+	}
+	match.ID = "g1"
+	match.Curated = true
+	return &match, nil
+}
+
+func (m *mockGoldenDataset) ListMatches(_ context.Context, filter domain.GoldenMatchFilter) ([]domain.GoldenMatch, error) {
+	m.lastFilter = filter
+	return m.matches, nil
+}
+
+func (m *mockGoldenDataset) DeleteMatch(_ context.Context, id string) error {
+	if id != "g1" {
+		return domain.ErrGoldenMatchNotFound
+	}
+	m.deleted = id
+	return nil
+}
+
+func setupGoldenRouter(golden *mockGoldenDataset) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{},
+		WithProviderAdmin(&mockProviderAdmin{disabled: map[string]string{}}, "secret"),
+		WithGoldenDatasetService(golden)).RegisterRoutes(r)
+	return r
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestGoldenDataset(t *testing.T) {
+	golden := &mockGoldenDataset{}
+	r := setupGoldenRouter(golden)
+
+	w := adminRequest(r, http.MethodGet, "/admin/golden?dest_provider=youtube", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+	assert.Equal(t, domain.GoldenMatchFilter{DestProvider: "youtube"}, golden.lastFilter)
+
+	w = adminRequest(r, http.MethodPost, "/admin/golden",
+		`{"source_provider":"spotify","source_track":{"external_id":"s1","name":"Song"},"dest_provider":"youtube","expected":{"external_id":"d1"}}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var saved domain.GoldenMatch
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &saved))
+	assert.Equal(t, "g1", saved.ID)
+	assert.True(t, saved.Curated)
+
+	w = adminRequest(r, http.MethodDelete, "/admin/golden/g1", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "g1", golden.deleted)
+}
+
+func TestGoldenDataset_Errors(t *testing.T) {
+	r := setupGoldenRouter(&mockGoldenDataset{})
+
+	w := adminRequest(r, http.MethodPost, "/admin/golden", `{"source_provider":"spotify","dest_provider":"youtube"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = adminRequest(r, http.MethodPost, "/admin/golden", `{"source_track":{"external_id":"s1"}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = adminRequest(r, http.MethodDelete, "/admin/golden/missing", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestExportGoldenMatches(t *testing.T) {
+	golden := &mockGoldenDataset{matches: []domain.GoldenMatch{
+		{
+			SourceProvider: "spotify", DestProvider: "youtube",
+			SourceTrack: domain.Track{ExternalID: "s1", Name: "Song"},
+			Expected:    &domain.Track{ExternalID: "d1"},
+			Rejected:    []domain.Track{{ExternalID: "d2"}},
+		},
+		{
+			SourceProvider: "spotify", DestProvider: "youtube",
+			SourceTrack: domain.Track{ExternalID: "s2", Name: "Other"},
+			Rejected:    []domain.Track{{ExternalID: "d3"}},
+		},
+	}}
+	r := setupGoldenRouter(golden)
+
+	w := adminRequest(r, http.MethodGet, "/admin/golden/export.ndjson?source_provider=spotify", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ndjsonContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, domain.GoldenMatchFilter{SourceProvider: "spotify"}, golden.lastFilter)
+
+	body := w.Body.String()
+	var cases []goldenCase
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var gc goldenCase
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &gc))
+		cases = append(cases, gc)
+	}
+	require.Len(t, cases, 2)
+
+	assert.Equal(t, domain.TrackStatusMatched, cases[0].Status)
+	assert.Equal(t, "d1", cases[0].ExpectedID)
+	assert.Equal(t, "d1", cases[0].MatchedTrack.ExternalID)
+	require.Len(t, cases[0].Candidates, 1)
+	assert.Equal(t, "d2", cases[0].Candidates[0].Track.ExternalID)
+
+	assert.Equal(t, domain.TrackStatusNotFound, cases[1].Status)
+	assert.Empty(t, cases[1].ExpectedID)
+	assert.Contains(t, body, `"expected_id":""`, "an empty expected_id says no candidate is right")
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var gzipWriters = sync.Pool{
+	New: func() any { return gzip.NewWriter(nil) },
+}
+
+// gzipWriter compresses everything written to the response. The gzip
+// stream is only started by the first write, so empty responses stay empty.
+type gzipWriter struct {
+	gin.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w *gzipWriter) WriteHeader(code int) {
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipWriter) Write(b []byte) (int, error) {
+	if w.gz == nil {
+		w.Header().Del("Content-Length")
+		w.gz = gzipWriters.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+	return w.gz.Write(b)
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends the data compressed so far, so streamed responses reach the
+// client as they are written.
+func (w *gzipWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipWriter) close() {
+	if w.gz == nil {
+		return
+	}
+	_ = w.gz.Close()
+	w.gz.Reset(nil)
+	gzipWriters.Put(w.gz)
+	w.gz = nil
+}
+
+// Gzip is a middleware that compresses responses for clients that send
+// "Accept-Encoding: gzip". WebSocket upgrades are left alone.
+func Gzip(c *gin.Context) {
+	if !acceptsGzip(c.Request) || c.GetHeader("Upgrade") != "" {
+		c.Next()
+		return
+	}
+
+	c.Header("Content-Encoding", "gzip")
+	c.Header("Vary", "Accept-Encoding")
+	w := &gzipWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	defer func() {
+		if w.gz == nil && !w.ResponseWriter.Written() {
+			w.Header().Del("Content-Encoding")
+		}
+		w.close()
+		c.Writer = w.ResponseWriter
+	}()
+	c.Next()
+}
+
+// acceptsGzip reports whether the Accept-Encoding header of r lists gzip
+// with a non-zero weight.
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(enc, ";")
+		if strings.TrimSpace(name) != "gzip" {
+			continue
+		}
+		q, ok := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !ok {
+			return true
+		}
+		weight, err := strconv.ParseFloat(q, 64)
+		return err != nil || weight > 0
+	}
+	return false
+}
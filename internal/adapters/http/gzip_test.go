@@ -0,0 +1,69 @@
+package http
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGzip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Gzip)
+	NewHandler(&mockMigrationService{migrationResult: largeResult(50)}).RegisterRoutes(r)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/migrations/mig-1", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	gz, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	var result domain.MigrationResult
+	require.NoError(t, json.NewDecoder(gz).Decode(&result))
+	assert.Len(t, result.TrackResults, 50)
+
+	// Without Accept-Encoding the body is sent as is.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/migrations/mig-1", nil))
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+}
+
+func TestGzip_EmptyBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Gzip)
+	r.DELETE("/empty", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/empty", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Zero(t, w.Body.Len())
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	for header, want := range map[string]bool{
+		"":                    false,
+		"gzip":                true,
+		"deflate, gzip;q=0.5": true,
+		"gzip;q=0":            false,
+		"identity":            false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", header)
+		assert.Equal(t, want, acceptsGzip(req), header)
+	}
+}
@@ -1,7 +1,13 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jpp0ca/MusicMigration-API/internal/domain"
@@ -10,37 +16,235 @@ import (
 
 // Handler holds the HTTP handlers for the migration API.
 type Handler struct {
-	service ports.MigrationService
+	service     ports.MigrationService
+	accounts    ports.AccountService
+	tokens      ports.TokenVault
+	importer    ports.PlaylistImporter
+	jobs        ports.JobService
+	profiles    ports.ProfileService
+	feedback    ports.MatchFeedbackService
+	connections ports.ConnectionService
+	oauth       ports.OAuthService
+	limiter     *RateLimiter
+	health      ports.HealthChecker
+	admin       ports.ProviderAdmin
+	adminKey    string
+	audit       ports.AuditLog
+	limits      ports.LimitService
+	golden      ports.GoldenDatasetService
+
+	// providers lists the registered provider names request bodies are
+	// validated against; nil disables the check.
+	providers []string
+}
+
+// Option configures optional dependencies of a Handler.
+type Option func(*Handler)
+
+// WithAccountService enables account registration and API key authentication
+// on all /api/v1 routes.
+func WithAccountService(accounts ports.AccountService) Option {
+	return func(h *Handler) {
+		h.accounts = accounts
+	}
+}
+
+// WithTokenVault enables the /api/v1/tokens endpoints and makes provider
+// tokens optional on requests, since they can be resolved from the vault.
+func WithTokenVault(tokens ports.TokenVault) Option {
+	return func(h *Handler) {
+		h.tokens = tokens
+	}
+}
+
+// WithRateLimiter enables per-client rate limiting on all /api/v1 routes.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(h *Handler) {
+		h.limiter = limiter
+	}
+}
+
+// WithHealthChecker makes /health a readiness probe that reports
+// connectivity to each provider.
+func WithHealthChecker(health ports.HealthChecker) Option {
+	return func(h *Handler) {
+		h.health = health
+	}
+}
+
+// WithProviderAdmin enables the /admin/providers endpoints, authorized by
+// the given key in the X-Admin-Key header.
+func WithProviderAdmin(admin ports.ProviderAdmin, key string) Option {
+	return func(h *Handler) {
+		h.admin = admin
+		h.adminKey = key
+	}
+}
+
+// WithProviders makes request bodies naming a provider outside names fail
+// validation with 422 Unprocessable Entity.
+func WithProviders(names []string) Option {
+	return func(h *Handler) {
+		h.providers = slices.Sorted(slices.Values(names))
+	}
 }
 
 // NewHandler creates a new HTTP handler with the given migration service.
-func NewHandler(service ports.MigrationService) *Handler {
-	return &Handler{service: service}
+func NewHandler(service ports.MigrationService, opts ...Option) *Handler {
+	h := &Handler{service: service}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // RegisterRoutes sets up all API routes on the given Gin engine.
 func (h *Handler) RegisterRoutes(r *gin.Engine) {
 	r.GET("/health", h.Health)
 
-	api := r.Group("/api/v1")
+	if h.admin != nil {
+		admin := r.Group("/admin", h.RequireAdminKey)
+		admin.GET("/providers", h.ListProviders)
+		admin.POST("/providers/:name/disable", h.DisableProvider)
+		admin.POST("/providers/:name/enable", h.EnableProvider)
+		if h.jobs != nil {
+			admin.GET("/jobs/:id/debug", h.GetJobDebugLog)
+		}
+		if h.audit != nil {
+			admin.GET("/audit", h.QueryAuditLog)
+		}
+		if h.limits != nil {
+			admin.GET("/accounts/:id/limits", h.GetAccountLimits)
+			admin.PUT("/accounts/:id/limits", h.SetAccountLimits)
+			admin.DELETE("/accounts/:id/limits", h.ResetAccountLimits)
+		}
+		if h.golden != nil {
+			admin.GET("/golden", h.ListGoldenMatches)
+			admin.POST("/golden", h.CurateGoldenMatch)
+			admin.GET("/golden/export.ndjson", h.ExportGoldenMatches)
+			admin.DELETE("/golden/:id", h.DeleteGoldenMatch)
+		}
+	}
+
+	// Authenticated requests are limited per account. Requests whose API
+	// key fails to authenticate are charged to their client IP, so
+	// guessing keys, each a new one, exhausts a single bucket and stops
+	// reaching the account store.
+	var auth, limit []gin.HandlerFunc
+	if h.accounts != nil {
+		if h.limiter != nil {
+			auth = append(auth, h.LimitAuthFailures)
+		}
+		auth = append(auth, h.RequireAPIKey)
+	}
+	if h.limiter != nil {
+		limit = []gin.HandlerFunc{h.RateLimit}
+	}
+
+	// v2 serves the same endpoints as v1 with responses wrapped in an
+	// envelope; v1 responses are left unchanged.
+	h.registerAPI(r.Group("/api/v1"), auth, limit)
+	h.registerAPI(r.Group("/api/v2", Envelope), auth, limit)
+
+	if h.jobs != nil {
+		ws := r.Group("/ws")
+		ws.Use(apiKeyFromQuery)
+		ws.Use(auth...)
+		ws.Use(limit...)
+		ws.GET("/migrations/:id", h.WatchJob)
+	}
+}
+
+// registerAPI sets up the versioned API routes on api. auth authenticates
+// the API key of a request, if accounts are enabled, and limit rate limits
+// it.
+func (h *Handler) registerAPI(api *gin.RouterGroup, auth, limit []gin.HandlerFunc) {
+	// The provider redirects the user's browser to the OAuth callback,
+	// which carries no API key; its state names the account instead.
+	if h.oauth != nil {
+		api.GET("/oauth/:provider/callback", append(limit, h.OAuthCallback)...)
+	}
+	if h.accounts != nil {
+		api.POST("/accounts", append(limit, h.RegisterAccount)...)
+	}
+	api = api.Group("", append(auth, limit...)...)
 	{
 		api.GET("/playlists", h.ListPlaylists)
+		api.GET("/playlists/:id", h.GetPlaylist)
+		api.PATCH("/playlists/:id", h.UpdatePlaylist)
+		api.DELETE("/playlists/:id", h.DeletePlaylist)
+		api.DELETE("/playlists/:id/tracks", h.RemoveTracks)
+		api.GET("/search", h.SearchTracks)
+		api.GET("/export/account", h.ExportAccount)
 		api.POST("/migrate", h.MigratePlaylist)
+		api.POST("/migrate/preview", h.PreviewMigration)
+		api.POST("/merge", h.MergePlaylists)
+		api.GET("/migrations", h.ListMigrations)
+		api.GET("/migrations/compare", h.CompareMigrations)
+		api.GET("/migrations/:id", h.GetMigration)
+		api.GET("/migrations/:id/report", h.GetMigrationReport)
+		api.GET("/migrations/:id/results.ndjson", h.GetMigrationResults)
+		api.POST("/migrations/:id/retry-failed", h.RetryFailedTracks)
+		api.POST("/migrations/:id/rollback", h.RollbackMigration)
+		api.POST("/migrations/:id/reverse", h.ReverseMigration)
+
+		if h.tokens != nil {
+			api.PUT("/tokens/:provider", h.StoreToken)
+			api.DELETE("/tokens/:provider", h.DeleteToken)
+		}
+		if h.connections != nil {
+			api.GET("/me/connections", h.ListConnections)
+			api.DELETE("/me/connections/:provider", h.RevokeConnection)
+		}
+		if h.oauth != nil {
+			api.GET("/oauth/:provider/authorize", h.AuthorizeProvider)
+		}
+		if h.importer != nil {
+			api.POST("/imports/m3u", h.ImportM3U)
+		}
+		if h.jobs != nil {
+			api.POST("/jobs", h.EnqueueMigration)
+			api.POST("/migrate/account", h.MigrateAccount)
+			api.GET("/jobs/:id", h.GetJob)
+		}
+		if h.profiles != nil {
+			api.POST("/profiles", h.CreateProfile)
+			api.GET("/profiles", h.ListProfiles)
+			api.GET("/profiles/:id", h.GetProfile)
+			api.PUT("/profiles/:id", h.UpdateProfile)
+			api.DELETE("/profiles/:id", h.DeleteProfile)
+			api.POST("/profiles/:id/migrate", h.MigrateWithProfile)
+		}
+		if h.feedback != nil {
+			api.POST("/matches/feedback", h.SubmitMatchFeedback)
+		}
 	}
 }
 
-// Health returns a simple health check response.
+// Health returns the health status of the API.
 //
 //	@Summary		Health check
-//	@Description	Returns the health status of the API
+//	@Description	Returns the health status of the API. When provider checks are enabled, each
+//	@Description	provider's API is pinged and the overall status is ok, degraded (some providers
+//	@Description	unreachable) or down (all unreachable). Down responds with 503 so load balancers
+//	@Description	can take the instance out of rotation.
 //	@Tags			health
 //	@Produce		json
-//	@Success		200	{object}	map[string]string
+//	@Success		200	{object}	domain.HealthReport
+//	@Failure		503	{object}	domain.HealthReport
 //	@Router			/health [get]
 func (h *Handler) Health(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status": "ok",
-	})
+	if h.health == nil {
+		c.JSON(http.StatusOK, domain.HealthReport{Status: domain.HealthOK})
+		return
+	}
+
+	report := h.health.Check(c.Request.Context())
+	status := http.StatusOK
+	if report.Status == domain.HealthDown {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
 }
 
 // ListPlaylists returns playlists for the given provider and authenticated user.
@@ -48,37 +252,349 @@ func (h *Handler) Health(c *gin.Context) {
 //	@Summary		List user playlists
 //	@Description	Returns all playlists for the authenticated user on the specified streaming provider.
 //	@Description	Supported providers: spotify, youtube.
+//	@Description	When 'limit' or 'cursor' is given, only a single page is returned; the cursor for the
+//	@Description	next page is sent in the X-Next-Cursor response header (absent on the last page).
 //	@Tags			playlists
 //	@Produce		json
 //	@Param			provider	query		string	true	"Streaming provider"	Enums(spotify, youtube)
+//	@Param			limit		query		int		false	"Page size (max 50)"
+//	@Param			cursor		query		string	false	"Cursor from a previous X-Next-Cursor header"
 //	@Param			Authorization	header	string	true	"Bearer token for the streaming provider"
 //	@Success		200	{array}		domain.Playlist
+//	@Header			200	{string}	X-Next-Cursor	"Cursor for the next page"
+//	@Header			200	{integer}	X-Total-Count	"Total number of playlists, when known"
 //	@Failure		400	{object}	ErrorResponse
 //	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		429	{object}	ErrorResponse
 //	@Failure		500	{object}	ErrorResponse
 //	@Security		BearerAuth
 //	@Router			/api/v1/playlists [get]
 func (h *Handler) ListPlaylists(c *gin.Context) {
-	provider := c.Query("provider")
-	if provider == "" {
+	provider, token, ok := h.requireProviderAndToken(c)
+	if !ok {
+		return
+	}
+
+	limitParam, cursor := c.Query("limit"), c.Query("cursor")
+	if limitParam != "" || cursor != "" {
+		h.listPlaylistsPage(c, provider, token, limitParam, cursor)
+		return
+	}
+
+	playlists, err := h.service.ListPlaylists(c.Request.Context(), provider, token)
+	if err != nil {
+		if providerError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, playlists)
+}
+
+// listPlaylistsPage serves a single page of playlists, exposing pagination
+// metadata in headers so the body keeps the same shape as the full listing.
+func (h *Handler) listPlaylistsPage(c *gin.Context, provider, token, limitParam, cursor string) {
+	limit := 0
+	if limitParam != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitParam); err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "bad_request",
+				Message: "query parameter 'limit' must be a positive integer",
+			})
+			return
+		}
+	}
+
+	page, err := h.service.ListPlaylistsPage(c.Request.Context(), provider, token, domain.PageRequest{
+		Limit:  limit,
+		Cursor: cursor,
+	})
+	if err != nil {
+		if providerError(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "bad_request",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if page.NextCursor != "" {
+		c.Header(nextCursorHeader, page.NextCursor)
+	}
+	if page.Total > 0 {
+		c.Header(totalCountHeader, strconv.Itoa(page.Total))
+	}
+	c.JSON(http.StatusOK, page.Items)
+}
+
+// GetPlaylist returns a single playlist including its tracks.
+//
+//	@Summary		Get playlist details
+//	@Description	Returns a single playlist with its full track list from the specified streaming provider.
+//	@Tags			playlists
+//	@Produce		json
+//	@Param			id				path		string	true	"Playlist ID"
+//	@Param			provider		query		string	true	"Streaming provider"	Enums(spotify, youtube)
+//	@Param			Authorization	header		string	true	"Bearer token for the streaming provider"
+//	@Success		200				{object}	domain.Playlist
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		429				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/api/v1/playlists/{id} [get]
+func (h *Handler) GetPlaylist(c *gin.Context) {
+	provider, token, ok := h.requireProviderAndToken(c)
+	if !ok {
+		return
+	}
+
+	playlist, err := h.service.GetPlaylist(c.Request.Context(), provider, token, c.Param("id"))
+	if err != nil {
+		if providerError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist)
+}
+
+// UpdatePlaylist changes the name, description or visibility of a playlist.
+//
+//	@Summary		Update playlist details
+//	@Description	Renames a playlist or changes its description, visibility or collaborative setting. Omitted fields are left unchanged.
+//	@Tags			playlists
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string					true	"Playlist ID"
+//	@Param			provider		query		string					true	"Streaming provider"	Enums(spotify, youtube)
+//	@Param			Authorization	header		string					true	"Bearer token for the streaming provider"
+//	@Param			request			body		domain.PlaylistUpdate	true	"Fields to update"
+//	@Success		204
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		422	{object}	ErrorResponse
+//	@Failure		429	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/api/v1/playlists/{id} [patch]
+func (h *Handler) UpdatePlaylist(c *gin.Context) {
+	provider, token, ok := h.requireProviderAndToken(c)
+	if !ok {
+		return
+	}
+
+	var update domain.PlaylistUpdate
+	if !h.bindJSON(c, &update) {
+		return
+	}
+
+	if err := h.service.UpdatePlaylist(c.Request.Context(), provider, token, c.Param("id"), update); err != nil {
+		if providerError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeletePlaylist deletes a playlist on the given provider.
+//
+//	@Summary		Delete playlist
+//	@Description	Deletes a playlist from the authenticated user's library on the specified provider.
+//	@Tags			playlists
+//	@Produce		json
+//	@Param			id				path	string	true	"Playlist ID"
+//	@Param			provider		query	string	true	"Streaming provider"	Enums(spotify, youtube)
+//	@Param			Authorization	header	string	true	"Bearer token for the streaming provider"
+//	@Success		204
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		429	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/api/v1/playlists/{id} [delete]
+func (h *Handler) DeletePlaylist(c *gin.Context) {
+	provider, token, ok := h.requireProviderAndToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePlaylist(c.Request.Context(), provider, token, c.Param("id")); err != nil {
+		if providerError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveTracks removes tracks from a playlist on the given provider.
+//
+//	@Summary		Remove tracks from playlist
+//	@Description	Removes the given tracks (by their provider-specific IDs) from a playlist.
+//	@Tags			playlists
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path	string						true	"Playlist ID"
+//	@Param			provider		query	string						true	"Streaming provider"	Enums(spotify, youtube)
+//	@Param			Authorization	header	string						true	"Bearer token for the streaming provider"
+//	@Param			request			body	domain.RemoveTracksRequest	true	"Track IDs to remove"
+//	@Success		204
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		429	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/api/v1/playlists/{id}/tracks [delete]
+func (h *Handler) RemoveTracks(c *gin.Context) {
+	provider, token, ok := h.requireProviderAndToken(c)
+	if !ok {
+		return
+	}
+
+	var req domain.RemoveTracksRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.service.RemoveTracks(c.Request.Context(), provider, token, c.Param("id"), req.TrackIDs); err != nil {
+		if providerError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SearchTracks looks up a track on a provider without running a migration.
+//
+//	@Summary		Search track
+//	@Description	Searches for a track on the specified provider and returns every candidate with its
+//	@Description	confidence score, in the order ranked by the provider. Useful for manual matching and
+//	@Description	for debugging confidence scoring.
+//	@Tags			search
+//	@Produce		json
+//	@Param			provider		query	string	true	"Streaming provider"	Enums(spotify, youtube)
+//	@Param			name			query	string	false	"Track name (required unless isrc is given)"
+//	@Param			artist			query	[]string	false	"Artist name, repeated for each artist"	collectionFormat(multi)
+//	@Param			album			query	string	false	"Album name"
+//	@Param			isrc			query	string	false	"ISRC code"
+//	@Param			release_date	query	string	false	"Release date (YYYY, YYYY-MM or YYYY-MM-DD); among similar candidates the one released closest wins"
+//	@Param			market			query	string	false	"ISO 3166-1 alpha-2 market to search in"
+//	@Param			classical		query	bool	false	"Score as a classical work (composer, work, movement)"
+//	@Param			strict_versions	query	bool	false	"Score different versions (live, remix, acoustic, cover) 0 instead of penalizing them"
+//	@Param			matching_strategy	query	string	false	"Scorer configuration"	Enums(isrc_only, strict, relaxed, duration_weighted)
+//	@Param			rerecordings	query	string	false	"Prefer or avoid re-recordings of the track, or with any do not prefer candidates released close to release_date"	Enums(any, prefer, avoid)
+//	@Param			Authorization	header	string	true	"Bearer token for the streaming provider"
+//	@Success		200	{array}		domain.TrackCandidate
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		429	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/api/v1/search [get]
+func (h *Handler) SearchTracks(c *gin.Context) {
+	provider, token, ok := h.requireProviderAndToken(c)
+	if !ok {
+		return
+	}
+
+	track := domain.Track{
+		Name:    c.Query("name"),
+		Artists: queryArtists(c),
+		Album:   c.Query("album"),
+		ISRC:    c.Query("isrc"),
+
+		ReleaseDate: c.Query("release_date"),
+	}
+	if track.Name == "" && track.ISRC == "" {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "bad_request",
-			Message: "query parameter 'provider' is required",
+			Message: "query parameter 'name' or 'isrc' is required",
 		})
 		return
 	}
 
-	token := extractToken(c)
-	if token == "" {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
-			Error:   "unauthorized",
-			Message: "Authorization header with Bearer token is required",
+	ctx := c.Request.Context()
+	if market := c.Query("market"); market != "" {
+		ctx = domain.ContextWithMarket(ctx, strings.ToUpper(market))
+	}
+	strategy := domain.MatchingStrategy(c.Query("matching_strategy"))
+	if !strategy.Valid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: fmt.Sprintf("unknown matching_strategy %q", strategy),
 		})
 		return
 	}
+	rerecordings := domain.RerecordingPreference(c.Query("rerecordings"))
+	if !rerecordings.Valid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: fmt.Sprintf("unknown rerecording preference %q", rerecordings),
+		})
+		return
+	}
+	classical, _ := strconv.ParseBool(c.Query("classical"))
+	strict, _ := strconv.ParseBool(c.Query("strict_versions"))
+	ctx = domain.ContextWithMatchOptions(ctx, domain.MatchOptions{
+		Classical:      classical,
+		StrictVersions: strict,
+		Strategy:       strategy,
+		Rerecordings:   rerecordings,
+	})
 
-	playlists, err := h.service.ListPlaylists(c.Request.Context(), provider, token)
+	candidates, err := h.service.SearchTracks(ctx, provider, token, track)
 	if err != nil {
+		if providerError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "internal_error",
 			Message: err.Error(),
@@ -86,7 +602,19 @@ func (h *Handler) ListPlaylists(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, playlists)
+	c.JSON(http.StatusOK, candidates)
+}
+
+// queryArtists returns the artists of the repeated artist query parameter.
+// Each one is a single name, commas included, as in "Tyler, The Creator".
+func queryArtists(c *gin.Context) []string {
+	var artists []string
+	for _, artist := range c.QueryArray("artist") {
+		if artist = strings.TrimSpace(artist); artist != "" {
+			artists = append(artists, artist)
+		}
+	}
+	return artists
 }
 
 // MigratePlaylist initiates a playlist migration between two streaming providers.
@@ -97,38 +625,449 @@ func (h *Handler) ListPlaylists(c *gin.Context) {
 //	@Description	and creates a new playlist with the matched tracks. Returns detailed results with confidence scores.
 //	@Tags			migration
 //	@Accept			json
-//	@Produce		json
-//	@Param			request	body		domain.MigrationRequest	true	"Migration request with source/dest providers, tokens, and playlist ID"
-//	@Success		200		{object}	domain.MigrationResult
-//	@Failure		400		{object}	ErrorResponse
-//	@Failure		500		{object}	ErrorResponse
+//	@Produce		json,application/x-ndjson
+//	@Param			request			body		domain.MigrationRequest	true	"Migration request with source/dest providers, tokens, and playlist ID"
+//	@Param			Idempotency-Key	header		string					false	"Client-generated key; repeated requests with the same key return the original migration"
+//	@Success		200				{object}	domain.MigrationResult
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		409				{object}	ErrorResponse
+//	@Failure		422				{object}	ErrorResponse
+//	@Failure		429				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Failure		503				{object}	ErrorResponse
+//	@Failure		504				{object}	ErrorResponse
 //	@Router			/api/v1/migrate [post]
 func (h *Handler) MigratePlaylist(c *gin.Context) {
 	var req domain.MigrationRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	req.IdempotencyKey = c.GetHeader(idempotencyKeyHeader)
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLen {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "bad_request",
-			Message: "invalid request body: " + err.Error(),
+			Message: fmt.Sprintf("%s header must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLen),
 		})
 		return
 	}
 
 	result, err := h.service.MigratePlaylist(c.Request.Context(), req)
 	if err != nil {
+		migrationFailed(c, err)
+		return
+	}
+
+	writeMigrationResult(c, result)
+}
+
+// migrationFailed responds with the status matching the error of a failed
+// migration.
+func migrationFailed(c *gin.Context, err error) {
+	if providerError(c, err) || limitError(c, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrMigrationInProgress) || errors.Is(err, domain.ErrPlaylistLocked) {
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "migration_in_progress",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, domain.ErrPlaylistExists) {
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "playlist_exists",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "idempotency_key_reused",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidFilter) {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "invalid_filter",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, domain.ErrQuotaExceeded) {
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error:   "quota_exceeded",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, domain.ErrTimeout) {
+		c.JSON(http.StatusGatewayTimeout, ErrorResponse{
+			Error:   "timeout",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "migration_failed",
+		Message: err.Error(),
+	})
+}
+
+// PreviewMigration estimates a migration without running it.
+//
+//	@Summary		Preview migration
+//	@Description	Fetches the source playlist and reports its track count, how many tracks carry an ISRC or a known match,
+//	@Description	the estimated duration and the API quota units per provider that migrating it would take.
+//	@Description	Nothing is searched on or written to the destination.
+//	@Tags			migration
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		domain.MigrationRequest	true	"Migration request to preview"
+//	@Success		200		{object}	domain.MigrationPreview
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		422		{object}	ErrorResponse
+//	@Failure		429		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Failure		503		{object}	ErrorResponse
+//	@Failure		504		{object}	ErrorResponse
+//	@Router			/api/v1/migrate/preview [post]
+func (h *Handler) PreviewMigration(c *gin.Context) {
+	var req domain.MigrationRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	preview, err := h.service.PreviewMigration(c.Request.Context(), req)
+	if err != nil {
+		if providerError(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrTimeout) {
+			c.JSON(http.StatusGatewayTimeout, ErrorResponse{
+				Error:   "timeout",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// ListMigrations returns the migration history of the authenticated account.
+//
+//	@Summary		List migrations
+//	@Description	Returns all migrations run by the authenticated account, oldest first.
+//	@Tags			migration
+//	@Produce		json
+//	@Success		200	{array}		domain.MigrationResult
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/migrations [get]
+func (h *Handler) ListMigrations(c *gin.Context) {
+	results, err := h.service.ListMigrations(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// GetMigration returns a single stored migration result.
+//
+//	@Summary		Get migration
+//	@Description	Returns the stored result of a previous migration. With "Accept: application/x-ndjson" the result
+//	@Description	is streamed as newline-delimited JSON: the result without track_results, then one line per track result.
+//	@Tags			migration
+//	@Produce		json,application/x-ndjson
+//	@Param			id	path		string	true	"Migration ID"
+//	@Success		200	{object}	domain.MigrationResult
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/migrations/{id} [get]
+func (h *Handler) GetMigration(c *gin.Context) {
+	result, err := h.service.GetMigration(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrMigrationNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	writeMigrationResult(c, result)
+}
+
+// RetryFailedTracks re-runs matching for the tracks a migration could not match.
+//
+//	@Summary		Retry failed tracks
+//	@Description	Searches again for tracks of a stored migration whose status is "not_found", or "error" with a
+//	@Description	retryable error code, merges the new results and appends newly matched tracks to the destination
+//	@Description	playlist. Retryable "add_failed" tracks are added again without searching.
+//	@Description	The Authorization header must carry a token for the destination provider.
+//	@Tags			migration
+//	@Produce		json,application/x-ndjson
+//	@Param			id				path		string	true	"Migration ID"
+//	@Param			Authorization	header		string	true	"Bearer token for the destination provider"
+//	@Success		200				{object}	domain.MigrationResult
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		409				{object}	ErrorResponse
+//	@Failure		429				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Failure		504				{object}	ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/api/v1/migrations/{id}/retry-failed [post]
+func (h *Handler) RetryFailedTracks(c *gin.Context) {
+	token, ok := h.requireToken(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.RetryFailedTracks(c.Request.Context(), c.Param("id"), token)
+	if err != nil {
+		if providerError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, domain.ErrMigrationNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrAlreadyRolledBack):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "rolled_back",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrQuotaExceeded):
+			c.JSON(http.StatusTooManyRequests, ErrorResponse{
+				Error:   "quota_exceeded",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrTimeout):
+			c.JSON(http.StatusGatewayTimeout, ErrorResponse{
+				Error:   "timeout",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "retry_failed",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	writeMigrationResult(c, result)
+}
+
+// ReverseMigration migrates the destination playlist of a stored migration
+// back to its source provider.
+//
+//	@Summary		Reverse migration
+//	@Description	Runs the mirror-image migration of a stored one: the playlist it created on the destination
+//	@Description	provider is migrated back to the source provider. Tracks the original migration matched are
+//	@Description	mapped back to their source IDs without searching; only tracks added since are searched.
+//	@Description	source_token is for the original destination provider and dest_token for the original source.
+//	@Tags			migration
+//	@Accept			json
+//	@Produce		json,application/x-ndjson
+//	@Param			id		path		string							true	"Migration ID"
+//	@Param			request	body		domain.ReverseMigrationRequest	true	"Tokens for the reversed direction"
+//	@Success		200		{object}	domain.MigrationResult
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Failure		429		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Failure		504		{object}	ErrorResponse
+//	@Router			/api/v1/migrations/{id}/reverse [post]
+func (h *Handler) ReverseMigration(c *gin.Context) {
+	var req domain.ReverseMigrationRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.service.ReverseMigration(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		if providerError(c, err) || limitError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, domain.ErrMigrationNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrAlreadyRolledBack):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "rolled_back",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrPlaylistLocked):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "migration_in_progress",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrQuotaExceeded):
+			c.JSON(http.StatusTooManyRequests, ErrorResponse{
+				Error:   "quota_exceeded",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrTimeout):
+			c.JSON(http.StatusGatewayTimeout, ErrorResponse{
+				Error:   "timeout",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "migration_failed",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	writeMigrationResult(c, result)
+}
+
+// RollbackMigration undoes a previous migration by deleting its destination playlist.
+//
+//	@Summary		Roll back migration
+//	@Description	Deletes the playlist created on the destination provider by a previous migration.
+//	@Description	The Authorization header must carry a token for the destination provider.
+//	@Tags			migration
+//	@Produce		json,application/x-ndjson
+//	@Param			id				path		string	true	"Migration ID"
+//	@Param			Authorization	header		string	true	"Bearer token for the destination provider"
+//	@Success		200				{object}	domain.MigrationResult
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		409				{object}	ErrorResponse
+//	@Failure		429				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/api/v1/migrations/{id}/rollback [post]
+func (h *Handler) RollbackMigration(c *gin.Context) {
+	token, ok := h.requireToken(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.RollbackMigration(c.Request.Context(), c.Param("id"), token)
+	if err != nil {
+		if providerError(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrMigrationNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, domain.ErrAlreadyRolledBack) {
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "rolled_back",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "migration_failed",
+			Error:   "rollback_failed",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	writeMigrationResult(c, result)
 }
 
+const (
+	// idempotencyKeyHeader carries a client-generated key that makes
+	// POST /migrate safe to retry.
+	idempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+)
+
 // ErrorResponse is the standard error response format.
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
+
+	// Fields details each rejected field when Error is "validation_failed".
+	Fields []FieldError `json:"fields,omitempty"`
+
+	// ResetsAt is when an account limit allows the request again, if
+	// known, when Error is "limit_exceeded".
+	ResetsAt *time.Time `json:"resets_at,omitempty"`
+}
+
+// requireProviderAndToken reads the 'provider' query parameter and the Bearer
+// token, writing an error response and returning ok=false if either is missing.
+func (h *Handler) requireProviderAndToken(c *gin.Context) (provider, token string, ok bool) {
+	provider = c.Query("provider")
+	if provider == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: "query parameter 'provider' is required",
+		})
+		return "", "", false
+	}
+
+	token, ok = h.requireToken(c)
+	if !ok {
+		return "", "", false
+	}
+
+	return provider, token, true
+}
+
+// requireToken reads the Bearer token, writing an error response and
+// returning ok=false if it is missing and cannot be resolved from the vault.
+func (h *Handler) requireToken(c *gin.Context) (token string, ok bool) {
+	token = extractToken(c)
+	if token == "" && h.tokens == nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "Authorization header with Bearer token is required",
+		})
+		return "", false
+	}
+
+	return token, true
 }
 
 // extractToken retrieves the Bearer token from the Authorization header.
@@ -139,3 +1078,58 @@ func extractToken(c *gin.Context) string {
 	}
 	return auth
 }
+
+// providerError writes the response and returns true if err was caused by
+// the provider itself rather than the request, or by a missing provider
+// token (400): 503 when it was disabled
+// through the admin API, 422 when it cannot be written to or lacks a
+// requested feature, and the status
+// the provider answered with when it rejected the token (401) or its scopes
+// (403), rate limited the request (429) or has no such playlist (404).
+func providerError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrMissingToken):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "missing_token",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidToken):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "invalid_token",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInsufficientScope):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "insufficient_scope",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrRateLimited):
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error:   "rate_limited",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrPlaylistNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "playlist_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrProviderDisabled):
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "provider_disabled",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrSourceOnlyProvider):
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "source_only_provider",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrCollaborativeUnsupported):
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "collaborative_unsupported",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
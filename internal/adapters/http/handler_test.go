@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jpp0ca/MusicMigration-API/internal/domain"
@@ -18,8 +21,13 @@ import (
 
 type mockMigrationService struct {
 	playlists       []domain.Playlist
+	playlist        *domain.Playlist
 	migrationResult *domain.MigrationResult
 	err             error
+	lastMarket      string
+	lastMatchOpts   domain.MatchOptions
+	lastRequest     domain.MigrationRequest
+	lastMerge       domain.MergeRequest
 }
 
 func (m *mockMigrationService) ListPlaylists(_ context.Context, _ string, _ string) ([]domain.Playlist, error) {
@@ -29,15 +37,140 @@ func (m *mockMigrationService) ListPlaylists(_ context.Context, _ string, _ stri
 	return m.playlists, nil
 }
 
-func (m *mockMigrationService) MigratePlaylist(_ context.Context, _ domain.MigrationRequest) (*domain.MigrationResult, error) {
+func (m *mockMigrationService) MigratePlaylist(_ context.Context, req domain.MigrationRequest) (*domain.MigrationResult, error) {
+	m.lastRequest = req
 	if m.err != nil {
 		return nil, m.err
 	}
 	return m.migrationResult, nil
 }
 
+func (m *mockMigrationService) MergePlaylists(_ context.Context, req domain.MergeRequest) (*domain.MigrationResult, error) {
+	m.lastMerge = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.migrationResult, nil
+}
+
+func (m *mockMigrationService) PreviewMigration(_ context.Context, req domain.MigrationRequest) (*domain.MigrationPreview, error) {
+	m.lastRequest = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &domain.MigrationPreview{
+		SourceProvider: req.SourceProvider,
+		DestProvider:   req.DestProvider,
+		PlaylistID:     req.PlaylistID,
+		TotalTracks:    3,
+	}, nil
+}
+
+func (m *mockMigrationService) ListPlaylistsPage(_ context.Context, _ string, _ string, page domain.PageRequest) (*domain.PlaylistPage, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	end := page.Limit
+	if end > len(m.playlists) {
+		end = len(m.playlists)
+	}
+	result := &domain.PlaylistPage{Items: m.playlists[:end], Total: len(m.playlists)}
+	if end < len(m.playlists) {
+		result.NextCursor = "next"
+	}
+	return result, nil
+}
+
+func (m *mockMigrationService) SearchTracks(ctx context.Context, _ string, _ string, track domain.Track) ([]domain.TrackCandidate, error) {
+	m.lastMarket = domain.MarketFromContext(ctx)
+	m.lastMatchOpts = domain.MatchOptionsFromContext(ctx)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []domain.TrackCandidate{{Track: track, ConfidenceScore: 1.0}}, nil
+}
+
+func (m *mockMigrationService) GetPlaylist(_ context.Context, _ string, _ string, _ string) (*domain.Playlist, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.playlist, nil
+}
+
+func (m *mockMigrationService) ExportAccount(_ context.Context, provider string, _ string) (*domain.AccountExport, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &domain.AccountExport{Provider: provider, Playlists: m.playlists}, nil
+}
+
+func (m *mockMigrationService) UpdatePlaylist(_ context.Context, _ string, _ string, _ string, _ domain.PlaylistUpdate) error {
+	return m.err
+}
+
+func (m *mockMigrationService) RemoveTracks(_ context.Context, _ string, _ string, _ string, _ []string) error {
+	return m.err
+}
+
+func (m *mockMigrationService) DeletePlaylist(_ context.Context, _ string, _ string, _ string) error {
+	return m.err
+}
+
+func (m *mockMigrationService) GetMigration(_ context.Context, id string) (*domain.MigrationResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.migrationResult != nil {
+		return m.migrationResult, nil
+	}
+	return &domain.MigrationResult{ID: id}, nil
+}
+
+func (m *mockMigrationService) ListMigrations(_ context.Context) ([]domain.MigrationResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []domain.MigrationResult{}, nil
+}
+
+func (m *mockMigrationService) CompareMigrations(_ context.Context, a, b string) (*domain.MigrationComparison, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &domain.MigrationComparison{A: a, B: b}, nil
+}
+
+func (m *mockMigrationService) RetryFailedTracks(_ context.Context, id string, _ string) (*domain.MigrationResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &domain.MigrationResult{ID: id}, nil
+}
+
+func (m *mockMigrationService) ReverseMigration(_ context.Context, id string, _ domain.ReverseMigrationRequest) (*domain.MigrationResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &domain.MigrationResult{ID: "reversed", ReversedFrom: id}, nil
+}
+
+func (m *mockMigrationService) RollbackMigration(_ context.Context, id string, _ string) (*domain.MigrationResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &domain.MigrationResult{ID: id, RolledBack: true}, nil
+}
+
 // -- Helpers -----------------------------------------------------------------
 
+type mockHealthChecker struct {
+	report *domain.HealthReport
+}
+
+func (m *mockHealthChecker) Check(_ context.Context) *domain.HealthReport {
+	return m.report
+}
+
 func setupRouter(svc *mockMigrationService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -63,6 +196,40 @@ func TestHealth(t *testing.T) {
 	assert.Equal(t, "ok", body["status"])
 }
 
+func TestHealth_ProviderChecks(t *testing.T) {
+	tests := []struct {
+		status domain.HealthStatus
+		code   int
+	}{
+		{domain.HealthOK, http.StatusOK},
+		{domain.HealthDegraded, http.StatusOK},
+		{domain.HealthDown, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			NewHandler(&mockMigrationService{}, WithHealthChecker(&mockHealthChecker{
+				report: &domain.HealthReport{
+					Status:    tt.status,
+					Providers: []domain.ProviderHealth{{Name: "spotify", Status: tt.status, LatencyMS: 12}},
+				},
+			})).RegisterRoutes(r)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			assert.Equal(t, tt.code, w.Code)
+
+			var report domain.HealthReport
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, tt.status, report.Status)
+			assert.Len(t, report.Providers, 1)
+		})
+	}
+}
+
 func TestListPlaylists_Success(t *testing.T) {
 	svc := &mockMigrationService{
 		playlists: []domain.Playlist{
@@ -85,6 +252,57 @@ func TestListPlaylists_Success(t *testing.T) {
 	assert.Len(t, playlists, 2)
 }
 
+func TestListPlaylists_Paginated(t *testing.T) {
+	svc := &mockMigrationService{
+		playlists: []domain.Playlist{
+			{ID: "1", Name: "Rock Classics"},
+			{ID: "2", Name: "Jazz Vibes"},
+			{ID: "3", Name: "Lo-fi"},
+		},
+	}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/playlists?provider=spotify&limit=2", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "next", w.Header().Get("X-Next-Cursor"))
+	assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+
+	var playlists []domain.Playlist
+	err := json.Unmarshal(w.Body.Bytes(), &playlists)
+	require.NoError(t, err)
+	assert.Len(t, playlists, 2)
+}
+
+func TestListPlaylists_InvalidLimit(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/playlists?provider=spotify&limit=abc", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListPlaylists_InvalidCursor(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: fmt.Errorf("spotify: %w %q", domain.ErrInvalidCursor, "abc")})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/playlists?provider=spotify&cursor=abc", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "bad_request", resp.Error)
+}
+
 func TestListPlaylists_MissingProvider(t *testing.T) {
 	r := setupRouter(&mockMigrationService{})
 
@@ -106,6 +324,171 @@ func TestListPlaylists_MissingToken(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
+func TestGetPlaylist_Success(t *testing.T) {
+	svc := &mockMigrationService{
+		playlist: &domain.Playlist{
+			ID:         "pl-1",
+			Name:       "Rock Classics",
+			TrackCount: 1,
+			Tracks:     []domain.Track{{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}}},
+		},
+	}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/playlists/pl-1?provider=spotify", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var playlist domain.Playlist
+	err := json.Unmarshal(w.Body.Bytes(), &playlist)
+	require.NoError(t, err)
+	assert.Len(t, playlist.Tracks, 1)
+}
+
+func TestGetPlaylist_NotFound(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: domain.ErrPlaylistNotFound})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/playlists/missing?provider=spotify", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUpdatePlaylist_Success(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/playlists/pl-1?provider=spotify",
+		bytes.NewReader([]byte(`{"name":"Renamed","public":true}`)))
+	req.Header.Set("Authorization", "Bearer test-token")
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestDeletePlaylist_MissingProvider(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/playlists/pl-1", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRemoveTracks_EmptyList(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/playlists/pl-1/tracks?provider=spotify",
+		bytes.NewReader([]byte(`{"track_ids":[]}`)))
+	req.Header.Set("Authorization", "Bearer test-token")
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSearchTracks_Success(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?provider=youtube&name=Yesterday&artist=The+Beatles", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var candidates []domain.TrackCandidate
+	err := json.Unmarshal(w.Body.Bytes(), &candidates)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "The Beatles", candidates[0].Track.Artist())
+}
+
+func TestSearchTracks_RepeatedArtist(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?provider=spotify&name=Earfquake&artist=Tyler,+The+Creator&artist=Playboi+Carti", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var candidates []domain.TrackCandidate
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &candidates))
+	require.Len(t, candidates, 1)
+	assert.Equal(t, []string{"Tyler, The Creator", "Playboi Carti"}, candidates[0].Track.Artists)
+}
+
+func TestSearchTracks_Market(t *testing.T) {
+	svc := &mockMigrationService{}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?provider=spotify&name=Yesterday&market=gb", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "GB", svc.lastMarket)
+}
+
+func TestSearchTracks_ReleaseDate(t *testing.T) {
+	svc := &mockMigrationService{}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?provider=spotify&name=Love+Story&release_date=2008-11-11&rerecordings=any", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var candidates []domain.TrackCandidate
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &candidates))
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "2008-11-11", candidates[0].Track.ReleaseDate)
+	assert.Equal(t, domain.RerecordingsAny, svc.lastMatchOpts.Rerecordings)
+}
+
+func TestSearchTracks_Rerecordings(t *testing.T) {
+	svc := &mockMigrationService{}
+	r := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?provider=spotify&name=Love+Story&rerecordings=prefer", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, domain.RerecordingsPrefer, svc.lastMatchOpts.Rerecordings)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/search?provider=spotify&name=Love+Story&rerecordings=sometimes", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSearchTracks_MissingName(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?provider=youtube", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestMigratePlaylist_Success(t *testing.T) {
 	svc := &mockMigrationService{
 		migrationResult: &domain.MigrationResult{
@@ -151,3 +534,223 @@ func TestMigratePlaylist_InvalidBody(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestMigratePlaylist_Timeout(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: &domain.StageTimeoutError{Stage: domain.StageCreate, Timeout: 15 * time.Second}})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate",
+		bytes.NewReader([]byte(`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"pl-1"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), "create timed out after 15s")
+}
+
+func TestMigratePlaylist_ProviderErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{domain.ErrMissingToken, http.StatusBadRequest, "missing_token"},
+		{domain.ErrInvalidToken, http.StatusUnauthorized, "invalid_token"},
+		{domain.ErrInsufficientScope, http.StatusForbidden, "insufficient_scope"},
+		{domain.ErrRateLimited, http.StatusTooManyRequests, "rate_limited"},
+		{domain.ErrPlaylistNotFound, http.StatusNotFound, "playlist_not_found"},
+		{errors.New("boom"), http.StatusInternalServerError, "migration_failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			r := setupRouter(&mockMigrationService{err: fmt.Errorf("source provider error: %w", tt.err)})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate",
+				bytes.NewReader([]byte(`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"pl-1"}`)))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			var resp ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.code, resp.Error)
+		})
+	}
+}
+
+func TestPreviewMigration(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate/preview",
+		bytes.NewReader([]byte(`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"pl-1"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var preview domain.MigrationPreview
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(t, "pl-1", preview.PlaylistID)
+	assert.Equal(t, 3, preview.TotalTracks)
+}
+
+func TestPreviewMigration_InvalidToken(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: fmt.Errorf("source provider error: %w", domain.ErrInvalidToken)})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate/preview",
+		bytes.NewReader([]byte(`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"pl-1"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestMigratePlaylist_IdempotencyKey(t *testing.T) {
+	body := []byte(`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"pl-1"}`)
+
+	svc := &mockMigrationService{migrationResult: &domain.MigrationResult{ID: "m1"}}
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "key-1")
+	setupRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "key-1", svc.lastRequest.IdempotencyKey)
+
+	svc = &mockMigrationService{err: domain.ErrMigrationInProgress}
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/migrate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "key-1")
+	setupRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestMigratePlaylist_PlaylistLocked(t *testing.T) {
+	body, _ := json.Marshal(domain.MigrationRequest{SourceProvider: "spotify", DestProvider: "youtube", PlaylistID: "p1"})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	setupRouter(&mockMigrationService{err: domain.ErrPlaylistLocked}).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "migration_in_progress")
+}
+
+func TestMigratePlaylist_PlaylistExists(t *testing.T) {
+	body, _ := json.Marshal(domain.MigrationRequest{SourceProvider: "spotify", DestProvider: "youtube", PlaylistID: "p1", ConflictPolicy: domain.ConflictSkip})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	setupRouter(&mockMigrationService{err: domain.ErrPlaylistExists}).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "playlist_exists")
+}
+
+func TestMigratePlaylist_InvalidFilter(t *testing.T) {
+	svc := &mockMigrationService{err: fmt.Errorf("%w: title pattern %q: missing closing )", domain.ErrInvalidFilter, "(")}
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate",
+		bytes.NewReader([]byte(`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"p1","exclude":{"title_patterns":["("]}}`)))
+	req.Header.Set("Content-Type", "application/json")
+	setupRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_filter")
+	assert.Equal(t, &domain.TrackFilter{TitlePatterns: []string{"("}}, svc.lastRequest.Exclude)
+}
+
+func TestMigratePlaylist_InvalidConflictPolicy(t *testing.T) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate",
+		bytes.NewReader([]byte(`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"p1","conflict_policy":"merge"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	setupRouter(&mockMigrationService{}).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "conflict_policy")
+}
+
+func TestRollbackMigration_Success(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrations/abc/rollback", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result domain.MigrationResult
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", result.ID)
+	assert.True(t, result.RolledBack)
+}
+
+func TestRollbackMigration_NotFound(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: domain.ErrMigrationNotFound})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrations/missing/rollback", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRollbackMigration_AlreadyRolledBack(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: fmt.Errorf("%w: abc", domain.ErrAlreadyRolledBack)})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrations/abc/rollback", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "rolled_back")
+}
+
+func TestReverseMigration(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrations/m1/reverse",
+		bytes.NewReader([]byte(`{"source_token":"t1","dest_token":"t2"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result domain.MigrationResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, "m1", result.ReversedFrom)
+}
+
+func TestReverseMigration_NotFound(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: domain.ErrMigrationNotFound})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrations/missing/reverse", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRetryFailedTracks_NotFound(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: domain.ErrMigrationNotFound})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrations/missing/retry-failed", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
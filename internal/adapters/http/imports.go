@@ -0,0 +1,89 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// maxImportSize bounds the size of an uploaded playlist file.
+const maxImportSize = 5 << 20
+
+// WithPlaylistImporter enables the /api/v1/imports/m3u endpoint.
+func WithPlaylistImporter(importer ports.PlaylistImporter) Option {
+	return func(h *Handler) {
+		h.importer = importer
+	}
+}
+
+// ImportM3U uploads an M3U or M3U8 playlist file for use as a migration source.
+//
+//	@Summary		Import M3U playlist
+//	@Description	Parses an M3U/M3U8 playlist file, e.g. exported from VLC or foobar2000, and keeps it
+//	@Description	for the caller's account. The file is sent as the request body or as the "file" field of
+//	@Description	a multipart form, up to 5 MiB. Migrate it with source_provider "m3u" and the returned ID.
+//	@Tags			playlists
+//	@Accept			plain,mpfd
+//	@Produce		json
+//	@Param			name	query		string	false	"Playlist name, if the file has no #PLAYLIST directive"
+//	@Success		201		{object}	domain.Playlist
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		413		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/imports/m3u [post]
+func (h *Handler) ImportM3U(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	var body io.Reader = c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		file, err := c.FormFile("file")
+		if err != nil {
+			importError(c, fmt.Errorf("%w: %w", domain.ErrInvalidPlaylistFile, err))
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			importError(c, err)
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	playlist, err := h.importer.ImportPlaylist(c.Request.Context(), c.Query("name"), body)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, playlist)
+}
+
+// importError writes the response for a failed playlist upload.
+func importError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+			Error:   "file_too_large",
+			Message: "playlist file must not exceed 5 MiB",
+		})
+	case errors.Is(err, domain.ErrInvalidPlaylistFile):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// -- Mock Importer -----------------------------------------------------------
+
+type mockImporter struct {
+	name string
+	body string
+}
+
+func (m *mockImporter) ImportPlaylist(_ context.Context, name string, r io.Reader) (*domain.Playlist, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	m.name, m.body = name, string(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("m3u: %w: no entries", domain.ErrInvalidPlaylistFile)
+	}
+	return &domain.Playlist{ID: "upload-1", Name: name, TrackCount: 1}, nil
+}
+
+func setupImportRouter(importer *mockImporter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{}, WithPlaylistImporter(importer)).RegisterRoutes(r)
+	return r
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestImportM3U_RawBody(t *testing.T) {
+	importer := &mockImporter{}
+	r := setupImportRouter(importer)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/m3u?name=Mix", strings.NewReader("a.mp3\n"))
+	req.Header.Set("Content-Type", "audio/x-mpegurl")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "Mix", importer.name)
+	assert.Equal(t, "a.mp3\n", importer.body)
+
+	var playlist domain.Playlist
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &playlist))
+	assert.Equal(t, "upload-1", playlist.ID)
+}
+
+func TestImportM3U_Multipart(t *testing.T) {
+	importer := &mockImporter{}
+	r := setupImportRouter(importer)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "mix.m3u8")
+	require.NoError(t, err)
+	_, _ = part.Write([]byte("b.mp3\n"))
+	require.NoError(t, mw.Close())
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/m3u", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "b.mp3\n", importer.body)
+}
+
+func TestImportM3U_MissingFile(t *testing.T) {
+	r := setupImportRouter(&mockImporter{})
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("name", "x"))
+	require.NoError(t, mw.Close())
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/m3u", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImportM3U_InvalidFile(t *testing.T) {
+	r := setupImportRouter(&mockImporter{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/m3u", strings.NewReader(""))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid playlist file")
+}
+
+func TestImportM3U_TooLarge(t *testing.T) {
+	r := setupImportRouter(&mockImporter{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/m3u", bytes.NewReader(make([]byte, maxImportSize+1)))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestImportM3U_NotConfigured(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/m3u", strings.NewReader("a.mp3\n"))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// WithJobService enables the /api/v1/jobs endpoints for running migrations
+// in the background.
+func WithJobService(jobs ports.JobService) Option {
+	return func(h *Handler) {
+		h.jobs = jobs
+	}
+}
+
+// EnqueueMigration queues a playlist migration to run in the background.
+//
+//	@Summary		Queue migration
+//	@Description	Validates a migration request and queues it, returning immediately. Poll the job until its
+//	@Description	status is "succeeded" (its migration_id then names the stored migration) or "failed".
+//	@Description	Queued and running jobs survive restarts when a persistent storage driver is used. Tokens in
+//	@Description	the request are stored with the job, encrypted, until it finished; omit them to use tokens from
+//	@Description	the vault instead. Queuing again with the same Idempotency-Key returns the first job.
+//	@Description	Jobs queued with priority=bulk, such as those of a large batch, run after interactive ones, and
+//	@Description	accounts take turns so one account's jobs do not hold up everyone else's.
+//	@Description	With debug=true and the admin key in X-Admin-Key, the job records its provider requests for
+//	@Description	GET /admin/jobs/{id}/debug.
+//	@Tags			migration
+//	@Accept			json
+//	@Produce		json
+//	@Param			request			body		domain.MigrationRequest	true	"Migration request with source/dest providers, tokens, and playlist ID"
+//	@Param			Idempotency-Key	header		string					false	"Client-generated key; a repeated key returns the job first queued with it"
+//	@Param			priority		query		string					false	"Queue priority; bulk jobs wait for interactive ones"	Enums(interactive, bulk)
+//	@Param			debug			query		bool					false	"Record provider requests (requires X-Admin-Key)"
+//	@Param			X-Admin-Key		header		string					false	"Admin key, required for debug"
+//	@Success		202				{object}	domain.Job
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		422				{object}	ErrorResponse
+//	@Failure		429				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Failure		503				{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/jobs [post]
+func (h *Handler) EnqueueMigration(c *gin.Context) {
+	var req domain.MigrationRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	req.IdempotencyKey = c.GetHeader(idempotencyKeyHeader)
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLen {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: fmt.Sprintf("%s header must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLen),
+		})
+		return
+	}
+	req.Priority = domain.JobPriority(c.Query("priority"))
+	if !req.Priority.Valid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: "priority must be interactive or bulk",
+		})
+		return
+	}
+	if debug, _ := strconv.ParseBool(c.Query("debug")); debug {
+		if !h.isAdmin(c) {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "forbidden",
+				Message: "debug jobs require a valid " + adminKeyHeader + " header",
+			})
+			return
+		}
+		req.Debug = true
+	}
+
+	job, err := h.jobs.EnqueueMigration(c.Request.Context(), req)
+	if err != nil {
+		enqueueError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// MigrateAccount queues the migration of the user's whole account.
+//
+//	@Summary		Migrate account
+//	@Description	Queues one bulk job that copies the account of the user from the source provider to the
+//	@Description	destination provider, returning immediately. The job runs the stages library (liked songs),
+//	@Description	albums, artists and playlists in this order, with the options of the request applied to
+//	@Description	every playlist. Stages a provider does not support are skipped. Poll the job for the
+//	@Description	progress of each stage; the playlists stage lists the migration of each playlist. The job
+//	@Description	counts once against the limit of concurrent jobs. Queuing again with the same
+//	@Description	Idempotency-Key returns the job first queued with it.
+//	@Tags			migration
+//	@Accept			json
+//	@Produce		json
+//	@Param			request			body		domain.AccountMigrationRequest	true	"Source/dest providers, tokens and migration options"
+//	@Param			Idempotency-Key	header		string							false	"Client-generated key; a repeated key returns the job first queued with it"
+//	@Success		202				{object}	domain.Job
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		422				{object}	ErrorResponse
+//	@Failure		429				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Failure		503				{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/migrate/account [post]
+func (h *Handler) MigrateAccount(c *gin.Context) {
+	var req domain.AccountMigrationRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	req.IdempotencyKey = c.GetHeader(idempotencyKeyHeader)
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLen {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: fmt.Sprintf("%s header must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLen),
+		})
+		return
+	}
+
+	job, err := h.jobs.EnqueueAccountMigration(c.Request.Context(), req)
+	if err != nil {
+		enqueueError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// enqueueError writes the response for an error queuing a job.
+func enqueueError(c *gin.Context, err error) {
+	if providerError(c, err) || limitError(c, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrProviderNotFound) || errors.Is(err, domain.ErrInvalidFilter) {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "idempotency_key_reused",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "internal_error",
+		Message: err.Error(),
+	})
+}
+
+// GetJob returns the status of a queued migration.
+//
+//	@Summary		Get job
+//	@Description	Returns the status of a migration queued with POST /api/v1/jobs. While the job searches for
+//	@Description	tracks, progress reports the tracks processed, percent complete, throughput and projected
+//	@Description	completion time.
+//	@Tags			migration
+//	@Produce		json
+//	@Param			id	path		string	true	"Job ID"
+//	@Success		200	{object}	domain.Job
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/jobs/{id} [get]
+func (h *Handler) GetJob(c *gin.Context) {
+	job, err := h.jobs.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,374 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// -- Mock Job Service --------------------------------------------------------
+
+type mockJobService struct {
+	enqueued        *domain.MigrationRequest
+	enqueuedAccount *domain.AccountMigrationRequest
+	err             error
+
+	// events are streamed by WatchJob; canceled receives canceled job IDs.
+	events   []domain.JobEvent
+	canceled chan string
+}
+
+func (m *mockJobService) EnqueueMigration(_ context.Context, req domain.MigrationRequest) (*domain.Job, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.enqueued = &req
+	return &domain.Job{ID: "job-1", Status: domain.JobQueued, Request: req}, nil
+}
+
+func (m *mockJobService) EnqueueAccountMigration(_ context.Context, req domain.AccountMigrationRequest) (*domain.Job, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.enqueuedAccount = &req
+	return &domain.Job{ID: "job-1", Kind: domain.JobAccount, Status: domain.JobQueued, Priority: domain.JobBulk, Request: req.MigrationRequest()}, nil
+}
+
+func (m *mockJobService) GetJob(_ context.Context, id string) (*domain.Job, error) {
+	if id != "job-1" {
+		return nil, domain.ErrJobNotFound
+	}
+	return &domain.Job{ID: id, Status: domain.JobSucceeded, MigrationID: "m-1"}, nil
+}
+
+func (m *mockJobService) CancelJob(_ context.Context, id string) (*domain.Job, error) {
+	if id != "job-1" {
+		return nil, domain.ErrJobNotFound
+	}
+	if m.canceled == nil {
+		return nil, domain.ErrJobFinished
+	}
+	m.canceled <- id
+	return &domain.Job{ID: id, Status: domain.JobCanceled}, nil
+}
+
+func (m *mockJobService) WatchJob(ctx context.Context, id string) (<-chan domain.JobEvent, error) {
+	if id != "job-1" {
+		return nil, domain.ErrJobNotFound
+	}
+	ch := make(chan domain.JobEvent)
+	go func() {
+		defer close(ch)
+		for _, ev := range m.events {
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+		// Keep the stream open while a cancel command is expected.
+		if m.canceled != nil {
+			<-ctx.Done()
+		}
+	}()
+	return ch, nil
+}
+
+func (m *mockJobService) GetJobDebugLog(_ context.Context, id string) (*domain.DebugLog, error) {
+	switch id {
+	case "job-1":
+		return &domain.DebugLog{Exchanges: []domain.ProviderExchange{
+			{Method: http.MethodGet, URL: "https://api.spotify.com/v1/search?q=x", Status: http.StatusOK},
+		}}, nil
+	case "job-2":
+		return nil, domain.ErrNoDebugLog
+	}
+	return nil, domain.ErrJobNotFound
+}
+
+func setupJobRouter(jobs *mockJobService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{}, WithJobService(jobs)).RegisterRoutes(r)
+	return r
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestEnqueueMigration(t *testing.T) {
+	jobs := &mockJobService{}
+	r := setupJobRouter(jobs)
+
+	body := `{"source_provider":"spotify","source_token":"secret","dest_provider":"youtube","playlist_id":"p1"}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(idempotencyKeyHeader, "key-1")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	require.NotNil(t, jobs.enqueued)
+	assert.Equal(t, "p1", jobs.enqueued.PlaylistID)
+	assert.Equal(t, "key-1", jobs.enqueued.IdempotencyKey)
+	assert.NotContains(t, w.Body.String(), "secret", "tokens must not be echoed")
+
+	var job domain.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, "job-1", job.ID)
+	assert.Equal(t, domain.JobQueued, job.Status)
+}
+
+func TestEnqueueMigration_Debug(t *testing.T) {
+	jobs := &mockJobService{}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{}, WithJobService(jobs),
+		WithProviderAdmin(&mockProviderAdmin{disabled: map[string]string{}}, "secret")).RegisterRoutes(r)
+
+	body := `{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"p1"}`
+	enqueue := func(key string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs?debug=true", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-Admin-Key", key)
+		}
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, enqueue(""))
+	assert.Equal(t, http.StatusForbidden, enqueue("wrong"))
+	assert.Nil(t, jobs.enqueued)
+
+	assert.Equal(t, http.StatusAccepted, enqueue("secret"))
+	require.NotNil(t, jobs.enqueued)
+	assert.True(t, jobs.enqueued.Debug)
+}
+
+func TestEnqueueMigration_Priority(t *testing.T) {
+	jobs := &mockJobService{}
+	r := setupJobRouter(jobs)
+
+	body := `{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"p1"}`
+	enqueue := func(priority string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs?priority="+priority, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, enqueue("urgent"))
+	assert.Nil(t, jobs.enqueued)
+
+	assert.Equal(t, http.StatusAccepted, enqueue("bulk"))
+	require.NotNil(t, jobs.enqueued)
+	assert.Equal(t, domain.JobBulk, jobs.enqueued.Priority)
+}
+
+func TestMigrateAccount(t *testing.T) {
+	jobs := &mockJobService{}
+	r := setupJobRouter(jobs)
+
+	body := `{"source_provider":"spotify","source_token":"secret","dest_provider":"youtube","dry_run":true,"classical":true,"name_pattern":"{playlist} (copy)"}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate/account", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(idempotencyKeyHeader, "key-1")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	require.NotNil(t, jobs.enqueuedAccount)
+	assert.Equal(t, "spotify", jobs.enqueuedAccount.SourceProvider)
+	assert.True(t, jobs.enqueuedAccount.DryRun)
+	assert.True(t, jobs.enqueuedAccount.Classical)
+	assert.Equal(t, "{playlist} (copy)", jobs.enqueuedAccount.NamePattern)
+	assert.Equal(t, "key-1", jobs.enqueuedAccount.IdempotencyKey)
+	assert.NotContains(t, w.Body.String(), "secret", "tokens must not be echoed")
+
+	var job domain.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, "job-1", job.ID)
+	assert.Equal(t, domain.JobAccount, job.Kind)
+}
+
+func TestMigrateAccount_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"missing provider", `{"source_provider":"spotify"}`, nil, http.StatusBadRequest},
+		{"unknown provider", `{"source_provider":"spotify","dest_provider":"tidal"}`, fmt.Errorf("destination provider error: %w", domain.ErrProviderNotFound), http.StatusUnprocessableEntity},
+		{"job limit", `{"source_provider":"spotify","dest_provider":"youtube"}`, &domain.LimitError{Limit: domain.LimitConcurrentJobs, Max: 2}, http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupJobRouter(&mockJobService{err: tt.err})
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate/account", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestGetJobDebugLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{}, WithJobService(&mockJobService{}),
+		WithProviderAdmin(&mockProviderAdmin{disabled: map[string]string{}}, "secret")).RegisterRoutes(r)
+
+	get := func(id, key string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/admin/jobs/"+id+"/debug", nil)
+		req.Header.Set("X-Admin-Key", key)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, get("job-1", "wrong").Code)
+
+	w := get("job-1", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var log domain.DebugLog
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &log))
+	require.Len(t, log.Exchanges, 1)
+	assert.Equal(t, http.StatusOK, log.Exchanges[0].Status)
+
+	assert.Equal(t, http.StatusNotFound, get("job-2", "secret").Code)
+	assert.Equal(t, http.StatusNotFound, get("other", "secret").Code)
+}
+
+func TestEnqueueMigration_Errors(t *testing.T) {
+	body := `{"source_provider":"spotify","dest_provider":"m3u","playlist_id":"p1"}`
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"source only", fmt.Errorf("destination provider error: %w", domain.ErrSourceOnlyProvider), http.StatusUnprocessableEntity},
+		{"unknown provider", fmt.Errorf("destination provider error: %w", domain.ErrProviderNotFound), http.StatusUnprocessableEntity},
+		{"idempotency key reused", domain.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity},
+		{"queue failure", fmt.Errorf("failed to queue migration: disk full"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupJobRouter(&mockJobService{err: tt.err})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	r := setupJobRouter(&mockJobService{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/job-1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var job domain.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, "m-1", job.MigrationID)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/other", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestJobRoutes_DisabledWithoutService(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/job-1", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func dialJob(t *testing.T, server *httptest.Server, id string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/migrations/" + id
+	ws, err := websocket.Dial(url, "", server.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func TestWatchJob_StreamsEvents(t *testing.T) {
+	jobs := &mockJobService{events: []domain.JobEvent{
+		{Type: domain.JobEventStatus, Job: &domain.Job{ID: "job-1", Status: domain.JobRunning}},
+		{Type: domain.JobEventTrack, Progress: &domain.JobProgress{Processed: 1, Total: 2, Matched: 1}},
+		{Type: domain.JobEventStatus, Job: &domain.Job{ID: "job-1", Status: domain.JobSucceeded}},
+	}}
+	server := httptest.NewServer(setupJobRouter(jobs))
+	defer server.Close()
+
+	ws := dialJob(t, server, "job-1")
+	var got []domain.JobEvent
+	for {
+		var ev domain.JobEvent
+		if err := websocket.JSON.Receive(ws, &ev); err != nil {
+			break
+		}
+		got = append(got, ev)
+	}
+
+	require.Len(t, got, 3, "the socket closes after the last event")
+	assert.Equal(t, domain.JobRunning, got[0].Job.Status)
+	assert.Equal(t, 2, got[1].Progress.Total)
+	assert.Equal(t, domain.JobSucceeded, got[2].Job.Status)
+}
+
+func TestWatchJob_Cancel(t *testing.T) {
+	jobs := &mockJobService{
+		events:   []domain.JobEvent{{Type: domain.JobEventStatus, Job: &domain.Job{ID: "job-1", Status: domain.JobRunning}}},
+		canceled: make(chan string, 1),
+	}
+	server := httptest.NewServer(setupJobRouter(jobs))
+	defer server.Close()
+
+	ws := dialJob(t, server, "job-1")
+	var ev domain.JobEvent
+	require.NoError(t, websocket.JSON.Receive(ws, &ev))
+
+	require.NoError(t, websocket.JSON.Send(ws, jobCommand{Type: "cancel"}))
+	select {
+	case id := <-jobs.canceled:
+		assert.Equal(t, "job-1", id)
+	case <-time.After(time.Second):
+		t.Fatal("job was not canceled")
+	}
+
+	require.NoError(t, websocket.JSON.Send(ws, jobCommand{Type: "pause"}))
+	require.NoError(t, websocket.JSON.Receive(ws, &ev))
+	assert.Equal(t, domain.JobEventError, ev.Type)
+	assert.Contains(t, ev.Error, "pause")
+}
+
+func TestWatchJob_NotFound(t *testing.T) {
+	r := setupJobRouter(&mockJobService{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ws/migrations/other", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// WithLimitService enables the /admin/accounts/{id}/limits endpoints for
+// managing account limits. It requires WithProviderAdmin for the admin key.
+func WithLimitService(limits ports.LimitService) Option {
+	return func(h *Handler) {
+		h.limits = limits
+	}
+}
+
+// GetAccountLimits returns the limits that apply to an account.
+//
+//	@Summary		Get account limits
+//	@Description	Returns the limits that apply to an account: those set for it, or the configured defaults.
+//	@Description	Zero means unlimited.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string	true	"Account ID"
+//	@Success		200	{object}	domain.AccountLimits
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/accounts/{id}/limits [get]
+func (h *Handler) GetAccountLimits(c *gin.Context) {
+	limits, err := h.limits.GetLimits(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		accountLimitsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, limits)
+}
+
+// SetAccountLimits gives an account its own limits.
+//
+//	@Summary		Set account limits
+//	@Description	Replaces the limits of an account; limits left out of the body are unlimited. They apply
+//	@Description	from the account's next request, to migrations and jobs already counted too.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Account ID"
+//	@Param			limits	body		domain.AccountLimits	true	"Limits of the account"
+//	@Success		200		{object}	domain.AccountLimits
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/accounts/{id}/limits [put]
+func (h *Handler) SetAccountLimits(c *gin.Context) {
+	var limits domain.AccountLimits
+	if !h.bindJSON(c, &limits) {
+		return
+	}
+
+	updated, err := h.limits.SetLimits(c.Request.Context(), c.Param("id"), &limits)
+	if err != nil {
+		accountLimitsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// ResetAccountLimits makes the default limits apply to an account again.
+//
+//	@Summary		Reset account limits
+//	@Description	Removes the limits set for an account, so the configured defaults apply again, and returns them.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string	true	"Account ID"
+//	@Success		200	{object}	domain.AccountLimits
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		AdminKeyAuth
+//	@Router			/admin/accounts/{id}/limits [delete]
+func (h *Handler) ResetAccountLimits(c *gin.Context) {
+	limits, err := h.limits.SetLimits(c.Request.Context(), c.Param("id"), nil)
+	if err != nil {
+		accountLimitsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, limits)
+}
+
+// accountLimitsError responds with the status matching an error of the limit
+// service.
+func accountLimitsError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "internal_error",
+		Message: err.Error(),
+	})
+}
+
+// limitError writes the response and returns true if err reports an
+// exceeded account limit: 429 for limits that free up over time, with
+// Retry-After and resets_at when the reset time is known, and 403 for a
+// playlist too large for the account.
+func limitError(c *gin.Context, err error) bool {
+	var limit *domain.LimitError
+	if !errors.As(err, &limit) {
+		return false
+	}
+	if limit.Limit == domain.LimitMaxTracksPerMigration {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "limit_exceeded",
+			Message: err.Error(),
+		})
+		return true
+	}
+
+	resp := ErrorResponse{
+		Error:   "limit_exceeded",
+		Message: err.Error(),
+	}
+	if !limit.ResetsAt.IsZero() {
+		resp.ResetsAt = &limit.ResetsAt
+		wait := time.Until(limit.ResetsAt)
+		c.Header("Retry-After", strconv.Itoa(max(int(math.Ceil(wait.Seconds())), 0)))
+	}
+	c.JSON(http.StatusTooManyRequests, resp)
+	return true
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// -- Mock LimitService -------------------------------------------------------
+
+type mockLimitService struct {
+	defaults domain.AccountLimits
+	limits   map[string]*domain.AccountLimits
+}
+
+func (m *mockLimitService) GetLimits(_ context.Context, accountID string) (*domain.AccountLimits, error) {
+	limits, ok := m.limits[accountID]
+	if !ok {
+		return nil, domain.ErrAccountNotFound
+	}
+	if limits == nil {
+		return &m.defaults, nil
+	}
+	return limits, nil
+}
+
+func (m *mockLimitService) SetLimits(ctx context.Context, accountID string, limits *domain.AccountLimits) (*domain.AccountLimits, error) {
+	if _, ok := m.limits[accountID]; !ok {
+		return nil, domain.ErrAccountNotFound
+	}
+	m.limits[accountID] = limits
+	return m.GetLimits(ctx, accountID)
+}
+
+func setupLimitsRouter(limits *mockLimitService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{},
+		WithProviderAdmin(&mockProviderAdmin{disabled: map[string]string{}}, "secret"),
+		WithLimitService(limits)).RegisterRoutes(r)
+	return r
+}
+
+func adminRequest(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Admin-Key", "secret")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestAccountLimits(t *testing.T) {
+	limits := &mockLimitService{
+		defaults: domain.AccountLimits{MigrationsPerDay: 10},
+		limits:   map[string]*domain.AccountLimits{"acc": nil},
+	}
+	r := setupLimitsRouter(limits)
+
+	w := adminRequest(r, http.MethodGet, "/admin/accounts/acc/limits", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"migrations_per_day":10,"max_tracks_per_migration":0,"concurrent_jobs":0}`, w.Body.String())
+
+	w = adminRequest(r, http.MethodPut, "/admin/accounts/acc/limits", `{"migrations_per_day":3,"concurrent_jobs":1}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, &domain.AccountLimits{MigrationsPerDay: 3, ConcurrentJobs: 1}, limits.limits["acc"])
+
+	w = adminRequest(r, http.MethodDelete, "/admin/accounts/acc/limits", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, limits.limits["acc"])
+	assert.JSONEq(t, `{"migrations_per_day":10,"max_tracks_per_migration":0,"concurrent_jobs":0}`, w.Body.String())
+}
+
+func TestAccountLimits_Errors(t *testing.T) {
+	r := setupLimitsRouter(&mockLimitService{limits: map[string]*domain.AccountLimits{"acc": nil}})
+
+	w := adminRequest(r, http.MethodGet, "/admin/accounts/missing/limits", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = adminRequest(r, http.MethodPut, "/admin/accounts/acc/limits", `{"migrations_per_day":-1}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/accounts/acc/limits", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestMigratePlaylist_LimitExceeded(t *testing.T) {
+	resets := time.Now().Add(90 * time.Minute).UTC().Truncate(time.Second)
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantResets bool
+	}{
+		{"daily limit", &domain.LimitError{Limit: domain.LimitMigrationsPerDay, Max: 5, ResetsAt: resets}, http.StatusTooManyRequests, true},
+		{"concurrent jobs", &domain.LimitError{Limit: domain.LimitConcurrentJobs, Max: 1}, http.StatusTooManyRequests, false},
+		{"playlist too large", &domain.LimitError{Limit: domain.LimitMaxTracksPerMigration, Max: 100}, http.StatusForbidden, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupRouter(&mockMigrationService{err: tt.err})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate",
+				bytes.NewReader([]byte(`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"pl-1"}`)))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			var resp ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, "limit_exceeded", resp.Error)
+			if !tt.wantResets {
+				assert.Nil(t, resp.ResetsAt)
+				assert.Empty(t, w.Header().Get("Retry-After"))
+				return
+			}
+			require.NotNil(t, resp.ResetsAt)
+			assert.True(t, resets.Equal(*resp.ResetsAt))
+			retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+			require.NoError(t, err)
+			assert.InDelta(t, 90*60, retryAfter, 5)
+		})
+	}
+}
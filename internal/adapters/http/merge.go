@@ -0,0 +1,43 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// MergePlaylists merges several playlists into one destination playlist.
+//
+//	@Summary		Merge playlists
+//	@Description	Fetches two or more source playlists, possibly of different providers, and migrates their
+//	@Description	tracks into one new destination playlist. Tracks found in several sources are added once:
+//	@Description	those sharing an ID or ISRC are merged before matching, those matched to the same destination
+//	@Description	track after it. The tracks are ordered by source, interleaved or by release date.
+//	@Tags			migration
+//	@Accept			json
+//	@Produce		json,application/x-ndjson
+//	@Param			request	body		domain.MergeRequest	true	"Source playlists, destination and ordering"
+//	@Success		200		{object}	domain.MigrationResult
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		422		{object}	ErrorResponse
+//	@Failure		429		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Failure		503		{object}	ErrorResponse
+//	@Failure		504		{object}	ErrorResponse
+//	@Router			/api/v1/merge [post]
+func (h *Handler) MergePlaylists(c *gin.Context) {
+	var req domain.MergeRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.service.MergePlaylists(c.Request.Context(), req)
+	if err != nil {
+		migrationFailed(c, err)
+		return
+	}
+
+	writeMigrationResult(c, result)
+}
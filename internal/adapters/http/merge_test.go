@@ -0,0 +1,68 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postMerge(h *Handler, body string) *httptest.ResponseRecorder {
+	r := gin.New()
+	h.RegisterRoutes(r)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/merge", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMergePlaylists(t *testing.T) {
+	svc := &mockMigrationService{migrationResult: &domain.MigrationResult{ID: "m1", DestPlaylistID: "merged"}}
+	w := postMerge(NewHandler(svc), `{
+		"sources": [{"provider": "spotify", "playlist_id": "p1"}, {"provider": "youtube", "playlist_id": "p2"}],
+		"source_tokens": {"spotify": "t1", "youtube": "t2"},
+		"dest_provider": "spotify",
+		"order": "interleave"
+	}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result domain.MigrationResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, "merged", result.DestPlaylistID)
+	assert.Len(t, svc.lastMerge.Sources, 2)
+	assert.Equal(t, "t2", svc.lastMerge.SourceTokens["youtube"])
+	assert.Equal(t, domain.MergeInterleave, svc.lastMerge.Order)
+}
+
+func TestMergePlaylists_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		code  int
+		field string
+	}{
+		{"one source", `{"sources":[{"provider":"spotify","playlist_id":"p1"}],"dest_provider":"youtube"}`, http.StatusBadRequest, "sources"},
+		{"unknown order", `{"sources":[{"provider":"spotify","playlist_id":"p1"},{"provider":"spotify","playlist_id":"p2"}],"dest_provider":"youtube","order":"shuffle"}`, http.StatusBadRequest, "order"},
+		{"unknown provider", `{"sources":[{"provider":"spotify","playlist_id":"p1"},{"provider":"tidal","playlist_id":"p2"}],"dest_provider":"youtube"}`, http.StatusUnprocessableEntity, "sources[1].provider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockMigrationService{}
+			w := postMerge(NewHandler(svc, WithProviders([]string{"spotify", "youtube"})), tt.body)
+
+			assert.Equal(t, tt.code, w.Code)
+			var resp ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.NotEmpty(t, resp.Fields)
+			assert.Equal(t, tt.field, resp.Fields[0].Field)
+			assert.Empty(t, svc.lastMerge.Sources, "invalid requests do not reach the service")
+		})
+	}
+}
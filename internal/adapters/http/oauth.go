@@ -0,0 +1,117 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// WithOAuthService enables the /api/v1/oauth endpoints that link provider
+// accounts through the authorization-code flow.
+func WithOAuthService(oauth ports.OAuthService) Option {
+	return func(h *Handler) {
+		h.oauth = oauth
+	}
+}
+
+// AuthorizationResponse holds the consent page a user opens to link a
+// provider.
+type AuthorizationResponse struct {
+	URL string `json:"url"`
+}
+
+// AuthorizeProvider starts linking a provider to the caller's account.
+//
+//	@Summary		Start linking a provider
+//	@Description	Returns the provider's consent page for the authenticated account. Once the user grants
+//	@Description	access, the provider redirects to the configured redirect URL, which must point at
+//	@Description	/api/v1/oauth/{provider}/callback, and the issued token is stored in the vault. The link
+//	@Description	must be opened within ten minutes.
+//	@Tags			tokens
+//	@Produce		json
+//	@Param			provider	path		string	true	"Streaming provider"	Enums(spotify, youtube)
+//	@Success		200			{object}	AuthorizationResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/oauth/{provider}/authorize [get]
+func (h *Handler) AuthorizeProvider(c *gin.Context) {
+	authURL, err := h.oauth.AuthorizeURL(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		h.oauthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AuthorizationResponse{URL: authURL})
+}
+
+// OAuthCallback completes linking a provider.
+//
+//	@Summary		Complete linking a provider
+//	@Description	Redirect target of the provider's consent page. The state names the account the link
+//	@Description	was started for, so no API key is needed. The authorization code is exchanged for a token,
+//	@Description	which is stored in the vault.
+//	@Tags			tokens
+//	@Produce		json
+//	@Param			provider	path	string	true	"Streaming provider"	Enums(spotify, youtube)
+//	@Param			state		query	string	true	"State issued by the authorize endpoint"
+//	@Param			code		query	string	false	"Authorization code"
+//	@Param			error		query	string	false	"Reason the user did not grant access"
+//	@Success		204
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/oauth/{provider}/callback [get]
+func (h *Handler) OAuthCallback(c *gin.Context) {
+	if reason := c.Query("error"); reason != "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "authorization_failed",
+			Message: "access was not granted: " + reason,
+		})
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: "query parameter 'code' is required",
+		})
+		return
+	}
+
+	if err := h.oauth.CompleteAuthorization(c.Request.Context(), c.Param("provider"), c.Query("state"), code); err != nil {
+		h.oauthError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// oauthError writes the response for an error of the OAuth service.
+func (h *Handler) oauthError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAuthorizationState):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_state",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrAuthorizationFailed):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "authorization_failed",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrAuthorizationUnavailable), errors.Is(err, domain.ErrProviderNotFound):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// -- Mock OAuth service ------------------------------------------------------
+
+type mockOAuthService struct {
+	err       error
+	completed []string
+}
+
+func (m *mockOAuthService) AuthorizeURL(_ context.Context, provider string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	return "https://accounts.example.com/authorize?provider=" + provider, nil
+}
+
+func (m *mockOAuthService) CompleteAuthorization(_ context.Context, provider, state, code string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.completed = append(m.completed, provider+"/"+state+"/"+code)
+	return nil
+}
+
+func setupOAuthRouter(oauth *mockOAuthService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{}, WithOAuthService(oauth)).RegisterRoutes(r)
+	return r
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestAuthorizeProvider(t *testing.T) {
+	r := setupOAuthRouter(&mockOAuthService{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/oauth/spotify/authorize", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp AuthorizationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "https://accounts.example.com/authorize?provider=spotify", resp.URL)
+}
+
+func TestOAuthCallback(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   error
+		code  int
+	}{
+		{"linked", "?state=s1&code=c1", nil, http.StatusNoContent},
+		{"denied", "?state=s1&error=access_denied", nil, http.StatusBadRequest},
+		{"no code", "?state=s1", nil, http.StatusBadRequest},
+		{"invalid state", "?state=forged&code=c1", domain.ErrInvalidAuthorizationState, http.StatusBadRequest},
+		{"rejected code", "?state=s1&code=used", fmt.Errorf("%w: status 400", domain.ErrAuthorizationFailed), http.StatusBadRequest},
+		{"vault failure", "?state=s1&code=c1", fmt.Errorf("failed to store token"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oauth := &mockOAuthService{err: tt.err}
+			r := setupOAuthRouter(oauth)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/oauth/spotify/callback"+tt.query, nil))
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.code == http.StatusNoContent {
+				assert.Equal(t, []string{"spotify/s1/c1"}, oauth.completed)
+			}
+		})
+	}
+}
+
+func TestOAuthCallback_NoAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	oauth := &mockOAuthService{}
+	NewHandler(&mockMigrationService{},
+		WithAccountService(&mockAccountService{validKey: "mm_valid"}),
+		WithOAuthService(oauth),
+	).RegisterRoutes(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/oauth/spotify/authorize", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/oauth/spotify/callback?state=s1&code=c1", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"spotify/s1/c1"}, oauth.completed)
+}
@@ -0,0 +1,222 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// WithProfileService enables the /api/v1/profiles endpoints for saved
+// migration profiles.
+func WithProfileService(profiles ports.ProfileService) Option {
+	return func(h *Handler) {
+		h.profiles = profiles
+	}
+}
+
+// CreateProfile saves a migration profile.
+//
+//	@Summary		Create migration profile
+//	@Description	Saves the providers and options of a migration under a name, so playlists can be migrated
+//	@Description	with POST /api/v1/profiles/{id}/migrate by playlist ID alone. id, account_id, created_at and
+//	@Description	updated_at are set by the server.
+//	@Tags			profiles
+//	@Accept			json
+//	@Produce		json
+//	@Param			profile	body		domain.MigrationProfile	true	"Profile settings"
+//	@Success		201		{object}	domain.MigrationProfile
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		422		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/profiles [post]
+func (h *Handler) CreateProfile(c *gin.Context) {
+	var profile domain.MigrationProfile
+	if !h.bindJSON(c, &profile) {
+		return
+	}
+
+	created, err := h.profiles.CreateProfile(c.Request.Context(), profile)
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListProfiles returns the caller's migration profiles.
+//
+//	@Summary		List migration profiles
+//	@Description	Returns the migration profiles of the authenticated account, oldest first.
+//	@Tags			profiles
+//	@Produce		json
+//	@Success		200	{array}		domain.MigrationProfile
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/profiles [get]
+func (h *Handler) ListProfiles(c *gin.Context) {
+	profiles, err := h.profiles.ListProfiles(c.Request.Context())
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profiles)
+}
+
+// GetProfile returns a single migration profile.
+//
+//	@Summary		Get migration profile
+//	@Tags			profiles
+//	@Produce		json
+//	@Param			id	path		string	true	"Profile ID"
+//	@Success		200	{object}	domain.MigrationProfile
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/profiles/{id} [get]
+func (h *Handler) GetProfile(c *gin.Context) {
+	profile, err := h.profiles.GetProfile(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateProfile replaces the settings of a migration profile.
+//
+//	@Summary		Update migration profile
+//	@Description	Replaces every setting of the profile; settings left out of the body are reset to their defaults.
+//	@Tags			profiles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Profile ID"
+//	@Param			profile	body		domain.MigrationProfile	true	"Profile settings"
+//	@Success		200		{object}	domain.MigrationProfile
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		422		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/profiles/{id} [put]
+func (h *Handler) UpdateProfile(c *gin.Context) {
+	var profile domain.MigrationProfile
+	if !h.bindJSON(c, &profile) {
+		return
+	}
+
+	updated, err := h.profiles.UpdateProfile(c.Request.Context(), c.Param("id"), profile)
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteProfile deletes a migration profile.
+//
+//	@Summary		Delete migration profile
+//	@Description	Deletes the profile. Migrations run with it are kept.
+//	@Tags			profiles
+//	@Produce		json
+//	@Param			id	path	string	true	"Profile ID"
+//	@Success		204
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/profiles/{id} [delete]
+func (h *Handler) DeleteProfile(c *gin.Context) {
+	if err := h.profiles.DeleteProfile(c.Request.Context(), c.Param("id")); err != nil {
+		profileError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MigrateWithProfile migrates a playlist with the settings of a profile.
+//
+//	@Summary		Migrate playlist with profile
+//	@Description	Migrates a playlist like POST /api/v1/migrate, taking the providers and options from the
+//	@Description	profile. Only the playlist ID, the tokens (unless they are in the vault) and dry_run are sent.
+//	@Tags			profiles
+//	@Accept			json
+//	@Produce		json,application/x-ndjson
+//	@Param			id				path		string							true	"Profile ID"
+//	@Param			request			body		domain.ProfileMigrationRequest	true	"Playlist ID and tokens"
+//	@Param			Idempotency-Key	header		string							false	"Client-generated key; repeated requests with the same key return the original migration"
+//	@Success		200				{object}	domain.MigrationResult
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		409				{object}	ErrorResponse
+//	@Failure		422				{object}	ErrorResponse
+//	@Failure		429				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Failure		503				{object}	ErrorResponse
+//	@Failure		504				{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/profiles/{id}/migrate [post]
+func (h *Handler) MigrateWithProfile(c *gin.Context) {
+	var req domain.ProfileMigrationRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	req.IdempotencyKey = c.GetHeader(idempotencyKeyHeader)
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLen {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: fmt.Sprintf("%s header must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLen),
+		})
+		return
+	}
+
+	result, err := h.profiles.MigrateWithProfile(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrProfileNotFound) {
+			profileError(c, err)
+			return
+		}
+		migrationFailed(c, err)
+		return
+	}
+
+	writeMigrationResult(c, result)
+}
+
+// profileError responds with the status matching an error of the profile
+// service.
+func profileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProfileNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidProfile):
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation_failed",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+}
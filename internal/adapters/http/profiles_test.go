@@ -0,0 +1,170 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// -- Mock Profile Service ----------------------------------------------------
+
+type mockProfileService struct {
+	profiles map[string]domain.MigrationProfile
+	migrated *domain.ProfileMigrationRequest
+	err      error
+}
+
+func (m *mockProfileService) CreateProfile(_ context.Context, profile domain.MigrationProfile) (*domain.MigrationProfile, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	profile.ID = "prof-1"
+	m.profiles[profile.ID] = profile
+	return &profile, nil
+}
+
+func (m *mockProfileService) GetProfile(_ context.Context, id string) (*domain.MigrationProfile, error) {
+	profile, ok := m.profiles[id]
+	if !ok {
+		return nil, domain.ErrProfileNotFound
+	}
+	return &profile, nil
+}
+
+func (m *mockProfileService) ListProfiles(context.Context) ([]domain.MigrationProfile, error) {
+	profiles := make([]domain.MigrationProfile, 0, len(m.profiles))
+	for _, profile := range m.profiles {
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func (m *mockProfileService) UpdateProfile(ctx context.Context, id string, profile domain.MigrationProfile) (*domain.MigrationProfile, error) {
+	if _, err := m.GetProfile(ctx, id); err != nil {
+		return nil, err
+	}
+	profile.ID = id
+	m.profiles[id] = profile
+	return &profile, nil
+}
+
+func (m *mockProfileService) DeleteProfile(ctx context.Context, id string) error {
+	if _, err := m.GetProfile(ctx, id); err != nil {
+		return err
+	}
+	delete(m.profiles, id)
+	return nil
+}
+
+func (m *mockProfileService) MigrateWithProfile(ctx context.Context, id string, req domain.ProfileMigrationRequest) (*domain.MigrationResult, error) {
+	if _, err := m.GetProfile(ctx, id); err != nil {
+		return nil, err
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.migrated = &req
+	return &domain.MigrationResult{ID: "m-1", SourcePlaylist: req.PlaylistID}, nil
+}
+
+func setupProfileRouter(profiles *mockProfileService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{}, WithProfileService(profiles), WithProviders([]string{"spotify", "youtube"})).RegisterRoutes(r)
+	return r
+}
+
+func doProfileRequest(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestProfiles_CRUD(t *testing.T) {
+	profiles := &mockProfileService{profiles: map[string]domain.MigrationProfile{}}
+	r := setupProfileRouter(profiles)
+
+	w := doProfileRequest(r, http.MethodPost, "/api/v1/profiles",
+		`{"name":"weekly","source_provider":"spotify","dest_provider":"youtube","min_score":0.8,"dedupe":true}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created domain.MigrationProfile
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "prof-1", created.ID)
+	assert.Equal(t, 0.8, created.MinScore)
+	assert.True(t, created.Dedupe)
+
+	w = doProfileRequest(r, http.MethodGet, "/api/v1/profiles", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"weekly"`)
+
+	w = doProfileRequest(r, http.MethodPut, "/api/v1/profiles/prof-1",
+		`{"name":"daily","source_provider":"spotify","dest_provider":"youtube"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "daily", profiles.profiles["prof-1"].Name)
+
+	w = doProfileRequest(r, http.MethodGet, "/api/v1/profiles/prof-1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"daily"`)
+
+	w = doProfileRequest(r, http.MethodDelete, "/api/v1/profiles/prof-1", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = doProfileRequest(r, http.MethodGet, "/api/v1/profiles/prof-1", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreateProfile_Validation(t *testing.T) {
+	r := setupProfileRouter(&mockProfileService{profiles: map[string]domain.MigrationProfile{}})
+
+	w := doProfileRequest(r, http.MethodPost, "/api/v1/profiles", `{"name":"x","source_provider":"spotify","dest_provider":"tidal"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown_provider")
+
+	w = doProfileRequest(r, http.MethodPost, "/api/v1/profiles", `{"name":"x","source_provider":"spotify","dest_provider":"youtube","min_score":2}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	r = setupProfileRouter(&mockProfileService{profiles: map[string]domain.MigrationProfile{}, err: domain.ErrInvalidProfile})
+	w = doProfileRequest(r, http.MethodPost, "/api/v1/profiles", `{"name":" ","source_provider":"spotify","dest_provider":"youtube"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestMigrateWithProfile(t *testing.T) {
+	profiles := &mockProfileService{profiles: map[string]domain.MigrationProfile{"prof-1": {ID: "prof-1"}}}
+	r := setupProfileRouter(profiles)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/profiles/prof-1/migrate", strings.NewReader(`{"playlist_id":"pl-1","dry_run":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(idempotencyKeyHeader, "key-1")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, profiles.migrated)
+	assert.Equal(t, "pl-1", profiles.migrated.PlaylistID)
+	assert.True(t, profiles.migrated.DryRun)
+	assert.Equal(t, "key-1", profiles.migrated.IdempotencyKey)
+	assert.Contains(t, w.Body.String(), `"m-1"`)
+
+	w = doProfileRequest(r, http.MethodPost, "/api/v1/profiles/missing/migrate", `{"playlist_id":"pl-1"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = doProfileRequest(r, http.MethodPost, "/api/v1/profiles/prof-1/migrate", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	profiles.err = domain.ErrPlaylistExists
+	w = doProfileRequest(r, http.MethodPost, "/api/v1/profiles/prof-1/migrate", `{"playlist_id":"pl-1"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "playlist_exists")
+}
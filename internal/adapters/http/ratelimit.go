@@ -0,0 +1,153 @@
+package http
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// RateLimiter is a token bucket rate limiter keyed by client. Each client may
+// burst up to `burst` requests and then refills at `rate` requests per second.
+// It is safe for concurrent use.
+type RateLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// sweepInterval controls how often idle buckets are evicted.
+const sweepInterval = time.Minute
+
+// NewRateLimiter creates a limiter allowing `rate` requests per second per
+// client with bursts of up to `burst` requests.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow consumes a token for key. When the bucket is empty it returns false
+// and how long the client should wait before the next token is available.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.refill(key)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, l.wait(b)
+}
+
+// Limited reports whether the bucket of key is empty, and how long until
+// the next token is available, without consuming a token.
+func (l *RateLimiter) Limited(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.refill(key)
+	if b.tokens >= 1 {
+		return false, 0
+	}
+	return true, l.wait(b)
+}
+
+// refill returns the bucket of key, created full if missing, with the
+// tokens added since it was last used. l.mu must be held.
+func (l *RateLimiter) refill(key string) *bucket {
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	return b
+}
+
+// wait returns how long b takes to refill to one token.
+func (l *RateLimiter) wait(b *bucket) time.Duration {
+	return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops buckets that have been idle long enough to refill completely,
+// since they are indistinguishable from a fresh bucket.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RateLimit is a middleware that rejects requests exceeding the limiter's
+// budget with 429 Too Many Requests and a Retry-After header. It runs after
+// RequireAPIKey: authenticated requests are keyed by account, others by
+// client IP.
+func (h *Handler) RateLimit(c *gin.Context) {
+	key := "ip:" + c.ClientIP()
+	if account, ok := domain.AccountFromContext(c.Request.Context()); ok {
+		key = "account:" + account.ID
+	}
+
+	if allowed, wait := h.limiter.Allow(key); !allowed {
+		rateLimited(c, wait)
+		return
+	}
+	c.Next()
+}
+
+// LimitAuthFailures is a middleware that runs before RequireAPIKey and
+// charges each request that fails to authenticate to the bucket of its
+// client IP. Once the bucket is empty, requests from the IP are rejected
+// before their key is looked up, whatever key they carry.
+func (h *Handler) LimitAuthFailures(c *gin.Context) {
+	key := "ip:" + c.ClientIP()
+	if limited, wait := h.limiter.Limited(key); limited {
+		rateLimited(c, wait)
+		return
+	}
+
+	c.Next()
+	if _, ok := domain.AccountFromContext(c.Request.Context()); !ok {
+		h.limiter.Allow(key)
+	}
+}
+
+// rateLimited rejects a request that has to wait before it is allowed.
+func rateLimited(c *gin.Context, wait time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrorResponse{
+		Error:   "rate_limited",
+		Message: "too many requests, retry after " + wait.Round(time.Second).String(),
+	})
+}
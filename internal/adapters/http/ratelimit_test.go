@@ -0,0 +1,108 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_BurstAndRefill(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := NewRateLimiter(1, 2)
+	limiter.now = func() time.Time { return now }
+
+	ok, _ := limiter.Allow("client")
+	assert.True(t, ok)
+	ok, _ = limiter.Allow("client")
+	assert.True(t, ok)
+
+	ok, wait := limiter.Allow("client")
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, wait)
+
+	// Other clients have their own bucket.
+	ok, _ = limiter.Allow("other")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	ok, _ = limiter.Allow("client")
+	assert.True(t, ok)
+}
+
+func TestRateLimit_Returns429WithRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{}, WithRateLimiter(NewRateLimiter(0.5, 1))).RegisterRoutes(r)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/migrations", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/migrations", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+
+	// The health endpoint is not rate limited.
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/health", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimit_PerAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(&mockMigrationService{},
+		WithAccountService(&mockAccountService{validKey: "mm_valid"}),
+		WithRateLimiter(NewRateLimiter(0.5, 1)),
+	).RegisterRoutes(r)
+
+	get := func(apiKey, ip string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/migrations", nil)
+		req.Header.Set(apiKeyHeader, apiKey)
+		req.RemoteAddr = ip + ":1234"
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, get("mm_valid", "192.0.2.1"))
+	assert.Equal(t, http.StatusTooManyRequests, get("mm_valid", "192.0.2.2"), "the account's bucket is shared across IPs")
+}
+
+func TestRateLimit_AuthFailuresShareIPBucket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	accounts := &mockAccountService{validKey: "mm_valid"}
+	NewHandler(&mockMigrationService{},
+		WithAccountService(accounts),
+		WithRateLimiter(NewRateLimiter(0.5, 2)),
+	).RegisterRoutes(r)
+
+	get := func(apiKey, ip string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/migrations", nil)
+		req.Header.Set(apiKeyHeader, apiKey)
+		req.RemoteAddr = ip + ":1234"
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, get("mm_guess-1", "192.0.2.1"))
+	assert.Equal(t, http.StatusUnauthorized, get("mm_guess-2", "192.0.2.1"))
+	lookups := accounts.lookups
+	for i := range 5 {
+		assert.Equal(t, http.StatusTooManyRequests, get(fmt.Sprintf("mm_random-%d", i), "192.0.2.1"), "random keys share the IP's bucket")
+	}
+	assert.Equal(t, lookups, accounts.lookups, "limited keys are not looked up")
+	assert.Equal(t, http.StatusTooManyRequests, get("mm_valid", "192.0.2.1"), "the IP is limited until a key authenticates")
+	assert.Equal(t, http.StatusOK, get("mm_valid", "192.0.2.2"), "other IPs are unaffected")
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// reportHeader lists the columns of a CSV migration report.
+var reportHeader = []string{
+	"position", "type", "source_name", "source_artists", "source_album", "source_isrc",
+	"status", "confidence_score", "matched_name", "matched_artists", "matched_id", "error",
+}
+
+// GetMigrationReport exports a stored migration as a downloadable report.
+//
+//	@Summary		Download migration report
+//	@Description	Returns every track of a stored migration with its status, matched counterpart and
+//	@Description	confidence score as a downloadable file. Only the "csv" format is supported.
+//	@Description	With unmatched=true, only the tracks left without a match are listed, at their original
+//	@Description	positions; filtered tracks are left out.
+//	@Tags			migration
+//	@Produce		text/csv
+//	@Param			id			path		string	true	"Migration ID"
+//	@Param			format		query		string	false	"Report format"	Enums(csv)	default(csv)
+//	@Param			unmatched	query		bool	false	"Only list tracks that were not matched"
+//	@Success		200			{file}		file
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/migrations/{id}/report [get]
+func (h *Handler) GetMigrationReport(c *gin.Context) {
+	if format := c.DefaultQuery("format", "csv"); format != "csv" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: fmt.Sprintf("unsupported report format %q, expected csv", format),
+		})
+		return
+	}
+
+	result, err := h.service.GetMigration(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrMigrationNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	unmatched, _ := strconv.ParseBool(c.Query("unmatched"))
+	filename := "migration-" + result.ID
+	if unmatched {
+		filename += "-unmatched"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := writeCSVReport(c.Writer, result, unmatched); err != nil {
+		c.Error(err)
+	}
+}
+
+// writeCSVReport writes one row per track result of a migration, or, if
+// unmatched is set, per track result that is neither matched nor filtered.
+func writeCSVReport(w io.Writer, result *domain.MigrationResult, unmatched bool) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(reportHeader); err != nil {
+		return err
+	}
+
+	for i, tr := range result.TrackResults {
+		if unmatched && (tr.Status == domain.TrackStatusMatched || tr.Status == domain.TrackStatusFiltered) {
+			continue
+		}
+		itemType := tr.SourceTrack.Type
+		if itemType == "" {
+			itemType = domain.ItemTypeTrack
+		}
+		row := []string{
+			strconv.Itoa(i + 1),
+			string(itemType),
+			tr.SourceTrack.Name,
+			strings.Join(tr.SourceTrack.Artists, "; "),
+			tr.SourceTrack.Album,
+			tr.SourceTrack.ISRC,
+			string(tr.Status),
+			strconv.FormatFloat(tr.ConfidenceScore, 'f', 2, 64),
+			"", "", "",
+			tr.Error,
+		}
+		if m := tr.MatchedTrack; m != nil {
+			row[8] = m.Name
+			row[9] = strings.Join(m.Artists, "; ")
+			row[10] = m.ExternalID
+		}
+		for j := range row {
+			row[j] = escapeFormula(row[j])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// escapeFormula prefixes cell with a quote if it starts with a character
+// spreadsheets read as the start of a formula, so track names and errors
+// taken from providers are shown as text instead of being evaluated.
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
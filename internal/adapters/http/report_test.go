@@ -0,0 +1,119 @@
+package http
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+func TestGetMigrationReport_CSV(t *testing.T) {
+	r := setupRouter(&mockMigrationService{
+		migrationResult: &domain.MigrationResult{
+			ID: "mig-1",
+			TrackResults: []domain.TrackResult{
+				{
+					SourceTrack:     domain.Track{Name: "Yesterday", Artists: []string{"The Beatles"}, ISRC: "GBAYE0601477"},
+					MatchedTrack:    &domain.Track{Name: "Yesterday (Remastered)", Artists: []string{"The Beatles"}, ExternalID: "vid-1"},
+					Status:          domain.TrackStatusMatched,
+					ConfidenceScore: 0.9,
+				},
+				{
+					SourceTrack: domain.Track{Name: "Episode 1", Type: domain.ItemTypeEpisode},
+					Status:      domain.TrackStatusUnsupported,
+					Error:       "destination provider does not support podcast episodes",
+				},
+			},
+		},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/migrations/mig-1/report?format=csv", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+	assert.Equal(t, `attachment; filename="migration-mig-1.csv"`, w.Header().Get("Content-Disposition"))
+
+	rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, reportHeader, rows[0])
+	assert.Equal(t, []string{
+		"1", "track", "Yesterday", "The Beatles", "", "GBAYE0601477",
+		"matched", "0.90", "Yesterday (Remastered)", "The Beatles", "vid-1", "",
+	}, rows[1])
+	assert.Equal(t, "episode", rows[2][1])
+	assert.Equal(t, "unsupported", rows[2][6])
+}
+
+func TestGetMigrationReport_Unmatched(t *testing.T) {
+	r := setupRouter(&mockMigrationService{
+		migrationResult: &domain.MigrationResult{
+			ID: "mig-1",
+			TrackResults: []domain.TrackResult{
+				{SourceTrack: domain.Track{Name: "Yesterday"}, Status: domain.TrackStatusMatched},
+				{SourceTrack: domain.Track{Name: "Skipped"}, Status: domain.TrackStatusFiltered},
+				{SourceTrack: domain.Track{Name: "Rare B-Side"}, Status: domain.TrackStatusNotFound},
+			},
+		},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/migrations/mig-1/report?unmatched=true", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `attachment; filename="migration-mig-1-unmatched.csv"`, w.Header().Get("Content-Disposition"))
+
+	rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"3", "Rare B-Side", "not_found"}, []string{rows[1][0], rows[1][2], rows[1][6]})
+}
+
+func TestGetMigrationReport_EscapesFormulas(t *testing.T) {
+	r := setupRouter(&mockMigrationService{
+		migrationResult: &domain.MigrationResult{
+			ID: "mig-1",
+			TrackResults: []domain.TrackResult{{
+				SourceTrack: domain.Track{Name: `=HYPERLINK("http://evil","x")`, Artists: []string{"@Artist"}, Album: "-1"},
+				Status:      domain.TrackStatusNotFound,
+				Error:       "+cmd",
+			}},
+		},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/migrations/mig-1/report", nil))
+
+	rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, `'=HYPERLINK("http://evil","x")`, rows[1][2])
+	assert.Equal(t, "'@Artist", rows[1][3])
+	assert.Equal(t, "'-1", rows[1][4])
+	assert.Equal(t, "'+cmd", rows[1][11])
+}
+
+func TestGetMigrationReport_UnsupportedFormat(t *testing.T) {
+	r := setupRouter(&mockMigrationService{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/migrations/mig-1/report?format=pdf", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetMigrationReport_NotFound(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: domain.ErrMigrationNotFound})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/migrations/missing/report", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// trackStatuses lists the statuses the results endpoint can filter by.
+var trackStatuses = []domain.TrackStatus{
+	domain.TrackStatusMatched,
+	domain.TrackStatusNotFound,
+	domain.TrackStatusError,
+	domain.TrackStatusUnavailableInMarket,
+	domain.TrackStatusUnavailable,
+	domain.TrackStatusUnsupported,
+	domain.TrackStatusAddFailed,
+	domain.TrackStatusNeedsReview,
+	domain.TrackStatusFiltered,
+}
+
+// resultFilter selects track results by status and confidence score.
+type resultFilter struct {
+	statuses []domain.TrackStatus
+	minScore float64
+	maxScore float64
+}
+
+// parseResultFilter reads the status, min_score and max_score query
+// parameters.
+func parseResultFilter(c *gin.Context) (resultFilter, error) {
+	f := resultFilter{maxScore: 1}
+	for _, s := range strings.Split(c.Query("status"), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		status := domain.TrackStatus(s)
+		if !slices.Contains(trackStatuses, status) {
+			return f, fmt.Errorf("unknown status %q", s)
+		}
+		f.statuses = append(f.statuses, status)
+	}
+
+	for _, p := range []struct {
+		name  string
+		value *float64
+	}{{"min_score", &f.minScore}, {"max_score", &f.maxScore}} {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		score, err := strconv.ParseFloat(raw, 64)
+		if err != nil || score < 0 || score > 1 {
+			return f, fmt.Errorf("%s must be a number between 0 and 1", p.name)
+		}
+		*p.value = score
+	}
+	if f.minScore > f.maxScore {
+		return f, errors.New("min_score must not be greater than max_score")
+	}
+	return f, nil
+}
+
+func (f resultFilter) keep(tr domain.TrackResult) bool {
+	if len(f.statuses) > 0 && !slices.Contains(f.statuses, tr.Status) {
+		return false
+	}
+	return tr.ConfidenceScore >= f.minScore && tr.ConfidenceScore <= f.maxScore
+}
+
+// GetMigrationResults streams the track results of a stored migration.
+//
+//	@Summary		Stream migration track results
+//	@Description	Streams the track results of a stored migration as newline-delimited JSON, one TrackResult
+//	@Description	per line in source order, for processing large migrations with line-oriented tools.
+//	@Description	Results can be filtered by status and confidence score.
+//	@Tags			migration
+//	@Produce		application/x-ndjson
+//	@Param			id			path		string	true	"Migration ID"
+//	@Param			status		query		string	false	"Comma-separated statuses to include, e.g. not_found,error"
+//	@Param			min_score	query		number	false	"Minimum confidence score (0-1)"
+//	@Param			max_score	query		number	false	"Maximum confidence score (0-1)"
+//	@Success		200			{object}	domain.TrackResult
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/migrations/{id}/results.ndjson [get]
+func (h *Handler) GetMigrationResults(c *gin.Context) {
+	filter, err := parseResultFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "bad_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.GetMigration(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrMigrationNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+	if err := encodeTrackResults(c, json.NewEncoder(c.Writer), result.TrackResults, filter.keep); err != nil {
+		c.Error(err)
+	}
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+func resultsService() *mockMigrationService {
+	return &mockMigrationService{
+		migrationResult: &domain.MigrationResult{
+			ID: "mig-1",
+			TrackResults: []domain.TrackResult{
+				{SourceTrack: domain.Track{Name: "A"}, Status: domain.TrackStatusMatched, ConfidenceScore: 0.95},
+				{SourceTrack: domain.Track{Name: "B"}, Status: domain.TrackStatusMatched, ConfidenceScore: 0.6},
+				{SourceTrack: domain.Track{Name: "C"}, Status: domain.TrackStatusNotFound},
+				{SourceTrack: domain.Track{Name: "D"}, Status: domain.TrackStatusError},
+			},
+		},
+	}
+}
+
+func getResults(t *testing.T, query string) (*httptest.ResponseRecorder, []string) {
+	t.Helper()
+	r := setupRouter(resultsService())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/migrations/mig-1/results.ndjson"+query, nil))
+
+	var names []string
+	if w.Code != http.StatusOK {
+		return w, nil
+	}
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var tr domain.TrackResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &tr))
+		names = append(names, tr.SourceTrack.Name)
+	}
+	return w, names
+}
+
+func TestGetMigrationResults(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"A", "B", "C", "D"}},
+		{"?status=not_found", []string{"C"}},
+		{"?status=not_found,error", []string{"C", "D"}},
+		{"?min_score=0.5", []string{"A", "B"}},
+		{"?status=matched&max_score=0.8", []string{"B"}},
+		{"?min_score=0.99", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w, names := getResults(t, tt.query)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, ndjsonContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestGetMigrationResults_InvalidFilter(t *testing.T) {
+	for _, query := range []string{"?status=lost", "?min_score=high", "?max_score=2", "?min_score=0.8&max_score=0.2"} {
+		w, _ := getResults(t, query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestGetMigrationResults_NotFound(t *testing.T) {
+	r := setupRouter(&mockMigrationService{err: domain.ErrMigrationNotFound})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/migrations/missing/results.ndjson", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// ndjsonContentType is the media type of newline-delimited JSON responses.
+const ndjsonContentType = "application/x-ndjson"
+
+// ndjsonFlushEvery is how many lines are written between flushes of a
+// streamed response.
+const ndjsonFlushEvery = 100
+
+// acceptsNDJSON reports whether the client asked for newline-delimited JSON.
+func acceptsNDJSON(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), ndjsonContentType)
+}
+
+// writeMigrationResult responds with result as a JSON object, or, if the
+// client accepts NDJSON, streams it: the first line is the result without
+// its track results, followed by one line per track result. Streaming
+// encodes one track at a time instead of building the whole body in memory,
+// and clients can render tracks as they arrive.
+func writeMigrationResult(c *gin.Context, result *domain.MigrationResult) {
+	if !acceptsNDJSON(c) {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+
+	summary := *result
+	summary.TrackResults = nil
+	enc := json.NewEncoder(c.Writer)
+	if err := enc.Encode(summary); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := encodeTrackResults(c, enc, result.TrackResults, nil); err != nil {
+		c.Error(err)
+	}
+}
+
+// encodeTrackResults writes one line per track result that keep accepts, or
+// every one if keep is nil, flushing the response as it goes.
+func encodeTrackResults(c *gin.Context, enc *json.Encoder, results []domain.TrackResult, keep func(domain.TrackResult) bool) error {
+	written := 0
+	for _, tr := range results {
+		if keep != nil && !keep(tr) {
+			continue
+		}
+		if err := enc.Encode(tr); err != nil {
+			return err
+		}
+		if written++; written%ndjsonFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+	}
+	return nil
+}
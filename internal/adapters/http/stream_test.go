@@ -0,0 +1,56 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func largeResult(n int) *domain.MigrationResult {
+	result := &domain.MigrationResult{ID: "mig-1", TotalTracks: n}
+	for i := range n {
+		result.TrackResults = append(result.TrackResults, domain.TrackResult{
+			SourceTrack:    domain.Track{Name: "Track", Artists: []string{"Artist"}},
+			Status:         domain.TrackStatusMatched,
+			SourcePosition: i,
+		})
+	}
+	return result
+}
+
+func TestGetMigration_NDJSON(t *testing.T) {
+	for _, path := range []string{"/api/v1/migrations/mig-1", "/api/v2/migrations/mig-1"} {
+		t.Run(path, func(t *testing.T) {
+			r := setupRouter(&mockMigrationService{migrationResult: largeResult(250)})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Accept", ndjsonContentType)
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, ndjsonContentType, w.Header().Get("Content-Type"))
+
+			scanner := bufio.NewScanner(w.Body)
+			require.True(t, scanner.Scan())
+			var summary domain.MigrationResult
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &summary))
+			assert.Equal(t, "mig-1", summary.ID)
+			assert.Empty(t, summary.TrackResults)
+
+			lines := 0
+			for scanner.Scan() {
+				var tr domain.TrackResult
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &tr))
+				assert.Equal(t, lines, tr.SourcePosition)
+				lines++
+			}
+			assert.Equal(t, 250, lines)
+		})
+	}
+}
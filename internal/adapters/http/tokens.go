@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// StoreToken saves a provider token in the vault for the authenticated account.
+//
+//	@Summary		Store provider token
+//	@Description	Encrypts and stores an OAuth token for a streaming provider. Once stored, requests for
+//	@Description	this provider may omit the Authorization header or source/dest tokens.
+//	@Tags			tokens
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider	path	string					true	"Streaming provider"	Enums(spotify, youtube)
+//	@Param			request		body	domain.ProviderToken	true	"Provider token"
+//	@Success		204
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/tokens/{provider} [put]
+func (h *Handler) StoreToken(c *gin.Context) {
+	var token domain.ProviderToken
+	if !h.bindJSON(c, &token) {
+		return
+	}
+
+	if err := h.tokens.StoreToken(c.Request.Context(), c.Param("provider"), token); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteToken removes a provider token from the vault.
+//
+//	@Summary		Delete provider token
+//	@Description	Removes the stored OAuth token for a streaming provider.
+//	@Tags			tokens
+//	@Produce		json
+//	@Param			provider	path	string	true	"Streaming provider"	Enums(spotify, youtube)
+//	@Success		204
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/api/v1/tokens/{provider} [delete]
+func (h *Handler) DeleteToken(c *gin.Context) {
+	if err := h.tokens.DeleteToken(c.Request.Context(), c.Param("provider")); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// FieldError describes why a single field of a request body was rejected.
+type FieldError struct {
+	// Code is a stable, machine-readable reason such as "required",
+	// "invalid_type" or "unknown_provider".
+	Code    string `json:"code"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// bindJSON decodes and validates the request body into obj. On failure it
+// writes the error response and returns false:
+//   - 400 bad_request when the body is not valid JSON,
+//   - 400 validation_failed when fields are missing, malformed or of the wrong type,
+//   - 422 validation_failed when the body is well-formed but names an unknown provider.
+func (h *Handler) bindJSON(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		fields := fieldErrors(obj, err)
+		if fields == nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "bad_request",
+				Message: "invalid request body: " + err.Error(),
+			})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, validationFailed(fields))
+		return false
+	}
+
+	if fields := h.providerErrors(obj); len(fields) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, validationFailed(fields))
+		return false
+	}
+	return true
+}
+
+func validationFailed(fields []FieldError) ErrorResponse {
+	return ErrorResponse{
+		Error:   "validation_failed",
+		Message: "request body has invalid fields",
+		Fields:  fields,
+	}
+}
+
+// fieldErrors converts binding errors into field errors. It returns nil for
+// errors that cannot be attributed to a field, such as malformed JSON.
+func fieldErrors(obj any, err error) []FieldError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []FieldError{{
+			Code:    "invalid_type",
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("must be of type %s", typeErr.Type),
+		}}
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+
+	fields := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		fields = append(fields, FieldError{
+			Code:    fe.Tag(),
+			Field:   jsonFieldName(obj, fe),
+			Message: fieldMessage(fe),
+		})
+	}
+	return fields
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "len":
+		return fmt.Sprintf("must be exactly %s characters long", fe.Param())
+	case "min":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must contain at least %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must contain at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	default:
+		return fmt.Sprintf("failed the %q check", fe.Tag())
+	}
+}
+
+// jsonFieldName returns the JSON name of the top-level field fe refers to,
+// falling back to the Go field name.
+func jsonFieldName(obj any, fe validator.FieldError) string {
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fe.Field()
+	}
+	sf, ok := t.FieldByName(fe.StructField())
+	if !ok {
+		return fe.Field()
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return fe.Field()
+	}
+	return name
+}
+
+// providerErrors checks provider names in a request body against the
+// registered providers. It is a no-op unless WithProviders was used.
+func (h *Handler) providerErrors(obj any) []FieldError {
+	if h.providers == nil {
+		return nil
+	}
+
+	var fields []FieldError
+	check := func(field, name string) {
+		if name == "" || slices.Contains(h.providers, name) {
+			return
+		}
+		fields = append(fields, FieldError{
+			Code:    "unknown_provider",
+			Field:   field,
+			Message: fmt.Sprintf("unknown provider %q, available: %s", name, strings.Join(h.providers, ", ")),
+		})
+	}
+
+	switch req := obj.(type) {
+	case *domain.MigrationRequest:
+		check("source_provider", req.SourceProvider)
+		check("dest_provider", req.DestProvider)
+	case *domain.MigrationProfile:
+		check("source_provider", req.SourceProvider)
+		check("dest_provider", req.DestProvider)
+	case *domain.MatchFeedback:
+		check("source_provider", req.SourceProvider)
+		check("dest_provider", req.DestProvider)
+	case *domain.MergeRequest:
+		for i, src := range req.Sources {
+			check(fmt.Sprintf("sources[%d].provider", i), src.Provider)
+		}
+		check("dest_provider", req.DestProvider)
+	}
+	return fields
+}
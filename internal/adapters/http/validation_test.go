@@ -0,0 +1,82 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postMigrate(t *testing.T, h *Handler, body string) (*httptest.ResponseRecorder, ErrorResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h.RegisterRoutes(r)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/migrate", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	var resp ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return w, resp
+}
+
+func TestBindJSON_MissingFields(t *testing.T) {
+	w, resp := postMigrate(t, NewHandler(&mockMigrationService{}), `{"source_provider":"spotify"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "validation_failed", resp.Error)
+	assert.Equal(t, []FieldError{
+		{Code: "required", Field: "dest_provider", Message: "is required"},
+		{Code: "required", Field: "playlist_id", Message: "is required"},
+	}, resp.Fields)
+}
+
+func TestBindJSON_InvalidType(t *testing.T) {
+	w, resp := postMigrate(t, NewHandler(&mockMigrationService{}),
+		`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"pl-1","dry_run":"yes"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.Len(t, resp.Fields, 1)
+	assert.Equal(t, "invalid_type", resp.Fields[0].Code)
+	assert.Equal(t, "dry_run", resp.Fields[0].Field)
+}
+
+func TestBindJSON_UnknownMatchingStrategy(t *testing.T) {
+	w, resp := postMigrate(t, NewHandler(&mockMigrationService{}),
+		`{"source_provider":"spotify","dest_provider":"youtube","playlist_id":"pl-1","matching_strategy":"fuzzy"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []FieldError{{
+		Code:    "oneof",
+		Field:   "matching_strategy",
+		Message: "must be one of: isrc_only, strict, relaxed, duration_weighted",
+	}}, resp.Fields)
+}
+
+func TestBindJSON_MalformedJSON(t *testing.T) {
+	w, resp := postMigrate(t, NewHandler(&mockMigrationService{}), `{"source_provider":`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "bad_request", resp.Error)
+	assert.Empty(t, resp.Fields)
+}
+
+func TestBindJSON_UnknownProvider(t *testing.T) {
+	h := NewHandler(&mockMigrationService{}, WithProviders([]string{"youtube", "spotify"}))
+	w, resp := postMigrate(t, h, `{"source_provider":"spotify","dest_provider":"tidal","playlist_id":"pl-1"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, []FieldError{{
+		Code:    "unknown_provider",
+		Field:   "dest_provider",
+		Message: `unknown provider "tidal", available: spotify, youtube`,
+	}}, resp.Fields)
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"golang.org/x/net/websocket"
+)
+
+// apiKeyQueryParam carries the account API key on WebSocket requests,
+// since browsers cannot set headers on them.
+const apiKeyQueryParam = "api_key"
+
+// jobCommand is a message sent by a client watching a job.
+type jobCommand struct {
+	// Type is the command; "cancel" cancels the job.
+	Type string `json:"type"`
+}
+
+const jobCommandCancel = "cancel"
+
+// apiKeyFromQuery is a middleware that copies the api_key query parameter
+// into the X-API-Key header when the header is missing, for RequireAPIKey.
+func apiKeyFromQuery(c *gin.Context) {
+	if key := c.Query(apiKeyQueryParam); key != "" && c.GetHeader(apiKeyHeader) == "" {
+		c.Request.Header.Set(apiKeyHeader, key)
+	}
+	c.Next()
+}
+
+// WatchJob streams the progress of a queued migration over a WebSocket.
+//
+//	@Summary		Watch job
+//	@Description	Upgrades to a WebSocket that pushes JSON job events: "status" events carry the job whenever
+//	@Description	its status changes, starting with the current one, and "track" events carry each track
+//	@Description	search result with counters and an estimated completion time. Clients may send
+//	@Description	{"type":"cancel"} to cancel the job; a failed command is answered with an "error" event.
+//	@Description	The server closes the socket once the job finished. Browsers may pass the API key in the
+//	@Description	api_key query parameter instead of the X-API-Key header.
+//	@Tags			migration
+//	@Produce		json
+//	@Param			id		path		string	true	"Job ID"
+//	@Param			api_key	query		string	false	"Account API key, for clients that cannot set headers"
+//	@Success		101		{object}	domain.JobEvent
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Security		APIKeyAuth
+//	@Router			/ws/migrations/{id} [get]
+func (h *Handler) WatchJob(c *gin.Context) {
+	id := c.Param("id")
+
+	// The watch ends when the job finished or the client went away.
+	ctx, stop := context.WithCancel(c.Request.Context())
+	defer stop()
+
+	events, err := h.jobs.WatchJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Clients authenticate with the API key, so the origin is not checked.
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+		go h.readJobCommands(ctx, stop, ws, id)
+		for ev := range events {
+			if err := websocket.JSON.Send(ws, ev); err != nil {
+				return
+			}
+		}
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// readJobCommands carries out the commands a client sends for job id until
+// the connection fails, then calls stop.
+func (h *Handler) readJobCommands(ctx context.Context, stop context.CancelFunc, ws *websocket.Conn, id string) {
+	defer stop()
+	for {
+		var cmd jobCommand
+		if err := websocket.JSON.Receive(ws, &cmd); err != nil {
+			return
+		}
+
+		var err error
+		switch cmd.Type {
+		case jobCommandCancel:
+			_, err = h.jobs.CancelJob(ctx, id)
+		default:
+			err = errors.New("unknown command " + cmd.Type)
+		}
+		if err != nil {
+			if err := websocket.JSON.Send(ws, domain.JobEvent{Type: domain.JobEventError, Error: err.Error()}); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package adapters
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// HTTPClientConfig configures the HTTP clients that talk to provider APIs.
+// Zero fields take the value of the factory defaults.
+type HTTPClientConfig struct {
+	// Timeout bounds a whole request, including reading the response body.
+	Timeout time.Duration
+
+	// DialTimeout bounds opening a connection.
+	DialTimeout time.Duration
+
+	// IdleConnTimeout is how long an unused keep-alive connection is kept.
+	IdleConnTimeout time.Duration
+
+	// MaxIdleConnsPerHost is how many keep-alive connections to one host
+	// are kept for reuse. Go's default of 2 makes migrations with many
+	// workers open and close connections all the time.
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost caps the connections to one host; 0 is unlimited.
+	MaxConnsPerHost int
+
+	// MaxResponseBytes caps the size of a response body: reading beyond it
+	// fails with ErrResponseTooLarge, so a misbehaving provider cannot
+	// exhaust the memory of the service.
+	MaxResponseBytes int
+
+	// Proxy is the URL of the proxy HTTP and HTTPS requests go through,
+	// except those to the comma-separated hosts and domains of NoProxy.
+	// Empty uses the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+	// variables.
+	Proxy   string
+	NoProxy string
+
+	// CABundle is a PEM file of root certificates trusted in addition to
+	// the system ones, such as the CA of a TLS-intercepting proxy.
+	CABundle string
+
+	// InsecureSkipVerify accepts any server certificate. It is meant for
+	// test setups only.
+	InsecureSkipVerify bool
+}
+
+// defaultHTTPClientConfig fills the fields the configuration leaves zero.
+var defaultHTTPClientConfig = HTTPClientConfig{
+	Timeout:             30 * time.Second,
+	DialTimeout:         10 * time.Second,
+	IdleConnTimeout:     90 * time.Second,
+	MaxIdleConnsPerHost: 32,
+	MaxResponseBytes:    10 << 20,
+}
+
+// merge returns c with its zero fields taken from base.
+func (c HTTPClientConfig) merge(base HTTPClientConfig) HTTPClientConfig {
+	if c.Timeout == 0 {
+		c.Timeout = base.Timeout
+	}
+	if c.DialTimeout == 0 {
+		c.DialTimeout = base.DialTimeout
+	}
+	if c.IdleConnTimeout == 0 {
+		c.IdleConnTimeout = base.IdleConnTimeout
+	}
+	if c.MaxIdleConnsPerHost == 0 {
+		c.MaxIdleConnsPerHost = base.MaxIdleConnsPerHost
+	}
+	if c.MaxConnsPerHost == 0 {
+		c.MaxConnsPerHost = base.MaxConnsPerHost
+	}
+	if c.MaxResponseBytes == 0 {
+		c.MaxResponseBytes = base.MaxResponseBytes
+	}
+	if c.Proxy == "" {
+		c.Proxy = base.Proxy
+	}
+	if c.NoProxy == "" {
+		c.NoProxy = base.NoProxy
+	}
+	if c.CABundle == "" {
+		c.CABundle = base.CABundle
+	}
+	c.InsecureSkipVerify = c.InsecureSkipVerify || base.InsecureSkipVerify
+	return c
+}
+
+// HTTPClientFactory builds the HTTP clients of providers from shared
+// defaults and per-provider overrides.
+type HTTPClientFactory struct {
+	defaults  clientSettings
+	overrides map[string]clientSettings
+}
+
+// clientSettings is an HTTPClientConfig with its proxy and TLS settings
+// resolved.
+type clientSettings struct {
+	cfg   HTTPClientConfig
+	proxy func(*http.Request) (*url.URL, error)
+	tls   *tls.Config
+}
+
+// NewHTTPClientFactory returns a factory whose clients use defaults, with
+// the non-zero fields of overrides[name] taking precedence for the client
+// of the provider called name. Invalid proxy URLs and CA bundles are
+// reported here rather than on the first request.
+func NewHTTPClientFactory(defaults HTTPClientConfig, overrides map[string]HTTPClientConfig) (*HTTPClientFactory, error) {
+	defaults = defaults.merge(defaultHTTPClientConfig)
+	base, err := resolve(defaults)
+	if err != nil {
+		return nil, err
+	}
+	f := &HTTPClientFactory{defaults: base, overrides: make(map[string]clientSettings, len(overrides))}
+	for name, override := range overrides {
+		if f.overrides[name], err = resolve(override.merge(defaults)); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return f, nil
+}
+
+// resolve parses the proxy settings of cfg and loads its CA bundle.
+func resolve(cfg HTTPClientConfig) (clientSettings, error) {
+	settings := clientSettings{cfg: cfg, proxy: http.ProxyFromEnvironment}
+	if cfg.Proxy != "" {
+		proxy, err := url.Parse(cfg.Proxy)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return clientSettings{}, fmt.Errorf("invalid proxy URL %q", cfg.Proxy)
+		}
+		proxyURL := (&httpproxy.Config{HTTPProxy: cfg.Proxy, HTTPSProxy: cfg.Proxy, NoProxy: cfg.NoProxy}).ProxyFunc()
+		settings.proxy = func(r *http.Request) (*url.URL, error) {
+			return proxyURL(r.URL)
+		}
+	}
+
+	if cfg.CABundle == "" && !cfg.InsecureSkipVerify {
+		return settings, nil
+	}
+	settings.tls = &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return clientSettings{}, fmt.Errorf("CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return clientSettings{}, fmt.Errorf("CA bundle %s holds no PEM certificates", cfg.CABundle)
+		}
+		settings.tls.RootCAs = pool
+	}
+	return settings, nil
+}
+
+// settings returns the resolved settings of the client of the provider
+// called name.
+func (f *HTTPClientFactory) settings(name string) clientSettings {
+	if s, ok := f.overrides[name]; ok {
+		return s
+	}
+	return f.defaults
+}
+
+// Config returns the settings of the client of the provider called name.
+func (f *HTTPClientFactory) Config(name string) HTTPClientConfig {
+	return f.settings(name).cfg
+}
+
+// Client returns a new client for the provider called name, with a
+// connection pool of its own. Its response bodies are limited to
+// MaxResponseBytes.
+func (f *HTTPClientFactory) Client(name string) *http.Client {
+	s := f.settings(name)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = s.proxy
+	transport.DialContext = (&net.Dialer{Timeout: s.cfg.DialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.IdleConnTimeout = s.cfg.IdleConnTimeout
+	transport.MaxIdleConnsPerHost = s.cfg.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = s.cfg.MaxConnsPerHost
+	if s.tls != nil {
+		transport.TLSClientConfig = s.tls.Clone()
+	}
+	return &http.Client{Transport: &limitTransport{base: transport, limit: int64(s.cfg.MaxResponseBytes)}, Timeout: s.cfg.Timeout}
+}
+
+// ErrResponseTooLarge is returned when reading a response body beyond the
+// MaxResponseBytes of its client.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// limitTransport limits the response bodies of base to limit bytes.
+type limitTransport struct {
+	base  http.RoundTripper
+	limit int64
+}
+
+func (t *limitTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, left: t.limit, limit: t.limit}
+	return resp, nil
+}
+
+// limitedBody reads up to limit bytes of a body and fails if the body goes
+// on. Unlike an io.LimitReader, it does not pass a cut body off as whole.
+type limitedBody struct {
+	io.ReadCloser
+	left, limit int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.left <= 0 {
+		var probe [1]byte
+		if n, err := b.ReadCloser.Read(probe[:]); n == 0 {
+			return 0, err
+		}
+		return 0, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, b.limit)
+	}
+	if int64(len(p)) > b.left {
+		p = p[:b.left]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.left -= int64(n)
+	return n, err
+}
+
+// RetryAfter returns the wait a rate-limited response asks for in its
+// Retry-After header, given in seconds or as an HTTP date, or 0 if the
+// header is missing or invalid.
+func RetryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
+
+// Pause waits interval before every request of a write but the first, the
+// request with index i, and returns the error of ctx if it is done.
+func Pause(ctx context.Context, i int, interval time.Duration) error {
+	if i == 0 || interval <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+package adapters
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// poolTransport returns the connection pool of a client of the factory.
+func poolTransport(c *http.Client) *http.Transport {
+	return c.Transport.(*limitTransport).base.(*http.Transport)
+}
+
+func TestHTTPClientFactory(t *testing.T) {
+	f, err := NewHTTPClientFactory(
+		HTTPClientConfig{Timeout: time.Minute, MaxIdleConnsPerHost: 64},
+		map[string]HTTPClientConfig{
+			"youtube": {Timeout: 5 * time.Second, MaxConnsPerHost: 8, Proxy: "http://proxy.internal:3128"},
+		},
+	)
+	require.NoError(t, err)
+
+	spotify := f.Client("spotify")
+	assert.Equal(t, time.Minute, spotify.Timeout)
+	transport := poolTransport(spotify)
+	assert.Equal(t, 64, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 0, transport.MaxConnsPerHost)
+	assert.Equal(t, 90*time.Second, transport.IdleConnTimeout, "unset fields take the defaults")
+
+	youtube := f.Client("youtube")
+	assert.Equal(t, 5*time.Second, youtube.Timeout)
+	transport = poolTransport(youtube)
+	assert.Equal(t, 64, transport.MaxIdleConnsPerHost, "unset overrides take the factory defaults")
+	assert.Equal(t, 8, transport.MaxConnsPerHost)
+	req, _ := http.NewRequest(http.MethodGet, "https://www.googleapis.com/youtube/v3/search", nil)
+	proxy, err := transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.internal:3128", proxy.Host)
+
+	assert.NotSame(t, poolTransport(f.Client("spotify")), poolTransport(spotify), "every client has its own pool")
+}
+
+func TestHTTPClientFactory_InvalidProxy(t *testing.T) {
+	_, err := NewHTTPClientFactory(HTTPClientConfig{Proxy: "proxy.internal"}, nil)
+	assert.ErrorContains(t, err, `invalid proxy URL "proxy.internal"`)
+
+	_, err = NewHTTPClientFactory(HTTPClientConfig{}, map[string]HTTPClientConfig{"lastfm": {Proxy: "://"}})
+	assert.ErrorContains(t, err, "lastfm: invalid proxy URL")
+}
+
+func TestHTTPClientFactory_NoProxy(t *testing.T) {
+	f, err := NewHTTPClientFactory(HTTPClientConfig{Proxy: "http://proxy.internal:3128", NoProxy: ".corp.internal"}, nil)
+	require.NoError(t, err)
+	transport := poolTransport(f.Client("spotify"))
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.spotify.com/v1/me", nil)
+	proxy, err := transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.internal:3128", proxy.Host)
+
+	req, _ = http.NewRequest(http.MethodGet, "https://musicbrainz.corp.internal/ws/2/recording", nil)
+	proxy, err = transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Nil(t, proxy)
+}
+
+func TestHTTPClientFactory_CABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+
+	get := func(cfg HTTPClientConfig) error {
+		f, err := NewHTTPClientFactory(cfg, nil)
+		require.NoError(t, err)
+		resp, err := f.Client("spotify").Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	assert.ErrorContains(t, get(HTTPClientConfig{}), "certificate")
+	assert.NoError(t, get(HTTPClientConfig{CABundle: bundle}))
+	assert.NoError(t, get(HTTPClientConfig{InsecureSkipVerify: true}))
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(empty, []byte("not a certificate"), 0o600))
+	_, err := NewHTTPClientFactory(HTTPClientConfig{}, map[string]HTTPClientConfig{"youtube": {CABundle: empty}})
+	assert.ErrorContains(t, err, "youtube: CA bundle")
+	_, err = NewHTTPClientFactory(HTTPClientConfig{CABundle: filepath.Join(t.TempDir(), "missing.pem")}, nil)
+	assert.ErrorContains(t, err, "CA bundle")
+}
+
+func TestHTTPClientFactory_MaxResponseBytes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 64)))
+	}))
+	defer srv.Close()
+
+	f, err := NewHTTPClientFactory(HTTPClientConfig{MaxResponseBytes: 64}, map[string]HTTPClientConfig{"youtube": {MaxResponseBytes: 32}})
+	require.NoError(t, err)
+	assert.Equal(t, 64, f.Config("lastfm").MaxResponseBytes)
+
+	get := func(provider string) ([]byte, error) {
+		resp, err := f.Client(provider).Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return io.ReadAll(resp.Body)
+	}
+
+	body, err := get("spotify")
+	require.NoError(t, err, "a body of exactly the limit is read whole")
+	assert.Len(t, body, 64)
+
+	_, err = get("youtube")
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+}
+
+func TestRetryAfter(t *testing.T) {
+	header := func(v string) http.Header {
+		h := http.Header{}
+		if v != "" {
+			h.Set("Retry-After", v)
+		}
+		return h
+	}
+	assert.Equal(t, 7*time.Second, RetryAfter(header("7")))
+	assert.Zero(t, RetryAfter(header("")))
+	assert.Zero(t, RetryAfter(header("soon")))
+	assert.Zero(t, RetryAfter(header("-3")))
+
+	wait := RetryAfter(header(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)))
+	assert.InDelta(t, time.Minute.Seconds(), wait.Seconds(), 2)
+}
+
+func TestPause(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+	require.NoError(t, Pause(ctx, 0, time.Hour), "the first request does not wait")
+	require.NoError(t, Pause(ctx, 3, 0))
+	require.NoError(t, Pause(ctx, 1, 10*time.Millisecond))
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	assert.Less(t, time.Since(start), time.Second)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, Pause(canceled, 0, 0), context.Canceled)
+	assert.ErrorIs(t, Pause(canceled, 1, time.Hour), context.Canceled)
+}
@@ -0,0 +1,128 @@
+// Package httpdebug records the provider HTTP traffic of migrations run in
+// debug mode, so operators can see what a provider answered without
+// reproducing a failure by hand, and removes credentials from recorded
+// traffic and other text that is logged or returned.
+package httpdebug
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// maxBody is how much of a response body an exchange keeps.
+const maxBody = 2048
+
+// redacted replaces credentials in recorded URLs and bodies.
+const redacted = "REDACTED"
+
+// secretParams are query parameters that carry credentials.
+var secretParams = map[string]bool{
+	"access_token":  true,
+	"api_key":       true,
+	"client_secret": true,
+	"code":          true,
+	"key":           true,
+	"refresh_token": true,
+	"token":         true,
+}
+
+// secretFields matches JSON fields of token responses and migration
+// requests that carry credentials.
+var secretFields = regexp.MustCompile(`("(?:access_token|refresh_token|id_token|client_secret|source_token|dest_token|token|api_key|password)"\s*:\s*)"[^"]*"`)
+
+// secretQuery matches credential query parameters in URLs quoted in text.
+var secretQuery = regexp.MustCompile(`(?i)([?&](?:access_token|api_key|client_secret|code|key|refresh_token|token)=)[^&\s"']+`)
+
+// authSchemes matches the credentials of Authorization header values.
+var authSchemes = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`)
+
+// Transport is an http.RoundTripper that records every exchange whose
+// request context comes from domain.ContextWithDebugCapture. Requests made
+// with other contexts pass through untouched.
+type Transport struct {
+	base http.RoundTripper
+	now  func() time.Time
+}
+
+// NewTransport wraps base, or http.DefaultTransport if base is nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, now: time.Now}
+}
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+	if !domain.DebugCaptureEnabled(ctx) {
+		return t.base.RoundTrip(r)
+	}
+
+	started := t.now()
+	exchange := domain.ProviderExchange{
+		Time:   started.UTC(),
+		Method: r.Method,
+		URL:    SanitizeURL(r.URL),
+	}
+	resp, err := t.base.RoundTrip(r)
+	exchange.DurationMS = t.now().Sub(started).Milliseconds()
+	if err != nil {
+		exchange.Error = err.Error()
+		domain.RecordProviderExchange(ctx, exchange)
+		return nil, err
+	}
+
+	exchange.Status = resp.StatusCode
+	// Read one byte beyond the limit to tell whether the body was cut, and
+	// hand the caller the bytes read followed by the rest of the body.
+	head, _ := io.ReadAll(io.LimitReader(resp.Body, maxBody+1))
+	resp.Body = readCloser{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+	if len(head) > maxBody {
+		head, exchange.Truncated = head[:maxBody], true
+	}
+	exchange.Body = SanitizeBody(string(head))
+	domain.RecordProviderExchange(ctx, exchange)
+	return resp, nil
+}
+
+// readCloser reads from a replacement reader and closes the original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// SanitizeURL returns u without user info and with the values of credential
+// query parameters replaced.
+func SanitizeURL(u *url.URL) string {
+	clean := *u
+	clean.User = nil
+	query := clean.Query()
+	for name := range query {
+		if secretParams[strings.ToLower(name)] {
+			query[name] = []string{redacted}
+		}
+	}
+	clean.RawQuery = query.Encode()
+	return clean.String()
+}
+
+// SanitizeBody replaces the tokens in a token response.
+func SanitizeBody(body string) string {
+	return secretFields.ReplaceAllString(body, `$1"`+redacted+`"`)
+}
+
+// SanitizeText replaces the credentials in free text such as an error
+// message: token fields of JSON bodies, credential query parameters of
+// URLs and Authorization header values.
+func SanitizeText(text string) string {
+	text = SanitizeBody(text)
+	text = secretQuery.ReplaceAllString(text, "${1}"+redacted)
+	return authSchemes.ReplaceAllString(text, "$1 "+redacted)
+}
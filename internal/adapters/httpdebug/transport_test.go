@@ -0,0 +1,118 @@
+package httpdebug
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport_RecordsSanitizedExchanges(t *testing.T) {
+	long := strings.Repeat("x", maxBody+100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			w.Write([]byte(`{"access_token": "secret-access", "refresh_token":"secret-refresh", "expires_in": 3600}`))
+		case "/search":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(long))
+		}
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	ctx, capture := domain.ContextWithDebugCapture(context.Background())
+	get := func(path string) string {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	assert.Contains(t, get("/token?grant_type=refresh_token"), "secret-access", "the caller gets the whole body")
+	assert.Equal(t, long, get("/search?q=love+story&key=api-key"))
+
+	log := capture.Log()
+	require.Len(t, log.Exchanges, 2)
+
+	token := log.Exchanges[0]
+	assert.Equal(t, http.MethodGet, token.Method)
+	assert.Equal(t, http.StatusOK, token.Status)
+	assert.Equal(t, `{"access_token": "REDACTED", "refresh_token":"REDACTED", "expires_in": 3600}`, token.Body)
+	assert.False(t, token.Truncated)
+
+	search := log.Exchanges[1]
+	assert.Equal(t, srv.URL+"/search?key=REDACTED&q=love+story", search.URL)
+	assert.Equal(t, http.StatusForbidden, search.Status)
+	assert.Len(t, search.Body, maxBody)
+	assert.True(t, search.Truncated)
+}
+
+func TestTransport_RecordsErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	ctx, capture := domain.ContextWithDebugCapture(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	require.Error(t, err)
+
+	log := capture.Log()
+	require.Len(t, log.Exchanges, 1)
+	assert.Zero(t, log.Exchanges[0].Status)
+	assert.NotEmpty(t, log.Exchanges[0].Error)
+}
+
+func TestTransport_PassesThroughWithoutCapture(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "ok", string(body))
+}
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{
+			"authorization header",
+			"request failed: Authorization: Bearer BQDa-x_9.z+/= rejected",
+			"request failed: Authorization: Bearer REDACTED rejected",
+		},
+		{
+			"url query",
+			`Get "https://www.googleapis.com/youtube/v3/search?key=AIza123&q=x": timeout`,
+			`Get "https://www.googleapis.com/youtube/v3/search?key=REDACTED&q=x": timeout`,
+		},
+		{
+			"request body",
+			`invalid body {"source_token":"tok-1","dest_token": "tok-2","playlist_id":"p1"}`,
+			`invalid body {"source_token":"REDACTED","dest_token": "REDACTED","playlist_id":"p1"}`,
+		},
+		{"plain text", "token expired for spotify", "token expired for spotify"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SanitizeText(tt.text))
+		})
+	}
+}
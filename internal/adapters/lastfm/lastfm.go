@@ -0,0 +1,354 @@
+// Package lastfm provides a source-only provider that exposes a Last.fm
+// user's loved tracks and top tracks as playlists, so scrobble history can
+// be turned into a playlist on a streaming service.
+package lastfm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// ProviderName is the name the Last.fm provider registers under.
+const ProviderName = "lastfm"
+
+const (
+	baseURL = "https://ws.audioscrobbler.com/2.0/"
+
+	// lovedPageSize is the number of loved tracks requested per call.
+	lovedPageSize = 200
+
+	// topTracks is the length of the top track playlists.
+	topTracks = 100
+)
+
+// Playlist IDs. Top tracks of a calendar year are available as "top-YYYY".
+const (
+	LovedPlaylistID = "loved"
+	topPrefix       = "top-"
+)
+
+// periods are the Last.fm chart periods offered as "top-<period>" playlists,
+// in listing order.
+var periods = []struct {
+	id   string
+	name string
+}{
+	{"7day", "last 7 days"},
+	{"1month", "last month"},
+	{"3month", "last 3 months"},
+	{"6month", "last 6 months"},
+	{"12month", "last 12 months"},
+	{"overall", "all time"},
+}
+
+// Provider implements ports.MusicProvider and ports.SourceOnly using the
+// Last.fm API. Last.fm listening data is public, so the token passed to
+// every call is the Last.fm username rather than an OAuth token.
+type Provider struct {
+	client  *http.Client
+	apiKey  string
+	baseURL string
+}
+
+// NewProvider creates a Last.fm provider authenticating with the given API
+// key. If client is nil, http.DefaultClient is used.
+func NewProvider(client *http.Client, apiKey string) *Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Provider{client: client, apiKey: apiKey, baseURL: baseURL}
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// SourceOnly implements ports.SourceOnly.
+func (p *Provider) SourceOnly() {}
+
+// -- API response types (internal) ------------------------------------------
+
+type trackList struct {
+	Tracks []trackResource `json:"track"`
+	Attr   struct {
+		TotalPages string `json:"totalPages"`
+	} `json:"@attr"`
+}
+
+type trackResource struct {
+	Name   string `json:"name"`
+	MBID   string `json:"mbid"`
+	URL    string `json:"url"`
+	Artist struct {
+		Name string `json:"name"`
+		Text string `json:"#text"` // user.getweeklytrackchart
+	} `json:"artist"`
+}
+
+func (t trackResource) toDomain() domain.Track {
+	artist := t.Artist.Name
+	if artist == "" {
+		artist = t.Artist.Text
+	}
+	track := domain.Track{
+		Name:          t.Name,
+		MusicBrainzID: t.MBID,
+		ExternalID:    t.URL,
+		Type:          domain.ItemTypeTrack,
+	}
+	if artist != "" {
+		track.Artists = []string{artist}
+	}
+	return track
+}
+
+// -- Port implementation ----------------------------------------------------
+
+func (p *Provider) GetPlaylists(ctx context.Context, token string) ([]domain.Playlist, error) {
+	page, err := p.GetPlaylistsPage(ctx, token, domain.PageRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetPlaylistsPage lists the loved tracks and the top tracks of each chart
+// period. Track counts are left at zero to avoid fetching every chart.
+func (p *Provider) GetPlaylistsPage(_ context.Context, token string, page domain.PageRequest) (*domain.PlaylistPage, error) {
+	offset := 0
+	if page.Cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(page.Cursor); err != nil || offset < 0 {
+			return nil, fmt.Errorf("lastfm: %w %q", domain.ErrInvalidCursor, page.Cursor)
+		}
+	}
+
+	ids := []string{LovedPlaylistID}
+	for _, period := range periods {
+		ids = append(ids, topPrefix+period.id)
+	}
+	playlists := make([]domain.Playlist, len(ids))
+	for i, id := range ids {
+		playlists[i] = domain.Playlist{ID: id, Name: playlistName(id), OwnerName: token, IsOwner: true, IsPublic: true}
+	}
+
+	offset = min(offset, len(playlists))
+	end := len(playlists)
+	if page.Limit > 0 {
+		end = min(offset+page.Limit, len(playlists))
+	}
+	result := &domain.PlaylistPage{Items: playlists[offset:end], Total: len(playlists)}
+	if end < len(playlists) {
+		result.NextCursor = strconv.Itoa(end)
+	}
+	return result, nil
+}
+
+func (p *Provider) GetPlaylist(ctx context.Context, token string, playlistID string) (*domain.Playlist, error) {
+	tracks, err := p.GetPlaylistTracks(ctx, token, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Playlist{
+		ID:         playlistID,
+		Name:       playlistName(playlistID),
+		OwnerName:  token,
+		TrackCount: len(tracks),
+		IsOwner:    true,
+		IsPublic:   true,
+	}, nil
+}
+
+func (p *Provider) GetPlaylistTracks(ctx context.Context, token string, playlistID string) ([]domain.Track, error) {
+	if playlistID == LovedPlaylistID {
+		return p.lovedTracks(ctx, token)
+	}
+
+	id, ok := strings.CutPrefix(playlistID, topPrefix)
+	if !ok {
+		return nil, domain.ErrPlaylistNotFound
+	}
+	for _, period := range periods {
+		if period.id == id {
+			return p.topTracks(ctx, token, url.Values{
+				"method": {"user.gettoptracks"},
+				"period": {id},
+				"limit":  {strconv.Itoa(topTracks)},
+			}, "toptracks")
+		}
+	}
+	if year, err := strconv.Atoi(id); err == nil && len(id) == 4 {
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return p.topTracks(ctx, token, url.Values{
+			"method": {"user.getweeklytrackchart"},
+			"from":   {strconv.FormatInt(from.Unix(), 10)},
+			"to":     {strconv.FormatInt(from.AddDate(1, 0, 0).Unix(), 10)},
+		}, "weeklytrackchart")
+	}
+	return nil, domain.ErrPlaylistNotFound
+}
+
+func (p *Provider) SearchTrack(_ context.Context, _ string, _ domain.Track) (*domain.Track, float64, error) {
+	return nil, 0, fmt.Errorf("lastfm: %w", domain.ErrSourceOnlyProvider)
+}
+
+func (p *Provider) CreatePlaylist(_ context.Context, _ string, _ string, _ string) (string, error) {
+	return "", fmt.Errorf("lastfm: %w", domain.ErrSourceOnlyProvider)
+}
+
+func (p *Provider) AddTracksToPlaylist(_ context.Context, _ string, _ string, trackIDs []string) ([]domain.AddOutcome, error) {
+	err := fmt.Errorf("lastfm: %w", domain.ErrSourceOnlyProvider)
+	return domain.NotAdded(nil, trackIDs, err), err
+}
+
+func (p *Provider) RemoveTracksFromPlaylist(_ context.Context, _ string, _ string, _ []string) error {
+	return fmt.Errorf("lastfm: %w", domain.ErrSourceOnlyProvider)
+}
+
+func (p *Provider) UpdatePlaylistDetails(_ context.Context, _ string, _ string, _ domain.PlaylistUpdate) error {
+	return fmt.Errorf("lastfm: %w", domain.ErrSourceOnlyProvider)
+}
+
+func (p *Provider) DeletePlaylist(_ context.Context, _ string, _ string) error {
+	return fmt.Errorf("lastfm: %w", domain.ErrSourceOnlyProvider)
+}
+
+// -- Charts ------------------------------------------------------------------
+
+// playlistName returns the display name of a playlist ID.
+func playlistName(playlistID string) string {
+	if playlistID == LovedPlaylistID {
+		return "Loved tracks"
+	}
+	id := strings.TrimPrefix(playlistID, topPrefix)
+	for _, period := range periods {
+		if period.id == id {
+			return fmt.Sprintf("Top %d tracks (%s)", topTracks, period.name)
+		}
+	}
+	return fmt.Sprintf("Top %d tracks of %s", topTracks, id)
+}
+
+// lovedTracks returns every loved track of the user, most recent first.
+func (p *Provider) lovedTracks(ctx context.Context, user string) ([]domain.Track, error) {
+	var tracks []domain.Track
+	for page := 1; ; page++ {
+		var resp struct {
+			LovedTracks trackList `json:"lovedtracks"`
+		}
+		err := p.call(ctx, url.Values{
+			"method": {"user.getlovedtracks"},
+			"user":   {user},
+			"limit":  {strconv.Itoa(lovedPageSize)},
+			"page":   {strconv.Itoa(page)},
+		}, &resp)
+		if err != nil {
+			return nil, fmt.Errorf("lastfm: failed to get loved tracks: %w", err)
+		}
+		for _, t := range resp.LovedTracks.Tracks {
+			tracks = append(tracks, t.toDomain())
+		}
+
+		totalPages, _ := strconv.Atoi(resp.LovedTracks.Attr.TotalPages)
+		if page >= totalPages || len(resp.LovedTracks.Tracks) == 0 {
+			return tracks, nil
+		}
+	}
+}
+
+// topTracks calls a chart method and returns its first topTracks entries,
+// which Last.fm orders by play count. key is the response's root object.
+func (p *Provider) topTracks(ctx context.Context, user string, params url.Values, key string) ([]domain.Track, error) {
+	params.Set("user", user)
+	var resp map[string]trackList
+	if err := p.call(ctx, params, &resp); err != nil {
+		return nil, fmt.Errorf("lastfm: failed to get top tracks: %w", err)
+	}
+
+	list := resp[key].Tracks
+	tracks := make([]domain.Track, 0, min(len(list), topTracks))
+	for _, t := range list[:min(len(list), topTracks)] {
+		tracks = append(tracks, t.toDomain())
+	}
+	return tracks, nil
+}
+
+// -- HTTP helpers ------------------------------------------------------------
+
+// Last.fm API error codes, see https://www.last.fm/api/errorcodes.
+const (
+	errInvalidParameters = 6 // also returned for unknown users
+	errRateLimitExceeded = 29
+)
+
+// apiError is returned by call when Last.fm responds with an error.
+type apiError struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("lastfm API returned status %d, error %d: %s", e.StatusCode, e.Code, adapters.ErrorBody(e.Message))
+}
+
+// Is reports rate limit errors as domain.ErrRateLimited and unknown users as
+// domain.ErrPlaylistNotFound.
+func (e *apiError) Is(target error) bool {
+	switch target {
+	case domain.ErrRateLimited:
+		return e.Code == errRateLimitExceeded || e.StatusCode == http.StatusTooManyRequests
+	case domain.ErrPlaylistNotFound:
+		return e.Code == errInvalidParameters
+	default:
+		return false
+	}
+}
+
+// call invokes an API method and decodes its JSON response into out.
+func (p *Provider) call(ctx context.Context, params url.Values, out any) error {
+	if params.Get("user") == "" {
+		return errors.New("a Last.fm username is required as token")
+	}
+	params.Set("api_key", p.apiKey)
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return adapters.SanitizeError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	// Errors come with a JSON body and, depending on the error, a 200 status.
+	var apiErr struct {
+		Error   int    `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != 0 {
+		return &apiError{StatusCode: resp.StatusCode, Code: apiErr.Error, Message: apiErr.Message}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &apiError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+	return json.Unmarshal(body, out)
+}
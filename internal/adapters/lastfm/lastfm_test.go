@@ -0,0 +1,156 @@
+package lastfm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T, handler http.HandlerFunc) *Provider {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	p := NewProvider(srv.Client(), "key")
+	p.baseURL = srv.URL
+	return p
+}
+
+func TestGetPlaylists(t *testing.T) {
+	p := NewProvider(nil, "key")
+
+	playlists, err := p.GetPlaylists(context.Background(), "alice")
+	require.NoError(t, err)
+
+	require.Len(t, playlists, 7)
+	assert.Equal(t, LovedPlaylistID, playlists[0].ID)
+	assert.Equal(t, "top-7day", playlists[1].ID)
+	assert.Equal(t, "Top 100 tracks (last 7 days)", playlists[1].Name)
+	assert.Equal(t, "top-overall", playlists[6].ID)
+}
+
+func TestGetPlaylistTracks_LovedPages(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "user.getlovedtracks", q.Get("method"))
+		assert.Equal(t, "alice", q.Get("user"))
+		assert.Equal(t, "key", q.Get("api_key"))
+		fmt.Fprintf(w, `{"lovedtracks":{"track":[{"name":"Song %[1]s","mbid":"mb-%[1]s","url":"https://last.fm/%[1]s","artist":{"name":"Artist"}}],"@attr":{"page":"%[1]s","totalPages":"2"}}}`, q.Get("page"))
+	})
+
+	tracks, err := p.GetPlaylistTracks(context.Background(), "alice", LovedPlaylistID)
+	require.NoError(t, err)
+
+	require.Len(t, tracks, 2)
+	assert.Equal(t, "Song 1", tracks[0].Name)
+	assert.Equal(t, []string{"Artist"}, tracks[0].Artists)
+	assert.Equal(t, "mb-1", tracks[0].MusicBrainzID)
+	assert.Equal(t, "https://last.fm/1", tracks[0].ExternalID)
+	assert.Equal(t, "Song 2", tracks[1].Name)
+}
+
+func TestGetPlaylistTracks_TopPeriod(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "user.gettoptracks", q.Get("method"))
+		assert.Equal(t, "1month", q.Get("period"))
+		assert.Equal(t, "100", q.Get("limit"))
+		fmt.Fprint(w, `{"toptracks":{"track":[{"name":"Hit","artist":{"name":"Band"}}]}}`)
+	})
+
+	tracks, err := p.GetPlaylistTracks(context.Background(), "alice", "top-1month")
+	require.NoError(t, err)
+
+	require.Len(t, tracks, 1)
+	assert.Equal(t, "Hit", tracks[0].Name)
+	assert.Equal(t, []string{"Band"}, tracks[0].Artists)
+}
+
+func TestGetPlaylist_TopYear(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "user.getweeklytrackchart", q.Get("method"))
+		assert.Equal(t, "1672531200", q.Get("from"))
+		assert.Equal(t, "1704067200", q.Get("to"))
+		fmt.Fprint(w, `{"weeklytrackchart":{"track":[`)
+		for i := range 150 {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"name":"Track %d","artist":{"#text":"Band"}}`, i)
+		}
+		fmt.Fprint(w, `]}}`)
+	})
+
+	playlist, err := p.GetPlaylist(context.Background(), "alice", "top-2023")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Top 100 tracks of 2023", playlist.Name)
+	assert.Equal(t, 100, playlist.TrackCount)
+
+	tracks, err := p.GetPlaylistTracks(context.Background(), "alice", "top-2023")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Band"}, tracks[0].Artists)
+}
+
+func TestGetPlaylistTracks_Errors(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("user") {
+		case "nobody":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":6,"message":"User not found"}`)
+		default:
+			fmt.Fprint(w, `{"error":29,"message":"Rate limit exceeded"}`)
+		}
+	})
+	ctx := context.Background()
+
+	_, err := p.GetPlaylistTracks(ctx, "nobody", LovedPlaylistID)
+	assert.ErrorIs(t, err, domain.ErrPlaylistNotFound)
+
+	_, err = p.GetPlaylistTracks(ctx, "busy", "top-overall")
+	assert.ErrorIs(t, err, domain.ErrRateLimited)
+
+	for _, id := range []string{"top-forever", "top-99", "recent"} {
+		_, err = p.GetPlaylistTracks(ctx, "alice", id)
+		assert.ErrorIs(t, err, domain.ErrPlaylistNotFound, id)
+	}
+}
+
+func TestErrorsRedactAPIKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprintf(w, "<html>upstream failed for %s</html>", r.URL)
+	}))
+	p := NewProvider(srv.Client(), "secret-key")
+	p.baseURL = srv.URL
+	ctx := context.Background()
+
+	_, err := p.GetPlaylistTracks(ctx, "alice", LovedPlaylistID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 502")
+	assert.NotContains(t, err.Error(), "secret-key", "response body")
+
+	srv.Close()
+	_, err = p.GetPlaylistTracks(ctx, "alice", LovedPlaylistID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "api_key=REDACTED")
+	assert.NotContains(t, err.Error(), "secret-key", "request URL")
+}
+
+func TestSourceOnly(t *testing.T) {
+	p := NewProvider(nil, "key")
+	ctx := context.Background()
+
+	_, _, err := p.SearchTrack(ctx, "alice", domain.Track{Name: "x"})
+	assert.ErrorIs(t, err, domain.ErrSourceOnlyProvider)
+	_, err = p.CreatePlaylist(ctx, "alice", "x", "")
+	assert.ErrorIs(t, err, domain.ErrSourceOnlyProvider)
+	outcomes, err := p.AddTracksToPlaylist(ctx, "alice", "x", []string{"a"})
+	assert.ErrorIs(t, err, domain.ErrSourceOnlyProvider)
+	require.Len(t, outcomes, 1)
+}
@@ -0,0 +1,300 @@
+// Package localfiles provides a source-only provider that reads a directory
+// of MP3 and FLAC files, so a ripped library can be migrated to a streaming
+// service.
+package localfiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// ProviderName is the name the local file provider registers under.
+const ProviderName = "localfiles"
+
+// LibraryPlaylistID is the playlist holding every file in the library. Each
+// top-level subdirectory is also exposed as a playlist, under its name.
+const LibraryPlaylistID = "library"
+
+// Provider implements ports.MusicProvider, ports.SourceOnly and
+// ports.AudioSource over a directory tree. Tags are read on every call, so
+// changes to the library show up without a restart. Tokens are ignored.
+type Provider struct {
+	root string
+}
+
+// NewProvider creates a provider serving the audio files below root.
+func NewProvider(root string) *Provider {
+	return &Provider{root: root}
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// SourceOnly implements ports.SourceOnly.
+func (p *Provider) SourceOnly() {}
+
+func (p *Provider) GetPlaylists(ctx context.Context, token string) ([]domain.Playlist, error) {
+	page, err := p.GetPlaylistsPage(ctx, token, domain.PageRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+func (p *Provider) GetPlaylistsPage(_ context.Context, _ string, page domain.PageRequest) (*domain.PlaylistPage, error) {
+	offset := 0
+	if page.Cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(page.Cursor); err != nil || offset < 0 {
+			return nil, fmt.Errorf("localfiles: %w %q", domain.ErrInvalidCursor, page.Cursor)
+		}
+	}
+
+	files, err := p.scan(".")
+	if err != nil {
+		return nil, err
+	}
+
+	playlists := []domain.Playlist{{
+		ID:         LibraryPlaylistID,
+		Name:       filepath.Base(p.root),
+		OwnerName:  "Local files",
+		TrackCount: len(files),
+		IsOwner:    true,
+	}}
+	byDir := make(map[string]int)
+	for _, f := range files {
+		dir, _, ok := strings.Cut(f, "/")
+		if !ok {
+			continue
+		}
+		if _, seen := byDir[dir]; !seen {
+			byDir[dir] = len(playlists)
+			playlists = append(playlists, domain.Playlist{ID: dir, Name: dir, OwnerName: "Local files", IsOwner: true})
+		}
+		playlists[byDir[dir]].TrackCount++
+	}
+
+	offset = min(offset, len(playlists))
+	end := len(playlists)
+	if page.Limit > 0 {
+		end = min(offset+page.Limit, len(playlists))
+	}
+	result := &domain.PlaylistPage{Items: playlists[offset:end], Total: len(playlists)}
+	if end < len(playlists) {
+		result.NextCursor = strconv.Itoa(end)
+	}
+	return result, nil
+}
+
+func (p *Provider) GetPlaylist(_ context.Context, _ string, playlistID string) (*domain.Playlist, error) {
+	dir, err := playlistDir(playlistID)
+	if err != nil {
+		return nil, err
+	}
+	files, err := p.scan(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	name := playlistID
+	if playlistID == LibraryPlaylistID {
+		name = filepath.Base(p.root)
+	}
+	return &domain.Playlist{
+		ID:         playlistID,
+		Name:       name,
+		OwnerName:  "Local files",
+		TrackCount: len(files),
+		IsOwner:    true,
+	}, nil
+}
+
+func (p *Provider) GetPlaylistTracks(ctx context.Context, _ string, playlistID string) ([]domain.Track, error) {
+	dir, err := playlistDir(playlistID)
+	if err != nil {
+		return nil, err
+	}
+	files, err := p.scan(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]domain.Track, 0, len(files))
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, p.readTrack(f))
+	}
+	return tracks, nil
+}
+
+func (p *Provider) SearchTrack(_ context.Context, _ string, _ domain.Track) (*domain.Track, float64, error) {
+	return nil, 0, fmt.Errorf("localfiles: %w", domain.ErrSourceOnlyProvider)
+}
+
+// OpenAudio implements ports.AudioSource by opening the file of track.
+func (p *Provider) OpenAudio(_ context.Context, _ string, track domain.Track) (io.ReadCloser, error) {
+	if !fs.ValidPath(track.ExternalID) || !isAudioFile(track.ExternalID) {
+		return nil, fmt.Errorf("localfiles: %w", domain.ErrNoAudio)
+	}
+	f, err := os.Open(filepath.Join(p.root, filepath.FromSlash(track.ExternalID)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("localfiles: %w", domain.ErrNoAudio)
+	}
+	return f, err
+}
+
+func (p *Provider) CreatePlaylist(_ context.Context, _ string, _ string, _ string) (string, error) {
+	return "", fmt.Errorf("localfiles: %w", domain.ErrSourceOnlyProvider)
+}
+
+func (p *Provider) AddTracksToPlaylist(_ context.Context, _ string, _ string, trackIDs []string) ([]domain.AddOutcome, error) {
+	err := fmt.Errorf("localfiles: %w", domain.ErrSourceOnlyProvider)
+	return domain.NotAdded(nil, trackIDs, err), err
+}
+
+func (p *Provider) RemoveTracksFromPlaylist(_ context.Context, _ string, _ string, _ []string) error {
+	return fmt.Errorf("localfiles: %w", domain.ErrSourceOnlyProvider)
+}
+
+func (p *Provider) UpdatePlaylistDetails(_ context.Context, _ string, _ string, _ domain.PlaylistUpdate) error {
+	return fmt.Errorf("localfiles: %w", domain.ErrSourceOnlyProvider)
+}
+
+func (p *Provider) DeletePlaylist(_ context.Context, _ string, _ string) error {
+	return fmt.Errorf("localfiles: %w", domain.ErrSourceOnlyProvider)
+}
+
+// -- Scanning ----------------------------------------------------------------
+
+// playlistDir maps a playlist ID to the directory it covers, relative to the
+// library root. Only top-level directories are playlists.
+func playlistDir(playlistID string) (string, error) {
+	if playlistID == LibraryPlaylistID {
+		return ".", nil
+	}
+	if playlistID == "" || playlistID == "." || playlistID == ".." ||
+		strings.ContainsAny(playlistID, `/\`) || strings.HasPrefix(playlistID, ".") {
+		return "", domain.ErrPlaylistNotFound
+	}
+	return playlistID, nil
+}
+
+// scan returns the slash-separated paths, relative to the root, of the audio
+// files below dir in lexical order. Hidden files and directories are skipped.
+func (p *Provider) scan(dir string) ([]string, error) {
+	var files []string
+	start := filepath.Join(p.root, dir)
+	if info, err := os.Stat(start); err == nil && !info.IsDir() {
+		return nil, domain.ErrPlaylistNotFound
+	}
+	err := filepath.WalkDir(start, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && fullPath != start {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !isAudioFile(d.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(p.root, fullPath)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) && dir != "." {
+		return nil, domain.ErrPlaylistNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("localfiles: failed to scan library: %w", err)
+	}
+	return files, nil
+}
+
+func isAudioFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".mp3", ".flac":
+		return true
+	default:
+		return false
+	}
+}
+
+// readTrack builds the track of the file at the slash-separated relative path
+// rel. Missing tags are filled in from the file name; unreadable files still
+// produce a track so they show up as unmatched rather than disappearing.
+func (p *Provider) readTrack(rel string) domain.Track {
+	t, _ := p.readTags(rel)
+	if t == nil {
+		t = &tags{}
+	}
+
+	track := domain.Track{
+		Name:          t.title,
+		Artists:       t.artists,
+		Album:         t.album,
+		ISRC:          strings.ToUpper(strings.ReplaceAll(t.isrc, "-", "")),
+		MusicBrainzID: t.musicBrainzID,
+		ReleaseDate:   t.releaseDate,
+		Genres:        t.genres,
+		ExternalID:    rel,
+		Type:          domain.ItemTypeTrack,
+	}
+	if track.Name == "" || len(track.Artists) == 0 {
+		artist, title := parseFileName(path.Base(rel))
+		if track.Name == "" {
+			track.Name = title
+		}
+		if len(track.Artists) == 0 && artist != "" {
+			track.Artists = []string{artist}
+		}
+	}
+	return track
+}
+
+func (p *Provider) readTags(rel string) (*tags, error) {
+	f, err := os.Open(filepath.Join(p.root, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(path.Ext(rel), ".flac") {
+		return readFLAC(f)
+	}
+	return readID3v2(f)
+}
+
+// trackNumberPrefix matches leading track numbers such as "01 - " or "3. ".
+var trackNumberPrefix = regexp.MustCompile(`^\d{1,3}\s*[-._)]?\s+`)
+
+// parseFileName derives an artist and title from a file name of the form
+// "[NN - ]Artist - Title.ext". The artist is empty if there is no separator.
+func parseFileName(name string) (artist, title string) {
+	name = strings.TrimSuffix(name, path.Ext(name))
+	name = trackNumberPrefix.ReplaceAllString(name, "")
+	if a, t, ok := strings.Cut(name, " - "); ok {
+		return strings.TrimSpace(a), strings.TrimSpace(t)
+	}
+	return "", strings.TrimSpace(name)
+}
@@ -0,0 +1,242 @@
+package localfiles
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// -- Fixtures ----------------------------------------------------------------
+
+// id3Frame encodes an ID3v2.3 (size as plain integer) or 2.4 (syncsafe) frame.
+func id3Frame(version byte, id string, data []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString(id)
+	size := make([]byte, 4)
+	if version == 4 {
+		n := len(data)
+		size = []byte{byte(n >> 21 & 0x7f), byte(n >> 14 & 0x7f), byte(n >> 7 & 0x7f), byte(n & 0x7f)}
+	} else {
+		binary.BigEndian.PutUint32(size, uint32(len(data)))
+	}
+	b.Write(size)
+	b.Write([]byte{0, 0})
+	b.Write(data)
+	return b.Bytes()
+}
+
+func utf8Text(values ...string) []byte {
+	var b bytes.Buffer
+	b.WriteByte(3)
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(0)
+		}
+		b.WriteString(v)
+	}
+	return b.Bytes()
+}
+
+// utf16Text encodes a UTF-16 text frame with a little-endian byte order mark.
+func utf16Text(value string) []byte {
+	b := []byte{1, 0xff, 0xfe}
+	for _, r := range value {
+		b = append(b, byte(r), byte(r>>8))
+	}
+	return b
+}
+
+func id3File(version byte, frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+	body = append(body, make([]byte, 16)...) // padding
+	n := len(body)
+	header := []byte{'I', 'D', '3', version, 0, 0,
+		byte(n >> 21 & 0x7f), byte(n >> 14 & 0x7f), byte(n >> 7 & 0x7f), byte(n & 0x7f)}
+	return append(append(header, body...), 0xff, 0xfb, 0x90, 0x00) // start of an MPEG frame
+}
+
+func flacFile(comments ...string) []byte {
+	var block bytes.Buffer
+	le := func(n int) { binary.Write(&block, binary.LittleEndian, uint32(n)) }
+	le(len("test"))
+	block.WriteString("test")
+	le(len(comments))
+	for _, c := range comments {
+		le(len(c))
+		block.WriteString(c)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("fLaC")
+	b.Write([]byte{0, 0, 0, 34}) // STREAMINFO
+	b.Write(make([]byte, 34))
+	n := block.Len()
+	b.Write([]byte{0x80 | 4, byte(n >> 16), byte(n >> 8), byte(n)})
+	b.Write(block.Bytes())
+	return b.Bytes()
+}
+
+func writeFile(t *testing.T, root, rel string, data []byte) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+}
+
+func newLibrary(t *testing.T) string {
+	root := t.TempDir()
+	writeFile(t, root, "Rock/01 Queen - Bohemian Rhapsody.mp3", id3File(3,
+		id3Frame(3, "TIT2", utf16Text("Bohemian Rhapsody")),
+		id3Frame(3, "TPE1", utf16Text("Queen")),
+		id3Frame(3, "TALB", []byte("\x00A Night at the Opera")),
+		id3Frame(3, "TSRC", []byte("\x00GB-UM7-10-29604")),
+		id3Frame(3, "TYER", []byte("\x001975")),
+		id3Frame(3, "TCON", []byte("\x00(17)Rock")),
+		id3Frame(3, "UFID", []byte("http://musicbrainz.org\x00b1a9c0e9-d987-4042-ae91-78d6a3267d69")),
+	))
+	writeFile(t, root, "Rock/Nirvana - Smells Like Teen Spirit.flac", flacFile(
+		"TITLE=Smells Like Teen Spirit",
+		"ARTIST=Nirvana",
+		"ALBUM=Nevermind",
+		"ISRC=USGF19942501",
+		"DATE=1991-09-10",
+		"GENRE=Grunge",
+		"GENRE=Alternative Rock",
+		"MUSICBRAINZ_TRACKID=4c2ea5a6-2a5e-4b4e-9d1b-1b3f0d2e6d51",
+	))
+	writeFile(t, root, "Dance/Get Lucky.mp3", id3File(4,
+		id3Frame(4, "TIT2", utf8Text("Get Lucky")),
+		id3Frame(4, "TPE1", utf8Text("Daft Punk", "Pharrell Williams")),
+		id3Frame(4, "TDRC", utf8Text("2013-04-19T00:00")),
+		id3Frame(4, "TCON", utf8Text("8", "Disco")),
+	))
+	writeFile(t, root, "03 - Daft Punk - One More Time.mp3", []byte("not tagged"))
+	writeFile(t, root, "Rock/cover.jpg", []byte("jpeg"))
+	writeFile(t, root, ".trash/Deleted - Song.mp3", []byte("not tagged"))
+	return root
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestGetPlaylists(t *testing.T) {
+	p := NewProvider(newLibrary(t))
+
+	playlists, err := p.GetPlaylists(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, playlists, 3)
+	assert.Equal(t, LibraryPlaylistID, playlists[0].ID)
+	assert.Equal(t, 4, playlists[0].TrackCount)
+	assert.Equal(t, "Dance", playlists[1].ID)
+	assert.Equal(t, 1, playlists[1].TrackCount)
+	assert.Equal(t, "Rock", playlists[2].ID)
+	assert.Equal(t, 2, playlists[2].TrackCount)
+}
+
+func TestGetPlaylistTracks_ReadsTags(t *testing.T) {
+	p := NewProvider(newLibrary(t))
+
+	tracks, err := p.GetPlaylistTracks(context.Background(), "", "Rock")
+	require.NoError(t, err)
+	require.Len(t, tracks, 2)
+
+	assert.Equal(t, domain.Track{
+		Name:          "Bohemian Rhapsody",
+		Artists:       []string{"Queen"},
+		Album:         "A Night at the Opera",
+		ISRC:          "GBUM71029604",
+		MusicBrainzID: "b1a9c0e9-d987-4042-ae91-78d6a3267d69",
+		ReleaseDate:   "1975",
+		Genres:        []string{"Rock"},
+		ExternalID:    "Rock/01 Queen - Bohemian Rhapsody.mp3",
+		Type:          domain.ItemTypeTrack,
+	}, tracks[0])
+
+	assert.Equal(t, "Smells Like Teen Spirit", tracks[1].Name)
+	assert.Equal(t, []string{"Nirvana"}, tracks[1].Artists)
+	assert.Equal(t, "Nevermind", tracks[1].Album)
+	assert.Equal(t, "USGF19942501", tracks[1].ISRC)
+	assert.Equal(t, "4c2ea5a6-2a5e-4b4e-9d1b-1b3f0d2e6d51", tracks[1].MusicBrainzID)
+	assert.Equal(t, "1991-09-10", tracks[1].ReleaseDate)
+	assert.Equal(t, []string{"Grunge", "Alternative Rock"}, tracks[1].Genres)
+}
+
+func TestGetPlaylistTracks_MultipleArtistsAndFileNames(t *testing.T) {
+	p := NewProvider(newLibrary(t))
+
+	tracks, err := p.GetPlaylistTracks(context.Background(), "", LibraryPlaylistID)
+	require.NoError(t, err)
+	require.Len(t, tracks, 4)
+
+	// Untagged files fall back to "NN - Artist - Title".
+	assert.Equal(t, "One More Time", tracks[0].Name)
+	assert.Equal(t, []string{"Daft Punk"}, tracks[0].Artists)
+
+	assert.Equal(t, "Get Lucky", tracks[1].Name)
+	assert.Equal(t, []string{"Daft Punk", "Pharrell Williams"}, tracks[1].Artists)
+	assert.Equal(t, "2013-04-19", tracks[1].ReleaseDate)
+	assert.Equal(t, []string{"Disco"}, tracks[1].Genres, "ID3v1 genre references are dropped")
+}
+
+func TestGetPlaylist_NotFound(t *testing.T) {
+	p := NewProvider(newLibrary(t))
+
+	for _, id := range []string{"Jazz", "../etc", ".trash", "03 - Daft Punk - One More Time.mp3"} {
+		_, err := p.GetPlaylist(context.Background(), "", id)
+		assert.ErrorIs(t, err, domain.ErrPlaylistNotFound, id)
+	}
+}
+
+func TestOpenAudio(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "Rock"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "Rock", "song.mp3"), []byte("audio"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "notes.txt"), []byte("text"), 0o644))
+	p := NewProvider(root)
+
+	r, err := p.OpenAudio(context.Background(), "", domain.Track{ExternalID: "Rock/song.mp3"})
+	require.NoError(t, err)
+	audio, err := io.ReadAll(r)
+	require.NoError(t, r.Close())
+	require.NoError(t, err)
+	assert.Equal(t, "audio", string(audio))
+
+	for _, id := range []string{"Rock/missing.mp3", "notes.txt", "../song.mp3", ""} {
+		_, err := p.OpenAudio(context.Background(), "", domain.Track{ExternalID: id})
+		assert.ErrorIs(t, err, domain.ErrNoAudio, id)
+	}
+}
+
+func TestSourceOnly(t *testing.T) {
+	p := NewProvider(t.TempDir())
+
+	_, _, err := p.SearchTrack(context.Background(), "", domain.Track{Name: "x"})
+	assert.ErrorIs(t, err, domain.ErrSourceOnlyProvider)
+
+	outcomes, err := p.AddTracksToPlaylist(context.Background(), "", LibraryPlaylistID, []string{"a"})
+	assert.ErrorIs(t, err, domain.ErrSourceOnlyProvider)
+	assert.False(t, outcomes[0].Added)
+}
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		name, artist, title string
+	}{
+		{"Queen - Bohemian Rhapsody.mp3", "Queen", "Bohemian Rhapsody"},
+		{"01 - Queen - Bohemian Rhapsody.mp3", "Queen", "Bohemian Rhapsody"},
+		{"07. AC-DC - Back in Black.flac", "AC-DC", "Back in Black"},
+		{"Interlude.mp3", "", "Interlude"},
+	}
+	for _, tt := range tests {
+		artist, title := parseFileName(tt.name)
+		assert.Equal(t, tt.artist, artist, tt.name)
+		assert.Equal(t, tt.title, title, tt.name)
+	}
+}
@@ -0,0 +1,327 @@
+package localfiles
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// tags holds the metadata read from an audio file. Empty fields were not
+// present in the file.
+type tags struct {
+	title         string
+	artists       []string
+	album         string
+	isrc          string
+	musicBrainzID string
+	releaseDate   string
+	genres        []string
+}
+
+// musicBrainzOwner identifies the MusicBrainz recording ID in ID3 UFID frames.
+const musicBrainzOwner = "http://musicbrainz.org"
+
+var errNoTags = errors.New("no supported tags")
+
+// -- ID3v2 (MP3) -------------------------------------------------------------
+
+// readID3v2 parses an ID3v2.2, 2.3 or 2.4 tag at the start of r.
+func readID3v2(r io.Reader) (*tags, error) {
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if string(header[:3]) != "ID3" {
+		return nil, errNoTags
+	}
+	version := header[3]
+	flags := header[5]
+	size := syncsafe(header[6:10])
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	if flags&0x80 != 0 && version < 4 {
+		body = removeUnsync(body)
+	}
+	if flags&0x40 != 0 && version >= 3 && len(body) >= 4 {
+		// Skip the extended header. Its size excludes itself in 2.3 and
+		// includes itself (syncsafe) in 2.4.
+		ext := int(binary.BigEndian.Uint32(body[:4])) + 4
+		if version == 4 {
+			ext = syncsafe(body[:4])
+		}
+		body = body[min(ext, len(body)):]
+	}
+
+	t := &tags{}
+	for len(body) > 0 {
+		var id string
+		var frameSize, headerSize int
+		var frameFlags uint16
+		if version == 2 {
+			if len(body) < 6 {
+				break
+			}
+			id = string(body[:3])
+			frameSize = int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+			headerSize = 6
+		} else {
+			if len(body) < 10 {
+				break
+			}
+			id = string(body[:4])
+			if version == 4 {
+				frameSize = syncsafe(body[4:8])
+			} else {
+				frameSize = int(binary.BigEndian.Uint32(body[4:8]))
+			}
+			frameFlags = binary.BigEndian.Uint16(body[8:10])
+			headerSize = 10
+		}
+		if id[0] == 0 || frameSize <= 0 || headerSize+frameSize > len(body) {
+			break // padding or a corrupt frame
+		}
+		data := body[headerSize : headerSize+frameSize]
+		body = body[headerSize+frameSize:]
+
+		if version == 4 && frameFlags&0x0002 != 0 {
+			data = removeUnsync(data)
+		}
+		if (version == 4 && frameFlags&0x000c != 0) || (version == 3 && frameFlags&0x00c0 != 0) {
+			continue // compressed or encrypted frames are not supported
+		}
+
+		switch id {
+		case "TIT2", "TT2":
+			t.title = firstOf(textValues(data))
+		case "TPE1", "TP1":
+			// ID3v2.4 stores several artists as null-separated values.
+			t.artists = textValues(data)
+		case "TALB", "TAL":
+			t.album = firstOf(textValues(data))
+		case "TSRC", "TRC":
+			t.isrc = firstOf(textValues(data))
+		case "TCON", "TCO":
+			t.genres = id3Genres(textValues(data))
+		case "TDRC", "TYER", "TYE":
+			// ID3v2.4 has a recording timestamp, earlier versions only the year.
+			t.releaseDate = dateOf(firstOf(textValues(data)))
+		case "UFID", "UFI":
+			if owner, id, ok := bytes.Cut(data, []byte{0}); ok && string(owner) == musicBrainzOwner {
+				t.musicBrainzID = string(id)
+			}
+		case "TXXX", "TXX":
+			values := textValues(data)
+			if len(values) == 2 && strings.EqualFold(values[0], "MusicBrainz Track Id") && t.musicBrainzID == "" {
+				t.musicBrainzID = values[1]
+			}
+		}
+	}
+	return t, nil
+}
+
+// syncsafe decodes a 4-byte integer that uses 7 bits per byte.
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7f)<<21 | int(b[1]&0x7f)<<14 | int(b[2]&0x7f)<<7 | int(b[3]&0x7f)
+}
+
+// removeUnsync reverses ID3 unsynchronisation, which inserts a zero byte
+// after every 0xFF.
+func removeUnsync(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte{0xff, 0x00}, []byte{0xff})
+}
+
+// textValues decodes an ID3 text frame into its null-separated values.
+func textValues(data []byte) []string {
+	if len(data) < 1 {
+		return nil
+	}
+	encoding, data := data[0], data[1:]
+
+	var text string
+	switch encoding {
+	case 0: // ISO-8859-1
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		text = string(runes)
+	case 1, 2: // UTF-16 with BOM, UTF-16BE
+		text = decodeUTF16(data, encoding == 2)
+	default: // UTF-8
+		text = string(data)
+	}
+
+	var values []string
+	for _, v := range strings.Split(text, "\x00") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// decodeUTF16 decodes UTF-16 text. Every value may start with its own byte
+// order mark; without one, bigEndian picks the order.
+func decodeUTF16(data []byte, bigEndian bool) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		switch {
+		case data[i] == 0xff && data[i+1] == 0xfe:
+			bigEndian = false
+			continue
+		case data[i] == 0xfe && data[i+1] == 0xff:
+			bigEndian = true
+			continue
+		}
+		if bigEndian {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		} else {
+			units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// -- FLAC Vorbis comments ----------------------------------------------------
+
+// readFLAC parses the Vorbis comment block of a FLAC stream.
+func readFLAC(r io.Reader) (*tags, error) {
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != "fLaC" {
+		return nil, errNoTags
+	}
+
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, err
+		}
+		last := header[0]&0x80 != 0
+		blockType := header[0] & 0x7f
+		size := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+
+		const vorbisComment = 4
+		if blockType != vorbisComment {
+			if _, err := io.CopyN(io.Discard, r, int64(size)); err != nil {
+				return nil, err
+			}
+			if last {
+				return nil, errNoTags
+			}
+			continue
+		}
+
+		block := make([]byte, size)
+		if _, err := io.ReadFull(r, block); err != nil {
+			return nil, err
+		}
+		return parseVorbisComments(block)
+	}
+}
+
+// parseVorbisComments reads the KEY=value comments of a Vorbis comment block.
+func parseVorbisComments(block []byte) (*tags, error) {
+	next := func() ([]byte, bool) {
+		if len(block) < 4 {
+			return nil, false
+		}
+		n := int(binary.LittleEndian.Uint32(block[:4]))
+		if n > len(block)-4 {
+			return nil, false
+		}
+		v := block[4 : 4+n]
+		block = block[4+n:]
+		return v, true
+	}
+
+	if _, ok := next(); !ok { // vendor string
+		return nil, errNoTags
+	}
+	if len(block) < 4 {
+		return nil, errNoTags
+	}
+	count := int(binary.LittleEndian.Uint32(block[:4]))
+	block = block[4:]
+
+	t := &tags{}
+	for i := 0; i < count; i++ {
+		comment, ok := next()
+		if !ok {
+			break
+		}
+		key, value, ok := strings.Cut(string(comment), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToUpper(key) {
+		case "TITLE":
+			t.title = value
+		case "ARTIST":
+			t.artists = append(t.artists, value)
+		case "ALBUM":
+			t.album = value
+		case "ISRC":
+			t.isrc = value
+		case "GENRE":
+			if value != "" {
+				t.genres = append(t.genres, value)
+			}
+		case "DATE":
+			t.releaseDate = dateOf(value)
+		case "MUSICBRAINZ_TRACKID":
+			t.musicBrainzID = value
+		}
+	}
+	return t, nil
+}
+
+// id3Genres returns the genre names of a content type frame. Earlier ID3
+// versions refer to the ID3v1 genre list as "(17)" or "17", optionally
+// followed by a name; bare references are dropped, as they name no genre.
+func id3Genres(values []string) []string {
+	var genres []string
+	for _, value := range values {
+		if strings.HasPrefix(value, "(") {
+			if end := strings.IndexByte(value, ')'); end > 0 && isDigits(value[1:end]) {
+				value = value[end+1:]
+			}
+		}
+		if value = strings.TrimSpace(value); value != "" && !isDigits(value) {
+			genres = append(genres, value)
+		}
+	}
+	return genres
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func firstOf(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// dateOf returns the date of a timestamp such as "2008-11-11T10:00", in
+// the YYYY, YYYY-MM or YYYY-MM-DD form of domain.Track.
+func dateOf(timestamp string) string {
+	date, _, _ := strings.Cut(timestamp, "T")
+	return date
+}
@@ -0,0 +1,296 @@
+// Package m3u provides a source-only provider that reads M3U and M3U8
+// playlist files, as exported by desktop players such as VLC or foobar2000.
+package m3u
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// ProviderName is the name the M3U provider registers under.
+const ProviderName = "m3u"
+
+// uploadPrefix starts the IDs of uploaded playlists, so they cannot clash
+// with file names.
+const uploadPrefix = "upload-"
+
+// Provider implements ports.MusicProvider, ports.SourceOnly and
+// ports.PlaylistImporter. Its playlists are the .m3u and .m3u8 files in an
+// optional directory plus files uploaded through ImportPlaylist, which are
+// kept in memory and only visible to the account that uploaded them. Tokens
+// are ignored. It is safe for concurrent use.
+type Provider struct {
+	dir string
+
+	mu      sync.Mutex
+	uploads map[string]*upload
+}
+
+type upload struct {
+	accountID string
+	playlist  domain.Playlist
+	tracks    []domain.Track
+}
+
+// Option configures optional behavior of a Provider.
+type Option func(*Provider)
+
+// WithDirectory serves the playlist files in dir, referenced by file name.
+func WithDirectory(dir string) Option {
+	return func(p *Provider) {
+		p.dir = dir
+	}
+}
+
+// NewProvider creates an M3U provider.
+func NewProvider(opts ...Option) *Provider {
+	p := &Provider{uploads: make(map[string]*upload)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// SourceOnly implements ports.SourceOnly.
+func (p *Provider) SourceOnly() {}
+
+// ImportPlaylist implements ports.PlaylistImporter. name is used when the
+// file has no #PLAYLIST directive.
+func (p *Provider) ImportPlaylist(ctx context.Context, name string, r io.Reader) (*domain.Playlist, error) {
+	fileName, tracks, err := Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("m3u: %w: %v", domain.ErrInvalidPlaylistFile, err)
+	}
+	if len(tracks) == 0 {
+		return nil, fmt.Errorf("m3u: %w: no entries", domain.ErrInvalidPlaylistFile)
+	}
+	if fileName != "" {
+		name = fileName
+	}
+	if name == "" {
+		name = "Imported playlist"
+	}
+
+	b := make([]byte, 12)
+	_, _ = rand.Read(b) // crypto/rand.Read never returns an error
+	u := &upload{
+		accountID: domain.AccountIDFromContext(ctx),
+		playlist: domain.Playlist{
+			ID:         uploadPrefix + hex.EncodeToString(b),
+			Name:       name,
+			OwnerName:  "Uploaded",
+			TrackCount: len(tracks),
+			IsOwner:    true,
+		},
+		tracks: tracks,
+	}
+
+	p.mu.Lock()
+	p.uploads[u.playlist.ID] = u
+	p.mu.Unlock()
+
+	playlist := u.playlist
+	playlist.Tracks = tracks
+	return &playlist, nil
+}
+
+func (p *Provider) GetPlaylists(ctx context.Context, token string) ([]domain.Playlist, error) {
+	page, err := p.GetPlaylistsPage(ctx, token, domain.PageRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+func (p *Provider) GetPlaylistsPage(ctx context.Context, _ string, page domain.PageRequest) (*domain.PlaylistPage, error) {
+	offset := 0
+	if page.Cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(page.Cursor); err != nil || offset < 0 {
+			return nil, fmt.Errorf("m3u: %w %q", domain.ErrInvalidCursor, page.Cursor)
+		}
+	}
+
+	playlists, err := p.filePlaylists()
+	if err != nil {
+		return nil, err
+	}
+	playlists = append(playlists, p.accountUploads(ctx)...)
+
+	offset = min(offset, len(playlists))
+	end := len(playlists)
+	if page.Limit > 0 {
+		end = min(offset+page.Limit, len(playlists))
+	}
+	result := &domain.PlaylistPage{Items: playlists[offset:end], Total: len(playlists)}
+	if end < len(playlists) {
+		result.NextCursor = strconv.Itoa(end)
+	}
+	return result, nil
+}
+
+func (p *Provider) GetPlaylist(ctx context.Context, _ string, playlistID string) (*domain.Playlist, error) {
+	playlist, _, err := p.load(ctx, playlistID)
+	return playlist, err
+}
+
+func (p *Provider) GetPlaylistTracks(ctx context.Context, _ string, playlistID string) ([]domain.Track, error) {
+	_, tracks, err := p.load(ctx, playlistID)
+	return tracks, err
+}
+
+func (p *Provider) SearchTrack(_ context.Context, _ string, _ domain.Track) (*domain.Track, float64, error) {
+	return nil, 0, fmt.Errorf("m3u: %w", domain.ErrSourceOnlyProvider)
+}
+
+func (p *Provider) CreatePlaylist(_ context.Context, _ string, _ string, _ string) (string, error) {
+	return "", fmt.Errorf("m3u: %w", domain.ErrSourceOnlyProvider)
+}
+
+func (p *Provider) AddTracksToPlaylist(_ context.Context, _ string, _ string, trackIDs []string) ([]domain.AddOutcome, error) {
+	err := fmt.Errorf("m3u: %w", domain.ErrSourceOnlyProvider)
+	return domain.NotAdded(nil, trackIDs, err), err
+}
+
+func (p *Provider) RemoveTracksFromPlaylist(_ context.Context, _ string, _ string, _ []string) error {
+	return fmt.Errorf("m3u: %w", domain.ErrSourceOnlyProvider)
+}
+
+func (p *Provider) UpdatePlaylistDetails(_ context.Context, _ string, _ string, _ domain.PlaylistUpdate) error {
+	return fmt.Errorf("m3u: %w", domain.ErrSourceOnlyProvider)
+}
+
+// DeletePlaylist forgets an uploaded playlist. Files in the directory are
+// never deleted.
+func (p *Provider) DeletePlaylist(ctx context.Context, _ string, playlistID string) error {
+	if !strings.HasPrefix(playlistID, uploadPrefix) {
+		return fmt.Errorf("m3u: %w", domain.ErrSourceOnlyProvider)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	u, ok := p.uploads[playlistID]
+	if !ok || u.accountID != domain.AccountIDFromContext(ctx) {
+		return domain.ErrPlaylistNotFound
+	}
+	delete(p.uploads, playlistID)
+	return nil
+}
+
+// -- Helpers -----------------------------------------------------------------
+
+// load returns the metadata and tracks of an uploaded playlist or a file in
+// the directory.
+func (p *Provider) load(ctx context.Context, playlistID string) (*domain.Playlist, []domain.Track, error) {
+	if strings.HasPrefix(playlistID, uploadPrefix) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		u, ok := p.uploads[playlistID]
+		if !ok || u.accountID != domain.AccountIDFromContext(ctx) {
+			return nil, nil, domain.ErrPlaylistNotFound
+		}
+		playlist := u.playlist
+		return &playlist, u.tracks, nil
+	}
+
+	if p.dir == "" || !isPlaylistFile(playlistID) || strings.ContainsAny(playlistID, `/\`) {
+		return nil, nil, domain.ErrPlaylistNotFound
+	}
+	data, err := os.ReadFile(filepath.Join(p.dir, playlistID))
+	if os.IsNotExist(err) {
+		return nil, nil, domain.ErrPlaylistNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("m3u: failed to read %s: %w", playlistID, err)
+	}
+
+	name, tracks, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("m3u: failed to parse %s: %w", playlistID, err)
+	}
+	if name == "" {
+		name = strings.TrimSuffix(playlistID, filepath.Ext(playlistID))
+	}
+	return &domain.Playlist{
+		ID:         playlistID,
+		Name:       name,
+		OwnerName:  "Local files",
+		TrackCount: len(tracks),
+		IsOwner:    true,
+	}, tracks, nil
+}
+
+// filePlaylists lists the playlist files in the directory by name. Track
+// counts are left at zero to avoid parsing every file.
+func (p *Provider) filePlaylists() ([]domain.Playlist, error) {
+	if p.dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return nil, fmt.Errorf("m3u: failed to list %s: %w", p.dir, err)
+	}
+
+	var playlists []domain.Playlist
+	for _, e := range entries {
+		if e.IsDir() || !isPlaylistFile(e.Name()) {
+			continue
+		}
+		playlists = append(playlists, domain.Playlist{
+			ID:        e.Name(),
+			Name:      strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())),
+			OwnerName: "Local files",
+			IsOwner:   true,
+		})
+	}
+	return playlists, nil
+}
+
+// accountUploads returns the playlists uploaded by the account in ctx, by name.
+func (p *Provider) accountUploads(ctx context.Context) []domain.Playlist {
+	accountID := domain.AccountIDFromContext(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var playlists []domain.Playlist
+	for _, u := range p.uploads {
+		if u.accountID == accountID {
+			playlists = append(playlists, u.playlist)
+		}
+	}
+	sort.Slice(playlists, func(i, j int) bool {
+		if playlists[i].Name != playlists[j].Name {
+			return playlists[i].Name < playlists[j].Name
+		}
+		return playlists[i].ID < playlists[j].ID
+	})
+	return playlists
+}
+
+func isPlaylistFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".m3u", ".m3u8":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,157 @@
+package m3u
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const extendedM3U = `#EXTM3U
+#PLAYLIST:Road Trip
+#EXTINF:354,Queen - Bohemian Rhapsody
+/music/Queen/01 - Bohemian Rhapsody.mp3
+#EXTINF:-1 tvg-name="a, b",AC/DC - Back in Black
+#EXTALB:Back in Black
+C:\Music\AC-DC\Back in Black.flac
+#EXTINF:200,Untitled
+#EXTART:Daft Punk
+https://example.com/stream.mp3?id=1
+`
+
+func TestParse_ExtendedM3U(t *testing.T) {
+	name, tracks, err := Parse(strings.NewReader(extendedM3U))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Road Trip", name)
+	require.Len(t, tracks, 3)
+	assert.Equal(t, "Bohemian Rhapsody", tracks[0].Name)
+	assert.Equal(t, []string{"Queen"}, tracks[0].Artists)
+	assert.Equal(t, "/music/Queen/01 - Bohemian Rhapsody.mp3", tracks[0].ExternalID)
+	assert.Equal(t, domain.ItemTypeTrack, tracks[0].Type)
+	assert.Equal(t, 354000, tracks[0].DurationMS)
+
+	assert.Equal(t, "Back in Black", tracks[1].Name)
+	assert.Equal(t, []string{"AC/DC"}, tracks[1].Artists)
+	assert.Equal(t, "Back in Black", tracks[1].Album)
+	assert.Zero(t, tracks[1].DurationMS)
+
+	assert.Equal(t, "Untitled", tracks[2].Name)
+	assert.Equal(t, []string{"Daft Punk"}, tracks[2].Artists)
+}
+
+func TestParse_PlainM3UFallsBackToFileNames(t *testing.T) {
+	input := "\ufeff# comment\r\n03. Radiohead - Creep.mp3\r\n\r\nmusic\\Unknown.flac\r\n"
+
+	name, tracks, err := Parse(strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Empty(t, name)
+	require.Len(t, tracks, 2)
+	assert.Equal(t, "Creep", tracks[0].Name)
+	assert.Equal(t, []string{"Radiohead"}, tracks[0].Artists)
+	assert.Equal(t, "Unknown", tracks[1].Name)
+	assert.Empty(t, tracks[1].Artists)
+}
+
+func TestParse_Latin1(t *testing.T) {
+	input := []byte("#EXTINF:100,Bj\xf6rk - J\xf3ga\nJoga.mp3\n")
+
+	_, tracks, err := Parse(strings.NewReader(string(input)))
+	require.NoError(t, err)
+
+	require.Len(t, tracks, 1)
+	assert.Equal(t, "Jóga", tracks[0].Name)
+	assert.Equal(t, []string{"Björk"}, tracks[0].Artists)
+}
+
+func TestImportPlaylist(t *testing.T) {
+	p := NewProvider()
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-1"})
+
+	playlist, err := p.ImportPlaylist(ctx, "ignored", strings.NewReader(extendedM3U))
+	require.NoError(t, err)
+	assert.Equal(t, "Road Trip", playlist.Name)
+	assert.Equal(t, 3, playlist.TrackCount)
+	assert.Len(t, playlist.Tracks, 3)
+
+	tracks, err := p.GetPlaylistTracks(ctx, "", playlist.ID)
+	require.NoError(t, err)
+	assert.Len(t, tracks, 3)
+
+	playlists, err := p.GetPlaylists(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, playlists, 1)
+	assert.Equal(t, playlist.ID, playlists[0].ID)
+
+	// Other accounts cannot see the upload.
+	other := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-2"})
+	_, err = p.GetPlaylist(other, "", playlist.ID)
+	assert.ErrorIs(t, err, domain.ErrPlaylistNotFound)
+	playlists, err = p.GetPlaylists(other, "")
+	require.NoError(t, err)
+	assert.Empty(t, playlists)
+
+	require.NoError(t, p.DeletePlaylist(ctx, "", playlist.ID))
+	_, err = p.GetPlaylist(ctx, "", playlist.ID)
+	assert.ErrorIs(t, err, domain.ErrPlaylistNotFound)
+}
+
+func TestImportPlaylist_NameFallback(t *testing.T) {
+	p := NewProvider()
+
+	playlist, err := p.ImportPlaylist(context.Background(), "Mix", strings.NewReader("a.mp3\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "Mix", playlist.Name)
+}
+
+func TestImportPlaylist_NoEntries(t *testing.T) {
+	p := NewProvider()
+
+	_, err := p.ImportPlaylist(context.Background(), "", strings.NewReader("#EXTM3U\n"))
+	assert.ErrorIs(t, err, domain.ErrInvalidPlaylistFile)
+}
+
+func TestDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Road Trip.m3u8"), []byte(extendedM3U), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("a.mp3\n"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub.m3u"), 0o755))
+	p := NewProvider(WithDirectory(dir))
+	ctx := context.Background()
+
+	page, err := p.GetPlaylistsPage(ctx, "", domain.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "Road Trip.m3u8", page.Items[0].ID)
+
+	playlist, err := p.GetPlaylist(ctx, "", "Road Trip.m3u8")
+	require.NoError(t, err)
+	assert.Equal(t, "Road Trip", playlist.Name)
+	assert.Equal(t, 3, playlist.TrackCount)
+
+	for _, id := range []string{"notes.txt", "../Road Trip.m3u8", "missing.m3u"} {
+		_, err := p.GetPlaylistTracks(ctx, "", id)
+		assert.ErrorIs(t, err, domain.ErrPlaylistNotFound, id)
+	}
+	assert.ErrorIs(t, p.DeletePlaylist(ctx, "", "Road Trip.m3u8"), domain.ErrSourceOnlyProvider)
+}
+
+func TestSourceOnly(t *testing.T) {
+	p := NewProvider()
+	ctx := context.Background()
+
+	_, _, err := p.SearchTrack(ctx, "", domain.Track{Name: "x"})
+	assert.ErrorIs(t, err, domain.ErrSourceOnlyProvider)
+	_, err = p.CreatePlaylist(ctx, "", "x", "")
+	assert.ErrorIs(t, err, domain.ErrSourceOnlyProvider)
+	outcomes, err := p.AddTracksToPlaylist(ctx, "", "x", []string{"a"})
+	assert.ErrorIs(t, err, domain.ErrSourceOnlyProvider)
+	require.Len(t, outcomes, 1)
+	assert.False(t, outcomes[0].Added)
+}
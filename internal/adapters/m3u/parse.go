@@ -0,0 +1,136 @@
+package m3u
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// maxLineLength bounds a single line of a playlist file.
+const maxLineLength = 64 * 1024
+
+// Parse reads an M3U or M3U8 playlist. It returns the playlist name from a
+// #PLAYLIST directive, if any, and one track per entry. Track names and
+// artists come from #EXTINF ("Artist - Title"), #EXTART and #EXTALB, falling
+// back to the entry's file name. Files that are not valid UTF-8 are read as
+// Latin-1, the usual encoding of plain .m3u files.
+func Parse(r io.Reader) (name string, tracks []domain.Track, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", nil, err
+	}
+	text := string(data)
+	if !utf8.ValidString(text) {
+		text = latin1(data)
+	}
+	text = strings.TrimPrefix(text, "\ufeff")
+
+	var pending domain.Track
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 4096), maxLineLength)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			seconds, artist, title := parseExtInf(strings.TrimPrefix(line, "#EXTINF:"))
+			pending.Name = title
+			pending.DurationMS = seconds * 1000
+			if artist != "" {
+				pending.Artists = []string{artist}
+			}
+		case strings.HasPrefix(line, "#EXTART:"):
+			if artist := strings.TrimSpace(strings.TrimPrefix(line, "#EXTART:")); artist != "" {
+				pending.Artists = []string{artist}
+			}
+		case strings.HasPrefix(line, "#EXTALB:"):
+			pending.Album = strings.TrimSpace(strings.TrimPrefix(line, "#EXTALB:"))
+		case strings.HasPrefix(line, "#"):
+			continue // other directives and comments
+		default:
+			track := pending
+			pending = domain.Track{}
+			if track.Name == "" || len(track.Artists) == 0 {
+				artist, title := splitArtistTitle(entryName(line))
+				if track.Name == "" {
+					track.Name = title
+				}
+				if len(track.Artists) == 0 && artist != "" {
+					track.Artists = []string{artist}
+				}
+			}
+			track.ExternalID = line
+			track.Type = domain.ItemTypeTrack
+			tracks = append(tracks, track)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, err
+	}
+	return name, tracks, nil
+}
+
+// parseExtInf splits the value of an #EXTINF directive,
+// `duration [key="value" ...],Artist - Title`, into the duration in seconds
+// (0 if unknown, which players write as -1), artist and title.
+func parseExtInf(value string) (seconds int, artist, title string) {
+	if end := strings.IndexAny(value, " ,"); end > 0 {
+		if s, err := strconv.Atoi(value[:end]); err == nil && s > 0 {
+			seconds = s
+		}
+	}
+
+	// The display text follows the first comma outside quoted attributes.
+	inQuotes := false
+	for i, r := range value {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ',' && !inQuotes:
+			artist, title = splitArtistTitle(value[i+1:])
+			return seconds, artist, title
+		}
+	}
+	return seconds, "", ""
+}
+
+// trackNumberPrefix matches leading track numbers such as "01 - " or "3. ".
+var trackNumberPrefix = regexp.MustCompile(`^\d{1,3}\s*[-._)]?\s+`)
+
+// splitArtistTitle splits "Artist - Title". The artist is empty if there is
+// no separator.
+func splitArtistTitle(s string) (artist, title string) {
+	s = strings.TrimSpace(s)
+	if a, t, ok := strings.Cut(s, " - "); ok {
+		return strings.TrimSpace(a), strings.TrimSpace(t)
+	}
+	return "", s
+}
+
+// entryName returns the file name of an entry without its extension and
+// leading track number. Entries may be local paths (with either separator)
+// or URLs.
+func entryName(entry string) string {
+	entry, _, _ = strings.Cut(entry, "?")
+	entry = strings.ReplaceAll(entry, `\`, "/")
+	name := path.Base(entry)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	return trackNumberPrefix.ReplaceAllString(name, "")
+}
+
+func latin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
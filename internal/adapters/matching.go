@@ -0,0 +1,61 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/pkg/matching"
+)
+
+// MatchTrack converts a track to the metadata compared by the matching
+// package.
+func MatchTrack(t domain.Track) matching.Track {
+	m := matching.Track{
+		Name:    t.Name,
+		Artists: t.Artists,
+		Album:   t.Album,
+		ISRC:    t.ISRC,
+
+		Duration:    time.Duration(t.DurationMS) * time.Millisecond,
+		ReleaseYear: t.ReleaseYear(),
+	}
+	if t.Show != nil {
+		m.Show = t.Show.Name
+	}
+	return m
+}
+
+// Scorer returns base adjusted for the match options of the migration in
+// ctx. Titles in different scripts are always also compared transliterated,
+// since services disagree on whether to romanize them, different versions
+// of a track (live, remix, ...) are penalized unless the relaxed strategy
+// is chosen, and candidates released in other years than the source lose
+// a little unless re-recordings are allowed or preferred.
+func Scorer(ctx context.Context, base matching.Scorer) matching.Scorer {
+	opts := domain.MatchOptionsFromContext(ctx)
+	if opts.Classical {
+		base = matching.WithClassical(base)
+	}
+	// Versions are parsed from the original titles, so they must be
+	// checked outside the transliteration, which normalizes them.
+	base = matching.WithTransliteration(base)
+	if opts.Strategy == domain.MatchingDurationWeighted {
+		base = matching.WithDuration(base)
+	}
+	if opts.Rerecordings == domain.RerecordingsDefault || opts.Rerecordings == domain.RerecordingsAvoid {
+		base = matching.WithReleaseYear(base)
+	}
+	switch {
+	case opts.StrictVersions || opts.Strategy == domain.MatchingStrict:
+		base = matching.WithStrictVersions(base)
+	case opts.Strategy == domain.MatchingRelaxed:
+		// Different versions score like the same one.
+	default:
+		base = matching.WithVersions(base)
+	}
+	if opts.Rerecordings == domain.RerecordingsPrefer || opts.Rerecordings == domain.RerecordingsAvoid {
+		base = matching.WithRerecordings(base, opts.Rerecordings == domain.RerecordingsPrefer)
+	}
+	return base
+}
@@ -0,0 +1,89 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/pkg/matching"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchTrack(t *testing.T) {
+	track := domain.Track{Name: "Ep 1", Artists: []string{"A"}, Album: "B", ISRC: "X", Show: &domain.Show{Name: "Show"}}
+
+	assert.Equal(t, matching.Track{Name: "Ep 1", Artists: []string{"A"}, Album: "B", ISRC: "X", Show: "Show"}, MatchTrack(track))
+
+	track.DurationMS = 1500
+	assert.Equal(t, 1500*time.Millisecond, MatchTrack(track).Duration)
+
+	track.ReleaseDate = "2008-11-11"
+	assert.Equal(t, 2008, MatchTrack(track).ReleaseYear)
+}
+
+func TestScorer_Classical(t *testing.T) {
+	source := matching.Track{Name: "Symphony No. 9 in D minor, Op. 125: II. Molto vivace"}
+	candidate := matching.Track{Name: "Beethoven: Symphony No. 9, Op. 125 - II. Molto vivace"}
+
+	plain := Scorer(context.Background(), matching.Catalog)
+	classical := Scorer(domain.ContextWithMatchOptions(context.Background(), domain.MatchOptions{Classical: true}), matching.Catalog)
+
+	assert.Greater(t, classical.Score(source, candidate), plain.Score(source, candidate))
+}
+
+func TestScorer_Transliteration(t *testing.T) {
+	source := matching.Track{Name: "Кино", Artists: []string{"Виктор Цой"}}
+	candidate := matching.Track{Name: "Kino", Artists: []string{"Viktor Tsoy"}}
+
+	assert.Greater(t, Scorer(context.Background(), matching.Catalog).Score(source, candidate), 0.8)
+}
+
+func TestScorer_Versions(t *testing.T) {
+	source := matching.Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}}
+	live := matching.Track{Name: "Bohemian Rhapsody (Live Aid)", Artists: []string{"Queen"}}
+
+	lenient := Scorer(context.Background(), matching.Catalog)
+	strict := Scorer(domain.ContextWithMatchOptions(context.Background(), domain.MatchOptions{StrictVersions: true}), matching.Catalog)
+
+	assert.Less(t, lenient.Score(source, live), 0.5)
+	assert.Greater(t, lenient.Score(source, live), 0.0)
+	assert.Equal(t, 0.0, strict.Score(source, live))
+}
+
+func TestScorer_Strategies(t *testing.T) {
+	source := matching.Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, Duration: 354 * time.Second}
+	live := matching.Track{Name: "Bohemian Rhapsody (Live Aid)", Artists: []string{"Queen"}, Duration: 354 * time.Second}
+	edit := matching.Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, Duration: 240 * time.Second}
+	scorer := func(strategy domain.MatchingStrategy) matching.Scorer {
+		return Scorer(domain.ContextWithMatchOptions(context.Background(), domain.MatchOptions{Strategy: strategy}), matching.Catalog)
+	}
+	base := scorer(domain.MatchingDefault)
+
+	assert.Equal(t, 0.0, scorer(domain.MatchingStrict).Score(source, live))
+	assert.Greater(t, scorer(domain.MatchingRelaxed).Score(source, live), base.Score(source, live))
+	assert.Less(t, scorer(domain.MatchingDurationWeighted).Score(source, edit), base.Score(source, edit))
+}
+
+func TestScorer_ReleaseYear(t *testing.T) {
+	source := matching.Track{Name: "Love Story", Artists: []string{"Taylor Swift"}, ReleaseYear: 2008}
+	rerecording := matching.Track{Name: "Love Story", Artists: []string{"Taylor Swift"}, ReleaseYear: 2021}
+
+	base := Scorer(context.Background(), matching.Catalog)
+	allowed := Scorer(domain.ContextWithMatchOptions(context.Background(), domain.MatchOptions{Rerecordings: domain.RerecordingsAny}), matching.Catalog)
+
+	assert.Less(t, base.Score(source, rerecording), allowed.Score(source, rerecording))
+	assert.Equal(t, allowed.Score(source, source), base.Score(source, source))
+}
+
+func TestScorer_Rerecordings(t *testing.T) {
+	source := matching.Track{Name: "Love Story", Artists: []string{"Taylor Swift"}, ReleaseYear: 2008}
+	original := matching.Track{Name: "Love Story", Artists: []string{"Taylor Swift"}, ReleaseYear: 2008}
+	rerecording := matching.Track{Name: "Love Story (Taylor's Version)", Artists: []string{"Taylor Swift"}, ReleaseYear: 2021}
+	scorer := func(pref domain.RerecordingPreference) matching.Scorer {
+		return Scorer(domain.ContextWithMatchOptions(context.Background(), domain.MatchOptions{Rerecordings: pref}), matching.Catalog)
+	}
+
+	assert.Greater(t, scorer(domain.RerecordingsPrefer).Score(source, rerecording), scorer(domain.RerecordingsPrefer).Score(source, original))
+	assert.Greater(t, scorer(domain.RerecordingsAvoid).Score(source, original), scorer(domain.RerecordingsAvoid).Score(source, rerecording))
+}
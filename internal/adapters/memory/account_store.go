@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// AccountStore implements ports.AccountStore by keeping accounts in memory.
+// It is safe for concurrent use.
+type AccountStore struct {
+	mu       sync.RWMutex
+	accounts map[string]domain.Account // keyed by API key hash
+}
+
+// NewAccountStore creates an empty in-memory account store.
+func NewAccountStore() *AccountStore {
+	return &AccountStore{
+		accounts: make(map[string]domain.Account),
+	}
+}
+
+func (s *AccountStore) Create(_ context.Context, account *domain.Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.accounts[account.APIKeyHash]; exists {
+		return fmt.Errorf("account with this API key already exists")
+	}
+	s.accounts[account.APIKeyHash] = *cloneAccount(*account)
+	return nil
+}
+
+func (s *AccountStore) GetByAPIKeyHash(_ context.Context, hash string) (*domain.Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	account, ok := s.accounts[hash]
+	if !ok {
+		return nil, domain.ErrAccountNotFound
+	}
+	return cloneAccount(account), nil
+}
+
+func (s *AccountStore) Get(_ context.Context, id string) (*domain.Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, account := range s.accounts {
+		if account.ID == id {
+			return cloneAccount(account), nil
+		}
+	}
+	return nil, domain.ErrAccountNotFound
+}
+
+func (s *AccountStore) SetLimits(_ context.Context, id string, limits *domain.AccountLimits) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for hash, account := range s.accounts {
+		if account.ID == id {
+			account.Limits = nil
+			if limits != nil {
+				copied := *limits
+				account.Limits = &copied
+			}
+			s.accounts[hash] = account
+			return nil
+		}
+	}
+	return domain.ErrAccountNotFound
+}
+
+func cloneAccount(account domain.Account) *domain.Account {
+	if account.Limits != nil {
+		limits := *account.Limits
+		account.Limits = &limits
+	}
+	return &account
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// AuditLog implements ports.AuditLog in memory. Entries are lost when the
+// process exits. It is safe for concurrent use.
+type AuditLog struct {
+	mu      sync.RWMutex
+	entries []domain.AuditEntry
+}
+
+// NewAuditLog creates an empty in-memory audit log.
+func NewAuditLog() *AuditLog {
+	return &AuditLog{}
+}
+
+func (l *AuditLog) Append(_ context.Context, entry *domain.AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, cloneAuditEntry(*entry))
+	return nil
+}
+
+func (l *AuditLog) Query(_ context.Context, q domain.AuditQuery) ([]domain.AuditEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	// Walking backwards keeps entries started at the same time newest first.
+	entries := make([]domain.AuditEntry, 0)
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if auditMatches(l.entries[i], q) {
+			entries = append(entries, cloneAuditEntry(l.entries[i]))
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedAt.After(entries[j].StartedAt)
+	})
+	if q.Limit > 0 && len(entries) > q.Limit {
+		entries = entries[:q.Limit]
+	}
+	return entries, nil
+}
+
+func auditMatches(entry domain.AuditEntry, q domain.AuditQuery) bool {
+	switch {
+	case q.AccountID != "" && entry.AccountID != q.AccountID,
+		q.Provider != "" && entry.Provider != q.Provider,
+		q.Action != "" && entry.Action != q.Action,
+		!q.Since.IsZero() && entry.StartedAt.Before(q.Since),
+		!q.Until.IsZero() && !entry.StartedAt.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+func cloneAuditEntry(entry domain.AuditEntry) domain.AuditEntry {
+	entry.TrackIDs = append([]string(nil), entry.TrackIDs...)
+	return entry
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// GoldenMatchStore implements ports.GoldenMatchStore by keeping the golden
+// dataset in memory. Entries are lost when the process exits. It is safe
+// for concurrent use.
+type GoldenMatchStore struct {
+	mu      sync.RWMutex
+	matches map[string]domain.GoldenMatch
+}
+
+// NewGoldenMatchStore creates an empty in-memory golden match store.
+func NewGoldenMatchStore() *GoldenMatchStore {
+	return &GoldenMatchStore{
+		matches: make(map[string]domain.GoldenMatch),
+	}
+}
+
+func (s *GoldenMatchStore) Save(_ context.Context, match *domain.GoldenMatch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.matches[match.ID] = *match
+	return nil
+}
+
+func (s *GoldenMatchStore) Get(_ context.Context, id string) (*domain.GoldenMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	match, ok := s.matches[id]
+	if !ok {
+		return nil, domain.ErrGoldenMatchNotFound
+	}
+	return &match, nil
+}
+
+func (s *GoldenMatchStore) List(_ context.Context, filter domain.GoldenMatchFilter) ([]domain.GoldenMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]domain.GoldenMatch, 0)
+	for _, match := range s.matches {
+		if filter.Matches(match) {
+			matches = append(matches, match)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].UpdatedAt.Before(matches[j].UpdatedAt)
+	})
+	return matches, nil
+}
+
+func (s *GoldenMatchStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.matches[id]; !ok {
+		return domain.ErrGoldenMatchNotFound
+	}
+	delete(s.matches, id)
+	return nil
+}
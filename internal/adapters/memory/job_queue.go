@@ -0,0 +1,316 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// finishedJobRetention is how long the memory queue keeps jobs after they
+// finished or were canceled.
+const finishedJobRetention = 24 * time.Hour
+
+// JobQueue implements ports.JobQueue in memory. Jobs are lost when the
+// process exits, so it only suits single-instance deployments, and are
+// forgotten, along with their idempotency keys, finishedJobRetention after
+// they finished. It is safe for concurrent use.
+type JobQueue struct {
+	mu   sync.Mutex
+	jobs map[string]*domain.Job
+
+	// active holds the queued and running jobs, so leasing a job does not
+	// go through the finished ones.
+	active map[string]*domain.Job
+
+	// finished holds the IDs of the finished jobs in the order they
+	// finished, for pruning them.
+	finished []string
+
+	// keys maps the idempotency keys of the jobs, by account, to their IDs.
+	keys map[idempotencyKey]string
+
+	// lastLeased holds the time each account last had a job leased.
+	lastLeased map[string]time.Time
+
+	retention time.Duration
+	now       func() time.Time
+}
+
+type idempotencyKey struct {
+	accountID string
+	key       string
+}
+
+// NewJobQueue creates an empty in-memory job queue.
+func NewJobQueue() *JobQueue {
+	return &JobQueue{
+		jobs:       make(map[string]*domain.Job),
+		active:     make(map[string]*domain.Job),
+		keys:       make(map[idempotencyKey]string),
+		lastLeased: make(map[string]time.Time),
+		retention:  finishedJobRetention,
+		now:        time.Now,
+	}
+}
+
+func (q *JobQueue) Enqueue(_ context.Context, job *domain.Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.prune()
+	if q.findByIdempotencyKey(job.AccountID, job.Request.IdempotencyKey) != nil {
+		return domain.ErrJobExists
+	}
+	stored := cloneJob(job)
+	stored.Status = domain.JobQueued
+	if stored.Priority == "" {
+		stored.Priority = domain.JobInteractive
+	}
+	if stored.Kind == "" {
+		stored.Kind = domain.JobPlaylist
+	}
+	q.jobs[job.ID] = stored
+	q.active[job.ID] = stored
+	if key := job.Request.IdempotencyKey; key != "" {
+		q.keys[idempotencyKey{job.AccountID, key}] = job.ID
+	}
+	return nil
+}
+
+func (q *JobQueue) Lease(_ context.Context, owner string, lease time.Duration) (*domain.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.prune()
+	now := q.now()
+	var available []*domain.Job
+	running := make(map[string]int)
+	for _, job := range q.active {
+		switch {
+		case job.Status == domain.JobQueued || (job.Status == domain.JobRunning && now.After(job.LeaseExpiresAt)):
+			available = append(available, job)
+		case job.Status == domain.JobRunning:
+			running[job.AccountID]++
+		}
+	}
+	if len(available) == 0 {
+		return nil, nil
+	}
+	sort.Slice(available, func(i, j int) bool {
+		a, b := available[i], available[j]
+		if a.Priority.Rank() != b.Priority.Rank() {
+			return a.Priority.Rank() < b.Priority.Rank()
+		}
+		if running[a.AccountID] != running[b.AccountID] {
+			return running[a.AccountID] < running[b.AccountID]
+		}
+		if la, lb := q.lastLeased[a.AccountID], q.lastLeased[b.AccountID]; !la.Equal(lb) {
+			return la.Before(lb)
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	job := available[0]
+	q.lastLeased[job.AccountID] = now
+	job.Status = domain.JobRunning
+	job.Attempts++
+	job.LeaseOwner = owner
+	job.LeaseExpiresAt = now.Add(lease)
+	job.UpdatedAt = now
+	return cloneJob(job), nil
+}
+
+func (q *JobQueue) Renew(_ context.Context, id string, owner string, lease time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, err := q.leased(id, owner)
+	if err != nil {
+		return err
+	}
+	job.LeaseExpiresAt = q.now().Add(lease)
+	return nil
+}
+
+func (q *JobQueue) SaveProgress(_ context.Context, id string, owner string, progress domain.JobProgress) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, err := q.leased(id, owner)
+	if err != nil {
+		return err
+	}
+	job.Progress = cloneProgress(&progress)
+	job.UpdatedAt = q.now()
+	return nil
+}
+
+func (q *JobQueue) Finish(_ context.Context, job *domain.Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stored, err := q.leased(job.ID, job.LeaseOwner)
+	if err != nil {
+		return err
+	}
+	stored.Status = job.Status
+	stored.MigrationID = job.MigrationID
+	stored.Error = job.Error
+	stored.DebugLog = cloneDebugLog(job.DebugLog)
+	stored.Tokens = nil
+	stored.LeaseOwner = ""
+	stored.LeaseExpiresAt = time.Time{}
+	stored.UpdatedAt = q.now()
+	q.finish(stored)
+	return nil
+}
+
+func (q *JobQueue) Get(_ context.Context, id string) (*domain.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+	return cloneJob(job), nil
+}
+
+func (q *JobQueue) GetByIdempotencyKey(_ context.Context, accountID string, key string) (*domain.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job := q.findByIdempotencyKey(accountID, key)
+	if job == nil {
+		return nil, domain.ErrJobNotFound
+	}
+	return cloneJob(job), nil
+}
+
+func (q *JobQueue) CountActive(_ context.Context, accountID string) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n := 0
+	for _, job := range q.active {
+		if job.AccountID == accountID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (q *JobQueue) Cancel(_ context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return domain.ErrJobNotFound
+	}
+	if job.Status.Finished() {
+		return domain.ErrJobFinished
+	}
+	job.Status = domain.JobCanceled
+	job.Tokens = nil
+	job.LeaseOwner = ""
+	job.LeaseExpiresAt = time.Time{}
+	job.UpdatedAt = q.now()
+	q.finish(job)
+	return nil
+}
+
+// leased returns the running job id if owner holds its lease.
+func (q *JobQueue) leased(id string, owner string) (*domain.Job, error) {
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+	if job.Status != domain.JobRunning || job.LeaseOwner != owner {
+		return nil, domain.ErrJobLeaseLost
+	}
+	return job, nil
+}
+
+// findByIdempotencyKey returns the account's job queued with key, or nil.
+func (q *JobQueue) findByIdempotencyKey(accountID string, key string) *domain.Job {
+	if key == "" {
+		return nil
+	}
+	id, ok := q.keys[idempotencyKey{accountID, key}]
+	if !ok {
+		return nil
+	}
+	return q.jobs[id]
+}
+
+// finish moves a job that finished or was canceled out of the active jobs.
+func (q *JobQueue) finish(job *domain.Job) {
+	delete(q.active, job.ID)
+	q.finished = append(q.finished, job.ID)
+}
+
+// prune forgets the jobs that finished longer than the retention ago.
+func (q *JobQueue) prune() {
+	cutoff := q.now().Add(-q.retention)
+	n := 0
+	for _, id := range q.finished {
+		job := q.jobs[id]
+		if !job.UpdatedAt.Before(cutoff) {
+			break
+		}
+		delete(q.jobs, id)
+		if key := job.Request.IdempotencyKey; key != "" {
+			delete(q.keys, idempotencyKey{job.AccountID, key})
+		}
+		n++
+	}
+	q.finished = q.finished[n:]
+}
+
+// cloneJob copies the slices and pointers of a job so callers cannot mutate
+// stored state.
+func cloneJob(job *domain.Job) *domain.Job {
+	c := *job
+	c.Request.Genres = slices.Clone(c.Request.Genres)
+	if c.Request.Exclude != nil {
+		exclude := *c.Request.Exclude
+		exclude.Artists = slices.Clone(exclude.Artists)
+		exclude.TitlePatterns = slices.Clone(exclude.TitlePatterns)
+		exclude.Genres = slices.Clone(exclude.Genres)
+		c.Request.Exclude = &exclude
+	}
+	c.Tokens = slices.Clone(c.Tokens)
+	c.Progress = cloneProgress(c.Progress)
+	c.DebugLog = cloneDebugLog(c.DebugLog)
+	return &c
+}
+
+func cloneProgress(progress *domain.JobProgress) *domain.JobProgress {
+	if progress == nil {
+		return nil
+	}
+	c := *progress
+	if c.EstimatedCompletion != nil {
+		at := *c.EstimatedCompletion
+		c.EstimatedCompletion = &at
+	}
+	c.Stages = slices.Clone(c.Stages)
+	for i := range c.Stages {
+		c.Stages[i].MigrationIDs = slices.Clone(c.Stages[i].MigrationIDs)
+	}
+	return &c
+}
+
+func cloneDebugLog(log *domain.DebugLog) *domain.DebugLog {
+	if log == nil {
+		return nil
+	}
+	c := *log
+	c.Exchanges = slices.Clone(c.Exchanges)
+	return &c
+}
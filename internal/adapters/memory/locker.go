@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Locker implements ports.Locker within a single process. It is safe for
+// concurrent use.
+type Locker struct {
+	mu    sync.Mutex
+	locks map[string]lock
+
+	now func() time.Time
+}
+
+type lock struct {
+	owner     string
+	expiresAt time.Time
+}
+
+// NewLocker creates a locker with no locks held.
+func NewLocker() *Locker {
+	return &Locker{locks: make(map[string]lock), now: time.Now}
+}
+
+func (l *Locker) Acquire(_ context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if held, ok := l.locks[key]; ok && held.owner != owner && now.Before(held.expiresAt) {
+		return false, nil
+	}
+	l.locks[key] = lock{owner: owner, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+func (l *Locker) Refresh(_ context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	held, ok := l.locks[key]
+	if !ok || held.owner != owner || !now.Before(held.expiresAt) {
+		return false, nil
+	}
+	l.locks[key] = lock{owner: owner, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+func (l *Locker) Release(_ context.Context, key string, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if held, ok := l.locks[key]; ok && held.owner == owner {
+		delete(l.locks, key)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// MatchFeedbackStore implements ports.MatchFeedbackStore in memory. It is
+// safe for concurrent use.
+type MatchFeedbackStore struct {
+	mu       sync.RWMutex
+	verdicts map[feedbackKey]map[string]domain.MatchVerdict
+}
+
+// feedbackKey identifies a match by its two tracks, the one with the lower
+// provider and ID first, so both directions share a key.
+type feedbackKey struct {
+	providerA, idA string
+	providerB, idB string
+}
+
+func newFeedbackKey(fromProvider, fromID, toProvider, toID string) feedbackKey {
+	if fromProvider > toProvider || (fromProvider == toProvider && fromID > toID) {
+		fromProvider, fromID, toProvider, toID = toProvider, toID, fromProvider, fromID
+	}
+	return feedbackKey{fromProvider, fromID, toProvider, toID}
+}
+
+// NewMatchFeedbackStore creates an empty in-memory match feedback store.
+func NewMatchFeedbackStore() *MatchFeedbackStore {
+	return &MatchFeedbackStore{verdicts: make(map[feedbackKey]map[string]domain.MatchVerdict)}
+}
+
+func (s *MatchFeedbackStore) Save(_ context.Context, feedback *domain.MatchFeedback) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := newFeedbackKey(feedback.SourceProvider, feedback.SourceTrack.ExternalID, feedback.DestProvider, feedback.MatchedTrack.ExternalID)
+	if s.verdicts[key] == nil {
+		s.verdicts[key] = make(map[string]domain.MatchVerdict)
+	}
+	s.verdicts[key][feedback.AccountID] = feedback.Verdict
+	return nil
+}
+
+func (s *MatchFeedbackStore) Tally(_ context.Context, fromProvider string, fromID string, toProvider string, toID string) (domain.MatchFeedbackTally, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tally domain.MatchFeedbackTally
+	for _, verdict := range s.verdicts[newFeedbackKey(fromProvider, fromID, toProvider, toID)] {
+		switch verdict {
+		case domain.MatchConfirmed:
+			tally.Confirmations++
+		case domain.MatchRejected:
+			tally.Rejections++
+		}
+	}
+	return tally, nil
+}
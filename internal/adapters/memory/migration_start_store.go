@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MigrationStartStore implements ports.MigrationStartStore in memory. It is
+// safe for concurrent use.
+type MigrationStartStore struct {
+	mu     sync.Mutex
+	starts map[string][]migrationStart
+}
+
+type migrationStart struct {
+	id string
+	at time.Time
+}
+
+// NewMigrationStartStore creates an empty migration start store.
+func NewMigrationStartStore() *MigrationStartStore {
+	return &MigrationStartStore{starts: make(map[string][]migrationStart)}
+}
+
+func (s *MigrationStartStore) Reserve(_ context.Context, accountID string, id string, at time.Time, since time.Time, max int) (int, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Starts before since no longer count against anything.
+	starts := slices.DeleteFunc(s.starts[accountID], func(m migrationStart) bool {
+		return m.at.Before(since)
+	})
+	if len(starts) >= max {
+		s.starts[accountID] = starts
+		return len(starts), false, nil
+	}
+	s.starts[accountID] = append(starts, migrationStart{id: id, at: at})
+	return len(starts) + 1, true, nil
+}
+
+func (s *MigrationStartStore) Count(_ context.Context, accountID string, since time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, m := range s.starts[accountID] {
+		if !m.at.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *MigrationStartStore) Cancel(_ context.Context, accountID string, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.starts[accountID] = slices.DeleteFunc(s.starts[accountID], func(m migrationStart) bool {
+		return m.id == id
+	})
+	if len(s.starts[accountID]) == 0 {
+		delete(s.starts, accountID)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// MigrationStore implements ports.MigrationStore by keeping migration results
+// in memory. Results are lost when the process exits. It is safe for
+// concurrent use.
+type MigrationStore struct {
+	mu         sync.RWMutex
+	migrations map[string]domain.MigrationResult
+}
+
+// NewMigrationStore creates an empty in-memory migration store.
+func NewMigrationStore() *MigrationStore {
+	return &MigrationStore{
+		migrations: make(map[string]domain.MigrationResult),
+	}
+}
+
+func (s *MigrationStore) Save(_ context.Context, result *domain.MigrationResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.migrations[result.ID] = cloneResult(*result)
+	return nil
+}
+
+func (s *MigrationStore) Get(_ context.Context, id string) (*domain.MigrationResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result, ok := s.migrations[id]
+	if !ok {
+		return nil, domain.ErrMigrationNotFound
+	}
+	result = cloneResult(result)
+	return &result, nil
+}
+
+func (s *MigrationStore) List(_ context.Context, accountID string) ([]domain.MigrationResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]domain.MigrationResult, 0)
+	for _, result := range s.migrations {
+		if result.AccountID == accountID {
+			results = append(results, cloneResult(result))
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CreatedAt.Before(results[j].CreatedAt)
+	})
+	return results, nil
+}
+
+func (s *MigrationStore) GetByIdempotencyKey(_ context.Context, accountID string, key string) (*domain.MigrationResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, result := range s.migrations {
+		if result.AccountID == accountID && result.IdempotencyKey == key {
+			result = cloneResult(result)
+			return &result, nil
+		}
+	}
+	return nil, domain.ErrMigrationNotFound
+}
+
+// cloneResult copies the slices, maps and pointers of a result so callers
+// cannot mutate stored state without going through Save.
+func cloneResult(result domain.MigrationResult) domain.MigrationResult {
+	result.TrackResults = slices.Clone(result.TrackResults)
+	result.Warnings = slices.Clone(result.Warnings)
+	result.DestPlaylistIDs = slices.Clone(result.DestPlaylistIDs)
+	result.Gaps = slices.Clone(result.Gaps)
+	result.MergedFrom = slices.Clone(result.MergedFrom)
+	result.ErrorBreakdown = maps.Clone(result.ErrorBreakdown)
+	result.QuotaUnitsUsed = maps.Clone(result.QuotaUnitsUsed)
+	if result.Concurrency != nil {
+		concurrency := *result.Concurrency
+		result.Concurrency = &concurrency
+	}
+	if result.Timing != nil {
+		timing := *result.Timing
+		result.Timing = &timing
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// ProfileStore implements ports.ProfileStore by keeping migration profiles
+// in memory. Profiles are lost when the process exits. It is safe for
+// concurrent use.
+type ProfileStore struct {
+	mu       sync.RWMutex
+	profiles map[string]domain.MigrationProfile
+}
+
+// NewProfileStore creates an empty in-memory profile store.
+func NewProfileStore() *ProfileStore {
+	return &ProfileStore{
+		profiles: make(map[string]domain.MigrationProfile),
+	}
+}
+
+func (s *ProfileStore) Save(_ context.Context, profile *domain.MigrationProfile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles[profile.ID] = *profile
+	return nil
+}
+
+func (s *ProfileStore) Get(_ context.Context, id string) (*domain.MigrationProfile, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profile, ok := s.profiles[id]
+	if !ok {
+		return nil, domain.ErrProfileNotFound
+	}
+	return &profile, nil
+}
+
+func (s *ProfileStore) List(_ context.Context, accountID string) ([]domain.MigrationProfile, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profiles := make([]domain.MigrationProfile, 0)
+	for _, profile := range s.profiles {
+		if profile.AccountID == accountID {
+			profiles = append(profiles, profile)
+		}
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].CreatedAt.Before(profiles[j].CreatedAt)
+	})
+	return profiles, nil
+}
+
+func (s *ProfileStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.profiles[id]; !ok {
+		return domain.ErrProfileNotFound
+	}
+	delete(s.profiles, id)
+	return nil
+}
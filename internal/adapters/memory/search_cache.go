@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// SearchCache implements ports.SearchCache in memory. It is safe for
+// concurrent use. Expired entries are dropped when they are next read.
+type SearchCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewSearchCache creates an empty in-memory search cache.
+func NewSearchCache() *SearchCache {
+	return &SearchCache{entries: make(map[string]cacheEntry), now: time.Now}
+}
+
+func (c *SearchCache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, domain.ErrCacheMiss
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, domain.ErrCacheMiss
+	}
+	return append([]byte(nil), entry.data...), nil
+}
+
+func (c *SearchCache) Put(_ context.Context, key string, data []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = cacheEntry{data: append([]byte(nil), data...), expiresAt: c.now().Add(ttl)}
+	return nil
+}
+
+func (c *SearchCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+	return nil
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// TokenStore implements ports.TokenStore by keeping encrypted tokens in
+// memory. It is safe for concurrent use.
+type TokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string][]byte // keyed by accountID + "/" + provider
+}
+
+// NewTokenStore creates an empty in-memory token store.
+func NewTokenStore() *TokenStore {
+	return &TokenStore{
+		tokens: make(map[string][]byte),
+	}
+}
+
+func (s *TokenStore) Put(_ context.Context, accountID string, provider string, ciphertext []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[accountID+"/"+provider] = append([]byte(nil), ciphertext...)
+	return nil
+}
+
+func (s *TokenStore) Get(_ context.Context, accountID string, provider string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ciphertext, ok := s.tokens[accountID+"/"+provider]
+	if !ok {
+		return nil, domain.ErrTokenNotFound
+	}
+	return append([]byte(nil), ciphertext...), nil
+}
+
+func (s *TokenStore) List(_ context.Context, accountID string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	providers := make([]string, 0)
+	for key := range s.tokens {
+		if provider, ok := strings.CutPrefix(key, accountID+"/"); ok {
+			providers = append(providers, provider)
+		}
+	}
+	sort.Strings(providers)
+	return providers, nil
+}
+
+func (s *TokenStore) Delete(_ context.Context, accountID string, provider string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, accountID+"/"+provider)
+	return nil
+}
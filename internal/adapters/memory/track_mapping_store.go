@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// TrackMappingStore implements ports.TrackMappingStore in memory. It is safe
+// for concurrent use.
+type TrackMappingStore struct {
+	mu       sync.RWMutex
+	mappings map[mappingKey]mappedTrack
+}
+
+// mappingKey identifies a track on one provider and the provider it is
+// mapped to.
+type mappingKey struct {
+	fromProvider string
+	fromID       string
+	toProvider   string
+}
+
+type mappedTrack struct {
+	track domain.Track
+	score float64
+}
+
+// NewTrackMappingStore creates an empty in-memory track mapping store.
+func NewTrackMappingStore() *TrackMappingStore {
+	return &TrackMappingStore{mappings: make(map[mappingKey]mappedTrack)}
+}
+
+func (s *TrackMappingStore) Save(_ context.Context, mapping *domain.TrackMapping) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mappings[mappingKey{mapping.ProviderA, mapping.TrackA.ExternalID, mapping.ProviderB}] = mappedTrack{cloneTrack(mapping.TrackB), mapping.Score}
+	s.mappings[mappingKey{mapping.ProviderB, mapping.TrackB.ExternalID, mapping.ProviderA}] = mappedTrack{cloneTrack(mapping.TrackA), mapping.Score}
+	return nil
+}
+
+func (s *TrackMappingStore) Find(_ context.Context, fromProvider string, fromID string, toProvider string) (*domain.Track, float64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mapped, ok := s.mappings[mappingKey{fromProvider, fromID, toProvider}]
+	if !ok {
+		return nil, 0, domain.ErrMappingNotFound
+	}
+	track := cloneTrack(mapped.track)
+	return &track, mapped.score, nil
+}
+
+func (s *TrackMappingStore) Delete(_ context.Context, providerA string, idA string, providerB string, idB string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, k := range []struct {
+		key mappingKey
+		to  string
+	}{
+		{mappingKey{providerA, idA, providerB}, idB},
+		{mappingKey{providerB, idB, providerA}, idA},
+	} {
+		if mapped, ok := s.mappings[k.key]; ok && mapped.track.ExternalID == k.to {
+			delete(s.mappings, k.key)
+		}
+	}
+	return nil
+}
+
+func cloneTrack(track domain.Track) domain.Track {
+	track.Artists = append([]string(nil), track.Artists...)
+	if track.Show != nil {
+		show := *track.Show
+		track.Show = &show
+	}
+	return track
+}
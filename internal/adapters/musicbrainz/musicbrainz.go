@@ -0,0 +1,236 @@
+// Package musicbrainz provides a metadata source that looks tracks up in
+// the MusicBrainz database, by recording ID, by ISRC or by name and artist.
+package musicbrainz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/pkg/matching"
+)
+
+// SourceName is the name the MusicBrainz metadata source goes by.
+const SourceName = "musicbrainz"
+
+const (
+	baseURL = "https://musicbrainz.org/ws/2/"
+
+	// userAgent identifies the service, as MusicBrainz requires of clients.
+	userAgent = "MusicMigration-API/1.0 ( https://github.com/jpp0ca/MusicMigration-API )"
+
+	// requestInterval is the pause between requests MusicBrainz asks
+	// clients to keep; faster clients are blocked.
+	requestInterval = time.Second
+
+	// searchLimit is the number of recordings a search considers.
+	searchLimit = 5
+
+	// minScore is the score a searched recording needs to be taken for the
+	// track.
+	minScore = 0.8
+)
+
+// Source implements ports.MetadataSource using the MusicBrainz API. Its
+// requests are spaced by the interval MusicBrainz asks for, so lookups from
+// concurrent migrations queue up.
+type Source struct {
+	client   *http.Client
+	baseURL  string
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewSource creates a MusicBrainz metadata source. If client is nil,
+// http.DefaultClient is used.
+func NewSource(client *http.Client) *Source {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Source{client: client, baseURL: baseURL, interval: requestInterval}
+}
+
+func (s *Source) Name() string {
+	return SourceName
+}
+
+// -- API response types (internal) ------------------------------------------
+
+type recording struct {
+	ID           string   `json:"id"`
+	Title        string   `json:"title"`
+	Length       int      `json:"length"`
+	ISRCs        []string `json:"isrcs"`
+	ArtistCredit []struct {
+		Name string `json:"name"`
+	} `json:"artist-credit"`
+	Releases []struct {
+		Title string `json:"title"`
+	} `json:"releases"`
+}
+
+func (r recording) toMetadata() *domain.TrackMetadata {
+	m := &domain.TrackMetadata{DurationMS: r.Length, MusicBrainzID: r.ID}
+	if len(r.ISRCs) > 0 {
+		m.ISRC = r.ISRCs[0]
+	}
+	if len(r.Releases) > 0 {
+		m.Album = r.Releases[0].Title
+	}
+	return m
+}
+
+func (r recording) toMatch() matching.Track {
+	t := matching.Track{Name: r.Title, Duration: time.Duration(r.Length) * time.Millisecond}
+	for _, credit := range r.ArtistCredit {
+		t.Artists = append(t.Artists, credit.Name)
+	}
+	if len(r.Releases) > 0 {
+		t.Album = r.Releases[0].Title
+	}
+	return t
+}
+
+// -- Port implementation ----------------------------------------------------
+
+// LookupMetadata looks the track up by its MusicBrainz recording ID if it
+// has one, else by its ISRC, else searches recordings by name and artist
+// and takes the best-scored one if it scores at least minScore.
+func (s *Source) LookupMetadata(ctx context.Context, track domain.Track) (*domain.TrackMetadata, error) {
+	switch {
+	case track.MusicBrainzID != "":
+		var rec recording
+		err := s.get(ctx, "recording/"+url.PathEscape(track.MusicBrainzID), url.Values{"inc": {"isrcs releases"}}, &rec)
+		if err != nil {
+			return nil, fmt.Errorf("musicbrainz: recording lookup failed: %w", err)
+		}
+		if rec.ID == "" {
+			return nil, nil
+		}
+		return rec.toMetadata(), nil
+
+	case track.ISRC != "":
+		var resp struct {
+			Recordings []recording `json:"recordings"`
+		}
+		err := s.get(ctx, "isrc/"+url.PathEscape(track.ISRC), url.Values{"inc": {"releases"}}, &resp)
+		if err != nil {
+			return nil, fmt.Errorf("musicbrainz: ISRC lookup failed: %w", err)
+		}
+		if len(resp.Recordings) == 0 {
+			return nil, nil
+		}
+		m := resp.Recordings[0].toMetadata()
+		m.ISRC = track.ISRC
+		return m, nil
+
+	case track.Name != "" && len(track.Artists) > 0:
+		var resp struct {
+			Recordings []recording `json:"recordings"`
+		}
+		query := fmt.Sprintf(`recording:"%s" AND artist:"%s"`, escape(track.Name), escape(track.Artists[0]))
+		err := s.get(ctx, "recording", url.Values{"query": {query}, "limit": {fmt.Sprint(searchLimit)}}, &resp)
+		if err != nil {
+			return nil, fmt.Errorf("musicbrainz: recording search failed: %w", err)
+		}
+		candidates := make([]matching.Track, len(resp.Recordings))
+		for i, rec := range resp.Recordings {
+			candidates[i] = rec.toMatch()
+		}
+		best, score := matching.BestCandidate(matching.Catalog, adapters.MatchTrack(track), candidates)
+		if best < 0 || score < minScore {
+			return nil, nil
+		}
+		return resp.Recordings[best].toMetadata(), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// escape escapes the characters of s that are special in a quoted term of
+// a Lucene query.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// -- HTTP helpers ------------------------------------------------------------
+
+// apiError is returned by get when MusicBrainz responds with an error.
+type apiError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("musicbrainz API returned status %d: %s", e.StatusCode, adapters.ErrorBody(e.Body))
+}
+
+// Is reports throttled requests, which MusicBrainz answers with 503, as
+// domain.ErrRateLimited.
+func (e *apiError) Is(target error) bool {
+	return target == domain.ErrRateLimited &&
+		(e.StatusCode == http.StatusServiceUnavailable || e.StatusCode == http.StatusTooManyRequests)
+}
+
+// get calls a JSON API endpoint, once the request interval has passed, and
+// decodes its response into out. Not found resources leave out untouched.
+func (s *Source) get(ctx context.Context, path string, params url.Values, out any) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	params.Set("fmt", "json")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return adapters.SanitizeError(err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return json.NewDecoder(resp.Body).Decode(out)
+	case http.StatusNotFound:
+		return nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return &apiError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+}
+
+// wait blocks until the request interval since the previous request has
+// passed, or ctx is done.
+func (s *Source) wait(ctx context.Context) error {
+	s.mu.Lock()
+	delay := max(time.Until(s.next), 0)
+	s.next = time.Now().Add(delay + s.interval)
+	s.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
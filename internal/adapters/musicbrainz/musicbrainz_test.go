@@ -0,0 +1,104 @@
+package musicbrainz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSource(t *testing.T, handler http.HandlerFunc) *Source {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	s := NewSource(srv.Client())
+	s.baseURL = srv.URL + "/"
+	s.interval = 0
+	return s
+}
+
+func TestLookupMetadata_ByRecordingID(t *testing.T) {
+	s := newTestSource(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/recording/mb-1", r.URL.Path)
+		assert.Equal(t, "isrcs releases", r.URL.Query().Get("inc"))
+		assert.Equal(t, "json", r.URL.Query().Get("fmt"))
+		assert.Contains(t, r.UserAgent(), "MusicMigration-API")
+		fmt.Fprint(w, `{"id":"mb-1","title":"One","length":215000,"isrcs":["GBAAA0000001"],"releases":[{"title":"First"}]}`)
+	})
+
+	m, err := s.LookupMetadata(context.Background(), domain.Track{Name: "One", MusicBrainzID: "mb-1"})
+	require.NoError(t, err)
+	assert.Equal(t, &domain.TrackMetadata{ISRC: "GBAAA0000001", DurationMS: 215000, Album: "First", MusicBrainzID: "mb-1"}, m)
+}
+
+func TestLookupMetadata_ByISRC(t *testing.T) {
+	s := newTestSource(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/isrc/GBAAA0000001", r.URL.Path)
+		fmt.Fprint(w, `{"isrc":"GBAAA0000001","recordings":[{"id":"mb-1","title":"One","length":215000,"releases":[{"title":"First"}]}]}`)
+	})
+
+	m, err := s.LookupMetadata(context.Background(), domain.Track{Name: "One", ISRC: "GBAAA0000001"})
+	require.NoError(t, err)
+	assert.Equal(t, &domain.TrackMetadata{ISRC: "GBAAA0000001", DurationMS: 215000, Album: "First", MusicBrainzID: "mb-1"}, m)
+}
+
+func TestLookupMetadata_SearchTakesBestMatch(t *testing.T) {
+	s := newTestSource(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/recording", r.URL.Path)
+		assert.Equal(t, `recording:"Say \"Hi\"" AND artist:"Band"`, r.URL.Query().Get("query"))
+		fmt.Fprint(w, `{"recordings":[
+			{"id":"cover","title":"Say \"Hi\"","length":1000,"artist-credit":[{"name":"Tribute"}]},
+			{"id":"mb-2","title":"Say \"Hi\"","length":180000,"isrcs":["USBBB0000002"],"artist-credit":[{"name":"Band"}],"releases":[{"title":"Hello"}]}
+		]}`)
+	})
+
+	m, err := s.LookupMetadata(context.Background(), domain.Track{Name: `Say "Hi"`, Artists: []string{"Band"}})
+	require.NoError(t, err)
+	assert.Equal(t, &domain.TrackMetadata{ISRC: "USBBB0000002", DurationMS: 180000, Album: "Hello", MusicBrainzID: "mb-2"}, m)
+}
+
+func TestLookupMetadata_Unknown(t *testing.T) {
+	s := newTestSource(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/recording" {
+			fmt.Fprint(w, `{"recordings":[{"id":"x","title":"Something Else","artist-credit":[{"name":"Other"}]}]}`)
+			return
+		}
+		http.NotFound(w, r)
+	})
+
+	m, err := s.LookupMetadata(context.Background(), domain.Track{Name: "One", Artists: []string{"Band"}})
+	require.NoError(t, err)
+	assert.Nil(t, m, "poor search results are not taken")
+
+	m, err = s.LookupMetadata(context.Background(), domain.Track{MusicBrainzID: "missing"})
+	require.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestLookupMetadata_Throttled(t *testing.T) {
+	s := newTestSource(t, func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "slow down", http.StatusServiceUnavailable)
+	})
+
+	_, err := s.LookupMetadata(context.Background(), domain.Track{ISRC: "GBAAA0000001"})
+	assert.ErrorIs(t, err, domain.ErrRateLimited)
+}
+
+func TestSource_SpacesRequests(t *testing.T) {
+	s := newTestSource(t, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+	s.interval = 20 * time.Millisecond
+
+	start := time.Now()
+	for range 3 {
+		_, err := s.LookupMetadata(context.Background(), domain.Track{MusicBrainzID: "mb-1"})
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
@@ -0,0 +1,149 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// -- Fake plugin implementation ----------------------------------------------
+
+type fakeProvider struct {
+	lastMarket string
+	added      []string
+}
+
+func (f *fakeProvider) Name() string { return "fake" }
+
+func (f *fakeProvider) GetPlaylists(_ context.Context, token string) ([]domain.Playlist, error) {
+	return []domain.Playlist{{ID: "pl-1", Name: "Mix for " + token}}, nil
+}
+
+func (f *fakeProvider) GetPlaylistsPage(_ context.Context, _ string, page domain.PageRequest) (*domain.PlaylistPage, error) {
+	return &domain.PlaylistPage{Items: []domain.Playlist{{ID: "pl-1"}}, NextCursor: page.Cursor + "next", Total: 2}, nil
+}
+
+func (f *fakeProvider) GetPlaylist(_ context.Context, _ string, id string) (*domain.Playlist, error) {
+	return nil, fmt.Errorf("fake: %s: %w", id, domain.ErrPlaylistNotFound)
+}
+
+func (f *fakeProvider) GetPlaylistTracks(_ context.Context, _ string, _ string) ([]domain.Track, error) {
+	return []domain.Track{{Name: "Song", Artists: []string{"Band"}, ExternalID: "t1"}}, nil
+}
+
+func (f *fakeProvider) SearchTrack(ctx context.Context, _ string, track domain.Track) (*domain.Track, float64, error) {
+	f.lastMarket = domain.MarketFromContext(ctx)
+	if track.Name == "missing" {
+		return nil, 0, nil
+	}
+	return &domain.Track{Name: track.Name, ExternalID: "t1"}, 0.8, nil
+}
+
+func (f *fakeProvider) CreatePlaylist(_ context.Context, _ string, name string, _ string) (string, error) {
+	return "new-" + name, nil
+}
+
+func (f *fakeProvider) AddTracksToPlaylist(_ context.Context, _ string, _ string, ids []string) ([]domain.AddOutcome, error) {
+	var outcomes []domain.AddOutcome
+	for _, id := range ids {
+		if id == "bad" {
+			outcomes = append(outcomes, domain.AddOutcome{TrackID: id, Error: "rejected"})
+			continue
+		}
+		f.added = append(f.added, id)
+		outcomes = append(outcomes, domain.AddOutcome{TrackID: id, Added: true})
+	}
+	return outcomes, nil
+}
+
+func (f *fakeProvider) RemoveTracksFromPlaylist(_ context.Context, _ string, _ string, _ []string) error {
+	return fmt.Errorf("boom")
+}
+
+func (f *fakeProvider) UpdatePlaylistDetails(_ context.Context, _ string, _ string, _ domain.PlaylistUpdate) error {
+	return nil
+}
+
+func (f *fakeProvider) DeletePlaylist(_ context.Context, _ string, _ string) error {
+	return nil
+}
+
+func connect(t *testing.T, impl *fakeProvider) *Provider {
+	t.Helper()
+	clientConn, serverConn := net.Pipe()
+	go ServeConn(impl, serverConn)
+
+	p, err := newProvider(clientConn)
+	require.NoError(t, err)
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+// -- Tests -------------------------------------------------------------------
+
+func TestPlugin_Handshake(t *testing.T) {
+	p := connect(t, &fakeProvider{})
+	assert.Equal(t, "fake", p.Name())
+}
+
+func TestPlugin_ForwardsCalls(t *testing.T) {
+	impl := &fakeProvider{}
+	p := connect(t, impl)
+	ctx := context.Background()
+
+	playlists, err := p.GetPlaylists(ctx, "tok")
+	require.NoError(t, err)
+	assert.Equal(t, "Mix for tok", playlists[0].Name)
+
+	page, err := p.GetPlaylistsPage(ctx, "tok", domain.PageRequest{Limit: 1, Cursor: "c"})
+	require.NoError(t, err)
+	assert.Equal(t, "cnext", page.NextCursor)
+
+	tracks, err := p.GetPlaylistTracks(ctx, "tok", "pl-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Band"}, tracks[0].Artists)
+
+	id, err := p.CreatePlaylist(ctx, "tok", "Road Trip", "")
+	require.NoError(t, err)
+	assert.Equal(t, "new-Road Trip", id)
+
+	outcomes, err := p.AddTracksToPlaylist(ctx, "tok", id, []string{"t1", "bad", "t2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t2"}, impl.added)
+	assert.Equal(t, []domain.AddOutcome{
+		{TrackID: "t1", Added: true},
+		{TrackID: "bad", Error: "rejected"},
+		{TrackID: "t2", Added: true},
+	}, outcomes)
+}
+
+func TestPlugin_SearchTrack(t *testing.T) {
+	impl := &fakeProvider{}
+	p := connect(t, impl)
+
+	matched, score, err := p.SearchTrack(domain.ContextWithMarket(context.Background(), "BR"), "tok", domain.Track{Name: "Song"})
+	require.NoError(t, err)
+	assert.Equal(t, "t1", matched.ExternalID)
+	assert.Equal(t, 0.8, score)
+	assert.Equal(t, "BR", impl.lastMarket)
+
+	matched, _, err = p.SearchTrack(context.Background(), "tok", domain.Track{Name: "missing"})
+	require.NoError(t, err)
+	assert.Nil(t, matched)
+}
+
+func TestPlugin_Errors(t *testing.T) {
+	p := connect(t, &fakeProvider{})
+
+	_, err := p.GetPlaylist(context.Background(), "tok", "nope")
+	assert.ErrorIs(t, err, domain.ErrPlaylistNotFound)
+
+	err = p.RemoveTracksFromPlaylist(context.Background(), "tok", "pl-1", []string{"t1"})
+	assert.EqualError(t, err, "fake: RemoveTracksFromPlaylist failed: boom")
+}
@@ -0,0 +1,81 @@
+// Package plugin lets third parties add providers without forking the API.
+// A plugin is an executable that serves a MusicProvider over JSON-RPC 1.0 on
+// its stdin and stdout; the API starts it at boot, asks for its name and
+// registers it like a built-in provider.
+//
+// Plugins written in Go only need to implement ports.MusicProvider and call
+// Serve from main. Plugins in other languages implement the "Provider.*"
+// methods below, each taking a single Request object as its only param.
+package plugin
+
+import (
+	"errors"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// serviceName prefixes every RPC method, e.g. "Provider.SearchTrack".
+const serviceName = "Provider"
+
+// Request carries the arguments of every plugin call; each method reads only
+// the fields it needs. Market is the requested search market, if any.
+type Request struct {
+	Token       string                `json:"token,omitempty"`
+	Market      string                `json:"market,omitempty"`
+	PlaylistID  string                `json:"playlist_id,omitempty"`
+	Page        domain.PageRequest    `json:"page"`
+	Track       domain.Track          `json:"track"`
+	Name        string                `json:"name,omitempty"`
+	Description string                `json:"description,omitempty"`
+	TrackIDs    []string              `json:"track_ids,omitempty"`
+	Update      domain.PlaylistUpdate `json:"update"`
+}
+
+// SearchReply is the result of Provider.SearchTrack. Track is null when
+// nothing matched.
+type SearchReply struct {
+	Track *domain.Track `json:"track"`
+	Score float64       `json:"score"`
+}
+
+// AddReply is the result of Provider.AddTracksToPlaylist: one outcome per
+// requested track, in order. Plugins that reply null are taken to have added
+// every track.
+type AddReply struct {
+	Outcomes []domain.AddOutcome `json:"outcomes"`
+}
+
+// Empty is the reply of methods that return nothing but an error.
+type Empty struct{}
+
+// sentinels are domain errors that survive the RPC boundary. Plugins signal
+// them by returning an error whose message equals the sentinel's.
+var sentinels = []error{
+	domain.ErrPlaylistNotFound,
+	domain.ErrUnavailableInMarket,
+	domain.ErrUnavailable,
+	domain.ErrRateLimited,
+	domain.ErrInvalidToken,
+	domain.ErrInsufficientScope,
+}
+
+// encodeError replaces errors wrapping a sentinel with the bare sentinel so
+// its message can be matched on the other side.
+func encodeError(err error) error {
+	for _, s := range sentinels {
+		if errors.Is(err, s) {
+			return s
+		}
+	}
+	return err
+}
+
+// decodeError maps an RPC error message back to its sentinel, if any.
+func decodeError(err error) error {
+	for _, s := range sentinels {
+		if err.Error() == s.Error() {
+			return s
+		}
+	}
+	return err
+}
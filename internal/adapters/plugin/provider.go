@@ -0,0 +1,190 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// handshakeTimeout bounds how long a plugin may take to report its name.
+const handshakeTimeout = 10 * time.Second
+
+// Provider implements ports.MusicProvider by forwarding every call to a
+// plugin process over JSON-RPC.
+type Provider struct {
+	name   string
+	client *rpc.Client
+	cmd    *exec.Cmd
+}
+
+// Start launches the plugin executable at path, connects to it over its
+// stdin and stdout and asks for its provider name. The plugin's stderr is
+// passed through to the API's.
+func Start(path string, args ...string) (*Provider, error) {
+	cmd := exec.Command(path, args...)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("plugin %s: failed to start: %w", path, err)
+	}
+
+	conn := struct {
+		io.Reader
+		io.Writer
+		io.Closer
+	}{stdout, stdin, stdin}
+
+	p, err := newProvider(conn)
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+	p.cmd = cmd
+	return p, nil
+}
+
+// newProvider connects to a plugin over conn and performs the name
+// handshake.
+func newProvider(conn io.ReadWriteCloser) (*Provider, error) {
+	p := &Provider{client: jsonrpc.NewClient(conn)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+	if err := p.call(ctx, "Name", Request{}, &p.name); err != nil {
+		p.client.Close()
+		return nil, fmt.Errorf("handshake failed: %w", err)
+	}
+	if p.name == "" {
+		p.client.Close()
+		return nil, fmt.Errorf("handshake failed: empty provider name")
+	}
+	return p, nil
+}
+
+// Close disconnects from the plugin and stops its process.
+func (p *Provider) Close() error {
+	err := p.client.Close()
+	if p.cmd != nil {
+		p.cmd.Process.Kill()
+		p.cmd.Wait()
+	}
+	return err
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+func (p *Provider) GetPlaylists(ctx context.Context, token string) ([]domain.Playlist, error) {
+	var playlists []domain.Playlist
+	if err := p.call(ctx, "GetPlaylists", Request{Token: token}, &playlists); err != nil {
+		return nil, err
+	}
+	return playlists, nil
+}
+
+func (p *Provider) GetPlaylistsPage(ctx context.Context, token string, page domain.PageRequest) (*domain.PlaylistPage, error) {
+	var result domain.PlaylistPage
+	if err := p.call(ctx, "GetPlaylistsPage", Request{Token: token, Page: page}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (p *Provider) GetPlaylist(ctx context.Context, token string, playlistID string) (*domain.Playlist, error) {
+	var playlist domain.Playlist
+	if err := p.call(ctx, "GetPlaylist", Request{Token: token, PlaylistID: playlistID}, &playlist); err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+func (p *Provider) GetPlaylistTracks(ctx context.Context, token string, playlistID string) ([]domain.Track, error) {
+	var tracks []domain.Track
+	if err := p.call(ctx, "GetPlaylistTracks", Request{Token: token, PlaylistID: playlistID}, &tracks); err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+func (p *Provider) SearchTrack(ctx context.Context, token string, track domain.Track) (*domain.Track, float64, error) {
+	var reply SearchReply
+	if err := p.call(ctx, "SearchTrack", Request{Token: token, Track: track}, &reply); err != nil {
+		return nil, 0, err
+	}
+	return reply.Track, reply.Score, nil
+}
+
+func (p *Provider) CreatePlaylist(ctx context.Context, token string, name string, description string) (string, error) {
+	var id string
+	if err := p.call(ctx, "CreatePlaylist", Request{Token: token, Name: name, Description: description}, &id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// AddTracksToPlaylist reports every track as not added if the plugin
+// returns an error, since JSON-RPC errors carry no result.
+func (p *Provider) AddTracksToPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) ([]domain.AddOutcome, error) {
+	var reply AddReply
+	if err := p.call(ctx, "AddTracksToPlaylist", Request{Token: token, PlaylistID: playlistID, TrackIDs: trackIDs}, &reply); err != nil {
+		return domain.NotAdded(nil, trackIDs, err), err
+	}
+	if reply.Outcomes == nil {
+		for _, id := range trackIDs {
+			reply.Outcomes = append(reply.Outcomes, domain.AddOutcome{TrackID: id, Added: true})
+		}
+	}
+	return reply.Outcomes, nil
+}
+
+func (p *Provider) RemoveTracksFromPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) error {
+	return p.call(ctx, "RemoveTracksFromPlaylist", Request{Token: token, PlaylistID: playlistID, TrackIDs: trackIDs}, &Empty{})
+}
+
+func (p *Provider) UpdatePlaylistDetails(ctx context.Context, token string, playlistID string, update domain.PlaylistUpdate) error {
+	return p.call(ctx, "UpdatePlaylistDetails", Request{Token: token, PlaylistID: playlistID, Update: update}, &Empty{})
+}
+
+func (p *Provider) DeletePlaylist(ctx context.Context, token string, playlistID string) error {
+	return p.call(ctx, "DeletePlaylist", Request{Token: token, PlaylistID: playlistID}, &Empty{})
+}
+
+// call invokes a plugin method, giving up when ctx is done. The plugin keeps
+// processing an abandoned call; its reply is discarded.
+func (p *Provider) call(ctx context.Context, method string, req Request, reply any) error {
+	req.Market = domain.MarketFromContext(ctx)
+
+	call := p.client.Go(serviceName+"."+method, req, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-call.Done:
+	}
+
+	if call.Error != nil {
+		name := p.name
+		if name == "" {
+			name = "plugin"
+		}
+		return fmt.Errorf("%s: %s failed: %w", name, method, adapters.SanitizeError(decodeError(call.Error)))
+	}
+	return nil
+}
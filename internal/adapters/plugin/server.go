@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"context"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// Serve exposes impl as a plugin over stdin and stdout and blocks until the
+// API closes the connection. Plugins must not write anything else to stdout;
+// use stderr for logs.
+func Serve(impl ports.MusicProvider) error {
+	return ServeConn(impl, struct {
+		io.Reader
+		io.Writer
+		io.Closer
+	}{os.Stdin, os.Stdout, os.Stdin})
+}
+
+// ServeConn exposes impl as a plugin over conn and blocks until it is closed.
+func ServeConn(impl ports.MusicProvider, conn io.ReadWriteCloser) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, &Handler{impl: impl}); err != nil {
+		return err
+	}
+	server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	return nil
+}
+
+// Handler adapts a MusicProvider to the net/rpc method signatures. It is
+// exported only because net/rpc requires it.
+type Handler struct {
+	impl ports.MusicProvider
+}
+
+func requestContext(req Request) context.Context {
+	ctx := context.Background()
+	if req.Market != "" {
+		ctx = domain.ContextWithMarket(ctx, req.Market)
+	}
+	return ctx
+}
+
+func (h *Handler) Name(_ Request, reply *string) error {
+	*reply = h.impl.Name()
+	return nil
+}
+
+func (h *Handler) GetPlaylists(req Request, reply *[]domain.Playlist) error {
+	playlists, err := h.impl.GetPlaylists(requestContext(req), req.Token)
+	if err != nil {
+		return encodeError(err)
+	}
+	*reply = playlists
+	return nil
+}
+
+func (h *Handler) GetPlaylistsPage(req Request, reply *domain.PlaylistPage) error {
+	page, err := h.impl.GetPlaylistsPage(requestContext(req), req.Token, req.Page)
+	if err != nil {
+		return encodeError(err)
+	}
+	*reply = *page
+	return nil
+}
+
+func (h *Handler) GetPlaylist(req Request, reply *domain.Playlist) error {
+	playlist, err := h.impl.GetPlaylist(requestContext(req), req.Token, req.PlaylistID)
+	if err != nil {
+		return encodeError(err)
+	}
+	*reply = *playlist
+	return nil
+}
+
+func (h *Handler) GetPlaylistTracks(req Request, reply *[]domain.Track) error {
+	tracks, err := h.impl.GetPlaylistTracks(requestContext(req), req.Token, req.PlaylistID)
+	if err != nil {
+		return encodeError(err)
+	}
+	*reply = tracks
+	return nil
+}
+
+func (h *Handler) SearchTrack(req Request, reply *SearchReply) error {
+	track, score, err := h.impl.SearchTrack(requestContext(req), req.Token, req.Track)
+	if err != nil {
+		return encodeError(err)
+	}
+	*reply = SearchReply{Track: track, Score: score}
+	return nil
+}
+
+func (h *Handler) CreatePlaylist(req Request, reply *string) error {
+	id, err := h.impl.CreatePlaylist(requestContext(req), req.Token, req.Name, req.Description)
+	if err != nil {
+		return encodeError(err)
+	}
+	*reply = id
+	return nil
+}
+
+func (h *Handler) AddTracksToPlaylist(req Request, reply *AddReply) error {
+	outcomes, err := h.impl.AddTracksToPlaylist(requestContext(req), req.Token, req.PlaylistID, req.TrackIDs)
+	if err != nil {
+		return encodeError(err)
+	}
+	*reply = AddReply{Outcomes: outcomes}
+	return nil
+}
+
+func (h *Handler) RemoveTracksFromPlaylist(req Request, _ *Empty) error {
+	return encodeError(h.impl.RemoveTracksFromPlaylist(requestContext(req), req.Token, req.PlaylistID, req.TrackIDs))
+}
+
+func (h *Handler) UpdatePlaylistDetails(req Request, _ *Empty) error {
+	return encodeError(h.impl.UpdatePlaylistDetails(requestContext(req), req.Token, req.PlaylistID, req.Update))
+}
+
+func (h *Handler) DeletePlaylist(req Request, _ *Empty) error {
+	return encodeError(h.impl.DeletePlaylist(requestContext(req), req.Token, req.PlaylistID))
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// AccountStore implements ports.AccountStore on PostgreSQL. Account limits are
+// stored as JSON, or an empty string if the account has none.
+type AccountStore struct {
+	db *sql.DB
+}
+
+// NewAccountStore creates an account store on a database returned by Open.
+func NewAccountStore(db *sql.DB) *AccountStore {
+	return &AccountStore{db: db}
+}
+
+const accountColumns = `id, name, api_key_hash, created_at, limits`
+
+func (s *AccountStore) Create(ctx context.Context, account *domain.Account) error {
+	limits, err := encodeLimits(account.Limits)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO accounts (`+accountColumns+`) VALUES ($1, $2, $3, $4, $5)`,
+		account.ID, account.Name, account.APIKeyHash, account.CreatedAt, limits,
+	)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to create account: %w", err)
+	}
+	return nil
+}
+
+func (s *AccountStore) GetByAPIKeyHash(ctx context.Context, hash string) (*domain.Account, error) {
+	return scanAccount(s.db.QueryRowContext(ctx,
+		`SELECT `+accountColumns+` FROM accounts WHERE api_key_hash = $1`, hash))
+}
+
+func (s *AccountStore) Get(ctx context.Context, id string) (*domain.Account, error) {
+	return scanAccount(s.db.QueryRowContext(ctx,
+		`SELECT `+accountColumns+` FROM accounts WHERE id = $1`, id))
+}
+
+func (s *AccountStore) SetLimits(ctx context.Context, id string, limits *domain.AccountLimits) error {
+	encoded, err := encodeLimits(limits)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx, `UPDATE accounts SET limits = $1 WHERE id = $2`, encoded, id)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to set account limits: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrAccountNotFound
+	}
+	return nil
+}
+
+func scanAccount(row *sql.Row) (*domain.Account, error) {
+	var account domain.Account
+	var limits string
+	err := row.Scan(&account.ID, &account.Name, &account.APIKeyHash, &account.CreatedAt, &limits)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAccountNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to get account: %w", err)
+	}
+	if limits != "" {
+		account.Limits = &domain.AccountLimits{}
+		if err := json.Unmarshal([]byte(limits), account.Limits); err != nil {
+			return nil, fmt.Errorf("postgres: failed to decode account limits: %w", err)
+		}
+	}
+	return &account, nil
+}
+
+func encodeLimits(limits *domain.AccountLimits) (string, error) {
+	if limits == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(limits)
+	if err != nil {
+		return "", fmt.Errorf("postgres: failed to encode account limits: %w", err)
+	}
+	return string(data), nil
+}
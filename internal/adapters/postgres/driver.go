@@ -0,0 +1,7 @@
+//go:build postgres
+
+package postgres
+
+import _ "github.com/jackc/pgx/v5/stdlib"
+
+const driverName = "pgx"
@@ -0,0 +1,6 @@
+//go:build !postgres
+
+package postgres
+
+// driverName is empty when the binary is built without the "postgres" tag.
+const driverName = ""
@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// JobQueue implements ports.JobQueue on PostgreSQL. Leases are claimed with a
+// single UPDATE of a row selected with SKIP LOCKED, so instances on any
+// number of hosts never run the same job at once, nor wait for each other.
+// Lease times are stored as Unix nanoseconds.
+type JobQueue struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewJobQueue creates a job queue on a database returned by Open.
+func NewJobQueue(db *sql.DB) *JobQueue {
+	return &JobQueue{db: db, now: time.Now}
+}
+
+// jobColumns lists the columns read by scanJob, in order.
+const jobColumns = `id, account_id, status, request, idempotency_key, tokens, priority, attempts, migration_id,
+	error, progress, debug, debug_log, lease_owner, lease_expires_at, created_at, updated_at, kind`
+
+func (q *JobQueue) Enqueue(ctx context.Context, job *domain.Job) error {
+	request, err := json.Marshal(job.Request)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to encode job request: %w", err)
+	}
+
+	priority := job.Priority
+	if priority == "" {
+		priority = domain.JobInteractive
+	}
+	kind := job.Kind
+	if kind == "" {
+		kind = domain.JobPlaylist
+	}
+	// A conflicting idempotency key inserts nothing instead of failing, so
+	// it can be told apart from other errors without the driver's types.
+	res, err := q.db.ExecContext(ctx,
+		`INSERT INTO jobs (id, account_id, kind, status, request, idempotency_key, tokens, priority, debug, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		 ON CONFLICT (account_id, idempotency_key) WHERE idempotency_key != '' DO NOTHING`,
+		job.ID, job.AccountID, kind, domain.JobQueued, string(request), job.Request.IdempotencyKey, job.Tokens, priority,
+		job.Debug, job.CreatedAt.UTC(), job.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to enqueue job: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("postgres: failed to enqueue job: %w", err)
+	}
+	if n == 0 {
+		return domain.ErrJobExists
+	}
+	return nil
+}
+
+func (q *JobQueue) Lease(ctx context.Context, owner string, lease time.Duration) (*domain.Job, error) {
+	// Bulk jobs go after interactive ones; within a priority, the accounts
+	// with the fewest running jobs, and then the ones served longest ago,
+	// go first.
+	now := q.now().UTC()
+	row := q.db.QueryRowContext(ctx,
+		`UPDATE jobs SET status = $1, attempts = attempts + 1, lease_owner = $2, lease_expires_at = $3, leased_at = $4, updated_at = $5
+		 WHERE id = (
+			SELECT j.id FROM jobs j
+			WHERE j.status = $6 OR (j.status = $7 AND j.lease_expires_at < $8)
+			ORDER BY
+				CASE j.priority WHEN $9 THEN 1 ELSE 0 END,
+				(SELECT COUNT(*) FROM jobs r WHERE r.account_id = j.account_id AND r.status = $10 AND r.lease_expires_at >= $11),
+				(SELECT MAX(r.leased_at) FROM jobs r WHERE r.account_id = j.account_id),
+				j.created_at
+			LIMIT 1
+			FOR UPDATE OF j SKIP LOCKED
+		 )
+		 RETURNING `+jobColumns,
+		domain.JobRunning, owner, now.Add(lease).UnixNano(), now.UnixNano(), now,
+		domain.JobQueued, domain.JobRunning, now.UnixNano(),
+		domain.JobBulk, domain.JobRunning, now.UnixNano(),
+	)
+	job, err := scanJob(row)
+	if errors.Is(err, domain.ErrJobNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to lease job: %w", err)
+	}
+	return job, nil
+}
+
+func (q *JobQueue) Renew(ctx context.Context, id string, owner string, lease time.Duration) error {
+	now := q.now().UTC()
+	res, err := q.db.ExecContext(ctx,
+		`UPDATE jobs SET lease_expires_at = $1, updated_at = $2 WHERE id = $3 AND status = $4 AND lease_owner = $5`,
+		now.Add(lease).UnixNano(), now, id, domain.JobRunning, owner,
+	)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to renew job lease: %w", err)
+	}
+	return q.requireUpdated(ctx, res, id)
+}
+
+func (q *JobQueue) SaveProgress(ctx context.Context, id string, owner string, progress domain.JobProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to encode job progress: %w", err)
+	}
+
+	res, err := q.db.ExecContext(ctx,
+		`UPDATE jobs SET progress = $1, updated_at = $2 WHERE id = $3 AND status = $4 AND lease_owner = $5`,
+		string(data), q.now().UTC(), id, domain.JobRunning, owner,
+	)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to save job progress: %w", err)
+	}
+	return q.requireUpdated(ctx, res, id)
+}
+
+func (q *JobQueue) Finish(ctx context.Context, job *domain.Job) error {
+	var debugLog []byte
+	if job.DebugLog != nil {
+		var err error
+		if debugLog, err = json.Marshal(job.DebugLog); err != nil {
+			return fmt.Errorf("postgres: failed to encode job debug log: %w", err)
+		}
+	}
+
+	res, err := q.db.ExecContext(ctx,
+		`UPDATE jobs SET status = $1, migration_id = $2, error = $3, debug_log = $4, tokens = NULL, lease_owner = '',
+			lease_expires_at = 0, updated_at = $5
+		 WHERE id = $6 AND status = $7 AND lease_owner = $8`,
+		job.Status, job.MigrationID, job.Error, string(debugLog), q.now().UTC(), job.ID, domain.JobRunning, job.LeaseOwner,
+	)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to finish job: %w", err)
+	}
+	return q.requireUpdated(ctx, res, job.ID)
+}
+
+func (q *JobQueue) Get(ctx context.Context, id string) (*domain.Job, error) {
+	job, err := scanJob(q.db.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id))
+	if err != nil && !errors.Is(err, domain.ErrJobNotFound) {
+		return nil, fmt.Errorf("postgres: failed to get job: %w", err)
+	}
+	return job, err
+}
+
+func (q *JobQueue) GetByIdempotencyKey(ctx context.Context, accountID string, key string) (*domain.Job, error) {
+	job, err := scanJob(q.db.QueryRowContext(ctx,
+		`SELECT `+jobColumns+` FROM jobs WHERE account_id = $1 AND idempotency_key = $2 AND idempotency_key != ''`,
+		accountID, key,
+	))
+	if err != nil && !errors.Is(err, domain.ErrJobNotFound) {
+		return nil, fmt.Errorf("postgres: failed to get job: %w", err)
+	}
+	return job, err
+}
+
+func (q *JobQueue) CountActive(ctx context.Context, accountID string) (int, error) {
+	var n int
+	err := q.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM jobs WHERE account_id = $1 AND status IN ($2, $3)`,
+		accountID, domain.JobQueued, domain.JobRunning,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("postgres: failed to count jobs: %w", err)
+	}
+	return n, nil
+}
+
+func (q *JobQueue) Cancel(ctx context.Context, id string) error {
+	res, err := q.db.ExecContext(ctx,
+		`UPDATE jobs SET status = $1, tokens = NULL, lease_owner = '', lease_expires_at = 0, updated_at = $2
+		 WHERE id = $3 AND status IN ($4, $5)`,
+		domain.JobCanceled, q.now().UTC(), id, domain.JobQueued, domain.JobRunning,
+	)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to cancel job: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("postgres: failed to cancel job: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+	if _, err := q.Get(ctx, id); err != nil {
+		return err
+	}
+	return domain.ErrJobFinished
+}
+
+// requireUpdated turns an update that matched no row into
+// domain.ErrJobLeaseLost, or domain.ErrJobNotFound if the job is gone.
+func (q *JobQueue) requireUpdated(ctx context.Context, res sql.Result, id string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("postgres: failed to update job: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+	if _, err := q.Get(ctx, id); err != nil {
+		return err
+	}
+	return domain.ErrJobLeaseLost
+}
+
+// scanJob reads a row selected with jobColumns. It returns
+// domain.ErrJobNotFound if there is no row.
+func scanJob(row *sql.Row) (*domain.Job, error) {
+	var (
+		job            domain.Job
+		request        string
+		idempotencyKey string
+		progress       string
+		debugLog       string
+		leaseExpiresAt int64
+	)
+	err := row.Scan(&job.ID, &job.AccountID, &job.Status, &request, &idempotencyKey, &job.Tokens, &job.Priority, &job.Attempts,
+		&job.MigrationID, &job.Error, &progress, &job.Debug, &debugLog, &job.LeaseOwner, &leaseExpiresAt,
+		&job.CreatedAt, &job.UpdatedAt, &job.Kind)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(request), &job.Request); err != nil {
+		return nil, fmt.Errorf("failed to decode job request: %w", err)
+	}
+	// The idempotency key is not part of the request's JSON form.
+	job.Request.IdempotencyKey = idempotencyKey
+	if progress != "" {
+		job.Progress = new(domain.JobProgress)
+		if err := json.Unmarshal([]byte(progress), job.Progress); err != nil {
+			return nil, fmt.Errorf("failed to decode job progress: %w", err)
+		}
+	}
+	if debugLog != "" {
+		job.DebugLog = new(domain.DebugLog)
+		if err := json.Unmarshal([]byte(debugLog), job.DebugLog); err != nil {
+			return nil, fmt.Errorf("failed to decode job debug log: %w", err)
+		}
+	}
+	if leaseExpiresAt != 0 {
+		job.LeaseExpiresAt = time.Unix(0, leaseExpiresAt).UTC()
+	}
+	return &job, nil
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Locker implements ports.Locker on PostgreSQL, so instances sharing the
+// database exclude each other, on whichever host they run. Expiry times
+// are stored as Unix nanoseconds.
+type Locker struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewLocker creates a locker on a database returned by Open.
+func NewLocker(db *sql.DB) *Locker {
+	return &Locker{db: db, now: time.Now}
+}
+
+func (l *Locker) Acquire(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	now := l.now()
+	// The upsert only replaces a lock that expired or is already ours, so
+	// exactly one of several concurrent callers changes a row.
+	res, err := l.db.ExecContext(ctx,
+		`INSERT INTO locks (key, owner, expires_at) VALUES ($1, $2, $3)
+		 ON CONFLICT (key) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+		 WHERE locks.owner = excluded.owner OR locks.expires_at <= $4`,
+		key, owner, now.Add(ttl).UnixNano(), now.UnixNano(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("postgres: failed to acquire lock: %w", err)
+	}
+	return affected(res)
+}
+
+func (l *Locker) Refresh(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	now := l.now()
+	res, err := l.db.ExecContext(ctx,
+		`UPDATE locks SET expires_at = $1 WHERE key = $2 AND owner = $3 AND expires_at > $4`,
+		now.Add(ttl).UnixNano(), key, owner, now.UnixNano(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("postgres: failed to refresh lock: %w", err)
+	}
+	return affected(res)
+}
+
+func (l *Locker) Release(ctx context.Context, key string, owner string) error {
+	if _, err := l.db.ExecContext(ctx, `DELETE FROM locks WHERE key = $1 AND owner = $2`, key, owner); err != nil {
+		return fmt.Errorf("postgres: failed to release lock: %w", err)
+	}
+	return nil
+}
+
+// affected reports whether a statement changed any row.
+func affected(res sql.Result) (bool, error) {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("postgres: failed to read affected rows: %w", err)
+	}
+	return n > 0, nil
+}
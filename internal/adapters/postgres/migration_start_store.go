@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// MigrationStartStore implements ports.MigrationStartStore on PostgreSQL. Start
+// times are stored as Unix nanoseconds.
+type MigrationStartStore struct {
+	db *sql.DB
+}
+
+// NewMigrationStartStore creates a migration start store on a database
+// returned by Open.
+func NewMigrationStartStore(db *sql.DB) *MigrationStartStore {
+	return &MigrationStartStore{db: db}
+}
+
+func (s *MigrationStartStore) Reserve(ctx context.Context, accountID string, id string, at time.Time, since time.Time, max int) (int, bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("postgres: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The advisory lock serializes the starts of the account across
+	// instances until the transaction ends, so they cannot both pass the
+	// count.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('migration_starts/' || $1))`, accountID); err != nil {
+		return 0, false, fmt.Errorf("postgres: failed to lock migration starts: %w", err)
+	}
+	// Starts before since no longer count against anything.
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM migration_starts WHERE account_id = $1 AND started_at < $2`, accountID, since.UnixNano(),
+	); err != nil {
+		return 0, false, fmt.Errorf("postgres: failed to prune migration starts: %w", err)
+	}
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO migration_starts (id, account_id, started_at)
+		 SELECT $1, $2, $3 WHERE (SELECT COUNT(*) FROM migration_starts WHERE account_id = $2) < $4`,
+		id, accountID, at.UnixNano(), max,
+	)
+	if err != nil {
+		return 0, false, fmt.Errorf("postgres: failed to reserve migration start: %w", err)
+	}
+	ok, err := affected(res)
+	if err != nil {
+		return 0, false, err
+	}
+	var n int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM migration_starts WHERE account_id = $1`, accountID,
+	).Scan(&n); err != nil {
+		return 0, false, fmt.Errorf("postgres: failed to count migration starts: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("postgres: failed to commit migration start: %w", err)
+	}
+	return n, ok, nil
+}
+
+func (s *MigrationStartStore) Count(ctx context.Context, accountID string, since time.Time) (int, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM migration_starts WHERE account_id = $1 AND started_at >= $2`, accountID, since.UnixNano(),
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("postgres: failed to count migration starts: %w", err)
+	}
+	return n, nil
+}
+
+func (s *MigrationStartStore) Cancel(ctx context.Context, accountID string, id string) error {
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM migration_starts WHERE account_id = $1 AND id = $2`, accountID, id,
+	); err != nil {
+		return fmt.Errorf("postgres: failed to cancel migration start: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// Package postgres implements the storage ports on a PostgreSQL database,
+// so several API instances on different hosts can share migrations, locks
+// and the job queue. The driver is only linked in when building with the
+// "postgres" tag:
+//
+//	go build -tags postgres ./cmd/api
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrNotCompiled is returned by Open when the binary was built without the
+// "postgres" build tag.
+var ErrNotCompiled = errors.New("postgres: support not compiled in, rebuild with -tags postgres")
+
+// migrationLockID is the advisory lock that serializes schema migrations
+// of instances starting at the same time.
+const migrationLockID = 0x6d6d6967 // "mmig"
+
+// schema holds the migrations applied in order at startup. The number of
+// applied migrations is tracked in the schema_version table, so existing
+// entries must never be edited; append new ones instead.
+var schema = []string{
+	`CREATE TABLE migrations (
+		id              TEXT PRIMARY KEY,
+		account_id      TEXT NOT NULL DEFAULT '',
+		idempotency_key TEXT NOT NULL DEFAULT '',
+		created_at      TIMESTAMPTZ NOT NULL,
+		data            TEXT NOT NULL
+	);
+	CREATE INDEX migrations_account_created ON migrations (account_id, created_at);
+	CREATE UNIQUE INDEX migrations_idempotency_key ON migrations (account_id, idempotency_key)
+		WHERE idempotency_key != '';
+
+	CREATE TABLE accounts (
+		id           TEXT PRIMARY KEY,
+		name         TEXT NOT NULL,
+		api_key_hash TEXT NOT NULL UNIQUE,
+		created_at   TIMESTAMPTZ NOT NULL,
+		limits       TEXT NOT NULL DEFAULT ''
+	);
+
+	CREATE TABLE tokens (
+		account_id TEXT NOT NULL,
+		provider   TEXT NOT NULL,
+		ciphertext BYTEA NOT NULL,
+		PRIMARY KEY (account_id, provider)
+	);
+
+	CREATE TABLE track_mappings (
+		from_provider TEXT NOT NULL,
+		from_id       TEXT NOT NULL,
+		to_provider   TEXT NOT NULL,
+		to_track      TEXT NOT NULL,
+		score         DOUBLE PRECISION NOT NULL,
+		updated_at    TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (from_provider, from_id, to_provider)
+	);
+
+	CREATE TABLE match_feedback (
+		provider_a TEXT NOT NULL,
+		id_a       TEXT NOT NULL,
+		provider_b TEXT NOT NULL,
+		id_b       TEXT NOT NULL,
+		account_id TEXT NOT NULL,
+		verdict    TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (provider_a, id_a, provider_b, id_b, account_id)
+	);
+
+	CREATE TABLE golden_matches (
+		id              TEXT PRIMARY KEY,
+		source_provider TEXT NOT NULL,
+		dest_provider   TEXT NOT NULL,
+		updated_at      TIMESTAMPTZ NOT NULL,
+		data            TEXT NOT NULL
+	);
+	CREATE INDEX golden_matches_updated ON golden_matches (updated_at);
+
+	CREATE TABLE jobs (
+		id               TEXT PRIMARY KEY,
+		account_id       TEXT NOT NULL DEFAULT '',
+		status           TEXT NOT NULL,
+		request          TEXT NOT NULL,
+		idempotency_key  TEXT NOT NULL DEFAULT '',
+		tokens           BYTEA,
+		priority         TEXT NOT NULL DEFAULT 'interactive',
+		attempts         INTEGER NOT NULL DEFAULT 0,
+		migration_id     TEXT NOT NULL DEFAULT '',
+		error            TEXT NOT NULL DEFAULT '',
+		progress         TEXT NOT NULL DEFAULT '',
+		debug            BOOLEAN NOT NULL DEFAULT FALSE,
+		debug_log        TEXT NOT NULL DEFAULT '',
+		lease_owner      TEXT NOT NULL DEFAULT '',
+		lease_expires_at BIGINT NOT NULL DEFAULT 0,
+		leased_at        BIGINT NOT NULL DEFAULT 0,
+		created_at       TIMESTAMPTZ NOT NULL,
+		updated_at       TIMESTAMPTZ NOT NULL
+	);
+	CREATE INDEX jobs_status_created ON jobs (status, created_at);
+	CREATE INDEX jobs_account_status ON jobs (account_id, status);
+	CREATE UNIQUE INDEX jobs_idempotency_key ON jobs (account_id, idempotency_key)
+		WHERE idempotency_key != '';
+
+	CREATE TABLE locks (
+		key        TEXT PRIMARY KEY,
+		owner      TEXT NOT NULL,
+		expires_at BIGINT NOT NULL
+	);
+
+	CREATE TABLE search_cache (
+		key        TEXT PRIMARY KEY,
+		data       BYTEA NOT NULL,
+		expires_at BIGINT NOT NULL
+	);
+
+	CREATE TABLE profiles (
+		id         TEXT PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL,
+		data       TEXT NOT NULL
+	);
+	CREATE INDEX profiles_account_created ON profiles (account_id, created_at);
+
+	CREATE TABLE audit_log (
+		id         TEXT PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		provider   TEXT NOT NULL,
+		action     TEXT NOT NULL,
+		started_at BIGINT NOT NULL,
+		data       TEXT NOT NULL
+	);
+	CREATE INDEX audit_log_started ON audit_log (started_at);
+	CREATE INDEX audit_log_account_started ON audit_log (account_id, started_at);
+	CREATE FUNCTION audit_log_append_only() RETURNS trigger LANGUAGE plpgsql AS $$
+	BEGIN
+		RAISE EXCEPTION 'audit log is append-only';
+	END;
+	$$;
+	CREATE TRIGGER audit_log_no_change BEFORE UPDATE OR DELETE ON audit_log
+		FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();`,
+
+	`CREATE TABLE migration_starts (
+		id         TEXT PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		started_at BIGINT NOT NULL
+	);
+	CREATE INDEX migration_starts_account ON migration_starts (account_id, started_at);`,
+
+	`ALTER TABLE jobs ADD COLUMN kind TEXT NOT NULL DEFAULT 'playlist';`,
+}
+
+// Open connects to the PostgreSQL database named by dsn, a URL or
+// key=value connection string, and applies any pending schema migrations.
+func Open(dsn string) (*sql.DB, error) {
+	if driverName == "" {
+		return nil, ErrNotCompiled
+	}
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to open database: %w", err)
+	}
+	if err := migrate(context.Background(), db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// migrate applies the pending migrations in one transaction, holding an
+// advisory lock so instances starting together apply each one once.
+func migrate(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to begin migrations: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("postgres: failed to lock schema: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)`); err != nil {
+		return fmt.Errorf("postgres: failed to create schema_version: %w", err)
+	}
+
+	var version int
+	err = tx.QueryRowContext(ctx, `SELECT version FROM schema_version`).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = tx.ExecContext(ctx, `INSERT INTO schema_version (version) VALUES (0)`)
+	}
+	if err != nil {
+		return fmt.Errorf("postgres: failed to read schema version: %w", err)
+	}
+
+	for i := version; i < len(schema); i++ {
+		if _, err := tx.ExecContext(ctx, schema[i]); err != nil {
+			return fmt.Errorf("postgres: migration %d failed: %w", i+1, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE schema_version SET version = $1`, len(schema)); err != nil {
+		return fmt.Errorf("postgres: failed to record schema version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("postgres: failed to commit migrations: %w", err)
+	}
+	return nil
+}
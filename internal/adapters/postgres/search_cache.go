@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// SearchCache implements ports.SearchCache on PostgreSQL, so cached searches
+// survive restarts. Expiry times are stored as Unix nanoseconds; expired
+// rows are replaced by the next Put of their key.
+type SearchCache struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewSearchCache creates a search cache on a database returned by Open.
+func NewSearchCache(db *sql.DB) *SearchCache {
+	return &SearchCache{db: db, now: time.Now}
+}
+
+func (c *SearchCache) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := c.db.QueryRowContext(ctx,
+		`SELECT data FROM search_cache WHERE key = $1 AND expires_at > $2`,
+		key, c.now().UnixNano(),
+	).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to read search cache: %w", err)
+	}
+	return data, nil
+}
+
+func (c *SearchCache) Put(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	_, err := c.db.ExecContext(ctx,
+		`INSERT INTO search_cache (key, data, expires_at) VALUES ($1, $2, $3)
+		 ON CONFLICT (key) DO UPDATE SET data = excluded.data, expires_at = excluded.expires_at`,
+		key, data, c.now().Add(ttl).UnixNano(),
+	)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to write search cache: %w", err)
+	}
+	return nil
+}
+
+func (c *SearchCache) Delete(ctx context.Context, key string) error {
+	if _, err := c.db.ExecContext(ctx, `DELETE FROM search_cache WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("postgres: failed to delete search cache entry: %w", err)
+	}
+	return nil
+}
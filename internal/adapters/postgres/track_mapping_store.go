@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// TrackMappingStore implements ports.TrackMappingStore on PostgreSQL. Each
+// mapping is stored as one row per direction.
+type TrackMappingStore struct {
+	db *sql.DB
+}
+
+// NewTrackMappingStore creates a track mapping store on a database returned
+// by Open.
+func NewTrackMappingStore(db *sql.DB) *TrackMappingStore {
+	return &TrackMappingStore{db: db}
+}
+
+func (s *TrackMappingStore) Save(ctx context.Context, mapping *domain.TrackMapping) error {
+	trackA, err := json.Marshal(mapping.TrackA)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to encode track: %w", err)
+	}
+	trackB, err := json.Marshal(mapping.TrackB)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to encode track: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	const upsert = `INSERT INTO track_mappings (from_provider, from_id, to_provider, to_track, score, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (from_provider, from_id, to_provider) DO UPDATE
+		SET to_track = excluded.to_track, score = excluded.score, updated_at = excluded.updated_at`
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, upsert,
+		mapping.ProviderA, mapping.TrackA.ExternalID, mapping.ProviderB, string(trackB), mapping.Score, now); err != nil {
+		return fmt.Errorf("postgres: failed to save track mapping: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, upsert,
+		mapping.ProviderB, mapping.TrackB.ExternalID, mapping.ProviderA, string(trackA), mapping.Score, now); err != nil {
+		return fmt.Errorf("postgres: failed to save track mapping: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (s *TrackMappingStore) Find(ctx context.Context, fromProvider string, fromID string, toProvider string) (*domain.Track, float64, error) {
+	var (
+		data  string
+		score float64
+	)
+	err := s.db.QueryRowContext(ctx,
+		`SELECT to_track, score FROM track_mappings WHERE from_provider = $1 AND from_id = $2 AND to_provider = $3`,
+		fromProvider, fromID, toProvider,
+	).Scan(&data, &score)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, domain.ErrMappingNotFound
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("postgres: failed to find track mapping: %w", err)
+	}
+
+	var track domain.Track
+	if err := json.Unmarshal([]byte(data), &track); err != nil {
+		return nil, 0, fmt.Errorf("postgres: failed to decode track: %w", err)
+	}
+	return &track, score, nil
+}
+
+func (s *TrackMappingStore) Delete(ctx context.Context, providerA string, idA string, providerB string, idB string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteMapping(ctx, tx, providerA, idA, providerB, idB); err != nil {
+		return err
+	}
+	if err := deleteMapping(ctx, tx, providerB, idB, providerA, idA); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteMapping removes the mapping of the track with ID fromID on
+// fromProvider to toProvider if it maps to the track with ID toID.
+func deleteMapping(ctx context.Context, tx *sql.Tx, fromProvider, fromID, toProvider, toID string) error {
+	var data string
+	err := tx.QueryRowContext(ctx,
+		`SELECT to_track FROM track_mappings WHERE from_provider = $1 AND from_id = $2 AND to_provider = $3`,
+		fromProvider, fromID, toProvider,
+	).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("postgres: failed to find track mapping: %w", err)
+	}
+
+	var track domain.Track
+	if err := json.Unmarshal([]byte(data), &track); err != nil {
+		return fmt.Errorf("postgres: failed to decode track: %w", err)
+	}
+	if track.ExternalID != toID {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM track_mappings WHERE from_provider = $1 AND from_id = $2 AND to_provider = $3`,
+		fromProvider, fromID, toProvider,
+	); err != nil {
+		return fmt.Errorf("postgres: failed to delete track mapping: %w", err)
+	}
+	return nil
+}
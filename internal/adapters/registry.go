@@ -2,22 +2,29 @@ package adapters
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
 	"github.com/jpp0ca/MusicMigration-API/internal/ports"
 )
 
 // ProviderRegistry maps provider names to their MusicProvider implementations.
-// It is safe for concurrent use.
+// Providers can be disabled and re-enabled at runtime. It is safe for
+// concurrent use.
 type ProviderRegistry struct {
 	mu        sync.RWMutex
 	providers map[string]ports.MusicProvider
+
+	// disabled maps the names of disabled providers to the reason given.
+	disabled map[string]string
 }
 
 // NewProviderRegistry creates an empty registry.
 func NewProviderRegistry() *ProviderRegistry {
 	return &ProviderRegistry{
 		providers: make(map[string]ports.MusicProvider),
+		disabled:  make(map[string]string),
 	}
 }
 
@@ -28,19 +35,28 @@ func (r *ProviderRegistry) Register(provider ports.MusicProvider) {
 	r.providers[provider.Name()] = provider
 }
 
-// Get returns the provider for the given name, or an error if not found.
+// Get returns the provider for the given name. It returns an error matching
+// domain.ErrProviderNotFound if none is registered, or
+// domain.ErrProviderDisabled if it has been disabled.
 func (r *ProviderRegistry) Get(name string) (ports.MusicProvider, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	provider, ok := r.providers[name]
 	if !ok {
-		return nil, fmt.Errorf("unknown provider: %s", name)
+		return nil, fmt.Errorf("%w: %s", domain.ErrProviderNotFound, name)
+	}
+	if reason, ok := r.disabled[name]; ok {
+		if reason == "" {
+			return nil, fmt.Errorf("%w: %s", domain.ErrProviderDisabled, name)
+		}
+		return nil, fmt.Errorf("%w: %s (%s)", domain.ErrProviderDisabled, name, reason)
 	}
 	return provider, nil
 }
 
-// Available returns the names of all registered providers.
+// Available returns the names of all registered providers, including
+// disabled ones.
 func (r *ProviderRegistry) Available() []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -51,3 +67,47 @@ func (r *ProviderRegistry) Available() []string {
 	}
 	return names
 }
+
+// Statuses reports every registered provider and whether it is enabled,
+// sorted by name.
+func (r *ProviderRegistry) Statuses() []domain.ProviderStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]domain.ProviderStatus, 0, len(r.providers))
+	for name := range r.providers {
+		reason, disabled := r.disabled[name]
+		statuses = append(statuses, domain.ProviderStatus{
+			Name:           name,
+			Enabled:        !disabled,
+			DisabledReason: reason,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Disable makes Get fail for the named provider until Enable is called.
+func (r *ProviderRegistry) Disable(name string, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.providers[name]; !ok {
+		return fmt.Errorf("%w: %s", domain.ErrProviderNotFound, name)
+	}
+	r.disabled[name] = reason
+	return nil
+}
+
+// Enable re-enables a disabled provider. Enabling an enabled provider is a
+// no-op.
+func (r *ProviderRegistry) Enable(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.providers[name]; !ok {
+		return fmt.Errorf("%w: %s", domain.ErrProviderNotFound, name)
+	}
+	delete(r.disabled, name)
+	return nil
+}
@@ -19,6 +19,12 @@ func (s *stubProvider) Name() string { return s.name }
 func (s *stubProvider) GetPlaylists(_ context.Context, _ string) ([]domain.Playlist, error) {
 	return nil, nil
 }
+func (s *stubProvider) GetPlaylistsPage(_ context.Context, _ string, _ domain.PageRequest) (*domain.PlaylistPage, error) {
+	return nil, nil
+}
+func (s *stubProvider) GetPlaylist(_ context.Context, _ string, _ string) (*domain.Playlist, error) {
+	return nil, nil
+}
 func (s *stubProvider) GetPlaylistTracks(_ context.Context, _ string, _ string) ([]domain.Track, error) {
 	return nil, nil
 }
@@ -28,7 +34,16 @@ func (s *stubProvider) SearchTrack(_ context.Context, _ string, _ domain.Track)
 func (s *stubProvider) CreatePlaylist(_ context.Context, _ string, _ string, _ string) (string, error) {
 	return "", nil
 }
-func (s *stubProvider) AddTracksToPlaylist(_ context.Context, _ string, _ string, _ []string) error {
+func (s *stubProvider) AddTracksToPlaylist(_ context.Context, _ string, _ string, _ []string) ([]domain.AddOutcome, error) {
+	return nil, nil
+}
+func (s *stubProvider) RemoveTracksFromPlaylist(_ context.Context, _ string, _ string, _ []string) error {
+	return nil
+}
+func (s *stubProvider) UpdatePlaylistDetails(_ context.Context, _ string, _ string, _ domain.PlaylistUpdate) error {
+	return nil
+}
+func (s *stubProvider) DeletePlaylist(_ context.Context, _ string, _ string) error {
 	return nil
 }
 
@@ -75,3 +90,26 @@ func TestProviderRegistry_OverwriteExisting(t *testing.T) {
 	available := registry.Available()
 	assert.Len(t, available, 1)
 }
+
+func TestProviderRegistry_DisableEnable(t *testing.T) {
+	registry := NewProviderRegistry()
+	registry.Register(&stubProvider{name: "spotify"})
+	registry.Register(&stubProvider{name: "youtube"})
+
+	require.NoError(t, registry.Disable("youtube", "quota exhausted"))
+
+	_, err := registry.Get("youtube")
+	require.ErrorIs(t, err, domain.ErrProviderDisabled)
+	assert.Contains(t, err.Error(), "quota exhausted")
+	assert.ElementsMatch(t, []string{"spotify", "youtube"}, registry.Available())
+	assert.Equal(t, []domain.ProviderStatus{
+		{Name: "spotify", Enabled: true},
+		{Name: "youtube", Enabled: false, DisabledReason: "quota exhausted"},
+	}, registry.Statuses())
+
+	require.NoError(t, registry.Enable("youtube"))
+	_, err = registry.Get("youtube")
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, registry.Disable("deezer", ""), domain.ErrProviderNotFound)
+}
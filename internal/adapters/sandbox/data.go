@@ -0,0 +1,41 @@
+package sandbox
+
+import "github.com/jpp0ca/MusicMigration-API/internal/domain"
+
+// catalog is the fixed set of tracks the sandbox knows about. Seed playlists
+// are built from it and searches match against it.
+var catalog = []domain.Track{
+	{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, Album: "A Night at the Opera", ISRC: "GBUM71029604", ExternalID: "sandbox-track-1", Type: domain.ItemTypeTrack},
+	{Name: "Smells Like Teen Spirit", Artists: []string{"Nirvana"}, Album: "Nevermind", ISRC: "USGF19942501", ExternalID: "sandbox-track-2", Type: domain.ItemTypeTrack},
+	{Name: "Back in Black", Artists: []string{"AC/DC"}, Album: "Back in Black", ISRC: "AUAP08000046", ExternalID: "sandbox-track-3", Type: domain.ItemTypeTrack},
+	{Name: "Get Lucky", Artists: []string{"Daft Punk", "Pharrell Williams"}, Album: "Random Access Memories", ISRC: "USQX91300108", ExternalID: "sandbox-track-4", Type: domain.ItemTypeTrack},
+	{Name: "This Is What You Came For", Artists: []string{"Calvin Harris", "Rihanna"}, Album: "This Is What You Came For", ISRC: "GBARL1600471", ExternalID: "sandbox-track-5", Type: domain.ItemTypeTrack},
+	{Name: "Blinding Lights", Artists: []string{"The Weeknd"}, Album: "After Hours", ISRC: "USUG11904206", ExternalID: "sandbox-track-6", Type: domain.ItemTypeTrack},
+}
+
+// seedPlaylists returns fresh copies of the playlists every sandbox starts
+// with.
+func seedPlaylists() []*playlist {
+	return []*playlist{
+		{
+			Playlist: domain.Playlist{
+				ID:          "sandbox-rock",
+				Name:        "Sandbox Rock Classics",
+				Description: "Deterministic sample playlist",
+				OwnerName:   "Sandbox User",
+				IsOwner:     true,
+			},
+			tracks: append([]domain.Track(nil), catalog[0:3]...),
+		},
+		{
+			Playlist: domain.Playlist{
+				ID:          "sandbox-mix",
+				Name:        "Sandbox Mix",
+				Description: "Deterministic sample playlist with collaborations",
+				OwnerName:   "Sandbox User",
+				IsOwner:     true,
+			},
+			tracks: append([]domain.Track(nil), catalog[3:6]...),
+		},
+	}
+}
@@ -0,0 +1,352 @@
+// Package sandbox provides a fake provider with deterministic in-memory data,
+// so the full migration flow can be exercised without real tokens or quota.
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/pkg/matching"
+)
+
+// ProviderName is the name the sandbox registers under.
+const ProviderName = "sandbox"
+
+// Provider implements ports.MusicProvider entirely in memory. Any non-empty
+// token is accepted. It is safe for concurrent use.
+type Provider struct {
+	failureRate float64
+
+	mu        sync.Mutex
+	playlists map[string]*playlist
+	nextID    int
+}
+
+type playlist struct {
+	domain.Playlist
+	tracks []domain.Track
+}
+
+// Option configures optional behavior of a Provider.
+type Option func(*Provider)
+
+// WithFailureRate makes the given fraction (0.0 to 1.0) of track searches
+// fail. Which tracks fail is derived from their name and artist, so the same
+// tracks fail on every run.
+func WithFailureRate(rate float64) Option {
+	return func(p *Provider) {
+		p.failureRate = min(max(rate, 0), 1)
+	}
+}
+
+// NewProvider creates a sandbox provider seeded with the sample playlists.
+func NewProvider(opts ...Option) *Provider {
+	p := &Provider{playlists: make(map[string]*playlist)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	for _, pl := range seedPlaylists() {
+		p.playlists[pl.ID] = pl
+	}
+	return p
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+func (p *Provider) GetPlaylists(ctx context.Context, token string) ([]domain.Playlist, error) {
+	page, err := p.GetPlaylistsPage(ctx, token, domain.PageRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+func (p *Provider) GetPlaylistsPage(_ context.Context, token string, page domain.PageRequest) (*domain.PlaylistPage, error) {
+	if err := checkToken(token); err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if page.Cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(page.Cursor); err != nil || offset < 0 {
+			return nil, fmt.Errorf("sandbox: %w %q", domain.ErrInvalidCursor, page.Cursor)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	all := make([]domain.Playlist, 0, len(p.playlists))
+	for _, pl := range p.playlists {
+		all = append(all, pl.summary())
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+
+	limit := page.Limit
+	if limit <= 0 {
+		limit = len(all)
+	}
+	end := min(offset+limit, len(all))
+	offset = min(offset, end)
+
+	result := &domain.PlaylistPage{Items: all[offset:end], Total: len(all)}
+	if end < len(all) {
+		result.NextCursor = strconv.Itoa(end)
+	}
+	return result, nil
+}
+
+func (p *Provider) GetPlaylist(_ context.Context, token string, playlistID string) (*domain.Playlist, error) {
+	if err := checkToken(token); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pl, ok := p.playlists[playlistID]
+	if !ok {
+		return nil, domain.ErrPlaylistNotFound
+	}
+	summary := pl.summary()
+	return &summary, nil
+}
+
+func (p *Provider) GetPlaylistTracks(_ context.Context, token string, playlistID string) ([]domain.Track, error) {
+	if err := checkToken(token); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pl, ok := p.playlists[playlistID]
+	if !ok {
+		return nil, domain.ErrPlaylistNotFound
+	}
+	return append([]domain.Track(nil), pl.tracks...), nil
+}
+
+// SearchTrack matches against the sample catalog by ISRC, then by name and
+// artist. Tracks outside the catalog still match with a lower score, so
+// migrations from real providers produce results too.
+func (p *Provider) SearchTrack(_ context.Context, token string, track domain.Track) (*domain.Track, float64, error) {
+	if err := checkToken(token); err != nil {
+		return nil, 0, err
+	}
+	if p.shouldFail(track) {
+		return nil, 0, fmt.Errorf("sandbox: simulated search failure for %q", track.Name)
+	}
+
+	for _, t := range catalog {
+		if track.ISRC != "" && strings.EqualFold(track.ISRC, t.ISRC) {
+			matched := t
+			return &matched, 1.0, nil
+		}
+	}
+	for _, t := range catalog {
+		if strings.EqualFold(track.Name, t.Name) && matching.ArtistOverlap(track.Artists, t.Artists) > 0 {
+			matched := t
+			return &matched, 0.9, nil
+		}
+	}
+
+	matched := track
+	matched.ExternalID = "sandbox-" + strconv.FormatUint(uint64(hashTrack(track)), 16)
+	return &matched, 0.75, nil
+}
+
+func (p *Provider) CreatePlaylist(_ context.Context, token string, name string, description string) (string, error) {
+	if err := checkToken(token); err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextID++
+	id := fmt.Sprintf("sandbox-created-%d", p.nextID)
+	p.playlists[id] = &playlist{Playlist: domain.Playlist{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		OwnerName:   "Sandbox User",
+		IsOwner:     true,
+	}}
+	return id, nil
+}
+
+func (p *Provider) AddTracksToPlaylist(_ context.Context, token string, playlistID string, trackIDs []string) ([]domain.AddOutcome, error) {
+	return p.addTracks(token, playlistID, -1, trackIDs)
+}
+
+// InsertTracksAt implements ports.PositionalAdder. Positions past the end
+// append.
+func (p *Provider) InsertTracksAt(_ context.Context, token string, playlistID string, position int, trackIDs []string) ([]domain.AddOutcome, error) {
+	return p.addTracks(token, playlistID, max(position, 0), trackIDs)
+}
+
+// addTracks inserts tracks at position, or appends them if position is
+// negative. IDs that did not come from this provider are rejected, which
+// lets end-to-end tests exercise partial adds.
+func (p *Provider) addTracks(token string, playlistID string, position int, trackIDs []string) ([]domain.AddOutcome, error) {
+	if err := checkToken(token); err != nil {
+		return domain.NotAdded(nil, trackIDs, err), err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pl, ok := p.playlists[playlistID]
+	if !ok {
+		return domain.NotAdded(nil, trackIDs, domain.ErrPlaylistNotFound), domain.ErrPlaylistNotFound
+	}
+
+	outcomes := make([]domain.AddOutcome, 0, len(trackIDs))
+	var ids []string
+	for _, id := range trackIDs {
+		if !strings.HasPrefix(id, "sandbox-") {
+			outcomes = append(outcomes, domain.AddOutcome{TrackID: id, Error: fmt.Sprintf("sandbox: unknown track %q", id)})
+			continue
+		}
+		outcomes = append(outcomes, domain.AddOutcome{TrackID: id, Added: true})
+		ids = append(ids, id)
+	}
+
+	if position < 0 || position > len(pl.tracks) {
+		position = len(pl.tracks)
+	}
+	pl.tracks = slices.Insert(pl.tracks, position, catalogTracks(ids)...)
+	return outcomes, nil
+}
+
+func (p *Provider) RemoveTracksFromPlaylist(_ context.Context, token string, playlistID string, trackIDs []string) error {
+	if err := checkToken(token); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pl, ok := p.playlists[playlistID]
+	if !ok {
+		return domain.ErrPlaylistNotFound
+	}
+	remove := make(map[string]bool, len(trackIDs))
+	for _, id := range trackIDs {
+		remove[id] = true
+	}
+	kept := pl.tracks[:0]
+	for _, t := range pl.tracks {
+		if !remove[t.ExternalID] {
+			kept = append(kept, t)
+		}
+	}
+	pl.tracks = kept
+	return nil
+}
+
+func (p *Provider) UpdatePlaylistDetails(_ context.Context, token string, playlistID string, update domain.PlaylistUpdate) error {
+	if err := checkToken(token); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pl, ok := p.playlists[playlistID]
+	if !ok {
+		return domain.ErrPlaylistNotFound
+	}
+	if update.Name != nil {
+		pl.Name = *update.Name
+	}
+	if update.Description != nil {
+		pl.Description = *update.Description
+	}
+	if update.Public != nil {
+		pl.IsPublic = *update.Public
+	}
+	if update.Collaborative != nil {
+		pl.IsCollaborative = *update.Collaborative
+		if pl.IsCollaborative {
+			pl.IsPublic = false
+		}
+	}
+	return nil
+}
+
+// CollaborativePlaylists implements ports.CollaborativePlaylists, mirroring
+// Spotify.
+func (p *Provider) CollaborativePlaylists() {}
+
+func (p *Provider) DeletePlaylist(_ context.Context, token string, playlistID string) error {
+	if err := checkToken(token); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.playlists[playlistID]; !ok {
+		return domain.ErrPlaylistNotFound
+	}
+	delete(p.playlists, playlistID)
+	return nil
+}
+
+// -- Helpers -----------------------------------------------------------------
+
+func (pl *playlist) summary() domain.Playlist {
+	summary := pl.Playlist
+	summary.TrackCount = len(pl.tracks)
+	return summary
+}
+
+// catalogTracks resolves track IDs against the catalog. Unknown IDs become
+// tracks named after their ID.
+func catalogTracks(trackIDs []string) []domain.Track {
+	tracks := make([]domain.Track, 0, len(trackIDs))
+	for _, id := range trackIDs {
+		track := domain.Track{ExternalID: id, Name: id}
+		for _, t := range catalog {
+			if t.ExternalID == id {
+				track = t
+				break
+			}
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks
+}
+
+func checkToken(token string) error {
+	if token == "" {
+		return fmt.Errorf("sandbox: a token is required (any value is accepted)")
+	}
+	return nil
+}
+
+// shouldFail deterministically picks failureRate of all tracks to fail.
+func (p *Provider) shouldFail(track domain.Track) bool {
+	if p.failureRate <= 0 {
+		return false
+	}
+	return float64(hashTrack(track)%1000) < p.failureRate*1000
+}
+
+func hashTrack(track domain.Track) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(track.Name + "|" + track.Artist())))
+	return h.Sum32()
+}
@@ -0,0 +1,133 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ ports.MusicProvider = (*Provider)(nil)
+
+func TestProvider_SeedPlaylists(t *testing.T) {
+	p := NewProvider()
+	ctx := context.Background()
+
+	playlists, err := p.GetPlaylists(ctx, "any")
+	require.NoError(t, err)
+	require.Len(t, playlists, 2)
+	assert.Equal(t, "sandbox-mix", playlists[0].ID)
+	assert.Equal(t, 3, playlists[0].TrackCount)
+
+	tracks, err := p.GetPlaylistTracks(ctx, "any", "sandbox-rock")
+	require.NoError(t, err)
+	assert.Equal(t, "Bohemian Rhapsody", tracks[0].Name)
+
+	_, err = p.GetPlaylist(ctx, "any", "missing")
+	assert.ErrorIs(t, err, domain.ErrPlaylistNotFound)
+
+	_, err = p.GetPlaylists(ctx, "")
+	assert.Error(t, err)
+}
+
+func TestProvider_GetPlaylistsPage(t *testing.T) {
+	p := NewProvider()
+	ctx := context.Background()
+
+	page, err := p.GetPlaylistsPage(ctx, "any", domain.PageRequest{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "1", page.NextCursor)
+	assert.Equal(t, 2, page.Total)
+
+	page, err = p.GetPlaylistsPage(ctx, "any", domain.PageRequest{Limit: 1, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, "sandbox-rock", page.Items[0].ID)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = p.GetPlaylistsPage(ctx, "any", domain.PageRequest{Limit: 1, Cursor: "abc"})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func TestProvider_SearchTrack(t *testing.T) {
+	p := NewProvider()
+	ctx := context.Background()
+
+	matched, score, err := p.SearchTrack(ctx, "any", domain.Track{Name: "x", ISRC: "USQX91300108"})
+	require.NoError(t, err)
+	assert.Equal(t, "sandbox-track-4", matched.ExternalID)
+	assert.Equal(t, 1.0, score)
+
+	matched, score, err = p.SearchTrack(ctx, "any", domain.Track{Name: "blinding lights", Artists: []string{"The Weeknd"}})
+	require.NoError(t, err)
+	assert.Equal(t, "sandbox-track-6", matched.ExternalID)
+	assert.Equal(t, 0.9, score)
+
+	unknown := domain.Track{Name: "Unknown Song", Artists: []string{"Nobody"}}
+	first, score, err := p.SearchTrack(ctx, "any", unknown)
+	require.NoError(t, err)
+	assert.Equal(t, 0.75, score)
+	second, _, _ := p.SearchTrack(ctx, "any", unknown)
+	assert.Equal(t, first.ExternalID, second.ExternalID)
+}
+
+func TestProvider_FailureRate(t *testing.T) {
+	ctx := context.Background()
+
+	_, _, err := NewProvider(WithFailureRate(1)).SearchTrack(ctx, "any", catalog[0])
+	assert.Error(t, err)
+
+	p := NewProvider(WithFailureRate(0.3))
+	failures := 0
+	for i := 0; i < 1000; i++ {
+		track := domain.Track{Name: fmt.Sprintf("Song %d", i), Artists: []string{"Artist"}}
+		_, _, err1 := p.SearchTrack(ctx, "any", track)
+		_, _, err2 := p.SearchTrack(ctx, "any", track)
+		assert.Equal(t, err1 == nil, err2 == nil, "failures must be deterministic")
+		if err1 != nil {
+			failures++
+		}
+	}
+	assert.InDelta(t, 300, failures, 60)
+}
+
+func TestProvider_PlaylistLifecycle(t *testing.T) {
+	p := NewProvider()
+	ctx := context.Background()
+
+	id, err := p.CreatePlaylist(ctx, "any", "Migrated", "desc")
+	require.NoError(t, err)
+
+	outcomes, err := p.AddTracksToPlaylist(ctx, "any", id, []string{"sandbox-track-1", "spotify-track", "sandbox-track-2"})
+	require.NoError(t, err)
+	assert.True(t, outcomes[0].Added)
+	assert.False(t, outcomes[1].Added)
+	assert.Equal(t, `sandbox: unknown track "spotify-track"`, outcomes[1].Error)
+	assert.True(t, outcomes[2].Added)
+	require.NoError(t, p.RemoveTracksFromPlaylist(ctx, "any", id, []string{"sandbox-track-1"}))
+	tracks, err := p.GetPlaylistTracks(ctx, "any", id)
+	require.NoError(t, err)
+	require.Len(t, tracks, 1)
+	assert.Equal(t, "Smells Like Teen Spirit", tracks[0].Name)
+
+	name := "Renamed"
+	require.NoError(t, p.UpdatePlaylistDetails(ctx, "any", id, domain.PlaylistUpdate{Name: &name}))
+	pl, err := p.GetPlaylist(ctx, "any", id)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", pl.Name)
+	assert.True(t, pl.IsOwner)
+
+	collaborative := true
+	require.NoError(t, p.UpdatePlaylistDetails(ctx, "any", id, domain.PlaylistUpdate{Collaborative: &collaborative}))
+	pl, err = p.GetPlaylist(ctx, "any", id)
+	require.NoError(t, err)
+	assert.True(t, pl.IsCollaborative)
+	assert.False(t, pl.IsPublic)
+
+	require.NoError(t, p.DeletePlaylist(ctx, "any", id))
+	assert.ErrorIs(t, p.DeletePlaylist(ctx, "any", id), domain.ErrPlaylistNotFound)
+}
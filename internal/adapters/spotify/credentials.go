@@ -0,0 +1,206 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+const (
+	tokenURL     = "https://accounts.spotify.com/api/token"
+	authorizeURL = "https://accounts.spotify.com/authorize"
+)
+
+// userScopes are requested when a user links their account: reading and
+// writing playlists, reading the library for account exports, and writing
+// it for account migrations.
+var userScopes = []string{
+	"playlist-read-private",
+	"playlist-read-collaborative",
+	"playlist-modify-private",
+	"playlist-modify-public",
+	"user-library-read",
+	"user-library-modify",
+	"user-follow-read",
+	"user-follow-modify",
+}
+
+// expiryMargin is subtracted from the lifetime of app tokens so they are
+// renewed before Spotify starts rejecting them.
+const expiryMargin = time.Minute
+
+// appCredentials obtains and caches an app-level access token through the
+// client-credentials flow. The token carries no user context, so it can only
+// be used for catalog endpoints such as search.
+type appCredentials struct {
+	clientID     string
+	clientSecret string
+	tokenURL     string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+}
+
+// Token returns the cached app token, requesting a new one when it is
+// missing or about to expire.
+func (a *appCredentials) Token(ctx context.Context, client *http.Client) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && time.Now().Before(a.expires) {
+		return a.token, nil
+	}
+
+	tok, err := a.requestToken(ctx, client, url.Values{"grant_type": {"client_credentials"}})
+	if err != nil {
+		return "", fmt.Errorf("spotify: client credentials request failed: %w", err)
+	}
+
+	a.token = tok.AccessToken
+	a.expires = time.Now().Add(time.Duration(tok.ExpiresIn)*time.Second - expiryMargin)
+	return a.token, nil
+}
+
+// requestToken posts form to the token endpoint, authenticated with the
+// app's client ID and secret.
+func (a *appCredentials) requestToken(ctx context.Context, client *http.Client, form url.Values) (*tokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(a.clientID, a.clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, adapters.SanitizeError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var tok tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return nil, errors.New("token response has no access token")
+	}
+	return &tok, nil
+}
+
+// RefreshToken implements ports.TokenRefresher. It needs the client
+// credentials of the app the user authorized, set with WithClientCredentials.
+func (p *Provider) RefreshToken(ctx context.Context, refreshToken string) (*domain.ProviderToken, error) {
+	if p.app == nil {
+		return nil, errors.New("spotify: refreshing tokens requires client credentials")
+	}
+
+	tok, err := p.app.requestToken(ctx, p.client, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("spotify: token refresh failed: %w", err)
+	}
+	return tok.providerToken(), nil
+}
+
+// AuthorizationURL implements ports.TokenAuthorizer. It needs the client
+// credentials and the redirect URL of the app.
+func (p *Provider) AuthorizationURL(state string) (string, error) {
+	if p.app == nil || p.redirectURL == "" {
+		return "", fmt.Errorf("%w: spotify needs client credentials and a redirect URL", domain.ErrAuthorizationUnavailable)
+	}
+
+	query := url.Values{
+		"client_id":     {p.app.clientID},
+		"response_type": {"code"},
+		"redirect_uri":  {p.redirectURL},
+		"scope":         {strings.Join(userScopes, " ")},
+		"state":         {state},
+	}
+	return authorizeURL + "?" + query.Encode(), nil
+}
+
+// ExchangeCode implements ports.TokenAuthorizer.
+func (p *Provider) ExchangeCode(ctx context.Context, code string) (*domain.ProviderToken, error) {
+	if p.app == nil || p.redirectURL == "" {
+		return nil, fmt.Errorf("%w: spotify needs client credentials and a redirect URL", domain.ErrAuthorizationUnavailable)
+	}
+
+	tok, err := p.app.requestToken(ctx, p.client, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {p.redirectURL},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("spotify: authorization code exchange failed: %w", err)
+	}
+	return tok.providerToken(), nil
+}
+
+// providerToken converts a token response for a user.
+func (tok *tokenResponse) providerToken() *domain.ProviderToken {
+	expires := time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return &domain.ProviderToken{
+		AccessToken:  tok.AccessToken,
+		RefreshToken: tok.RefreshToken,
+		ExpiresAt:    &expires,
+		Scopes:       strings.Fields(tok.Scope),
+	}
+}
+
+// invalidate drops the cached token if it is still the given one, so the
+// next call to Token requests a fresh one.
+func (a *appCredentials) invalidate(token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token == token {
+		a.token = ""
+	}
+}
+
+// searchGet performs a catalog GET request. When client credentials are
+// configured it uses the app token, which spares the user's quota and works
+// regardless of the scopes granted to the user token; if no app token can be
+// obtained or Spotify rejects it, the request falls back to userToken.
+func (p *Provider) searchGet(ctx context.Context, userToken string, endpoint string) ([]byte, error) {
+	if p.app == nil {
+		return p.doGet(ctx, userToken, endpoint)
+	}
+
+	appToken, err := p.app.Token(ctx, p.client)
+	if err != nil {
+		return p.doGet(ctx, userToken, endpoint)
+	}
+
+	body, err := p.doGet(ctx, appToken, endpoint)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		p.app.invalidate(appToken)
+		return p.doGet(ctx, userToken, endpoint)
+	}
+	return body, err
+}
@@ -0,0 +1,122 @@
+package spotify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+func TestAppCredentials_Token_CachesUntilExpiry(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		id, secret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "id", id)
+		assert.Equal(t, "secret", secret)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		w.Write([]byte(`{"access_token":"app-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	app := &appCredentials{clientID: "id", clientSecret: "secret", tokenURL: srv.URL}
+
+	token, err := app.Token(context.Background(), srv.Client())
+	require.NoError(t, err)
+	assert.Equal(t, "app-token", token)
+
+	_, err = app.Token(context.Background(), srv.Client())
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	app.invalidate("app-token")
+	_, err = app.Token(context.Background(), srv.Client())
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestAppCredentials_Token_Rejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_client"}`))
+	}))
+	defer srv.Close()
+
+	app := &appCredentials{clientID: "id", clientSecret: "wrong", tokenURL: srv.URL}
+
+	_, err := app.Token(context.Background(), srv.Client())
+	assert.ErrorContains(t, err, "status 400")
+}
+
+func TestProvider_RefreshToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _, _ := r.BasicAuth()
+		assert.Equal(t, "id", id)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "refresh-1", r.PostForm.Get("refresh_token"))
+		w.Write([]byte(`{"access_token":"access-2","token_type":"Bearer","expires_in":3600,"scope":"playlist-read-private playlist-modify-private"}`))
+	}))
+	defer srv.Close()
+
+	p := NewProvider(srv.Client())
+	_, err := p.RefreshToken(context.Background(), "refresh-1")
+	assert.ErrorContains(t, err, "requires client credentials")
+
+	p = NewProvider(srv.Client(), WithClientCredentials("id", "secret"))
+	p.app.tokenURL = srv.URL
+	token, err := p.RefreshToken(context.Background(), "refresh-1")
+	require.NoError(t, err)
+	assert.Equal(t, "access-2", token.AccessToken)
+	assert.Empty(t, token.RefreshToken)
+	require.NotNil(t, token.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *token.ExpiresAt, time.Minute)
+	assert.Equal(t, []string{"playlist-read-private", "playlist-modify-private"}, token.Scopes)
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "https://api.example.com/callback", r.PostForm.Get("redirect_uri"))
+		if r.PostForm.Get("code") != "code-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"access-1","refresh_token":"refresh-1","token_type":"Bearer","expires_in":3600,"scope":"playlist-read-private"}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewProvider(srv.Client(), WithClientCredentials("id", "secret")).AuthorizationURL("state-1")
+	assert.ErrorIs(t, err, domain.ErrAuthorizationUnavailable)
+
+	p := NewProvider(srv.Client(), WithClientCredentials("id", "secret"), WithRedirectURL("https://api.example.com/callback"))
+	p.app.tokenURL = srv.URL
+	authURL, err := p.AuthorizationURL("state-1")
+	require.NoError(t, err)
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "accounts.spotify.com", u.Host)
+	assert.Equal(t, "id", u.Query().Get("client_id"))
+	assert.Equal(t, "https://api.example.com/callback", u.Query().Get("redirect_uri"))
+	assert.Equal(t, "state-1", u.Query().Get("state"))
+	assert.Contains(t, u.Query().Get("scope"), "playlist-modify-private")
+
+	token, err := p.ExchangeCode(context.Background(), "code-1")
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token.AccessToken)
+	assert.Equal(t, "refresh-1", token.RefreshToken)
+
+	_, err = p.ExchangeCode(context.Background(), "used")
+	assert.ErrorContains(t, err, "status 400")
+}
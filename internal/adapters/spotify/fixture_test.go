@@ -0,0 +1,52 @@
+package spotify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/fixture"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider_Fixture(t *testing.T) {
+	p := NewProvider(fixture.Client(t, "testdata/playlist.json"))
+	ctx := context.Background()
+
+	playlist, err := p.GetPlaylist(ctx, "token", "3cEYpjA9oz9GiPac4AsH4n")
+	require.NoError(t, err)
+	assert.Equal(t, domain.Playlist{
+		ID:          "3cEYpjA9oz9GiPac4AsH4n",
+		Name:        "Fixture Mix",
+		Description: "A playlist for testing",
+		OwnerName:   "Fixture User",
+		TrackCount:  4,
+		IsOwner:     true,
+		IsPublic:    true,
+	}, *playlist)
+
+	_, err = p.GetPlaylist(ctx, "token", "missing")
+	assert.ErrorIs(t, err, domain.ErrPlaylistNotFound)
+
+	tracks, err := p.GetPlaylistTracks(ctx, "token", "3cEYpjA9oz9GiPac4AsH4n")
+	require.NoError(t, err)
+	require.Len(t, tracks, 3, "the local track is skipped")
+	assert.Equal(t, []string{"Daft Punk", "Pharrell Williams"}, tracks[0].Artists)
+	assert.Equal(t, "USQX91300108", tracks[0].ISRC)
+	assert.Equal(t, "2013-05-17", tracks[0].ReleaseDate)
+	assert.Equal(t, 369626, tracks[0].DurationMS)
+	assert.Equal(t, "Solitude Is Bliss", tracks[1].Name)
+	assert.True(t, tracks[2].IsEpisode())
+	assert.Equal(t, "spotify:episode:512ojhOuo1ktJprKbVcKyQ", tracks[2].ExternalID)
+	assert.Equal(t, "Fixture Podcast", tracks[2].Show.Name)
+
+	matched, score, err := p.SearchTrack(ctx, "token", tracks[0])
+	require.NoError(t, err)
+	assert.Equal(t, "69kOkLUCkxIZYexIgSG8rq", matched.ExternalID)
+	assert.Equal(t, 1.0, score)
+
+	matched, _, err = p.SearchTrack(ctx, "token", domain.Track{Name: "No Such Song", Artists: []string{"Nobody"}, ISRC: "GBAYE0601498"})
+	require.NoError(t, err)
+	assert.Nil(t, matched)
+}
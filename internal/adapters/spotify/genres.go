@@ -0,0 +1,110 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// genreArtist is an artist as GET /artists returns it.
+type genreArtist struct {
+	ID     string   `json:"id"`
+	Genres []string `json:"genres"`
+}
+
+// TagGenres sets the Genres of Spotify tracks to the genres of their
+// artists, as Spotify only rates artists. The tracks are looked up by ID to
+// learn their artists and the artists then fetched, both 50 per request.
+// Episodes and tracks without an ID are left untouched.
+func (p *Provider) TagGenres(ctx context.Context, token string, tracks []domain.Track) error {
+	var trackIDs []string
+	for _, track := range tracks {
+		if track.ExternalID != "" && !track.IsEpisode() {
+			trackIDs = append(trackIDs, track.ExternalID)
+		}
+	}
+	if len(trackIDs) == 0 {
+		return nil
+	}
+
+	artistsOf := make(map[string][]string, len(trackIDs))
+	var artistIDs []string
+	seen := make(map[string]bool)
+	for start := 0; start < len(trackIDs); start += maxLookup {
+		batch := trackIDs[start:min(start+maxLookup, len(trackIDs))]
+		body, err := p.searchGet(ctx, token, fmt.Sprintf("%s/tracks?ids=%s", baseURL, url.QueryEscape(strings.Join(batch, ","))))
+		if err != nil {
+			return fmt.Errorf("spotify: track lookup failed: %w", err)
+		}
+		var resp struct {
+			Tracks []*trackData `json:"tracks"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("spotify: failed to parse tracks response: %w", err)
+		}
+		for _, t := range resp.Tracks {
+			if t == nil {
+				continue
+			}
+			for _, a := range t.Artists {
+				if a.ID == "" {
+					continue
+				}
+				artistsOf[t.ID] = append(artistsOf[t.ID], a.ID)
+				if !seen[a.ID] {
+					seen[a.ID] = true
+					artistIDs = append(artistIDs, a.ID)
+				}
+			}
+		}
+	}
+
+	genresOf := make(map[string][]string, len(artistIDs))
+	for start := 0; start < len(artistIDs); start += maxLookup {
+		batch := artistIDs[start:min(start+maxLookup, len(artistIDs))]
+		body, err := p.searchGet(ctx, token, fmt.Sprintf("%s/artists?ids=%s", baseURL, url.QueryEscape(strings.Join(batch, ","))))
+		if err != nil {
+			return fmt.Errorf("spotify: artist lookup failed: %w", err)
+		}
+		var resp struct {
+			Artists []*genreArtist `json:"artists"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("spotify: failed to parse artists response: %w", err)
+		}
+		for _, a := range resp.Artists {
+			if a != nil {
+				genresOf[a.ID] = a.Genres
+			}
+		}
+	}
+
+	applyGenres(tracks, artistsOf, genresOf)
+	return nil
+}
+
+// applyGenres sets the Genres of each track with an entry in artistsOf to
+// the union of its artists' genres in genresOf, in artist order.
+func applyGenres(tracks []domain.Track, artistsOf, genresOf map[string][]string) {
+	for i := range tracks {
+		artists, ok := artistsOf[tracks[i].ExternalID]
+		if !ok || tracks[i].IsEpisode() {
+			continue
+		}
+		var genres []string
+		seen := make(map[string]bool)
+		for _, artist := range artists {
+			for _, genre := range genresOf[artist] {
+				if !seen[genre] {
+					seen[genre] = true
+					genres = append(genres, genre)
+				}
+			}
+		}
+		tracks[i].Genres = genres
+	}
+}
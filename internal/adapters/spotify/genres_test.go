@@ -0,0 +1,25 @@
+package spotify
+
+import (
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyGenres(t *testing.T) {
+	tracks := []domain.Track{
+		{Name: "Take Five", ExternalID: "t1"},
+		{Name: "Duet", ExternalID: "t2"},
+		{Name: "Unknown", ExternalID: "t3"},
+		{Name: "Episode", ExternalID: "t1", Type: domain.ItemTypeEpisode},
+	}
+	applyGenres(tracks,
+		map[string][]string{"t1": {"a1"}, "t2": {"a1", "a2"}},
+		map[string][]string{"a1": {"cool jazz", "jazz"}, "a2": {"jazz", "vocal jazz"}})
+
+	assert.Equal(t, []string{"cool jazz", "jazz"}, tracks[0].Genres)
+	assert.Equal(t, []string{"cool jazz", "jazz", "vocal jazz"}, tracks[1].Genres)
+	assert.Nil(t, tracks[2].Genres)
+	assert.Nil(t, tracks[3].Genres)
+}
@@ -0,0 +1,55 @@
+//go:build integration
+
+package spotify
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIntegration reads a playlist of the account of SPOTIFY_TOKEN from the
+// real API and searches for its first track. SPOTIFY_PLAYLIST_ID selects the
+// playlist, defaulting to the first one of the account. Nothing is written.
+func TestIntegration(t *testing.T) {
+	token := os.Getenv("SPOTIFY_TOKEN")
+	if token == "" {
+		t.Skip("SPOTIFY_TOKEN is not set")
+	}
+	var opts []Option
+	if id, secret := os.Getenv("SPOTIFY_CLIENT_ID"), os.Getenv("SPOTIFY_CLIENT_SECRET"); id != "" && secret != "" {
+		opts = append(opts, WithClientCredentials(id, secret))
+	}
+	p := NewProvider(nil, opts...)
+	ctx := context.Background()
+
+	require.NoError(t, p.CheckToken(ctx, token, false))
+
+	playlistID := os.Getenv("SPOTIFY_PLAYLIST_ID")
+	if playlistID == "" {
+		playlists, err := p.GetPlaylists(ctx, token)
+		require.NoError(t, err)
+		if len(playlists) == 0 {
+			t.Skip("the account has no playlists; set SPOTIFY_PLAYLIST_ID")
+		}
+		playlistID = playlists[0].ID
+	}
+
+	playlist, err := p.GetPlaylist(ctx, token, playlistID)
+	require.NoError(t, err)
+	assert.Equal(t, playlistID, playlist.ID)
+
+	tracks, err := p.GetPlaylistTracks(ctx, token, playlistID)
+	require.NoError(t, err)
+	if len(tracks) == 0 || tracks[0].IsEpisode() {
+		t.Skip("the playlist does not start with a track")
+	}
+
+	matched, score, err := p.SearchTrack(ctx, token, tracks[0])
+	require.NoError(t, err)
+	require.NotNil(t, matched, "a track of a Spotify playlist is found on Spotify")
+	assert.Greater(t, score, 0.5)
+}
@@ -0,0 +1,240 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/pkg/matching"
+)
+
+// libraryPage is a page of a /me library endpoint.
+type libraryPage[T any] struct {
+	Items []T    `json:"items"`
+	Next  string `json:"next"`
+}
+
+type savedAlbumItem struct {
+	Album albumData `json:"album"`
+}
+
+type savedShowItem struct {
+	Show showData `json:"show"`
+}
+
+// -- LibraryReader implementation --------------------------------------------
+
+// GetLikedTracks implements ports.LibraryReader. It needs the
+// user-library-read scope.
+func (p *Provider) GetLikedTracks(ctx context.Context, token string) ([]domain.Track, error) {
+	endpoint := fmt.Sprintf("%s/me/tracks?limit=%d", baseURL, maxPerPage)
+	items, err := getLibrary(ctx, p, token, endpoint, "liked songs", parsePage[trackItem])
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]domain.Track, 0, len(items))
+	for _, item := range items {
+		if item.Track.ID == "" {
+			continue // skip unavailable tracks
+		}
+		tracks = append(tracks, toTrack(item.Track))
+	}
+	return tracks, nil
+}
+
+// GetSavedAlbums implements ports.LibraryReader. It needs the
+// user-library-read scope.
+func (p *Provider) GetSavedAlbums(ctx context.Context, token string) ([]domain.Album, error) {
+	endpoint := fmt.Sprintf("%s/me/albums?limit=%d", baseURL, maxPerPage)
+	items, err := getLibrary(ctx, p, token, endpoint, "saved albums", parsePage[savedAlbumItem])
+	if err != nil {
+		return nil, err
+	}
+
+	albums := make([]domain.Album, 0, len(items))
+	for _, item := range items {
+		albums = append(albums, toAlbum(item.Album))
+	}
+	return albums, nil
+}
+
+// GetSavedShows implements ports.LibraryReader. It needs the
+// user-library-read scope.
+func (p *Provider) GetSavedShows(ctx context.Context, token string) ([]domain.Show, error) {
+	endpoint := fmt.Sprintf("%s/me/shows?limit=%d", baseURL, maxPerPage)
+	items, err := getLibrary(ctx, p, token, endpoint, "saved shows", parsePage[savedShowItem])
+	if err != nil {
+		return nil, err
+	}
+
+	shows := make([]domain.Show, 0, len(items))
+	for _, item := range items {
+		shows = append(shows, domain.Show{ID: item.Show.ID, Name: item.Show.Name, Publisher: item.Show.Publisher})
+	}
+	return shows, nil
+}
+
+// GetFollowedArtists implements ports.LibraryReader. It needs the
+// user-follow-read scope.
+func (p *Provider) GetFollowedArtists(ctx context.Context, token string) ([]domain.Artist, error) {
+	endpoint := fmt.Sprintf("%s/me/following?type=artist&limit=%d", baseURL, maxPerPage)
+	items, err := getLibrary(ctx, p, token, endpoint, "followed artists", func(body []byte) (libraryPage[artistData], error) {
+		// Followed artists are paged by cursor and nested in an object.
+		var resp struct {
+			Artists libraryPage[artistData] `json:"artists"`
+		}
+		err := json.Unmarshal(body, &resp)
+		return resp.Artists, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	artists := make([]domain.Artist, 0, len(items))
+	for _, item := range items {
+		artists = append(artists, domain.Artist{ID: item.ID, Name: item.Name})
+	}
+	return artists, nil
+}
+
+// -- LibraryWriter implementation --------------------------------------------
+
+const (
+	// maxSaveTracks, maxSaveAlbums and maxFollow are how many IDs Spotify
+	// takes per library write.
+	maxSaveTracks = 50
+	maxSaveAlbums = 20
+	maxFollow     = 50
+)
+
+// SaveTracks implements ports.LibraryWriter. It needs the
+// user-library-modify scope.
+func (p *Provider) SaveTracks(ctx context.Context, token string, trackIDs []string) error {
+	return putLibrary(ctx, p, token, baseURL+"/me/tracks", "liked songs", trackIDs, maxSaveTracks)
+}
+
+// SaveAlbums implements ports.LibraryWriter. It needs the
+// user-library-modify scope.
+func (p *Provider) SaveAlbums(ctx context.Context, token string, albumIDs []string) error {
+	return putLibrary(ctx, p, token, baseURL+"/me/albums", "saved albums", albumIDs, maxSaveAlbums)
+}
+
+// FollowArtists implements ports.LibraryWriter. It needs the
+// user-follow-modify scope.
+func (p *Provider) FollowArtists(ctx context.Context, token string, artistIDs []string) error {
+	return putLibrary(ctx, p, token, baseURL+"/me/following?type=artist", "followed artists", artistIDs, maxFollow)
+}
+
+// SearchAlbum implements ports.LibraryWriter. It takes the first result
+// with the same name and at least one of the album's artists.
+func (p *Provider) SearchAlbum(ctx context.Context, token string, album domain.Album) (*domain.Album, error) {
+	query := fmt.Sprintf("album:%s", album.Name)
+	if len(album.Artists) > 0 {
+		query += " artist:" + album.Artists[0]
+	}
+	endpoint := fmt.Sprintf("%s/search?type=album&limit=5&q=%s%s", baseURL, url.QueryEscape(query), marketParam(ctx))
+	body, err := p.searchGet(ctx, token, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("spotify: album search failed: %w", err)
+	}
+
+	var resp struct {
+		Albums libraryPage[albumData] `json:"albums"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("spotify: failed to parse album search response: %w", err)
+	}
+	name := matching.Normalize(album.Name)
+	for _, item := range resp.Albums.Items {
+		found := toAlbum(item)
+		if matching.Normalize(found.Name) == name && matching.ArtistOverlap(album.Artists, found.Artists) > 0 {
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+// SearchArtist implements ports.LibraryWriter. It takes the first result
+// with the same name.
+func (p *Provider) SearchArtist(ctx context.Context, token string, artist domain.Artist) (*domain.Artist, error) {
+	endpoint := fmt.Sprintf("%s/search?type=artist&limit=5&q=%s", baseURL, url.QueryEscape("artist:"+artist.Name))
+	body, err := p.searchGet(ctx, token, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("spotify: artist search failed: %w", err)
+	}
+
+	var resp struct {
+		Artists libraryPage[artistData] `json:"artists"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("spotify: failed to parse artist search response: %w", err)
+	}
+	name := matching.Normalize(artist.Name)
+	for _, item := range resp.Artists.Items {
+		if matching.Normalize(item.Name) == name {
+			return &domain.Artist{ID: item.ID, Name: item.Name}, nil
+		}
+	}
+	return nil, nil
+}
+
+// putLibrary writes ids to a library endpoint, at most max per request.
+// what names the items in errors.
+func putLibrary(ctx context.Context, p *Provider, token, endpoint, what string, ids []string, max int) error {
+	for chunk := range slices.Chunk(ids, max) {
+		// Marshaling a struct of strings cannot fail.
+		payload, _ := json.Marshal(struct {
+			IDs []string `json:"ids"`
+		}{chunk})
+		if _, err := p.doPut(ctx, token, endpoint, payload); err != nil {
+			return fmt.Errorf("spotify: failed to write %s: %w", what, err)
+		}
+	}
+	return nil
+}
+
+// getLibrary fetches every item of a paged library endpoint, following the
+// next URLs Spotify returns. what names the items in errors.
+func getLibrary[T any](ctx context.Context, p *Provider, token, endpoint, what string, parse func([]byte) (libraryPage[T], error)) ([]T, error) {
+	var items []T
+	for endpoint != "" {
+		body, err := p.doGet(ctx, token, endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("spotify: failed to get %s: %w", what, err)
+		}
+
+		page, err := parse(body)
+		if err != nil {
+			return nil, fmt.Errorf("spotify: failed to parse %s response: %w", what, err)
+		}
+		items = append(items, page.Items...)
+		endpoint = page.Next
+	}
+	return items, nil
+}
+
+// parsePage parses a page of a library endpoint that is not nested.
+func parsePage[T any](body []byte) (libraryPage[T], error) {
+	var page libraryPage[T]
+	err := json.Unmarshal(body, &page)
+	return page, err
+}
+
+func toAlbum(a albumData) domain.Album {
+	artists := make([]string, 0, len(a.Artists))
+	for _, artist := range a.Artists {
+		artists = append(artists, artist.Name)
+	}
+
+	return domain.Album{
+		ID:          a.ID,
+		Name:        a.Name,
+		Artists:     artists,
+		ReleaseDate: a.ReleaseDate,
+		TrackCount:  a.TotalTracks,
+	}
+}
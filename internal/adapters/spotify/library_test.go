@@ -0,0 +1,84 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePage_SavedAlbums(t *testing.T) {
+	page, err := parsePage[savedAlbumItem]([]byte(`{
+		"items": [{
+			"added_at": "2026-01-01T00:00:00Z",
+			"album": {
+				"id": "2gP2LMVcIFgVczSJqn340t",
+				"name": "Fearless",
+				"artists": [{"id": "06HL4z0CvFAxyc27GXpf02", "name": "Taylor Swift"}],
+				"release_date": "2008-11-11",
+				"total_tracks": 13
+			}
+		}],
+		"next": "https://api.spotify.com/v1/me/albums?offset=1&limit=1"
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.spotify.com/v1/me/albums?offset=1&limit=1", page.Next)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, domain.Album{
+		ID:          "2gP2LMVcIFgVczSJqn340t",
+		Name:        "Fearless",
+		Artists:     []string{"Taylor Swift"},
+		ReleaseDate: "2008-11-11",
+		TrackCount:  13,
+	}, toAlbum(page.Items[0].Album))
+}
+
+func TestProvider_SaveTracksInChunks(t *testing.T) {
+	var requests [][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/v1/me/tracks", r.URL.Path)
+		var body struct {
+			IDs []string `json:"ids"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body.IDs)
+	}))
+	defer srv.Close()
+
+	ids := make([]string, maxSaveTracks+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("track-%d", i)
+	}
+	p := NewProvider(&http.Client{Transport: serverTransport{srv}})
+	require.NoError(t, p.SaveTracks(context.Background(), "token", ids))
+	require.Len(t, requests, 2)
+	assert.Len(t, requests[0], maxSaveTracks)
+	assert.Equal(t, []string{ids[maxSaveTracks]}, requests[1])
+}
+
+func TestProvider_SearchAlbum(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "album", r.URL.Query().Get("type"))
+		fmt.Fprint(w, `{"albums":{"items":[
+			{"id":"tribute","name":"Discovery","artists":[{"name":"Tribute Band"}]},
+			{"id":"original","name":"Discovery","artists":[{"name":"Daft Punk"}],"total_tracks":14}]}}`)
+	}))
+	defer srv.Close()
+	p := NewProvider(&http.Client{Transport: serverTransport{srv}})
+
+	found, err := p.SearchAlbum(context.Background(), "token", domain.Album{Name: "Discovery", Artists: []string{"Daft Punk"}})
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "original", found.ID, "albums of other artists do not match")
+
+	found, err = p.SearchAlbum(context.Background(), "token", domain.Album{Name: "Homework", Artists: []string{"Daft Punk"}})
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
@@ -3,33 +3,113 @@ package spotify
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
 	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/pkg/matching"
 )
 
 const (
 	baseURL    = "https://api.spotify.com/v1"
 	maxPerPage = 50
 	maxBatch   = 100
+
+	// maxLookup is the most IDs GET /tracks accepts per request.
+	maxLookup = 50
+
+	// maxPlaylistItems is the most tracks a playlist can hold.
+	maxPlaylistItems = 10000
+
+	// defaultAddInterval spaces the requests of a playlist write, which
+	// matters most when a rejected batch is retried one track at a time.
+	defaultAddInterval = 50 * time.Millisecond
+
+	// defaultPageConcurrency is how many pages of playlist tracks are
+	// fetched at once.
+	defaultPageConcurrency = 4
 )
 
+// textRules follow the Web API: descriptions are limited to 300
+// characters, rejected with line breaks and stripped of HTML.
+var textRules = adapters.TextRules{
+	MaxDescriptionBytes:   300,
+	StripHTML:             true,
+	SingleLineDescription: true,
+}
+
 // Provider implements ports.MusicProvider for Spotify using the Web API.
 type Provider struct {
-	client *http.Client
+	client      *http.Client
+	app         *appCredentials
+	redirectURL string
+
+	addInterval     time.Duration
+	pageConcurrency int
+}
+
+// Option configures optional behavior of a Provider.
+type Option func(*Provider)
+
+// WithClientCredentials makes track and episode searches use an app-level
+// token obtained with the client-credentials flow instead of the user's
+// token. Searches fall back to the user's token if the app token cannot be
+// obtained. The credentials are also used to refresh user tokens.
+func WithClientCredentials(clientID, clientSecret string) Option {
+	return func(p *Provider) {
+		p.app = &appCredentials{
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			tokenURL:     tokenURL,
+		}
+	}
+}
+
+// WithRedirectURL sets the callback URL registered with the app of
+// WithClientCredentials, which lets users link their account through the
+// authorization-code flow.
+func WithRedirectURL(redirectURL string) Option {
+	return func(p *Provider) {
+		p.redirectURL = redirectURL
+	}
+}
+
+// WithAddInterval sets the pause between the requests that add tracks to a
+// playlist. Defaults to 50ms; zero sends them back to back.
+func WithAddInterval(interval time.Duration) Option {
+	return func(p *Provider) {
+		p.addInterval = max(interval, 0)
+	}
+}
+
+// WithPageConcurrency sets how many pages of a playlist's tracks are
+// fetched at once. Defaults to 4; 1 fetches them one after the other.
+func WithPageConcurrency(n int) Option {
+	return func(p *Provider) {
+		p.pageConcurrency = max(n, 1)
+	}
 }
 
 // NewProvider creates a new Spotify provider with the given HTTP client.
 // If client is nil, http.DefaultClient is used.
-func NewProvider(client *http.Client) *Provider {
+func NewProvider(client *http.Client, opts ...Option) *Provider {
 	if client == nil {
 		client = http.DefaultClient
 	}
-	return &Provider{client: client}
+	p := &Provider{client: client, addInterval: defaultAddInterval, pageConcurrency: defaultPageConcurrency}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 func (p *Provider) Name() string {
@@ -45,17 +125,37 @@ type playlistsResponse struct {
 }
 
 type playlistItem struct {
-	ID          string        `json:"id"`
-	Name        string        `json:"name"`
-	Description string        `json:"description"`
-	Owner       playlistOwner `json:"owner"`
-	Tracks      trackRef      `json:"tracks"`
+	ID            string        `json:"id"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	Owner         playlistOwner `json:"owner"`
+	Tracks        trackRef      `json:"tracks"`
+	Collaborative bool          `json:"collaborative"`
+	Public        *bool         `json:"public"`
 }
 
 type playlistOwner struct {
+	ID          string `json:"id"`
 	DisplayName string `json:"display_name"`
 }
 
+// playlist converts the item, owned by userID or another user, to a
+// domain.Playlist.
+func (item playlistItem) playlist(userID string) domain.Playlist {
+	return domain.Playlist{
+		ID:              item.ID,
+		Name:            item.Name,
+		Description:     item.Description,
+		OwnerName:       item.Owner.DisplayName,
+		TrackCount:      item.Tracks.Total,
+		IsOwner:         item.Owner.ID == userID,
+		IsCollaborative: item.Collaborative,
+		// Spotify leaves public null when the status is not relevant, as
+		// for other users' playlists, which are visible anyway.
+		IsPublic: item.Public == nil || *item.Public,
+	}
+}
+
 type trackRef struct {
 	Total int `json:"total"`
 }
@@ -63,6 +163,24 @@ type trackRef struct {
 type tracksResponse struct {
 	Items []trackItem `json:"items"`
 	Next  string      `json:"next"`
+	Total int         `json:"total"`
+}
+
+// tracks converts the items of the page, skipping local and unavailable
+// tracks.
+func (r tracksResponse) tracks() []domain.Track {
+	tracks := make([]domain.Track, 0, len(r.Items))
+	for _, item := range r.Items {
+		if item.Track.ID == "" {
+			continue
+		}
+		if item.Track.Type == "episode" {
+			tracks = append(tracks, toEpisode(item.Track))
+			continue
+		}
+		tracks = append(tracks, toTrack(item.Track))
+	}
+	return tracks
 }
 
 type trackItem struct {
@@ -75,14 +193,50 @@ type trackData struct {
 	Artists     []artistData `json:"artists"`
 	Album       albumData    `json:"album"`
 	ExternalIDs externalIDs  `json:"external_ids"`
+	PreviewURL  string       `json:"preview_url"`
+	DurationMS  int          `json:"duration_ms"`
+	Popularity  int          `json:"popularity"`
+	Explicit    bool         `json:"explicit"`
+
+	// Episodes have their own artwork and preview fields.
+	Images          []imageData `json:"images"`
+	AudioPreviewURL string      `json:"audio_preview_url"`
+
+	// IsPlayable is only set when a market is passed to the API, and
+	// AvailableMarkets only when none is; an empty list means the track
+	// cannot be played anywhere.
+	IsPlayable       *bool     `json:"is_playable"`
+	AvailableMarkets *[]string `json:"available_markets"`
+
+	// Type is "episode" for podcast episodes, which carry a show instead of
+	// artists and album.
+	Type string    `json:"type"`
+	Show *showData `json:"show"`
+}
+
+type showData struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Publisher string `json:"publisher"`
 }
 
 type artistData struct {
+	ID   string `json:"id"`
 	Name string `json:"name"`
 }
 
 type albumData struct {
-	Name string `json:"name"`
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Artists     []artistData `json:"artists"`
+	Images      []imageData  `json:"images"`
+	ReleaseDate string       `json:"release_date"`
+	TotalTracks int          `json:"total_tracks"`
+}
+
+// imageData is a Spotify image; lists are ordered widest first.
+type imageData struct {
+	URL string `json:"url"`
 }
 
 type externalIDs struct {
@@ -90,7 +244,8 @@ type externalIDs struct {
 }
 
 type searchResponse struct {
-	Tracks searchTracks `json:"tracks"`
+	Tracks   searchTracks `json:"tracks"`
+	Episodes searchTracks `json:"episodes"`
 }
 
 type searchTracks struct {
@@ -105,77 +260,241 @@ type createPlaylistResponse struct {
 
 func (p *Provider) GetPlaylists(ctx context.Context, token string) ([]domain.Playlist, error) {
 	var playlists []domain.Playlist
-	endpoint := fmt.Sprintf("%s/me/playlists?limit=%d", baseURL, maxPerPage)
+	page := domain.PageRequest{Limit: maxPerPage}
 
-	for endpoint != "" {
-		body, err := p.doGet(ctx, token, endpoint)
+	for {
+		resp, err := p.GetPlaylistsPage(ctx, token, page)
 		if err != nil {
-			return nil, fmt.Errorf("spotify: failed to get playlists: %w", err)
+			return nil, err
 		}
+		playlists = append(playlists, resp.Items...)
 
-		var resp playlistsResponse
-		if err := json.Unmarshal(body, &resp); err != nil {
-			return nil, fmt.Errorf("spotify: failed to parse playlists response: %w", err)
+		if resp.NextCursor == "" {
+			break
+		}
+		page.Cursor = resp.NextCursor
+	}
+
+	return playlists, nil
+}
+
+// GetPlaylistsPage fetches one page of playlists. The cursor is the numeric
+// offset of the first playlist in the page.
+func (p *Provider) GetPlaylistsPage(ctx context.Context, token string, page domain.PageRequest) (*domain.PlaylistPage, error) {
+	limit := page.Limit
+	if limit <= 0 || limit > maxPerPage {
+		limit = maxPerPage
+	}
+
+	offset := 0
+	if page.Cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(page.Cursor); err != nil || offset < 0 {
+			return nil, fmt.Errorf("spotify: %w %q", domain.ErrInvalidCursor, page.Cursor)
 		}
+	}
+
+	userID, err := p.currentUserID(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/me/playlists?limit=%d&offset=%d", baseURL, limit, offset)
+	body, err := p.doGet(ctx, token, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("spotify: failed to get playlists: %w", err)
+	}
+
+	var resp playlistsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("spotify: failed to parse playlists response: %w", err)
+	}
+
+	result := &domain.PlaylistPage{
+		Items: make([]domain.Playlist, 0, len(resp.Items)),
+		Total: resp.Total,
+	}
+	for _, item := range resp.Items {
+		result.Items = append(result.Items, item.playlist(userID))
+	}
+	if resp.Next != "" {
+		result.NextCursor = strconv.Itoa(offset + len(resp.Items))
+	}
+
+	return result, nil
+}
+
+func (p *Provider) GetPlaylist(ctx context.Context, token string, playlistID string) (*domain.Playlist, error) {
+	userID, err := p.currentUserID(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/playlists/%s?fields=id,name,description,owner(id,display_name),tracks(total),collaborative,public", baseURL, playlistID)
 
-		for _, item := range resp.Items {
-			playlists = append(playlists, domain.Playlist{
-				ID:          item.ID,
-				Name:        item.Name,
-				Description: item.Description,
-				OwnerName:   item.Owner.DisplayName,
-				TrackCount:  item.Tracks.Total,
-			})
+	body, err := p.doGet(ctx, token, endpoint)
+	if err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, domain.ErrPlaylistNotFound
 		}
+		return nil, fmt.Errorf("spotify: failed to get playlist: %w", err)
+	}
 
-		endpoint = resp.Next
+	var item playlistItem
+	if err := json.Unmarshal(body, &item); err != nil {
+		return nil, fmt.Errorf("spotify: failed to parse playlist response: %w", err)
 	}
 
-	return playlists, nil
+	playlist := item.playlist(userID)
+	return &playlist, nil
 }
 
 func (p *Provider) GetPlaylistTracks(ctx context.Context, token string, playlistID string) ([]domain.Track, error) {
 	var tracks []domain.Track
-	endpoint := fmt.Sprintf("%s/playlists/%s/tracks?limit=%d", baseURL, playlistID, maxPerPage)
+	err := p.StreamPlaylistTracks(ctx, token, playlistID, func(page []domain.Track) error {
+		tracks = append(tracks, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
 
-	for endpoint != "" {
-		body, err := p.doGet(ctx, token, endpoint)
-		if err != nil {
-			return nil, fmt.Errorf("spotify: failed to get playlist tracks: %w", err)
-		}
+// StreamPlaylistTracks reads the tracks of a playlist page by page. Once the
+// first page has told how many tracks there are, the other pages are
+// fetched by offset, up to pageConcurrency at a time, and handed to page in
+// order.
+func (p *Provider) StreamPlaylistTracks(ctx context.Context, token string, playlistID string, page func([]domain.Track) error) error {
+	endpoint := fmt.Sprintf("%s/playlists/%s/tracks?limit=%d&additional_types=track,episode", baseURL, playlistID, maxPerPage)
+	resp, err := p.getTracksPage(ctx, token, endpoint)
+	if err != nil {
+		return err
+	}
+	if err := page(resp.tracks()); err != nil {
+		return err
+	}
 
-		var resp tracksResponse
-		if err := json.Unmarshal(body, &resp); err != nil {
-			return nil, fmt.Errorf("spotify: failed to parse tracks response: %w", err)
+	endpoints := pageEndpoints(resp.Next, resp.Total)
+	if endpoints != nil && p.pageConcurrency > 1 {
+		return p.streamTracksPages(ctx, token, endpoints, page)
+	}
+	// Without a total, pages can only be followed one by one.
+	for resp.Next != "" {
+		if resp, err = p.getTracksPage(ctx, token, resp.Next); err != nil {
+			return err
+		}
+		if err := page(resp.tracks()); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		for _, item := range resp.Items {
-			if item.Track.ID == "" {
-				continue // skip local or unavailable tracks
+func (p *Provider) getTracksPage(ctx context.Context, token string, endpoint string) (*tracksResponse, error) {
+	body, err := p.doGet(ctx, token, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("spotify: failed to get playlist tracks: %w", err)
+	}
+
+	var resp tracksResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("spotify: failed to parse tracks response: %w", err)
+	}
+	return &resp, nil
+}
+
+// streamTracksPages fetches the pages at endpoints, up to pageConcurrency at
+// a time and in the order of endpoints, and hands each to page as soon as
+// the pages before it have been. The first failure cancels the other
+// requests and is returned.
+func (p *Provider) streamTracksPages(ctx context.Context, token string, endpoints []string, page func([]domain.Track) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel(nil)
+
+	next := make(chan int, len(endpoints))
+	pages := make([][]domain.Track, len(endpoints))
+	ready := make([]chan struct{}, len(endpoints))
+	for i := range endpoints {
+		next <- i
+		ready[i] = make(chan struct{})
+	}
+	close(next)
+
+	for range min(p.pageConcurrency, len(endpoints)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if resp, err := p.getTracksPage(ctx, token, endpoints[i]); err != nil {
+					cancel(err)
+				} else {
+					pages[i] = resp.tracks()
+				}
+				close(ready[i])
 			}
-			tracks = append(tracks, toTrack(item.Track))
+		}()
+	}
+
+	for i := range endpoints {
+		<-ready[i]
+		if pages[i] == nil {
+			return context.Cause(ctx)
+		}
+		if err := page(pages[i]); err != nil {
+			return err
 		}
+		pages[i] = nil
+	}
+	return nil
+}
 
-		endpoint = resp.Next
+// pageEndpoints returns the URLs of the pages of a listing of total items,
+// starting with the page at next, by the offset and limit of next. It
+// returns nil if next does not carry them or total is unknown.
+func pageEndpoints(next string, total int) []string {
+	if next == "" {
+		return nil
+	}
+	u, err := url.Parse(next)
+	if err != nil {
+		return nil
+	}
+	q := u.Query()
+	offset, err := strconv.Atoi(q.Get("offset"))
+	if err != nil {
+		return nil
+	}
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil || limit <= 0 || total <= offset {
+		return nil
 	}
 
-	return tracks, nil
+	var endpoints []string
+	for ; offset < total; offset += limit {
+		q.Set("offset", strconv.Itoa(offset))
+		u.RawQuery = q.Encode()
+		endpoints = append(endpoints, u.String())
+	}
+	return endpoints
 }
 
 func (p *Provider) SearchTrack(ctx context.Context, token string, track domain.Track) (*domain.Track, float64, error) {
 	// Try ISRC-based search first for higher accuracy
-	if track.ISRC != "" {
+	if searchesISRC(ctx, track) {
 		result, score, err := p.searchByISRC(ctx, token, track)
-		if err == nil && result != nil {
-			return result, score, nil
+		if (err == nil || errors.Is(err, domain.ErrUnavailableInMarket) || errors.Is(err, domain.ErrUnavailable)) && result != nil {
+			return result, score, err
 		}
 	}
 
 	// Fallback to name + artist search
-	query := fmt.Sprintf("track:%s artist:%s", track.Name, track.Artist)
-	endpoint := fmt.Sprintf("%s/search?type=track&limit=5&q=%s", baseURL, url.QueryEscape(query))
+	endpoint := fmt.Sprintf("%s/search?type=track&limit=5&q=%s%s", baseURL, url.QueryEscape(trackQuery(ctx, track)), marketParam(ctx))
 
-	body, err := p.doGet(ctx, token, endpoint)
+	body, err := p.searchGet(ctx, token, endpoint)
 	if err != nil {
 		return nil, 0, fmt.Errorf("spotify: search failed: %w", err)
 	}
@@ -189,18 +508,98 @@ func (p *Provider) SearchTrack(ctx context.Context, token string, track domain.T
 		return nil, 0, nil
 	}
 
-	best := resp.Tracks.Items[0]
-	matched := toTrack(best)
-	score := calculateConfidence(track, matched)
+	// Score every result and take the best one that is playable in the
+	// market; if none are, the track exists but is region-locked or greyed
+	// out, as the best one reports. Spotify's own ranking only breaks ties,
+	// since it often puts re-recordings, other movements of a work or live
+	// versions first. The others are reported as runner-ups.
+	scorer := adapters.Scorer(ctx, matching.Catalog)
+	candidates := make([]domain.TrackCandidate, 0, len(resp.Tracks.Items))
+	unavailableIDs := make(map[string]error, len(resp.Tracks.Items))
+	for _, item := range resp.Tracks.Items {
+		matched := toTrack(item)
+		candidates = append(candidates, domain.TrackCandidate{Track: matched, ConfidenceScore: score(scorer, track, matched)})
+		if err := unavailable(item); err != nil {
+			unavailableIDs[item.ID] = err
+		}
+	}
+	domain.RankCandidates(candidates)
+
+	best := slices.IndexFunc(candidates, func(c domain.TrackCandidate) bool { return unavailableIDs[c.Track.ExternalID] == nil })
+	if best < 0 {
+		return &candidates[0].Track, candidates[0].ConfidenceScore, fmt.Errorf("spotify: %w", unavailableIDs[candidates[0].Track.ExternalID])
+	}
+	matched := candidates[best]
+	domain.ReportRunnerUps(ctx, slices.DeleteFunc(candidates, func(c domain.TrackCandidate) bool {
+		return c.Track.ExternalID == matched.Track.ExternalID || unavailableIDs[c.Track.ExternalID] != nil
+	}))
+	return &matched.Track, matched.ConfidenceScore, nil
+}
+
+func (p *Provider) SearchTrackCandidates(ctx context.Context, token string, track domain.Track) ([]domain.TrackCandidate, error) {
+	candidates := []domain.TrackCandidate{}
+	seen := make(map[string]bool)
+
+	if searchesISRC(ctx, track) {
+		result, score, err := p.searchByISRC(ctx, token, track)
+		switch {
+		case err == nil && result != nil:
+			candidates = append(candidates, domain.TrackCandidate{Track: *result, ConfidenceScore: score})
+			seen[result.ExternalID] = true
+		case errors.Is(err, domain.ErrUnavailableInMarket), errors.Is(err, domain.ErrUnavailable):
+			// The recording exists but cannot be added; it is no candidate.
+		case err != nil:
+			return nil, fmt.Errorf("spotify: ISRC search failed: %w", err)
+		}
+	}
+
+	if track.Name == "" {
+		return candidates, nil
+	}
+
+	endpoint := fmt.Sprintf("%s/search?type=track&limit=5&q=%s%s", baseURL, url.QueryEscape(trackQuery(ctx, track)), marketParam(ctx))
 
-	return &matched, score, nil
+	body, err := p.searchGet(ctx, token, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("spotify: search failed: %w", err)
+	}
+
+	var resp searchResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("spotify: failed to parse search response: %w", err)
+	}
+
+	for _, item := range resp.Tracks.Items {
+		if seen[item.ID] {
+			continue
+		}
+		matched := toTrack(item)
+		candidates = append(candidates, domain.TrackCandidate{
+			Track:           matched,
+			ConfidenceScore: score(adapters.Scorer(ctx, matching.Catalog), track, matched),
+		})
+	}
+
+	domain.RankCandidates(candidates)
+	return candidates, nil
+}
+
+// searchesISRC reports whether track is looked up by its ISRC before it is
+// searched by name. The ISRC of an original recording finds that very
+// recording, so it is not looked up when the match options prefer a
+// re-recording.
+func searchesISRC(ctx context.Context, track domain.Track) bool {
+	if track.ISRC == "" {
+		return false
+	}
+	return domain.MatchOptionsFromContext(ctx).Rerecordings != domain.RerecordingsPrefer || matching.IsRerecording(track.Name)
 }
 
 func (p *Provider) searchByISRC(ctx context.Context, token string, track domain.Track) (*domain.Track, float64, error) {
 	query := fmt.Sprintf("isrc:%s", track.ISRC)
-	endpoint := fmt.Sprintf("%s/search?type=track&limit=1&q=%s", baseURL, url.QueryEscape(query))
+	endpoint := fmt.Sprintf("%s/search?type=track&limit=1&q=%s%s", baseURL, url.QueryEscape(query), marketParam(ctx))
 
-	body, err := p.doGet(ctx, token, endpoint)
+	body, err := p.searchGet(ctx, token, endpoint)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -215,21 +614,106 @@ func (p *Provider) searchByISRC(ctx context.Context, token string, track domain.
 	}
 
 	matched := toTrack(resp.Tracks.Items[0])
+	if err := unavailable(resp.Tracks.Items[0]); err != nil {
+		return &matched, 1.0, fmt.Errorf("spotify: %w", err)
+	}
 	return &matched, 1.0, nil // ISRC match is exact
 }
 
-func (p *Provider) CreatePlaylist(ctx context.Context, token string, name string, description string) (string, error) {
-	// First, get the current user ID
-	userBody, err := p.doGet(ctx, token, baseURL+"/me")
+// LookupTracks fetches tracks by ID, up to 50 per request. Tracks that are
+// not playable in the market in ctx, or in any market, are reported as nil,
+// so they are searched for instead.
+func (p *Provider) LookupTracks(ctx context.Context, token string, ids []string) ([]*domain.Track, error) {
+	tracks := make([]*domain.Track, 0, len(ids))
+	for start := 0; start < len(ids); start += maxLookup {
+		batch := ids[start:min(start+maxLookup, len(ids))]
+		endpoint := fmt.Sprintf("%s/tracks?ids=%s%s", baseURL, url.QueryEscape(strings.Join(batch, ",")), marketParam(ctx))
+
+		body, err := p.searchGet(ctx, token, endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("spotify: track lookup failed: %w", err)
+		}
+
+		var resp struct {
+			Tracks []*trackData `json:"tracks"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("spotify: failed to parse tracks response: %w", err)
+		}
+
+		// Unknown IDs come back as null, in request order.
+		for i := range batch {
+			if i >= len(resp.Tracks) || resp.Tracks[i] == nil || !playable(*resp.Tracks[i]) {
+				tracks = append(tracks, nil)
+				continue
+			}
+			track := toTrack(*resp.Tracks[i])
+			tracks = append(tracks, &track)
+		}
+	}
+	return tracks, nil
+}
+
+// OpenAudio implements ports.AudioSource by downloading the 30-second
+// preview of track, which Spotify does not offer for every track. Previews
+// are public, so the token is not sent.
+func (p *Provider) OpenAudio(ctx context.Context, _ string, track domain.Track) (io.ReadCloser, error) {
+	if track.PreviewURL == "" {
+		return nil, fmt.Errorf("spotify: %w", domain.ErrNoAudio)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, track.PreviewURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("spotify: failed to get current user: %w", err)
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, adapters.SanitizeError(err)
 	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("spotify: preview download failed: %w", &apiError{StatusCode: resp.StatusCode, Body: string(body)})
+	}
+	return resp.Body, nil
+}
 
-	var user struct {
-		ID string `json:"id"`
+// SearchEpisode looks up a podcast episode by name, narrowed by its show.
+func (p *Provider) SearchEpisode(ctx context.Context, token string, episode domain.Track) (*domain.Track, float64, error) {
+	query := episode.Name
+	if episode.Show != nil && episode.Show.Name != "" {
+		query += " " + episode.Show.Name
 	}
-	if err := json.Unmarshal(userBody, &user); err != nil {
-		return "", fmt.Errorf("spotify: failed to parse user response: %w", err)
+	endpoint := fmt.Sprintf("%s/search?type=episode&limit=5&q=%s%s", baseURL, url.QueryEscape(query), marketParam(ctx))
+
+	body, err := p.searchGet(ctx, token, endpoint)
+	if err != nil {
+		return nil, 0, fmt.Errorf("spotify: episode search failed: %w", err)
+	}
+
+	var resp searchResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, 0, fmt.Errorf("spotify: failed to parse episode search response: %w", err)
+	}
+
+	var best *domain.Track
+	bestScore := 0.0
+	for _, item := range resp.Episodes.Items {
+		if item.ID == "" {
+			continue
+		}
+		matched := toEpisode(item)
+		if confidence := score(matching.CatalogEpisode, episode, matched); best == nil || confidence > bestScore {
+			best, bestScore = &matched, confidence
+		}
+	}
+
+	return best, bestScore, nil
+}
+
+func (p *Provider) CreatePlaylist(ctx context.Context, token string, name string, description string) (string, error) {
+	userID, err := p.currentUserID(ctx, token)
+	if err != nil {
+		return "", err
 	}
 
 	payload := map[string]interface{}{
@@ -239,7 +723,7 @@ func (p *Provider) CreatePlaylist(ctx context.Context, token string, name string
 	}
 	payloadBytes, _ := json.Marshal(payload)
 
-	endpoint := fmt.Sprintf("%s/users/%s/playlists", baseURL, user.ID)
+	endpoint := fmt.Sprintf("%s/users/%s/playlists", baseURL, userID)
 	body, err := p.doPost(ctx, token, endpoint, payloadBytes)
 	if err != nil {
 		return "", fmt.Errorf("spotify: failed to create playlist: %w", err)
@@ -253,35 +737,270 @@ func (p *Provider) CreatePlaylist(ctx context.Context, token string, name string
 	return resp.ID, nil
 }
 
-func (p *Provider) AddTracksToPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) error {
+func (p *Provider) AddTracksToPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) ([]domain.AddOutcome, error) {
+	return p.addTracks(ctx, token, playlistID, -1, trackIDs)
+}
+
+// InsertTracksAt implements ports.PositionalAdder.
+func (p *Provider) InsertTracksAt(ctx context.Context, token string, playlistID string, position int, trackIDs []string) ([]domain.AddOutcome, error) {
+	return p.addTracks(ctx, token, playlistID, position, trackIDs)
+}
+
+// addTracks adds tracks starting at position, or appends them if position is
+// negative. Spotify rejects a whole batch when one of its URIs is invalid, so
+// a rejected batch is retried one track at a time to add all the others. Any
+// other error, or the cancellation of ctx, aborts the call with an error
+// telling how many tracks were added before.
+func (p *Provider) addTracks(ctx context.Context, token string, playlistID string, position int, trackIDs []string) ([]domain.AddOutcome, error) {
+	outcomes := make([]domain.AddOutcome, 0, len(trackIDs))
+	added, requests := 0, 0
+	post := func(batch []string) error {
+		if err := adapters.Pause(ctx, requests, p.addInterval); err != nil {
+			return err
+		}
+		requests++
+		at := -1
+		if position >= 0 {
+			at = position + added
+		}
+		return p.postTracks(ctx, token, playlistID, at, batch)
+	}
+	abort := func(err error) ([]domain.AddOutcome, error) {
+		err = fmt.Errorf("spotify: adding tracks stopped after %d of %d: %w", added, len(trackIDs), err)
+		return domain.NotAdded(outcomes, trackIDs, err), err
+	}
+
 	// Spotify accepts up to 100 URIs per request
+	for i := 0; i < len(trackIDs); i += maxBatch {
+		batch := trackIDs[i:min(i+maxBatch, len(trackIDs))]
+
+		err := post(batch)
+		if err == nil {
+			for _, id := range batch {
+				outcomes = append(outcomes, domain.AddOutcome{TrackID: id, Added: true})
+			}
+			added += len(batch)
+			continue
+		}
+		if !rejected(err) {
+			return abort(err)
+		}
+
+		for _, id := range batch {
+			err := post([]string{id})
+			switch {
+			case err == nil:
+				outcomes = append(outcomes, domain.AddOutcome{TrackID: id, Added: true})
+				added++
+			case rejected(err):
+				outcomes = append(outcomes, domain.AddOutcome{TrackID: id, Error: err.Error()})
+			default:
+				return abort(err)
+			}
+		}
+	}
+
+	return outcomes, nil
+}
+
+// postTracks adds one batch of tracks at position, or at the end if position
+// is negative.
+func (p *Provider) postTracks(ctx context.Context, token string, playlistID string, position int, trackIDs []string) error {
+	uris := make([]string, 0, len(trackIDs))
+	for _, id := range trackIDs {
+		uris = append(uris, toURI(id))
+	}
+
+	payload := map[string]interface{}{
+		"uris": uris,
+	}
+	if position >= 0 {
+		payload["position"] = position
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	endpoint := fmt.Sprintf("%s/playlists/%s/tracks", baseURL, playlistID)
+	if _, err := p.doPost(ctx, token, endpoint, payloadBytes); err != nil {
+		return fmt.Errorf("spotify: failed to add tracks to playlist: %w", err)
+	}
+	return nil
+}
+
+// rejected reports whether Spotify refused a request because of its content,
+// such as an invalid or unknown track URI, rather than because of the token,
+// the playlist or a server problem.
+func rejected(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest
+}
+
+func (p *Provider) RemoveTracksFromPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) error {
+	// Spotify accepts up to 100 track objects per request
 	for i := 0; i < len(trackIDs); i += maxBatch {
 		end := i + maxBatch
 		if end > len(trackIDs) {
 			end = len(trackIDs)
 		}
 
-		uris := make([]string, 0, end-i)
+		tracks := make([]map[string]string, 0, end-i)
 		for _, id := range trackIDs[i:end] {
-			uris = append(uris, fmt.Sprintf("spotify:track:%s", id))
+			tracks = append(tracks, map[string]string{"uri": toURI(id)})
 		}
 
 		payload := map[string]interface{}{
-			"uris": uris,
+			"tracks": tracks,
 		}
 		payloadBytes, _ := json.Marshal(payload)
 
 		endpoint := fmt.Sprintf("%s/playlists/%s/tracks", baseURL, playlistID)
-		if _, err := p.doPost(ctx, token, endpoint, payloadBytes); err != nil {
-			return fmt.Errorf("spotify: failed to add tracks to playlist: %w", err)
+		if _, err := p.doDelete(ctx, token, endpoint, payloadBytes); err != nil {
+			return fmt.Errorf("spotify: failed to remove tracks from playlist: %w", err)
 		}
 	}
 
 	return nil
 }
 
+func (p *Provider) UpdatePlaylistDetails(ctx context.Context, token string, playlistID string, update domain.PlaylistUpdate) error {
+	payload := map[string]interface{}{}
+	if update.Name != nil {
+		payload["name"] = *update.Name
+	}
+	if update.Description != nil {
+		payload["description"] = *update.Description
+	}
+	if update.Public != nil {
+		payload["public"] = *update.Public
+	}
+	if update.Collaborative != nil {
+		payload["collaborative"] = *update.Collaborative
+		// Spotify rejects public collaborative playlists.
+		if *update.Collaborative {
+			payload["public"] = false
+		}
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	endpoint := fmt.Sprintf("%s/playlists/%s", baseURL, playlistID)
+	if _, err := p.doPut(ctx, token, endpoint, payloadBytes); err != nil {
+		return fmt.Errorf("spotify: failed to update playlist: %w", err)
+	}
+	return nil
+}
+
+// SanitizePlaylistText implements ports.TextSanitizer.
+func (p *Provider) SanitizePlaylistText(name, description string) (string, string) {
+	return textRules.Sanitize(name, description)
+}
+
+// MaxPlaylistItems implements ports.PlaylistCapacity.
+func (p *Provider) MaxPlaylistItems() int {
+	return maxPlaylistItems
+}
+
+// CollaborativePlaylists implements ports.CollaborativePlaylists.
+func (p *Provider) CollaborativePlaylists() {}
+
+func (p *Provider) DeletePlaylist(ctx context.Context, token string, playlistID string) error {
+	// Spotify has no hard delete; unfollowing a playlist removes it from the
+	// owner's library, which is the closest equivalent.
+	endpoint := fmt.Sprintf("%s/playlists/%s/followers", baseURL, playlistID)
+	if _, err := p.doDelete(ctx, token, endpoint, nil); err != nil {
+		return fmt.Errorf("spotify: failed to delete playlist: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that the Spotify Web API is reachable. The request is
+// unauthenticated, so any response below 500 counts as reachable.
+func (p *Provider) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("spotify: ping failed: %w", adapters.SanitizeError(err))
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("spotify: ping failed: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// currentUserID returns the Spotify user ID of the token's owner.
+func (p *Provider) currentUserID(ctx context.Context, token string) (string, error) {
+	body, err := p.doGet(ctx, token, baseURL+"/me")
+	if err != nil {
+		return "", fmt.Errorf("spotify: failed to get current user: %w", err)
+	}
+
+	var user struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return "", fmt.Errorf("spotify: failed to parse user response: %w", err)
+	}
+	return user.ID, nil
+}
+
+// CheckToken implements ports.TokenChecker. Spotify does not report the
+// scopes granted to a token, so only the token itself is validated; a
+// missing scope still fails the first call that needs it with
+// domain.ErrInsufficientScope.
+func (p *Provider) CheckToken(ctx context.Context, token string, _ bool) error {
+	if _, err := p.doGet(ctx, token, baseURL+"/me"); err != nil {
+		return fmt.Errorf("spotify: token check failed: %w", err)
+	}
+	return nil
+}
+
 // -- HTTP helpers ------------------------------------------------------------
 
+// apiError is returned by the HTTP helpers when Spotify responds with a
+// non-success status code.
+type apiError struct {
+	StatusCode int
+	Body       string
+
+	// retryAfter is the wait the Retry-After header asked for, if any.
+	retryAfter time.Duration
+}
+
+// RetryAfter returns the wait a rate-limited response asked for, for
+// domain.RetryAfter.
+func (e *apiError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("spotify API returned status %d: %s", e.StatusCode, adapters.ErrorBody(e.Body))
+}
+
+// Is reports 401, 403, 404 and 429 responses as domain.ErrInvalidToken,
+// domain.ErrInsufficientScope, domain.ErrPlaylistNotFound and
+// domain.ErrRateLimited respectively.
+func (e *apiError) Is(target error) bool {
+	switch target {
+	case domain.ErrInvalidToken:
+		return e.StatusCode == http.StatusUnauthorized
+	case domain.ErrInsufficientScope:
+		return e.StatusCode == http.StatusForbidden
+	case domain.ErrPlaylistNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case domain.ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
+
 func (p *Provider) doGet(ctx context.Context, token string, endpoint string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
@@ -291,7 +1010,7 @@ func (p *Provider) doGet(ctx context.Context, token string, endpoint string) ([]
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, adapters.SanitizeError(err)
 	}
 	defer resp.Body.Close()
 
@@ -301,7 +1020,7 @@ func (p *Provider) doGet(ctx context.Context, token string, endpoint string) ([]
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("spotify API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body), retryAfter: adapters.RetryAfter(resp.Header)}
 	}
 
 	return body, nil
@@ -316,9 +1035,35 @@ func (p *Provider) doPost(ctx context.Context, token string, endpoint string, pa
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, adapters.SanitizeError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body), retryAfter: adapters.RetryAfter(resp.Header)}
+	}
+
+	return body, nil
+}
+
+func (p *Provider) doPut(ctx context.Context, token string, endpoint string, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, strings.NewReader(string(payload)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, adapters.SanitizeError(err)
+	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
@@ -327,7 +1072,40 @@ func (p *Provider) doPost(ctx context.Context, token string, endpoint string, pa
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("spotify API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body), retryAfter: adapters.RetryAfter(resp.Header)}
+	}
+
+	return body, nil
+}
+
+func (p *Provider) doDelete(ctx context.Context, token string, endpoint string, payload []byte) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = strings.NewReader(string(payload))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, adapters.SanitizeError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body), retryAfter: adapters.RetryAfter(resp.Header)}
 	}
 
 	return body, nil
@@ -342,44 +1120,98 @@ func toTrack(t trackData) domain.Track {
 	}
 
 	return domain.Track{
-		Name:       t.Name,
-		Artist:     strings.Join(artists, ", "),
-		Album:      t.Album.Name,
-		ISRC:       t.ExternalIDs.ISRC,
-		ExternalID: t.ID,
+		Name:        t.Name,
+		Artists:     artists,
+		Album:       t.Album.Name,
+		ISRC:        t.ExternalIDs.ISRC,
+		ExternalID:  t.ID,
+		AlbumArtURL: firstImage(t.Album.Images),
+		PreviewURL:  t.PreviewURL,
+		DurationMS:  t.DurationMS,
+		ReleaseDate: t.Album.ReleaseDate,
+		Popularity:  t.Popularity,
+		Explicit:    t.Explicit,
+	}
+}
+
+// marketParam returns the market query parameter for the market in ctx, or
+// an empty string when none was requested.
+func marketParam(ctx context.Context) string {
+	if market := domain.MarketFromContext(ctx); market != "" {
+		return "&market=" + url.QueryEscape(market)
 	}
+	return ""
 }
 
-func calculateConfidence(source, matched domain.Track) float64 {
-	score := 0.0
+// playable reports whether a search result can be played.
+func playable(t trackData) bool {
+	return unavailable(t) == nil
+}
 
-	// ISRC match is the strongest signal
-	if source.ISRC != "" && matched.ISRC != "" && strings.EqualFold(source.ISRC, matched.ISRC) {
-		return 1.0
+// unavailable returns domain.ErrUnavailableInMarket if a search result
+// cannot be played in the requested market, domain.ErrUnavailable if it is
+// available in no market at all, or nil. Results fetched with a market only
+// tell the former, and results fetched without one only the latter.
+func unavailable(t trackData) error {
+	switch {
+	case t.IsPlayable != nil && !*t.IsPlayable:
+		return domain.ErrUnavailableInMarket
+	case t.AvailableMarkets != nil && len(*t.AvailableMarkets) == 0:
+		return domain.ErrUnavailable
 	}
+	return nil
+}
 
-	// Name comparison (case-insensitive)
-	if strings.EqualFold(source.Name, matched.Name) {
-		score += 0.5
-	} else if strings.Contains(strings.ToLower(matched.Name), strings.ToLower(source.Name)) {
-		score += 0.3
+// toEpisode converts a Spotify episode. Its ExternalID is the full episode
+// URI so it can be told apart from track IDs when added to a playlist.
+func toEpisode(t trackData) domain.Track {
+	episode := domain.Track{
+		Name:        t.Name,
+		ExternalID:  "spotify:episode:" + t.ID,
+		Type:        domain.ItemTypeEpisode,
+		AlbumArtURL: firstImage(t.Images),
+		PreviewURL:  t.AudioPreviewURL,
 	}
+	if t.Show != nil {
+		episode.Show = &domain.Show{ID: t.Show.ID, Name: t.Show.Name, Publisher: t.Show.Publisher}
+	}
+	return episode
+}
 
-	// Artist comparison
-	if strings.EqualFold(source.Artist, matched.Artist) {
-		score += 0.35
-	} else if strings.Contains(strings.ToLower(matched.Artist), strings.ToLower(source.Artist)) {
-		score += 0.2
+// firstImage returns the URL of the largest image, if any.
+func firstImage(images []imageData) string {
+	if len(images) == 0 {
+		return ""
 	}
+	return images[0].URL
+}
 
-	// Album comparison
-	if source.Album != "" && strings.EqualFold(source.Album, matched.Album) {
-		score += 0.15
+// toURI returns the playlist item URI for an ExternalID. Track IDs are bare
+// while episode IDs are already full URIs.
+func toURI(id string) string {
+	if strings.HasPrefix(id, "spotify:") {
+		return id
 	}
+	return "spotify:track:" + id
+}
 
-	if score > 1.0 {
-		score = 1.0
+// trackQuery builds the search query for a track. Classical titles are
+// searched by their parsed work instead, since catalogs word them
+// differently ("Op. 125" vs "Op.125", "Sinfonie Nr. 9").
+func trackQuery(ctx context.Context, track domain.Track) string {
+	if domain.MatchOptionsFromContext(ctx).Classical {
+		if work, ok := matching.ParseWork(track.Name); ok {
+			query := work.Query()
+			if work.Composer == "" && len(track.Artists) > 0 {
+				query += " " + track.Artists[0]
+			}
+			return query
+		}
 	}
+	return fmt.Sprintf("track:%s artist:%s", track.Name, track.Artist())
+}
 
-	return score
+// score rates matched against source with scorer.
+func score(scorer matching.Scorer, source, matched domain.Track) float64 {
+	return scorer.Score(adapters.MatchTrack(source), adapters.MatchTrack(matched))
 }
@@ -0,0 +1,360 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, domain.ErrInvalidToken},
+		{http.StatusForbidden, domain.ErrInsufficientScope},
+		{http.StatusNotFound, domain.ErrPlaylistNotFound},
+		{http.StatusTooManyRequests, domain.ErrRateLimited},
+	}
+	sentinels := []error{domain.ErrInvalidToken, domain.ErrInsufficientScope, domain.ErrPlaylistNotFound, domain.ErrRateLimited}
+	for _, tt := range tests {
+		err := fmt.Errorf("spotify: failed: %w", &apiError{StatusCode: tt.status})
+		for _, s := range sentinels {
+			assert.Equal(t, s == tt.want, errors.Is(err, s), "status %d, %v", tt.status, s)
+		}
+	}
+
+	assert.False(t, errors.Is(&apiError{StatusCode: http.StatusInternalServerError}, domain.ErrRateLimited))
+}
+
+func TestProvider_SearchRetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "12")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	p := NewProvider(&http.Client{Transport: serverTransport{srv}})
+	_, _, err := p.SearchTrack(context.Background(), "token", domain.Track{Name: "Get Lucky", Artists: []string{"Daft Punk"}})
+	require.ErrorIs(t, err, domain.ErrRateLimited)
+	wait, ok := domain.RetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, 12*time.Second, wait)
+}
+
+func TestProvider_SearchRanksByScore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tracks":{"items":[
+			{"id":"karaoke","name":"Bohemian Rhapsody (Karaoke Version)","artists":[{"name":"Karaoke Hits"}],"album":{"name":"Karaoke Classics"}},
+			{"id":"locked","name":"Bohemian Rhapsody","artists":[{"name":"Queen"}],"album":{"name":"A Night at the Opera"},"is_playable":false},
+			{"id":"live","name":"Bohemian Rhapsody - Live","artists":[{"name":"Queen"}],"album":{"name":"Live Killers"}},
+			{"id":"original","name":"Bohemian Rhapsody","artists":[{"name":"Queen"}],"album":{"name":"A Night at the Opera (2011 Remaster)"}}]}}`)
+	}))
+	defer srv.Close()
+	p := NewProvider(&http.Client{Transport: serverTransport{srv}})
+	track := domain.Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, Album: "A Night at the Opera"}
+
+	ctx, runnerUps := domain.ContextWithCandidateReport(context.Background())
+	matched, score, err := p.SearchTrack(ctx, "token", track)
+	require.NoError(t, err)
+	assert.Equal(t, "original", matched.ExternalID, "the best-scored playable result wins over the top-ranked one")
+
+	reported := runnerUps()
+	require.Len(t, reported, 2, "unplayable results are not runner-ups")
+	assert.ElementsMatch(t, []string{"live", "karaoke"}, []string{reported[0].Track.ExternalID, reported[1].Track.ExternalID})
+	assert.Greater(t, score, reported[0].ConfidenceScore)
+}
+
+func TestProvider_SearchUnavailable(t *testing.T) {
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		items string
+		want  error
+	}{
+		{
+			"region-locked in the requested market",
+			domain.ContextWithMarket(context.Background(), "DE"),
+			`{"id":"locked","name":"Bohemian Rhapsody","artists":[{"name":"Queen"}],"is_playable":false}`,
+			domain.ErrUnavailableInMarket,
+		},
+		{
+			"greyed out in every market",
+			context.Background(),
+			`{"id":"locked","name":"Bohemian Rhapsody","artists":[{"name":"Queen"}],"available_markets":[]}`,
+			domain.ErrUnavailable,
+		},
+		{
+			"available elsewhere",
+			context.Background(),
+			`{"id":"locked","name":"Bohemian Rhapsody","artists":[{"name":"Queen"}],"available_markets":["JP"]}`,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"tracks":{"items":[%s]}}`, tt.items)
+			}))
+			defer srv.Close()
+			p := NewProvider(&http.Client{Transport: serverTransport{srv}})
+
+			for _, track := range []domain.Track{
+				{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}},
+				{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, ISRC: "GBUM71029604"},
+			} {
+				matched, _, err := p.SearchTrack(tt.ctx, "token", track)
+				require.NotNil(t, matched, "the unavailable track is still reported")
+				assert.Equal(t, "locked", matched.ExternalID)
+				if tt.want == nil {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestProvider_SearchPreferringRerecordingsSkipsISRC(t *testing.T) {
+	var isrcQueries atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Query().Get("q"), "isrc:") {
+			isrcQueries.Add(1)
+			fmt.Fprint(w, `{"tracks":{"items":[{"id":"original","name":"Love Story","artists":[{"name":"Taylor Swift"}]}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"tracks":{"items":[
+			{"id":"original","name":"Love Story","artists":[{"name":"Taylor Swift"}]},
+			{"id":"rerecording","name":"Love Story (Taylor's Version)","artists":[{"name":"Taylor Swift"}]}]}}`)
+	}))
+	defer srv.Close()
+	p := NewProvider(&http.Client{Transport: serverTransport{srv}})
+	track := domain.Track{Name: "Love Story", Artists: []string{"Taylor Swift"}, ISRC: "USCJY0803275"}
+	ctx := domain.ContextWithMatchOptions(context.Background(), domain.MatchOptions{Rerecordings: domain.RerecordingsPrefer})
+
+	matched, _, err := p.SearchTrack(ctx, "token", track)
+	require.NoError(t, err)
+	assert.Equal(t, "rerecording", matched.ExternalID)
+
+	candidates, err := p.SearchTrackCandidates(ctx, "token", track)
+	require.NoError(t, err)
+	require.NotEmpty(t, candidates)
+	assert.Equal(t, "rerecording", candidates[0].Track.ExternalID)
+	assert.Zero(t, isrcQueries.Load())
+
+	matched, _, err = p.SearchTrack(context.Background(), "token", track)
+	require.NoError(t, err)
+	assert.Equal(t, "original", matched.ExternalID, "without the preference the ISRC decides")
+}
+
+func TestProvider_SearchTrackCandidatesByISRC(t *testing.T) {
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, `{"error":{"status":400,"message":"bad query"}}`, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"tracks":{"items":[]}}`)
+	}))
+	defer srv.Close()
+	p := NewProvider(&http.Client{Transport: serverTransport{srv}})
+	track := domain.Track{ISRC: "GBUM71029604"}
+
+	candidates, err := p.SearchTrackCandidates(context.Background(), "token", track)
+	require.NoError(t, err)
+	assert.NotNil(t, candidates, "no candidates encode as an empty list")
+	assert.Empty(t, candidates)
+
+	fail = true
+	_, err = p.SearchTrackCandidates(context.Background(), "token", track)
+	assert.Error(t, err, "a failed ISRC lookup is reported")
+}
+
+// pagedPlaylist serves a playlist of total tracks named "t<index>" in pages
+// of limit, answering later pages faster so they complete out of order.
+func pagedPlaylist(t *testing.T, total int, failOffset int) (*httptest.Server, *atomic.Int32) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		require.NoError(t, err)
+		if offset == failOffset {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		time.Sleep(time.Duration(total-offset) * 50 * time.Microsecond)
+
+		var items []string
+		for i := offset; i < min(offset+limit, total); i++ {
+			items = append(items, fmt.Sprintf(`{"track":{"id":"t%d","name":"Track %d","artists":[{"name":"A"}]}}`, i, i))
+		}
+		next := "null"
+		if offset+limit < total {
+			next = fmt.Sprintf(`"https://api.spotify.com/v1%s?offset=%d&limit=%d&additional_types=track,episode"`, r.URL.Path, offset+limit, limit)
+		}
+		fmt.Fprintf(w, `{"items":[%s],"next":%s,"total":%d}`, strings.Join(items, ","), next, total)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &peak
+}
+
+func TestProvider_GetPlaylistTracksConcurrentPages(t *testing.T) {
+	srv, peak := pagedPlaylist(t, 430, -1)
+	p := NewProvider(&http.Client{Transport: serverTransport{srv}}, WithPageConcurrency(3))
+
+	tracks, err := p.GetPlaylistTracks(context.Background(), "token", "pl-1")
+	require.NoError(t, err)
+	require.Len(t, tracks, 430)
+	for i, track := range tracks {
+		require.Equal(t, fmt.Sprintf("t%d", i), track.ExternalID, "tracks keep the playlist order")
+	}
+	assert.Greater(t, peak.Load(), int32(1))
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+
+	srv, peak = pagedPlaylist(t, 430, -1)
+	tracks, err = NewProvider(&http.Client{Transport: serverTransport{srv}}, WithPageConcurrency(1)).GetPlaylistTracks(context.Background(), "token", "pl-1")
+	require.NoError(t, err)
+	assert.Len(t, tracks, 430)
+	assert.EqualValues(t, 1, peak.Load())
+
+	srv, _ = pagedPlaylist(t, 430, 200)
+	_, err = NewProvider(&http.Client{Transport: serverTransport{srv}}).GetPlaylistTracks(context.Background(), "token", "pl-1")
+	assert.ErrorContains(t, err, "spotify: failed to get playlist tracks")
+}
+
+func TestProvider_StreamPlaylistTracks(t *testing.T) {
+	srv, _ := pagedPlaylist(t, 430, -1)
+	p := NewProvider(&http.Client{Transport: serverTransport{srv}}, WithPageConcurrency(3))
+
+	var sizes []int
+	stop := errors.New("stop")
+	err := p.StreamPlaylistTracks(context.Background(), "token", "pl-1", func(page []domain.Track) error {
+		sizes = append(sizes, len(page))
+		if len(sizes) == 3 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []int{50, 50, 50}, sizes)
+}
+
+func TestPageEndpoints(t *testing.T) {
+	next := "https://api.spotify.com/v1/playlists/x/tracks?offset=50&limit=50&additional_types=track,episode"
+	endpoints := pageEndpoints(next, 151)
+	require.Len(t, endpoints, 3)
+	for i, offset := range []string{"50", "100", "150"} {
+		u, err := url.Parse(endpoints[i])
+		require.NoError(t, err)
+		assert.Equal(t, offset, u.Query().Get("offset"))
+		assert.Equal(t, "50", u.Query().Get("limit"))
+		assert.Equal(t, "track,episode", u.Query().Get("additional_types"))
+	}
+
+	assert.Nil(t, pageEndpoints("", 151))
+	assert.Nil(t, pageEndpoints(next, 0), "without a total pages are followed")
+	assert.Nil(t, pageEndpoints("https://api.spotify.com/v1/playlists/x/tracks?cursor=abc", 151))
+}
+
+func TestToTrack_ReleaseDateAndPopularity(t *testing.T) {
+	var data trackData
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "1vrd6UOGamcKNGnSHJQlSt",
+		"name": "Love Story",
+		"artists": [{"name": "Taylor Swift"}],
+		"album": {"name": "Fearless", "release_date": "2008-11-11"},
+		"popularity": 71
+	}`), &data))
+
+	track := toTrack(data)
+	assert.Equal(t, "2008-11-11", track.ReleaseDate)
+	assert.Equal(t, 2008, track.ReleaseYear())
+	assert.Equal(t, 71, track.Popularity)
+}
+
+// serverTransport sends every request to srv, keeping its path and query.
+type serverTransport struct {
+	srv *httptest.Server
+}
+
+func (t serverTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	target, _ := url.Parse(t.srv.URL)
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// cancelingTransport cancels a context once it has returned the response
+// to the request numbered after.
+type cancelingTransport struct {
+	base     http.RoundTripper
+	after    int
+	cancel   context.CancelFunc
+	requests int
+}
+
+func (t *cancelingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(r)
+	if t.requests++; t.requests == t.after {
+		t.cancel()
+	}
+	return resp, err
+}
+
+func TestProvider_AddTracksCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			URIs []string `json:"uris"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if strings.HasSuffix(body.URIs[0], ":bad") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"snapshot_id":"s"}`)
+	}))
+	defer srv.Close()
+
+	ids := make([]string, 0, 2*maxBatch)
+	for i := range 2 * maxBatch {
+		ids = append(ids, fmt.Sprintf("t%d", i))
+	}
+	ids[maxBatch] = "bad"
+
+	// The second batch is rejected for its bad track and retried one track
+	// at a time; the cancellation comes after the first track of the retry.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	transport := &cancelingTransport{base: serverTransport{srv}, after: 4, cancel: cancel}
+	p := NewProvider(&http.Client{Transport: transport}, WithAddInterval(0))
+	outcomes, err := p.AddTracksToPlaylist(ctx, "token", "pl", ids)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "stopped after 101 of 200")
+	assert.Equal(t, 4, transport.requests)
+	require.Len(t, outcomes, 2*maxBatch)
+	assert.True(t, outcomes[maxBatch-1].Added)
+	assert.False(t, outcomes[maxBatch].Added)
+	assert.True(t, outcomes[maxBatch+1].Added)
+	assert.False(t, outcomes[maxBatch+2].Added)
+	assert.ErrorContains(t, err, outcomes[maxBatch+2].Error)
+}
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// AuditLog implements ports.AuditLog on SQLite. Entries are stored as JSON
+// documents next to the columns they are queried by, with start times as
+// Unix nanoseconds. Triggers reject updates and deletes of the table.
+type AuditLog struct {
+	db *sql.DB
+}
+
+// NewAuditLog creates an audit log on a database returned by Open.
+func NewAuditLog(db *sql.DB) *AuditLog {
+	return &AuditLog{db: db}
+}
+
+func (l *AuditLog) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to encode audit entry: %w", err)
+	}
+
+	_, err = l.db.ExecContext(ctx,
+		`INSERT INTO audit_log (id, account_id, provider, action, started_at, data) VALUES (?, ?, ?, ?, ?, ?)`,
+		entry.ID, entry.AccountID, entry.Provider, entry.Action, entry.StartedAt.UnixNano(), string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to append audit entry: %w", err)
+	}
+	return nil
+}
+
+func (l *AuditLog) Query(ctx context.Context, q domain.AuditQuery) ([]domain.AuditEntry, error) {
+	var where []string
+	var args []any
+	if q.AccountID != "" {
+		where, args = append(where, "account_id = ?"), append(args, q.AccountID)
+	}
+	if q.Provider != "" {
+		where, args = append(where, "provider = ?"), append(args, q.Provider)
+	}
+	if q.Action != "" {
+		where, args = append(where, "action = ?"), append(args, q.Action)
+	}
+	if !q.Since.IsZero() {
+		where, args = append(where, "started_at >= ?"), append(args, q.Since.UnixNano())
+	}
+	if !q.Until.IsZero() {
+		where, args = append(where, "started_at < ?"), append(args, q.Until.UnixNano())
+	}
+
+	query := `SELECT data FROM audit_log`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY started_at DESC`
+	if q.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, q.Limit)
+	}
+
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]domain.AuditEntry, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("sqlite: failed to read audit entry: %w", err)
+		}
+		var entry domain.AuditEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			return nil, fmt.Errorf("sqlite: failed to decode audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
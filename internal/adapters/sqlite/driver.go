@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package sqlite
+
+import _ "github.com/mattn/go-sqlite3"
+
+const driverName = "sqlite3"
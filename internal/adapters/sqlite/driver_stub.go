@@ -0,0 +1,6 @@
+//go:build !sqlite
+
+package sqlite
+
+// driverName is empty when the binary is built without the "sqlite" tag.
+const driverName = ""
@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// GoldenMatchStore implements ports.GoldenMatchStore on SQLite. Entries are
+// stored as JSON documents keyed by ID, with their providers in columns
+// for filtering.
+type GoldenMatchStore struct {
+	db *sql.DB
+}
+
+// NewGoldenMatchStore creates a golden match store on a database returned
+// by Open.
+func NewGoldenMatchStore(db *sql.DB) *GoldenMatchStore {
+	return &GoldenMatchStore{db: db}
+}
+
+func (s *GoldenMatchStore) Save(ctx context.Context, match *domain.GoldenMatch) error {
+	data, err := json.Marshal(match)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to encode golden match: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO golden_matches (id, source_provider, dest_provider, updated_at, data) VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT (id) DO UPDATE SET updated_at = excluded.updated_at, data = excluded.data`,
+		match.ID, match.SourceProvider, match.DestProvider, match.UpdatedAt.UTC(), string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to save golden match: %w", err)
+	}
+	return nil
+}
+
+func (s *GoldenMatchStore) Get(ctx context.Context, id string) (*domain.GoldenMatch, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM golden_matches WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrGoldenMatchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: failed to get golden match: %w", err)
+	}
+
+	var match domain.GoldenMatch
+	if err := json.Unmarshal([]byte(data), &match); err != nil {
+		return nil, fmt.Errorf("sqlite: failed to decode golden match: %w", err)
+	}
+	return &match, nil
+}
+
+func (s *GoldenMatchStore) List(ctx context.Context, filter domain.GoldenMatchFilter) ([]domain.GoldenMatch, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT data FROM golden_matches
+		WHERE (? = '' OR source_provider = ?) AND (? = '' OR dest_provider = ?)
+		ORDER BY updated_at`,
+		filter.SourceProvider, filter.SourceProvider, filter.DestProvider, filter.DestProvider)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: failed to list golden matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches := make([]domain.GoldenMatch, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("sqlite: failed to read golden match: %w", err)
+		}
+		var match domain.GoldenMatch
+		if err := json.Unmarshal([]byte(data), &match); err != nil {
+			return nil, fmt.Errorf("sqlite: failed to decode golden match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+func (s *GoldenMatchStore) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM golden_matches WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to delete golden match: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrGoldenMatchNotFound
+	}
+	return nil
+}
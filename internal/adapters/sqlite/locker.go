@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Locker implements ports.Locker on SQLite, so processes sharing the
+// database file exclude each other. Expiry times are stored as Unix
+// nanoseconds.
+type Locker struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewLocker creates a locker on a database returned by Open.
+func NewLocker(db *sql.DB) *Locker {
+	return &Locker{db: db, now: time.Now}
+}
+
+func (l *Locker) Acquire(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	now := l.now()
+	// The upsert only replaces a lock that expired or is already ours, so
+	// exactly one of several concurrent callers changes a row.
+	res, err := l.db.ExecContext(ctx,
+		`INSERT INTO locks (key, owner, expires_at) VALUES (?, ?, ?)
+		 ON CONFLICT (key) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+		 WHERE locks.owner = excluded.owner OR locks.expires_at <= ?`,
+		key, owner, now.Add(ttl).UnixNano(), now.UnixNano(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("sqlite: failed to acquire lock: %w", err)
+	}
+	return affected(res)
+}
+
+func (l *Locker) Refresh(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	now := l.now()
+	res, err := l.db.ExecContext(ctx,
+		`UPDATE locks SET expires_at = ? WHERE key = ? AND owner = ? AND expires_at > ?`,
+		now.Add(ttl).UnixNano(), key, owner, now.UnixNano(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("sqlite: failed to refresh lock: %w", err)
+	}
+	return affected(res)
+}
+
+func (l *Locker) Release(ctx context.Context, key string, owner string) error {
+	if _, err := l.db.ExecContext(ctx, `DELETE FROM locks WHERE key = ? AND owner = ?`, key, owner); err != nil {
+		return fmt.Errorf("sqlite: failed to release lock: %w", err)
+	}
+	return nil
+}
+
+// affected reports whether a statement changed any row.
+func affected(res sql.Result) (bool, error) {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("sqlite: failed to read affected rows: %w", err)
+	}
+	return n > 0, nil
+}
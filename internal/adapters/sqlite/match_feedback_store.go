@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// MatchFeedbackStore implements ports.MatchFeedbackStore on SQLite. Each
+// verdict is one row, keyed by the match with the track of the lower
+// provider and ID first, so both directions share a row.
+type MatchFeedbackStore struct {
+	db *sql.DB
+}
+
+// NewMatchFeedbackStore creates a match feedback store on a database
+// returned by Open.
+func NewMatchFeedbackStore(db *sql.DB) *MatchFeedbackStore {
+	return &MatchFeedbackStore{db: db}
+}
+
+// orderMatch puts the two tracks of a match in key order.
+func orderMatch(fromProvider, fromID, toProvider, toID string) (string, string, string, string) {
+	if fromProvider > toProvider || (fromProvider == toProvider && fromID > toID) {
+		return toProvider, toID, fromProvider, fromID
+	}
+	return fromProvider, fromID, toProvider, toID
+}
+
+func (s *MatchFeedbackStore) Save(ctx context.Context, feedback *domain.MatchFeedback) error {
+	providerA, idA, providerB, idB := orderMatch(feedback.SourceProvider, feedback.SourceTrack.ExternalID,
+		feedback.DestProvider, feedback.MatchedTrack.ExternalID)
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO match_feedback (provider_a, id_a, provider_b, id_b, account_id, verdict, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (provider_a, id_a, provider_b, id_b, account_id) DO UPDATE
+		SET verdict = excluded.verdict, created_at = excluded.created_at`,
+		providerA, idA, providerB, idB, feedback.AccountID, string(feedback.Verdict), feedback.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to save match feedback: %w", err)
+	}
+	return nil
+}
+
+func (s *MatchFeedbackStore) Tally(ctx context.Context, fromProvider string, fromID string, toProvider string, toID string) (domain.MatchFeedbackTally, error) {
+	providerA, idA, providerB, idB := orderMatch(fromProvider, fromID, toProvider, toID)
+	var tally domain.MatchFeedbackTally
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(verdict = ?), 0), COALESCE(SUM(verdict = ?), 0) FROM match_feedback
+		WHERE provider_a = ? AND id_a = ? AND provider_b = ? AND id_b = ?`,
+		string(domain.MatchConfirmed), string(domain.MatchRejected), providerA, idA, providerB, idB,
+	).Scan(&tally.Confirmations, &tally.Rejections)
+	if err != nil {
+		return domain.MatchFeedbackTally{}, fmt.Errorf("sqlite: failed to tally match feedback: %w", err)
+	}
+	return tally, nil
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// MigrationStartStore implements ports.MigrationStartStore on SQLite. Start
+// times are stored as Unix nanoseconds.
+type MigrationStartStore struct {
+	db *sql.DB
+}
+
+// NewMigrationStartStore creates a migration start store on a database
+// returned by Open.
+func NewMigrationStartStore(db *sql.DB) *MigrationStartStore {
+	return &MigrationStartStore{db: db}
+}
+
+func (s *MigrationStartStore) Reserve(ctx context.Context, accountID string, id string, at time.Time, since time.Time, max int) (int, bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("sqlite: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Starts before since no longer count against anything.
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM migration_starts WHERE account_id = ? AND started_at < ?`, accountID, since.UnixNano(),
+	); err != nil {
+		return 0, false, fmt.Errorf("sqlite: failed to prune migration starts: %w", err)
+	}
+	// Counting and inserting in one statement keeps processes sharing the
+	// file from both passing the count.
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO migration_starts (id, account_id, started_at)
+		 SELECT ?, ?, ? WHERE (SELECT COUNT(*) FROM migration_starts WHERE account_id = ?) < ?`,
+		id, accountID, at.UnixNano(), accountID, max,
+	)
+	if err != nil {
+		return 0, false, fmt.Errorf("sqlite: failed to reserve migration start: %w", err)
+	}
+	ok, err := affected(res)
+	if err != nil {
+		return 0, false, err
+	}
+	var n int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM migration_starts WHERE account_id = ?`, accountID,
+	).Scan(&n); err != nil {
+		return 0, false, fmt.Errorf("sqlite: failed to count migration starts: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("sqlite: failed to commit migration start: %w", err)
+	}
+	return n, ok, nil
+}
+
+func (s *MigrationStartStore) Count(ctx context.Context, accountID string, since time.Time) (int, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM migration_starts WHERE account_id = ? AND started_at >= ?`, accountID, since.UnixNano(),
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("sqlite: failed to count migration starts: %w", err)
+	}
+	return n, nil
+}
+
+func (s *MigrationStartStore) Cancel(ctx context.Context, accountID string, id string) error {
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM migration_starts WHERE account_id = ? AND id = ?`, accountID, id,
+	); err != nil {
+		return fmt.Errorf("sqlite: failed to cancel migration start: %w", err)
+	}
+	return nil
+}
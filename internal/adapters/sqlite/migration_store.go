@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// MigrationStore implements ports.MigrationStore on SQLite. Results are
+// stored as JSON documents keyed by ID.
+type MigrationStore struct {
+	db *sql.DB
+}
+
+// NewMigrationStore creates a migration store on a database returned by Open.
+func NewMigrationStore(db *sql.DB) *MigrationStore {
+	return &MigrationStore{db: db}
+}
+
+func (s *MigrationStore) Save(ctx context.Context, result *domain.MigrationResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to encode migration: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO migrations (id, account_id, idempotency_key, created_at, data) VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT (id) DO UPDATE SET account_id = excluded.account_id, data = excluded.data`,
+		result.ID, result.AccountID, result.IdempotencyKey, result.CreatedAt, string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to save migration: %w", err)
+	}
+	return nil
+}
+
+func (s *MigrationStore) Get(ctx context.Context, id string) (*domain.MigrationResult, error) {
+	return s.getOne(ctx, `SELECT data FROM migrations WHERE id = ?`, id)
+}
+
+func (s *MigrationStore) GetByIdempotencyKey(ctx context.Context, accountID string, key string) (*domain.MigrationResult, error) {
+	return s.getOne(ctx,
+		`SELECT data FROM migrations WHERE account_id = ? AND idempotency_key = ?`, accountID, key)
+}
+
+// getOne runs a query selecting the data column of at most one migration.
+func (s *MigrationStore) getOne(ctx context.Context, query string, args ...any) (*domain.MigrationResult, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrMigrationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: failed to get migration: %w", err)
+	}
+
+	var result domain.MigrationResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		return nil, fmt.Errorf("sqlite: failed to decode migration: %w", err)
+	}
+	return &result, nil
+}
+
+func (s *MigrationStore) List(ctx context.Context, accountID string) ([]domain.MigrationResult, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT data FROM migrations WHERE account_id = ? ORDER BY created_at`, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: failed to list migrations: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]domain.MigrationResult, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("sqlite: failed to read migration: %w", err)
+		}
+		var result domain.MigrationResult
+		if err := json.Unmarshal([]byte(data), &result); err != nil {
+			return nil, fmt.Errorf("sqlite: failed to decode migration: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
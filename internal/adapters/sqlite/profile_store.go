@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// ProfileStore implements ports.ProfileStore on SQLite. Profiles are stored
+// as JSON documents keyed by ID.
+type ProfileStore struct {
+	db *sql.DB
+}
+
+// NewProfileStore creates a profile store on a database returned by Open.
+func NewProfileStore(db *sql.DB) *ProfileStore {
+	return &ProfileStore{db: db}
+}
+
+func (s *ProfileStore) Save(ctx context.Context, profile *domain.MigrationProfile) error {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to encode profile: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO profiles (id, account_id, created_at, data) VALUES (?, ?, ?, ?)
+		 ON CONFLICT (id) DO UPDATE SET account_id = excluded.account_id, data = excluded.data`,
+		profile.ID, profile.AccountID, profile.CreatedAt, string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to save profile: %w", err)
+	}
+	return nil
+}
+
+func (s *ProfileStore) Get(ctx context.Context, id string) (*domain.MigrationProfile, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM profiles WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: failed to get profile: %w", err)
+	}
+
+	var profile domain.MigrationProfile
+	if err := json.Unmarshal([]byte(data), &profile); err != nil {
+		return nil, fmt.Errorf("sqlite: failed to decode profile: %w", err)
+	}
+	return &profile, nil
+}
+
+func (s *ProfileStore) List(ctx context.Context, accountID string) ([]domain.MigrationProfile, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT data FROM profiles WHERE account_id = ? ORDER BY created_at`, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: failed to list profiles: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := make([]domain.MigrationProfile, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("sqlite: failed to read profile: %w", err)
+		}
+		var profile domain.MigrationProfile
+		if err := json.Unmarshal([]byte(data), &profile); err != nil {
+			return nil, fmt.Errorf("sqlite: failed to decode profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, rows.Err()
+}
+
+func (s *ProfileStore) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM profiles WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to delete profile: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrProfileNotFound
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+// Package sqlite implements the storage ports on an embedded SQLite database
+// for single-binary deployments. The driver is only linked in when building
+// with the "sqlite" tag:
+//
+//	go build -tags sqlite ./cmd/api
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrNotCompiled is returned by Open when the binary was built without the
+// "sqlite" build tag.
+var ErrNotCompiled = errors.New("sqlite: support not compiled in, rebuild with -tags sqlite")
+
+// schema holds the migrations applied in order at startup. The number of
+// applied migrations is tracked in PRAGMA user_version, so existing entries
+// must never be edited; append new ones instead.
+var schema = []string{
+	`CREATE TABLE migrations (
+		id         TEXT PRIMARY KEY,
+		account_id TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP NOT NULL,
+		data       TEXT NOT NULL
+	);
+	CREATE INDEX migrations_account_created ON migrations (account_id, created_at);
+
+	CREATE TABLE accounts (
+		id           TEXT PRIMARY KEY,
+		name         TEXT NOT NULL,
+		api_key_hash TEXT NOT NULL UNIQUE,
+		created_at   TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE tokens (
+		account_id TEXT NOT NULL,
+		provider   TEXT NOT NULL,
+		ciphertext BLOB NOT NULL,
+		PRIMARY KEY (account_id, provider)
+	);`,
+
+	`ALTER TABLE migrations ADD COLUMN idempotency_key TEXT NOT NULL DEFAULT '';
+	CREATE UNIQUE INDEX migrations_idempotency_key ON migrations (account_id, idempotency_key)
+		WHERE idempotency_key != '';`,
+
+	`CREATE TABLE track_mappings (
+		from_provider TEXT NOT NULL,
+		from_id       TEXT NOT NULL,
+		to_provider   TEXT NOT NULL,
+		to_track      TEXT NOT NULL,
+		score         REAL NOT NULL,
+		updated_at    TIMESTAMP NOT NULL,
+		PRIMARY KEY (from_provider, from_id, to_provider)
+	);`,
+
+	`CREATE TABLE jobs (
+		id               TEXT PRIMARY KEY,
+		account_id       TEXT NOT NULL DEFAULT '',
+		status           TEXT NOT NULL,
+		request          TEXT NOT NULL,
+		idempotency_key  TEXT NOT NULL DEFAULT '',
+		attempts         INTEGER NOT NULL DEFAULT 0,
+		migration_id     TEXT NOT NULL DEFAULT '',
+		error            TEXT NOT NULL DEFAULT '',
+		lease_owner      TEXT NOT NULL DEFAULT '',
+		lease_expires_at INTEGER NOT NULL DEFAULT 0,
+		created_at       TIMESTAMP NOT NULL,
+		updated_at       TIMESTAMP NOT NULL
+	);
+	CREATE INDEX jobs_status_created ON jobs (status, created_at);`,
+
+	`CREATE TABLE locks (
+		key        TEXT PRIMARY KEY,
+		owner      TEXT NOT NULL,
+		expires_at INTEGER NOT NULL
+	);`,
+
+	`ALTER TABLE jobs ADD COLUMN progress TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE search_cache (
+		key        TEXT PRIMARY KEY,
+		data       BLOB NOT NULL,
+		expires_at INTEGER NOT NULL
+	);`,
+
+	`CREATE TABLE profiles (
+		id         TEXT PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		data       TEXT NOT NULL
+	);
+	CREATE INDEX profiles_account_created ON profiles (account_id, created_at);`,
+
+	`ALTER TABLE jobs ADD COLUMN debug INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE jobs ADD COLUMN debug_log TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE audit_log (
+		id         TEXT PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		provider   TEXT NOT NULL,
+		action     TEXT NOT NULL,
+		started_at INTEGER NOT NULL,
+		data       TEXT NOT NULL
+	);
+	CREATE INDEX audit_log_started ON audit_log (started_at);
+	CREATE INDEX audit_log_account_started ON audit_log (account_id, started_at);
+	CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+	BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END;
+	CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+	BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END;`,
+
+	`ALTER TABLE accounts ADD COLUMN limits TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE match_feedback (
+		provider_a TEXT NOT NULL,
+		id_a       TEXT NOT NULL,
+		provider_b TEXT NOT NULL,
+		id_b       TEXT NOT NULL,
+		account_id TEXT NOT NULL,
+		verdict    TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (provider_a, id_a, provider_b, id_b, account_id)
+	);`,
+
+	`ALTER TABLE jobs ADD COLUMN priority TEXT NOT NULL DEFAULT 'interactive';
+	ALTER TABLE jobs ADD COLUMN leased_at INTEGER NOT NULL DEFAULT 0;`,
+
+	`CREATE TABLE golden_matches (
+		id              TEXT PRIMARY KEY,
+		source_provider TEXT NOT NULL,
+		dest_provider   TEXT NOT NULL,
+		updated_at      TIMESTAMP NOT NULL,
+		data            TEXT NOT NULL
+	);
+	CREATE INDEX golden_matches_updated ON golden_matches (updated_at);`,
+
+	// Tokens of finished jobs were kept in their requests, and repeated
+	// idempotency keys queued duplicate jobs; the duplicates keep their
+	// migration but get a key of their own.
+	`ALTER TABLE jobs ADD COLUMN tokens BLOB;
+	UPDATE jobs SET request = json_set(request, '$.source_token', '', '$.dest_token', '')
+		WHERE status NOT IN ('queued', 'running');
+	UPDATE jobs SET idempotency_key = 'job-' || id
+		WHERE idempotency_key != '' AND rowid NOT IN (
+			SELECT MIN(rowid) FROM jobs WHERE idempotency_key != '' GROUP BY account_id, idempotency_key
+		);
+	CREATE UNIQUE INDEX jobs_idempotency_key ON jobs (account_id, idempotency_key)
+		WHERE idempotency_key != '';`,
+
+	`CREATE TABLE migration_starts (
+		id         TEXT PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		started_at INTEGER NOT NULL
+	);
+	CREATE INDEX migration_starts_account ON migration_starts (account_id, started_at);`,
+
+	`ALTER TABLE jobs ADD COLUMN kind TEXT NOT NULL DEFAULT 'playlist';`,
+}
+
+// Open opens (creating if needed) the SQLite database at path and applies
+// any pending schema migrations.
+func Open(path string) (*sql.DB, error) {
+	if driverName == "" {
+		return nil, ErrNotCompiled
+	}
+
+	db, err := sql.Open(driverName, path)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: failed to open %s: %w", path, err)
+	}
+	// SQLite allows a single writer; serializing connections avoids
+	// "database is locked" errors under concurrent migrations.
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("sqlite: failed to read schema version: %w", err)
+	}
+
+	for i := version; i < len(schema); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("sqlite: failed to begin migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(schema[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("sqlite: migration %d failed: %w", i+1, err)
+		}
+		// PRAGMA does not accept bound parameters.
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("sqlite: failed to record migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("sqlite: failed to commit migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
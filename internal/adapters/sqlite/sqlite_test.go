@@ -0,0 +1,680 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+func openTestDB(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, path
+}
+
+// -- Schema ------------------------------------------------------------------
+
+func TestOpen_MigrationsAreIdempotent(t *testing.T) {
+	db, path := openTestDB(t)
+	require.NoError(t, db.Close())
+
+	db, err := Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var version int
+	require.NoError(t, db.QueryRow("PRAGMA user_version").Scan(&version))
+	assert.Equal(t, len(schema), version)
+}
+
+// -- MigrationStore ----------------------------------------------------------
+
+func TestMigrationStore_SaveGetList(t *testing.T) {
+	db, _ := openTestDB(t)
+	store := NewMigrationStore(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	second := &domain.MigrationResult{ID: "m2", AccountID: "acc", CreatedAt: now.Add(time.Minute)}
+	first := &domain.MigrationResult{
+		ID:           "m1",
+		AccountID:    "acc",
+		CreatedAt:    now,
+		TrackResults: []domain.TrackResult{{Status: domain.TrackStatusMatched}},
+	}
+	require.NoError(t, store.Save(ctx, second))
+	require.NoError(t, store.Save(ctx, first))
+	require.NoError(t, store.Save(ctx, &domain.MigrationResult{ID: "m3", AccountID: "other", CreatedAt: now}))
+
+	got, err := store.Get(ctx, "m1")
+	require.NoError(t, err)
+	assert.Len(t, got.TrackResults, 1)
+
+	first.RolledBack = true
+	require.NoError(t, store.Save(ctx, first))
+	got, err = store.Get(ctx, "m1")
+	require.NoError(t, err)
+	assert.True(t, got.RolledBack)
+
+	list, err := store.List(ctx, "acc")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "m1", list[0].ID)
+	assert.Equal(t, "m2", list[1].ID)
+
+	_, err = store.Get(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrMigrationNotFound)
+}
+
+func TestMigrationStore_GetByIdempotencyKey(t *testing.T) {
+	db, _ := openTestDB(t)
+	store := NewMigrationStore(db)
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, &domain.MigrationResult{ID: "m1", AccountID: "acc", IdempotencyKey: "k1", CreatedAt: time.Now()}))
+	require.NoError(t, store.Save(ctx, &domain.MigrationResult{ID: "m2", AccountID: "acc", CreatedAt: time.Now()}))
+	require.NoError(t, store.Save(ctx, &domain.MigrationResult{ID: "m3", AccountID: "acc", CreatedAt: time.Now()}))
+
+	got, err := store.GetByIdempotencyKey(ctx, "acc", "k1")
+	require.NoError(t, err)
+	assert.Equal(t, "m1", got.ID)
+
+	_, err = store.GetByIdempotencyKey(ctx, "other", "k1")
+	assert.ErrorIs(t, err, domain.ErrMigrationNotFound)
+}
+
+// -- AccountStore ------------------------------------------------------------
+
+func TestAccountStore(t *testing.T) {
+	db, _ := openTestDB(t)
+	store := NewAccountStore(db)
+	ctx := context.Background()
+
+	account := &domain.Account{ID: "acc", Name: "me", APIKeyHash: "hash", CreatedAt: time.Now().UTC()}
+	require.NoError(t, store.Create(ctx, account))
+	assert.Error(t, store.Create(ctx, &domain.Account{ID: "acc2", Name: "dup", APIKeyHash: "hash"}))
+
+	got, err := store.GetByAPIKeyHash(ctx, "hash")
+	require.NoError(t, err)
+	assert.Equal(t, "acc", got.ID)
+
+	_, err = store.GetByAPIKeyHash(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+
+	limits := &domain.AccountLimits{MigrationsPerDay: 5, ConcurrentJobs: 2}
+	require.NoError(t, store.SetLimits(ctx, "acc", limits))
+	got, err = store.Get(ctx, "acc")
+	require.NoError(t, err)
+	assert.Equal(t, limits, got.Limits)
+
+	require.NoError(t, store.SetLimits(ctx, "acc", nil))
+	got, err = store.Get(ctx, "acc")
+	require.NoError(t, err)
+	assert.Nil(t, got.Limits)
+
+	_, err = store.Get(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+	assert.ErrorIs(t, store.SetLimits(ctx, "unknown", limits), domain.ErrAccountNotFound)
+}
+
+// -- TokenStore --------------------------------------------------------------
+
+func TestTokenStore(t *testing.T) {
+	db, _ := openTestDB(t)
+	store := NewTokenStore(db)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "acc", "spotify", []byte("one")))
+	require.NoError(t, store.Put(ctx, "acc", "spotify", []byte("two")))
+
+	got, err := store.Get(ctx, "acc", "spotify")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("two"), got)
+
+	require.NoError(t, store.Put(ctx, "acc", "youtube", []byte("three")))
+	require.NoError(t, store.Put(ctx, "other", "deezer", []byte("four")))
+	providers, err := store.List(ctx, "acc")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spotify", "youtube"}, providers)
+
+	require.NoError(t, store.Delete(ctx, "acc", "spotify"))
+	_, err = store.Get(ctx, "acc", "spotify")
+	assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+}
+
+// -- TrackMappingStore -------------------------------------------------------
+
+func TestTrackMappingStore(t *testing.T) {
+	db, _ := openTestDB(t)
+	store := NewTrackMappingStore(db)
+	ctx := context.Background()
+
+	mapping := &domain.TrackMapping{
+		ProviderA: "spotify",
+		TrackA:    domain.Track{Name: "Song", Artists: []string{"Artist"}, ExternalID: "sp-1"},
+		ProviderB: "youtube",
+		TrackB:    domain.Track{Name: "Song (Official Video)", ExternalID: "vid-1"},
+		Score:     0.9,
+	}
+	require.NoError(t, store.Save(ctx, mapping))
+
+	track, score, err := store.Find(ctx, "spotify", "sp-1", "youtube")
+	require.NoError(t, err)
+	assert.Equal(t, "vid-1", track.ExternalID)
+	assert.Equal(t, 0.9, score)
+
+	track, _, err = store.Find(ctx, "youtube", "vid-1", "spotify")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Artist"}, track.Artists)
+
+	mapping.TrackB.ExternalID = "vid-2"
+	require.NoError(t, store.Save(ctx, mapping))
+	track, _, err = store.Find(ctx, "spotify", "sp-1", "youtube")
+	require.NoError(t, err)
+	assert.Equal(t, "vid-2", track.ExternalID)
+
+	_, _, err = store.Find(ctx, "spotify", "sp-1", "deezer")
+	assert.ErrorIs(t, err, domain.ErrMappingNotFound)
+
+	// Deleting a stale pair only removes the direction still mapping it.
+	require.NoError(t, store.Delete(ctx, "spotify", "sp-1", "youtube", "vid-1"))
+	_, _, err = store.Find(ctx, "youtube", "vid-1", "spotify")
+	assert.ErrorIs(t, err, domain.ErrMappingNotFound)
+	track, _, err = store.Find(ctx, "spotify", "sp-1", "youtube")
+	require.NoError(t, err)
+	assert.Equal(t, "vid-2", track.ExternalID)
+
+	require.NoError(t, store.Delete(ctx, "youtube", "vid-2", "spotify", "sp-1"))
+	_, _, err = store.Find(ctx, "spotify", "sp-1", "youtube")
+	assert.ErrorIs(t, err, domain.ErrMappingNotFound)
+	_, _, err = store.Find(ctx, "youtube", "vid-2", "spotify")
+	assert.ErrorIs(t, err, domain.ErrMappingNotFound)
+}
+
+// -- MatchFeedbackStore -----------------------------------------------------
+
+func TestMatchFeedbackStore(t *testing.T) {
+	db, _ := openTestDB(t)
+	store := NewMatchFeedbackStore(db)
+	ctx := context.Background()
+
+	feedback := func(account string, verdict domain.MatchVerdict) *domain.MatchFeedback {
+		return &domain.MatchFeedback{
+			AccountID:      account,
+			SourceProvider: "spotify",
+			SourceTrack:    domain.Track{ExternalID: "sp-1"},
+			DestProvider:   "youtube",
+			MatchedTrack:   domain.Track{ExternalID: "vid-1"},
+			Verdict:        verdict,
+			CreatedAt:      time.Now(),
+		}
+	}
+	require.NoError(t, store.Save(ctx, feedback("acc-1", domain.MatchRejected)))
+	require.NoError(t, store.Save(ctx, feedback("acc-2", domain.MatchRejected)))
+	require.NoError(t, store.Save(ctx, feedback("acc-1", domain.MatchConfirmed)), "replaces the earlier verdict")
+
+	tally, err := store.Tally(ctx, "youtube", "vid-1", "spotify", "sp-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.MatchFeedbackTally{Confirmations: 1, Rejections: 1}, tally)
+
+	tally, err = store.Tally(ctx, "spotify", "sp-1", "youtube", "vid-2")
+	require.NoError(t, err)
+	assert.Zero(t, tally)
+}
+
+func TestGoldenMatchStore(t *testing.T) {
+	db, _ := openTestDB(t)
+	store := NewGoldenMatchStore(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	match := &domain.GoldenMatch{
+		ID: "g1", SourceProvider: "spotify", SourceTrack: domain.Track{ExternalID: "sp-1", Name: "One"},
+		DestProvider: "youtube", Expected: &domain.Track{ExternalID: "vid-1"}, UpdatedAt: now,
+	}
+	require.NoError(t, store.Save(ctx, &domain.GoldenMatch{ID: "g2", SourceProvider: "spotify", DestProvider: "deezer", UpdatedAt: now.Add(time.Minute)}))
+	require.NoError(t, store.Save(ctx, match))
+
+	match.Rejected = []domain.Track{{ExternalID: "vid-2"}}
+	require.NoError(t, store.Save(ctx, match))
+	got, err := store.Get(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, "One", got.SourceTrack.Name)
+	assert.Equal(t, "vid-1", got.Expected.ExternalID)
+	assert.Len(t, got.Rejected, 1)
+
+	all, err := store.List(ctx, domain.GoldenMatchFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "g1", all[0].ID, "oldest update first")
+	youtube, err := store.List(ctx, domain.GoldenMatchFilter{SourceProvider: "spotify", DestProvider: "youtube"})
+	require.NoError(t, err)
+	require.Len(t, youtube, 1)
+	assert.Equal(t, "g1", youtube[0].ID)
+
+	require.NoError(t, store.Delete(ctx, "g1"))
+	_, err = store.Get(ctx, "g1")
+	assert.ErrorIs(t, err, domain.ErrGoldenMatchNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, "g1"), domain.ErrGoldenMatchNotFound)
+}
+
+// -- JobQueue ----------------------------------------------------------------
+
+func TestJobQueue_LeaseAndFinish(t *testing.T) {
+	db, _ := openTestDB(t)
+	queue := NewJobQueue(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	req := domain.MigrationRequest{SourceProvider: "spotify", DestProvider: "youtube", PlaylistID: "p1", IdempotencyKey: "key"}
+	later := req
+	later.IdempotencyKey = "other-key"
+	require.NoError(t, queue.Enqueue(ctx, &domain.Job{ID: "j2", AccountID: "acc", Request: later, CreatedAt: now.Add(time.Second)}))
+	require.NoError(t, queue.Enqueue(ctx, &domain.Job{ID: "j1", AccountID: "acc", Request: req, CreatedAt: now}))
+
+	active, err := queue.CountActive(ctx, "acc")
+	require.NoError(t, err)
+	assert.Equal(t, 2, active)
+
+	job, err := queue.Lease(ctx, "worker-a", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, "j1", job.ID)
+	assert.Equal(t, domain.JobRunning, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	assert.Equal(t, "worker-a", job.LeaseOwner)
+	assert.Equal(t, req, job.Request)
+
+	require.NoError(t, queue.Renew(ctx, "j1", "worker-a", time.Minute))
+	assert.ErrorIs(t, queue.Renew(ctx, "j1", "worker-b", time.Minute), domain.ErrJobLeaseLost)
+
+	progress := domain.JobProgress{Processed: 1, Total: 4, Matched: 1, Percent: 25, TracksPerSecond: 2}
+	require.NoError(t, queue.SaveProgress(ctx, "j1", "worker-a", progress))
+	assert.ErrorIs(t, queue.SaveProgress(ctx, "j1", "worker-b", progress), domain.ErrJobLeaseLost)
+
+	job.Status = domain.JobSucceeded
+	job.MigrationID = "m1"
+	require.NoError(t, queue.Finish(ctx, job))
+	assert.ErrorIs(t, queue.Finish(ctx, job), domain.ErrJobLeaseLost)
+
+	active, err = queue.CountActive(ctx, "acc")
+	require.NoError(t, err)
+	assert.Equal(t, 1, active, "finished jobs are not active")
+
+	stored, err := queue.Get(ctx, "j1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobSucceeded, stored.Status)
+	assert.Equal(t, "m1", stored.MigrationID)
+	assert.Empty(t, stored.LeaseOwner)
+	require.NotNil(t, stored.Progress, "progress is kept once the job finished")
+	assert.Equal(t, progress, *stored.Progress)
+
+	next, err := queue.Lease(ctx, "worker-a", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "j2", next.ID)
+
+	none, err := queue.Lease(ctx, "worker-a", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	_, err = queue.Get(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrJobNotFound)
+}
+
+func TestJobQueue_IdempotencyKeyAndTokens(t *testing.T) {
+	db, _ := openTestDB(t)
+	queue := NewJobQueue(db)
+	ctx := context.Background()
+
+	req := domain.MigrationRequest{SourceProvider: "spotify", DestProvider: "youtube", PlaylistID: "p1", IdempotencyKey: "key"}
+	now := time.Now().UTC()
+	job := &domain.Job{ID: "j1", AccountID: "acc", Request: req, Tokens: []byte("sealed"), CreatedAt: now}
+	require.NoError(t, queue.Enqueue(ctx, job))
+	assert.ErrorIs(t, queue.Enqueue(ctx, &domain.Job{ID: "j2", AccountID: "acc", Request: req, CreatedAt: now}),
+		domain.ErrJobExists)
+	require.NoError(t, queue.Enqueue(ctx, &domain.Job{ID: "j3", AccountID: "other", Request: req, CreatedAt: now.Add(time.Second)}),
+		"keys are scoped to the account")
+
+	found, err := queue.GetByIdempotencyKey(ctx, "acc", "key")
+	require.NoError(t, err)
+	assert.Equal(t, "j1", found.ID)
+	_, err = queue.GetByIdempotencyKey(ctx, "acc", "missing")
+	assert.ErrorIs(t, err, domain.ErrJobNotFound)
+
+	leased, err := queue.Lease(ctx, "worker", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("sealed"), leased.Tokens)
+
+	leased.Status = domain.JobSucceeded
+	require.NoError(t, queue.Finish(ctx, leased))
+	stored, err := queue.Get(ctx, leased.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.Tokens, "tokens are cleared once the job finished")
+
+	require.NoError(t, queue.Cancel(ctx, "j3"))
+	stored, err = queue.Get(ctx, "j3")
+	require.NoError(t, err)
+	assert.Nil(t, stored.Tokens)
+}
+
+func TestJobQueue_PriorityAndFairness(t *testing.T) {
+	db, _ := openTestDB(t)
+	queue := NewJobQueue(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	enqueue := func(id, account string, priority domain.JobPriority, age time.Duration) {
+		t.Helper()
+		require.NoError(t, queue.Enqueue(ctx, &domain.Job{ID: id, AccountID: account, Priority: priority, CreatedAt: now.Add(-age)}))
+	}
+	enqueue("bulk", "other", domain.JobBulk, 10*time.Minute)
+	enqueue("b1", "batch", "", 5*time.Minute)
+	enqueue("b2", "batch", "", 4*time.Minute)
+	enqueue("b3", "batch", "", 3*time.Minute)
+	enqueue("s1", "solo", domain.JobInteractive, time.Minute)
+
+	lease := func() string {
+		t.Helper()
+		now = now.Add(time.Second)
+		queue.now = func() time.Time { return now }
+		job, err := queue.Lease(ctx, "worker", time.Hour)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		return job.ID
+	}
+	assert.Equal(t, "b1", lease())
+	assert.Equal(t, "s1", lease(), "an account without running jobs goes first")
+	assert.Equal(t, "b2", lease(), "the account served longest ago goes first")
+
+	enqueue("s2", "solo", "", 0)
+	assert.Equal(t, "s2", lease(), "the account with fewer running jobs goes first")
+	assert.Equal(t, "b3", lease())
+	assert.Equal(t, "bulk", lease(), "bulk jobs wait for interactive ones")
+
+	stored, err := queue.Get(ctx, "b1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobInteractive, stored.Priority)
+}
+
+func TestJobQueue_DebugLog(t *testing.T) {
+	db, _ := openTestDB(t)
+	queue := NewJobQueue(db)
+	ctx := context.Background()
+
+	require.NoError(t, queue.Enqueue(ctx, &domain.Job{ID: "j1", Debug: true, CreatedAt: time.Now()}))
+	job, err := queue.Lease(ctx, "worker-a", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.True(t, job.Debug)
+	assert.Nil(t, job.DebugLog)
+
+	log := &domain.DebugLog{Exchanges: []domain.ProviderExchange{{
+		Time:   time.Now().UTC().Truncate(time.Second),
+		Method: "GET",
+		URL:    "https://www.googleapis.com/youtube/v3/search?key=REDACTED",
+		Status: 403,
+		Body:   `{"error": "quotaExceeded"}`,
+	}}, Dropped: 2}
+	job.Status = domain.JobFailed
+	job.DebugLog = log
+	require.NoError(t, queue.Finish(ctx, job))
+
+	stored, err := queue.Get(ctx, "j1")
+	require.NoError(t, err)
+	assert.True(t, stored.Debug)
+	assert.Equal(t, log, stored.DebugLog)
+}
+
+func TestJobQueue_ExpiredLeaseIsTakenOver(t *testing.T) {
+	db, path := openTestDB(t)
+	queue := NewJobQueue(db)
+	ctx := context.Background()
+
+	require.NoError(t, queue.Enqueue(ctx, &domain.Job{ID: "j1", CreatedAt: time.Now()}))
+	job, err := queue.Lease(ctx, "worker-a", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+
+	// The job survives reopening the database, as after a restart.
+	require.NoError(t, db.Close())
+	db, err = Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+	queue = NewJobQueue(db)
+
+	none, err := queue.Lease(ctx, "worker-b", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, none, "lease still valid")
+
+	queue.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	taken, err := queue.Lease(ctx, "worker-b", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, taken)
+	assert.Equal(t, "worker-b", taken.LeaseOwner)
+	assert.Equal(t, 2, taken.Attempts)
+
+	job.Status = domain.JobFailed
+	assert.ErrorIs(t, queue.Finish(ctx, job), domain.ErrJobLeaseLost)
+}
+
+func TestJobQueue_Cancel(t *testing.T) {
+	db, _ := openTestDB(t)
+	queue := NewJobQueue(db)
+	ctx := context.Background()
+
+	require.NoError(t, queue.Enqueue(ctx, &domain.Job{ID: "j1", CreatedAt: time.Now()}))
+	job, err := queue.Lease(ctx, "worker-a", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+
+	require.NoError(t, queue.Cancel(ctx, "j1"))
+	assert.ErrorIs(t, queue.Renew(ctx, "j1", "worker-a", time.Minute), domain.ErrJobLeaseLost)
+	assert.ErrorIs(t, queue.Cancel(ctx, "j1"), domain.ErrJobFinished)
+	assert.ErrorIs(t, queue.Cancel(ctx, "missing"), domain.ErrJobNotFound)
+
+	stored, err := queue.Get(ctx, "j1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobCanceled, stored.Status)
+	assert.Empty(t, stored.LeaseOwner)
+
+	none, err := queue.Lease(ctx, "worker-b", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, none, "canceled jobs are not leased again")
+}
+
+// -- MigrationStartStore -----------------------------------------------------
+
+func TestMigrationStartStore(t *testing.T) {
+	db, _ := openTestDB(t)
+	store := NewMigrationStartStore(db)
+	ctx := context.Background()
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	n, ok, err := store.Reserve(ctx, "acc", "m1", day.Add(time.Hour), day, 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, n)
+	n, ok, err = store.Reserve(ctx, "acc", "m2", day.Add(2*time.Hour), day, 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, n)
+	n, ok, err = store.Reserve(ctx, "acc", "m3", day.Add(3*time.Hour), day, 2)
+	require.NoError(t, err)
+	assert.False(t, ok, "the limit is reached")
+	assert.Equal(t, 2, n)
+
+	n, ok, err = store.Reserve(ctx, "other", "m4", day.Add(3*time.Hour), day, 2)
+	require.NoError(t, err)
+	assert.True(t, ok, "other accounts are counted apart")
+	assert.Equal(t, 1, n)
+
+	require.NoError(t, store.Cancel(ctx, "acc", "m1"))
+	require.NoError(t, store.Cancel(ctx, "acc", "unknown"))
+	n, err = store.Count(ctx, "acc", day)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// Starts of the previous day do not count.
+	next := day.Add(24 * time.Hour)
+	n, ok, err = store.Reserve(ctx, "acc", "m5", next, next, 1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, n)
+	n, err = store.Count(ctx, "acc", day)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "starts before since are pruned")
+}
+
+// -- Locker ------------------------------------------------------------------
+
+func TestLocker(t *testing.T) {
+	db, _ := openTestDB(t)
+	locker := NewLocker(db)
+	ctx := context.Background()
+
+	ok, err := locker.Acquire(ctx, "k", "a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = locker.Acquire(ctx, "k", "b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "held by a")
+
+	ok, err = locker.Refresh(ctx, "k", "a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = locker.Refresh(ctx, "k", "b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, locker.Release(ctx, "k", "b"), "releasing another owner's lock is a no-op")
+	ok, _ = locker.Acquire(ctx, "k", "b", time.Minute)
+	assert.False(t, ok)
+
+	require.NoError(t, locker.Release(ctx, "k", "a"))
+	ok, err = locker.Acquire(ctx, "k", "b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// An expired lock is taken over.
+	locker.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	ok, err = locker.Acquire(ctx, "k", "c", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+// -- SearchCache -------------------------------------------------------------
+
+func TestSearchCache(t *testing.T) {
+	db, _ := openTestDB(t)
+	cache := NewSearchCache(db)
+	ctx := context.Background()
+
+	_, err := cache.Get(ctx, "q")
+	assert.ErrorIs(t, err, domain.ErrCacheMiss)
+
+	require.NoError(t, cache.Put(ctx, "q", []byte("first"), time.Minute))
+	require.NoError(t, cache.Put(ctx, "q", []byte("second"), time.Minute))
+	data, err := cache.Get(ctx, "q")
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	cache.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, err = cache.Get(ctx, "q")
+	assert.ErrorIs(t, err, domain.ErrCacheMiss, "expired")
+
+	cache.now = time.Now
+	require.NoError(t, cache.Delete(ctx, "q"))
+	_, err = cache.Get(ctx, "q")
+	assert.ErrorIs(t, err, domain.ErrCacheMiss)
+}
+
+// -- ProfileStore ------------------------------------------------------------
+
+func TestProfileStore(t *testing.T) {
+	db, _ := openTestDB(t)
+	store := NewProfileStore(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	profile := &domain.MigrationProfile{ID: "p1", AccountID: "acc", Name: "weekly", MinScore: 0.8, CreatedAt: now}
+	require.NoError(t, store.Save(ctx, &domain.MigrationProfile{ID: "p2", AccountID: "acc", CreatedAt: now.Add(time.Minute)}))
+	require.NoError(t, store.Save(ctx, profile))
+	require.NoError(t, store.Save(ctx, &domain.MigrationProfile{ID: "p3", AccountID: "other", CreatedAt: now}))
+
+	profile.Dedupe = true
+	require.NoError(t, store.Save(ctx, profile))
+	got, err := store.Get(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, "weekly", got.Name)
+	assert.Equal(t, 0.8, got.MinScore)
+	assert.True(t, got.Dedupe)
+
+	list, err := store.List(ctx, "acc")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "p1", list[0].ID)
+	assert.Equal(t, "p2", list[1].ID)
+
+	require.NoError(t, store.Delete(ctx, "p1"))
+	_, err = store.Get(ctx, "p1")
+	assert.ErrorIs(t, err, domain.ErrProfileNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, "p1"), domain.ErrProfileNotFound)
+}
+
+// -- AuditLog ----------------------------------------------------------------
+
+func TestAuditLog(t *testing.T) {
+	db, _ := openTestDB(t)
+	log := NewAuditLog(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	entries := []domain.AuditEntry{
+		{ID: "a1", AccountID: "acc", Provider: "spotify", Action: domain.AuditCreatePlaylist, PlaylistID: "pl-1", StartedAt: now},
+		{ID: "a2", AccountID: "acc", Provider: "spotify", Action: domain.AuditAddTracks, PlaylistID: "pl-1", TrackIDs: []string{"t1", "t2"}, StartedAt: now.Add(time.Second)},
+		{ID: "a3", AccountID: "other", Provider: "youtube", Action: domain.AuditDeletePlaylist, PlaylistID: "pl-2", Error: "forbidden", StartedAt: now.Add(2 * time.Second)},
+	}
+	for i := range entries {
+		require.NoError(t, log.Append(ctx, &entries[i]))
+	}
+
+	all, err := log.Query(ctx, domain.AuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "a3", all[0].ID, "most recent first")
+	assert.Equal(t, "forbidden", all[0].Error)
+	assert.Equal(t, []string{"t1", "t2"}, all[1].TrackIDs)
+
+	acc, err := log.Query(ctx, domain.AuditQuery{AccountID: "acc", Action: domain.AuditAddTracks})
+	require.NoError(t, err)
+	require.Len(t, acc, 1)
+	assert.Equal(t, "a2", acc[0].ID)
+
+	window, err := log.Query(ctx, domain.AuditQuery{Since: now.Add(time.Second), Until: now.Add(2 * time.Second)})
+	require.NoError(t, err)
+	require.Len(t, window, 1)
+	assert.Equal(t, "a2", window[0].ID)
+
+	limited, err := log.Query(ctx, domain.AuditQuery{Provider: "spotify", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+	assert.Equal(t, "a2", limited[0].ID)
+
+	_, err = db.Exec(`DELETE FROM audit_log`)
+	assert.Error(t, err, "entries cannot be removed")
+	_, err = db.Exec(`UPDATE audit_log SET account_id = 'x'`)
+	assert.Error(t, err, "entries cannot be changed")
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// TokenStore implements ports.TokenStore on SQLite. Only ciphertext produced
+// by the token vault is stored.
+type TokenStore struct {
+	db *sql.DB
+}
+
+// NewTokenStore creates a token store on a database returned by Open.
+func NewTokenStore(db *sql.DB) *TokenStore {
+	return &TokenStore{db: db}
+}
+
+func (s *TokenStore) Put(ctx context.Context, accountID string, provider string, ciphertext []byte) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO tokens (account_id, provider, ciphertext) VALUES (?, ?, ?)
+		 ON CONFLICT (account_id, provider) DO UPDATE SET ciphertext = excluded.ciphertext`,
+		accountID, provider, ciphertext,
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to store token: %w", err)
+	}
+	return nil
+}
+
+func (s *TokenStore) Get(ctx context.Context, accountID string, provider string) ([]byte, error) {
+	var ciphertext []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT ciphertext FROM tokens WHERE account_id = ? AND provider = ?`, accountID, provider,
+	).Scan(&ciphertext)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: failed to get token: %w", err)
+	}
+	return ciphertext, nil
+}
+
+func (s *TokenStore) List(ctx context.Context, accountID string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT provider FROM tokens WHERE account_id = ? ORDER BY provider`, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: failed to list tokens: %w", err)
+	}
+	defer rows.Close()
+
+	providers := make([]string, 0)
+	for rows.Next() {
+		var provider string
+		if err := rows.Scan(&provider); err != nil {
+			return nil, fmt.Errorf("sqlite: failed to read token: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, rows.Err()
+}
+
+func (s *TokenStore) Delete(ctx context.Context, accountID string, provider string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM tokens WHERE account_id = ? AND provider = ?`, accountID, provider)
+	if err != nil {
+		return fmt.Errorf("sqlite: failed to delete token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package adapters
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// TextRules describe what a provider accepts in playlist names and
+// descriptions. Providers use them to implement ports.TextSanitizer.
+type TextRules struct {
+	// MaxNameBytes and MaxDescriptionBytes cut longer texts at a rune
+	// boundary. Zero means no limit.
+	MaxNameBytes        int
+	MaxDescriptionBytes int
+
+	// StripHTML removes HTML tags and unescapes entities, as some providers
+	// do on their own after accepting the text.
+	StripHTML bool
+
+	// SingleLineDescription joins description lines with spaces.
+	SingleLineDescription bool
+
+	// StripEmoji removes emoji and other pictographic symbols.
+	StripEmoji bool
+
+	// Forbidden lists characters that are removed.
+	Forbidden string
+}
+
+// Sanitize returns name and description changed to follow the rules.
+// Control characters are always removed, surrounding whitespace trimmed and
+// names put on a single line.
+func (r TextRules) Sanitize(name, description string) (string, string) {
+	name = strings.Join(strings.Fields(r.clean(name)), " ")
+	description = r.clean(description)
+	if r.SingleLineDescription {
+		description = strings.Join(strings.Fields(description), " ")
+	}
+	return truncate(name, r.MaxNameBytes), truncate(description, r.MaxDescriptionBytes)
+}
+
+func (r TextRules) clean(s string) string {
+	if r.StripHTML {
+		s = html.UnescapeString(htmlTag.ReplaceAllString(s, ""))
+	}
+	s = strings.Map(func(c rune) rune {
+		switch {
+		case c == '\n':
+			return c
+		case c == '\t', c == '\r':
+			return ' '
+		case unicode.IsControl(c), strings.ContainsRune(r.Forbidden, c):
+			return -1
+		case r.StripEmoji && isEmoji(c):
+			return -1
+		}
+		return c
+	}, s)
+	return strings.TrimSpace(s)
+}
+
+// isEmoji reports whether c is a pictographic symbol or one of the
+// modifiers and joiners emoji sequences are built from.
+func isEmoji(c rune) bool {
+	return unicode.Is(unicode.So, c) ||
+		c == '\u200d' || c == '\ufe0f' || // zero width joiner, emoji presentation selector
+		(c >= 0x1f3fb && c <= 0x1f3ff) // skin tone modifiers
+}
+
+// truncate cuts s to at most maxBytes bytes without splitting a rune.
+func truncate(s string, maxBytes int) string {
+	if maxBytes <= 0 || len(s) <= maxBytes {
+		return s
+	}
+	for maxBytes > 0 && !utf8.RuneStart(s[maxBytes]) {
+		maxBytes--
+	}
+	return strings.TrimSpace(s[:maxBytes])
+}
@@ -0,0 +1,62 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextRules_Sanitize(t *testing.T) {
+	tests := []struct {
+		name            string
+		rules           TextRules
+		inName, inDesc  string
+		wantName, wantD string
+	}{
+		{
+			name:     "control characters and whitespace",
+			inName:   "  Road\tTrip\x00 ",
+			inDesc:   "line one\nline two\x07",
+			wantName: "Road Trip",
+			wantD:    "line one\nline two",
+		},
+		{
+			name:     "html",
+			rules:    TextRules{StripHTML: true, SingleLineDescription: true},
+			inName:   "Rock &amp; Roll",
+			inDesc:   "<b>Best</b> of\n<i>2024</i>",
+			wantName: "Rock & Roll",
+			wantD:    "Best of 2024",
+		},
+		{
+			name:     "emoji and forbidden characters",
+			rules:    TextRules{StripEmoji: true, Forbidden: "<>"},
+			inName:   "Summer 🌞 Vibes 👍🏽",
+			inDesc:   "<3 Café ❤️",
+			wantName: "Summer Vibes",
+			wantD:    "3 Café",
+		},
+		{
+			name:     "length limits",
+			rules:    TextRules{MaxNameBytes: 6, MaxDescriptionBytes: 4},
+			inName:   "Ação Total",
+			inDesc:   "abcdef",
+			wantName: "Ação",
+			wantD:    "abcd",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, desc := tt.rules.Sanitize(tt.inName, tt.inDesc)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantD, desc)
+		})
+	}
+}
+
+func TestTruncate_KeepsRunesWhole(t *testing.T) {
+	s := strings.Repeat("é", 10)
+	assert.Equal(t, strings.Repeat("é", 2), truncate(s, 5))
+	assert.Equal(t, s, truncate(s, 0))
+}
@@ -0,0 +1,71 @@
+package youtube
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// BlockMode is how search candidates matching the blocklist are treated.
+type BlockMode string
+
+const (
+	// BlockPenalize ranks blocked candidates after all others and scales
+	// their score by blockPenalty, so they only win when nothing else was
+	// found.
+	BlockPenalize BlockMode = "penalize"
+
+	// BlockReject drops blocked candidates.
+	BlockReject BlockMode = "reject"
+)
+
+// blockPenalty scales the score of penalized candidates.
+const blockPenalty = 0.5
+
+// DefaultBlocklist names channels and upload styles that re-edit or
+// re-perform tracks. Their titles often equal the original's once cleaned,
+// so they would otherwise win matches over official uploads.
+var DefaultBlocklist = []string{
+	"8D Audio",
+	"8D",
+	"Nightcore",
+	"Karaoke",
+	"Sing King",
+	"Slowed",
+	"Sped Up",
+	"Bass Boosted",
+}
+
+// blocklist matches candidates whose channel or raw title contains one of
+// its terms as whole words, ignoring case.
+type blocklist struct {
+	terms []*regexp.Regexp
+	mode  BlockMode
+}
+
+func newBlocklist(terms []string, mode BlockMode) *blocklist {
+	b := &blocklist{mode: mode}
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			b.terms = append(b.terms, regexp.MustCompile(`(?i)(^|[^\pL\pN])`+regexp.QuoteMeta(term)+`($|[^\pL\pN])`))
+		}
+	}
+	return b
+}
+
+// blocked reports whether a candidate with the given title and channel is
+// blocked when searching for source. Terms that appear in the source track
+// itself, such as a karaoke version migrated on purpose, do not block.
+func (b *blocklist) blocked(source domain.Track, title, channel string) bool {
+	if b == nil {
+		return false
+	}
+	wanted := source.Name + " " + strings.Join(source.Artists, " ") + " " + source.Album
+	for _, re := range b.terms {
+		if (re.MatchString(title) || re.MatchString(channel)) && !re.MatchString(wanted) {
+			return true
+		}
+	}
+	return false
+}
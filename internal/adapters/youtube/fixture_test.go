@@ -0,0 +1,51 @@
+package youtube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/fixture"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider_Fixture(t *testing.T) {
+	p := NewProvider(fixture.Client(t, "testdata/playlist.json"))
+	ctx := context.Background()
+
+	playlist, err := p.GetPlaylist(ctx, "token", "PLfixture01")
+	require.NoError(t, err)
+	assert.Equal(t, domain.Playlist{
+		ID:          "PLfixture01",
+		Name:        "Fixture Mix",
+		Description: "A playlist for testing",
+		OwnerName:   "Fixture Channel",
+		TrackCount:  3,
+		IsOwner:     true,
+	}, *playlist)
+
+	_, err = p.GetPlaylist(ctx, "token", "PLmissing")
+	assert.ErrorIs(t, err, domain.ErrPlaylistNotFound)
+
+	tracks, err := p.GetPlaylistTracks(ctx, "token", "PLfixture01")
+	require.NoError(t, err)
+	require.Len(t, tracks, 2, "the deleted video is skipped")
+	assert.Equal(t, "Get Lucky", tracks[0].Name)
+	assert.Equal(t, []string{"Daft Punk", "Pharrell Williams", "Nile Rodgers"}, tracks[0].Artists)
+	assert.Equal(t, "https://i.ytimg.com/vi/5NV6Rdv1a3I/hqdefault.jpg", tracks[0].AlbumArtURL)
+	assert.Equal(t, "Solitude Is Bliss", tracks[1].Name)
+	assert.Equal(t, "vOQa9TYh8wA", tracks[1].ExternalID)
+
+	matched, score, err := p.SearchTrack(ctx, "token", domain.Track{Name: "Get Lucky", Artists: []string{"Daft Punk"}})
+	require.NoError(t, err)
+	assert.Equal(t, "5NV6Rdv1a3I", matched.ExternalID)
+	assert.Greater(t, score, 0.8)
+
+	matched, _, err = p.SearchTrack(ctx, "token", domain.Track{Name: "No Such Song", Artists: []string{"Nobody"}})
+	require.NoError(t, err)
+	assert.Nil(t, matched)
+
+	_, _, err = p.SearchTrack(ctx, "token", domain.Track{Name: "Forbidden Song", Artists: []string{"Somebody"}})
+	assert.ErrorIs(t, err, domain.ErrRateLimited)
+}
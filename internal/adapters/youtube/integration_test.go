@@ -0,0 +1,49 @@
+//go:build integration
+
+package youtube
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIntegration reads a playlist of the account of YOUTUBE_TOKEN from the
+// real API and searches for its first video. YOUTUBE_PLAYLIST_ID selects
+// the playlist, defaulting to the first one of the account. Nothing is
+// written, but the search costs 100 quota units.
+func TestIntegration(t *testing.T) {
+	token := os.Getenv("YOUTUBE_TOKEN")
+	if token == "" {
+		t.Skip("YOUTUBE_TOKEN is not set")
+	}
+	p := NewProvider(nil)
+	ctx := context.Background()
+
+	playlistID := os.Getenv("YOUTUBE_PLAYLIST_ID")
+	if playlistID == "" {
+		playlists, err := p.GetPlaylists(ctx, token)
+		require.NoError(t, err)
+		if len(playlists) == 0 {
+			t.Skip("the account has no playlists; set YOUTUBE_PLAYLIST_ID")
+		}
+		playlistID = playlists[0].ID
+	}
+
+	playlist, err := p.GetPlaylist(ctx, token, playlistID)
+	require.NoError(t, err)
+	assert.Equal(t, playlistID, playlist.ID)
+
+	tracks, err := p.GetPlaylistTracks(ctx, token, playlistID)
+	require.NoError(t, err)
+	if len(tracks) == 0 {
+		t.Skip("the playlist is empty")
+	}
+
+	matched, _, err := p.SearchTrack(ctx, token, tracks[0])
+	require.NoError(t, err)
+	assert.NotNil(t, matched, "a video of a YouTube playlist is found on YouTube")
+}
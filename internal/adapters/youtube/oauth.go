@@ -0,0 +1,149 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// oauthClient holds the credentials of the Google OAuth client users
+// authorized.
+type oauthClient struct {
+	clientID     string
+	clientSecret string
+	tokenURL     string
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+}
+
+// RefreshToken implements ports.TokenRefresher. It needs the OAuth client
+// set with WithOAuthClient. Google usually keeps the refresh token, so the
+// returned token often has none.
+func (p *Provider) RefreshToken(ctx context.Context, refreshToken string) (*domain.ProviderToken, error) {
+	if p.oauth == nil {
+		return nil, errors.New("youtube: refreshing tokens requires an OAuth client")
+	}
+
+	tok, err := p.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("youtube: token refresh failed: %w", err)
+	}
+	return tok, nil
+}
+
+// AuthorizationURL implements ports.TokenAuthorizer. It needs the OAuth
+// client and its redirect URL. Offline access with forced consent makes
+// Google issue a refresh token even to users who linked before.
+func (p *Provider) AuthorizationURL(state string) (string, error) {
+	if p.oauth == nil || p.redirectURL == "" {
+		return "", fmt.Errorf("%w: youtube needs an OAuth client and a redirect URL", domain.ErrAuthorizationUnavailable)
+	}
+
+	query := url.Values{
+		"client_id":     {p.oauth.clientID},
+		"response_type": {"code"},
+		"redirect_uri":  {p.redirectURL},
+		"scope":         {scopeManage},
+		"access_type":   {"offline"},
+		"prompt":        {"consent"},
+		"state":         {state},
+	}
+	return authorizeURL + "?" + query.Encode(), nil
+}
+
+// ExchangeCode implements ports.TokenAuthorizer.
+func (p *Provider) ExchangeCode(ctx context.Context, code string) (*domain.ProviderToken, error) {
+	if p.oauth == nil || p.redirectURL == "" {
+		return nil, fmt.Errorf("%w: youtube needs an OAuth client and a redirect URL", domain.ErrAuthorizationUnavailable)
+	}
+
+	tok, err := p.requestToken(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {p.redirectURL},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("youtube: authorization code exchange failed: %w", err)
+	}
+	return tok, nil
+}
+
+// requestToken posts form to the token endpoint with the OAuth client's
+// credentials.
+func (p *Provider) requestToken(ctx context.Context, form url.Values) (*domain.ProviderToken, error) {
+	form.Set("client_id", p.oauth.clientID)
+	form.Set("client_secret", p.oauth.clientSecret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.oauth.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, adapters.SanitizeError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var tok tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return nil, errors.New("token response has no access token")
+	}
+
+	expires := time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return &domain.ProviderToken{
+		AccessToken:  tok.AccessToken,
+		RefreshToken: tok.RefreshToken,
+		ExpiresAt:    &expires,
+		Scopes:       strings.Fields(tok.Scope),
+	}, nil
+}
+
+// RevokeToken implements ports.TokenRevoker. Revoking either token of a
+// grant revokes the whole grant. Google answers 400 for tokens that are
+// already invalid, which counts as revoked.
+func (p *Provider) RevokeToken(ctx context.Context, token string) error {
+	form := url.Values{"token": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, revokeURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("youtube: token revocation failed: %w", adapters.SanitizeError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("youtube: token revocation failed: %w", &apiError{StatusCode: resp.StatusCode, Body: string(body)})
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package youtube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+func TestProvider_RefreshToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.PostForm.Get("refresh_token") != "refresh-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		assert.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		assert.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+		w.Write([]byte(`{"access_token":"access-2","expires_in":3599,"scope":"https://www.googleapis.com/auth/youtube","token_type":"Bearer"}`))
+	}))
+	defer srv.Close()
+
+	p := NewProvider(srv.Client(), WithOAuthClient("client-id", "client-secret"))
+	p.oauth.tokenURL = srv.URL
+
+	token, err := p.RefreshToken(context.Background(), "refresh-1")
+	require.NoError(t, err)
+	assert.Equal(t, "access-2", token.AccessToken)
+	require.NotNil(t, token.ExpiresAt)
+	assert.Equal(t, []string{scopeManage}, token.Scopes)
+
+	_, err = p.RefreshToken(context.Background(), "revoked")
+	assert.ErrorContains(t, err, "status 400")
+
+	_, err = NewProvider(srv.Client()).RefreshToken(context.Background(), "refresh-1")
+	assert.ErrorContains(t, err, "requires an OAuth client")
+}
+
+func TestProvider_RevokeToken(t *testing.T) {
+	var revoked []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "/revoke", r.URL.Path)
+		switch token := r.PostForm.Get("token"); token {
+		case "expired":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_token"}`))
+		case "broken":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			revoked = append(revoked, token)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewProvider(&http.Client{Transport: serverTransport{srv}})
+	require.NoError(t, p.RevokeToken(context.Background(), "refresh-1"))
+	assert.Equal(t, []string{"refresh-1"}, revoked)
+	assert.NoError(t, p.RevokeToken(context.Background(), "expired"), "invalid tokens count as revoked")
+	assert.ErrorContains(t, p.RevokeToken(context.Background(), "broken"), "status 503")
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+		assert.Equal(t, "https://api.example.com/callback", r.PostForm.Get("redirect_uri"))
+		assert.Equal(t, "code-1", r.PostForm.Get("code"))
+		w.Write([]byte(`{"access_token":"access-1","refresh_token":"refresh-1","expires_in":3599,"scope":"https://www.googleapis.com/auth/youtube"}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewProvider(srv.Client(), WithRedirectURL("https://api.example.com/callback")).AuthorizationURL("state-1")
+	assert.ErrorIs(t, err, domain.ErrAuthorizationUnavailable)
+
+	p := NewProvider(srv.Client(), WithOAuthClient("client-id", "client-secret"), WithRedirectURL("https://api.example.com/callback"))
+	p.oauth.tokenURL = srv.URL
+	authURL, err := p.AuthorizationURL("state-1")
+	require.NoError(t, err)
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "accounts.google.com", u.Host)
+	assert.Equal(t, "client-id", u.Query().Get("client_id"))
+	assert.Equal(t, scopeManage, u.Query().Get("scope"))
+	assert.Equal(t, "offline", u.Query().Get("access_type"))
+	assert.Equal(t, "state-1", u.Query().Get("state"))
+
+	token, err := p.ExchangeCode(context.Background(), "code-1")
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token.AccessToken)
+	assert.Equal(t, "refresh-1", token.RefreshToken)
+}
@@ -3,43 +3,205 @@ package youtube
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/cleaning"
 	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+	"github.com/jpp0ca/MusicMigration-API/pkg/matching"
 )
 
 const (
 	baseURL    = "https://www.googleapis.com/youtube/v3"
 	maxResults = 50
+
+	// musicCategoryID restricts searches to the "Music" video category.
+	musicCategoryID = "10"
+
+	tokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+	tokenURL     = "https://oauth2.googleapis.com/token"
+	revokeURL    = "https://oauth2.googleapis.com/revoke"
+	authorizeURL = "https://accounts.google.com/o/oauth2/v2/auth"
+
+	// oembedURL serves embed metadata of public videos without an API key
+	// or quota.
+	oembedURL = "https://www.youtube.com/oembed"
+
+	// maxPlaylistItems is the most videos a playlist can hold.
+	maxPlaylistItems = 5000
+
+	// defaultAddInterval spaces the single-video inserts of a playlist
+	// write, which YouTube otherwise throttles when sent back to back.
+	defaultAddInterval = 100 * time.Millisecond
+)
+
+// textRules follow the Data API, which rejects playlist titles over 150
+// characters, descriptions over 5000 bytes and angle brackets in either.
+// Emoji are removed as well, since some are rejected as invalid characters.
+var textRules = adapters.TextRules{
+	MaxNameBytes:        150,
+	MaxDescriptionBytes: 5000,
+	StripEmoji:          true,
+	Forbidden:           "<>",
+}
+
+// OAuth scopes of the YouTube Data API. Reading playlists needs any of
+// them, writing needs scopeManage or scopeForceSSL.
+const (
+	scopeReadOnly = "https://www.googleapis.com/auth/youtube.readonly"
+	scopeManage   = "https://www.googleapis.com/auth/youtube"
+	scopeForceSSL = "https://www.googleapis.com/auth/youtube.force-ssl"
 )
 
 // Provider implements ports.MusicProvider for YouTube using the Data API v3.
 type Provider struct {
-	client *http.Client
+	client      *http.Client
+	cleaner     *cleaning.Cleaner
+	oauth       *oauthClient
+	redirectURL string
+
+	cache       ports.SearchCache
+	cacheTTL    time.Duration
+	verifyCache bool
+
+	addInterval time.Duration
+	blocklist   *blocklist
+}
+
+// Option configures optional behavior of a Provider.
+type Option func(*Provider)
+
+// WithTitleCleaner sets the rules used to strip noise from video titles
+// before parsing and scoring them. Defaults to cleaning.Default().
+func WithTitleCleaner(cleaner *cleaning.Cleaner) Option {
+	return func(p *Provider) {
+		p.cleaner = cleaner
+	}
+}
+
+// WithOAuthClient sets the Google OAuth client the users' tokens were issued
+// to, which is needed to refresh them.
+func WithOAuthClient(clientID, clientSecret string) Option {
+	return func(p *Provider) {
+		p.oauth = &oauthClient{
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			tokenURL:     tokenURL,
+		}
+	}
+}
+
+// WithRedirectURL sets the callback URL registered with the OAuth client of
+// WithOAuthClient, which lets users link their account through the
+// authorization-code flow.
+func WithRedirectURL(redirectURL string) Option {
+	return func(p *Provider) {
+		p.redirectURL = redirectURL
+	}
+}
+
+// WithSearchCache keeps search responses in cache for ttl, keyed by the
+// normalized query, video category and region. A search costs 100 quota
+// units, so answering repeated searches from the cache saves most of the
+// daily quota when the same tracks are migrated again.
+func WithSearchCache(cache ports.SearchCache, ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.cache = cache
+		p.cacheTTL = ttl
+	}
+}
+
+// WithOEmbedVerification checks the videos of cached search responses with
+// the quota-free oEmbed endpoint before using them, so videos deleted or
+// made private since the search are not matched. A cached search whose
+// videos are all gone is run again.
+func WithOEmbedVerification() Option {
+	return func(p *Provider) {
+		p.verifyCache = true
+	}
+}
+
+// WithAddInterval sets the pause between the inserts of videos added to a
+// playlist. Defaults to 100ms; zero sends them back to back.
+func WithAddInterval(interval time.Duration) Option {
+	return func(p *Provider) {
+		p.addInterval = max(interval, 0)
+	}
+}
+
+// WithBlocklist sets the terms that mark search candidates as unwanted
+// uploads, such as nightcore or karaoke versions, and how those are
+// treated. Defaults to DefaultBlocklist with BlockPenalize; no terms
+// disable the blocklist.
+func WithBlocklist(terms []string, mode BlockMode) Option {
+	return func(p *Provider) {
+		p.blocklist = nil
+		if len(terms) > 0 {
+			p.blocklist = newBlocklist(terms, mode)
+		}
+	}
 }
 
 // NewProvider creates a new YouTube provider with the given HTTP client.
 // If client is nil, http.DefaultClient is used.
-func NewProvider(client *http.Client) *Provider {
+func NewProvider(client *http.Client, opts ...Option) *Provider {
 	if client == nil {
 		client = http.DefaultClient
 	}
-	return &Provider{client: client}
+	p := &Provider{
+		client:      client,
+		addInterval: defaultAddInterval,
+		blocklist:   newBlocklist(DefaultBlocklist, BlockPenalize),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.cleaner == nil {
+		p.cleaner = cleaning.Default()
+	}
+	return p
 }
 
 func (p *Provider) Name() string {
 	return "youtube"
 }
 
+// QuotaCost implements ports.QuotaCoster using the YouTube Data API v3 unit
+// costs: search.list = 100, playlists.insert = 50, playlistItems.insert = 50,
+// and playlists.list and playlistItems.list = 1.
+func (p *Provider) QuotaCost(op domain.QuotaOperation) int {
+	switch op {
+	case domain.QuotaOpSearch:
+		return 100
+	case domain.QuotaOpCreatePlaylist, domain.QuotaOpAddTrack:
+		return 50
+	case domain.QuotaOpFetch:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// TrackPageSize implements ports.TrackPager.
+func (p *Provider) TrackPageSize() int { return maxResults }
+
 // -- API response types (internal) ------------------------------------------
 
 type playlistListResponse struct {
 	Items         []playlistResource `json:"items"`
 	NextPageToken string             `json:"nextPageToken"`
+	PageInfo      pageInfo           `json:"pageInfo"`
+}
+
+type pageInfo struct {
+	TotalResults int `json:"totalResults"`
 }
 
 type playlistResource struct {
@@ -48,14 +210,32 @@ type playlistResource struct {
 	ContentDetails struct {
 		ItemCount int `json:"itemCount"`
 	} `json:"contentDetails"`
+	Status struct {
+		PrivacyStatus string `json:"privacyStatus"`
+	} `json:"status"`
 }
 
 type playlistSnippet struct {
 	Title        string `json:"title"`
 	Description  string `json:"description"`
+	ChannelID    string `json:"channelId"`
 	ChannelTitle string `json:"channelTitle"`
 }
 
+// playlist converts the resource to a domain.Playlist. YouTube playlists
+// cannot be collaborative through the Data API.
+func (r playlistResource) playlist(owned bool) domain.Playlist {
+	return domain.Playlist{
+		ID:          r.ID,
+		Name:        r.Snippet.Title,
+		Description: r.Snippet.Description,
+		OwnerName:   r.Snippet.ChannelTitle,
+		TrackCount:  r.ContentDetails.ItemCount,
+		IsOwner:     owned,
+		IsPublic:    r.Status.PrivacyStatus == "public",
+	}
+}
+
 type playlistItemsResponse struct {
 	Items         []playlistItemResource `json:"items"`
 	NextPageToken string                 `json:"nextPageToken"`
@@ -69,12 +249,50 @@ type playlistItemSnippet struct {
 	Title                  string     `json:"title"`
 	VideoOwnerChannelTitle string     `json:"videoOwnerChannelTitle"`
 	ResourceID             resourceID `json:"resourceId"`
+	Thumbnails             thumbnails `json:"thumbnails"`
+}
+
+type thumbnails struct {
+	Default thumbnail `json:"default"`
+	High    thumbnail `json:"high"`
+}
+
+type thumbnail struct {
+	URL string `json:"url"`
+}
+
+// best returns the highest-resolution thumbnail URL available.
+func (t thumbnails) best() string {
+	if t.High.URL != "" {
+		return t.High.URL
+	}
+	return t.Default.URL
 }
 
 type resourceID struct {
 	VideoID string `json:"videoId"`
 }
 
+type playlistItemIDsResponse struct {
+	Items []struct {
+		ID      string              `json:"id"`
+		Snippet playlistItemSnippet `json:"snippet"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+type playlistDetailsResponse struct {
+	Items []struct {
+		Snippet struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+		} `json:"snippet"`
+		Status struct {
+			PrivacyStatus string `json:"privacyStatus"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
 type searchListResponse struct {
 	Items []searchResult `json:"items"`
 }
@@ -89,54 +307,121 @@ type searchResultID struct {
 }
 
 type searchSnippet struct {
-	Title        string `json:"title"`
-	ChannelTitle string `json:"channelTitle"`
+	Title        string     `json:"title"`
+	ChannelTitle string     `json:"channelTitle"`
+	Thumbnails   thumbnails `json:"thumbnails"`
 }
 
 // -- MusicProvider implementation --------------------------------------------
 
 func (p *Provider) GetPlaylists(ctx context.Context, token string) ([]domain.Playlist, error) {
 	var playlists []domain.Playlist
-	pageToken := ""
+	page := domain.PageRequest{Limit: maxResults}
 
 	for {
-		endpoint := fmt.Sprintf(
-			"%s/playlists?part=snippet,contentDetails&mine=true&maxResults=%d",
-			baseURL, maxResults,
-		)
-		if pageToken != "" {
-			endpoint += "&pageToken=" + pageToken
-		}
-
-		body, err := p.doGet(ctx, token, endpoint)
+		resp, err := p.GetPlaylistsPage(ctx, token, page)
 		if err != nil {
-			return nil, fmt.Errorf("youtube: failed to get playlists: %w", err)
-		}
-
-		var resp playlistListResponse
-		if err := json.Unmarshal(body, &resp); err != nil {
-			return nil, fmt.Errorf("youtube: failed to parse playlists response: %w", err)
+			return nil, err
 		}
+		playlists = append(playlists, resp.Items...)
 
-		for _, item := range resp.Items {
-			playlists = append(playlists, domain.Playlist{
-				ID:          item.ID,
-				Name:        item.Snippet.Title,
-				Description: item.Snippet.Description,
-				OwnerName:   item.Snippet.ChannelTitle,
-				TrackCount:  item.ContentDetails.ItemCount,
-			})
-		}
-
-		if resp.NextPageToken == "" {
+		if resp.NextCursor == "" {
 			break
 		}
-		pageToken = resp.NextPageToken
+		page.Cursor = resp.NextCursor
 	}
 
 	return playlists, nil
 }
 
+// GetPlaylistsPage fetches one page of playlists. The cursor is YouTube's
+// nextPageToken.
+func (p *Provider) GetPlaylistsPage(ctx context.Context, token string, page domain.PageRequest) (*domain.PlaylistPage, error) {
+	limit := page.Limit
+	if limit <= 0 || limit > maxResults {
+		limit = maxResults
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/playlists?part=snippet,contentDetails,status&mine=true&maxResults=%d",
+		baseURL, limit,
+	)
+	if page.Cursor != "" {
+		endpoint += "&pageToken=" + url.QueryEscape(page.Cursor)
+	}
+
+	body, err := p.doGet(ctx, token, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("youtube: failed to get playlists: %w", err)
+	}
+
+	var resp playlistListResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("youtube: failed to parse playlists response: %w", err)
+	}
+
+	result := &domain.PlaylistPage{
+		Items:      make([]domain.Playlist, 0, len(resp.Items)),
+		NextCursor: resp.NextPageToken,
+		Total:      resp.PageInfo.TotalResults,
+	}
+	// The listing only has the user's own playlists.
+	for _, item := range resp.Items {
+		result.Items = append(result.Items, item.playlist(true))
+	}
+
+	return result, nil
+}
+
+func (p *Provider) GetPlaylist(ctx context.Context, token string, playlistID string) (*domain.Playlist, error) {
+	endpoint := fmt.Sprintf("%s/playlists?part=snippet,contentDetails,status&id=%s", baseURL, url.QueryEscape(playlistID))
+
+	body, err := p.doGet(ctx, token, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("youtube: failed to get playlist: %w", err)
+	}
+
+	var resp playlistListResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("youtube: failed to parse playlist response: %w", err)
+	}
+
+	if len(resp.Items) == 0 {
+		return nil, domain.ErrPlaylistNotFound
+	}
+
+	channelID, err := p.channelID(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	item := resp.Items[0]
+	playlist := item.playlist(item.Snippet.ChannelID == channelID)
+	return &playlist, nil
+}
+
+// channelID returns the ID of the token owner's YouTube channel, or an
+// empty string if the account has none.
+func (p *Provider) channelID(ctx context.Context, token string) (string, error) {
+	body, err := p.doGet(ctx, token, baseURL+"/channels?part=id&mine=true")
+	if err != nil {
+		return "", fmt.Errorf("youtube: failed to get channel: %w", err)
+	}
+
+	var resp struct {
+		Items []struct {
+			ID string `json:"id"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("youtube: failed to parse channel response: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return "", nil
+	}
+	return resp.Items[0].ID, nil
+}
+
 func (p *Provider) GetPlaylistTracks(ctx context.Context, token string, playlistID string) ([]domain.Track, error) {
 	var tracks []domain.Track
 	pageToken := ""
@@ -167,18 +452,19 @@ func (p *Provider) GetPlaylistTracks(ctx context.Context, token string, playlist
 
 			// YouTube playlist items only give us title and channel; we parse
 			// the track name and artist from the video title heuristically.
-			name, artist := parseVideoTitle(item.Snippet.Title)
+			name, artists := p.parseVideoTitle(item.Snippet.Title)
 			if name == "" {
 				name = item.Snippet.Title
 			}
-			if artist == "" {
-				artist = item.Snippet.VideoOwnerChannelTitle
+			if len(artists) == 0 && item.Snippet.VideoOwnerChannelTitle != "" {
+				artists = []string{item.Snippet.VideoOwnerChannelTitle}
 			}
 
 			tracks = append(tracks, domain.Track{
-				Name:       name,
-				Artist:     artist,
-				ExternalID: item.Snippet.ResourceID.VideoID,
+				Name:        name,
+				Artists:     artists,
+				ExternalID:  item.Snippet.ResourceID.VideoID,
+				AlbumArtURL: item.Snippet.Thumbnails.best(),
 			})
 		}
 
@@ -192,36 +478,192 @@ func (p *Provider) GetPlaylistTracks(ctx context.Context, token string, playlist
 }
 
 func (p *Provider) SearchTrack(ctx context.Context, token string, track domain.Track) (*domain.Track, float64, error) {
-	query := fmt.Sprintf("%s %s", track.Name, track.Artist)
+	candidates, err := p.SearchTrackCandidates(ctx, token, track)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(candidates) == 0 {
+		return nil, 0, nil
+	}
+
+	// Candidates come best-scored first; the others are reported as
+	// runner-ups.
+	domain.ReportRunnerUps(ctx, candidates[1:])
+	return &candidates[0].Track, candidates[0].ConfidenceScore, nil
+}
+
+func (p *Provider) SearchTrackCandidates(ctx context.Context, token string, track domain.Track) ([]domain.TrackCandidate, error) {
+	query := fmt.Sprintf("%s %s", track.Name, track.Artist())
+	items, err := p.searchVideos(ctx, token, query, musicCategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Candidates are ranked by score rather than by YouTube, whose top
+	// result is often a live recording, a cover or another movement of a
+	// work; its ranking only breaks ties. Candidates penalized by the
+	// blocklist go after all others, so the top-ranked candidate is an
+	// unblocked one whenever there is one.
+	candidates := make([]domain.TrackCandidate, 0, len(items))
+	var blocked []domain.TrackCandidate
+	for _, item := range items {
+		matched := domain.Track{
+			Name:        item.Snippet.Title,
+			Artists:     []string{item.Snippet.ChannelTitle},
+			ExternalID:  item.ID.VideoID,
+			AlbumArtURL: item.Snippet.Thumbnails.best(),
+		}
+		// Score against the cleaned title so upload markers such as
+		// "(Official Video)" don't count against the match.
+		scored := matched
+		scored.Name = p.cleaner.Clean(matched.Name)
+		candidate := domain.TrackCandidate{
+			Track:           matched,
+			ConfidenceScore: score(adapters.Scorer(ctx, matching.Title), track, scored),
+		}
+		if p.blocklist.blocked(track, item.Snippet.Title, item.Snippet.ChannelTitle) {
+			if p.blocklist.mode != BlockReject {
+				candidate.ConfidenceScore *= blockPenalty
+				blocked = append(blocked, candidate)
+			}
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	domain.RankCandidates(candidates)
+	domain.RankCandidates(blocked)
+	return append(candidates, blocked...), nil
+}
+
+// SearchEpisode looks up a podcast episode as a video. Episodes are not
+// restricted to the Music category.
+func (p *Provider) SearchEpisode(ctx context.Context, token string, episode domain.Track) (*domain.Track, float64, error) {
+	query := episode.Name
+	if episode.Show != nil && episode.Show.Name != "" {
+		query = episode.Show.Name + " " + query
+	}
+	items, err := p.searchVideos(ctx, token, query, "")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(items) == 0 {
+		return nil, 0, nil
+	}
+
+	// Take the top result as ranked by YouTube
+	best := items[0]
+	matched := domain.Track{
+		Name:        best.Snippet.Title,
+		ExternalID:  best.ID.VideoID,
+		Type:        domain.ItemTypeEpisode,
+		Show:        &domain.Show{Name: best.Snippet.ChannelTitle},
+		AlbumArtURL: best.Snippet.Thumbnails.best(),
+	}
+	return &matched, score(matching.TitleEpisode, episode, matched), nil
+}
+
+// searchVideos runs a video search, optionally restricted to a category, in
+// the market carried by ctx. With a search cache, cached responses are used
+// instead, which is reported on ctx, and new responses are cached.
+func (p *Provider) searchVideos(ctx context.Context, token string, query string, categoryID string) ([]searchResult, error) {
+	market := domain.MarketFromContext(ctx)
+	key := searchCacheKey(query, categoryID, market)
+	if items, ok := p.cachedSearch(ctx, key); ok {
+		domain.ReportCacheHit(ctx)
+		return items, nil
+	}
+
 	endpoint := fmt.Sprintf(
-		"%s/search?part=snippet&type=video&videoCategoryId=10&maxResults=5&q=%s",
+		"%s/search?part=snippet&type=video&maxResults=5&q=%s",
 		baseURL, url.QueryEscape(query),
 	)
+	if categoryID != "" {
+		endpoint += "&videoCategoryId=" + categoryID
+	}
+	if market != "" {
+		endpoint += "&regionCode=" + url.QueryEscape(market)
+	}
 
 	body, err := p.doGet(ctx, token, endpoint)
 	if err != nil {
-		return nil, 0, fmt.Errorf("youtube: search failed: %w", err)
+		return nil, fmt.Errorf("youtube: search failed: %w", err)
 	}
 
 	var resp searchListResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, 0, fmt.Errorf("youtube: failed to parse search response: %w", err)
+		return nil, fmt.Errorf("youtube: failed to parse search response: %w", err)
 	}
 
-	if len(resp.Items) == 0 {
-		return nil, 0, nil
+	// The cache only saves quota; a search that cannot be cached is still
+	// answered.
+	if p.cache != nil {
+		_ = p.cache.Put(ctx, key, body, p.cacheTTL)
 	}
+	return resp.Items, nil
+}
 
-	// Pick the best result based on title similarity
-	best := resp.Items[0]
-	matched := domain.Track{
-		Name:       best.Snippet.Title,
-		Artist:     best.Snippet.ChannelTitle,
-		ExternalID: best.ID.VideoID,
+// searchCacheKey identifies a search in the search cache. Queries that
+// normalize alike share an entry.
+func searchCacheKey(query string, categoryID string, market string) string {
+	return fmt.Sprintf("youtube:search:%s:%s:%s", categoryID, strings.ToUpper(market), matching.Normalize(query))
+}
+
+// cachedSearch returns the cached results of the search stored under key,
+// if any. With oEmbed verification, results whose videos are gone are
+// dropped, and the entry is evicted if none is left. Cache failures count
+// as misses.
+func (p *Provider) cachedSearch(ctx context.Context, key string) ([]searchResult, bool) {
+	if p.cache == nil {
+		return nil, false
+	}
+	data, err := p.cache.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+	var resp searchListResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false
+	}
+	if !p.verifyCache || len(resp.Items) == 0 {
+		return resp.Items, true
 	}
 
-	score := calculateConfidence(track, matched)
-	return &matched, score, nil
+	items := make([]searchResult, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		if p.videoAvailable(ctx, item.ID.VideoID) {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		_ = p.cache.Delete(ctx, key)
+		return nil, false
+	}
+	return items, true
+}
+
+// videoAvailable reports whether the video with the given ID still exists
+// and is public, asking the oEmbed endpoint. oEmbed answers 404 for deleted
+// videos and 403 for private ones; 401 means embedding is disabled, which
+// does not matter for a playlist. Videos that cannot be checked are assumed
+// to be available.
+func (p *Provider) videoAvailable(ctx context.Context, videoID string) bool {
+	query := url.Values{
+		"format": {"json"},
+		"url":    {"https://www.youtube.com/watch?v=" + videoID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, oembedURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return true
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return true
+	}
+	resp.Body.Close()
+	return resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusForbidden
 }
 
 func (p *Provider) CreatePlaylist(ctx context.Context, token string, name string, description string) (string, error) {
@@ -252,31 +694,314 @@ func (p *Provider) CreatePlaylist(ctx context.Context, token string, name string
 	return resp.ID, nil
 }
 
-func (p *Provider) AddTracksToPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) error {
+func (p *Provider) AddTracksToPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) ([]domain.AddOutcome, error) {
+	return p.addVideos(ctx, token, playlistID, -1, trackIDs)
+}
+
+// InsertTracksAt implements ports.PositionalAdder.
+func (p *Provider) InsertTracksAt(ctx context.Context, token string, playlistID string, position int, trackIDs []string) ([]domain.AddOutcome, error) {
+	return p.addVideos(ctx, token, playlistID, position, trackIDs)
+}
+
+// addVideos adds videos starting at position, or appends them if position is
+// negative. Videos YouTube rejects, e.g. because they were removed, are
+// skipped; any other error, or the cancellation of ctx, aborts the call with
+// an error telling how many videos were added before.
+func (p *Provider) addVideos(ctx context.Context, token string, playlistID string, position int, trackIDs []string) ([]domain.AddOutcome, error) {
+	outcomes := make([]domain.AddOutcome, 0, len(trackIDs))
+	added := 0
+
 	// YouTube requires adding one video at a time via playlistItems.insert
-	for _, videoID := range trackIDs {
-		payload := map[string]interface{}{
-			"snippet": map[string]interface{}{
-				"playlistId": playlistID,
-				"resourceId": map[string]string{
-					"kind":    "youtube#video",
-					"videoId": videoID,
-				},
+	for i, videoID := range trackIDs {
+		if err := adapters.Pause(ctx, i, p.addInterval); err != nil {
+			err = fmt.Errorf("youtube: adding videos stopped after %d of %d: %w", added, len(trackIDs), err)
+			return domain.NotAdded(outcomes, trackIDs, err), err
+		}
+		snippet := map[string]interface{}{
+			"playlistId": playlistID,
+			"resourceId": map[string]string{
+				"kind":    "youtube#video",
+				"videoId": videoID,
 			},
 		}
+		if position >= 0 {
+			snippet["position"] = position + added
+		}
+		payload := map[string]interface{}{"snippet": snippet}
 		payloadBytes, _ := json.Marshal(payload)
 
 		endpoint := fmt.Sprintf("%s/playlistItems?part=snippet", baseURL)
 		if _, err := p.doPost(ctx, token, endpoint, payloadBytes); err != nil {
-			return fmt.Errorf("youtube: failed to add video %s to playlist: %w", videoID, err)
+			if !rejected(err) {
+				err = fmt.Errorf("youtube: failed to add video %s to playlist after adding %d of %d: %w", videoID, added, len(trackIDs), err)
+				return domain.NotAdded(outcomes, trackIDs, err), err
+			}
+			err = fmt.Errorf("youtube: failed to add video %s to playlist: %w", videoID, err)
+			outcomes = append(outcomes, domain.AddOutcome{TrackID: videoID, Error: err.Error()})
+			continue
+		}
+		outcomes = append(outcomes, domain.AddOutcome{TrackID: videoID, Added: true})
+		added++
+	}
+
+	return outcomes, nil
+}
+
+// rejected reports whether YouTube refused to add a single video, e.g.
+// because it does not exist or cannot be added, as opposed to errors that
+// affect every further request such as a missing playlist.
+func rejected(err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || strings.Contains(apiErr.Body, "playlistNotFound") {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusBadRequest || apiErr.StatusCode == http.StatusNotFound
+}
+
+func (p *Provider) RemoveTracksFromPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) error {
+	remove := make(map[string]bool, len(trackIDs))
+	for _, id := range trackIDs {
+		remove[id] = true
+	}
+
+	// Deletion works on playlist item IDs, so we first have to map each
+	// video ID to the item(s) that reference it.
+	var itemIDs []string
+	pageToken := ""
+	for {
+		endpoint := fmt.Sprintf(
+			"%s/playlistItems?part=snippet&playlistId=%s&maxResults=%d",
+			baseURL, playlistID, maxResults,
+		)
+		if pageToken != "" {
+			endpoint += "&pageToken=" + pageToken
+		}
+
+		body, err := p.doGet(ctx, token, endpoint)
+		if err != nil {
+			return fmt.Errorf("youtube: failed to get playlist items: %w", err)
+		}
+
+		var resp playlistItemIDsResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("youtube: failed to parse playlist items response: %w", err)
+		}
+
+		for _, item := range resp.Items {
+			if remove[item.Snippet.ResourceID.VideoID] {
+				itemIDs = append(itemIDs, item.ID)
+			}
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	// YouTube requires removing one item at a time via playlistItems.delete
+	for _, itemID := range itemIDs {
+		endpoint := fmt.Sprintf("%s/playlistItems?id=%s", baseURL, url.QueryEscape(itemID))
+		if _, err := p.doDelete(ctx, token, endpoint, nil); err != nil {
+			return fmt.Errorf("youtube: failed to remove item %s from playlist: %w", itemID, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) UpdatePlaylistDetails(ctx context.Context, token string, playlistID string, update domain.PlaylistUpdate) error {
+	// playlists.update replaces the whole snippet, so unchanged fields must
+	// be carried over from the current playlist.
+	endpoint := fmt.Sprintf("%s/playlists?part=snippet,status&id=%s", baseURL, url.QueryEscape(playlistID))
+	body, err := p.doGet(ctx, token, endpoint)
+	if err != nil {
+		return fmt.Errorf("youtube: failed to get playlist: %w", err)
+	}
+
+	var current playlistDetailsResponse
+	if err := json.Unmarshal(body, &current); err != nil {
+		return fmt.Errorf("youtube: failed to parse playlist response: %w", err)
+	}
+	if len(current.Items) == 0 {
+		return fmt.Errorf("youtube: playlist %s not found", playlistID)
+	}
+
+	title := current.Items[0].Snippet.Title
+	description := current.Items[0].Snippet.Description
+	privacy := current.Items[0].Status.PrivacyStatus
+	if update.Name != nil {
+		title = *update.Name
+	}
+	if update.Description != nil {
+		description = *update.Description
+	}
+	if update.Public != nil {
+		privacy = "private"
+		if *update.Public {
+			privacy = "public"
 		}
 	}
 
+	payload := map[string]interface{}{
+		"id": playlistID,
+		"snippet": map[string]string{
+			"title":       title,
+			"description": description,
+		},
+		"status": map[string]string{
+			"privacyStatus": privacy,
+		},
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	endpoint = fmt.Sprintf("%s/playlists?part=snippet,status", baseURL)
+	if _, err := p.doPut(ctx, token, endpoint, payloadBytes); err != nil {
+		return fmt.Errorf("youtube: failed to update playlist: %w", err)
+	}
+	return nil
+}
+
+func (p *Provider) DeletePlaylist(ctx context.Context, token string, playlistID string) error {
+	endpoint := fmt.Sprintf("%s/playlists?id=%s", baseURL, url.QueryEscape(playlistID))
+	if _, err := p.doDelete(ctx, token, endpoint, nil); err != nil {
+		return fmt.Errorf("youtube: failed to delete playlist: %w", err)
+	}
 	return nil
 }
 
+// Ping checks that the YouTube Data API is reachable. The request is
+// unauthenticated, so any response below 500 counts as reachable.
+func (p *Provider) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/videos", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("youtube: ping failed: %w", adapters.SanitizeError(err))
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("youtube: ping failed: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SanitizePlaylistText implements ports.TextSanitizer.
+func (p *Provider) SanitizePlaylistText(name, description string) (string, string) {
+	return textRules.Sanitize(name, description)
+}
+
+// MaxPlaylistItems implements ports.PlaylistCapacity.
+func (p *Provider) MaxPlaylistItems() int {
+	return maxPlaylistItems
+}
+
+// CheckToken implements ports.TokenChecker using Google's tokeninfo
+// endpoint, which reports the scopes granted to an access token.
+func (p *Provider) CheckToken(ctx context.Context, token string, write bool) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenInfoURL+"?"+url.Values{"access_token": {token}}.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("youtube: token check failed: %w", adapters.SanitizeError(err))
+	}
+	defer resp.Body.Close()
+
+	// tokeninfo answers 400 for unknown and expired tokens.
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("youtube: %w", domain.ErrInvalidToken)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("youtube: token check failed: %w", &apiError{StatusCode: resp.StatusCode, Body: string(body)})
+	}
+
+	var info struct {
+		Scope string `json:"scope"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return fmt.Errorf("youtube: failed to parse token info: %w", err)
+	}
+	return missingScope(strings.Fields(info.Scope), write)
+}
+
+// missingScope returns a *domain.MissingScopeError naming the scope to
+// request if granted does not allow reading, or writing if write is set.
+func missingScope(granted []string, write bool) error {
+	var read, manage bool
+	for _, scope := range granted {
+		switch scope {
+		case scopeManage, scopeForceSSL:
+			read, manage = true, true
+		case scopeReadOnly:
+			read = true
+		}
+	}
+
+	switch {
+	case write && !manage:
+		return &domain.MissingScopeError{Provider: "youtube", Scope: scopeManage}
+	case !read:
+		return &domain.MissingScopeError{Provider: "youtube", Scope: scopeReadOnly}
+	default:
+		return nil
+	}
+}
+
 // -- HTTP helpers ------------------------------------------------------------
 
+// apiError is returned by the HTTP helpers when YouTube responds with a
+// non-success status code.
+type apiError struct {
+	StatusCode int
+	Body       string
+
+	// retryAfter is the wait the Retry-After header asked for, if any.
+	retryAfter time.Duration
+}
+
+// RetryAfter returns the wait a rate-limited response asked for, for
+// domain.RetryAfter.
+func (e *apiError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("youtube API returned status %d: %s", e.StatusCode, adapters.ErrorBody(e.Body))
+}
+
+// Is reports 429 responses and 403 rate or quota errors as
+// domain.ErrRateLimited, other 403 responses as domain.ErrInsufficientScope,
+// 401 responses as domain.ErrInvalidToken and missing playlists as
+// domain.ErrPlaylistNotFound.
+func (e *apiError) Is(target error) bool {
+	switch target {
+	case domain.ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests || e.quotaError()
+	case domain.ErrInsufficientScope:
+		return e.StatusCode == http.StatusForbidden && !e.quotaError()
+	case domain.ErrInvalidToken:
+		return e.StatusCode == http.StatusUnauthorized
+	case domain.ErrPlaylistNotFound:
+		return e.StatusCode == http.StatusNotFound && strings.Contains(e.Body, "playlistNotFound")
+	default:
+		return false
+	}
+}
+
+// quotaError reports whether the response is a 403 rate or quota error.
+func (e *apiError) quotaError() bool {
+	return e.StatusCode == http.StatusForbidden &&
+		(strings.Contains(e.Body, "rateLimitExceeded") || strings.Contains(e.Body, "quotaExceeded"))
+}
+
 func (p *Provider) doGet(ctx context.Context, token string, endpoint string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
@@ -286,7 +1011,7 @@ func (p *Provider) doGet(ctx context.Context, token string, endpoint string) ([]
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, adapters.SanitizeError(err)
 	}
 	defer resp.Body.Close()
 
@@ -296,7 +1021,7 @@ func (p *Provider) doGet(ctx context.Context, token string, endpoint string) ([]
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("youtube API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body), retryAfter: adapters.RetryAfter(resp.Header)}
 	}
 
 	return body, nil
@@ -312,7 +1037,7 @@ func (p *Provider) doPost(ctx context.Context, token string, endpoint string, pa
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, adapters.SanitizeError(err)
 	}
 	defer resp.Body.Close()
 
@@ -322,75 +1047,93 @@ func (p *Provider) doPost(ctx context.Context, token string, endpoint string, pa
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("youtube API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body), retryAfter: adapters.RetryAfter(resp.Header)}
 	}
 
 	return body, nil
 }
 
-// -- Helpers -----------------------------------------------------------------
+func (p *Provider) doPut(ctx context.Context, token string, endpoint string, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, strings.NewReader(string(payload)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
 
-// parseVideoTitle attempts to split a YouTube video title into track name and
-// artist. Common formats: "Artist - Track", "Artist - Track (Official Video)".
-func parseVideoTitle(title string) (name, artist string) {
-	// Remove common suffixes
-	suffixes := []string{
-		"(Official Video)", "(Official Music Video)", "(Official Audio)",
-		"(Lyric Video)", "(Lyrics)", "(Audio)", "[Official Video]",
-		"[Official Music Video]", "[Official Audio]", "(HD)", "(HQ)",
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, adapters.SanitizeError(err)
 	}
-	cleaned := title
-	for _, suffix := range suffixes {
-		cleaned = strings.TrimSpace(strings.Replace(cleaned, suffix, "", 1))
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	// Split on " - " separator
-	parts := strings.SplitN(cleaned, " - ", 2)
-	if len(parts) == 2 {
-		return strings.TrimSpace(parts[1]), strings.TrimSpace(parts[0])
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body), retryAfter: adapters.RetryAfter(resp.Header)}
 	}
 
-	return cleaned, ""
+	return body, nil
 }
 
-func calculateConfidence(source domain.Track, matched domain.Track) float64 {
-	score := 0.0
+func (p *Provider) doDelete(ctx context.Context, token string, endpoint string, payload []byte) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = strings.NewReader(string(payload))
+	}
 
-	sourceName := strings.ToLower(source.Name)
-	matchedTitle := strings.ToLower(matched.Name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
-	// Check if the track name appears in the YouTube title
-	if strings.Contains(matchedTitle, sourceName) {
-		score += 0.5
-	} else {
-		// Check individual words overlap
-		sourceWords := strings.Fields(sourceName)
-		matchCount := 0
-		for _, word := range sourceWords {
-			if len(word) > 2 && strings.Contains(matchedTitle, word) {
-				matchCount++
-			}
-		}
-		if len(sourceWords) > 0 {
-			score += 0.5 * float64(matchCount) / float64(len(sourceWords))
-		}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, adapters.SanitizeError(err)
 	}
+	defer resp.Body.Close()
 
-	// Check if the artist appears in the title or channel
-	sourceArtist := strings.ToLower(source.Artist)
-	matchedArtist := strings.ToLower(matched.Artist)
-	if strings.Contains(matchedArtist, sourceArtist) || strings.Contains(matchedTitle, sourceArtist) {
-		score += 0.4
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	// Bonus for exact title match
-	if sourceName == matchedTitle {
-		score += 0.1
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body), retryAfter: adapters.RetryAfter(resp.Header)}
 	}
 
-	if score > 1.0 {
-		score = 1.0
+	return body, nil
+}
+
+// -- Helpers -----------------------------------------------------------------
+
+// parseVideoTitle attempts to split a YouTube video title into track name and
+// artist. Common formats: "Artist - Track", "Artist - Track (Official Video)",
+// "Artist ft. Other - Track". Featured artists are returned after the main
+// artist. The main artist is kept whole: titles separate artists with
+// commas, "&" or "x" just as names like "Earth, Wind & Fire" contain them.
+func (p *Provider) parseVideoTitle(title string) (name string, artists []string) {
+	cleaned, featured := cleaning.SplitFeaturing(p.cleaner.Clean(title))
+
+	// Split on " - " separator
+	parts := strings.SplitN(cleaned, " - ", 2)
+	if len(parts) != 2 {
+		return cleaned, nil
 	}
 
-	return score
+	name = strings.TrimSpace(parts[1])
+	artists = append([]string{strings.TrimSpace(parts[0])}, featured...)
+	return name, artists
+}
+
+// score rates matched against source with scorer.
+func score(scorer matching.Scorer, source, matched domain.Track) float64 {
+	return scorer.Score(adapters.MatchTrack(source), adapters.MatchTrack(matched))
 }
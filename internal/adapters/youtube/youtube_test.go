@@ -0,0 +1,212 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		granted []string
+		write   bool
+		want    string
+	}{
+		{name: "read with readonly", granted: []string{scopeReadOnly}},
+		{name: "write with manage", granted: []string{"openid", scopeManage}, write: true},
+		{name: "write with force-ssl", granted: []string{scopeForceSSL}, write: true},
+		{name: "write with readonly", granted: []string{scopeReadOnly}, write: true, want: scopeManage},
+		{name: "read without scopes", granted: []string{"openid"}, want: scopeReadOnly},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := missingScope(tt.granted, tt.write)
+			if tt.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, domain.ErrInsufficientScope)
+			assert.Equal(t, &domain.MissingScopeError{Provider: "youtube", Scope: tt.want}, err)
+		})
+	}
+}
+
+// serverTransport sends every request to srv, keeping its path and query.
+type serverTransport struct {
+	srv *httptest.Server
+}
+
+func (t serverTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	target, _ := url.Parse(t.srv.URL)
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestProvider_SearchCache(t *testing.T) {
+	var searches atomic.Int32
+	gone := map[string]bool{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/youtube/v3/search":
+			searches.Add(1)
+			fmt.Fprint(w, `{"items":[
+				{"id":{"videoId":"v1"},"snippet":{"title":"Song","channelTitle":"Artist"}},
+				{"id":{"videoId":"v2"},"snippet":{"title":"Song (Live)","channelTitle":"Artist"}}]}`)
+		case "/oembed":
+			if gone[strings.TrimPrefix(r.URL.Query().Get("url"), "https://www.youtube.com/watch?v=")] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, `{"title":"Song"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: serverTransport{srv}}
+	p := NewProvider(client, WithSearchCache(memory.NewSearchCache(), time.Hour), WithOEmbedVerification())
+	track := domain.Track{Name: "Song", Artists: []string{"Artist"}}
+
+	search := func(track domain.Track) (string, bool) {
+		ctx, cached := domain.ContextWithCacheReport(context.Background())
+		matched, _, err := p.SearchTrack(ctx, "token", track)
+		require.NoError(t, err)
+		require.NotNil(t, matched)
+		return matched.ExternalID, cached()
+	}
+
+	id, cached := search(track)
+	assert.Equal(t, "v1", id)
+	assert.False(t, cached)
+
+	// Queries that normalize alike are answered from the cache.
+	id, cached = search(domain.Track{Name: "song", Artists: []string{"ARTIST"}})
+	assert.Equal(t, "v1", id)
+	assert.True(t, cached)
+	assert.EqualValues(t, 1, searches.Load())
+
+	// Videos gone since the search are dropped from the cached results.
+	gone["v1"] = true
+	id, cached = search(track)
+	assert.Equal(t, "v2", id)
+	assert.True(t, cached)
+
+	// If none are left, the search runs again.
+	gone["v2"] = true
+	_, cached = search(track)
+	assert.False(t, cached)
+	assert.EqualValues(t, 2, searches.Load())
+}
+
+func TestProvider_SearchBlocklist(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items":[
+			{"id":{"videoId":"nc"},"snippet":{"title":"Daft Punk - One More Time (Nightcore)","channelTitle":"NightcoreReality"}},
+			{"id":{"videoId":"8d"},"snippet":{"title":"Daft Punk - One More Time","channelTitle":"8D Audio Tunes"}},
+			{"id":{"videoId":"kk"},"snippet":{"title":"One More Time (Karaoke Version)","channelTitle":"Sing King"}},
+			{"id":{"videoId":"official"},"snippet":{"title":"Daft Punk - One More Time (Official Video)","channelTitle":"Daft Punk"}}]}`)
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: serverTransport{srv}}
+	track := domain.Track{Name: "One More Time", Artists: []string{"Daft Punk"}}
+
+	candidates, err := NewProvider(client).SearchTrackCandidates(context.Background(), "token", track)
+	require.NoError(t, err)
+	require.Len(t, candidates, 4)
+	assert.Equal(t, "official", candidates[0].Track.ExternalID, "penalized candidates rank last")
+	assert.Equal(t, "nc", candidates[1].Track.ExternalID)
+	assert.Less(t, candidates[2].ConfidenceScore, candidates[0].ConfidenceScore)
+
+	matched, _, err := NewProvider(client).SearchTrack(context.Background(), "token", track)
+	require.NoError(t, err)
+	assert.Equal(t, "official", matched.ExternalID)
+
+	candidates, err = NewProvider(client, WithBlocklist([]string{"karaoke"}, BlockReject)).SearchTrackCandidates(context.Background(), "token", track)
+	require.NoError(t, err)
+	assert.Len(t, candidates, 3, "only the karaoke version is dropped")
+
+	karaoke := domain.Track{Name: "One More Time (Karaoke Version)", Artists: []string{"Sing King"}}
+	candidates, err = NewProvider(client, WithBlocklist([]string{"karaoke"}, BlockReject)).SearchTrackCandidates(context.Background(), "token", karaoke)
+	require.NoError(t, err)
+	assert.Len(t, candidates, 4, "terms the source track has do not block")
+
+	matched, _, err = NewProvider(client, WithBlocklist(nil, "")).SearchTrack(context.Background(), "token", track)
+	require.NoError(t, err)
+	assert.Equal(t, "nc", matched.ExternalID, "without a blocklist, ties keep YouTube's ranking")
+}
+
+func TestProvider_SearchRanksByScore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items":[
+			{"id":{"videoId":"cover"},"snippet":{"title":"Around the World (Piano Cover)","channelTitle":"Piano Tribute"}},
+			{"id":{"videoId":"other"},"snippet":{"title":"Harder Better Faster Stronger","channelTitle":"Daft Punk"}},
+			{"id":{"videoId":"official"},"snippet":{"title":"Daft Punk - Around the World (Official Audio)","channelTitle":"Daft Punk"}}]}`)
+	}))
+	defer srv.Close()
+	p := NewProvider(&http.Client{Transport: serverTransport{srv}}, WithBlocklist(nil, ""))
+	track := domain.Track{Name: "Around the World", Artists: []string{"Daft Punk"}}
+
+	ctx, runnerUps := domain.ContextWithCandidateReport(context.Background())
+	matched, score, err := p.SearchTrack(ctx, "token", track)
+	require.NoError(t, err)
+	assert.Equal(t, "official", matched.ExternalID, "the best-scored result wins over the top-ranked one")
+
+	reported := runnerUps()
+	require.Len(t, reported, 2)
+	assert.ElementsMatch(t, []string{"cover", "other"}, []string{reported[0].Track.ExternalID, reported[1].Track.ExternalID})
+	assert.Greater(t, score, reported[0].ConfidenceScore)
+	assert.GreaterOrEqual(t, reported[0].ConfidenceScore, reported[1].ConfidenceScore)
+}
+
+// cancelingTransport cancels a context once it has returned the response
+// to the request numbered after.
+type cancelingTransport struct {
+	base     http.RoundTripper
+	after    int
+	cancel   context.CancelFunc
+	requests int
+}
+
+func (t *cancelingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(r)
+	if t.requests++; t.requests == t.after {
+		t.cancel()
+	}
+	return resp, err
+}
+
+func TestProvider_AddVideosCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	transport := &cancelingTransport{base: serverTransport{srv}, after: 2, cancel: cancel}
+	p := NewProvider(&http.Client{Transport: transport}, WithAddInterval(10*time.Millisecond))
+	started := time.Now()
+	outcomes, err := p.AddTracksToPlaylist(ctx, "token", "pl", []string{"v1", "v2", "v3", "v4"})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "stopped after 2 of 4")
+	assert.Equal(t, 2, transport.requests)
+	require.Len(t, outcomes, 4)
+	assert.True(t, outcomes[1].Added)
+	assert.False(t, outcomes[2].Added)
+	assert.GreaterOrEqual(t, time.Since(started), 10*time.Millisecond, "inserts are paced")
+}
@@ -0,0 +1,366 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// stageSkipped is returned by an account stage that one of the providers
+// does not support. It tells why.
+type stageSkipped string
+
+func (s stageSkipped) Error() string { return string(s) }
+
+// accountRun is an account job being run.
+type accountRun struct {
+	service *Service
+	req     domain.MigrationRequest
+	source  ports.MusicProvider
+	dest    ports.MusicProvider
+}
+
+// runAccountMigration runs the stages of an account job in the order of
+// domain.AccountStages and reports them in the job's progress. Stages one of
+// the providers does not support are skipped. A stage that fails does not
+// stop the ones after it, but fails the job.
+func (j *JobService) runAccountMigration(ctx context.Context, job *domain.Job, progress *accountProgress) error {
+	s := j.service
+	run := &accountRun{service: s, req: job.Request}
+	var err error
+	if run.source, err = s.registry.Get(run.req.SourceProvider); err != nil {
+		return fmt.Errorf("source provider error: %w", err)
+	}
+	if run.dest, err = s.registry.Get(run.req.DestProvider); err != nil {
+		return fmt.Errorf("destination provider error: %w", err)
+	}
+	if run.req.SourceToken, err = s.resolveToken(ctx, run.req.SourceProvider, run.req.SourceToken); err != nil {
+		return err
+	}
+	if run.req.DestToken, err = s.resolveToken(ctx, run.req.DestProvider, run.req.DestToken); err != nil {
+		return err
+	}
+	if run.req.Market != "" {
+		run.req.Market = strings.ToUpper(run.req.Market)
+		ctx = domain.ContextWithMarket(ctx, run.req.Market)
+	}
+	ctx = domain.ContextWithMatchOptions(ctx, run.req.MatchOptions())
+
+	stages := map[domain.AccountStage]func(context.Context, *accountStage) error{
+		domain.AccountStageLibrary:   run.migrateLikedTracks,
+		domain.AccountStageAlbums:    run.migrateAlbums,
+		domain.AccountStageArtists:   run.migrateArtists,
+		domain.AccountStagePlaylists: run.migratePlaylists,
+	}
+	var failed []string
+	for i, name := range domain.AccountStages {
+		stage := &accountStage{progress: progress, index: i}
+		stage.start(ctx)
+		err := stages[name](ctx, stage)
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+		var skipped stageSkipped
+		switch {
+		case errors.As(err, &skipped):
+			stage.finish(ctx, domain.AccountStageSkipped, err)
+		case err != nil:
+			stage.finish(ctx, domain.AccountStageFailed, err)
+			failed = append(failed, fmt.Sprintf("%s stage failed: %v", name, err))
+		default:
+			stage.finish(ctx, domain.AccountStageSucceeded, nil)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// library returns the source as a library reader and the destination as a
+// library writer, or a stageSkipped error if either is not.
+func (r *accountRun) library() (ports.LibraryReader, ports.LibraryWriter, error) {
+	reader, ok := r.source.(ports.LibraryReader)
+	if !ok {
+		return nil, nil, stageSkipped(fmt.Sprintf("%s has no library to read", r.req.SourceProvider))
+	}
+	writer, ok := r.dest.(ports.LibraryWriter)
+	if !ok {
+		return nil, nil, stageSkipped(fmt.Sprintf("%s has no library to write", r.req.DestProvider))
+	}
+	return reader, writer, nil
+}
+
+// migrateLikedTracks searches the liked songs of the source on the
+// destination and likes the matches there.
+func (r *accountRun) migrateLikedTracks(ctx context.Context, stage *accountStage) error {
+	reader, writer, err := r.library()
+	if err != nil {
+		return err
+	}
+	tracks, err := reader.GetLikedTracks(ctx, r.req.SourceToken)
+	if err != nil {
+		return fmt.Errorf("source provider error: %w", err)
+	}
+	stage.begin(ctx, len(tracks))
+
+	results, _ := r.service.searchTracksParallel(withSearchListener(ctx, stage), r.dest, r.req.DestToken, tracks)
+	// Liked songs are listed most recently liked first; liking the oldest
+	// first keeps their order on the destination.
+	var ids []string
+	for _, tr := range slices.Backward(results) {
+		if tr.Status == domain.TrackStatusMatched {
+			ids = append(ids, tr.MatchedTrack.ExternalID)
+		}
+	}
+	if r.req.DryRun || len(ids) == 0 {
+		return nil
+	}
+	if err := writer.SaveTracks(ctx, r.req.DestToken, ids); err != nil {
+		return fmt.Errorf("destination provider error: %w", err)
+	}
+	return nil
+}
+
+// migrateAlbums searches the saved albums of the source on the destination
+// and saves the matches there.
+func (r *accountRun) migrateAlbums(ctx context.Context, stage *accountStage) error {
+	reader, writer, err := r.library()
+	if err != nil {
+		return err
+	}
+	albums, err := reader.GetSavedAlbums(ctx, r.req.SourceToken)
+	if err != nil {
+		return fmt.Errorf("source provider error: %w", err)
+	}
+	ids, err := findAll(ctx, stage, albums, func(ctx context.Context, album domain.Album) (string, error) {
+		found, err := writer.SearchAlbum(ctx, r.req.DestToken, album)
+		if found == nil || err != nil {
+			return "", err
+		}
+		return found.ID, nil
+	})
+	if err != nil || r.req.DryRun || len(ids) == 0 {
+		return err
+	}
+	if err := writer.SaveAlbums(ctx, r.req.DestToken, ids); err != nil {
+		return fmt.Errorf("destination provider error: %w", err)
+	}
+	return nil
+}
+
+// migrateArtists searches the followed artists of the source on the
+// destination and follows the matches there.
+func (r *accountRun) migrateArtists(ctx context.Context, stage *accountStage) error {
+	reader, writer, err := r.library()
+	if err != nil {
+		return err
+	}
+	artists, err := reader.GetFollowedArtists(ctx, r.req.SourceToken)
+	if err != nil {
+		return fmt.Errorf("source provider error: %w", err)
+	}
+	ids, err := findAll(ctx, stage, artists, func(ctx context.Context, artist domain.Artist) (string, error) {
+		found, err := writer.SearchArtist(ctx, r.req.DestToken, artist)
+		if found == nil || err != nil {
+			return "", err
+		}
+		return found.ID, nil
+	})
+	if err != nil || r.req.DryRun || len(ids) == 0 {
+		return err
+	}
+	if err := writer.FollowArtists(ctx, r.req.DestToken, ids); err != nil {
+		return fmt.Errorf("destination provider error: %w", err)
+	}
+	return nil
+}
+
+// findAll looks up each item on the destination with find, which returns
+// the destination ID of the item or "" if it is not there, and returns the
+// IDs found. The stage counts the items as they are looked up.
+func findAll[T any](ctx context.Context, stage *accountStage, items []T, find func(context.Context, T) (string, error)) ([]string, error) {
+	stage.begin(ctx, len(items))
+	var ids []string
+	for _, item := range items {
+		id, err := find(ctx, item)
+		if err != nil {
+			return nil, fmt.Errorf("destination provider error: %w", err)
+		}
+		if id != "" {
+			ids = append(ids, id)
+		}
+		stage.done(ctx, id != "")
+	}
+	return ids, nil
+}
+
+// migratePlaylists migrates every playlist of the source, one after the
+// other, with the options of the job. Each playlist is keyed with the job's
+// idempotency key, so a job run again after its worker died returns the
+// migrations the first run stored instead of migrating them twice. A
+// playlist that fails does not stop the others, unless the account reached
+// its daily limit of migrations.
+func (r *accountRun) migratePlaylists(ctx context.Context, stage *accountStage) error {
+	playlists, err := r.source.GetPlaylists(ctx, r.req.SourceToken)
+	if err != nil {
+		return fmt.Errorf("source provider error: %w", err)
+	}
+	stage.begin(ctx, len(playlists))
+
+	var errs []string
+	for _, p := range playlists {
+		req := r.req
+		req.PlaylistID = p.ID
+		req.IdempotencyKey = r.req.IdempotencyKey + "/" + p.ID
+		result, err := r.service.MigratePlaylist(ctx, req)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, domain.ErrLimitExceeded) {
+			return fmt.Errorf("playlist %q: %w", p.Name, err)
+		}
+		if err != nil {
+			log.Printf("[jobs] failed to migrate playlist %s of account job: %v", p.ID, err)
+			errs = append(errs, fmt.Sprintf("playlist %q: %v", p.Name, err))
+			stage.done(ctx, false)
+			continue
+		}
+		stage.migrated(result.ID)
+		stage.done(ctx, true)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d playlists failed: %s", len(errs), len(playlists), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// accountProgress reports the stages of an account job: it publishes a
+// progress event per item done and saves the progress to the queue, so the
+// job status reports it on every instance.
+type accountProgress struct {
+	job    *domain.Job
+	queue  ports.JobQueue
+	events *jobEvents
+	now    func() time.Time
+
+	saved    time.Time
+	progress domain.JobProgress
+}
+
+func newAccountProgress(job *domain.Job, queue ports.JobQueue, events *jobEvents) *accountProgress {
+	p := &accountProgress{job: job, queue: queue, events: events, now: time.Now}
+	for _, stage := range domain.AccountStages {
+		p.progress.Stages = append(p.progress.Stages, domain.AccountStageProgress{Stage: stage, Status: domain.AccountStagePending})
+	}
+	return p
+}
+
+// snapshot returns a copy of the progress that later updates do not change.
+func (p *accountProgress) snapshot() *domain.JobProgress {
+	progress := p.progress
+	progress.Stages = slices.Clone(progress.Stages)
+	for i := range progress.Stages {
+		progress.Stages[i].MigrationIDs = slices.Clone(progress.Stages[i].MigrationIDs)
+	}
+	return &progress
+}
+
+// update adds up the counts of the stages, publishes the progress and saves
+// it to the queue if force is set or the last save is at least
+// jobProgressInterval ago. Each stage weighs the same in the percentage.
+func (p *accountProgress) update(ctx context.Context, force bool) {
+	progress := &p.progress
+	progress.Processed, progress.Total, progress.Matched, progress.Failed = 0, 0, 0, 0
+	done := 0.0
+	for _, stage := range progress.Stages {
+		progress.Processed += stage.Processed
+		progress.Total += stage.Total
+		progress.Matched += stage.Matched
+		progress.Failed += stage.Failed
+		switch {
+		case stage.Status == domain.AccountStagePending:
+		case stage.Status != domain.AccountStageRunning:
+			done++
+		case stage.Total > 0:
+			done += float64(stage.Processed) / float64(stage.Total)
+		}
+	}
+	progress.Percent = done * 100 / float64(len(progress.Stages))
+
+	p.events.publish(p.job.ID, domain.JobEvent{Type: domain.JobEventProgress, Progress: p.snapshot()})
+
+	now := p.now()
+	if !force && now.Sub(p.saved) < jobProgressInterval {
+		return
+	}
+	p.saved = now
+	err := p.queue.SaveProgress(ctx, p.job.ID, p.job.LeaseOwner, *p.snapshot())
+	if err != nil && !errors.Is(err, domain.ErrJobLeaseLost) && ctx.Err() == nil {
+		log.Printf("[jobs] failed to save progress of job %s: %v", p.job.ID, err)
+	}
+}
+
+// accountStage reports one stage of an account job. It follows the search
+// pass of the liked songs.
+type accountStage struct {
+	progress *accountProgress
+	index    int
+}
+
+func (s *accountStage) stage() *domain.AccountStageProgress {
+	return &s.progress.progress.Stages[s.index]
+}
+
+func (s *accountStage) start(ctx context.Context) {
+	s.stage().Status = domain.AccountStageRunning
+	s.progress.update(ctx, true)
+}
+
+// begin sets the number of items of the stage.
+func (s *accountStage) begin(ctx context.Context, total int) {
+	s.stage().Total = total
+	s.progress.update(ctx, true)
+}
+
+// done counts an item, found on the destination or not.
+func (s *accountStage) done(ctx context.Context, matched bool) {
+	stage := s.stage()
+	stage.Processed++
+	if matched {
+		stage.Matched++
+	} else {
+		stage.Failed++
+	}
+	s.progress.update(ctx, false)
+}
+
+// migrated records the migration of a playlist.
+func (s *accountStage) migrated(id string) {
+	stage := s.stage()
+	stage.MigrationIDs = append(stage.MigrationIDs, id)
+}
+
+func (s *accountStage) finish(ctx context.Context, status domain.AccountStageStatus, err error) {
+	stage := s.stage()
+	stage.Status = status
+	if err != nil {
+		stage.Error = err.Error()
+	}
+	s.progress.update(ctx, true)
+}
+
+func (s *accountStage) searchStarted(ctx context.Context, total int) {
+	s.begin(ctx, total)
+}
+
+func (s *accountStage) trackSearched(ctx context.Context, result domain.TrackResult, _, _ int) {
+	s.done(ctx, result.Status == domain.TrackStatusMatched)
+}
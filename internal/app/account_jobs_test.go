@@ -0,0 +1,173 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accountProvider is a mockProvider with a library to read and write.
+type accountProvider struct {
+	*mockProvider
+	liked   []domain.Track
+	albums  []domain.Album
+	artists []domain.Artist
+
+	// foundAlbums and foundArtists map names to the IDs searches find.
+	foundAlbums  map[string]string
+	foundArtists map[string]string
+
+	savedTracks     []string
+	savedAlbums     []string
+	followedArtists []string
+}
+
+func (l *accountProvider) GetLikedTracks(context.Context, string) ([]domain.Track, error) {
+	return l.liked, nil
+}
+
+func (l *accountProvider) GetSavedAlbums(context.Context, string) ([]domain.Album, error) {
+	return l.albums, nil
+}
+
+func (l *accountProvider) GetSavedShows(context.Context, string) ([]domain.Show, error) {
+	return nil, nil
+}
+
+func (l *accountProvider) GetFollowedArtists(context.Context, string) ([]domain.Artist, error) {
+	return l.artists, nil
+}
+
+func (l *accountProvider) SaveTracks(_ context.Context, _ string, trackIDs []string) error {
+	l.savedTracks = append(l.savedTracks, trackIDs...)
+	return nil
+}
+
+func (l *accountProvider) SearchAlbum(_ context.Context, _ string, album domain.Album) (*domain.Album, error) {
+	if id, ok := l.foundAlbums[album.Name]; ok {
+		return &domain.Album{ID: id, Name: album.Name}, nil
+	}
+	return nil, nil
+}
+
+func (l *accountProvider) SaveAlbums(_ context.Context, _ string, albumIDs []string) error {
+	l.savedAlbums = append(l.savedAlbums, albumIDs...)
+	return nil
+}
+
+func (l *accountProvider) SearchArtist(_ context.Context, _ string, artist domain.Artist) (*domain.Artist, error) {
+	if id, ok := l.foundArtists[artist.Name]; ok {
+		return &domain.Artist{ID: id, Name: artist.Name}, nil
+	}
+	return nil, nil
+}
+
+func (l *accountProvider) FollowArtists(_ context.Context, _ string, artistIDs []string) error {
+	l.followedArtists = append(l.followedArtists, artistIDs...)
+	return nil
+}
+
+var accountRequest = domain.AccountMigrationRequest{
+	SourceProvider: "source", SourceToken: "t1", DestProvider: "dest", DestToken: "t2", IdempotencyKey: "key",
+}
+
+func TestJobService_EnqueueAccountMigration(t *testing.T) {
+	queue := memory.NewJobQueue()
+	jobs := newTestJobService(queue)
+	source, err := jobs.service.registry.Get("source")
+	require.NoError(t, err)
+	source.(*mockProvider).playlists = []domain.Playlist{{ID: "pl-1", Name: "Road Trip"}, {ID: "pl-2", Name: "Focus"}}
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc"})
+
+	req := accountRequest
+	req.Classical = true
+	job, err := jobs.EnqueueAccountMigration(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobAccount, job.Kind)
+	assert.Equal(t, domain.JobBulk, job.Priority)
+	assert.True(t, job.Request.Classical, "the options apply to every playlist")
+	assert.Empty(t, job.Request.PlaylistID)
+
+	again, err := jobs.EnqueueAccountMigration(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, again.ID, "a repeated key returns the queued job")
+	active, err := queue.CountActive(ctx, "acc")
+	require.NoError(t, err)
+	assert.Equal(t, 1, active, "an account migration is one job")
+
+	runCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go jobs.Run(runCtx, 1)
+	done := waitForJob(t, jobs, ctx, job.ID)
+	require.Equal(t, domain.JobSucceeded, done.Status, done.Error)
+	require.NotNil(t, done.Progress)
+	require.Len(t, done.Progress.Stages, len(domain.AccountStages))
+	for _, stage := range done.Progress.Stages[:3] {
+		assert.Equal(t, domain.AccountStageSkipped, stage.Status, "the mock provider has no library")
+		assert.NotEmpty(t, stage.Error)
+	}
+	playlists := done.Progress.Stages[3]
+	assert.Equal(t, domain.AccountStagePlaylists, playlists.Stage)
+	assert.Equal(t, domain.AccountStageSucceeded, playlists.Status)
+	assert.Equal(t, 2, playlists.Matched)
+	assert.Len(t, playlists.MigrationIDs, 2)
+	assert.Equal(t, float64(100), done.Progress.Percent)
+
+	req.IdempotencyKey = ""
+	req.DestProvider = "unknown"
+	_, err = jobs.EnqueueAccountMigration(ctx, req)
+	assert.ErrorIs(t, err, domain.ErrProviderNotFound)
+}
+
+func TestJobService_AccountMigrationCopiesLibrary(t *testing.T) {
+	source := &accountProvider{
+		mockProvider: &mockProvider{name: "source"},
+		liked: []domain.Track{
+			{Name: "Track B", Artists: []string{"Artist B"}},
+			{Name: "Track A", Artists: []string{"Artist A"}},
+			{Name: "Track C", Artists: []string{"Artist C"}},
+		},
+		albums:  []domain.Album{{Name: "Album A"}, {Name: "Album B"}},
+		artists: []domain.Artist{{Name: "Artist A"}},
+	}
+	dest := &accountProvider{
+		mockProvider: &mockProvider{name: "dest", searchResults: map[string]*searchResult{
+			"Track A|Artist A": {track: &domain.Track{Name: "Track A", ExternalID: "a"}, score: 0.9},
+			"Track B|Artist B": {track: &domain.Track{Name: "Track B", ExternalID: "b"}, score: 0.9},
+		}},
+		foundAlbums:  map[string]string{"Album B": "album-b"},
+		foundArtists: map[string]string{"Artist A": "artist-a"},
+	}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+	jobs := NewJobService(newService(registry, 1), memory.NewJobQueue())
+	ctx := context.Background()
+
+	job, err := jobs.EnqueueAccountMigration(ctx, accountRequest)
+	require.NoError(t, err)
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go jobs.Run(runCtx, 1)
+	done := waitForJob(t, jobs, ctx, job.ID)
+	require.Equal(t, domain.JobSucceeded, done.Status, done.Error)
+
+	assert.Equal(t, []string{"a", "b"}, dest.savedTracks, "the oldest liked song is liked first")
+	assert.Equal(t, []string{"album-b"}, dest.savedAlbums)
+	assert.Equal(t, []string{"artist-a"}, dest.followedArtists)
+
+	library := done.Progress.Stages[0]
+	assert.Equal(t, domain.AccountStageSucceeded, library.Status)
+	assert.Equal(t, 3, library.Total)
+	assert.Equal(t, 2, library.Matched)
+	assert.Equal(t, 1, library.Failed)
+	albums := done.Progress.Stages[1]
+	assert.Equal(t, 2, albums.Processed)
+	assert.Equal(t, 1, albums.Matched)
+	assert.Equal(t, domain.AccountStageSucceeded, done.Progress.Stages[3].Status, "a source without playlists migrates none")
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// apiKeyPrefix makes keys recognizable in logs and secret scanners.
+const apiKeyPrefix = "mm_"
+
+// AccountService implements ports.AccountService. API keys are only kept as
+// SHA-256 hashes; the plain-text key is returned once on registration.
+type AccountService struct {
+	store ports.AccountStore
+}
+
+// NewAccountService creates a new account service backed by the given store.
+func NewAccountService(store ports.AccountStore) *AccountService {
+	return &AccountService{store: store}
+}
+
+func (s *AccountService) Register(ctx context.Context, name string) (*domain.Account, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", domain.ErrInvalidAccountName
+	}
+
+	key := make([]byte, 32)
+	_, _ = rand.Read(key) // crypto/rand.Read never returns an error
+	apiKey := apiKeyPrefix + hex.EncodeToString(key)
+
+	account := &domain.Account{
+		ID:         newID(),
+		Name:       name,
+		CreatedAt:  time.Now().UTC(),
+		APIKeyHash: hashAPIKey(apiKey),
+	}
+	if err := s.store.Create(ctx, account); err != nil {
+		return nil, "", fmt.Errorf("failed to create account: %w", err)
+	}
+
+	return account, apiKey, nil
+}
+
+func (s *AccountService) Authenticate(ctx context.Context, apiKey string) (*domain.Account, error) {
+	if !strings.HasPrefix(apiKey, apiKeyPrefix) {
+		return nil, domain.ErrAccountNotFound
+	}
+	return s.store.GetByAPIKeyHash(ctx, hashAPIKey(apiKey))
+}
+
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountService_RegisterAndAuthenticate(t *testing.T) {
+	svc := NewAccountService(memory.NewAccountStore())
+
+	account, apiKey, err := svc.Register(context.Background(), "my-app")
+	require.NoError(t, err)
+	assert.Equal(t, "my-app", account.Name)
+	assert.NotEmpty(t, account.ID)
+	assert.NotContains(t, account.APIKeyHash, apiKey)
+
+	authed, err := svc.Authenticate(context.Background(), apiKey)
+	require.NoError(t, err)
+	assert.Equal(t, account.ID, authed.ID)
+}
+
+func TestAccountService_AuthenticateInvalidKey(t *testing.T) {
+	svc := NewAccountService(memory.NewAccountStore())
+
+	_, err := svc.Authenticate(context.Background(), "mm_does-not-exist")
+	require.ErrorIs(t, err, domain.ErrAccountNotFound)
+}
+
+func TestAccountService_RegisterRequiresName(t *testing.T) {
+	svc := NewAccountService(memory.NewAccountStore())
+
+	_, _, err := svc.Register(context.Background(), "  ")
+	require.ErrorIs(t, err, domain.ErrInvalidAccountName)
+}
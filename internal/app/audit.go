@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// WithAuditLog records every playlist write the Service makes to a
+// provider, successful or not, in auditLog.
+func WithAuditLog(auditLog ports.AuditLog) Option {
+	return func(s *Service) {
+		s.audit = auditLog
+	}
+}
+
+// The methods below make a playlist write through provider and record it in
+// the audit log. Every write of the Service goes through them.
+
+func (s *Service) createPlaylist(ctx context.Context, provider ports.MusicProvider, token string, name string, description string) (string, error) {
+	started := time.Now()
+	playlistID, err := provider.CreatePlaylist(ctx, token, name, description)
+	s.recordWrite(ctx, provider, domain.AuditCreatePlaylist, playlistID, nil, started, err)
+	return playlistID, err
+}
+
+func (s *Service) addTracks(ctx context.Context, provider ports.MusicProvider, token string, playlistID string, trackIDs []string) ([]domain.AddOutcome, error) {
+	started := time.Now()
+	outcomes, err := provider.AddTracksToPlaylist(ctx, token, playlistID, trackIDs)
+	s.recordWrite(ctx, provider, domain.AuditAddTracks, playlistID, trackIDs, started, err)
+	return outcomes, err
+}
+
+// insertTracks is addTracks for providers implementing
+// ports.PositionalAdder.
+func (s *Service) insertTracks(ctx context.Context, provider ports.MusicProvider, token string, playlistID string, position int, trackIDs []string) ([]domain.AddOutcome, error) {
+	started := time.Now()
+	outcomes, err := provider.(ports.PositionalAdder).InsertTracksAt(ctx, token, playlistID, position, trackIDs)
+	s.recordWrite(ctx, provider, domain.AuditAddTracks, playlistID, trackIDs, started, err)
+	return outcomes, err
+}
+
+func (s *Service) removeTracks(ctx context.Context, provider ports.MusicProvider, token string, playlistID string, trackIDs []string) error {
+	started := time.Now()
+	err := provider.RemoveTracksFromPlaylist(ctx, token, playlistID, trackIDs)
+	s.recordWrite(ctx, provider, domain.AuditRemoveTracks, playlistID, trackIDs, started, err)
+	return err
+}
+
+func (s *Service) updatePlaylist(ctx context.Context, provider ports.MusicProvider, token string, playlistID string, update domain.PlaylistUpdate) error {
+	started := time.Now()
+	err := provider.UpdatePlaylistDetails(ctx, token, playlistID, update)
+	s.recordWrite(ctx, provider, domain.AuditUpdatePlaylist, playlistID, nil, started, err)
+	return err
+}
+
+func (s *Service) deletePlaylist(ctx context.Context, provider ports.MusicProvider, token string, playlistID string) error {
+	started := time.Now()
+	err := provider.DeletePlaylist(ctx, token, playlistID)
+	s.recordWrite(ctx, provider, domain.AuditDeletePlaylist, playlistID, nil, started, err)
+	return err
+}
+
+// recordWrite appends a write that started at started and returned err to
+// the audit log, if the Service has one. The write has been made by then,
+// so a failure to record it is logged rather than returned, and a canceled
+// ctx does not prevent recording.
+func (s *Service) recordWrite(ctx context.Context, provider ports.MusicProvider, action domain.AuditAction, playlistID string, trackIDs []string, started time.Time, err error) {
+	if s.audit == nil {
+		return
+	}
+	entry := &domain.AuditEntry{
+		ID:         newID(),
+		AccountID:  domain.AccountIDFromContext(ctx),
+		Provider:   provider.Name(),
+		Action:     action,
+		PlaylistID: playlistID,
+		TrackIDs:   trackIDs,
+		StartedAt:  started.UTC(),
+		FinishedAt: time.Now().UTC(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if err := s.audit.Append(context.WithoutCancel(ctx), entry); err != nil {
+		log.Printf("[audit] failed to record %s on %s playlist %s: %v", action, entry.Provider, playlistID, err)
+	}
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RecordsWritesInAuditLog(t *testing.T) {
+	auditLog := memory.NewAuditLog()
+	svc := newHookService()
+	WithAuditLog(auditLog)(svc)
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc"})
+
+	result, err := svc.MigratePlaylist(ctx, hookRequest)
+	require.NoError(t, err)
+	_, err = svc.RollbackMigration(ctx, result.ID, "t2")
+	require.NoError(t, err)
+
+	entries, err := auditLog.Query(ctx, domain.AuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	// Most recent first.
+	actions := []domain.AuditAction{entries[2].Action, entries[1].Action, entries[0].Action}
+	assert.Equal(t, []domain.AuditAction{domain.AuditCreatePlaylist, domain.AuditAddTracks, domain.AuditDeletePlaylist}, actions)
+	for _, entry := range entries {
+		assert.Equal(t, "acc", entry.AccountID)
+		assert.Equal(t, "dest", entry.Provider)
+		assert.Equal(t, "dest-pl", entry.PlaylistID)
+		assert.Empty(t, entry.Error)
+		assert.False(t, entry.FinishedAt.Before(entry.StartedAt))
+	}
+	assert.Equal(t, []string{"a"}, entries[1].TrackIDs)
+}
+
+func TestService_DryRunWritesNothingToAuditLog(t *testing.T) {
+	auditLog := memory.NewAuditLog()
+	svc := newHookService()
+	WithAuditLog(auditLog)(svc)
+
+	req := hookRequest
+	req.DryRun = true
+	_, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+
+	entries, err := auditLog.Query(context.Background(), domain.AuditQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// CompareMigrations diffs two stored migrations of the caller's account.
+// Both must have migrated the same source playlist to the same destination
+// provider, or domain.ErrMigrationsNotComparable is returned.
+func (s *Service) CompareMigrations(ctx context.Context, a, b string) (*domain.MigrationComparison, error) {
+	resultA, err := s.getOwnedMigration(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	resultB, err := s.getOwnedMigration(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	if resultA.SourceProvider != resultB.SourceProvider || resultA.SourcePlaylist != resultB.SourcePlaylist ||
+		resultA.DestProvider != resultB.DestProvider {
+		return nil, fmt.Errorf("%w: %s migrated %s playlist %q to %s, %s migrated %s playlist %q to %s",
+			domain.ErrMigrationsNotComparable,
+			resultA.ID, resultA.SourceProvider, resultA.SourcePlaylist, resultA.DestProvider,
+			resultB.ID, resultB.SourceProvider, resultB.SourcePlaylist, resultB.DestProvider)
+	}
+	return compareResults(resultA, resultB), nil
+}
+
+// compareResults pairs the track results of a and b by their source track,
+// the way repeats are told apart when deduplicating, and lists the pairs
+// that differ in status or matched track in the order of a, followed by
+// the tracks only b has. Repeats of a source track are paired in order.
+func compareResults(a, b *domain.MigrationResult) *domain.MigrationComparison {
+	cmp := &domain.MigrationComparison{
+		A:              a.ID,
+		B:              b.ID,
+		SourceProvider: a.SourceProvider,
+		DestProvider:   a.DestProvider,
+		SourcePlaylist: a.SourcePlaylist,
+		Summary:        map[domain.TrackChangeKind]int{},
+		Changes:        []domain.TrackChange{},
+	}
+	add := func(kind domain.TrackChangeKind, source domain.Track, ra, rb *domain.TrackResult) {
+		cmp.Summary[kind]++
+		cmp.Changes = append(cmp.Changes, domain.TrackChange{Kind: kind, SourceTrack: source, A: ra, B: rb})
+	}
+
+	inB := make(map[string][]int)
+	for i, tr := range b.TrackResults {
+		key := dedupeKeys(tr.SourceTrack)[0]
+		inB[key] = append(inB[key], i)
+	}
+	paired := make([]bool, len(b.TrackResults))
+	for i := range a.TrackResults {
+		ra := &a.TrackResults[i]
+		key := dedupeKeys(ra.SourceTrack)[0]
+		if len(inB[key]) == 0 {
+			add(domain.TrackChangeRemoved, ra.SourceTrack, ra, nil)
+			continue
+		}
+		j := inB[key][0]
+		inB[key] = inB[key][1:]
+		paired[j] = true
+		rb := &b.TrackResults[j]
+		if kind, changed := trackChange(*ra, *rb); changed {
+			add(kind, ra.SourceTrack, ra, rb)
+		} else {
+			cmp.Unchanged++
+		}
+	}
+	for j := range b.TrackResults {
+		if !paired[j] {
+			add(domain.TrackChangeAdded, b.TrackResults[j].SourceTrack, nil, &b.TrackResults[j])
+		}
+	}
+	return cmp
+}
+
+// trackChange classifies how the result b of a source track differs from
+// its result a, if it does.
+func trackChange(a, b domain.TrackResult) (domain.TrackChangeKind, bool) {
+	matchedA, matchedB := matchedID(a), matchedID(b)
+	switch {
+	case matchedA == "" && matchedB != "":
+		return domain.TrackChangeMatched, true
+	case matchedA != "" && matchedB == "":
+		return domain.TrackChangeUnmatched, true
+	case matchedA != matchedB:
+		return domain.TrackChangeRematched, true
+	case a.Status != b.Status:
+		return domain.TrackChangeStatus, true
+	}
+	return "", false
+}
+
+// matchedID returns the ID of the destination track tr was matched to, or
+// "" if it was not matched.
+func matchedID(tr domain.TrackResult) string {
+	if tr.Status != domain.TrackStatusMatched || tr.MatchedTrack == nil {
+		return ""
+	}
+	return tr.MatchedTrack.ExternalID
+}
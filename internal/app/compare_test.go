@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func comparedMatch(id, destID string) domain.TrackResult {
+	return domain.TrackResult{
+		SourceTrack:  domain.Track{ExternalID: id},
+		Status:       domain.TrackStatusMatched,
+		MatchedTrack: &domain.Track{ExternalID: destID},
+	}
+}
+
+func comparedMiss(id string, status domain.TrackStatus) domain.TrackResult {
+	return domain.TrackResult{SourceTrack: domain.Track{ExternalID: id}, Status: status}
+}
+
+func TestCompareMigrations(t *testing.T) {
+	store := memory.NewMigrationStore()
+	ctx := context.Background()
+	require.NoError(t, store.Save(ctx, &domain.MigrationResult{
+		ID: "before", SourceProvider: "spotify", DestProvider: "youtube", SourcePlaylist: "pl-1",
+		TrackResults: []domain.TrackResult{
+			comparedMatch("s1", "d1"),
+			comparedMiss("s2", domain.TrackStatusNotFound),
+			comparedMatch("s3", "d3"),
+			comparedMatch("s4", "d4"),
+			comparedMiss("s5", domain.TrackStatusNotFound),
+			comparedMatch("s6", "d6"),
+			comparedMatch("s1", "d1"),
+		},
+	}))
+	require.NoError(t, store.Save(ctx, &domain.MigrationResult{
+		ID: "after", SourceProvider: "spotify", DestProvider: "youtube", SourcePlaylist: "pl-1",
+		TrackResults: []domain.TrackResult{
+			comparedMatch("s1", "d1"),
+			comparedMatch("s2", "d2"),
+			comparedMiss("s3", domain.TrackStatusNotFound),
+			comparedMatch("s4", "other"),
+			comparedMiss("s5", domain.TrackStatusError),
+			comparedMatch("s1", "d1-live"),
+			comparedMatch("s7", "d7"),
+		},
+	}))
+	svc := newService(adapters.NewProviderRegistry(), 1, WithMigrationStore(store))
+
+	cmp, err := svc.CompareMigrations(ctx, "before", "after")
+	require.NoError(t, err)
+	assert.Equal(t, "pl-1", cmp.SourcePlaylist)
+	assert.Equal(t, 1, cmp.Unchanged)
+	assert.Equal(t, map[domain.TrackChangeKind]int{
+		domain.TrackChangeMatched:   1,
+		domain.TrackChangeUnmatched: 1,
+		domain.TrackChangeRematched: 2,
+		domain.TrackChangeStatus:    1,
+		domain.TrackChangeRemoved:   1,
+		domain.TrackChangeAdded:     1,
+	}, cmp.Summary)
+
+	var kinds []domain.TrackChangeKind
+	var ids []string
+	for _, c := range cmp.Changes {
+		kinds = append(kinds, c.Kind)
+		ids = append(ids, c.SourceTrack.ExternalID)
+	}
+	assert.Equal(t, []domain.TrackChangeKind{
+		domain.TrackChangeMatched, domain.TrackChangeUnmatched, domain.TrackChangeRematched, domain.TrackChangeStatus,
+		domain.TrackChangeRemoved, domain.TrackChangeRematched, domain.TrackChangeAdded,
+	}, kinds)
+	assert.Equal(t, []string{"s2", "s3", "s4", "s5", "s6", "s1", "s7"}, ids, "repeats are paired in order")
+	assert.Nil(t, cmp.Changes[4].B)
+	assert.Nil(t, cmp.Changes[6].A)
+	assert.Equal(t, "d1-live", cmp.Changes[5].B.MatchedTrack.ExternalID)
+}
+
+func TestCompareMigrations_Errors(t *testing.T) {
+	store := memory.NewMigrationStore()
+	ctx := context.Background()
+	require.NoError(t, store.Save(ctx, &domain.MigrationResult{ID: "a", SourceProvider: "spotify", DestProvider: "youtube", SourcePlaylist: "pl-1"}))
+	require.NoError(t, store.Save(ctx, &domain.MigrationResult{ID: "b", SourceProvider: "spotify", DestProvider: "youtube", SourcePlaylist: "pl-2"}))
+	require.NoError(t, store.Save(ctx, &domain.MigrationResult{ID: "c", AccountID: "acc-2", SourceProvider: "spotify", DestProvider: "youtube", SourcePlaylist: "pl-1"}))
+	svc := newService(adapters.NewProviderRegistry(), 1, WithMigrationStore(store))
+
+	_, err := svc.CompareMigrations(ctx, "a", "b")
+	assert.ErrorIs(t, err, domain.ErrMigrationsNotComparable)
+	_, err = svc.CompareMigrations(ctx, "a", "c")
+	assert.ErrorIs(t, err, domain.ErrMigrationNotFound, "migrations of other accounts are not found")
+	_, err = svc.CompareMigrations(ctx, "missing", "a")
+	assert.ErrorIs(t, err, domain.ErrMigrationNotFound)
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"sync"
+)
+
+// adaptiveLimiter bounds concurrent calls to a single provider. It follows
+// AIMD: the limit halves whenever the provider rate limits a call and grows by
+// one after limit consecutive successes, up to max. It is safe for concurrent
+// use.
+type adaptiveLimiter struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	max       int
+	limit     int
+	inFlight  int
+	successes int
+}
+
+func newAdaptiveLimiter(max int) *adaptiveLimiter {
+	if max < 1 {
+		max = 1
+	}
+	l := &adaptiveLimiter{max: max, limit: max}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// Acquire blocks until a call may start under the current limit.
+func (l *adaptiveLimiter) Acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.inFlight >= l.limit {
+		l.cond.Wait()
+	}
+	l.inFlight++
+}
+
+// Release ends a call started with Acquire and adjusts the limit depending on
+// whether the provider rate limited it. It returns the new limit.
+func (l *adaptiveLimiter) Release(rateLimited bool) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	if rateLimited {
+		l.limit = max(1, l.limit/2)
+		l.successes = 0
+	} else if l.limit < l.max {
+		l.successes++
+		if l.successes >= l.limit {
+			l.limit++
+			l.successes = 0
+		}
+	}
+
+	l.cond.Broadcast()
+	return l.limit
+}
+
+// Limit returns the current concurrency limit.
+func (l *adaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
@@ -0,0 +1,179 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// rateLimitedProvider is a mockProvider that rate limits its first searches.
+type rateLimitedProvider struct {
+	*mockProvider
+	mu          sync.Mutex
+	rateLimited int
+	retryAfter  time.Duration
+}
+
+// retryAfterError carries a provider's Retry-After hint.
+type retryAfterError time.Duration
+
+func (e retryAfterError) Error() string             { return "retry later" }
+func (e retryAfterError) RetryAfter() time.Duration { return time.Duration(e) }
+
+func (p *rateLimitedProvider) SearchTrack(ctx context.Context, token string, track domain.Track) (*domain.Track, float64, error) {
+	p.mu.Lock()
+	if p.rateLimited > 0 {
+		p.rateLimited--
+		p.mu.Unlock()
+		return nil, 0, fmt.Errorf("dest: %w: %w", domain.ErrRateLimited, retryAfterError(p.retryAfter))
+	}
+	p.mu.Unlock()
+	return p.mockProvider.SearchTrack(ctx, token, track)
+}
+
+// -- adaptiveLimiter ---------------------------------------------------------
+
+func TestAdaptiveLimiter_HalvesAndRecovers(t *testing.T) {
+	l := newAdaptiveLimiter(8)
+	assert.Equal(t, 8, l.Limit())
+
+	l.Acquire()
+	assert.Equal(t, 4, l.Release(true))
+	l.Acquire()
+	assert.Equal(t, 2, l.Release(true))
+	l.Acquire()
+	assert.Equal(t, 1, l.Release(true))
+	l.Acquire()
+	assert.Equal(t, 1, l.Release(true), "limit never drops below one")
+
+	// Grows by one after limit consecutive successes.
+	l.Acquire()
+	assert.Equal(t, 2, l.Release(false))
+	l.Acquire()
+	assert.Equal(t, 2, l.Release(false))
+	l.Acquire()
+	assert.Equal(t, 3, l.Release(false))
+}
+
+func TestAdaptiveLimiter_NeverExceedsMax(t *testing.T) {
+	l := newAdaptiveLimiter(2)
+	for i := 0; i < 10; i++ {
+		l.Acquire()
+		l.Release(false)
+	}
+	assert.Equal(t, 2, l.Limit())
+}
+
+// -- Service -----------------------------------------------------------------
+
+func TestMigratePlaylist_BacksOffWhenRateLimited(t *testing.T) {
+	source := &mockProvider{
+		name:   "source",
+		tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+	}
+	dest := &rateLimitedProvider{
+		mockProvider: &mockProvider{
+			name:      "dest",
+			createdID: "pl",
+			searchResults: map[string]*searchResult{
+				"Track A|Artist A": {
+					track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+					score: 0.9,
+				},
+			},
+		},
+		rateLimited: 2,
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 4)
+	svc.rateLimitBackoff = 0
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.MatchedTracks)
+	require.NotNil(t, result.Concurrency)
+	assert.Equal(t, 4, result.Concurrency.Max)
+	assert.Equal(t, 1, result.Concurrency.Min)
+	assert.Equal(t, 2, result.Concurrency.RateLimited)
+	assert.Equal(t, 2, result.Concurrency.Final, "grows back after the successful search")
+	assert.Equal(t, 3, result.TrackResults[0].Attempts)
+	assert.Equal(t, 2, result.TrackResults[0].Retries)
+	assert.Equal(t, 2, svc.limiterFor("dest").Limit(), "reduced limit carries over to the next migration")
+}
+
+func TestMigratePlaylist_DefersSearchesThatStayRateLimited(t *testing.T) {
+	newDest := func(rateLimited int, retryAfter time.Duration) *rateLimitedProvider {
+		return &rateLimitedProvider{
+			mockProvider: &mockProvider{
+				name:      "dest",
+				createdID: "pl",
+				searchResults: map[string]*searchResult{
+					"Track A|Artist A": {
+						track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+						score: 0.9,
+					},
+				},
+			},
+			rateLimited: rateLimited,
+			retryAfter:  retryAfter,
+		}
+	}
+	migrate := func(dest *rateLimitedProvider) *domain.MigrationResult {
+		registry := adapters.NewProviderRegistry()
+		registry.Register(&mockProvider{
+			name:   "source",
+			tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+		})
+		registry.Register(dest)
+		svc := newService(registry, 2)
+		svc.rateLimitBackoff = 0
+		result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+			SourceProvider: "source",
+			SourceToken:    "t1",
+			DestProvider:   "dest",
+			DestToken:      "t2",
+			PlaylistID:     "pl-1",
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	start := time.Now()
+	result := migrate(newDest(4, 50*time.Millisecond))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond, "waits for the Retry-After window")
+	assert.Equal(t, 1, result.MatchedTracks)
+	assert.Equal(t, 1, result.Concurrency.Deferred)
+	tr := result.TrackResults[0]
+	assert.Equal(t, domain.TrackStatusMatched, tr.Status)
+	assert.Equal(t, 5, tr.Attempts)
+	assert.Equal(t, 1, tr.Deferrals)
+
+	result = migrate(newDest(100, 0))
+	assert.Equal(t, 0, result.MatchedTracks)
+	assert.Equal(t, maxRateLimitDeferrals, result.Concurrency.Deferred)
+	tr = result.TrackResults[0]
+	assert.Equal(t, domain.ErrorCodeRateLimited, tr.ErrorCode)
+	assert.Equal(t, maxRateLimitAttempts*(maxRateLimitDeferrals+1), tr.Attempts)
+
+	result = migrate(newDest(100, time.Hour))
+	assert.Equal(t, 0, result.Concurrency.Deferred, "windows beyond maxRetryAfter fail right away")
+	assert.Equal(t, maxRateLimitAttempts, result.TrackResults[0].Attempts)
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// ConnectionService implements ports.ConnectionService on the token vault.
+type ConnectionService struct {
+	registry *adapters.ProviderRegistry
+	tokens   ports.TokenVault
+	now      func() time.Time
+}
+
+// NewConnectionService creates a connection service for the tokens stored
+// in tokens, revoking them with the providers of registry.
+func NewConnectionService(registry *adapters.ProviderRegistry, tokens ports.TokenVault) *ConnectionService {
+	return &ConnectionService{registry: registry, tokens: tokens, now: time.Now}
+}
+
+// ListConnections reports a connection for every stored token. Tokens that
+// cannot be decrypted are skipped.
+func (s *ConnectionService) ListConnections(ctx context.Context) ([]domain.ProviderConnection, error) {
+	providers, err := s.tokens.ListProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	connections := make([]domain.ProviderConnection, 0, len(providers))
+	for _, provider := range providers {
+		token, err := s.tokens.GetToken(ctx, provider)
+		if err != nil {
+			log.Printf("[tokens] skipping %s connection: %v", provider, err)
+			continue
+		}
+		conn := domain.ProviderConnection{
+			Provider:  provider,
+			ExpiresAt: token.ExpiresAt,
+			Expired:   token.ExpiresAt != nil && !s.now().Before(*token.ExpiresAt),
+			Scopes:    token.Scopes,
+		}
+		if p, err := s.registry.Get(provider); err == nil && token.RefreshToken != "" {
+			_, conn.Refreshable = p.(ports.TokenRefresher)
+		}
+		connections = append(connections, conn)
+	}
+	return connections, nil
+}
+
+// RevokeConnection revokes the refresh token, which ends the whole grant,
+// or else the access token. If the provider fails to revoke it the token
+// is kept, so revoking can be retried; TokenVault.DeleteToken only forgets
+// it.
+func (s *ConnectionService) RevokeConnection(ctx context.Context, provider string) error {
+	token, err := s.tokens.GetToken(ctx, provider)
+	if err != nil {
+		return err
+	}
+
+	if p, err := s.registry.Get(provider); err == nil {
+		if revoker, ok := p.(ports.TokenRevoker); ok {
+			revoke := token.RefreshToken
+			if revoke == "" {
+				revoke = token.AccessToken
+			}
+			if err := revoker.RevokeToken(ctx, revoke); err != nil {
+				return fmt.Errorf("%w: %s: %v", domain.ErrRevocationFailed, provider, err)
+			}
+			log.Printf("[tokens] revoked %s token", provider)
+		}
+	}
+	return s.tokens.DeleteToken(ctx, provider)
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revokingProvider is a mockProvider that can refresh and revoke tokens.
+type revokingProvider struct {
+	*refreshingProvider
+	revoked []string
+}
+
+func (p *revokingProvider) RevokeToken(_ context.Context, token string) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.revoked = append(p.revoked, token)
+	return nil
+}
+
+func newConnectionService(t *testing.T, tokens map[string]domain.ProviderToken) (*ConnectionService, *revokingProvider, context.Context) {
+	t.Helper()
+	provider := &revokingProvider{refreshingProvider: &refreshingProvider{mockProvider: &mockProvider{name: "youtube"}}}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(provider)
+	registry.Register(&mockProvider{name: "spotify"})
+
+	vault, err := NewTokenVault(memory.NewTokenStore(), testKey)
+	require.NoError(t, err)
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-1"})
+	for name, token := range tokens {
+		require.NoError(t, vault.StoreToken(ctx, name, token))
+	}
+	return NewConnectionService(registry, vault), provider, ctx
+}
+
+func TestConnectionService_ListConnections(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	valid := time.Now().Add(time.Hour)
+	svc, _, ctx := newConnectionService(t, map[string]domain.ProviderToken{
+		"youtube": {AccessToken: "a", RefreshToken: "r", ExpiresAt: &expired, Scopes: []string{"youtube"}},
+		"spotify": {AccessToken: "b", RefreshToken: "r", ExpiresAt: &valid},
+	})
+
+	connections, err := svc.ListConnections(ctx)
+	require.NoError(t, err)
+	require.Len(t, connections, 2)
+
+	spotify, youtube := connections[0], connections[1]
+	assert.Equal(t, "spotify", spotify.Provider)
+	assert.False(t, spotify.Expired)
+	assert.False(t, spotify.Refreshable, "the provider cannot refresh tokens")
+	assert.Equal(t, "youtube", youtube.Provider)
+	assert.True(t, youtube.Expired)
+	assert.True(t, youtube.Refreshable)
+	assert.Equal(t, []string{"youtube"}, youtube.Scopes)
+
+	_, err = svc.ListConnections(context.Background())
+	assert.Error(t, err, "the vault requires an account")
+}
+
+func TestConnectionService_RevokeConnection(t *testing.T) {
+	svc, provider, ctx := newConnectionService(t, map[string]domain.ProviderToken{
+		"youtube": {AccessToken: "a", RefreshToken: "r"},
+		"spotify": {AccessToken: "b"},
+	})
+
+	provider.err = errors.New("unreachable")
+	err := svc.RevokeConnection(ctx, "youtube")
+	assert.ErrorIs(t, err, domain.ErrRevocationFailed)
+	assert.ErrorContains(t, err, "unreachable")
+	_, err = svc.tokens.GetToken(ctx, "youtube")
+	require.NoError(t, err, "the token is kept until it is revoked")
+
+	provider.err = nil
+	require.NoError(t, svc.RevokeConnection(ctx, "youtube"))
+	assert.Equal(t, []string{"r"}, provider.revoked)
+	_, err = svc.tokens.GetToken(ctx, "youtube")
+	assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+
+	// Providers that cannot revoke tokens only have them removed.
+	require.NoError(t, svc.RevokeConnection(ctx, "spotify"))
+	assert.ErrorIs(t, svc.RevokeConnection(ctx, "spotify"), domain.ErrTokenNotFound)
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// dedupeTracks returns tracks without the repeats of earlier tracks, and how
+// many it dropped. Tracks repeat one another if they share their ID or their
+// ISRC; tracks with neither, such as local files, if they share their name
+// and artists.
+func dedupeTracks(tracks []domain.Track) ([]domain.Track, int) {
+	return make(deduper, len(tracks)).dedupe(tracks)
+}
+
+// deduper holds the keys of the tracks seen so far, so the pages of a
+// streamed playlist can be deduplicated one at a time.
+type deduper map[string]bool
+
+// dedupe is dedupeTracks for tracks that follow those seen before.
+func (seen deduper) dedupe(tracks []domain.Track) ([]domain.Track, int) {
+	kept := make([]domain.Track, 0, len(tracks))
+	for _, track := range tracks {
+		keys := dedupeKeys(track)
+		if slices.ContainsFunc(keys, func(key string) bool { return seen[key] }) {
+			continue
+		}
+		for _, key := range keys {
+			seen[key] = true
+		}
+		kept = append(kept, track)
+	}
+	return kept, len(tracks) - len(kept)
+}
+
+// dedupeKeys returns the keys under which track repeats another.
+func dedupeKeys(track domain.Track) []string {
+	var keys []string
+	if track.ExternalID != "" {
+		keys = append(keys, "id:"+track.ExternalID)
+	}
+	if track.ISRC != "" {
+		keys = append(keys, "isrc:"+strings.ToUpper(track.ISRC))
+	}
+	if len(keys) == 0 {
+		keys = append(keys, "name:"+strings.ToLower(track.Name+"|"+track.Artist()))
+	}
+	return keys
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupeTracks(t *testing.T) {
+	tracks := []domain.Track{
+		{Name: "One", ExternalID: "a", ISRC: "USRC17607839"},
+		{Name: "One", ExternalID: "a"},
+		{Name: "One (Remastered)", ExternalID: "b", ISRC: "usrc17607839"},
+		{Name: "Two", ExternalID: "c"},
+		{Name: "Local", Artists: []string{"X"}},
+		{Name: "local", Artists: []string{"x"}},
+		{Name: "Local", Artists: []string{"Y"}},
+	}
+
+	kept, removed := dedupeTracks(tracks)
+	assert.Equal(t, 3, removed)
+	var names []string
+	for _, track := range kept {
+		names = append(names, track.Name+"/"+track.Artist())
+	}
+	assert.Equal(t, []string{"One/", "Two/", "Local/X", "Local/Y"}, names)
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// ExportAccount reads every playlist of the user on provider with its
+// tracks and, if the provider is a ports.LibraryReader, the user's library.
+// Any failed read fails the export, so that an archive is never silently
+// incomplete.
+func (s *Service) ExportAccount(ctx context.Context, provider string, token string) (*domain.AccountExport, error) {
+	p, err := s.registry.Get(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err = s.resolveToken(ctx, provider, token)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &domain.AccountExport{
+		Provider:        provider,
+		ExportedAt:      time.Now().UTC(),
+		Playlists:       []domain.Playlist{},
+		LikedTracks:     []domain.Track{},
+		SavedAlbums:     []domain.Album{},
+		SavedShows:      []domain.Show{},
+		FollowedArtists: []domain.Artist{},
+	}
+
+	playlists, err := p.GetPlaylists(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	for _, playlist := range playlists {
+		tracks, err := p.GetPlaylistTracks(ctx, token, playlist.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export playlist %q: %w", playlist.Name, err)
+		}
+		playlist.Tracks = tracks
+		playlist.TrackCount = len(tracks)
+		export.Playlists = append(export.Playlists, playlist)
+	}
+
+	library, ok := p.(ports.LibraryReader)
+	if !ok {
+		return export, nil
+	}
+	if export.LikedTracks, err = nonNil(library.GetLikedTracks(ctx, token)); err != nil {
+		return nil, fmt.Errorf("failed to export liked songs: %w", err)
+	}
+	if export.SavedAlbums, err = nonNil(library.GetSavedAlbums(ctx, token)); err != nil {
+		return nil, fmt.Errorf("failed to export saved albums: %w", err)
+	}
+	if export.SavedShows, err = nonNil(library.GetSavedShows(ctx, token)); err != nil {
+		return nil, fmt.Errorf("failed to export saved shows: %w", err)
+	}
+	if export.FollowedArtists, err = nonNil(library.GetFollowedArtists(ctx, token)); err != nil {
+		return nil, fmt.Errorf("failed to export followed artists: %w", err)
+	}
+	return export, nil
+}
+
+// nonNil passes through the result of a library read, replacing a nil
+// slice with an empty one so that the section is exported as [].
+func nonNil[T any](items []T, err error) ([]T, error) {
+	if items == nil {
+		items = []T{}
+	}
+	return items, err
+}
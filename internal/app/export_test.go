@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type libraryProvider struct {
+	*mockProvider
+	albumsErr error
+}
+
+func (p *libraryProvider) GetLikedTracks(_ context.Context, _ string) ([]domain.Track, error) {
+	return []domain.Track{{Name: "Liked", Artists: []string{"Artist"}, ExternalID: "liked"}}, nil
+}
+
+func (p *libraryProvider) GetSavedAlbums(_ context.Context, _ string) ([]domain.Album, error) {
+	if p.albumsErr != nil {
+		return nil, p.albumsErr
+	}
+	return []domain.Album{{ID: "al1", Name: "Fearless", Artists: []string{"Taylor Swift"}}}, nil
+}
+
+func (p *libraryProvider) GetSavedShows(_ context.Context, _ string) ([]domain.Show, error) {
+	return nil, nil
+}
+
+func (p *libraryProvider) GetFollowedArtists(_ context.Context, _ string) ([]domain.Artist, error) {
+	return []domain.Artist{{ID: "ar1", Name: "Taylor Swift"}}, nil
+}
+
+func TestExportAccount(t *testing.T) {
+	provider := &libraryProvider{mockProvider: &mockProvider{
+		name:      "library",
+		playlists: []domain.Playlist{{ID: "pl-1", Name: "Mix", TrackCount: 5}},
+		tracks:    []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+	}}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(provider)
+	svc := newService(registry, 1)
+
+	export, err := svc.ExportAccount(context.Background(), "library", "tok")
+	require.NoError(t, err)
+	assert.Equal(t, "library", export.Provider)
+	assert.False(t, export.ExportedAt.IsZero())
+	require.Len(t, export.Playlists, 1)
+	assert.Equal(t, provider.tracks, export.Playlists[0].Tracks)
+	assert.Equal(t, 1, export.Playlists[0].TrackCount, "the count is of the exported tracks")
+	assert.Len(t, export.LikedTracks, 1)
+	assert.Equal(t, "Fearless", export.SavedAlbums[0].Name)
+	assert.NotNil(t, export.SavedShows, "empty sections are exported as []")
+	assert.Empty(t, export.SavedShows)
+	assert.Equal(t, "ar1", export.FollowedArtists[0].ID)
+
+	provider.albumsErr = domain.ErrInsufficientScope
+	_, err = svc.ExportAccount(context.Background(), "library", "tok")
+	assert.ErrorIs(t, err, domain.ErrInsufficientScope)
+}
+
+func TestExportAccount_PlaylistsOnly(t *testing.T) {
+	svc := newHookService()
+	source, err := svc.registry.Get("source")
+	require.NoError(t, err)
+	source.(*mockProvider).playlists = []domain.Playlist{{ID: "pl-1", Name: "Mix"}}
+
+	export, err := svc.ExportAccount(context.Background(), "source", "tok")
+	require.NoError(t, err)
+	require.Len(t, export.Playlists, 1)
+	assert.Len(t, export.Playlists[0].Tracks, 1)
+	assert.Empty(t, export.LikedTracks)
+	assert.NotNil(t, export.LikedTracks)
+
+	_, err = svc.ExportAccount(context.Background(), "missing", "tok")
+	assert.ErrorIs(t, err, domain.ErrProviderNotFound)
+}
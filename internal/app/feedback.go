@@ -0,0 +1,192 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// WithMatchFeedback makes migrations consult users' verdicts on matches:
+// known matches users rejected are searched again, search results they
+// rejected are reported as not found, and those they confirmed are
+// accepted with full confidence.
+func WithMatchFeedback(feedback ports.MatchFeedbackStore) Option {
+	return func(s *Service) {
+		s.feedback = feedback
+	}
+}
+
+// MatchFeedbackService implements ports.MatchFeedbackService.
+type MatchFeedbackService struct {
+	store      ports.MatchFeedbackStore
+	migrations ports.MigrationStore
+	mappings   ports.TrackMappingStore
+	golden     ports.GoldenMatchStore
+	quorum     int
+
+	// goldenLocks serializes the updates of each golden dataset entry,
+	// which read the entry and save it back.
+	goldenMu    sync.Mutex
+	goldenLocks map[string]*goldenLock
+}
+
+// NewMatchFeedbackService creates a feedback service that keeps verdicts in
+// store. Accounts only give verdicts on matches reported in their own
+// migrations in migrations. If mappings is not nil, matches confirmed by
+// most of at least quorum accounts are saved there as track mappings, so
+// migrations reuse them without searching, and removed again once most
+// accounts no longer confirm them. If golden is not nil, the verdicts of
+// most accounts are drawn into the golden dataset there.
+func NewMatchFeedbackService(store ports.MatchFeedbackStore, migrations ports.MigrationStore, mappings ports.TrackMappingStore, golden ports.GoldenMatchStore, quorum int) *MatchFeedbackService {
+	return &MatchFeedbackService{
+		store:       store,
+		migrations:  migrations,
+		mappings:    mappings,
+		golden:      golden,
+		quorum:      quorum,
+		goldenLocks: make(map[string]*goldenLock),
+	}
+}
+
+func (s *MatchFeedbackService) SubmitFeedback(ctx context.Context, feedback domain.MatchFeedback) (*domain.MatchFeedbackTally, error) {
+	if feedback.SourceTrack.ExternalID == "" || feedback.MatchedTrack.ExternalID == "" {
+		return nil, fmt.Errorf("%w: source_track and matched_track need an external_id", domain.ErrInvalidFeedback)
+	}
+	if feedback.SourceProvider == feedback.DestProvider {
+		return nil, fmt.Errorf("%w: source and destination provider must differ", domain.ErrInvalidFeedback)
+	}
+	feedback.AccountID = domain.AccountIDFromContext(ctx)
+	source, matched, err := s.reportedMatch(ctx, feedback)
+	if err != nil {
+		return nil, err
+	}
+	feedback.SourceTrack = source
+	feedback.MatchedTrack = matched
+	feedback.CreatedAt = time.Now().UTC()
+
+	previous, err := s.store.Tally(ctx, feedback.SourceProvider, source.ExternalID, feedback.DestProvider, matched.ExternalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to tally match feedback: %w", err)
+	}
+	if err := s.store.Save(ctx, &feedback); err != nil {
+		return nil, fmt.Errorf("failed to save match feedback: %w", err)
+	}
+	tally, err := s.store.Tally(ctx, feedback.SourceProvider, source.ExternalID, feedback.DestProvider, matched.ExternalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to tally match feedback: %w", err)
+	}
+
+	if s.mappings != nil {
+		switch {
+		case tally.Verdict() == domain.MatchConfirmed && tally.Accounts() >= s.quorum:
+			err = s.mappings.Save(ctx, &domain.TrackMapping{
+				ProviderA: feedback.SourceProvider,
+				TrackA:    source,
+				ProviderB: feedback.DestProvider,
+				TrackB:    matched,
+				Score:     1,
+			})
+		case previous.Verdict() == domain.MatchConfirmed && tally.Verdict() != domain.MatchConfirmed:
+			err = s.mappings.Delete(ctx, feedback.SourceProvider, source.ExternalID, feedback.DestProvider, matched.ExternalID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to update track mapping: %w", err)
+		}
+	}
+	if s.golden != nil {
+		if err := s.recordGoldenMatch(ctx, feedback, tally.Verdict()); err != nil {
+			return nil, fmt.Errorf("failed to update golden dataset: %w", err)
+		}
+	}
+	return &tally, nil
+}
+
+// reportedMatch looks up the match feedback is about in the migrations of
+// the calling account and returns the source track and the destination
+// track, either the match or one of the candidates ranked below it, as the
+// migration reported them. It returns domain.ErrInvalidFeedback if none of
+// the account's migrations reported the match.
+func (s *MatchFeedbackService) reportedMatch(ctx context.Context, feedback domain.MatchFeedback) (domain.Track, domain.Track, error) {
+	results, err := s.migrations.List(ctx, feedback.AccountID)
+	if err != nil {
+		return domain.Track{}, domain.Track{}, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	for i := len(results) - 1; i >= 0; i-- {
+		result := &results[i]
+		if result.DestProvider != feedback.DestProvider || !migratedFrom(result, feedback.SourceProvider) {
+			continue
+		}
+		for _, tr := range result.TrackResults {
+			if tr.SourceTrack.ExternalID != feedback.SourceTrack.ExternalID {
+				continue
+			}
+			if tr.MatchedTrack != nil && tr.MatchedTrack.ExternalID == feedback.MatchedTrack.ExternalID {
+				return tr.SourceTrack, *tr.MatchedTrack, nil
+			}
+			for _, c := range tr.Candidates {
+				if c.Track.ExternalID == feedback.MatchedTrack.ExternalID {
+					return tr.SourceTrack, c.Track, nil
+				}
+			}
+		}
+	}
+	return domain.Track{}, domain.Track{}, fmt.Errorf("%w: none of your migrations matched %s on %s to %s on %s", domain.ErrInvalidFeedback,
+		feedback.SourceTrack.ExternalID, feedback.SourceProvider, feedback.MatchedTrack.ExternalID, feedback.DestProvider)
+}
+
+// migratedFrom reports whether result read tracks from provider, as its
+// source or as one of the playlists it merged.
+func migratedFrom(result *domain.MigrationResult, provider string) bool {
+	if result.SourceProvider == provider {
+		return true
+	}
+	return slices.ContainsFunc(result.MergedFrom, func(m domain.MergeSource) bool {
+		return m.Provider == provider
+	})
+}
+
+// matchVerdict returns the verdict of most accounts on matching track on
+// sourceProvider to matched on destProvider. Lookup failures are logged and
+// count as no verdict.
+func (s *Service) matchVerdict(ctx context.Context, sourceProvider, destProvider string, track, matched domain.Track) domain.MatchVerdict {
+	if s.feedback == nil || sourceProvider == "" || track.ExternalID == "" || matched.ExternalID == "" {
+		return ""
+	}
+	tally, err := s.feedback.Tally(ctx, sourceProvider, track.ExternalID, destProvider, matched.ExternalID)
+	if err != nil {
+		log.Printf("[migration] match feedback lookup failed: %v", err)
+		return ""
+	}
+	return tally.Verdict()
+}
+
+// applyFeedback adjusts search results by the verdicts users gave on their
+// matches: rejected matches become not found, and confirmed ones are
+// accepted with a score of 1 even if the strategy wanted them reviewed.
+func (s *Service) applyFeedback(ctx context.Context, sourceProvider, destProvider string, results []domain.TrackResult) {
+	for i := range results {
+		tr := &results[i]
+		if tr.MatchedTrack == nil || (tr.Status != domain.TrackStatusMatched && tr.Status != domain.TrackStatusNeedsReview) {
+			continue
+		}
+		switch s.matchVerdict(ctx, sourceProvider, destProvider, tr.SourceTrack, *tr.MatchedTrack) {
+		case domain.MatchRejected:
+			log.Printf("[migration] match of '%s - %s' to '%s' was rejected by users",
+				tr.SourceTrack.Artist(), tr.SourceTrack.Name, tr.MatchedTrack.ExternalID)
+			tr.Status = domain.TrackStatusNotFound
+			tr.Error = fmt.Sprintf("best candidate %s was rejected by match feedback", tr.MatchedTrack.ExternalID)
+			tr.MatchedTrack = nil
+			tr.ConfidenceScore = 0
+		case domain.MatchConfirmed:
+			tr.Status = domain.TrackStatusMatched
+			tr.Error = ""
+			tr.ConfidenceScore = 1
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func feedbackOn(verdict domain.MatchVerdict) domain.MatchFeedback {
+	return domain.MatchFeedback{
+		SourceProvider: "source",
+		SourceTrack:    domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "sp-a"},
+		DestProvider:   "dest",
+		MatchedTrack:   domain.Track{Name: "Track A (Live)", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+		Verdict:        verdict,
+	}
+}
+
+// reportedMatches returns a migration store holding, for each account, a
+// migration that matched the tracks of feedbackOn, with another candidate
+// vid-b ranked below.
+func reportedMatches(t *testing.T, accountIDs ...string) *memory.MigrationStore {
+	t.Helper()
+	store := memory.NewMigrationStore()
+	match := feedbackOn("")
+	for _, id := range accountIDs {
+		require.NoError(t, store.Save(context.Background(), &domain.MigrationResult{
+			ID:             "mig-" + id,
+			AccountID:      id,
+			SourceProvider: match.SourceProvider,
+			DestProvider:   match.DestProvider,
+			TrackResults: []domain.TrackResult{{
+				SourceTrack:  match.SourceTrack,
+				MatchedTrack: &match.MatchedTrack,
+				Status:       domain.TrackStatusMatched,
+				Candidates:   []domain.TrackCandidate{{Track: domain.Track{Name: "Track A", ExternalID: "vid-b"}}},
+			}},
+		}))
+	}
+	return store
+}
+
+func TestMatchFeedbackService_SubmitFeedback(t *testing.T) {
+	mappings := memory.NewTrackMappingStore()
+	svc := NewMatchFeedbackService(memory.NewMatchFeedbackStore(), reportedMatches(t, "acc-1", "acc-2", "acc-3"), mappings, nil, 3)
+	first := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-1"})
+	second := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-2"})
+	third := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-3"})
+
+	tally, err := svc.SubmitFeedback(first, feedbackOn(domain.MatchRejected))
+	require.NoError(t, err)
+	assert.Equal(t, domain.MatchFeedbackTally{Rejections: 1}, *tally)
+
+	tally, err = svc.SubmitFeedback(second, feedbackOn(domain.MatchConfirmed))
+	require.NoError(t, err)
+	assert.Equal(t, domain.MatchVerdict(""), tally.Verdict(), "a tie holds no verdict")
+	_, _, err = mappings.Find(context.Background(), "source", "sp-a", "dest")
+	assert.ErrorIs(t, err, domain.ErrMappingNotFound)
+
+	tally, err = svc.SubmitFeedback(first, feedbackOn(domain.MatchConfirmed))
+	require.NoError(t, err)
+	assert.Equal(t, domain.MatchFeedbackTally{Confirmations: 2}, *tally, "the first account changed its verdict")
+	_, _, err = mappings.Find(context.Background(), "source", "sp-a", "dest")
+	assert.ErrorIs(t, err, domain.ErrMappingNotFound, "two accounts are below the quorum")
+
+	// The tracks are taken from the migration, not from the request.
+	spoofed := feedbackOn(domain.MatchConfirmed)
+	spoofed.MatchedTrack.Name = "Something Else"
+	_, err = svc.SubmitFeedback(third, spoofed)
+	require.NoError(t, err)
+	matched, score, err := mappings.Find(context.Background(), "dest", "vid-a", "source")
+	require.NoError(t, err)
+	assert.Equal(t, "sp-a", matched.ExternalID)
+	assert.Equal(t, 1.0, score)
+	matched, _, err = mappings.Find(context.Background(), "source", "sp-a", "dest")
+	require.NoError(t, err)
+	assert.Equal(t, "Track A (Live)", matched.Name)
+
+	// Once most accounts no longer confirm the match, the mapping goes.
+	_, err = svc.SubmitFeedback(first, feedbackOn(domain.MatchRejected))
+	require.NoError(t, err)
+	_, _, err = mappings.Find(context.Background(), "source", "sp-a", "dest")
+	require.NoError(t, err)
+	_, err = svc.SubmitFeedback(second, feedbackOn(domain.MatchRejected))
+	require.NoError(t, err)
+	_, _, err = mappings.Find(context.Background(), "source", "sp-a", "dest")
+	assert.ErrorIs(t, err, domain.ErrMappingNotFound)
+	_, _, err = mappings.Find(context.Background(), "dest", "vid-a", "source")
+	assert.ErrorIs(t, err, domain.ErrMappingNotFound)
+
+	invalid := feedbackOn(domain.MatchConfirmed)
+	invalid.MatchedTrack.ExternalID = ""
+	_, err = svc.SubmitFeedback(first, invalid)
+	assert.ErrorIs(t, err, domain.ErrInvalidFeedback)
+
+	unreported := feedbackOn(domain.MatchConfirmed)
+	unreported.MatchedTrack.ExternalID = "vid-z"
+	_, err = svc.SubmitFeedback(first, unreported)
+	assert.ErrorIs(t, err, domain.ErrInvalidFeedback, "the match is not in any of the account's migrations")
+
+	stranger := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-4"})
+	_, err = svc.SubmitFeedback(stranger, feedbackOn(domain.MatchRejected))
+	assert.ErrorIs(t, err, domain.ErrInvalidFeedback, "the match is in other accounts' migrations only")
+}
+
+func TestMigratePlaylist_AppliesMatchFeedback(t *testing.T) {
+	source := &mockProvider{
+		name:      "source",
+		createdID: "source-pl",
+		tracks:    []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "sp-a"}},
+	}
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "dest-pl",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {track: &domain.Track{Name: "Track A (Live)", Artists: []string{"Artist A"}, ExternalID: "vid-a"}, score: 0.8},
+		},
+	}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	mappings := memory.NewTrackMappingStore()
+	migrations := memory.NewMigrationStore()
+	store := memory.NewMatchFeedbackStore()
+	feedback := NewMatchFeedbackService(store, migrations, mappings, nil, 1)
+	svc := newService(registry, 1, WithMigrationStore(migrations), WithTrackMappings(mappings), WithMatchFeedback(store))
+	req := domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	}
+
+	result, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.MatchedTracks)
+
+	// The saved mapping is no longer reused once users reject it, and the
+	// search result it came from is not accepted either.
+	_, err = feedback.SubmitFeedback(context.Background(), feedbackOn(domain.MatchRejected))
+	require.NoError(t, err)
+	result, err = svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dest.searchCallCount)
+	assert.Equal(t, 0, result.MatchedTracks)
+	assert.Equal(t, domain.TrackStatusNotFound, result.TrackResults[0].Status)
+	assert.Contains(t, result.TrackResults[0].Error, "rejected by match feedback")
+
+	// Confirmed, the match is reused with full confidence without searching.
+	_, err = feedback.SubmitFeedback(context.Background(), feedbackOn(domain.MatchConfirmed))
+	require.NoError(t, err)
+	result, err = svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dest.searchCallCount)
+	assert.Equal(t, 1, result.MatchedTracks)
+	assert.Equal(t, 1.0, result.TrackResults[0].ConfidenceScore)
+}
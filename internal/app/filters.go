@@ -0,0 +1,141 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// trackFilter is a compiled domain.TrackFilter, together with the genres of
+// domain.MigrationRequest.Genres.
+type trackFilter struct {
+	artists  map[string]bool
+	patterns []*regexp.Regexp
+	sources  []string
+	explicit bool
+
+	// excludedGenres and includedGenres match genres by whole words.
+	excludedGenres []genrePattern
+	includedGenres []genrePattern
+}
+
+// genrePattern matches the genres containing a genre as whole words.
+type genrePattern struct {
+	genre string
+	re    *regexp.Regexp
+}
+
+// compileFilter compiles the exclude filter f and the genres to migrate
+// only, or returns nil if they exclude nothing. A title pattern that is not
+// a valid regular expression is reported as domain.ErrInvalidFilter.
+func compileFilter(f *domain.TrackFilter, genres []string) (*trackFilter, error) {
+	filter := &trackFilter{includedGenres: compileGenres(genres)}
+	if f == nil || (len(f.Artists) == 0 && len(f.TitlePatterns) == 0 && !f.Explicit && len(f.Genres) == 0) {
+		if filter.includedGenres == nil {
+			return nil, nil
+		}
+		return filter, nil
+	}
+
+	filter.artists = make(map[string]bool, len(f.Artists))
+	filter.explicit = f.Explicit
+	filter.excludedGenres = compileGenres(f.Genres)
+	for _, artist := range f.Artists {
+		if artist = strings.TrimSpace(artist); artist != "" {
+			filter.artists[strings.ToLower(artist)] = true
+		}
+	}
+	for _, pattern := range f.TitlePatterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: title pattern %q: %v", domain.ErrInvalidFilter, pattern, err)
+		}
+		filter.patterns = append(filter.patterns, re)
+		filter.sources = append(filter.sources, pattern)
+	}
+	return filter, nil
+}
+
+// compileGenres returns the patterns of genres, skipping blank ones.
+func compileGenres(genres []string) []genrePattern {
+	var patterns []genrePattern
+	for _, genre := range genres {
+		if genre = strings.TrimSpace(genre); genre != "" {
+			re := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(genre) + `\b`)
+			patterns = append(patterns, genrePattern{genre: genre, re: re})
+		}
+	}
+	return patterns
+}
+
+// matchGenre returns the first of patterns that one of genres matches.
+func matchGenre(patterns []genrePattern, genres []string) (string, bool) {
+	for _, p := range patterns {
+		for _, genre := range genres {
+			if p.re.MatchString(genre) {
+				return p.genre, true
+			}
+		}
+	}
+	return "", false
+}
+
+// needsGenres reports whether the filter looks at the genres of tracks, so
+// that they must be fetched before filtering.
+func (f *trackFilter) needsGenres() bool {
+	return f != nil && (len(f.excludedGenres) > 0 || len(f.includedGenres) > 0)
+}
+
+// excludes returns the reason the filter excludes track, or "" if it does
+// not. A nil filter excludes nothing.
+func (f *trackFilter) excludes(track domain.Track) string {
+	if f == nil {
+		return ""
+	}
+	for _, artist := range track.Artists {
+		if f.artists[strings.ToLower(strings.TrimSpace(artist))] {
+			return fmt.Sprintf("excluded by artist %q", artist)
+		}
+	}
+	for i, re := range f.patterns {
+		if re.MatchString(track.Name) {
+			return fmt.Sprintf("excluded by title pattern %q", f.sources[i])
+		}
+	}
+	if f.explicit && track.Explicit {
+		return "excluded as explicit"
+	}
+	if genre, ok := matchGenre(f.excludedGenres, track.Genres); ok {
+		return fmt.Sprintf("excluded by genre %q", genre)
+	}
+	if len(f.includedGenres) > 0 {
+		if len(track.Genres) == 0 {
+			return "not in the selected genres: no known genre"
+		}
+		if _, ok := matchGenre(f.includedGenres, track.Genres); !ok {
+			return fmt.Sprintf("not in the selected genres: %s", strings.Join(track.Genres, ", "))
+		}
+	}
+	return ""
+}
+
+// filterTracks returns, for each of tracks, the reason filter excludes it,
+// and how many it excludes. It returns nil if filter excludes none.
+func filterTracks(filter *trackFilter, tracks []domain.Track) ([]string, int) {
+	var reasons []string
+	excluded := 0
+	for i, track := range tracks {
+		reason := filter.excludes(track)
+		if reason == "" {
+			continue
+		}
+		if reasons == nil {
+			reasons = make([]string, len(tracks))
+		}
+		reasons[i] = reason
+		excluded++
+	}
+	return reasons, excluded
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackFilter_Excludes(t *testing.T) {
+	filter, err := compileFilter(&domain.TrackFilter{
+		Artists:       []string{" Nightcore Crew "},
+		TitlePatterns: []string{"sped up", "^intro$"},
+		Explicit:      true,
+	}, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		track domain.Track
+		want  string
+	}{
+		{domain.Track{Name: "Song", Artists: []string{"Band", "nightcore crew"}}, `excluded by artist "nightcore crew"`},
+		{domain.Track{Name: "Song (Sped Up)", Artists: []string{"Band"}}, `excluded by title pattern "sped up"`},
+		{domain.Track{Name: "Intro", Artists: []string{"Band"}}, `excluded by title pattern "^intro$"`},
+		{domain.Track{Name: "Song", Artists: []string{"Band"}, Explicit: true}, "excluded as explicit"},
+		{domain.Track{Name: "Outro (Intro Reprise)", Artists: []string{"Band"}}, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, filter.excludes(tt.track), tt.track.Name)
+	}
+
+	empty, err := compileFilter(&domain.TrackFilter{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+	assert.Empty(t, empty.excludes(domain.Track{Name: "Song", Explicit: true}))
+
+	_, err = compileFilter(&domain.TrackFilter{TitlePatterns: []string{"(unclosed"}}, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidFilter)
+}
+
+func TestMigratePlaylist_ExcludeFilter(t *testing.T) {
+	svc := newHookService()
+	source, err := svc.registry.Get("source")
+	require.NoError(t, err)
+	source.(*mockProvider).tracks = append(source.(*mockProvider).tracks,
+		domain.Track{Name: "Track A (Sped Up)", Artists: []string{"Artist A"}})
+	dest, err := svc.registry.Get("dest")
+	require.NoError(t, err)
+
+	req := hookRequest
+	req.Exclude = &domain.TrackFilter{TitlePatterns: []string{"sped up"}}
+	result, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.TotalTracks)
+	assert.Equal(t, 1, result.MatchedTracks)
+	assert.Equal(t, 1, result.FilteredTracks)
+	assert.Zero(t, result.FailedTracks)
+	filtered := result.TrackResults[1]
+	assert.Equal(t, domain.TrackStatusFiltered, filtered.Status)
+	assert.Equal(t, `excluded by title pattern "sped up"`, filtered.Error)
+	assert.Equal(t, 1, dest.(*mockProvider).searchCallCount, "filtered tracks are not searched")
+	assert.Equal(t, []string{"a"}, dest.(*mockProvider).addedTracks)
+
+	req.Exclude = &domain.TrackFilter{Artists: []string{"artist a"}}
+	_, err = svc.MigratePlaylist(context.Background(), req)
+	assert.ErrorIs(t, err, domain.ErrInvalidFilter, "a filter excluding every track fails")
+
+	req.Exclude = &domain.TrackFilter{TitlePatterns: []string{"["}}
+	_, err = svc.MigratePlaylist(context.Background(), req)
+	assert.ErrorIs(t, err, domain.ErrInvalidFilter)
+}
+
+func TestTrackFilter_Genres(t *testing.T) {
+	filter, err := compileFilter(&domain.TrackFilter{Genres: []string{"smooth jazz"}}, []string{"jazz", " "})
+	require.NoError(t, err)
+	assert.True(t, filter.needsGenres())
+
+	tests := []struct {
+		genres []string
+		want   string
+	}{
+		{[]string{"vocal jazz", "swing"}, ""},
+		{[]string{"Jazz"}, ""},
+		{[]string{"smooth jazz"}, `excluded by genre "smooth jazz"`},
+		{[]string{"jazzcore"}, "not in the selected genres: jazzcore"},
+		{[]string{"rock", "pop"}, "not in the selected genres: rock, pop"},
+		{nil, "not in the selected genres: no known genre"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, filter.excludes(domain.Track{Name: "Song", Genres: tt.genres}), tt.genres)
+	}
+
+	only, err := compileFilter(nil, []string{"k-pop"})
+	require.NoError(t, err)
+	assert.Empty(t, only.excludes(domain.Track{Genres: []string{"K-Pop"}}))
+
+	noGenres, err := compileFilter(&domain.TrackFilter{Explicit: true}, nil)
+	require.NoError(t, err)
+	assert.False(t, noGenres.needsGenres())
+}
+
+// genreProvider tags tracks with the genres of their name.
+type genreProvider struct {
+	*mockProvider
+	genres    map[string][]string
+	tagCalled bool
+}
+
+func (p *genreProvider) TagGenres(_ context.Context, _ string, tracks []domain.Track) error {
+	p.tagCalled = true
+	for i := range tracks {
+		tracks[i].Genres = p.genres[tracks[i].Name]
+	}
+	return nil
+}
+
+func TestMigratePlaylist_Genres(t *testing.T) {
+	source := &genreProvider{
+		mockProvider: &mockProvider{
+			name: "source",
+			tracks: []domain.Track{
+				{Name: "Take Five", Artists: []string{"Dave Brubeck"}},
+				{Name: "Smells Like Teen Spirit", Artists: []string{"Nirvana"}},
+				{Name: "Unknown", Artists: []string{"Nobody"}},
+			},
+		},
+		genres: map[string][]string{
+			"Take Five":               {"cool jazz", "jazz"},
+			"Smells Like Teen Spirit": {"grunge"},
+		},
+	}
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "dest-pl",
+		searchResults: map[string]*searchResult{
+			"Take Five|Dave Brubeck": {track: &domain.Track{Name: "Take Five", ExternalID: "five"}, score: 0.9},
+		},
+	}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+	svc := newService(registry, 1)
+
+	req := hookRequest
+	result, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, source.tagCalled, "genres are only fetched for genre filters")
+	assert.Zero(t, result.FilteredTracks)
+
+	req.Genres = []string{"jazz"}
+	result, err = svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, source.tagCalled)
+	assert.Equal(t, 1, result.MatchedTracks)
+	assert.Equal(t, 2, result.FilteredTracks)
+	assert.Zero(t, result.FailedTracks)
+	assert.Equal(t, []string{"cool jazz", "jazz"}, result.TrackResults[0].SourceTrack.Genres)
+	assert.Equal(t, "not in the selected genres: grunge", result.TrackResults[1].Error)
+	assert.Equal(t, "not in the selected genres: no known genre", result.TrackResults[2].Error)
+	assert.Equal(t, []string{"five", "five"}, dest.addedTracks, "both migrations add only the jazz track")
+
+	req.Genres = []string{"classical"}
+	_, err = svc.MigratePlaylist(context.Background(), req)
+	assert.ErrorIs(t, err, domain.ErrInvalidFilter, "a genre no track has excludes every track")
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// minIdentificationScore is the confidence an identification needs for the
+// recording found to be searched; weaker ones are likely other recordings.
+const minIdentificationScore = 0.5
+
+// WithFingerprinter enables the fingerprint fallback: source tracks whose
+// search found nothing are identified by the acoustic fingerprint of their
+// audio, when the source provider can supply it, and searched again under
+// the metadata of the recording found. It helps with local files and other
+// sources whose titles and tags are junk.
+func WithFingerprinter(fingerprinter ports.Fingerprinter) Option {
+	return func(s *Service) {
+		s.fingerprinter = fingerprinter
+	}
+}
+
+// fingerprintStage retries the tracks of run that were not found by
+// identifying their audio, if a fingerprinter is set and the source
+// provider is a ports.AudioSource. A track is matched if the search for the
+// identified recording finds a candidate the migration accepts, with the
+// search score weighted by the confidence of the identification. Tracks
+// that cannot be identified with at least minIdentificationScore keep their
+// result.
+func (s *Service) fingerprintStage(ctx context.Context, run *migrationRun) error {
+	audio, ok := run.source.(ports.AudioSource)
+	if s.fingerprinter == nil || !ok {
+		return nil
+	}
+	var indices []int
+	for i, tr := range run.results {
+		if tr.Status == domain.TrackStatusNotFound && !tr.SourceTrack.IsEpisode() {
+			indices = append(indices, i)
+		}
+	}
+	if len(indices) == 0 {
+		return nil
+	}
+
+	matchOpts := domain.MatchOptionsFromContext(ctx)
+	strategy, minScore := matchOpts.Strategy, matchOpts.Threshold()
+	var matched []domain.TrackResult
+	searches := 0
+	for _, i := range indices {
+		if ctx.Err() != nil {
+			break
+		}
+		track := run.results[i].SourceTrack
+		identified, confidence, err := s.identify(ctx, run, audio, track)
+		if err != nil {
+			if !errors.Is(err, domain.ErrNoAudio) {
+				log.Printf("[migration] fingerprinting '%s - %s' failed: %v", track.Artist(), track.Name, err)
+			}
+			continue
+		}
+		if identified == nil || confidence < minIdentificationScore {
+			continue
+		}
+
+		query := track
+		query.Name, query.Artists, query.Album = identified.Name, identified.Artists, identified.Album
+		query.DurationMS, query.MusicBrainzID = identified.DurationMS, identified.MusicBrainzID
+		var found *domain.Track
+		var score float64
+		err = s.runStage(ctx, domain.StageSearch, func(ctx context.Context) error {
+			var err error
+			found, score, err = run.dest.SearchTrack(ctx, run.req.DestToken, query)
+			return err
+		})
+		searches++
+		if err != nil || found == nil {
+			continue
+		}
+		score *= confidence
+		if score < minScore || !strategy.Confirms(query, *found) {
+			continue
+		}
+
+		tr := run.results[i]
+		tr.Status = domain.TrackStatusMatched
+		tr.MatchedTrack = found
+		tr.ConfidenceScore = score
+		tr.Error = ""
+		tr.Fingerprinted = true
+		run.results[i] = tr
+		matched = append(matched, tr)
+		log.Printf("[migration] fingerprint matched '%s - %s' as '%s - %s' -> '%s' (score: %.2f)",
+			track.Artist(), track.Name, identified.Artist(), identified.Name, found.ExternalID, score)
+	}
+	s.chargeQuota(run, domain.QuotaOpSearch, searches)
+	s.saveMappings(ctx, run.req.SourceProvider, run.req.DestProvider, matched)
+	if len(matched) > 0 {
+		log.Printf("[migration] fingerprints matched %d of %d tracks not found", len(matched), len(indices))
+	}
+	return nil
+}
+
+// identify fingerprints the audio of track, within the search timeout.
+func (s *Service) identify(ctx context.Context, run *migrationRun, audio ports.AudioSource, track domain.Track) (*domain.Track, float64, error) {
+	var identified *domain.Track
+	var confidence float64
+	err := s.runStage(ctx, domain.StageSearch, func(ctx context.Context) error {
+		r, err := audio.OpenAudio(ctx, run.req.SourceToken, track)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		identified, confidence, err = s.fingerprinter.IdentifyAudio(ctx, r)
+		return err
+	})
+	return identified, confidence, err
+}
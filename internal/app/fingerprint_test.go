@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// audioProvider is a mockProvider that serves the names of its tracks as
+// their audio.
+type audioProvider struct {
+	*mockProvider
+}
+
+func (a *audioProvider) OpenAudio(_ context.Context, _ string, track domain.Track) (io.ReadCloser, error) {
+	if track.ExternalID == "" {
+		return nil, domain.ErrNoAudio
+	}
+	return io.NopCloser(strings.NewReader(track.ExternalID)), nil
+}
+
+// fakeFingerprinter identifies audio from catalog by its content.
+type fakeFingerprinter struct {
+	catalog map[string]identification
+	calls   int
+}
+
+type identification struct {
+	track      domain.Track
+	confidence float64
+}
+
+func (f *fakeFingerprinter) IdentifyAudio(_ context.Context, audio io.Reader) (*domain.Track, float64, error) {
+	f.calls++
+	b, err := io.ReadAll(audio)
+	if err != nil {
+		return nil, 0, err
+	}
+	id, ok := f.catalog[string(b)]
+	if !ok {
+		return nil, 0, nil
+	}
+	return &id.track, id.confidence, nil
+}
+
+func TestFingerprintStage_MatchesIdentifiedTracks(t *testing.T) {
+	fingerprinter := &fakeFingerprinter{catalog: map[string]identification{
+		"f1": {track: domain.Track{Name: "Real Song", Artists: []string{"Real Band"}}, confidence: 0.95},
+		"f2": {track: domain.Track{Name: "Vague Song", Artists: []string{"Someone"}}, confidence: 0.4},
+	}}
+	svc := newService(adapters.NewProviderRegistry(), 1, WithFingerprinter(fingerprinter))
+	source := &audioProvider{&mockProvider{name: "localfiles"}}
+	dest := &mockProvider{name: "dest", searchResults: map[string]*searchResult{
+		"Real Song|Real Band":  {track: &domain.Track{ExternalID: "d1"}, score: 1},
+		"Vague Song|Someone":   {track: &domain.Track{ExternalID: "d2"}, score: 1},
+		"Track 01|Unknown":     {track: &domain.Track{ExternalID: "never"}, score: 1},
+		"Already Found|Artist": {track: &domain.Track{ExternalID: "never"}, score: 1},
+	}}
+	run := &migrationRun{
+		req:    domain.MigrationRequest{SourceProvider: source.name, DestProvider: dest.name},
+		source: source, dest: dest, timing: &domain.MigrationTiming{},
+		results: []domain.TrackResult{
+			{SourceTrack: domain.Track{ExternalID: "f1", Name: "Track 01", Artists: []string{"Unknown"}}, Status: domain.TrackStatusNotFound, Error: "no match"},
+			{SourceTrack: domain.Track{ExternalID: "f2", Name: "Track 02"}, Status: domain.TrackStatusNotFound},
+			{SourceTrack: domain.Track{ExternalID: "f3", Name: "Track 03"}, Status: domain.TrackStatusNotFound},
+			{SourceTrack: domain.Track{Name: "No Audio"}, Status: domain.TrackStatusNotFound},
+			{SourceTrack: domain.Track{ExternalID: "f1", Name: "Already Found"}, Status: domain.TrackStatusMatched},
+		},
+	}
+
+	require.NoError(t, svc.fingerprintStage(context.Background(), run))
+	assert.Equal(t, 3, fingerprinter.calls, "tracks without audio and matched tracks are skipped")
+	assert.Equal(t, 1, dest.searchCallCount, "only confidently identified tracks are searched again")
+
+	first := run.results[0]
+	assert.Equal(t, domain.TrackStatusMatched, first.Status)
+	assert.True(t, first.Fingerprinted)
+	assert.Equal(t, "d1", first.MatchedTrack.ExternalID)
+	assert.InDelta(t, 0.95, first.ConfidenceScore, 1e-9)
+	assert.Empty(t, first.Error)
+	assert.Equal(t, "Track 01", first.SourceTrack.Name, "the source track is kept")
+
+	assert.Equal(t, domain.TrackStatusNotFound, run.results[1].Status, "weak identifications are rejected")
+	assert.Equal(t, domain.TrackStatusNotFound, run.results[2].Status)
+	assert.Equal(t, domain.TrackStatusNotFound, run.results[3].Status)
+	assert.False(t, run.results[4].Fingerprinted)
+}
+
+func TestFingerprintStage_SkipsSourcesWithoutAudio(t *testing.T) {
+	fingerprinter := &fakeFingerprinter{}
+	svc := newService(adapters.NewProviderRegistry(), 1, WithFingerprinter(fingerprinter))
+	run := newRun(&mockProvider{name: "source"}, &mockProvider{name: "dest"}, domain.MigrationRequest{})
+	run.results = []domain.TrackResult{{SourceTrack: domain.Track{ExternalID: "s1"}, Status: domain.TrackStatusNotFound}}
+
+	require.NoError(t, svc.fingerprintStage(context.Background(), run))
+	assert.Zero(t, fingerprinter.calls)
+	assert.Equal(t, domain.TrackStatusNotFound, run.results[0].Status)
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// GoldenDatasetService implements ports.GoldenDatasetService.
+type GoldenDatasetService struct {
+	store ports.GoldenMatchStore
+}
+
+// NewGoldenDatasetService creates a service curating the golden dataset in
+// store.
+func NewGoldenDatasetService(store ports.GoldenMatchStore) *GoldenDatasetService {
+	return &GoldenDatasetService{store: store}
+}
+
+func (s *GoldenDatasetService) CurateMatch(ctx context.Context, match domain.GoldenMatch) (*domain.GoldenMatch, error) {
+	if match.SourceTrack.ExternalID == "" {
+		return nil, fmt.Errorf("%w: source_track needs an external_id", domain.ErrInvalidGoldenMatch)
+	}
+	if match.SourceProvider == match.DestProvider {
+		return nil, fmt.Errorf("%w: source and destination provider must differ", domain.ErrInvalidGoldenMatch)
+	}
+	if match.Expected != nil && match.Expected.ExternalID == "" {
+		return nil, fmt.Errorf("%w: expected needs an external_id", domain.ErrInvalidGoldenMatch)
+	}
+	if match.Expected == nil && len(match.Rejected) == 0 {
+		return nil, fmt.Errorf("%w: expected or rejected must be set", domain.ErrInvalidGoldenMatch)
+	}
+	for _, t := range match.Rejected {
+		if t.ExternalID == "" {
+			return nil, fmt.Errorf("%w: rejected tracks need an external_id", domain.ErrInvalidGoldenMatch)
+		}
+		if match.Expected != nil && t.ExternalID == match.Expected.ExternalID {
+			return nil, fmt.Errorf("%w: %s is both expected and rejected", domain.ErrInvalidGoldenMatch, t.ExternalID)
+		}
+	}
+
+	match.ID = domain.GoldenMatchID(match.SourceProvider, match.SourceTrack.ExternalID, match.DestProvider)
+	match.Curated = true
+	match.UpdatedAt = time.Now().UTC()
+	if err := s.store.Save(ctx, &match); err != nil {
+		return nil, fmt.Errorf("failed to save golden match: %w", err)
+	}
+	return &match, nil
+}
+
+func (s *GoldenDatasetService) ListMatches(ctx context.Context, filter domain.GoldenMatchFilter) ([]domain.GoldenMatch, error) {
+	return s.store.List(ctx, filter)
+}
+
+func (s *GoldenDatasetService) DeleteMatch(ctx context.Context, id string) error {
+	return s.store.Delete(ctx, id)
+}
+
+// recordGoldenMatch updates the golden dataset entry of the source track of
+// feedback with verdict, the verdict most accounts hold on the match: a
+// confirmed match becomes the expected track, a rejected one a rejected
+// candidate, and a match without a verdict is dropped from the entry.
+// Curated entries are left alone.
+func (s *MatchFeedbackService) recordGoldenMatch(ctx context.Context, feedback domain.MatchFeedback, verdict domain.MatchVerdict) error {
+	id := domain.GoldenMatchID(feedback.SourceProvider, feedback.SourceTrack.ExternalID, feedback.DestProvider)
+	unlock := s.lockGolden(id)
+	defer unlock()
+
+	match, err := s.golden.Get(ctx, id)
+	if errors.Is(err, domain.ErrGoldenMatchNotFound) {
+		match = &domain.GoldenMatch{
+			ID:             id,
+			SourceProvider: feedback.SourceProvider,
+			SourceTrack:    feedback.SourceTrack,
+			DestProvider:   feedback.DestProvider,
+		}
+	} else if err != nil {
+		return err
+	}
+	if match.Curated {
+		return nil
+	}
+
+	matched := feedback.MatchedTrack
+	if match.Expected != nil && match.Expected.ExternalID == matched.ExternalID {
+		match.Expected = nil
+	}
+	match.Rejected = slices.DeleteFunc(match.Rejected, func(t domain.Track) bool {
+		return t.ExternalID == matched.ExternalID
+	})
+	switch verdict {
+	case domain.MatchConfirmed:
+		match.Expected = &matched
+	case domain.MatchRejected:
+		match.Rejected = append(match.Rejected, matched)
+	}
+
+	if match.Expected == nil && len(match.Rejected) == 0 {
+		if err := s.golden.Delete(ctx, id); err != nil && !errors.Is(err, domain.ErrGoldenMatchNotFound) {
+			return err
+		}
+		return nil
+	}
+	match.UpdatedAt = time.Now().UTC()
+	return s.golden.Save(ctx, match)
+}
+
+// goldenLock is the lock of one golden dataset entry, shared by the updates
+// waiting on it.
+type goldenLock struct {
+	mu      sync.Mutex
+	waiters int
+}
+
+// lockGolden takes the lock of the golden dataset entry id, waiting for
+// other updates of the entry to finish, and returns the func releasing it.
+func (s *MatchFeedbackService) lockGolden(id string) (unlock func()) {
+	s.goldenMu.Lock()
+	l, ok := s.goldenLocks[id]
+	if !ok {
+		l = &goldenLock{}
+		s.goldenLocks[id] = l
+	}
+	l.waiters++
+	s.goldenMu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		s.goldenMu.Lock()
+		if l.waiters--; l.waiters == 0 {
+			delete(s.goldenLocks, id)
+		}
+		s.goldenMu.Unlock()
+	}
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchFeedbackService_DrawsGoldenDataset(t *testing.T) {
+	golden := memory.NewGoldenMatchStore()
+	svc := NewMatchFeedbackService(memory.NewMatchFeedbackStore(), reportedMatches(t, "acc-1", "acc-2"), nil, golden, 1)
+	first := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-1"})
+	second := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-2"})
+	id := domain.GoldenMatchID("source", "sp-a", "dest")
+
+	_, err := svc.SubmitFeedback(first, feedbackOn(domain.MatchRejected))
+	require.NoError(t, err)
+	match, err := golden.Get(context.Background(), id)
+	require.NoError(t, err)
+	assert.Nil(t, match.Expected)
+	assert.Equal(t, []domain.Track{feedbackOn("").MatchedTrack}, match.Rejected)
+	assert.Equal(t, "Track A", match.SourceTrack.Name)
+
+	_, err = svc.SubmitFeedback(second, feedbackOn(domain.MatchConfirmed))
+	require.NoError(t, err)
+	_, err = golden.Get(context.Background(), id)
+	assert.ErrorIs(t, err, domain.ErrGoldenMatchNotFound, "a tie leaves nothing known")
+
+	other := feedbackOn(domain.MatchConfirmed)
+	other.MatchedTrack = domain.Track{Name: "Track A", ExternalID: "vid-b"}
+	_, err = svc.SubmitFeedback(first, other)
+	require.NoError(t, err)
+	_, err = svc.SubmitFeedback(first, feedbackOn(domain.MatchConfirmed))
+	require.NoError(t, err)
+	match, err = golden.Get(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "vid-a", match.Expected.ExternalID, "the latest confirmed match is expected")
+	assert.Empty(t, match.Rejected)
+}
+
+func TestMatchFeedbackService_GoldenUpdatesDoNotRace(t *testing.T) {
+	golden := memory.NewGoldenMatchStore()
+	svc := NewMatchFeedbackService(memory.NewMatchFeedbackStore(), reportedMatches(t, ""), nil, golden, 1)
+
+	const n = 20
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() {
+			feedback := feedbackOn(domain.MatchRejected)
+			feedback.MatchedTrack = domain.Track{ExternalID: fmt.Sprintf("vid-%d", i)}
+			assert.NoError(t, svc.recordGoldenMatch(context.Background(), feedback, domain.MatchRejected))
+		})
+	}
+	wg.Wait()
+
+	match, err := golden.Get(context.Background(), domain.GoldenMatchID("source", "sp-a", "dest"))
+	require.NoError(t, err)
+	assert.Len(t, match.Rejected, n, "no update is lost")
+	assert.Empty(t, svc.goldenLocks)
+}
+
+func TestGoldenDatasetService_CurateMatch(t *testing.T) {
+	golden := memory.NewGoldenMatchStore()
+	svc := NewGoldenDatasetService(golden)
+	feedback := NewMatchFeedbackService(memory.NewMatchFeedbackStore(), reportedMatches(t, ""), nil, golden, 1)
+	ctx := context.Background()
+
+	curated, err := svc.CurateMatch(ctx, domain.GoldenMatch{
+		SourceProvider: "source",
+		SourceTrack:    domain.Track{Name: "Track A", ExternalID: "sp-a"},
+		DestProvider:   "dest",
+		Rejected:       []domain.Track{{ExternalID: "vid-a"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.GoldenMatchID("source", "sp-a", "dest"), curated.ID)
+	assert.True(t, curated.Curated)
+
+	_, err = feedback.SubmitFeedback(ctx, feedbackOn(domain.MatchConfirmed))
+	require.NoError(t, err)
+	matches, err := svc.ListMatches(ctx, domain.GoldenMatchFilter{DestProvider: "dest"})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Nil(t, matches[0].Expected, "feedback does not change curated entries")
+
+	require.NoError(t, svc.DeleteMatch(ctx, curated.ID))
+	assert.ErrorIs(t, svc.DeleteMatch(ctx, curated.ID), domain.ErrGoldenMatchNotFound)
+
+	for _, invalid := range []domain.GoldenMatch{
+		{SourceProvider: "source", DestProvider: "dest", Expected: &domain.Track{ExternalID: "vid-a"}},
+		{SourceProvider: "source", SourceTrack: domain.Track{ExternalID: "sp-a"}, DestProvider: "source", Expected: &domain.Track{ExternalID: "vid-a"}},
+		{SourceProvider: "source", SourceTrack: domain.Track{ExternalID: "sp-a"}, DestProvider: "dest"},
+		{SourceProvider: "source", SourceTrack: domain.Track{ExternalID: "sp-a"}, DestProvider: "dest",
+			Expected: &domain.Track{ExternalID: "vid-a"}, Rejected: []domain.Track{{ExternalID: "vid-a"}}},
+	} {
+		_, err := svc.CurateMatch(ctx, invalid)
+		assert.ErrorIs(t, err, domain.ErrInvalidGoldenMatch)
+	}
+}
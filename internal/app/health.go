@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// HealthChecker implements ports.HealthChecker by pinging every registered
+// provider that implements ports.Pinger. Providers without it are not
+// reported.
+type HealthChecker struct {
+	registry *adapters.ProviderRegistry
+	timeout  time.Duration
+}
+
+// NewHealthChecker creates a checker that gives each provider ping at most
+// timeout to complete.
+func NewHealthChecker(registry *adapters.ProviderRegistry, timeout time.Duration) *HealthChecker {
+	return &HealthChecker{registry: registry, timeout: timeout}
+}
+
+// Check pings all providers concurrently and derives the overall status.
+func (h *HealthChecker) Check(ctx context.Context) *domain.HealthReport {
+	names := h.registry.Available()
+	sort.Strings(names)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results []domain.ProviderHealth
+	)
+	for _, name := range names {
+		provider, err := h.registry.Get(name)
+		if err != nil {
+			continue
+		}
+		pinger, ok := provider.(ports.Pinger)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, pinger ports.Pinger) {
+			defer wg.Done()
+			health := h.ping(ctx, name, pinger)
+			mu.Lock()
+			results = append(results, health)
+			mu.Unlock()
+		}(name, pinger)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return &domain.HealthReport{
+		Status:    overallStatus(results),
+		Providers: results,
+	}
+}
+
+func (h *HealthChecker) ping(ctx context.Context, name string, pinger ports.Pinger) domain.ProviderHealth {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := pinger.Ping(ctx)
+	health := domain.ProviderHealth{
+		Name:      name,
+		Status:    domain.HealthOK,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		health.Status = domain.HealthDown
+		health.Error = err.Error()
+	}
+	return health
+}
+
+// overallStatus is ok when every provider is up, down when all are down and
+// degraded otherwise. With no pingable providers the API itself is ok.
+func overallStatus(providers []domain.ProviderHealth) domain.HealthStatus {
+	down := 0
+	for _, p := range providers {
+		if p.Status == domain.HealthDown {
+			down++
+		}
+	}
+	switch {
+	case down == 0:
+		return domain.HealthOK
+	case down == len(providers):
+		return domain.HealthDown
+	default:
+		return domain.HealthDegraded
+	}
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// pingProvider is a mockProvider whose API connectivity can be checked.
+type pingProvider struct {
+	*mockProvider
+	err error
+}
+
+func (p *pingProvider) Ping(_ context.Context) error {
+	return p.err
+}
+
+func TestHealthChecker_Check(t *testing.T) {
+	tests := []struct {
+		name   string
+		errs   []error
+		status domain.HealthStatus
+	}{
+		{"all up", []error{nil, nil}, domain.HealthOK},
+		{"some down", []error{nil, errors.New("timeout")}, domain.HealthDegraded},
+		{"all down", []error{errors.New("timeout"), errors.New("timeout")}, domain.HealthDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := adapters.NewProviderRegistry()
+			registry.Register(&pingProvider{mockProvider: &mockProvider{name: "a"}, err: tt.errs[0]})
+			registry.Register(&pingProvider{mockProvider: &mockProvider{name: "b"}, err: tt.errs[1]})
+
+			report := NewHealthChecker(registry, time.Second).Check(context.Background())
+
+			assert.Equal(t, tt.status, report.Status)
+			assert.Len(t, report.Providers, 2)
+			assert.Equal(t, "a", report.Providers[0].Name)
+		})
+	}
+}
+
+func TestHealthChecker_SkipsProvidersWithoutPing(t *testing.T) {
+	registry := adapters.NewProviderRegistry()
+	registry.Register(&mockProvider{name: "plain"})
+
+	report := NewHealthChecker(registry, time.Second).Check(context.Background())
+
+	assert.Equal(t, domain.HealthOK, report.Status)
+	assert.Empty(t, report.Providers)
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// hookTimeout bounds a single run of a post-migration hook.
+const hookTimeout = 30 * time.Second
+
+// WithHooks registers actions to run after each completed migration. Hooks
+// run in the background once the result is stored, so they never delay or
+// fail the migration; their errors are logged.
+func WithHooks(hooks ...ports.MigrationHook) Option {
+	return func(s *Service) {
+		s.hooks = append(s.hooks, hooks...)
+	}
+}
+
+// runHooks starts every hook for result. Dry runs have nothing to act on
+// and are skipped.
+func (s *Service) runHooks(ctx context.Context, result *domain.MigrationResult) {
+	if result.DryRun {
+		return
+	}
+	// The hooks outlive the request, so they only keep its values.
+	ctx = context.WithoutCancel(ctx)
+	for _, hook := range s.hooks {
+		go func() {
+			hookCtx, cancel := context.WithTimeout(ctx, hookTimeout)
+			defer cancel()
+			if err := hook.Run(hookCtx, result); err != nil {
+				log.Printf("[hooks] %s failed for migration %s: %v", hook.Name(), result.ID, err)
+			}
+		}()
+	}
+}
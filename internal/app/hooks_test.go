@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHook sends every result it runs for on ran and returns err.
+type recordingHook struct {
+	ran chan *domain.MigrationResult
+	err error
+}
+
+func (h *recordingHook) Name() string { return "recording" }
+
+func (h *recordingHook) Run(ctx context.Context, result *domain.MigrationResult) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	h.ran <- result
+	return h.err
+}
+
+func newHookService(hooks ...*recordingHook) *Service {
+	source := &mockProvider{
+		name:   "source",
+		tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+	}
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "dest-pl",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {track: &domain.Track{Name: "Track A", ExternalID: "a"}, score: 0.9},
+		},
+	}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	var opts []Option
+	for _, h := range hooks {
+		opts = append(opts, WithHooks(h))
+	}
+	return newService(registry, 1, opts...)
+}
+
+var hookRequest = domain.MigrationRequest{
+	SourceProvider: "source",
+	SourceToken:    "t1",
+	DestProvider:   "dest",
+	DestToken:      "t2",
+	PlaylistID:     "pl-1",
+}
+
+func TestMigratePlaylist_RunsHooks(t *testing.T) {
+	failing := &recordingHook{ran: make(chan *domain.MigrationResult, 1), err: errors.New("boom")}
+	ok := &recordingHook{ran: make(chan *domain.MigrationResult, 1)}
+	svc := newHookService(failing, ok)
+
+	// The request context is canceled as soon as the response is written;
+	// hooks must not be.
+	ctx, cancel := context.WithCancel(context.Background())
+	result, err := svc.MigratePlaylist(ctx, hookRequest)
+	cancel()
+	require.NoError(t, err, "hook errors must not fail the migration")
+
+	for _, h := range []*recordingHook{failing, ok} {
+		select {
+		case got := <-h.ran:
+			assert.Equal(t, result.ID, got.ID)
+		case <-time.After(time.Second):
+			t.Fatal("hook did not run")
+		}
+	}
+}
+
+func TestMigratePlaylist_DryRunSkipsHooks(t *testing.T) {
+	hook := &recordingHook{ran: make(chan *domain.MigrationResult, 1)}
+	svc := newHookService(hook)
+
+	req := hookRequest
+	req.DryRun = true
+	_, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+
+	select {
+	case <-hook.ran:
+		t.Fatal("hook ran for a dry run")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
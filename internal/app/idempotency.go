@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// idempotencyScope identifies an idempotency key; keys are only unique
+// within an account.
+type idempotencyScope struct {
+	accountID string
+	key       string
+}
+
+// claimIdempotencyKey returns the stored migration created with
+// req.IdempotencyKey if there is one. Otherwise it marks the key as in flight
+// until release is called, so concurrent retries get
+// domain.ErrMigrationInProgress instead of running the migration twice.
+// Failed migrations are not stored, so their key can be retried.
+func (s *Service) claimIdempotencyKey(ctx context.Context, req domain.MigrationRequest) (existing *domain.MigrationResult, release func(), err error) {
+	scope := idempotencyScope{accountID: domain.AccountIDFromContext(ctx), key: req.IdempotencyKey}
+
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+
+	if s.inflight[scope] {
+		return nil, nil, domain.ErrMigrationInProgress
+	}
+
+	existing, err = s.store.GetByIdempotencyKey(ctx, scope.accountID, scope.key)
+	switch {
+	case err == nil:
+		if existing.SourceProvider != req.SourceProvider || existing.DestProvider != req.DestProvider ||
+			existing.SourcePlaylist != req.PlaylistID || existing.DryRun != req.DryRun {
+			return nil, nil, domain.ErrIdempotencyKeyReused
+		}
+		return existing, nil, nil
+	case !errors.Is(err, domain.ErrMigrationNotFound):
+		return nil, nil, err
+	}
+
+	s.inflight[scope] = true
+	return nil, func() {
+		s.inflightMu.Lock()
+		delete(s.inflight, scope)
+		s.inflightMu.Unlock()
+	}, nil
+}
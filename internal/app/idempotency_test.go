@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdempotencyService() (*Service, *mockProvider) {
+	source := &mockProvider{
+		name:   "source",
+		tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+	}
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "dest-pl",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {
+				track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+				score: 0.9,
+			},
+		},
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+	return newService(registry, 1), dest
+}
+
+func TestMigratePlaylist_IdempotencyKeyReturnsOriginal(t *testing.T) {
+	svc, dest := newIdempotencyService()
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "alice"})
+	req := domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		IdempotencyKey: "key-1",
+	}
+
+	first, err := svc.MigratePlaylist(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", first.IdempotencyKey)
+
+	second, err := svc.MigratePlaylist(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, []string{"vid-a"}, dest.addedTracks, "destination must only be written once")
+
+	// Keys are scoped to the account.
+	bob := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "bob"})
+	other, err := svc.MigratePlaylist(bob, req)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, other.ID)
+}
+
+func TestMigratePlaylist_IdempotencyKeyReused(t *testing.T) {
+	svc, _ := newIdempotencyService()
+	req := domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		IdempotencyKey: "key-1",
+	}
+
+	_, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+
+	req.PlaylistID = "pl-2"
+	_, err = svc.MigratePlaylist(context.Background(), req)
+	assert.ErrorIs(t, err, domain.ErrIdempotencyKeyReused)
+}
+
+func TestMigratePlaylist_IdempotencyKeyInProgress(t *testing.T) {
+	svc, _ := newIdempotencyService()
+	ctx := context.Background()
+	req := domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		IdempotencyKey: "key-1",
+	}
+
+	existing, release, err := svc.claimIdempotencyKey(ctx, req)
+	require.NoError(t, err)
+	require.Nil(t, existing)
+
+	_, err = svc.MigratePlaylist(ctx, req)
+	assert.ErrorIs(t, err, domain.ErrMigrationInProgress)
+
+	release()
+	_, err = svc.MigratePlaylist(ctx, req)
+	assert.NoError(t, err)
+}
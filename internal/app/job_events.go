@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// jobEventBuffer is how many events a watcher may fall behind before
+// further events are dropped for it.
+const jobEventBuffer = 64
+
+// jobEvents fans out events of the jobs running in this process to their
+// watchers. It is safe for concurrent use.
+type jobEvents struct {
+	mu       sync.Mutex
+	watchers map[string]map[chan domain.JobEvent]struct{}
+}
+
+func newJobEvents() *jobEvents {
+	return &jobEvents{watchers: make(map[string]map[chan domain.JobEvent]struct{})}
+}
+
+// subscribe registers a watcher of job id. The returned channel receives
+// the job's events until unsubscribe is called.
+func (e *jobEvents) subscribe(id string) (events <-chan domain.JobEvent, unsubscribe func()) {
+	ch := make(chan domain.JobEvent, jobEventBuffer)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.watchers[id] == nil {
+		e.watchers[id] = make(map[chan domain.JobEvent]struct{})
+	}
+	e.watchers[id][ch] = struct{}{}
+
+	return ch, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.watchers[id], ch)
+		if len(e.watchers[id]) == 0 {
+			delete(e.watchers, id)
+		}
+	}
+}
+
+// publish sends ev to the watchers of job id. Watchers that fell behind
+// miss the event rather than stall the migration.
+func (e *jobEvents) publish(id string, ev domain.JobEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for ch := range e.watchers[id] {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// searchListener follows the search pass of a migration. It is carried in
+// the migration context, so a job can report the progress of the migration
+// it runs.
+type searchListener interface {
+	// searchStarted is called before the first track is searched, with the
+	// number of tracks queued by then.
+	searchStarted(ctx context.Context, total int)
+
+	// trackSearched is called for each track whose search finished, with
+	// the number of tracks searched so far and of tracks queued so far,
+	// which grows while a streamed source playlist is still being fetched.
+	trackSearched(ctx context.Context, result domain.TrackResult, done, total int)
+}
+
+type searchListenerKey struct{}
+
+func withSearchListener(ctx context.Context, l searchListener) context.Context {
+	return context.WithValue(ctx, searchListenerKey{}, l)
+}
+
+func searchListenerFromContext(ctx context.Context) searchListener {
+	l, _ := ctx.Value(searchListenerKey{}).(searchListener)
+	return l
+}
+
+// jobProgressInterval is how often at most the progress of a running job is
+// saved to the queue. Watchers in this process see every track.
+const jobProgressInterval = time.Second
+
+// jobProgress follows the search pass of a running job: it publishes a
+// track event per searched track and saves the progress to the queue, so
+// the job status reports it on every instance.
+type jobProgress struct {
+	job    *domain.Job
+	queue  ports.JobQueue
+	events *jobEvents
+	now    func() time.Time
+
+	started  time.Time
+	saved    time.Time
+	progress *domain.JobProgress
+}
+
+func (p *jobProgress) searchStarted(ctx context.Context, total int) {
+	p.started = p.now()
+	p.progress = &domain.JobProgress{Total: total}
+	if total == 0 {
+		p.progress.Percent = 100
+	}
+	p.save(ctx, true)
+}
+
+func (p *jobProgress) trackSearched(ctx context.Context, result domain.TrackResult, done, total int) {
+	p.progress.Processed, p.progress.Total = done, total
+	if result.Status == domain.TrackStatusMatched {
+		p.progress.Matched++
+	} else {
+		p.progress.Failed++
+	}
+	p.progress.Percent = float64(done) * 100 / float64(p.progress.Total)
+
+	// Project the remaining searches at the throughput seen so far.
+	now := p.now()
+	elapsed := now.Sub(p.started)
+	if elapsed > 0 {
+		p.progress.TracksPerSecond = float64(done) / elapsed.Seconds()
+	}
+	eta := now.Add(elapsed * time.Duration(p.progress.Total-done) / time.Duration(done)).UTC()
+	p.progress.EstimatedCompletion = &eta
+
+	progress := *p.progress
+	p.events.publish(p.job.ID, domain.JobEvent{
+		Type:     domain.JobEventTrack,
+		Track:    &result,
+		Progress: &progress,
+	})
+	p.save(ctx, done == p.progress.Total)
+}
+
+// save stores the progress in the queue if force is set or the last save
+// is at least jobProgressInterval ago.
+func (p *jobProgress) save(ctx context.Context, force bool) {
+	now := p.now()
+	if !force && now.Sub(p.saved) < jobProgressInterval {
+		return
+	}
+	p.saved = now
+
+	err := p.queue.SaveProgress(ctx, p.job.ID, p.job.LeaseOwner, *p.progress)
+	if err != nil && !errors.Is(err, domain.ErrJobLeaseLost) && ctx.Err() == nil {
+		log.Printf("[jobs] failed to save progress of job %s: %v", p.job.ID, err)
+	}
+}
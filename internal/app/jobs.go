@@ -0,0 +1,555 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+const (
+	// jobLease is how long a worker holds a job without renewing it. A
+	// worker that dies loses its jobs to other workers after this long.
+	jobLease = time.Minute
+
+	// jobPollInterval is how long an idle worker waits before asking the
+	// queue for a job again.
+	jobPollInterval = time.Second
+
+	// maxJobAttempts bounds how often a job is leased, so a job that keeps
+	// crashing its worker is eventually marked failed.
+	maxJobAttempts = 3
+)
+
+// errJobAbandoned cancels a migration whose job was canceled or taken over
+// by another worker.
+var errJobAbandoned = errors.New("job abandoned")
+
+// JobService implements ports.JobService on a ports.JobQueue and runs the
+// queued migrations with a Service. Several instances, in one or several
+// processes, can share a queue.
+type JobService struct {
+	service *Service
+	queue   ports.JobQueue
+
+	// owner identifies this instance in job leases.
+	owner string
+
+	lease        time.Duration
+	pollInterval time.Duration
+
+	// events carries the progress of jobs running in this process to
+	// watchers; running holds the cancel functions of their migrations.
+	events    *jobEvents
+	runningMu sync.Mutex
+	running   map[string]context.CancelCauseFunc
+
+	notifiers []ports.JobNotifier
+
+	// vault, if set, seals the tokens of queued jobs.
+	vault *TokenVault
+}
+
+// JobOption configures optional behavior of a JobService.
+type JobOption func(*JobService)
+
+// WithJobNotifiers registers notifiers told about every job that succeeded
+// or failed. Like hooks, they run in the background and their errors are
+// logged.
+func WithJobNotifiers(notifiers ...ports.JobNotifier) JobOption {
+	return func(j *JobService) {
+		j.notifiers = append(j.notifiers, notifiers...)
+	}
+}
+
+// WithJobTokenVault encrypts the provider tokens of queued jobs with the
+// vault key, so a persistent queue only stores ciphertext. Without it,
+// tokens are stored as given, which only suits the in-memory queue.
+func WithJobTokenVault(vault *TokenVault) JobOption {
+	return func(j *JobService) {
+		j.vault = vault
+	}
+}
+
+// NewJobService creates a job service that queues migrations on queue and
+// runs them with service once Run is called.
+func NewJobService(service *Service, queue ports.JobQueue, opts ...JobOption) *JobService {
+	j := &JobService{
+		service:      service,
+		queue:        queue,
+		owner:        newInstanceID(),
+		lease:        jobLease,
+		pollInterval: jobPollInterval,
+		events:       newJobEvents(),
+		running:      make(map[string]context.CancelCauseFunc),
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
+
+// EnqueueMigration checks that the providers and the filter of req are valid
+// and queues it for the caller's account, as an interactive job unless the
+// request asks for bulk priority. A request with the idempotency key of a
+// job the account already queued returns that job. Unless the request has
+// an idempotency key, the job ID is used as one, so a job run again after
+// its worker died returns the migration the first run stored instead of
+// migrating twice.
+func (j *JobService) EnqueueMigration(ctx context.Context, req domain.MigrationRequest) (*domain.Job, error) {
+	return j.enqueue(ctx, domain.JobPlaylist, req)
+}
+
+// EnqueueAccountMigration checks req like EnqueueMigration and queues one
+// bulk job for the caller's account running the stages of an account
+// migration; see domain.JobAccount. The job counts once against the
+// concurrent jobs of the account, and each playlist it migrates against its
+// daily migrations.
+func (j *JobService) EnqueueAccountMigration(ctx context.Context, req domain.AccountMigrationRequest) (*domain.Job, error) {
+	return j.enqueue(ctx, domain.JobAccount, req.MigrationRequest())
+}
+
+// enqueue queues a job of kind running req, as described on
+// EnqueueMigration.
+func (j *JobService) enqueue(ctx context.Context, kind domain.JobKind, req domain.MigrationRequest) (*domain.Job, error) {
+	if err := j.checkRequest(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.IdempotencyKey != "" {
+		existing, err := j.queue.GetByIdempotencyKey(ctx, domain.AccountIDFromContext(ctx), req.IdempotencyKey)
+		if err == nil {
+			return sameJob(existing, kind, req)
+		}
+		if !errors.Is(err, domain.ErrJobNotFound) {
+			return nil, fmt.Errorf("failed to look up job: %w", err)
+		}
+	}
+	if err := j.checkJobLimits(ctx, req); err != nil {
+		return nil, err
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = domain.JobInteractive
+	}
+	now := time.Now().UTC()
+	job := &domain.Job{
+		ID:        newID(),
+		AccountID: domain.AccountIDFromContext(ctx),
+		Kind:      kind,
+		Status:    domain.JobQueued,
+		Request:   req,
+		Priority:  priority,
+		Debug:     req.Debug,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if job.Request.IdempotencyKey == "" {
+		job.Request.IdempotencyKey = "job-" + job.ID
+	}
+
+	j.sealTokens(job)
+
+	err := j.queue.Enqueue(ctx, job)
+	if errors.Is(err, domain.ErrJobExists) {
+		// A concurrent request with the same key queued its job first.
+		existing, err := j.queue.GetByIdempotencyKey(ctx, job.AccountID, req.IdempotencyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up job: %w", err)
+		}
+		return sameJob(existing, kind, req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue migration: %w", err)
+	}
+	return job, nil
+}
+
+// checkRequest checks that the providers, the filter and the priority of
+// req are valid and that tokens for both providers are given or stored.
+func (j *JobService) checkRequest(ctx context.Context, req domain.MigrationRequest) error {
+	if _, err := j.service.registry.Get(req.SourceProvider); err != nil {
+		return fmt.Errorf("source provider error: %w", err)
+	}
+	dest, err := j.service.registry.Get(req.DestProvider)
+	if err != nil {
+		return fmt.Errorf("destination provider error: %w", err)
+	}
+	if _, ok := dest.(ports.SourceOnly); ok {
+		return fmt.Errorf("destination provider error: %s: %w", req.DestProvider, domain.ErrSourceOnlyProvider)
+	}
+	if _, err := compileFilter(req.Exclude, req.Genres); err != nil {
+		return err
+	}
+	if !req.Priority.Valid() {
+		return fmt.Errorf("unknown job priority %q", req.Priority)
+	}
+	if err := j.service.requireToken(ctx, req.SourceProvider, req.SourceToken); err != nil {
+		return err
+	}
+	return j.service.requireToken(ctx, req.DestProvider, req.DestToken)
+}
+
+// sameJob returns existing, a job queued with the idempotency key of req,
+// if it is of kind and migrates the same playlist, and
+// domain.ErrIdempotencyKeyReused otherwise.
+func sameJob(existing *domain.Job, kind domain.JobKind, req domain.MigrationRequest) (*domain.Job, error) {
+	queued := existing.Request
+	if existing.Kind != kind || queued.SourceProvider != req.SourceProvider || queued.DestProvider != req.DestProvider ||
+		queued.PlaylistID != req.PlaylistID || queued.DryRun != req.DryRun {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	return existing, nil
+}
+
+// jobTokens is the form in which the provider tokens of a job request are
+// sealed into domain.Job.Tokens.
+type jobTokens struct {
+	Source string `json:"source,omitempty"`
+	Dest   string `json:"dest,omitempty"`
+}
+
+// sealTokens moves the provider tokens of the request of job to
+// job.Tokens, encrypted with the vault key if the service has a vault.
+// The additional data binds them to the job.
+func (j *JobService) sealTokens(job *domain.Job) {
+	if job.Request.SourceToken == "" && job.Request.DestToken == "" {
+		return
+	}
+	// Marshaling a struct of strings cannot fail.
+	data, _ := json.Marshal(jobTokens{Source: job.Request.SourceToken, Dest: job.Request.DestToken})
+	if j.vault != nil {
+		data = j.vault.seal(data, jobAdditionalData(job.ID))
+	}
+	job.Tokens = data
+	job.Request.SourceToken = ""
+	job.Request.DestToken = ""
+}
+
+// openTokens restores the tokens sealed by sealTokens into the request of
+// job.
+func (j *JobService) openTokens(job *domain.Job) error {
+	if len(job.Tokens) == 0 {
+		return nil
+	}
+	data := job.Tokens
+	if j.vault != nil {
+		var err error
+		if data, err = j.vault.open(data, jobAdditionalData(job.ID)); err != nil {
+			return fmt.Errorf("failed to decrypt job tokens: %w", err)
+		}
+	}
+	var tokens jobTokens
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return fmt.Errorf("failed to decode job tokens: %w", err)
+	}
+	job.Request.SourceToken = tokens.Source
+	job.Request.DestToken = tokens.Dest
+	return nil
+}
+
+func jobAdditionalData(jobID string) []byte {
+	return []byte("job/" + jobID)
+}
+
+// GetJob returns a job owned by the caller's account. Jobs of other
+// accounts are reported as not found.
+func (j *JobService) GetJob(ctx context.Context, id string) (*domain.Job, error) {
+	job, err := j.queue.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.AccountID != domain.AccountIDFromContext(ctx) {
+		return nil, domain.ErrJobNotFound
+	}
+	return job, nil
+}
+
+// GetJobDebugLog returns the provider traffic recorded by a job queued in
+// debug mode, of any account. The log is stored once the job finished, so
+// it is empty before.
+func (j *JobService) GetJobDebugLog(ctx context.Context, id string) (*domain.DebugLog, error) {
+	job, err := j.queue.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !job.Debug {
+		return nil, domain.ErrNoDebugLog
+	}
+	if job.DebugLog == nil {
+		return &domain.DebugLog{Exchanges: []domain.ProviderExchange{}}, nil
+	}
+	return job.DebugLog, nil
+}
+
+// CancelJob cancels a queued or running job owned by the caller's account.
+// A migration running in this process is stopped at once; one running in
+// another process stops when its worker next renews the lease.
+func (j *JobService) CancelJob(ctx context.Context, id string) (*domain.Job, error) {
+	if _, err := j.GetJob(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := j.queue.Cancel(ctx, id); err != nil {
+		return nil, err
+	}
+
+	j.runningMu.Lock()
+	if cancel, ok := j.running[id]; ok {
+		cancel(errJobAbandoned)
+	}
+	j.runningMu.Unlock()
+
+	job, err := j.queue.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	j.publishStatus(job)
+	return job, nil
+}
+
+// WatchJob streams the events of a job owned by the caller's account.
+// Track events are only seen for jobs running in this process; status
+// changes made elsewhere are picked up by polling the queue.
+func (j *JobService) WatchJob(ctx context.Context, id string) (<-chan domain.JobEvent, error) {
+	job, err := j.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	events, unsubscribe := j.events.subscribe(id)
+	out := make(chan domain.JobEvent)
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+
+		send := func(ev domain.JobEvent) bool {
+			select {
+			case out <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		status := job.Status
+		if !send(domain.JobEvent{Type: domain.JobEventStatus, Job: job}) || status.Finished() {
+			return
+		}
+
+		ticker := time.NewTicker(j.pollInterval)
+		defer ticker.Stop()
+		for {
+			var ev domain.JobEvent
+			select {
+			case <-ctx.Done():
+				return
+			case ev = <-events:
+			case <-ticker.C:
+				job, err := j.queue.Get(ctx, id)
+				if err != nil || job.Status == status {
+					continue
+				}
+				ev = domain.JobEvent{Type: domain.JobEventStatus, Job: job}
+			}
+
+			if ev.Type == domain.JobEventStatus {
+				if ev.Job.Status == status {
+					continue
+				}
+				status = ev.Job.Status
+			}
+			if !send(ev) || status.Finished() {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Run processes queued jobs with up to concurrency migrations at a time
+// until ctx is cancelled. Jobs interrupted by the cancellation are left
+// leased, so another instance, or this one after a restart, picks them up
+// once the lease expires.
+func (j *JobService) Run(ctx context.Context, concurrency int) {
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			j.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// work leases and runs jobs one at a time until ctx is cancelled.
+func (j *JobService) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := j.queue.Lease(ctx, j.owner, j.lease)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[jobs] failed to lease job: %v", err)
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(j.pollInterval):
+			}
+			continue
+		}
+		j.runJob(ctx, job)
+	}
+}
+
+// runJob runs a leased job, renewing its lease until the migration
+// finishes, and records the outcome.
+func (j *JobService) runJob(ctx context.Context, job *domain.Job) {
+	if job.Attempts > maxJobAttempts {
+		job.Status = domain.JobFailed
+		job.Error = fmt.Sprintf("gave up after %d attempts", maxJobAttempts)
+		j.finish(job, nil)
+		return
+	}
+	if err := j.openTokens(job); err != nil {
+		job.Status = domain.JobFailed
+		job.Error = err.Error()
+		j.finish(job, nil)
+		return
+	}
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if job.AccountID != "" {
+		runCtx = domain.ContextWithAccount(runCtx, &domain.Account{ID: job.AccountID})
+	}
+	// Account jobs report their stages rather than the search pass of each
+	// migration they run.
+	progress := &jobProgress{job: job, queue: j.queue, events: j.events, now: time.Now}
+	accountProgress := newAccountProgress(job, j.queue, j.events)
+	if job.Kind != domain.JobAccount {
+		runCtx = withSearchListener(runCtx, progress)
+	}
+	var capture *domain.DebugCapture
+	if job.Debug {
+		runCtx, capture = domain.ContextWithDebugCapture(runCtx)
+	}
+
+	j.runningMu.Lock()
+	j.running[job.ID] = cancel
+	j.runningMu.Unlock()
+	defer func() {
+		j.runningMu.Lock()
+		delete(j.running, job.ID)
+		j.runningMu.Unlock()
+	}()
+	j.publishStatus(job)
+
+	var renewals sync.WaitGroup
+	renewals.Add(1)
+	go func() {
+		defer renewals.Done()
+		j.renewLease(runCtx, cancel, job)
+	}()
+
+	log.Printf("[jobs] running job %s (attempt %d)", job.ID, job.Attempts)
+	var (
+		result *domain.MigrationResult
+		err    error
+	)
+	if job.Kind == domain.JobAccount {
+		err = j.runAccountMigration(runCtx, job, accountProgress)
+	} else {
+		result, err = j.service.MigratePlaylist(runCtx, job.Request)
+	}
+	cancel(nil)
+	renewals.Wait()
+
+	if ctx.Err() != nil {
+		log.Printf("[jobs] job %s interrupted by shutdown, it will be retried", job.ID)
+		return
+	}
+	if errors.Is(context.Cause(runCtx), errJobAbandoned) {
+		log.Printf("[jobs] job %s was canceled or taken over, dropping its outcome", job.ID)
+		return
+	}
+	if job.Kind == domain.JobAccount {
+		job.Progress = accountProgress.snapshot()
+	} else if progress.progress != nil {
+		job.Progress = progress.progress
+	}
+	if capture != nil {
+		job.DebugLog = capture.Log()
+	}
+	if err != nil {
+		job.Status = domain.JobFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = domain.JobSucceeded
+		if result != nil {
+			job.MigrationID = result.ID
+		}
+	}
+	j.finish(job, result)
+}
+
+// renewLease extends the lease on job every third of the lease duration
+// until ctx is done. If the lease is lost, it cancels the migration through
+// cancel, since the job was canceled or another worker has taken it over.
+func (j *JobService) renewLease(ctx context.Context, cancel context.CancelCauseFunc, job *domain.Job) {
+	ticker := time.NewTicker(j.lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := j.queue.Renew(ctx, job.ID, j.owner, j.lease)
+			if errors.Is(err, domain.ErrJobLeaseLost) || errors.Is(err, domain.ErrJobNotFound) {
+				log.Printf("[jobs] lost lease on job %s, abandoning it", job.ID)
+				cancel(errJobAbandoned)
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				log.Printf("[jobs] failed to renew lease on job %s: %v", job.ID, err)
+			}
+		}
+	}
+}
+
+// finish records the outcome of job, whose migration is result if it
+// succeeded, and notifies the requester. It does not use the worker
+// context, so a result is not lost to a shutdown that starts just after the
+// migration finished.
+func (j *JobService) finish(job *domain.Job, result *domain.MigrationResult) {
+	if err := j.queue.Finish(context.Background(), job); err != nil {
+		log.Printf("[jobs] failed to record outcome of job %s: %v", job.ID, err)
+		return
+	}
+	j.publishStatus(job)
+	j.notify(job, result)
+}
+
+// notify starts every notifier for a job that is over.
+func (j *JobService) notify(job *domain.Job, result *domain.MigrationResult) {
+	finished := *job
+	for _, notifier := range j.notifiers {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+			defer cancel()
+			if err := notifier.NotifyJob(ctx, &finished, result); err != nil {
+				log.Printf("[jobs] %s notification failed for job %s: %v", notifier.Name(), job.ID, err)
+			}
+		}()
+	}
+}
+
+// publishStatus tells the watchers of job in this process its status.
+func (j *JobService) publishStatus(job *domain.Job) {
+	published := *job
+	j.events.publish(job.ID, domain.JobEvent{Type: domain.JobEventStatus, Job: &published})
+}
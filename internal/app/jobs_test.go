@@ -0,0 +1,381 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJobService(queue *memory.JobQueue) *JobService {
+	jobs := NewJobService(newHookService(), queue)
+	jobs.pollInterval = 10 * time.Millisecond
+	return jobs
+}
+
+// waitForJob polls until the job leaves the queued and running states.
+func waitForJob(t *testing.T, jobs *JobService, ctx context.Context, id string) *domain.Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := jobs.GetJob(ctx, id)
+		require.NoError(t, err)
+		if job.Status.Finished() {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("job did not finish")
+	return nil
+}
+
+func TestJobService_RunsQueuedMigration(t *testing.T) {
+	queue := memory.NewJobQueue()
+	jobs := newTestJobService(queue)
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc"})
+
+	job, err := jobs.EnqueueMigration(ctx, hookRequest)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobQueued, job.Status)
+	assert.Equal(t, "acc", job.AccountID)
+
+	runCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go jobs.Run(runCtx, 2)
+
+	done := waitForJob(t, jobs, ctx, job.ID)
+	assert.Equal(t, domain.JobSucceeded, done.Status)
+	assert.Equal(t, 1, done.Attempts)
+
+	result, err := jobs.service.GetMigration(ctx, done.MigrationID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.MatchedTracks)
+	assert.Equal(t, "job-"+job.ID, result.IdempotencyKey)
+
+	_, err = jobs.GetJob(context.Background(), job.ID)
+	assert.ErrorIs(t, err, domain.ErrJobNotFound, "other accounts cannot see the job")
+}
+
+func TestJobService_RecordsFailure(t *testing.T) {
+	jobs := newTestJobService(memory.NewJobQueue())
+	ctx := context.Background()
+
+	// A key already used for another playlist makes the migration fail.
+	req := hookRequest
+	req.IdempotencyKey = "key"
+	other := req
+	other.PlaylistID = "pl-2"
+	_, err := jobs.service.MigratePlaylist(ctx, other)
+	require.NoError(t, err)
+
+	job, err := jobs.EnqueueMigration(ctx, req)
+	require.NoError(t, err)
+
+	runCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go jobs.Run(runCtx, 1)
+
+	done := waitForJob(t, jobs, ctx, job.ID)
+	assert.Equal(t, domain.JobFailed, done.Status)
+	assert.Contains(t, done.Error, domain.ErrIdempotencyKeyReused.Error())
+}
+
+// notification is a job notifier call recorded by chanNotifier.
+type notification struct {
+	job    *domain.Job
+	result *domain.MigrationResult
+}
+
+type chanNotifier chan notification
+
+func (n chanNotifier) Name() string { return "chan" }
+
+func (n chanNotifier) NotifyJob(_ context.Context, job *domain.Job, result *domain.MigrationResult) error {
+	n <- notification{job, result}
+	return nil
+}
+
+func TestJobService_NotifiesRequester(t *testing.T) {
+	notified := make(chanNotifier, 2)
+	jobs := NewJobService(newHookService(), memory.NewJobQueue(), WithJobNotifiers(notified))
+	jobs.pollInterval = 10 * time.Millisecond
+	ctx := context.Background()
+
+	req := hookRequest
+	req.NotifyEmail = "listener@example.com"
+	req.IdempotencyKey = "key"
+	succeeded, err := jobs.EnqueueMigration(ctx, req)
+	require.NoError(t, err)
+
+	runCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go jobs.Run(runCtx, 1)
+
+	n := <-notified
+	assert.Equal(t, succeeded.ID, n.job.ID)
+	assert.Equal(t, domain.JobSucceeded, n.job.Status)
+	assert.Equal(t, "listener@example.com", n.job.Request.NotifyEmail)
+	require.NotNil(t, n.result)
+	assert.Equal(t, n.job.MigrationID, n.result.ID)
+
+	// A key already used for another playlist makes the migration fail.
+	used := req
+	used.IdempotencyKey = "used"
+	used.PlaylistID = "pl-2"
+	_, err = jobs.service.MigratePlaylist(ctx, used)
+	require.NoError(t, err)
+	req.IdempotencyKey = "used"
+	failed, err := jobs.EnqueueMigration(ctx, req)
+	require.NoError(t, err)
+
+	n = <-notified
+	assert.Equal(t, failed.ID, n.job.ID)
+	assert.Equal(t, domain.JobFailed, n.job.Status)
+	assert.Nil(t, n.result)
+}
+
+func TestJobService_StoresDebugLog(t *testing.T) {
+	jobs := newTestJobService(memory.NewJobQueue())
+	ctx := context.Background()
+
+	plain, err := jobs.EnqueueMigration(ctx, hookRequest)
+	require.NoError(t, err)
+	req := hookRequest
+	req.Debug = true
+	req.IdempotencyKey = "debug"
+	debug, err := jobs.EnqueueMigration(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, debug.Debug)
+
+	log, err := jobs.GetJobDebugLog(ctx, debug.ID)
+	require.NoError(t, err)
+	assert.Empty(t, log.Exchanges, "nothing is recorded before the job runs")
+
+	runCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go jobs.Run(runCtx, 1)
+	waitForJob(t, jobs, ctx, plain.ID)
+	waitForJob(t, jobs, ctx, debug.ID)
+
+	log, err = jobs.GetJobDebugLog(ctx, debug.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, log.Exchanges)
+	assert.Contains(t, log.Exchanges[0].URL, "search:")
+
+	_, err = jobs.GetJobDebugLog(ctx, plain.ID)
+	assert.ErrorIs(t, err, domain.ErrNoDebugLog)
+	_, err = jobs.GetJobDebugLog(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrJobNotFound)
+}
+
+func TestJobService_EnqueueValidatesProviders(t *testing.T) {
+	jobs := newTestJobService(memory.NewJobQueue())
+
+	req := hookRequest
+	req.DestProvider = "unknown"
+	_, err := jobs.EnqueueMigration(context.Background(), req)
+	assert.ErrorIs(t, err, domain.ErrProviderNotFound)
+}
+
+func TestJobService_EnqueueIsIdempotent(t *testing.T) {
+	jobs := newTestJobService(memory.NewJobQueue())
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc"})
+
+	req := hookRequest
+	req.IdempotencyKey = "key"
+	first, err := jobs.EnqueueMigration(ctx, req)
+	require.NoError(t, err)
+
+	again, err := jobs.EnqueueMigration(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID, "a repeated key returns the first job")
+
+	other := req
+	other.PlaylistID = "pl-2"
+	_, err = jobs.EnqueueMigration(ctx, other)
+	assert.ErrorIs(t, err, domain.ErrIdempotencyKeyReused)
+
+	elsewhere := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "other"})
+	job, err := jobs.EnqueueMigration(elsewhere, req)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, job.ID, "keys are scoped to the account")
+}
+
+func TestJobService_SealsTokens(t *testing.T) {
+	vault, err := NewTokenVault(memory.NewTokenStore(), testKey)
+	require.NoError(t, err)
+	queue := memory.NewJobQueue()
+	jobs := NewJobService(newHookService(), queue, WithJobTokenVault(vault))
+	jobs.pollInterval = 10 * time.Millisecond
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc"})
+
+	job, err := jobs.EnqueueMigration(ctx, hookRequest)
+	require.NoError(t, err)
+
+	queued, err := queue.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Empty(t, queued.Request.SourceToken)
+	assert.Empty(t, queued.Request.DestToken)
+	require.NotEmpty(t, queued.Tokens)
+	assert.NotContains(t, string(queued.Tokens), hookRequest.DestToken, "tokens are encrypted")
+
+	opened := *queued
+	require.NoError(t, jobs.openTokens(&opened))
+	assert.Equal(t, hookRequest.SourceToken, opened.Request.SourceToken)
+	assert.Equal(t, hookRequest.DestToken, opened.Request.DestToken)
+
+	tampered := *queued
+	tampered.ID = "other"
+	assert.Error(t, jobs.openTokens(&tampered), "sealed tokens are bound to their job")
+
+	runCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go jobs.Run(runCtx, 1)
+
+	done := waitForJob(t, jobs, ctx, job.ID)
+	assert.Equal(t, domain.JobSucceeded, done.Status)
+	assert.Nil(t, done.Tokens, "tokens are cleared once the job finished")
+}
+
+func TestJobService_EnqueuePriority(t *testing.T) {
+	queue := memory.NewJobQueue()
+	jobs := newTestJobService(queue)
+	batch := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "batch"})
+	solo := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "solo"})
+
+	req := hookRequest
+	req.Priority = "urgent"
+	_, err := jobs.EnqueueMigration(batch, req)
+	assert.ErrorContains(t, err, "unknown job priority")
+
+	var bulk []string
+	req.Priority = domain.JobBulk
+	for range 3 {
+		job, err := jobs.EnqueueMigration(batch, req)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobBulk, job.Priority)
+		bulk = append(bulk, job.ID)
+		time.Sleep(time.Millisecond)
+	}
+	interactive, err := jobs.EnqueueMigration(solo, hookRequest)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobInteractive, interactive.Priority)
+
+	leased, err := queue.Lease(context.Background(), "worker", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, leased)
+	assert.Equal(t, interactive.ID, leased.ID, "interactive jobs go before older bulk ones")
+
+	leased, err = queue.Lease(context.Background(), "worker", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, leased)
+	assert.Equal(t, bulk[0], leased.ID)
+}
+
+func TestJobService_GivesUpAfterMaxAttempts(t *testing.T) {
+	queue := memory.NewJobQueue()
+	jobs := newTestJobService(queue)
+	ctx := context.Background()
+
+	job, err := jobs.EnqueueMigration(ctx, hookRequest)
+	require.NoError(t, err)
+
+	// Simulate workers that died while holding the lease.
+	for range maxJobAttempts {
+		leased, err := queue.Lease(ctx, "dead-worker", -time.Second)
+		require.NoError(t, err)
+		require.NotNil(t, leased)
+	}
+
+	runCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go jobs.Run(runCtx, 1)
+
+	done := waitForJob(t, jobs, ctx, job.ID)
+	assert.Equal(t, domain.JobFailed, done.Status)
+	assert.Contains(t, done.Error, "gave up")
+}
+
+func TestJobService_WatchAndCancelRunningJob(t *testing.T) {
+	dest := &slowProvider{mockProvider: &mockProvider{}, slowTrack: "Slow"}
+	jobs := NewJobService(newService(newTimeoutFixture(dest), 2), memory.NewJobQueue())
+	jobs.pollInterval = 10 * time.Millisecond
+	ctx := context.Background()
+
+	job, err := jobs.EnqueueMigration(ctx, timeoutRequest)
+	require.NoError(t, err)
+
+	watchCtx, stopWatch := context.WithTimeout(ctx, 2*time.Second)
+	defer stopWatch()
+	events, err := jobs.WatchJob(watchCtx, job.ID)
+	require.NoError(t, err)
+
+	ev := <-events
+	assert.Equal(t, domain.JobEventStatus, ev.Type)
+	assert.Equal(t, domain.JobQueued, ev.Job.Status)
+
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go jobs.Run(runCtx, 1)
+
+	ev = <-events
+	assert.Equal(t, domain.JobRunning, ev.Job.Status)
+
+	// The fast track finishes while the slow one blocks.
+	ev = <-events
+	require.Equal(t, domain.JobEventTrack, ev.Type)
+	assert.Equal(t, "Fast", ev.Track.SourceTrack.Name)
+	assert.Equal(t, 1, ev.Progress.Processed)
+	assert.Equal(t, 2, ev.Progress.Total)
+	assert.Equal(t, 1, ev.Progress.Matched)
+	assert.Equal(t, 50.0, ev.Progress.Percent)
+	assert.Positive(t, ev.Progress.TracksPerSecond)
+	assert.NotNil(t, ev.Progress.EstimatedCompletion)
+
+	// The job status reports the progress saved with the first track.
+	running, err := jobs.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, running.Progress)
+	assert.Equal(t, 2, running.Progress.Total)
+
+	canceled, err := jobs.CancelJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobCanceled, canceled.Status)
+
+	var last domain.JobEvent
+	for ev := range events {
+		last = ev
+	}
+	require.NotNil(t, last.Job, "the stream ends with the final status")
+	assert.Equal(t, domain.JobCanceled, last.Job.Status)
+
+	_, err = jobs.CancelJob(ctx, job.ID)
+	assert.ErrorIs(t, err, domain.ErrJobFinished)
+
+	// The worker drops the outcome of the canceled migration.
+	time.Sleep(50 * time.Millisecond)
+	stored, err := jobs.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobCanceled, stored.Status)
+}
+
+func TestJobService_CancelChecksOwner(t *testing.T) {
+	jobs := newTestJobService(memory.NewJobQueue())
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc"})
+
+	job, err := jobs.EnqueueMigration(ctx, hookRequest)
+	require.NoError(t, err)
+
+	_, err = jobs.CancelJob(context.Background(), job.ID)
+	assert.ErrorIs(t, err, domain.ErrJobNotFound)
+	_, err = jobs.WatchJob(context.Background(), job.ID)
+	assert.ErrorIs(t, err, domain.ErrJobNotFound)
+
+	canceled, err := jobs.CancelJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobCanceled, canceled.Status)
+}
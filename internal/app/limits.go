@@ -0,0 +1,201 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// LimitService implements ports.LimitService. Limits are kept with the
+// account; accounts without their own get the defaults. Passed to a Service
+// with WithLimits, it makes migrations and jobs enforce them.
+type LimitService struct {
+	accounts ports.AccountStore
+	starts   ports.MigrationStartStore
+	defaults domain.AccountLimits
+	now      func() time.Time
+}
+
+// NewLimitService creates a limit service for the accounts in accounts,
+// applying defaults to those without limits of their own. Migrations count
+// against the daily limit from when they start, recorded in starts.
+func NewLimitService(accounts ports.AccountStore, starts ports.MigrationStartStore, defaults domain.AccountLimits) *LimitService {
+	return &LimitService{accounts: accounts, starts: starts, defaults: defaults, now: time.Now}
+}
+
+// WithLimits enforces the account limits of limits on migrations and on the
+// jobs of a JobService running them. Requests made without an account are
+// not limited.
+func WithLimits(limits *LimitService) Option {
+	return func(s *Service) {
+		s.limits = limits
+	}
+}
+
+func (l *LimitService) GetLimits(ctx context.Context, accountID string) (*domain.AccountLimits, error) {
+	account, err := l.accounts.Get(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return l.limitsOf(account), nil
+}
+
+func (l *LimitService) SetLimits(ctx context.Context, accountID string, limits *domain.AccountLimits) (*domain.AccountLimits, error) {
+	if err := l.accounts.SetLimits(ctx, accountID, limits); err != nil {
+		return nil, err
+	}
+	return l.GetLimits(ctx, accountID)
+}
+
+func (l *LimitService) limitsOf(account *domain.Account) *domain.AccountLimits {
+	if account.Limits != nil {
+		limits := *account.Limits
+		return &limits
+	}
+	limits := l.defaults
+	return &limits
+}
+
+// forContext returns the limits of the account in ctx, or nil if there is
+// none or it is not stored, such as the account of a job queued before it
+// was deleted.
+func (l *LimitService) forContext(ctx context.Context) (*domain.AccountLimits, error) {
+	accountID := domain.AccountIDFromContext(ctx)
+	if accountID == "" {
+		return nil, nil
+	}
+	limits, err := l.GetLimits(ctx, accountID)
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account limits: %w", err)
+	}
+	return limits, nil
+}
+
+// dayEnd returns the end of the UTC day containing t, when daily limits
+// reset.
+func dayEnd(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
+
+// checkLimits checks a migration about to be queued against the daily limit
+// of the account in ctx, without counting it. It returns the limits of the
+// account, nil if there are none. Dry runs do not count against the daily
+// limit.
+func (s *Service) checkLimits(ctx context.Context, req domain.MigrationRequest) (*domain.AccountLimits, error) {
+	if s.limits == nil {
+		return nil, nil
+	}
+	limits, err := s.limits.forContext(ctx)
+	if err != nil || limits == nil || req.DryRun || limits.MigrationsPerDay == 0 {
+		return limits, err
+	}
+
+	now := s.limits.now()
+	resets := dayEnd(now)
+	used, err := s.limits.starts.Count(ctx, domain.AccountIDFromContext(ctx), resets.Add(-24*time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count migrations: %w", err)
+	}
+	if used >= limits.MigrationsPerDay {
+		return nil, &domain.LimitError{Limit: domain.LimitMigrationsPerDay, Max: limits.MigrationsPerDay, ResetsAt: resets}
+	}
+	return limits, nil
+}
+
+// startMigration counts a migration starting now against the limits of the
+// account in ctx. Checking and counting are atomic, so concurrent migrations
+// cannot exceed the daily limit. It returns those limits, nil if there are
+// none, a warning when the migration is the last the account may run today,
+// and the start, which the caller keeps once the migration succeeds and
+// cancels otherwise. Dry runs do not count against the daily limit.
+func (s *Service) startMigration(ctx context.Context, req domain.MigrationRequest) (*domain.AccountLimits, string, *migrationStart, error) {
+	if s.limits == nil {
+		return nil, "", nil, nil
+	}
+	limits, err := s.limits.forContext(ctx)
+	if err != nil || limits == nil || req.DryRun || limits.MigrationsPerDay == 0 {
+		return limits, "", nil, err
+	}
+
+	now := s.limits.now()
+	resets := dayEnd(now)
+	start := &migrationStart{starts: s.limits.starts, accountID: domain.AccountIDFromContext(ctx), id: newID()}
+	used, ok, err := start.starts.Reserve(ctx, start.accountID, start.id, now, resets.Add(-24*time.Hour), limits.MigrationsPerDay)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to count migrations: %w", err)
+	}
+	if !ok {
+		return nil, "", nil, &domain.LimitError{Limit: domain.LimitMigrationsPerDay, Max: limits.MigrationsPerDay, ResetsAt: resets}
+	}
+	if used == limits.MigrationsPerDay {
+		return limits, fmt.Sprintf("this is the last of the %d migrations the account may run today; the limit resets at %s",
+			limits.MigrationsPerDay, resets.Format(time.RFC3339)), start, nil
+	}
+	return limits, "", start, nil
+}
+
+// migrationStart is a migration counted against the daily limit of its
+// account by startMigration. A nil migrationStart counts nothing.
+type migrationStart struct {
+	starts    ports.MigrationStartStore
+	accountID string
+	id        string
+	kept      bool
+}
+
+// keep keeps the migration counted after cancel.
+func (m *migrationStart) keep() {
+	if m != nil {
+		m.kept = true
+	}
+}
+
+// cancel stops counting the migration, unless keep was called, so that
+// migrations that fail do not use up the daily limit.
+func (m *migrationStart) cancel(ctx context.Context) {
+	if m == nil || m.kept {
+		return
+	}
+	if err := m.starts.Cancel(ctx, m.accountID, m.id); err != nil {
+		log.Printf("[migration] failed to cancel migration start %s: %v", m.id, err)
+	}
+}
+
+// checkTrackLimit returns a LimitError if a playlist of n tracks exceeds
+// limits.
+func checkTrackLimit(limits *domain.AccountLimits, n int) error {
+	if limits == nil || limits.MaxTracksPerMigration == 0 || n <= limits.MaxTracksPerMigration {
+		return nil
+	}
+	return &domain.LimitError{Limit: domain.LimitMaxTracksPerMigration, Max: limits.MaxTracksPerMigration}
+}
+
+// checkJobLimits checks a job about to be queued against the limits of the
+// account in ctx: the number of its queued and running jobs, and the
+// migrations it may still run today.
+func (j *JobService) checkJobLimits(ctx context.Context, req domain.MigrationRequest) error {
+	s := j.service
+	if s.limits == nil {
+		return nil
+	}
+	limits, err := s.checkLimits(ctx, req)
+	if err != nil || limits == nil || limits.ConcurrentJobs == 0 {
+		return err
+	}
+	active, err := j.queue.CountActive(ctx, domain.AccountIDFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to count jobs: %w", err)
+	}
+	if active >= limits.ConcurrentJobs {
+		return &domain.LimitError{Limit: domain.LimitConcurrentJobs, Max: limits.ConcurrentJobs}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLimitedService returns a hook service enforcing limits on account
+// "acc", and a context of that account.
+func newLimitedService(t *testing.T, limits *domain.AccountLimits) (*Service, *LimitService, context.Context) {
+	t.Helper()
+	accounts := memory.NewAccountStore()
+	account := &domain.Account{ID: "acc", APIKeyHash: "hash", Limits: limits}
+	require.NoError(t, accounts.Create(context.Background(), account))
+
+	limitService := NewLimitService(accounts, memory.NewMigrationStartStore(), domain.AccountLimits{MigrationsPerDay: 100})
+	svc := newHookService()
+	WithLimits(limitService)(svc)
+	return svc, limitService, domain.ContextWithAccount(context.Background(), account)
+}
+
+func TestLimitService_DefaultsAndOverrides(t *testing.T) {
+	_, limits, ctx := newLimitedService(t, nil)
+
+	got, err := limits.GetLimits(ctx, "acc")
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccountLimits{MigrationsPerDay: 100}, *got)
+
+	got, err = limits.SetLimits(ctx, "acc", &domain.AccountLimits{ConcurrentJobs: 3})
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccountLimits{ConcurrentJobs: 3}, *got, "limits left out are unlimited")
+
+	got, err = limits.SetLimits(ctx, "acc", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 100, got.MigrationsPerDay)
+
+	_, err = limits.GetLimits(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+	_, err = limits.SetLimits(ctx, "missing", &domain.AccountLimits{})
+	assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+}
+
+func TestMigratePlaylist_MigrationsPerDayLimit(t *testing.T) {
+	svc, limits, ctx := newLimitedService(t, &domain.AccountLimits{MigrationsPerDay: 2})
+
+	first, err := svc.MigratePlaylist(ctx, hookRequest)
+	require.NoError(t, err)
+	assert.Empty(t, first.Warnings)
+
+	second, err := svc.MigratePlaylist(ctx, hookRequest)
+	require.NoError(t, err)
+	require.Len(t, second.Warnings, 1)
+	assert.Contains(t, second.Warnings[0], "last of the 2 migrations")
+
+	_, err = svc.MigratePlaylist(ctx, hookRequest)
+	require.ErrorIs(t, err, domain.ErrLimitExceeded)
+	var limitErr *domain.LimitError
+	require.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, domain.LimitMigrationsPerDay, limitErr.Limit)
+	assert.Equal(t, dayEnd(time.Now()), limitErr.ResetsAt)
+
+	dryRun := hookRequest
+	dryRun.DryRun = true
+	_, err = svc.MigratePlaylist(ctx, dryRun)
+	assert.NoError(t, err, "dry runs are not limited")
+
+	_, err = svc.MigratePlaylist(context.Background(), hookRequest)
+	assert.NoError(t, err, "requests without an account are not limited")
+
+	// Migrations of the previous day do not count.
+	limits.now = func() time.Time { return time.Now().Add(24 * time.Hour) }
+	_, err = svc.MigratePlaylist(ctx, hookRequest)
+	assert.NoError(t, err)
+}
+
+func TestMigratePlaylist_ConcurrentMigrationsPerDayLimit(t *testing.T) {
+	svc, _, ctx := newLimitedService(t, &domain.AccountLimits{MigrationsPerDay: 3})
+
+	// Starts are counted before any migration ends, so running them at once
+	// cannot exceed the limit.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	started, limited := 0, 0
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _, err := svc.startMigration(ctx, hookRequest)
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				started++
+			} else if errors.Is(err, domain.ErrLimitExceeded) {
+				limited++
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 3, started)
+	assert.Equal(t, 7, limited)
+}
+
+func TestMigratePlaylist_FailedMigrationsAreNotCounted(t *testing.T) {
+	svc, _, ctx := newLimitedService(t, &domain.AccountLimits{MigrationsPerDay: 1})
+
+	// Another instance is migrating the same playlist.
+	key := playlistLockKey(ctx, hookRequest)
+	ok, err := svc.locker.Acquire(ctx, key, "other-instance", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, err = svc.MigratePlaylist(ctx, hookRequest)
+	require.ErrorIs(t, err, domain.ErrPlaylistLocked)
+	require.NoError(t, svc.locker.Release(ctx, key, "other-instance"))
+
+	_, err = svc.MigratePlaylist(ctx, hookRequest)
+	require.NoError(t, err, "the failed migration must not use up the limit")
+	_, err = svc.MigratePlaylist(ctx, hookRequest)
+	assert.ErrorIs(t, err, domain.ErrLimitExceeded)
+}
+
+func TestMigratePlaylist_MaxTracksLimit(t *testing.T) {
+	svc, _, ctx := newLimitedService(t, &domain.AccountLimits{MaxTracksPerMigration: 1})
+
+	_, err := svc.MigratePlaylist(ctx, hookRequest)
+	require.NoError(t, err)
+
+	source, err := svc.registry.Get("source")
+	require.NoError(t, err)
+	source.(*mockProvider).tracks = append(source.(*mockProvider).tracks, domain.Track{Name: "Track B", Artists: []string{"Artist B"}})
+
+	_, err = svc.MigratePlaylist(ctx, hookRequest)
+	var limitErr *domain.LimitError
+	require.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, domain.LimitMaxTracksPerMigration, limitErr.Limit)
+	assert.True(t, limitErr.ResetsAt.IsZero())
+}
+
+func TestJobService_ConcurrentJobsLimit(t *testing.T) {
+	svc, _, ctx := newLimitedService(t, &domain.AccountLimits{ConcurrentJobs: 1})
+	jobs := NewJobService(svc, memory.NewJobQueue())
+
+	_, err := jobs.EnqueueMigration(ctx, hookRequest)
+	require.NoError(t, err)
+
+	_, err = jobs.EnqueueMigration(ctx, hookRequest)
+	var limitErr *domain.LimitError
+	require.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, domain.LimitConcurrentJobs, limitErr.Limit)
+
+	_, err = jobs.EnqueueMigration(context.Background(), hookRequest)
+	assert.NoError(t, err, "jobs of other accounts do not count")
+}
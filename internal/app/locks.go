@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// playlistLockTTL is how long a playlist lock outlives an instance that
+// stopped refreshing it, e.g. because it crashed.
+const playlistLockTTL = time.Minute
+
+// WithLocker shares playlist locks through locker, so instances using the
+// same backend never migrate the same playlist to the same provider at once.
+// It is required.
+func WithLocker(locker ports.Locker) Option {
+	return func(s *Service) {
+		s.locker = locker
+	}
+}
+
+// newInstanceID identifies a Service or JobService in locks and leases. It
+// is unique across processes and hosts.
+func newInstanceID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), newID()[:8])
+}
+
+// playlistLockKey names the lock of a migration: one per account, source
+// playlist and destination provider.
+func playlistLockKey(ctx context.Context, req domain.MigrationRequest) string {
+	return fmt.Sprintf("migrate/%s/%s/%s/%s",
+		domain.AccountIDFromContext(ctx), req.SourceProvider, req.PlaylistID, req.DestProvider)
+}
+
+// lockPlaylist takes the lock of a migration and keeps refreshing it until
+// release is called. It returns domain.ErrPlaylistLocked if another
+// migration of the playlist to the same provider holds the lock, on this
+// instance or another: each acquisition has an owner of its own, since
+// lockers let an owner take a lock it already holds.
+func (s *Service) lockPlaylist(ctx context.Context, req domain.MigrationRequest) (release func(), err error) {
+	key := playlistLockKey(ctx, req)
+	owner := s.lockOwner + "/" + newID()
+	ok, err := s.locker.Acquire(ctx, key, owner, playlistLockTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock playlist: %w", err)
+	}
+	if !ok {
+		return nil, domain.ErrPlaylistLocked
+	}
+
+	// Refreshing and releasing must outlive a canceled request, or the
+	// lock would stay held until it expires.
+	lockCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(playlistLockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lockCtx.Done():
+				return
+			case <-ticker.C:
+				if ok, err := s.locker.Refresh(lockCtx, key, owner, playlistLockTTL); err != nil || !ok {
+					log.Printf("[migration] failed to refresh lock %s (held: %t): %v", key, ok, err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		stop()
+		<-done
+		if err := s.locker.Release(context.WithoutCancel(ctx), key, owner); err != nil {
+			log.Printf("[migration] failed to release lock %s: %v", key, err)
+		}
+	}, nil
+}
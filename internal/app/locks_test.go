@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigratePlaylist_RejectsLockedPlaylist(t *testing.T) {
+	svc := newHookService()
+	ctx := context.Background()
+
+	// Another instance is migrating the same playlist.
+	key := playlistLockKey(ctx, hookRequest)
+	ok, err := svc.locker.Acquire(ctx, key, "other-instance", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = svc.MigratePlaylist(ctx, hookRequest)
+	assert.ErrorIs(t, err, domain.ErrPlaylistLocked)
+
+	dryRun := hookRequest
+	dryRun.DryRun = true
+	_, err = svc.MigratePlaylist(ctx, dryRun)
+	assert.NoError(t, err, "dry runs are not locked")
+
+	other := hookRequest
+	other.PlaylistID = "pl-2"
+	_, err = svc.MigratePlaylist(ctx, other)
+	assert.NoError(t, err, "other playlists are not locked")
+
+	require.NoError(t, svc.locker.Release(ctx, key, "other-instance"))
+	_, err = svc.MigratePlaylist(ctx, hookRequest)
+	assert.NoError(t, err)
+}
+
+func TestMigratePlaylist_LockedOnSameInstance(t *testing.T) {
+	svc := newHookService()
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc"})
+
+	// A migration of the playlist is running on this Service.
+	release, err := svc.lockPlaylist(ctx, hookRequest)
+	require.NoError(t, err)
+
+	results := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := svc.MigratePlaylist(ctx, hookRequest)
+			results <- err
+		}()
+	}
+	for range 2 {
+		assert.ErrorIs(t, <-results, domain.ErrPlaylistLocked)
+	}
+
+	// Concurrent migrations on the Service exclude each other too, and the
+	// one refused releases nothing.
+	_, err = svc.lockPlaylist(ctx, hookRequest)
+	assert.ErrorIs(t, err, domain.ErrPlaylistLocked)
+
+	release()
+	_, err = svc.MigratePlaylist(ctx, hookRequest)
+	assert.NoError(t, err)
+}
+
+func TestMigratePlaylist_ReleasesLock(t *testing.T) {
+	svc := newHookService()
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc"})
+
+	_, err := svc.MigratePlaylist(ctx, hookRequest)
+	require.NoError(t, err)
+
+	ok, err := svc.locker.Acquire(ctx, playlistLockKey(ctx, hookRequest), "other-instance", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "lock must be released when the migration ends")
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// knownMatch returns the destination counterpart of track if it is in known
+// or, failing that, in the track mapping store and not rejected by users.
+func (s *Service) knownMatch(ctx context.Context, sourceProvider, destProvider string, track domain.Track, known map[string]knownMatch) (knownMatch, bool) {
+	if track.ExternalID == "" {
+		return knownMatch{}, false
+	}
+	if match, ok := known[track.ExternalID]; ok {
+		return match, true
+	}
+	// Merges have no single source provider to look the track up under.
+	if s.mappings == nil || sourceProvider == "" {
+		return knownMatch{}, false
+	}
+
+	matched, score, err := s.mappings.Find(ctx, sourceProvider, track.ExternalID, destProvider)
+	if err != nil {
+		if !errors.Is(err, domain.ErrMappingNotFound) {
+			log.Printf("[migration] track mapping lookup failed: %v", err)
+		}
+		return knownMatch{}, false
+	}
+	// Mappings users rejected since they were saved are searched again.
+	if s.matchVerdict(ctx, sourceProvider, destProvider, track, *matched) == domain.MatchRejected {
+		return knownMatch{}, false
+	}
+	return knownMatch{track: *matched, score: score}, true
+}
+
+// saveMappings records every matched search result as a track mapping.
+// Failures are logged; they only cost a search next time. Merges, which have
+// no single source provider, record none.
+func (s *Service) saveMappings(ctx context.Context, sourceProvider, destProvider string, results []domain.TrackResult) {
+	if s.mappings == nil || sourceProvider == "" {
+		return
+	}
+	for _, tr := range results {
+		if !isPlaced(tr) || tr.SourceTrack.ExternalID == "" || tr.MatchedTrack.ExternalID == "" {
+			continue
+		}
+		err := s.mappings.Save(ctx, &domain.TrackMapping{
+			ProviderA: sourceProvider,
+			TrackA:    tr.SourceTrack,
+			ProviderB: destProvider,
+			TrackB:    *tr.MatchedTrack,
+			Score:     tr.ConfidenceScore,
+		})
+		if err != nil {
+			log.Printf("[migration] failed to save track mapping: %v", err)
+		}
+	}
+}
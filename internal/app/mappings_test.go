@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigratePlaylist_ReusesTrackMappings(t *testing.T) {
+	source := &mockProvider{
+		name:      "source",
+		createdID: "source-pl",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "sp-a"},
+			{Name: "Track B", Artists: []string{"Artist B"}, ExternalID: "sp-b"},
+		},
+	}
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "dest-pl",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"}, score: 0.9},
+		},
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1, WithTrackMappings(memory.NewTrackMappingStore()))
+	req := domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	}
+
+	_, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dest.searchCallCount)
+
+	// Track A is now mapped; only the unmatched Track B is searched again.
+	result, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 3, dest.searchCallCount)
+	assert.Equal(t, "vid-a", result.TrackResults[0].MatchedTrack.ExternalID)
+	assert.Equal(t, 0.9, result.TrackResults[0].ConfidenceScore)
+
+	// Mappings work in the opposite direction too.
+	source.searchCallCount = 0
+	dest.tracks = []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"}}
+	back, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "dest",
+		SourceToken:    "t2",
+		DestProvider:   "source",
+		DestToken:      "t1",
+		PlaylistID:     "dest-pl",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, source.searchCallCount)
+	assert.Equal(t, "sp-a", back.TrackResults[0].MatchedTrack.ExternalID)
+}
@@ -0,0 +1,246 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// defaultMergeName is the name of merged playlists whose request sets none.
+const defaultMergeName = "Merged playlist"
+
+// MergePlaylists fetches every source playlist of req, orders their tracks
+// as requested and migrates them into one new destination playlist. Repeats
+// are dropped twice: tracks sharing an ID or ISRC before the search, and
+// tracks the matching engine resolves to the same destination track after
+// it, which catches repeats across providers without a common ISRC.
+func (s *Service) MergePlaylists(ctx context.Context, req domain.MergeRequest) (*domain.MigrationResult, error) {
+	if len(req.Sources) < 2 {
+		return nil, fmt.Errorf("a merge needs at least 2 source playlists, got %d", len(req.Sources))
+	}
+	if !req.Order.Valid() {
+		return nil, fmt.Errorf("unknown merge order %q", req.Order)
+	}
+	if !req.MatchingStrategy.Valid() {
+		return nil, fmt.Errorf("unknown matching strategy %q", req.MatchingStrategy)
+	}
+	if req.MinScore < 0 || req.MinScore > 1 {
+		return nil, fmt.Errorf("min score %.2f is not between 0 and 1", req.MinScore)
+	}
+	started := time.Now()
+	timing := &domain.MigrationTiming{}
+
+	dest, err := s.registry.Get(req.DestProvider)
+	if err != nil {
+		return nil, fmt.Errorf("destination provider error: %w", err)
+	}
+	if _, ok := dest.(ports.SourceOnly); ok {
+		return nil, fmt.Errorf("destination provider error: %s: %w", req.DestProvider, domain.ErrSourceOnlyProvider)
+	}
+
+	// The merge runs through the stages of a migration without a single
+	// source provider.
+	migration := domain.MigrationRequest{
+		DestProvider:     req.DestProvider,
+		DryRun:           req.DryRun,
+		Market:           strings.ToUpper(req.Market),
+		MatchingStrategy: req.MatchingStrategy,
+		MinScore:         req.MinScore,
+	}
+	limits, limitWarning, start, err := s.startMigration(ctx, migration)
+	if err != nil {
+		return nil, err
+	}
+	defer start.cancel(context.WithoutCancel(ctx))
+	if migration.DestToken, err = s.resolveToken(ctx, req.DestProvider, req.DestToken); err != nil {
+		return nil, err
+	}
+	if err := checkToken(ctx, dest, migration.DestToken, !req.DryRun); err != nil {
+		return nil, fmt.Errorf("destination provider error: %w", err)
+	}
+
+	if migration.Market != "" {
+		ctx = domain.ContextWithMarket(ctx, migration.Market)
+	}
+	ctx = domain.ContextWithMatchOptions(ctx, migration.MatchOptions())
+
+	ctx, cancel := s.withDeadline(ctx)
+	defer cancel()
+
+	stageStart := time.Now()
+	lists, err := s.fetchMergeSources(ctx, req)
+	timing.FetchMS = msSince(stageStart)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &migrationRun{req: migration, dest: dest, timing: timing, limits: limits, name: req.Name}
+	if run.name == "" {
+		run.name = defaultMergeName
+	}
+	if limitWarning != "" {
+		run.warn(limitWarning)
+	}
+	var removed int
+	if run.tracks, removed = dedupeTracks(mergeOrder(lists, req.Order)); removed > 0 {
+		run.warn(fmt.Sprintf("merged %d tracks found in more than one source playlist", removed))
+	}
+	if len(run.tracks) == 0 {
+		return nil, fmt.Errorf("source playlists are empty")
+	}
+	if err := checkTrackLimit(limits, len(run.tracks)); err != nil {
+		return nil, err
+	}
+	log.Printf("[migration] merging %d tracks of %d playlists into %s", len(run.tracks), len(req.Sources), req.DestProvider)
+
+	stages := []migrationStage{s.enrichStage, s.matchStage, dropRepeatedMatches, s.writeStage}
+	if err := s.runPipeline(ctx, run, stages); err != nil {
+		return nil, err
+	}
+	start.keep()
+
+	matched, filtered, episodes := countResults(run.results)
+	assignPositions(run.results)
+	timing.TotalMS = msSince(started)
+	log.Printf("[migration] merge complete in %dms (search %dms)", timing.TotalMS, timing.SearchMS)
+
+	result := &domain.MigrationResult{
+		ID:               newID(),
+		AccountID:        domain.AccountIDFromContext(ctx),
+		DestProvider:     req.DestProvider,
+		TotalTracks:      len(run.results),
+		TotalEpisodes:    episodes,
+		MatchedTracks:    matched,
+		FailedTracks:     len(run.results) - matched - filtered,
+		FilteredTracks:   filtered,
+		ErrorBreakdown:   errorBreakdown(run.results),
+		DryRun:           req.DryRun,
+		Market:           migration.Market,
+		MatchingStrategy: req.MatchingStrategy,
+		MinScore:         req.MinScore,
+		CreatedAt:        time.Now().UTC(),
+		TrackResults:     run.results,
+		Warnings:         run.warnings,
+		Concurrency:      run.concurrency,
+		Timing:           timing,
+		MergedFrom:       req.Sources,
+	}
+	result.DestPlaylistName, result.DestPlaylistDescription = run.destName, run.destDescription
+	if len(run.destPlaylistIDs) > 0 {
+		result.DestPlaylistID = run.destPlaylistIDs[0]
+	}
+	if len(run.destPlaylistIDs) > 1 {
+		result.DestPlaylistIDs = run.destPlaylistIDs
+	}
+	if run.quotaUsed > 0 {
+		result.QuotaUnitsUsed = map[string]int{req.DestProvider: run.quotaUsed}
+	}
+
+	if err := s.store.Save(context.WithoutCancel(ctx), result); err != nil {
+		log.Printf("[migration] failed to store migration %s: %v", result.ID, err)
+	}
+	s.runHooks(ctx, result)
+
+	return result, nil
+}
+
+// fetchMergeSources fetches the tracks of every source playlist of req, in
+// request order. The token of each source provider is resolved and checked
+// once.
+func (s *Service) fetchMergeSources(ctx context.Context, req domain.MergeRequest) ([][]domain.Track, error) {
+	tokens := make(map[string]string)
+	lists := make([][]domain.Track, len(req.Sources))
+	for i, src := range req.Sources {
+		source, err := s.registry.Get(src.Provider)
+		if err != nil {
+			return nil, fmt.Errorf("source provider error: %w", err)
+		}
+		token, ok := tokens[src.Provider]
+		if !ok {
+			if token, err = s.resolveToken(ctx, src.Provider, req.SourceTokens[src.Provider]); err != nil {
+				return nil, err
+			}
+			if err := checkToken(ctx, source, token, false); err != nil {
+				return nil, fmt.Errorf("source provider error: %w", err)
+			}
+			tokens[src.Provider] = token
+		}
+
+		log.Printf("[migration] fetching tracks from %s playlist %s", src.Provider, src.PlaylistID)
+		err = s.runStage(ctx, domain.StageFetch, func(ctx context.Context) error {
+			var err error
+			lists[i], err = source.GetPlaylistTracks(ctx, token, src.PlaylistID)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tracks of %s playlist %s: %w", src.Provider, src.PlaylistID, err)
+		}
+	}
+	return lists, nil
+}
+
+// mergeOrder joins the track lists of the sources of a merge in order.
+func mergeOrder(lists [][]domain.Track, order domain.MergeOrder) []domain.Track {
+	var tracks []domain.Track
+	if order == domain.MergeInterleave {
+		for i := 0; ; i++ {
+			taken := false
+			for _, list := range lists {
+				if i < len(list) {
+					tracks = append(tracks, list[i])
+					taken = true
+				}
+			}
+			if !taken {
+				return tracks
+			}
+		}
+	}
+
+	for _, list := range lists {
+		tracks = append(tracks, list...)
+	}
+	if order == domain.MergeReleaseDate {
+		// Dates are YYYY, YYYY-MM or YYYY-MM-DD, so they sort as strings.
+		slices.SortStableFunc(tracks, func(a, b domain.Track) int {
+			switch {
+			case a.ReleaseDate == b.ReleaseDate:
+				return 0
+			case a.ReleaseDate == "":
+				return 1
+			case b.ReleaseDate == "":
+				return -1
+			}
+			return strings.Compare(a.ReleaseDate, b.ReleaseDate)
+		})
+	}
+	return tracks
+}
+
+// dropRepeatedMatches removes the tracks of a merge matched to the same
+// destination track as an earlier one, such as a song of two providers
+// that report no ISRC.
+func dropRepeatedMatches(_ context.Context, run *migrationRun) error {
+	seen := make(map[string]bool)
+	tracks, results := run.tracks[:0], run.results[:0]
+	for i, tr := range run.results {
+		if isPlaced(tr) {
+			if seen[tr.MatchedTrack.ExternalID] {
+				continue
+			}
+			seen[tr.MatchedTrack.ExternalID] = true
+		}
+		tracks, results = append(tracks, run.tracks[i]), append(results, tr)
+	}
+	if removed := len(run.results) - len(results); removed > 0 {
+		run.warn(fmt.Sprintf("merged %d tracks matched to the same %s track as an earlier one", removed, run.req.DestProvider))
+	}
+	run.tracks, run.results = tracks, results
+	return nil
+}
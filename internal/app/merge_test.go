@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMergeService() (*Service, *mockProvider) {
+	first := &mockProvider{
+		name: "first",
+		tracks: []domain.Track{
+			{Name: "Shared", Artists: []string{"A"}, ISRC: "USAAA0000001", ExternalID: "f1"},
+			{Name: "Only First", Artists: []string{"B"}, ExternalID: "f2"},
+		},
+	}
+	second := &mockProvider{
+		name: "second",
+		tracks: []domain.Track{
+			{Name: "Shared", Artists: []string{"A"}, ISRC: "usaaa0000001", ExternalID: "s1"},
+			{Name: "Only First (Remastered)", Artists: []string{"B"}, ExternalID: "s2"},
+			{Name: "Only Second", Artists: []string{"C"}, ExternalID: "s3"},
+		},
+	}
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "merged-pl",
+		searchResults: map[string]*searchResult{
+			"Shared|A":                  {track: &domain.Track{Name: "Shared", ExternalID: "d1"}, score: 1},
+			"Only First|B":              {track: &domain.Track{Name: "Only First", ExternalID: "d2"}, score: 0.9},
+			"Only First (Remastered)|B": {track: &domain.Track{Name: "Only First", ExternalID: "d2"}, score: 0.8},
+			"Only Second|C":             {track: &domain.Track{Name: "Only Second", ExternalID: "d3"}, score: 0.9},
+		},
+	}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(first)
+	registry.Register(second)
+	registry.Register(dest)
+	return newService(registry, 1), dest
+}
+
+func TestMergePlaylists(t *testing.T) {
+	svc, dest := newMergeService()
+
+	result, err := svc.MergePlaylists(context.Background(), domain.MergeRequest{
+		Sources: []domain.MergeSource{
+			{Provider: "first", PlaylistID: "pl-1"},
+			{Provider: "second", PlaylistID: "pl-2"},
+		},
+		SourceTokens: map[string]string{"first": "t1", "second": "t2"},
+		DestProvider: "dest",
+		DestToken:    "t3",
+		Name:         "Everything",
+		Order:        domain.MergeInterleave,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "merged-pl", result.DestPlaylistID)
+	assert.Equal(t, "Everything", result.DestPlaylistName)
+	assert.Empty(t, result.SourceProvider)
+	assert.Len(t, result.MergedFrom, 2)
+	assert.Equal(t, 3, result.TotalTracks)
+	assert.Equal(t, 3, result.MatchedTracks)
+	assert.Equal(t, []string{"d1", "d2", "d3"}, dest.addedTracks)
+	assert.Equal(t, "Shared", result.TrackResults[0].SourceTrack.Name)
+	assert.Equal(t, "s3", result.TrackResults[2].SourceTrack.ExternalID)
+	assert.Equal(t, 2, result.TrackResults[2].SourcePosition)
+	assert.Equal(t, []string{
+		"merged 1 tracks found in more than one source playlist",
+		"merged 1 tracks matched to the same dest track as an earlier one",
+	}, result.Warnings)
+	assert.Equal(t, 4, dest.searchCallCount, "the repeat sharing an ISRC is not searched")
+
+	stored, err := svc.GetMigration(context.Background(), result.ID)
+	require.NoError(t, err)
+	assert.Equal(t, result.MergedFrom, stored.MergedFrom)
+
+	_, err = svc.ReverseMigration(context.Background(), result.ID, domain.ReverseMigrationRequest{})
+	assert.ErrorContains(t, err, "cannot be reversed")
+}
+
+func TestMergePlaylists_Errors(t *testing.T) {
+	svc, _ := newMergeService()
+	req := domain.MergeRequest{
+		Sources:      []domain.MergeSource{{Provider: "first", PlaylistID: "pl-1"}},
+		SourceTokens: map[string]string{"first": "t1", "second": "t2"},
+		DestProvider: "dest",
+		DestToken:    "t3",
+	}
+
+	_, err := svc.MergePlaylists(context.Background(), req)
+	assert.ErrorContains(t, err, "at least 2 source playlists")
+
+	req.Sources = append(req.Sources, domain.MergeSource{Provider: "missing", PlaylistID: "pl-2"})
+	_, err = svc.MergePlaylists(context.Background(), req)
+	assert.ErrorIs(t, err, domain.ErrProviderNotFound)
+
+	req.Sources[1].Provider = "second"
+	req.Order = "shuffle"
+	_, err = svc.MergePlaylists(context.Background(), req)
+	assert.ErrorContains(t, err, `unknown merge order "shuffle"`)
+}
+
+func TestMergeOrder(t *testing.T) {
+	lists := [][]domain.Track{
+		{{Name: "a1", ReleaseDate: "2001"}, {Name: "a2"}, {Name: "a3", ReleaseDate: "1999-05-01"}},
+		{{Name: "b1", ReleaseDate: "2001-02"}},
+	}
+	names := func(tracks []domain.Track) []string {
+		var names []string
+		for _, track := range tracks {
+			names = append(names, track.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"a1", "a2", "a3", "b1"}, names(mergeOrder(lists, "")))
+	assert.Equal(t, []string{"a1", "a2", "a3", "b1"}, names(mergeOrder(lists, domain.MergeBySource)))
+	assert.Equal(t, []string{"a1", "b1", "a2", "a3"}, names(mergeOrder(lists, domain.MergeInterleave)))
+	assert.Equal(t, []string{"a3", "a1", "b1", "a2"}, names(mergeOrder(lists, domain.MergeReleaseDate)))
+}
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+	"github.com/jpp0ca/MusicMigration-API/pkg/matching"
+)
+
+// MetadataEnrichment configures how migrations fill in the ISRC, duration
+// and album of source tracks that lack them before searching for them,
+// which makes matching them more reliable.
+type MetadataEnrichment struct {
+	// Sources are consulted in order for each track still missing metadata,
+	// after the source provider itself if it can look up tracks by ID. A
+	// source that fails is skipped for the track.
+	Sources []ports.MetadataSource
+
+	// Timeout bounds each single lookup; zero means no timeout.
+	Timeout time.Duration
+
+	// Cache keeps the answers of Sources for CacheTTL, including that a
+	// source does not know a track, so repeated migrations of the same
+	// tracks do not ask again. Without a cache every track is looked up.
+	Cache    ports.SearchCache
+	CacheTTL time.Duration
+}
+
+// WithMetadataEnrichment enables filling in missing metadata of source
+// tracks from the chain of e. Without sources, tracks are matched with the
+// metadata their provider gave.
+func WithMetadataEnrichment(e MetadataEnrichment) Option {
+	return func(s *Service) {
+		s.metadata = e
+	}
+}
+
+// enrichMetadata fills in the missing metadata of the tracks of run at
+// indices, in place: first from the source provider, then from each source
+// of the chain in order, until a track is complete or the chain is
+// exhausted. Tracks are looked up concurrently by the service's workers.
+func (s *Service) enrichMetadata(ctx context.Context, run *migrationRun, indices []int) {
+	if len(s.metadata.Sources) == 0 {
+		return
+	}
+	var missing []int
+	for _, i := range indices {
+		if track := run.tracks[i]; !track.IsEpisode() && track.MissingMetadata() {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	var filled atomic.Int32
+	if lookup, ok := run.source.(ports.TrackLookup); ok {
+		filled.Add(int32(s.sourceMetadata(ctx, run, lookup, missing)))
+	}
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for range min(s.workers, len(missing)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				if s.chainMetadata(ctx, &run.tracks[i]) {
+					filled.Add(1)
+				}
+			}
+		}()
+	}
+	for _, i := range missing {
+		if run.tracks[i].MissingMetadata() {
+			queue <- i
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if n := filled.Load(); n > 0 {
+		log.Printf("[migration] filled in metadata of %d of %d incomplete tracks", n, len(missing))
+	}
+}
+
+// sourceMetadata fills in the metadata of the tracks at indices that have
+// an ID from the source provider's own lookup, in one batch, and returns
+// how many it completed at least in part. A failed lookup leaves the tracks
+// to the chain.
+func (s *Service) sourceMetadata(ctx context.Context, run *migrationRun, lookup ports.TrackLookup, indices []int) int {
+	var ids []string
+	var withID []int
+	for _, i := range indices {
+		if id := run.tracks[i].ExternalID; id != "" {
+			ids = append(ids, id)
+			withID = append(withID, i)
+		}
+	}
+	if len(ids) == 0 {
+		return 0
+	}
+
+	lookupCtx, cancel := s.metadataContext(ctx)
+	defer cancel()
+	found, err := lookup.LookupTracks(lookupCtx, run.req.SourceToken, ids)
+	if err != nil {
+		log.Printf("[migration] %s metadata lookup failed: %v", run.req.SourceProvider, err)
+		return 0
+	}
+	filled := 0
+	for j, i := range withID {
+		if j >= len(found) || found[j] == nil {
+			continue
+		}
+		if run.tracks[i].FillMetadata(trackMetadata(*found[j])) {
+			filled++
+		}
+	}
+	return filled
+}
+
+// chainMetadata fills in the metadata of track from the sources of the
+// chain in order, stopping once it is complete, and reports whether any
+// source added to it. Each source sees what earlier ones filled in, so one
+// that finds the ISRC helps the next look the track up by it.
+func (s *Service) chainMetadata(ctx context.Context, track *domain.Track) bool {
+	filled := false
+	for _, source := range s.metadata.Sources {
+		if ctx.Err() != nil || !track.MissingMetadata() {
+			break
+		}
+		m, err := s.lookupMetadata(ctx, source, *track)
+		if err != nil {
+			log.Printf("[migration] %s metadata lookup of %q failed: %v", source.Name(), track.Name, err)
+			continue
+		}
+		if track.FillMetadata(*m) {
+			filled = true
+		}
+	}
+	return filled
+}
+
+// lookupMetadata asks source about track, answering from the cache when it
+// holds the answer and caching new ones. A source that does not know the
+// track answers empty metadata. Cache failures count as misses.
+func (s *Service) lookupMetadata(ctx context.Context, source ports.MetadataSource, track domain.Track) (*domain.TrackMetadata, error) {
+	cache := s.metadata.Cache
+	key := metadataCacheKey(source.Name(), track)
+	if cache != nil {
+		if data, err := cache.Get(ctx, key); err == nil {
+			var m domain.TrackMetadata
+			if json.Unmarshal(data, &m) == nil {
+				return &m, nil
+			}
+		}
+	}
+
+	lookupCtx, cancel := s.metadataContext(ctx)
+	defer cancel()
+	m, err := source.LookupMetadata(lookupCtx, track)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		m = &domain.TrackMetadata{}
+	}
+	if cache != nil {
+		if data, err := json.Marshal(m); err == nil {
+			_ = cache.Put(ctx, key, data, s.metadata.CacheTTL)
+		}
+	}
+	return m, nil
+}
+
+// metadataContext bounds ctx by the lookup timeout, if one is set.
+func (s *Service) metadataContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.metadata.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.metadata.Timeout)
+}
+
+// metadataCacheKey identifies the lookup of track in a metadata source by
+// what sources look tracks up by. Tracks that normalize alike share an
+// entry.
+func metadataCacheKey(source string, track domain.Track) string {
+	return fmt.Sprintf("metadata:%s:%s:%s:%s", source, strings.ToUpper(track.ISRC), track.MusicBrainzID,
+		matching.Normalize(track.Artist()+" - "+track.Name))
+}
+
+// trackMetadata returns the metadata of a track a provider looked up.
+func trackMetadata(t domain.Track) domain.TrackMetadata {
+	return domain.TrackMetadata{ISRC: t.ISRC, DurationMS: t.DurationMS, Album: t.Album, MusicBrainzID: t.MusicBrainzID}
+}
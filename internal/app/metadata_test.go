@@ -0,0 +1,133 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metadataSource is a ports.MetadataSource answering from catalog, by track
+// name, and recording the tracks it was asked about.
+type metadataSource struct {
+	name    string
+	catalog map[string]domain.TrackMetadata
+	delay   time.Duration
+
+	mu      sync.Mutex
+	lookups []string
+}
+
+func (m *metadataSource) Name() string { return m.name }
+
+func (m *metadataSource) LookupMetadata(ctx context.Context, track domain.Track) (*domain.TrackMetadata, error) {
+	m.mu.Lock()
+	m.lookups = append(m.lookups, track.Name)
+	m.mu.Unlock()
+	if m.delay > 0 {
+		select {
+		case <-time.After(m.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if md, ok := m.catalog[track.Name]; ok {
+		return &md, nil
+	}
+	return nil, nil
+}
+
+func newMetadataRun(source ports.MusicProvider, tracks ...domain.Track) *migrationRun {
+	return &migrationRun{
+		req:    domain.MigrationRequest{SourceProvider: source.Name(), DestProvider: "dest"},
+		source: source, dest: &mockProvider{name: "dest"}, timing: &domain.MigrationTiming{},
+		tracks: tracks,
+	}
+}
+
+func TestEnrichStage_FillsMetadataFromChain(t *testing.T) {
+	musicbrainz := &metadataSource{name: "musicbrainz", catalog: map[string]domain.TrackMetadata{
+		"One":  {ISRC: "ISRC1", MusicBrainzID: "mb-1"},
+		"Two":  {ISRC: "ISRC2", DurationMS: 200000, Album: "Album Two"},
+		"Done": {Album: "Never asked"},
+	}}
+	deezer := &metadataSource{name: "deezer", catalog: map[string]domain.TrackMetadata{
+		"One": {ISRC: "OTHER", Album: "Album One"},
+		"Two": {Album: "Never asked"},
+	}}
+	source := &lookupProvider{
+		mockProvider: &mockProvider{name: "lastfm"},
+		catalog:      map[string]domain.Track{"s1": {ExternalID: "s1", DurationMS: 180000}},
+	}
+	svc := newService(adapters.NewProviderRegistry(), 2, WithMetadataEnrichment(MetadataEnrichment{
+		Sources: []ports.MetadataSource{musicbrainz, deezer},
+	}))
+	run := newMetadataRun(source,
+		domain.Track{ExternalID: "s1", Name: "One"},
+		domain.Track{Name: "Two"},
+		domain.Track{Name: "Done", ISRC: "ISRC3", DurationMS: 1000, Album: "Complete"},
+		domain.Track{Name: "Episode", Type: domain.ItemTypeEpisode},
+	)
+
+	require.NoError(t, svc.enrichStage(context.Background(), run))
+	assert.Equal(t, [][]string{{"s1"}}, source.lookups, "the source provider is asked first")
+	assert.ElementsMatch(t, []string{"One", "Two"}, musicbrainz.lookups)
+	assert.Equal(t, []string{"One"}, deezer.lookups, "complete tracks leave the chain")
+
+	assert.Equal(t, domain.Track{ExternalID: "s1", Name: "One", ISRC: "ISRC1", DurationMS: 180000, Album: "Album One", MusicBrainzID: "mb-1"}, run.tracks[0])
+	assert.Equal(t, domain.Track{Name: "Two", ISRC: "ISRC2", DurationMS: 200000, Album: "Album Two"}, run.tracks[1])
+	assert.Equal(t, "Complete", run.tracks[2].Album)
+	assert.Empty(t, run.tracks[3].ISRC)
+	assert.Equal(t, []int{0, 1, 2, 3}, run.pending)
+}
+
+func TestEnrichStage_WithoutSourcesKeepsTracks(t *testing.T) {
+	source := &lookupProvider{mockProvider: &mockProvider{name: "lastfm"}}
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	run := newMetadataRun(source, domain.Track{ExternalID: "s1", Name: "One"})
+
+	require.NoError(t, svc.enrichStage(context.Background(), run))
+	assert.Empty(t, source.lookups)
+	assert.Equal(t, domain.Track{ExternalID: "s1", Name: "One"}, run.tracks[0])
+}
+
+func TestEnrichStage_CachesMetadataAnswers(t *testing.T) {
+	musicbrainz := &metadataSource{name: "musicbrainz", catalog: map[string]domain.TrackMetadata{
+		"One": {ISRC: "ISRC1"},
+	}}
+	svc := newService(adapters.NewProviderRegistry(), 1, WithMetadataEnrichment(MetadataEnrichment{
+		Sources:  []ports.MetadataSource{musicbrainz},
+		Cache:    memory.NewSearchCache(),
+		CacheTTL: time.Hour,
+	}))
+
+	for range 2 {
+		run := newMetadataRun(&mockProvider{name: "lastfm"}, domain.Track{Name: "One"}, domain.Track{Name: "Unknown"})
+		require.NoError(t, svc.enrichStage(context.Background(), run))
+		assert.Equal(t, "ISRC1", run.tracks[0].ISRC)
+	}
+	assert.ElementsMatch(t, []string{"One", "Unknown"}, musicbrainz.lookups, "answers, even empty ones, are cached")
+}
+
+func TestEnrichStage_SlowMetadataSourceTimesOut(t *testing.T) {
+	slow := &metadataSource{name: "musicbrainz", delay: time.Minute}
+	deezer := &metadataSource{name: "deezer", catalog: map[string]domain.TrackMetadata{
+		"One": {ISRC: "ISRC1"},
+	}}
+	svc := newService(adapters.NewProviderRegistry(), 1, WithMetadataEnrichment(MetadataEnrichment{
+		Sources: []ports.MetadataSource{slow, deezer},
+		Timeout: 10 * time.Millisecond,
+	}))
+	run := newMetadataRun(&mockProvider{name: "lastfm"}, domain.Track{Name: "One"})
+
+	require.NoError(t, svc.enrichStage(context.Background(), run))
+	assert.Equal(t, []string{"One"}, slow.lookups)
+	assert.Equal(t, "ISRC1", run.tracks[0].ISRC, "the next source is asked once the slow one timed out")
+}
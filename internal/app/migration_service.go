@@ -2,31 +2,132 @@ package app
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
 	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
 )
 
 // Service implements ports.MigrationService using a worker pool pattern for
 // concurrent track matching across streaming providers.
 type Service struct {
 	registry *adapters.ProviderRegistry
+	store    ports.MigrationStore
+	tokens   ports.TokenVault
+	progress ProgressFunc
+	quota    *QuotaTracker
+	mappings ports.TrackMappingStore
+	feedback ports.MatchFeedbackStore
+	audit    ports.AuditLog
+	limits   *LimitService
+	timeouts Timeouts
+	hooks    []ports.MigrationHook
+	metadata MetadataEnrichment
 	workers  int
+
+	// fingerprinter identifies tracks not found by their audio, if set.
+	fingerprinter ports.Fingerprinter
+
+	// limiters adapt search concurrency per destination provider and persist
+	// across migrations, so a provider that rate limited one migration starts
+	// the next at the reduced limit.
+	limitersMu sync.Mutex
+	limiters   map[string]*adaptiveLimiter
+
+	// rateLimitBackoff is the base delay before retrying a rate-limited
+	// search; attempt n waits n times as long.
+	rateLimitBackoff time.Duration
+
+	// inflight holds the idempotency keys of running migrations, scoped by
+	// account.
+	inflightMu sync.Mutex
+	inflight   map[idempotencyScope]bool
+
+	// locker guards against concurrent migrations of the same playlist,
+	// across instances when it is shared; lockOwner identifies this Service
+	// and prefixes the owner of each lock it takes.
+	locker    ports.Locker
+	lockOwner string
+}
+
+// ProgressFunc is called each time a track search finishes, with the number
+// of tracks processed so far and the total number of tracks. While a
+// streamed source playlist is still being fetched, total counts the tracks
+// queued so far.
+type ProgressFunc func(done, total int)
+
+// Option configures optional dependencies of a Service.
+type Option func(*Service)
+
+// WithProgress registers a callback that reports track matching progress.
+func WithProgress(fn ProgressFunc) Option {
+	return func(s *Service) {
+		s.progress = fn
+	}
+}
+
+// WithMigrationStore sets the store used to persist migration results. It is
+// required.
+func WithMigrationStore(store ports.MigrationStore) Option {
+	return func(s *Service) {
+		s.store = store
+	}
+}
+
+// WithTokenVault lets requests omit provider tokens; missing tokens are then
+// looked up in the vault for the account in the request context.
+func WithTokenVault(tokens ports.TokenVault) Option {
+	return func(s *Service) {
+		s.tokens = tokens
+	}
+}
+
+// WithQuotaTracker enables daily quota budget checks for providers with
+// unit-based API quotas.
+func WithQuotaTracker(quota *QuotaTracker) Option {
+	return func(s *Service) {
+		s.quota = quota
+	}
+}
+
+// WithTrackMappings makes migrations record every match as a cross-provider
+// track mapping and reuse known mappings instead of searching.
+func WithTrackMappings(mappings ports.TrackMappingStore) Option {
+	return func(s *Service) {
+		s.mappings = mappings
+	}
 }
 
 // NewService creates a new migration service with the given provider registry
-// and number of concurrent workers for track matching.
-func NewService(registry *adapters.ProviderRegistry, workers int) *Service {
+// and number of concurrent workers for track matching. The options must
+// include WithMigrationStore and WithLocker.
+func NewService(registry *adapters.ProviderRegistry, workers int, opts ...Option) *Service {
 	if workers < 1 {
 		workers = 1
 	}
-	return &Service{
-		registry: registry,
-		workers:  workers,
+	s := &Service{
+		registry:         registry,
+		workers:          workers,
+		limiters:         make(map[string]*adaptiveLimiter),
+		inflight:         make(map[idempotencyScope]bool),
+		rateLimitBackoff: 500 * time.Millisecond,
+		lockOwner:        newInstanceID(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.store == nil || s.locker == nil {
+		panic("app: NewService requires WithMigrationStore and WithLocker")
+	}
+	return s
 }
 
 func (s *Service) ListPlaylists(ctx context.Context, provider string, token string) ([]domain.Playlist, error) {
@@ -34,10 +135,164 @@ func (s *Service) ListPlaylists(ctx context.Context, provider string, token stri
 	if err != nil {
 		return nil, err
 	}
+
+	token, err = s.resolveToken(ctx, provider, token)
+	if err != nil {
+		return nil, err
+	}
 	return p.GetPlaylists(ctx, token)
 }
 
+func (s *Service) ListPlaylistsPage(ctx context.Context, provider string, token string, page domain.PageRequest) (*domain.PlaylistPage, error) {
+	p, err := s.registry.Get(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err = s.resolveToken(ctx, provider, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetPlaylistsPage(ctx, token, page)
+}
+
+func (s *Service) SearchTracks(ctx context.Context, provider string, token string, track domain.Track) ([]domain.TrackCandidate, error) {
+	p, err := s.registry.Get(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err = s.resolveToken(ctx, provider, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if searcher, ok := p.(ports.CandidateSearcher); ok {
+		return searcher.SearchTrackCandidates(ctx, token, track)
+	}
+
+	// Providers without candidate search only expose their best match.
+	matched, score, err := p.SearchTrack(ctx, token, track)
+	if err != nil {
+		return nil, err
+	}
+	if matched == nil {
+		return []domain.TrackCandidate{}, nil
+	}
+	return []domain.TrackCandidate{{Track: *matched, ConfidenceScore: score}}, nil
+}
+
+func (s *Service) GetPlaylist(ctx context.Context, provider string, token string, playlistID string) (*domain.Playlist, error) {
+	p, err := s.registry.Get(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err = s.resolveToken(ctx, provider, token)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist, err := p.GetPlaylist(ctx, token, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := p.GetPlaylistTracks(ctx, token, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	playlist.Tracks = tracks
+	playlist.TrackCount = len(tracks)
+
+	return playlist, nil
+}
+
+func (s *Service) UpdatePlaylist(ctx context.Context, provider string, token string, playlistID string, update domain.PlaylistUpdate) error {
+	p, err := s.registry.Get(provider)
+	if err != nil {
+		return err
+	}
+
+	if err := checkCollaborative(p, update); err != nil {
+		return err
+	}
+	if update.Name != nil {
+		name, _ := sanitizeText(p, *update.Name, "")
+		update.Name = &name
+	}
+	if update.Description != nil {
+		_, description := sanitizeText(p, "", *update.Description)
+		update.Description = &description
+	}
+
+	token, err = s.resolveToken(ctx, provider, token)
+	if err != nil {
+		return err
+	}
+
+	return s.updatePlaylist(ctx, p, token, playlistID, update)
+}
+
+func (s *Service) RemoveTracks(ctx context.Context, provider string, token string, playlistID string, trackIDs []string) error {
+	p, err := s.registry.Get(provider)
+	if err != nil {
+		return err
+	}
+
+	token, err = s.resolveToken(ctx, provider, token)
+	if err != nil {
+		return err
+	}
+
+	return s.removeTracks(ctx, p, token, playlistID, trackIDs)
+}
+
+func (s *Service) DeletePlaylist(ctx context.Context, provider string, token string, playlistID string) error {
+	p, err := s.registry.Get(provider)
+	if err != nil {
+		return err
+	}
+
+	token, err = s.resolveToken(ctx, provider, token)
+	if err != nil {
+		return err
+	}
+
+	return s.deletePlaylist(ctx, p, token, playlistID)
+}
+
 func (s *Service) MigratePlaylist(ctx context.Context, req domain.MigrationRequest) (*domain.MigrationResult, error) {
+	return s.migrate(ctx, req, migrateOptions{})
+}
+
+// migrateOptions adjusts a migration run by migrate.
+type migrateOptions struct {
+	// known maps source track ExternalIDs to their already known destination
+	// counterpart; those tracks are not searched.
+	known map[string]knownMatch
+
+	// reversedFrom is the ID of the migration being reversed, if any.
+	reversedFrom string
+}
+
+type knownMatch struct {
+	track domain.Track
+	score float64
+}
+
+func (s *Service) migrate(ctx context.Context, req domain.MigrationRequest, opts migrateOptions) (*domain.MigrationResult, error) {
+	if req.IdempotencyKey != "" {
+		existing, release, err := s.claimIdempotencyKey(ctx, req)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+		defer release()
+	}
+	started := time.Now()
+	timing := &domain.MigrationTiming{}
+
 	source, err := s.registry.Get(req.SourceProvider)
 	if err != nil {
 		return nil, fmt.Errorf("source provider error: %w", err)
@@ -47,93 +302,695 @@ func (s *Service) MigratePlaylist(ctx context.Context, req domain.MigrationReque
 	if err != nil {
 		return nil, fmt.Errorf("destination provider error: %w", err)
 	}
+	if !req.MatchingStrategy.Valid() {
+		return nil, fmt.Errorf("unknown matching strategy %q", req.MatchingStrategy)
+	}
+	if !req.ConflictPolicy.Valid() {
+		return nil, fmt.Errorf("unknown conflict policy %q", req.ConflictPolicy)
+	}
+	if req.MinScore < 0 || req.MinScore > 1 {
+		return nil, fmt.Errorf("min score %.2f is not between 0 and 1", req.MinScore)
+	}
+	if req.ReviewThreshold < 0 || req.ReviewThreshold > 1 {
+		return nil, fmt.Errorf("review threshold %.2f is not between 0 and 1", req.ReviewThreshold)
+	}
+	if !req.Rerecordings.Valid() {
+		return nil, fmt.Errorf("unknown rerecording preference %q", req.Rerecordings)
+	}
+	filter, err := compileFilter(req.Exclude, req.Genres)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := dest.(ports.SourceOnly); ok {
+		return nil, fmt.Errorf("destination provider error: %s: %w", req.DestProvider, domain.ErrSourceOnlyProvider)
+	}
+	limits, limitWarning, start, err := s.startMigration(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer start.cancel(context.WithoutCancel(ctx))
 
-	// Step 1: Fetch tracks from source playlist
-	log.Printf("[migration] fetching tracks from %s playlist %s", req.SourceProvider, req.PlaylistID)
-	tracks, err := source.GetPlaylistTracks(ctx, req.SourceToken, req.PlaylistID)
+	// Dry runs create nothing, so they cannot produce duplicates.
+	if !req.DryRun {
+		release, err := s.lockPlaylist(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	sourceToken, err := s.resolveToken(ctx, req.SourceProvider, req.SourceToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch source tracks: %w", err)
+		return nil, err
 	}
+	req.SourceToken = sourceToken
 
-	if len(tracks) == 0 {
-		return nil, fmt.Errorf("source playlist is empty")
+	destToken, err := s.resolveToken(ctx, req.DestProvider, req.DestToken)
+	if err != nil {
+		return nil, err
 	}
+	req.DestToken = destToken
 
-	log.Printf("[migration] found %d tracks, starting migration to %s", len(tracks), req.DestProvider)
+	// Dry runs only search, so the destination token needs no write scope.
+	if err := checkToken(ctx, source, req.SourceToken, false); err != nil {
+		return nil, fmt.Errorf("source provider error: %w", err)
+	}
+	if err := checkToken(ctx, dest, req.DestToken, !req.DryRun); err != nil {
+		return nil, fmt.Errorf("destination provider error: %w", err)
+	}
 
-	// Step 2: Search for each track on destination using worker pool
-	results := s.searchTracksParallel(ctx, dest, req.DestToken, tracks)
+	if req.Market != "" {
+		req.Market = strings.ToUpper(req.Market)
+		ctx = domain.ContextWithMarket(ctx, req.Market)
+	}
+	ctx = domain.ContextWithMatchOptions(ctx, req.MatchOptions())
 
-	// Step 3: Collect matched track IDs for batch insertion
-	var matchedIDs []string
-	matched := 0
-	failed := 0
+	ctx, cancel := s.withDeadline(ctx)
+	defer cancel()
+
+	run := &migrationRun{req: req, opts: opts, source: source, dest: dest, timing: timing, limits: limits, filter: filter}
+	if limitWarning != "" {
+		run.warn(limitWarning)
+	}
+	err = s.runPipeline(ctx, run, s.pipeline(run))
+	run.releaseQuota()
+	if err != nil {
+		return nil, err
+	}
+	start.keep()
+	results := run.results
+	matched, filtered, episodes := countResults(results)
+
+	gaps := assignPositions(results)
+	if run.reuse != nil {
+		placeAfterExisting(results, run.existing, run.held)
+	}
+	timing.TotalMS = msSince(started)
+
+	log.Printf("[migration] migration complete in %dms (search %dms)", timing.TotalMS, timing.SearchMS)
+
+	result := &domain.MigrationResult{
+		ID:             newID(),
+		AccountID:      domain.AccountIDFromContext(ctx),
+		SourceProvider: req.SourceProvider,
+		DestProvider:   req.DestProvider,
+		SourcePlaylist: req.PlaylistID,
+		TotalTracks:    len(results),
+		TotalEpisodes:  episodes,
+		MatchedTracks:  matched,
+		FailedTracks:   len(results) - matched - filtered,
+		FilteredTracks: filtered,
+		ErrorBreakdown: errorBreakdown(results),
+		DryRun:         req.DryRun,
+		Market:         req.Market,
+		IdempotencyKey: req.IdempotencyKey,
+		PreserveOrder:  req.PreserveOrder,
+		Classical:      req.Classical,
+		StrictVersions: req.StrictVersions,
+		CopySharing:    req.CopySharing,
+		ReversedFrom:   opts.reversedFrom,
+		CreatedAt:      time.Now().UTC(),
+		TrackResults:   results,
+		Warnings:       run.warnings,
+		Concurrency:    run.concurrency,
+		Timing:         timing,
+	}
+	result.DestPlaylistName, result.DestPlaylistDescription = run.destName, run.destDescription
+	result.MatchingStrategy, result.MinScore = req.MatchingStrategy, req.MinScore
+	result.Rerecordings = req.Rerecordings
+	result.ReusedPlaylist = run.reuse != nil
+	result.ReviewThreshold, result.ReviewPlaylistID = req.ReviewThreshold, run.reviewPlaylistID
+	result.ReviewTracks = countReview(results)
+	if len(run.destPlaylistIDs) > 0 {
+		result.DestPlaylistID = run.destPlaylistIDs[0]
+	}
+	if len(run.destPlaylistIDs) > 1 {
+		result.DestPlaylistIDs = run.destPlaylistIDs
+	}
+	if run.quotaUsed > 0 || run.sourceQuotaUsed > 0 {
+		result.QuotaUnitsUsed = make(map[string]int)
+		if run.sourceQuotaUsed > 0 {
+			result.QuotaUnitsUsed[req.SourceProvider] += run.sourceQuotaUsed
+		}
+		if run.quotaUsed > 0 {
+			result.QuotaUnitsUsed[req.DestProvider] += run.quotaUsed
+		}
+	}
+	if req.PreserveOrder {
+		result.Gaps = gaps
+	}
+
+	// The result is stored even if the migration deadline expired after the
+	// last provider call.
+	if err := s.store.Save(context.WithoutCancel(ctx), result); err != nil {
+		log.Printf("[migration] failed to store migration %s: %v", result.ID, err)
+	}
+	s.runHooks(ctx, result)
+
+	return result, nil
+}
 
-	for i := range results {
-		if results[i].Status == domain.TrackStatusMatched && results[i].MatchedTrack != nil {
-			matchedIDs = append(matchedIDs, results[i].MatchedTrack.ExternalID)
+// countReview counts the matches in the review playlist.
+func countReview(results []domain.TrackResult) int {
+	n := 0
+	for _, tr := range results {
+		if isPlaced(tr) && tr.Review {
+			n++
+		}
+	}
+	return n
+}
+
+// errorBreakdown counts the failed results by cause: their error code, or
+// their status if the failure is not classified. It returns nil if no
+// result failed.
+func errorBreakdown(results []domain.TrackResult) map[string]int {
+	var breakdown map[string]int
+	for _, tr := range results {
+		if isPlaced(tr) || tr.Status == domain.TrackStatusFiltered {
+			continue
+		}
+		cause := string(tr.ErrorCode)
+		if cause == "" {
+			cause = string(tr.Status)
+		}
+		if breakdown == nil {
+			breakdown = make(map[string]int)
+		}
+		breakdown[cause]++
+	}
+	return breakdown
+}
+
+// countResults counts the matched, filtered and episode results.
+func countResults(results []domain.TrackResult) (matched, filtered, episodes int) {
+	for _, tr := range results {
+		if isPlaced(tr) {
 			matched++
-		} else {
-			failed++
+		}
+		if tr.Status == domain.TrackStatusFiltered {
+			filtered++
+		}
+		if tr.SourceTrack.IsEpisode() {
+			episodes++
 		}
 	}
+	return matched, filtered, episodes
+}
 
-	log.Printf("[migration] search complete: %d matched, %d failed", matched, failed)
+func (s *Service) GetMigration(ctx context.Context, id string) (*domain.MigrationResult, error) {
+	return s.getOwnedMigration(ctx, id)
+}
 
-	// Step 4: Create destination playlist
-	playlistName := fmt.Sprintf("Migrated from %s", req.SourceProvider)
-	destPlaylistID, err := dest.CreatePlaylist(
-		ctx, req.DestToken, playlistName,
-		fmt.Sprintf("Migrated %d/%d tracks", matched, len(tracks)),
-	)
+func (s *Service) ListMigrations(ctx context.Context) ([]domain.MigrationResult, error) {
+	return s.store.List(ctx, domain.AccountIDFromContext(ctx))
+}
+
+func (s *Service) RetryFailedTracks(ctx context.Context, id string, token string) (*domain.MigrationResult, error) {
+	result, err := s.getOwnedMigration(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create destination playlist: %w", err)
+		return nil, err
 	}
 
-	log.Printf("[migration] created destination playlist: %s", destPlaylistID)
+	if result.RolledBack {
+		return nil, fmt.Errorf("%w: %s", domain.ErrAlreadyRolledBack, id)
+	}
 
-	// Step 5: Add matched tracks to the destination playlist
-	if len(matchedIDs) > 0 {
-		if err := dest.AddTracksToPlaylist(ctx, req.DestToken, destPlaylistID, matchedIDs); err != nil {
-			return nil, fmt.Errorf("failed to add tracks to destination playlist: %w", err)
+	dest, err := s.registry.Get(result.DestProvider)
+	if err != nil {
+		return nil, fmt.Errorf("destination provider error: %w", err)
+	}
+
+	token, err = s.resolveToken(ctx, result.DestProvider, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var indices []int
+	var tracks []domain.Track
+	var readd []int
+	for i, tr := range result.TrackResults {
+		switch {
+		case tr.Status == domain.TrackStatusNotFound,
+			tr.Status == domain.TrackStatusError && tr.RetryWorthy():
+			indices = append(indices, i)
+			tracks = append(tracks, tr.SourceTrack)
+		case tr.Status == domain.TrackStatusAddFailed && tr.RetryWorthy():
+			readd = append(readd, i)
 		}
 	}
 
-	log.Printf("[migration] migration complete")
+	if len(tracks) == 0 && len(readd) == 0 {
+		return result, nil
+	}
 
-	return &domain.MigrationResult{
-		SourcePlaylist: req.PlaylistID,
-		DestPlaylistID: destPlaylistID,
-		TotalTracks:    len(tracks),
-		MatchedTracks:  matched,
-		FailedTracks:   failed,
-		TrackResults:   results,
-	}, nil
+	log.Printf("[migration] retrying %d failed tracks of %s", len(tracks)+len(readd), id)
+	started := time.Now()
+	timing := &domain.MigrationTiming{}
+
+	ctx, cancel := s.withDeadline(ctx)
+	defer cancel()
+
+	if result.Market != "" {
+		ctx = domain.ContextWithMarket(ctx, result.Market)
+	}
+	ctx = domain.ContextWithMatchOptions(ctx, domain.MatchOptions{
+		Classical:      result.Classical,
+		StrictVersions: result.StrictVersions,
+		Strategy:       result.MatchingStrategy,
+		MinScore:       result.MinScore,
+		Rerecordings:   result.Rerecordings,
+	})
+
+	var reservation *QuotaReservation
+	if estimate := quotaCost(dest, domain.QuotaOpSearch, len(tracks)) + quotaCost(dest, domain.QuotaOpAddTrack, len(tracks)); s.quota != nil && estimate > 0 {
+		if reservation, _, err = s.quota.Reserve(result.DestProvider, estimate); err != nil {
+			return nil, err
+		}
+		defer reservation.Release()
+	}
+
+	stageStart := time.Now()
+	retried, concurrency := s.searchTracksParallel(ctx, dest, token, tracks)
+	timing.SearchMS = msSince(stageStart)
+	result.Concurrency = concurrency
+	quotaUsed := quotaCost(dest, domain.QuotaOpSearch, uncachedSearches(retried))
+	s.spendQuota(reservation, result.DestProvider, quotaUsed)
+
+	s.applyFeedback(ctx, result.SourceProvider, result.DestProvider, retried)
+	s.saveMappings(ctx, result.SourceProvider, result.DestProvider, retried)
+
+	added := make(map[int]bool)
+	for i, tr := range retried {
+		tr.SourcePosition = indices[i]
+		result.TrackResults[indices[i]] = tr
+		if isPlaced(tr) {
+			added[indices[i]] = true
+		}
+	}
+	log.Printf("[migration] retry matched %d of %d tracks", len(added), len(tracks))
+
+	// Tracks whose add failed keep their match and are only added again.
+	for _, i := range readd {
+		result.TrackResults[i].Status = domain.TrackStatusMatched
+		result.TrackResults[i].Error = ""
+		result.TrackResults[i].ErrorCode = ""
+		result.TrackResults[i].Retryable = false
+		added[i] = true
+	}
+
+	// Matches below the review threshold, and re-added ones that were for
+	// review already, go into the review playlist.
+	var review []int
+	for i := range result.TrackResults {
+		tr := &result.TrackResults[i]
+		if added[i] && (tr.Review || tr.ConfidenceScore < result.ReviewThreshold) {
+			tr.Review = true
+			review = append(review, i)
+			delete(added, i)
+		}
+	}
+
+	var newIDs []string
+	var newIndices []int
+	for i, tr := range result.TrackResults {
+		if added[i] {
+			newIDs = append(newIDs, tr.MatchedTrack.ExternalID)
+			newIndices = append(newIndices, i)
+		}
+	}
+	addFailed := 0
+
+	// With PreserveOrder, newly matched tracks are inserted at their original
+	// relative position when the provider supports it; otherwise they are
+	// appended to the end of the destination playlist.
+	// Tracks of a split migration are appended to its last part, since
+	// inserting them in place would overflow earlier parts, and go on into
+	// new parts once it is full.
+	playlists := destPlaylists(result)
+	parts := []playlistPart{{start: 0, end: len(newIDs)}}
+	if !result.ReusedPlaylist {
+		held := 0
+		for i, tr := range result.TrackResults {
+			if isPlaced(tr) && !tr.Review && !added[i] {
+				held++
+			}
+		}
+		parts = appendedParts(dest, len(playlists), held, len(newIDs))
+	}
+	if len(parts) > 1 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"%d retried tracks exceed the %s limit of %d per playlist; %d of them go into new parts",
+			len(newIDs), result.DestProvider, playlistCapacity(dest), len(newIDs)-parts[0].end))
+	}
+	positional, _ := dest.(ports.PositionalAdder)
+	if len(playlists) > 1 || len(parts) > 1 || result.ReusedPlaylist {
+		positional = nil
+	}
+	if result.PreserveOrder && positional != nil {
+		runs, gaps := insertionRuns(result.TrackResults, added)
+		result.Gaps = gaps
+		if !result.DryRun {
+			// Runs hold newIndices in order. Tracks that fail to insert take
+			// no position, so later runs move up by the failures so far.
+			var abort error
+			next := 0
+			for _, run := range runs {
+				runIndices := newIndices[next : next+len(run.trackIDs)]
+				next += len(run.trackIDs)
+				if abort != nil {
+					addFailed += applyAddOutcomes(result.TrackResults, runIndices, nil, abort)
+					continue
+				}
+				var outcomes []domain.AddOutcome
+				stageStart = time.Now()
+				abort = s.runStage(ctx, domain.StageAdd, func(ctx context.Context) error {
+					var err error
+					outcomes, err = s.insertTracks(ctx, dest, token, result.DestPlaylistID, run.position-addFailed, run.trackIDs)
+					return err
+				})
+				timing.AddMS += msSince(stageStart)
+				addFailed += applyAddOutcomes(result.TrackResults, runIndices, outcomes, abort)
+			}
+			if abort != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("failed to insert tracks into destination playlist: %v", abort))
+			}
+			if addFailed > 0 {
+				result.Gaps = assignPositions(result.TrackResults)
+			}
+		}
+	} else {
+		if result.PreserveOrder && len(newIDs) > 0 {
+			reason := fmt.Sprintf("%s cannot insert tracks at a position", result.DestProvider)
+			if len(playlists) > 1 || len(parts) > 1 {
+				reason = "the destination playlist is split into parts"
+			} else if result.ReusedPlaylist {
+				reason = "the destination playlist was reused"
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"%s; %d retried tracks were appended out of order", reason, len(newIDs)))
+		}
+		if len(newIDs) > 0 && !result.DryRun {
+			targets := playlists[len(playlists)-1:]
+			var openErr error
+			if len(parts) > 1 {
+				stageStart = time.Now()
+				var used int
+				used, openErr = s.openParts(ctx, dest, token, result, len(parts)-1)
+				timing.CreateMS = msSince(stageStart)
+				quotaUsed += used
+				s.spendQuota(reservation, result.DestProvider, used)
+				if openErr != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("failed to create destination playlist: %v", openErr))
+				}
+				targets = append(targets, destPlaylists(result)[len(playlists):]...)
+			}
+			stageStart = time.Now()
+			for j, part := range parts {
+				partIndices := newIndices[part.start:part.end]
+				if j >= len(targets) {
+					addFailed += applyAddOutcomes(result.TrackResults, partIndices, nil, openErr)
+					continue
+				}
+				if part.start == part.end {
+					continue
+				}
+				var outcomes []domain.AddOutcome
+				err := s.runStage(ctx, domain.StageAdd, func(ctx context.Context) error {
+					var err error
+					outcomes, err = s.addTracks(ctx, dest, token, targets[j], newIDs[part.start:part.end])
+					return err
+				})
+				if err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("failed to add tracks to destination playlist: %v", err))
+				}
+				addFailed += applyAddOutcomes(result.TrackResults, partIndices, outcomes, err)
+			}
+			timing.AddMS = msSince(stageStart)
+		}
+		appendPositions(result.TrackResults, added, false)
+	}
+	result.MatchedTracks += len(newIDs) - addFailed
+	result.FailedTracks -= len(newIDs) - addFailed
+	if len(newIDs) > 0 && !result.DryRun {
+		addCost := quotaCost(dest, domain.QuotaOpAddTrack, len(newIDs))
+		quotaUsed += addCost
+		s.spendQuota(reservation, result.DestProvider, addCost)
+	}
+	if len(review) > 0 {
+		reviewCost := s.retryReview(ctx, dest, token, result, review)
+		quotaUsed += reviewCost
+		s.spendQuota(reservation, result.DestProvider, reviewCost)
+	}
+
+	result.ErrorBreakdown = errorBreakdown(result.TrackResults)
+
+	timing.TotalMS = msSince(started)
+	result.Timing = timing
+
+	if quotaUsed > 0 {
+		if result.QuotaUnitsUsed == nil {
+			result.QuotaUnitsUsed = make(map[string]int)
+		}
+		result.QuotaUnitsUsed[result.DestProvider] += quotaUsed
+	}
+
+	if err := s.store.Save(context.WithoutCancel(ctx), result); err != nil {
+		return nil, fmt.Errorf("failed to update migration: %w", err)
+	}
+
+	return result, nil
+}
+
+// retryReview adds the late matches at review to the review playlist of
+// result, creating it if the migration had none, and updates the counts of
+// result. It returns the quota units used, which the caller records.
+func (s *Service) retryReview(ctx context.Context, dest ports.MusicProvider, token string, result *domain.MigrationResult, review []int) int {
+	added := make(map[int]bool, len(review))
+	for _, i := range review {
+		added[i] = true
+	}
+	failed, quotaUsed := 0, 0
+	if !result.DryRun {
+		ids := make([]string, len(review))
+		for j, i := range review {
+			ids[j] = result.TrackResults[i].MatchedTrack.ExternalID
+		}
+		var err error
+		if result.ReviewPlaylistID == "" {
+			name := result.DestPlaylistName
+			if name == "" {
+				name = result.DestPlaylistID
+			}
+			if result.ReviewPlaylistID, err = s.createReviewPlaylist(ctx, dest, token, name, len(ids)); err == nil {
+				quotaUsed += quotaCost(dest, domain.QuotaOpCreatePlaylist, 1)
+			}
+		}
+		var outcomes []domain.AddOutcome
+		if err == nil {
+			err = s.runStage(ctx, domain.StageAdd, func(ctx context.Context) error {
+				var err error
+				outcomes, err = s.addTracks(ctx, dest, token, result.ReviewPlaylistID, ids)
+				return err
+			})
+			quotaUsed += quotaCost(dest, domain.QuotaOpAddTrack, len(ids))
+		}
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to add tracks to review playlist: %v", err))
+		}
+		failed = applyAddOutcomes(result.TrackResults, review, outcomes, err)
+	}
+	appendPositions(result.TrackResults, added, true)
+	result.MatchedTracks += len(review) - failed
+	result.FailedTracks -= len(review) - failed
+	result.ReviewTracks = countReview(result.TrackResults)
+	return quotaUsed
+}
+
+func (s *Service) ReverseMigration(ctx context.Context, id string, req domain.ReverseMigrationRequest) (*domain.MigrationResult, error) {
+	original, err := s.getOwnedMigration(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if original.RolledBack {
+		return nil, fmt.Errorf("%w: %s", domain.ErrAlreadyRolledBack, id)
+	}
+	if original.DryRun {
+		return nil, fmt.Errorf("migration %s was a dry run and created no playlist", id)
+	}
+	if len(original.MergedFrom) > 0 {
+		return nil, fmt.Errorf("migration %s merged %d playlists and cannot be reversed", id, len(original.MergedFrom))
+	}
+	if len(original.DestPlaylistIDs) > 1 {
+		return nil, fmt.Errorf("migration %s was split into %d playlists and cannot be reversed", id, len(original.DestPlaylistIDs))
+	}
+
+	// Every track the original migration added maps straight back to its
+	// source track.
+	known := make(map[string]knownMatch)
+	for _, tr := range original.TrackResults {
+		if isPlaced(tr) && tr.SourceTrack.ExternalID != "" {
+			known[tr.MatchedTrack.ExternalID] = knownMatch{track: tr.SourceTrack, score: tr.ConfidenceScore}
+		}
+	}
+
+	return s.migrate(ctx, domain.MigrationRequest{
+		SourceProvider: original.DestProvider,
+		SourceToken:    req.SourceToken,
+		DestProvider:   original.SourceProvider,
+		DestToken:      req.DestToken,
+		PlaylistID:     original.DestPlaylistID,
+		DryRun:         req.DryRun,
+		Market:         original.Market,
+		PreserveOrder:  original.PreserveOrder,
+		Classical:      original.Classical,
+		StrictVersions: original.StrictVersions,
+		CopySharing:    original.CopySharing,
+
+		MatchingStrategy: original.MatchingStrategy,
+		MinScore:         original.MinScore,
+		Rerecordings:     original.Rerecordings,
+	}, migrateOptions{known: known, reversedFrom: original.ID})
+}
+
+func (s *Service) RollbackMigration(ctx context.Context, id string, token string) (*domain.MigrationResult, error) {
+	result, err := s.getOwnedMigration(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.RolledBack {
+		return nil, fmt.Errorf("%w: %s", domain.ErrAlreadyRolledBack, id)
+	}
+	if result.DryRun {
+		return nil, fmt.Errorf("migration %s was a dry run and created no playlist", id)
+	}
+
+	dest, err := s.registry.Get(result.DestProvider)
+	if err != nil {
+		return nil, fmt.Errorf("destination provider error: %w", err)
+	}
+
+	token, err = s.resolveToken(ctx, result.DestProvider, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// A reused playlist existed before the migration, so only the tracks
+	// the migration added are removed from it.
+	if result.ReusedPlaylist {
+		var added []string
+		for _, tr := range result.TrackResults {
+			if isPlaced(tr) && !tr.Existing {
+				added = append(added, tr.MatchedTrack.ExternalID)
+			}
+		}
+		log.Printf("[migration] rolling back %s: removing %d tracks from %s playlist %s", id, len(added), result.DestProvider, result.DestPlaylistID)
+		if len(added) > 0 {
+			if err := s.removeTracks(ctx, dest, token, result.DestPlaylistID, added); err != nil {
+				return nil, fmt.Errorf("failed to remove tracks from destination playlist: %w", err)
+			}
+		}
+	} else {
+		for _, playlistID := range destPlaylists(result) {
+			log.Printf("[migration] rolling back %s: deleting %s playlist %s", id, result.DestProvider, playlistID)
+			if err := s.deletePlaylist(ctx, dest, token, playlistID); err != nil {
+				return nil, fmt.Errorf("failed to delete destination playlist: %w", err)
+			}
+		}
+	}
+	if result.ReviewPlaylistID != "" {
+		log.Printf("[migration] rolling back %s: deleting %s review playlist %s", id, result.DestProvider, result.ReviewPlaylistID)
+		if err := s.deletePlaylist(ctx, dest, token, result.ReviewPlaylistID); err != nil {
+			return nil, fmt.Errorf("failed to delete review playlist: %w", err)
+		}
+	}
+
+	result.RolledBack = true
+	if err := s.store.Save(ctx, result); err != nil {
+		return nil, fmt.Errorf("failed to update migration: %w", err)
+	}
+
+	return result, nil
+}
+
+// maxRateLimitAttempts is how many times a single search is tried while the
+// provider keeps rate limiting it.
+const maxRateLimitAttempts = 3
+
+// maxRateLimitDeferrals is how many times a search still rate limited after
+// maxRateLimitAttempts goes back into the retry queue before its track fails.
+const maxRateLimitDeferrals = 3
+
+// maxRetryAfter is the longest Retry-After window a deferred search waits
+// for; a provider asking for longer fails the track right away.
+const maxRetryAfter = 2 * time.Minute
+
+// retryDelay returns how long a search rate limited with err waits in the
+// retry queue: the provider's Retry-After window or, without one, the
+// longest backoff of the immediate retries. It reports false if the
+// window exceeds maxRetryAfter.
+func (s *Service) retryDelay(err error) (time.Duration, bool) {
+	delay, ok := domain.RetryAfter(err)
+	if !ok {
+		return maxRateLimitAttempts * s.rateLimitBackoff, true
+	}
+	return delay, delay <= maxRetryAfter
 }
 
 // searchTracksParallel uses a worker pool to search for tracks concurrently
-// on the destination provider. The number of concurrent workers is controlled
-// by s.workers to respect API rate limits.
+// on the destination provider. At most s.workers searches run at once; the
+// provider's adaptive limiter lowers that when it rate limits searches and
+// raises it again as searches succeed. Searches that stay rate limited are
+// deferred: their worker moves on and they are queued again once the
+// provider's Retry-After window has passed.
 func (s *Service) searchTracksParallel(
 	ctx context.Context,
-	dest interface {
-		SearchTrack(ctx context.Context, token string, track domain.Track) (*domain.Track, float64, error)
-	},
+	dest ports.MusicProvider,
 	token string,
 	tracks []domain.Track,
-) []domain.TrackResult {
+) ([]domain.TrackResult, *domain.ConcurrencyStats) {
+	feed := make(chan []domain.Track, 1)
+	feed <- tracks
+	close(feed)
+	return s.searchTrackStream(ctx, dest, token, feed)
+}
+
+// indexedTrack is a track of a stream to search at index of the results;
+// deferred searches carry their attempts and start time into the next round.
+type indexedTrack struct {
+	index     int
+	track     domain.Track
+	attempts  int
+	deferrals int
+	started   time.Time
+}
+
+// searchTrackStream is searchTracksParallel for tracks that arrive in
+// batches on feed: the batches are searched as they come, and the results,
+// in the order the tracks arrived, are returned once feed is closed and
+// every track has one.
+func (s *Service) searchTrackStream(
+	ctx context.Context,
+	dest ports.MusicProvider,
+	token string,
+	feed <-chan []domain.Track,
+) ([]domain.TrackResult, *domain.ConcurrencyStats) {
+	limiter := s.limiterFor(dest.Name())
+	stats := &domain.ConcurrencyStats{Max: s.workers, Min: limiter.Limit()}
+	var statsMu sync.Mutex
+	episodes, _ := dest.(ports.EpisodeSearcher)
+	matchOpts := domain.MatchOptionsFromContext(ctx)
+	strategy, minScore := matchOpts.Strategy, matchOpts.Threshold()
 
 	type indexedResult struct {
 		index  int
 		result domain.TrackResult
 	}
 
-	trackCh := make(chan struct {
-		index int
-		track domain.Track
-	}, len(tracks))
-
-	resultCh := make(chan indexedResult, len(tracks))
+	trackCh := make(chan indexedTrack, s.workers)
+	resultCh := make(chan indexedResult, s.workers)
 
 	// Launch worker goroutines
 	var wg sync.WaitGroup
@@ -144,38 +1001,153 @@ func (s *Service) searchTracksParallel(
 			for item := range trackCh {
 				select {
 				case <-ctx.Done():
-					resultCh <- indexedResult{
-						index: item.index,
-						result: domain.TrackResult{
-							SourceTrack: item.track,
-							Status:      domain.TrackStatusError,
-							Error:       "context cancelled",
-						},
+					tr := domain.TrackResult{SourceTrack: item.track, Status: domain.TrackStatusError}
+					if err := s.timeoutError(ctx); err != nil {
+						tr.Fail(domain.ErrorCodeTimeout, err.Error())
+					} else {
+						tr.Fail(domain.ErrorCodeCancelled, "context cancelled")
 					}
+					resultCh <- indexedResult{index: item.index, result: tr}
 					continue
 				default:
 				}
 
-				matched, score, err := dest.SearchTrack(ctx, token, item.track)
+				var (
+					matched   *domain.Track
+					score     float64
+					err       error
+					attempts  int
+					latency   time.Duration
+					cached    func() bool
+					runnerUps func() []domain.TrackCandidate
+				)
+				if item.track.IsEpisode() && episodes == nil {
+					tr := domain.TrackResult{SourceTrack: item.track, Status: domain.TrackStatusUnsupported}
+					tr.Fail(domain.ErrorCodeUnsupported, "destination provider does not support podcast episodes")
+					resultCh <- indexedResult{index: item.index, result: tr}
+					continue
+				}
+
+				searchStart := item.started
+				if searchStart.IsZero() {
+					searchStart = time.Now()
+				}
+				for attempt := 1; ; attempt++ {
+					attempts = item.attempts + attempt
+					limiter.Acquire()
+					callStart := time.Now()
+					var searchCtx context.Context
+					searchCtx, cached = domain.ContextWithCacheReport(ctx)
+					searchCtx, runnerUps = domain.ContextWithCandidateReport(searchCtx)
+					err = s.runStage(searchCtx, domain.StageSearch, func(ctx context.Context) error {
+						var err error
+						if item.track.IsEpisode() {
+							matched, score, err = episodes.SearchEpisode(ctx, token, item.track)
+						} else {
+							matched, score, err = dest.SearchTrack(ctx, token, item.track)
+						}
+						return err
+					})
+					latency = time.Since(callStart)
+					rateLimited := errors.Is(err, domain.ErrRateLimited)
+					limit := limiter.Release(rateLimited)
+
+					statsMu.Lock()
+					stats.Min = min(stats.Min, limit)
+					if rateLimited {
+						stats.RateLimited++
+					}
+					statsMu.Unlock()
+
+					if !rateLimited || attempt == maxRateLimitAttempts {
+						break
+					}
+					log.Printf("[worker-%d] rate limited by %s, concurrency now %d, retrying '%s - %s'",
+						workerID, dest.Name(), limit, item.track.Artist(), item.track.Name)
+					select {
+					case <-ctx.Done():
+					case <-time.After(time.Duration(attempt) * s.rateLimitBackoff):
+					}
+				}
+
+				if errors.Is(err, domain.ErrRateLimited) && item.deferrals < maxRateLimitDeferrals && ctx.Err() == nil {
+					if delay, ok := s.retryDelay(err); ok {
+						item.attempts, item.deferrals, item.started = attempts, item.deferrals+1, searchStart
+						statsMu.Lock()
+						stats.Deferred++
+						statsMu.Unlock()
+						log.Printf("[worker-%d] still rate limited by %s, deferring '%s - %s' for %s",
+							workerID, dest.Name(), item.track.Artist(), item.track.Name, delay)
+						go func(item indexedTrack) {
+							select {
+							case <-ctx.Done():
+							case <-time.After(delay):
+							}
+							trackCh <- item
+						}(item)
+						continue
+					}
+				}
+
 				tr := domain.TrackResult{
 					SourceTrack: item.track,
+					SearchMS:    msSince(searchStart),
+					LatencyMS:   latency.Milliseconds(),
+					Attempts:    attempts,
+					Retries:     attempts - 1,
+					Deferrals:   item.deferrals,
+					Cached:      cached(),
+				}
+				if matched != nil {
+					tr.Candidates = runnerUps()
 				}
 
-				if err != nil {
+				if errors.Is(err, domain.ErrUnavailableInMarket) {
+					tr.Status = domain.TrackStatusUnavailableInMarket
+					tr.MatchedTrack = matched
+					tr.ConfidenceScore = score
+					tr.Fail(domain.ErrorCodeUnavailableInMarket, "")
+					log.Printf("[worker-%d] unavailable in market: '%s - %s'",
+						workerID, item.track.Artist(), item.track.Name)
+				} else if errors.Is(err, domain.ErrUnavailable) {
+					tr.Status = domain.TrackStatusUnavailable
+					tr.MatchedTrack = matched
+					tr.ConfidenceScore = score
+					tr.Fail(domain.ErrorCodeUnavailable, "")
+					log.Printf("[worker-%d] unavailable in every market: '%s - %s'",
+						workerID, item.track.Artist(), item.track.Name)
+				} else if err != nil {
 					tr.Status = domain.TrackStatusError
-					tr.Error = err.Error()
+					tr.Fail(domain.ErrorCodeOf(err), err.Error())
 					log.Printf("[worker-%d] error searching '%s - %s': %v",
-						workerID, item.track.Artist, item.track.Name, err)
+						workerID, item.track.Artist(), item.track.Name, err)
 				} else if matched == nil {
 					tr.Status = domain.TrackStatusNotFound
 					log.Printf("[worker-%d] not found: '%s - %s'",
-						workerID, item.track.Artist, item.track.Name)
+						workerID, item.track.Artist(), item.track.Name)
+				} else if !strategy.Confirms(item.track, *matched) {
+					tr.Status = domain.TrackStatusNeedsReview
+					tr.MatchedTrack = matched
+					tr.ConfidenceScore = score
+					tr.Error = "match not confirmed by ISRC"
+					log.Printf("[worker-%d] needs review: '%s - %s' -> '%s' (score: %.2f)",
+						workerID, item.track.Artist(), item.track.Name, matched.ExternalID, score)
+				} else if score < minScore {
+					tr.Status = domain.TrackStatusNotFound
+					tr.ConfidenceScore = score
+					minimum := string(strategy)
+					if matchOpts.MinScore > strategy.MinScore() {
+						minimum = "requested"
+					}
+					tr.Error = fmt.Sprintf("best candidate scored %.2f, below the %s minimum of %.2f", score, minimum, minScore)
+					log.Printf("[worker-%d] rejected: '%s - %s' -> '%s' (score: %.2f)",
+						workerID, item.track.Artist(), item.track.Name, matched.ExternalID, score)
 				} else {
 					tr.Status = domain.TrackStatusMatched
 					tr.MatchedTrack = matched
 					tr.ConfidenceScore = score
 					log.Printf("[worker-%d] matched: '%s - %s' -> '%s' (score: %.2f)",
-						workerID, item.track.Artist, item.track.Name, matched.ExternalID, score)
+						workerID, item.track.Artist(), item.track.Name, matched.ExternalID, score)
 				}
 
 				resultCh <- indexedResult{index: item.index, result: tr}
@@ -183,26 +1155,219 @@ func (s *Service) searchTracksParallel(
 		}(i)
 	}
 
-	// Send tracks to worker pool
-	for i, track := range tracks {
-		trackCh <- struct {
-			index int
-			track domain.Track
-		}{index: i, track: track}
+	// Queue the tracks of each batch for the worker pool and collect results
+	// in the order the tracks arrived. The track channel stays open while
+	// more batches may arrive or deferred searches may be queued again,
+	// until every track has a result.
+	listener := searchListenerFromContext(ctx)
+	started := false
+	var (
+		results []domain.TrackResult
+		queue   []indexedTrack
+		done    int
+	)
+	for feed != nil || done < len(results) {
+		var send chan<- indexedTrack
+		var next indexedTrack
+		if len(queue) > 0 {
+			send, next = trackCh, queue[0]
+		}
+		select {
+		case batch, ok := <-feed:
+			if !ok {
+				feed = nil
+				batch = nil
+			}
+			if !started && (ok || len(results) == 0) {
+				started = true
+				if listener != nil {
+					listener.searchStarted(ctx, len(batch))
+				}
+			}
+			for _, track := range batch {
+				queue = append(queue, indexedTrack{index: len(results), track: track})
+				results = append(results, domain.TrackResult{})
+			}
+		case send <- next:
+			queue = queue[1:]
+		case ir := <-resultCh:
+			results[ir.index] = ir.result
+			done++
+			if s.progress != nil {
+				s.progress(done, len(results))
+			}
+			if listener != nil {
+				listener.trackSearched(ctx, ir.result, done, len(results))
+			}
+		}
 	}
 	close(trackCh)
+	wg.Wait()
 
-	// Wait for all workers to finish, then close results channel
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
+	stats.Final = limiter.Limit()
+	return results, stats
+}
 
-	// Collect results preserving original order
-	results := make([]domain.TrackResult, len(tracks))
-	for ir := range resultCh {
-		results[ir.index] = ir.result
+// msSince returns the milliseconds elapsed since start.
+func msSince(start time.Time) int64 {
+	return time.Since(start).Milliseconds()
+}
+
+// limiterFor returns the adaptive concurrency limiter of a provider,
+// creating it on first use.
+func (s *Service) limiterFor(provider string) *adaptiveLimiter {
+	s.limitersMu.Lock()
+	defer s.limitersMu.Unlock()
+
+	l, ok := s.limiters[provider]
+	if !ok {
+		l = newAdaptiveLimiter(s.workers)
+		s.limiters[provider] = l
+	}
+	return l
+}
+
+// applyAddOutcomes marks the results at indices, whose matched tracks were
+// added as a batch, as add_failed where the outcome says so. Tracks without
+// an outcome count as failed with err. It returns the number of failures.
+func applyAddOutcomes(results []domain.TrackResult, indices []int, outcomes []domain.AddOutcome, err error) int {
+	failed := 0
+	for i, idx := range indices {
+		var outcome domain.AddOutcome
+		switch {
+		case i < len(outcomes):
+			outcome = outcomes[i]
+		case err != nil:
+			outcome.Error = err.Error()
+		default:
+			outcome.Error = "not added"
+		}
+		if outcome.Added {
+			continue
+		}
+		// Tracks the destination refused on their own will be refused
+		// again; the others were caught by a failed call.
+		code := domain.ErrorCodeProviderError
+		if err != nil && outcome.Error == err.Error() {
+			code = domain.ErrorCodeOf(err)
+		} else if i < len(outcomes) {
+			code = domain.ErrorCodeRejected
+		}
+		results[idx].Status = domain.TrackStatusAddFailed
+		results[idx].Error = outcome.Error
+		results[idx].Fail(code, "")
+		failed++
+	}
+	return failed
+}
+
+// spendQuota records units spent on provider against reservation, or
+// directly if nothing was reserved.
+func (s *Service) spendQuota(reservation *QuotaReservation, provider string, units int) {
+	if reservation != nil && units > 0 {
+		reservation.Spend(units)
+		return
+	}
+	s.recordQuota(provider, units)
+}
+
+// recordQuota adds units to the daily quota usage of provider, if quota
+// tracking is enabled.
+func (s *Service) recordQuota(provider string, units int) {
+	if s.quota != nil && units > 0 {
+		s.quota.Record(provider, units)
+	}
+}
+
+// resolveToken returns token unchanged when it is set. Otherwise it falls
+// back to the token stored in the vault for the caller's account, and
+// returns domain.ErrMissingToken if there is none.
+func (s *Service) resolveToken(ctx context.Context, provider string, token string) (string, error) {
+	if token != "" {
+		return token, nil
+	}
+	if s.tokens == nil {
+		return "", fmt.Errorf("no %s token provided: %w", provider, domain.ErrMissingToken)
+	}
+
+	stored, err := s.tokens.GetToken(ctx, provider)
+	if errors.Is(err, domain.ErrTokenNotFound) {
+		return "", fmt.Errorf("no %s token provided or stored: %w", provider, domain.ErrMissingToken)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s token: %w", provider, err)
+	}
+	if stored.ExpiresAt != nil && time.Now().Add(tokenRefreshMargin).After(*stored.ExpiresAt) {
+		return s.refreshToken(ctx, provider, stored)
 	}
+	return stored.AccessToken, nil
+}
+
+// requireToken returns domain.ErrMissingToken if token is empty and
+// resolveToken could not fall back to a token in the vault, without
+// refreshing the stored token. It checks requests that run later.
+func (s *Service) requireToken(ctx context.Context, provider string, token string) error {
+	if token != "" {
+		return nil
+	}
+	if s.tokens == nil {
+		return fmt.Errorf("no %s token provided: %w", provider, domain.ErrMissingToken)
+	}
+	_, err := s.tokens.GetToken(ctx, provider)
+	if errors.Is(err, domain.ErrTokenNotFound) {
+		return fmt.Errorf("no %s token provided or stored: %w", provider, domain.ErrMissingToken)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up %s token: %w", provider, err)
+	}
+	return nil
+}
+
+// sanitizeText returns name and description sanitized for provider, if it
+// restricts them.
+func sanitizeText(provider ports.MusicProvider, name, description string) (string, string) {
+	if sanitizer, ok := provider.(ports.TextSanitizer); ok {
+		return sanitizer.SanitizePlaylistText(name, description)
+	}
+	return name, description
+}
+
+// checkToken validates token with the provider before a migration starts, if
+// the provider supports it. Only a rejected token or a missing scope fails
+// the check; other errors are logged and left to the migration itself.
+func checkToken(ctx context.Context, provider ports.MusicProvider, token string, write bool) error {
+	checker, ok := provider.(ports.TokenChecker)
+	if !ok {
+		return nil
+	}
+
+	err := checker.CheckToken(ctx, token, write)
+	if errors.Is(err, domain.ErrInvalidToken) || errors.Is(err, domain.ErrInsufficientScope) {
+		return err
+	}
+	if err != nil {
+		log.Printf("[migration] could not check %s token: %v", provider.Name(), err)
+	}
+	return nil
+}
+
+// getOwnedMigration loads a stored migration and verifies that it belongs to
+// the account in ctx. Migrations owned by other accounts are reported as not
+// found so their existence is not leaked.
+func (s *Service) getOwnedMigration(ctx context.Context, id string) (*domain.MigrationResult, error) {
+	result, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if result.AccountID != domain.AccountIDFromContext(ctx) {
+		return nil, domain.ErrMigrationNotFound
+	}
+	return result, nil
+}
 
-	return results
+// newID returns a random hex identifier.
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // crypto/rand.Read never returns an error
+	return hex.EncodeToString(b)
 }
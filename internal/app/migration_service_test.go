@@ -2,52 +2,89 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
 
 	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
 	"github.com/jpp0ca/MusicMigration-API/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// newService creates a service backed by in-memory stores; opts may replace
+// them.
+func newService(registry *adapters.ProviderRegistry, workers int, opts ...Option) *Service {
+	defaults := []Option{WithMigrationStore(memory.NewMigrationStore()), WithLocker(memory.NewLocker())}
+	return NewService(registry, workers, append(defaults, opts...)...)
+}
+
 // -- Mock provider -----------------------------------------------------------
 
 type mockProvider struct {
 	name            string
+	lastToken       string
 	playlists       []domain.Playlist
 	tracks          []domain.Track
 	searchResults   map[string]*searchResult
 	createdID       string
 	addedTracks     []string
+	rejectAdd       map[string]bool
+	deletedIDs      []string
 	mu              sync.Mutex
 	searchCallCount int
+	lastMarket      string
+	lastMatchOpts   domain.MatchOptions
 }
 
 type searchResult struct {
-	track *domain.Track
-	score float64
-	err   error
+	track     *domain.Track
+	score     float64
+	err       error
+	cached    bool
+	runnerUps []domain.TrackCandidate
 }
 
 func (m *mockProvider) Name() string { return m.name }
 
-func (m *mockProvider) GetPlaylists(_ context.Context, _ string) ([]domain.Playlist, error) {
+func (m *mockProvider) GetPlaylists(_ context.Context, token string) ([]domain.Playlist, error) {
+	m.lastToken = token
 	return m.playlists, nil
 }
 
+func (m *mockProvider) GetPlaylistsPage(_ context.Context, _ string, _ domain.PageRequest) (*domain.PlaylistPage, error) {
+	return &domain.PlaylistPage{Items: m.playlists}, nil
+}
+
+func (m *mockProvider) GetPlaylist(_ context.Context, _ string, playlistID string) (*domain.Playlist, error) {
+	for _, p := range m.playlists {
+		if p.ID == playlistID {
+			return &p, nil
+		}
+	}
+	return nil, domain.ErrPlaylistNotFound
+}
+
 func (m *mockProvider) GetPlaylistTracks(_ context.Context, _ string, _ string) ([]domain.Track, error) {
 	return m.tracks, nil
 }
 
-func (m *mockProvider) SearchTrack(_ context.Context, _ string, track domain.Track) (*domain.Track, float64, error) {
+func (m *mockProvider) SearchTrack(ctx context.Context, _ string, track domain.Track) (*domain.Track, float64, error) {
 	m.mu.Lock()
 	m.searchCallCount++
+	m.lastMarket = domain.MarketFromContext(ctx)
+	m.lastMatchOpts = domain.MatchOptionsFromContext(ctx)
 	m.mu.Unlock()
+	domain.RecordProviderExchange(ctx, domain.ProviderExchange{Method: "GET", URL: "search:" + track.Name})
 
-	key := track.Name + "|" + track.Artist
+	key := track.Name + "|" + track.Artist()
 	if result, ok := m.searchResults[key]; ok {
+		if result.cached {
+			domain.ReportCacheHit(ctx)
+		}
+		domain.ReportRunnerUps(ctx, result.runnerUps)
 		return result.track, result.score, result.err
 	}
 	return nil, 0, nil
@@ -57,20 +94,80 @@ func (m *mockProvider) CreatePlaylist(_ context.Context, _ string, _ string, _ s
 	return m.createdID, nil
 }
 
-func (m *mockProvider) AddTracksToPlaylist(_ context.Context, _ string, _ string, trackIDs []string) error {
+func (m *mockProvider) AddTracksToPlaylist(_ context.Context, _ string, _ string, trackIDs []string) ([]domain.AddOutcome, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.addedTracks = append(m.addedTracks, trackIDs...)
+	outcomes := make([]domain.AddOutcome, 0, len(trackIDs))
+	for _, id := range trackIDs {
+		if m.rejectAdd[id] {
+			outcomes = append(outcomes, domain.AddOutcome{TrackID: id, Error: "rejected"})
+			continue
+		}
+		m.addedTracks = append(m.addedTracks, id)
+		outcomes = append(outcomes, domain.AddOutcome{TrackID: id, Added: true})
+	}
+	return outcomes, nil
+}
+
+func (m *mockProvider) RemoveTracksFromPlaylist(_ context.Context, _ string, _ string, _ []string) error {
 	return nil
 }
 
+func (m *mockProvider) UpdatePlaylistDetails(_ context.Context, _ string, _ string, _ domain.PlaylistUpdate) error {
+	return nil
+}
+
+func (m *mockProvider) DeletePlaylist(_ context.Context, _ string, playlistID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deletedIDs = append(m.deletedIDs, playlistID)
+	return nil
+}
+
+// episodeProvider is a mockProvider that can also match podcast episodes.
+type episodeProvider struct {
+	*mockProvider
+}
+
+func (e *episodeProvider) SearchEpisode(_ context.Context, _ string, episode domain.Track) (*domain.Track, float64, error) {
+	return &domain.Track{Name: episode.Name, Type: domain.ItemTypeEpisode, ExternalID: "ep-" + episode.Name}, 0.9, nil
+}
+
+// sourceOnlyProvider is a mockProvider that can only be migrated from.
+type sourceOnlyProvider struct {
+	*mockProvider
+}
+
+func (p *sourceOnlyProvider) SourceOnly() {}
+
+// tokenCheckProvider is a mockProvider that validates tokens up front.
+type tokenCheckProvider struct {
+	*mockProvider
+	err    error
+	writes []bool
+}
+
+func (p *tokenCheckProvider) CheckToken(_ context.Context, _ string, write bool) error {
+	p.writes = append(p.writes, write)
+	return p.err
+}
+
+// sanitizingProvider is a mockProvider that only accepts short names.
+type sanitizingProvider struct {
+	*mockProvider
+}
+
+func (p *sanitizingProvider) SanitizePlaylistText(name, description string) (string, string) {
+	return name[:8], "sanitized: " + description
+}
+
 // -- Tests -------------------------------------------------------------------
 
 func TestMigratePlaylist_AllMatched(t *testing.T) {
 	sourceTracks := []domain.Track{
-		{Name: "Bohemian Rhapsody", Artist: "Queen", ISRC: "GBUM71029604"},
-		{Name: "Stairway to Heaven", Artist: "Led Zeppelin", ISRC: "USAT20700634"},
-		{Name: "Hotel California", Artist: "Eagles", ISRC: "USEE10400237"},
+		{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, ISRC: "GBUM71029604"},
+		{Name: "Stairway to Heaven", Artists: []string{"Led Zeppelin"}, ISRC: "USAT20700634"},
+		{Name: "Hotel California", Artists: []string{"Eagles"}, ISRC: "USEE10400237"},
 	}
 
 	source := &mockProvider{
@@ -83,15 +180,15 @@ func TestMigratePlaylist_AllMatched(t *testing.T) {
 		createdID: "new-playlist-123",
 		searchResults: map[string]*searchResult{
 			"Bohemian Rhapsody|Queen": {
-				track: &domain.Track{Name: "Bohemian Rhapsody", Artist: "Queen", ExternalID: "vid-1"},
+				track: &domain.Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, ExternalID: "vid-1"},
 				score: 0.95,
 			},
 			"Stairway to Heaven|Led Zeppelin": {
-				track: &domain.Track{Name: "Stairway to Heaven", Artist: "Led Zeppelin", ExternalID: "vid-2"},
+				track: &domain.Track{Name: "Stairway to Heaven", Artists: []string{"Led Zeppelin"}, ExternalID: "vid-2"},
 				score: 0.90,
 			},
 			"Hotel California|Eagles": {
-				track: &domain.Track{Name: "Hotel California", Artist: "Eagles", ExternalID: "vid-3"},
+				track: &domain.Track{Name: "Hotel California", Artists: []string{"Eagles"}, ExternalID: "vid-3"},
 				score: 0.88,
 			},
 		},
@@ -101,7 +198,7 @@ func TestMigratePlaylist_AllMatched(t *testing.T) {
 	registry.Register(source)
 	registry.Register(dest)
 
-	svc := NewService(registry, 3)
+	svc := newService(registry, 3)
 	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
 		SourceProvider: "source",
 		SourceToken:    "token-source",
@@ -120,9 +217,9 @@ func TestMigratePlaylist_AllMatched(t *testing.T) {
 
 func TestMigratePlaylist_PartialMatch(t *testing.T) {
 	sourceTracks := []domain.Track{
-		{Name: "Track A", Artist: "Artist A"},
-		{Name: "Track B", Artist: "Artist B"},
-		{Name: "Track C", Artist: "Artist C"},
+		{Name: "Track A", Artists: []string{"Artist A"}},
+		{Name: "Track B", Artists: []string{"Artist B"}},
+		{Name: "Track C", Artists: []string{"Artist C"}},
 	}
 
 	source := &mockProvider{
@@ -135,7 +232,7 @@ func TestMigratePlaylist_PartialMatch(t *testing.T) {
 		createdID: "new-playlist-456",
 		searchResults: map[string]*searchResult{
 			"Track A|Artist A": {
-				track: &domain.Track{Name: "Track A", Artist: "Artist A", ExternalID: "vid-a"},
+				track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
 				score: 0.85,
 			},
 			// Track B not found (not in map)
@@ -151,7 +248,7 @@ func TestMigratePlaylist_PartialMatch(t *testing.T) {
 	registry.Register(source)
 	registry.Register(dest)
 
-	svc := NewService(registry, 2)
+	svc := newService(registry, 2)
 	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
 		SourceProvider: "source",
 		SourceToken:    "t1",
@@ -164,9 +261,122 @@ func TestMigratePlaylist_PartialMatch(t *testing.T) {
 	assert.Equal(t, 3, result.TotalTracks)
 	assert.Equal(t, 1, result.MatchedTracks)
 	assert.Equal(t, 2, result.FailedTracks)
+	assert.Equal(t, map[string]int{"not_found": 1, "provider_error": 1}, result.ErrorBreakdown)
 	assert.Len(t, dest.addedTracks, 1)
 }
 
+func TestMigratePlaylist_UnavailableInMarket(t *testing.T) {
+	source := &mockProvider{
+		name: "source",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}},
+			{Name: "Track B", Artists: []string{"Artist B"}},
+			{Name: "Track C", Artists: []string{"Artist C"}},
+		},
+	}
+
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "new-playlist",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {
+				track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+				score: 0.9,
+			},
+			"Track B|Artist B": {
+				track: &domain.Track{Name: "Track B", Artists: []string{"Artist B"}, ExternalID: "vid-b"},
+				score: 0.9,
+				err:   fmt.Errorf("dest: %w", domain.ErrUnavailableInMarket),
+			},
+			"Track C|Artist C": {
+				track: &domain.Track{Name: "Track C", Artists: []string{"Artist C"}, ExternalID: "vid-c"},
+				score: 0.9,
+				err:   fmt.Errorf("dest: %w", domain.ErrUnavailable),
+			},
+		},
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 2)
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		Market:         "de",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "DE", dest.lastMarket)
+	assert.Equal(t, "DE", result.Market)
+	assert.Equal(t, 1, result.MatchedTracks)
+	assert.Equal(t, 2, result.FailedTracks)
+	assert.Equal(t, []string{"vid-a"}, dest.addedTracks)
+	assert.Equal(t, domain.TrackStatusUnavailableInMarket, result.TrackResults[1].Status)
+	assert.Equal(t, domain.ErrorCodeUnavailableInMarket, result.TrackResults[1].ErrorCode)
+	assert.False(t, result.TrackResults[1].Retryable)
+	assert.Equal(t, "vid-b", result.TrackResults[1].MatchedTrack.ExternalID)
+
+	assert.Equal(t, domain.TrackStatusUnavailable, result.TrackResults[2].Status)
+	assert.Equal(t, domain.ErrorCodeUnavailable, result.TrackResults[2].ErrorCode)
+	assert.False(t, result.TrackResults[2].Retryable)
+	assert.Equal(t, "vid-c", result.TrackResults[2].MatchedTrack.ExternalID)
+	assert.Equal(t, map[string]int{"unavailable_in_market": 1, "unavailable": 1}, result.ErrorBreakdown)
+}
+
+func TestMigratePlaylist_Episodes(t *testing.T) {
+	tracks := []domain.Track{
+		{Name: "Track A", Artists: []string{"Artist A"}},
+		{Name: "Episode 1", Type: domain.ItemTypeEpisode, Show: &domain.Show{Name: "The Show"}},
+	}
+	searchResults := map[string]*searchResult{
+		"Track A|Artist A": {
+			track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+			score: 0.9,
+		},
+	}
+	req := domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	}
+
+	t.Run("unsupported destination", func(t *testing.T) {
+		dest := &mockProvider{name: "dest", createdID: "pl", searchResults: searchResults}
+		registry := adapters.NewProviderRegistry()
+		registry.Register(&mockProvider{name: "source", tracks: tracks})
+		registry.Register(dest)
+
+		result, err := newService(registry, 2).MigratePlaylist(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.TotalEpisodes)
+		assert.Equal(t, 1, result.FailedTracks)
+		assert.Equal(t, domain.TrackStatusUnsupported, result.TrackResults[1].Status)
+		assert.Equal(t, 1, dest.searchCallCount)
+	})
+
+	t.Run("episode-capable destination", func(t *testing.T) {
+		dest := &episodeProvider{&mockProvider{name: "dest", createdID: "pl", searchResults: searchResults}}
+		registry := adapters.NewProviderRegistry()
+		registry.Register(&mockProvider{name: "source", tracks: tracks})
+		registry.Register(dest)
+
+		result, err := newService(registry, 2).MigratePlaylist(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.MatchedTracks)
+		assert.Equal(t, domain.TrackStatusMatched, result.TrackResults[1].Status)
+		assert.ElementsMatch(t, []string{"vid-a", "ep-Episode 1"}, dest.addedTracks)
+	})
+}
+
 func TestMigratePlaylist_EmptyPlaylist(t *testing.T) {
 	source := &mockProvider{
 		name:   "source",
@@ -179,7 +389,7 @@ func TestMigratePlaylist_EmptyPlaylist(t *testing.T) {
 	registry.Register(source)
 	registry.Register(dest)
 
-	svc := NewService(registry, 2)
+	svc := newService(registry, 2)
 	_, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
 		SourceProvider: "source",
 		SourceToken:    "t1",
@@ -194,7 +404,7 @@ func TestMigratePlaylist_EmptyPlaylist(t *testing.T) {
 
 func TestMigratePlaylist_UnknownProvider(t *testing.T) {
 	registry := adapters.NewProviderRegistry()
-	svc := NewService(registry, 2)
+	svc := newService(registry, 2)
 
 	_, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
 		SourceProvider: "unknown",
@@ -212,8 +422,8 @@ func TestMigratePlaylist_ConcurrencyWorkerCount(t *testing.T) {
 	tracks := make([]domain.Track, 20)
 	for i := range tracks {
 		tracks[i] = domain.Track{
-			Name:   fmt.Sprintf("Track %d", i),
-			Artist: fmt.Sprintf("Artist %d", i),
+			Name:    fmt.Sprintf("Track %d", i),
+			Artists: []string{fmt.Sprintf("Artist %d", i)},
 		}
 	}
 
@@ -225,7 +435,7 @@ func TestMigratePlaylist_ConcurrencyWorkerCount(t *testing.T) {
 		searchResults[key] = &searchResult{
 			track: &domain.Track{
 				Name:       fmt.Sprintf("Track %d", i),
-				Artist:     fmt.Sprintf("Artist %d", i),
+				Artists:    []string{fmt.Sprintf("Artist %d", i)},
 				ExternalID: fmt.Sprintf("vid-%d", i),
 			},
 			score: 0.9,
@@ -242,7 +452,7 @@ func TestMigratePlaylist_ConcurrencyWorkerCount(t *testing.T) {
 	registry.Register(source)
 	registry.Register(dest)
 
-	svc := NewService(registry, 5)
+	svc := newService(registry, 5)
 	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
 		SourceProvider: "source",
 		SourceToken:    "t1",
@@ -265,6 +475,28 @@ func TestMigratePlaylist_ConcurrencyWorkerCount(t *testing.T) {
 	assert.Equal(t, 20, statusCounts[domain.TrackStatusMatched])
 }
 
+func TestGetPlaylist_IncludesTracks(t *testing.T) {
+	provider := &mockProvider{
+		name:      "test",
+		playlists: []domain.Playlist{{ID: "1", Name: "Playlist A", TrackCount: 99}},
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}},
+			{Name: "Track B", Artists: []string{"Artist B"}},
+		},
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(provider)
+
+	svc := newService(registry, 2)
+	playlist, err := svc.GetPlaylist(context.Background(), "test", "token", "1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Playlist A", playlist.Name)
+	assert.Equal(t, 2, playlist.TrackCount)
+	assert.Len(t, playlist.Tracks, 2)
+}
+
 func TestListPlaylists(t *testing.T) {
 	provider := &mockProvider{
 		name: "test",
@@ -277,10 +509,762 @@ func TestListPlaylists(t *testing.T) {
 	registry := adapters.NewProviderRegistry()
 	registry.Register(provider)
 
-	svc := NewService(registry, 2)
+	svc := newService(registry, 2)
 	playlists, err := svc.ListPlaylists(context.Background(), "test", "token")
 
 	require.NoError(t, err)
 	assert.Len(t, playlists, 2)
 	assert.Equal(t, "Playlist A", playlists[0].Name)
 }
+
+func TestRollbackMigration(t *testing.T) {
+	source := &mockProvider{
+		name:   "source",
+		tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+	}
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "dest-pl",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {
+				track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+				score: 0.9,
+			},
+		},
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1)
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.ID)
+
+	rolledBack, err := svc.RollbackMigration(context.Background(), result.ID, "t2")
+	require.NoError(t, err)
+	assert.True(t, rolledBack.RolledBack)
+	assert.Equal(t, []string{"dest-pl"}, dest.deletedIDs)
+
+	_, err = svc.RollbackMigration(context.Background(), result.ID, "t2")
+	require.ErrorIs(t, err, domain.ErrAlreadyRolledBack)
+}
+
+func TestRollbackMigration_NotFound(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+
+	_, err := svc.RollbackMigration(context.Background(), "missing", "t")
+	require.ErrorIs(t, err, domain.ErrMigrationNotFound)
+}
+
+func TestReverseMigration(t *testing.T) {
+	source := &mockProvider{
+		name:      "source",
+		createdID: "source-pl",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "sp-a"},
+			{Name: "Track B", Artists: []string{"Artist B"}, ExternalID: "sp-b"},
+		},
+		searchResults: map[string]*searchResult{
+			"Track X|Artist X": {track: &domain.Track{Name: "Track X", ExternalID: "sp-x"}, score: 0.8},
+		},
+	}
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "dest-pl",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {track: &domain.Track{Name: "Track A", ExternalID: "vid-a"}, score: 0.9},
+			"Track B|Artist B": {track: &domain.Track{Name: "Track B", ExternalID: "vid-b"}, score: 0.7},
+		},
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1)
+	original, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+	require.NoError(t, err)
+
+	// The destination playlist now holds the migrated tracks plus one added
+	// by the user afterwards.
+	dest.tracks = []domain.Track{
+		{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+		{Name: "Track B", Artists: []string{"Artist B"}, ExternalID: "vid-b"},
+		{Name: "Track X", Artists: []string{"Artist X"}, ExternalID: "vid-x"},
+	}
+
+	reversed, err := svc.ReverseMigration(context.Background(), original.ID, domain.ReverseMigrationRequest{
+		SourceToken: "t2",
+		DestToken:   "t1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, original.ID, reversed.ReversedFrom)
+	assert.Equal(t, "dest", reversed.SourceProvider)
+	assert.Equal(t, "source", reversed.DestProvider)
+	assert.Equal(t, "dest-pl", reversed.SourcePlaylist)
+	assert.Equal(t, 3, reversed.MatchedTracks)
+	assert.Equal(t, 0.7, reversed.TrackResults[1].ConfidenceScore)
+	assert.Equal(t, []string{"sp-a", "sp-b", "sp-x"}, source.addedTracks)
+	assert.Equal(t, 1, source.searchCallCount, "known matches must not be searched again")
+}
+
+func TestReverseMigration_DryRun(t *testing.T) {
+	source := &mockProvider{
+		name:   "source",
+		tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+	}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(&mockProvider{name: "dest"})
+
+	svc := newService(registry, 1)
+	original, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		DryRun:         true,
+	})
+	require.NoError(t, err)
+
+	_, err = svc.ReverseMigration(context.Background(), original.ID, domain.ReverseMigrationRequest{SourceToken: "t2", DestToken: "t1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dry run")
+}
+
+func TestMigrationHistory_ScopedToAccount(t *testing.T) {
+	source := &mockProvider{
+		name:   "source",
+		tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+	}
+	dest := &mockProvider{name: "dest", createdID: "dest-pl"}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1)
+	alice := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "alice"})
+	bob := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "bob"})
+
+	result, err := svc.MigratePlaylist(alice, domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", result.AccountID)
+
+	history, err := svc.ListMigrations(alice)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	history, err = svc.ListMigrations(bob)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	_, err = svc.GetMigration(bob, result.ID)
+	require.ErrorIs(t, err, domain.ErrMigrationNotFound)
+
+	_, err = svc.RollbackMigration(bob, result.ID, "t2")
+	require.ErrorIs(t, err, domain.ErrMigrationNotFound)
+}
+
+func TestListPlaylists_TokenFromVault(t *testing.T) {
+	provider := &mockProvider{name: "test"}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(provider)
+
+	vault, err := NewTokenVault(memory.NewTokenStore(), testKey)
+	require.NoError(t, err)
+
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-1"})
+	require.NoError(t, vault.StoreToken(ctx, "test", domain.ProviderToken{AccessToken: "stored-token"}))
+
+	svc := newService(registry, 1, WithTokenVault(vault))
+
+	_, err = svc.ListPlaylists(ctx, "test", "")
+	require.NoError(t, err)
+	assert.Equal(t, "stored-token", provider.lastToken)
+
+	// An explicit token takes precedence over the stored one.
+	_, err = svc.ListPlaylists(ctx, "test", "explicit-token")
+	require.NoError(t, err)
+	assert.Equal(t, "explicit-token", provider.lastToken)
+}
+
+func TestMigratePlaylist_MissingToken(t *testing.T) {
+	registry := adapters.NewProviderRegistry()
+	registry.Register(&mockProvider{name: "source"})
+	registry.Register(&mockProvider{name: "dest"})
+	req := domain.MigrationRequest{SourceProvider: "source", SourceToken: "t1", DestProvider: "dest", PlaylistID: "pl-1"}
+
+	_, err := newService(registry, 1).MigratePlaylist(context.Background(), req)
+	assert.ErrorIs(t, err, domain.ErrMissingToken)
+
+	vault, err := NewTokenVault(memory.NewTokenStore(), testKey)
+	require.NoError(t, err)
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-1"})
+	_, err = newService(registry, 1, WithTokenVault(vault)).MigratePlaylist(ctx, req)
+	assert.ErrorIs(t, err, domain.ErrMissingToken, "the vault holds no dest token either")
+
+	jobs := NewJobService(newService(registry, 1), memory.NewJobQueue())
+	_, err = jobs.EnqueueMigration(context.Background(), req)
+	assert.ErrorIs(t, err, domain.ErrMissingToken, "jobs are refused before they are queued")
+}
+
+func TestMigratePlaylist_DryRun(t *testing.T) {
+	source := &mockProvider{
+		name: "source",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}},
+			{Name: "Track B", Artists: []string{"Artist B"}},
+		},
+	}
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "should-not-be-created",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {
+				track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+				score: 0.9,
+			},
+		},
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	var progress []int
+	svc := newService(registry, 1, WithProgress(func(done, total int) {
+		assert.Equal(t, 2, total)
+		progress = append(progress, done)
+	}))
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		DryRun:         true,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Empty(t, result.DestPlaylistID)
+	assert.Equal(t, 1, result.MatchedTracks)
+	assert.Empty(t, dest.addedTracks)
+	assert.Equal(t, []int{1, 2}, progress)
+
+	_, err = svc.RollbackMigration(context.Background(), result.ID, "t2")
+	require.Error(t, err)
+}
+
+func TestSearchTracks_FallsBackToSingleMatch(t *testing.T) {
+	provider := &mockProvider{
+		name: "test",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {
+				track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "id-a"},
+				score: 0.8,
+			},
+		},
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(provider)
+
+	svc := newService(registry, 1)
+	candidates, err := svc.SearchTracks(context.Background(), "test", "token", domain.Track{Name: "Track A", Artists: []string{"Artist A"}})
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "id-a", candidates[0].Track.ExternalID)
+	assert.Equal(t, 0.8, candidates[0].ConfidenceScore)
+
+	candidates, err = svc.SearchTracks(context.Background(), "test", "token", domain.Track{Name: "Unknown"})
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+}
+
+func TestRetryFailedTracks(t *testing.T) {
+	source := &mockProvider{
+		name: "source",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}},
+			{Name: "Track B", Artists: []string{"Artist B"}},
+		},
+	}
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "dest-pl",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {
+				track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+				score: 0.9,
+			},
+			"Track B|Artist B": {err: fmt.Errorf("temporary failure")},
+		},
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1)
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.FailedTracks)
+	assert.Equal(t, map[string]int{"provider_error": 1}, result.ErrorBreakdown)
+
+	// The transient failure clears up.
+	dest.searchResults["Track B|Artist B"] = &searchResult{
+		track: &domain.Track{Name: "Track B", Artists: []string{"Artist B"}, ExternalID: "vid-b"},
+		score: 0.8,
+	}
+
+	retried, err := svc.RetryFailedTracks(context.Background(), result.ID, "t2")
+	require.NoError(t, err)
+	assert.Equal(t, 2, retried.MatchedTracks)
+	assert.Equal(t, 0, retried.FailedTracks)
+	assert.Nil(t, retried.ErrorBreakdown)
+	assert.Equal(t, domain.TrackStatusMatched, retried.TrackResults[1].Status)
+	assert.Equal(t, []string{"vid-a", "vid-b"}, dest.addedTracks)
+	assert.Equal(t, 3, dest.searchCallCount)
+
+	stored, err := svc.GetMigration(context.Background(), result.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.MatchedTracks)
+}
+
+func TestMigratePlaylist_AddFailed(t *testing.T) {
+	source := &mockProvider{
+		name: "source",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}},
+			{Name: "Track B", Artists: []string{"Artist B"}},
+			{Name: "Track C", Artists: []string{"Artist C"}},
+		},
+	}
+	dest := &mockProvider{
+		name:      "dest",
+		createdID: "dest-pl",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {track: &domain.Track{ExternalID: "vid-a"}, score: 0.9},
+			"Track B|Artist B": {track: &domain.Track{ExternalID: "vid-b"}, score: 0.9},
+			"Track C|Artist C": {track: &domain.Track{ExternalID: "vid-c"}, score: 0.9},
+		},
+		rejectAdd: map[string]bool{"vid-b": true},
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1)
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "dest-pl", result.DestPlaylistID)
+	assert.Equal(t, 2, result.MatchedTracks)
+	assert.Equal(t, 1, result.FailedTracks)
+	assert.Equal(t, domain.TrackStatusAddFailed, result.TrackResults[1].Status)
+	assert.Equal(t, "rejected", result.TrackResults[1].Error)
+	assert.Equal(t, "vid-b", result.TrackResults[1].MatchedTrack.ExternalID)
+	assert.Equal(t, domain.ErrorCodeRejected, result.TrackResults[1].ErrorCode)
+	assert.False(t, result.TrackResults[1].Retryable)
+	assert.Nil(t, result.TrackResults[1].DestPosition)
+	assert.Equal(t, 1, *result.TrackResults[2].DestPosition)
+
+	// A track the destination refused is not retried.
+	dest.rejectAdd = nil
+	retried, err := svc.RetryFailedTracks(context.Background(), result.ID, "t2")
+	require.NoError(t, err)
+	assert.Equal(t, 1, retried.FailedTracks)
+	assert.Equal(t, []string{"vid-a", "vid-c"}, dest.addedTracks)
+}
+
+// failingAdder is a mockProvider whose next add call fails with err.
+type failingAdder struct {
+	*mockProvider
+	err error
+}
+
+func (f *failingAdder) AddTracksToPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) ([]domain.AddOutcome, error) {
+	if err := f.err; err != nil {
+		f.err = nil
+		return domain.NotAdded(nil, trackIDs, err), err
+	}
+	return f.mockProvider.AddTracksToPlaylist(ctx, token, playlistID, trackIDs)
+}
+
+func TestRetryFailedTracks_ReaddsRetryableFailures(t *testing.T) {
+	source := &mockProvider{
+		name:   "source",
+		tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+	}
+	dest := &failingAdder{
+		mockProvider: &mockProvider{
+			name:      "dest",
+			createdID: "dest-pl",
+			searchResults: map[string]*searchResult{
+				"Track A|Artist A": {track: &domain.Track{ExternalID: "vid-a"}, score: 0.9},
+			},
+		},
+		err: fmt.Errorf("dest: %w", domain.ErrRateLimited),
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1)
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TrackStatusAddFailed, result.TrackResults[0].Status)
+	assert.Equal(t, domain.ErrorCodeRateLimited, result.TrackResults[0].ErrorCode)
+	assert.True(t, result.TrackResults[0].Retryable)
+
+	// Retry adds it again without searching.
+	retried, err := svc.RetryFailedTracks(context.Background(), result.ID, "t2")
+	require.NoError(t, err)
+	assert.Equal(t, 0, retried.FailedTracks)
+	assert.Equal(t, domain.TrackStatusMatched, retried.TrackResults[0].Status)
+	assert.Empty(t, retried.TrackResults[0].ErrorCode)
+	assert.Equal(t, []string{"vid-a"}, dest.addedTracks)
+	assert.Equal(t, 1, dest.searchCallCount)
+}
+
+func TestMigratePlaylist_SourceOnlyDestination(t *testing.T) {
+	source := &mockProvider{name: "source", tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}}}
+	dest := &sourceOnlyProvider{&mockProvider{name: "files"}}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1)
+	_, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "files",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		DryRun:         true,
+	})
+
+	assert.ErrorIs(t, err, domain.ErrSourceOnlyProvider)
+	assert.Zero(t, dest.searchCallCount)
+}
+
+func TestMigratePlaylist_CheckTokens(t *testing.T) {
+	missing := &domain.MissingScopeError{Provider: "dest", Scope: "playlist-modify-private"}
+	tests := []struct {
+		name    string
+		err     error
+		dryRun  bool
+		wantErr error
+	}{
+		{name: "valid"},
+		{name: "dry run", dryRun: true},
+		{name: "missing scope", err: missing, wantErr: domain.ErrInsufficientScope},
+		{name: "invalid token", err: domain.ErrInvalidToken, wantErr: domain.ErrInvalidToken},
+		{name: "check unavailable", err: errors.New("connection refused")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &tokenCheckProvider{mockProvider: &mockProvider{name: "source", tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}}}}
+			dest := &tokenCheckProvider{mockProvider: &mockProvider{name: "dest", createdID: "new-pl"}, err: tt.err}
+
+			registry := adapters.NewProviderRegistry()
+			registry.Register(source)
+			registry.Register(dest)
+
+			svc := newService(registry, 1)
+			_, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+				SourceProvider: "source",
+				SourceToken:    "t1",
+				DestProvider:   "dest",
+				DestToken:      "t2",
+				PlaylistID:     "pl-1",
+				DryRun:         tt.dryRun,
+			})
+
+			assert.Equal(t, []bool{false}, source.writes)
+			assert.Equal(t, []bool{!tt.dryRun}, dest.writes)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Zero(t, dest.searchCallCount)
+			if tt.err == missing {
+				assert.Contains(t, err.Error(), "missing required scope playlist-modify-private")
+			}
+		})
+	}
+}
+
+func TestMigratePlaylist_SanitizesPlaylistText(t *testing.T) {
+	source := &mockProvider{name: "source", tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}}}
+	dest := &sanitizingProvider{mockProvider: &mockProvider{
+		name:          "dest",
+		createdID:     "new-pl",
+		searchResults: map[string]*searchResult{"Track A|Artist A": {track: &domain.Track{ExternalID: "a"}, score: 0.9}},
+	}}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1)
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Migrated", result.DestPlaylistName)
+	assert.Equal(t, "sanitized: Migrated 1/1 tracks", result.DestPlaylistDescription)
+}
+
+func TestMigratePlaylist_MatchingStrategy(t *testing.T) {
+	source := &mockProvider{name: "source", tracks: []domain.Track{
+		{Name: "Exact", Artists: []string{"A"}},
+		{Name: "Fuzzy", Artists: []string{"A"}},
+	}}
+	dest := &mockProvider{name: "dest", createdID: "new", searchResults: map[string]*searchResult{
+		"Exact|A": {track: &domain.Track{ExternalID: "d1"}, score: 0.95},
+		"Fuzzy|A": {track: &domain.Track{ExternalID: "d2"}, score: 0.5},
+	}}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+	svc := newService(registry, 2)
+
+	req := domain.MigrationRequest{
+		SourceProvider:   "source",
+		SourceToken:      "t1",
+		DestProvider:     "dest",
+		DestToken:        "t2",
+		PlaylistID:       "pl-1",
+		MatchingStrategy: domain.MatchingStrict,
+	}
+	result, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.MatchingStrict, result.MatchingStrategy)
+	assert.Equal(t, 1, result.MatchedTracks)
+	assert.Equal(t, []string{"d1"}, dest.addedTracks)
+	fuzzy := result.TrackResults[1]
+	assert.Equal(t, domain.TrackStatusNotFound, fuzzy.Status)
+	assert.Nil(t, fuzzy.MatchedTrack)
+	assert.Equal(t, "best candidate scored 0.50, below the strict minimum of 0.70", fuzzy.Error)
+
+	req.MatchingStrategy = "fuzzy"
+	_, err = svc.MigratePlaylist(context.Background(), req)
+	assert.EqualError(t, err, `unknown matching strategy "fuzzy"`)
+}
+
+func TestMigratePlaylist_MinScore(t *testing.T) {
+	source := &mockProvider{name: "source", tracks: []domain.Track{
+		{Name: "Close", Artists: []string{"A"}},
+		{Name: "Loose", Artists: []string{"A"}},
+	}}
+	dest := &mockProvider{name: "dest", createdID: "new", searchResults: map[string]*searchResult{
+		"Close|A": {track: &domain.Track{ExternalID: "d1"}, score: 0.9},
+		"Loose|A": {track: &domain.Track{ExternalID: "d2"}, score: 0.75},
+	}}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+	svc := newService(registry, 2)
+
+	req := domain.MigrationRequest{
+		SourceProvider:   "source",
+		SourceToken:      "t1",
+		DestProvider:     "dest",
+		DestToken:        "t2",
+		PlaylistID:       "pl-1",
+		MatchingStrategy: domain.MatchingStrict,
+		MinScore:         0.8,
+	}
+	result, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.8, result.MinScore)
+	assert.Equal(t, []string{"d1"}, dest.addedTracks)
+	assert.Equal(t, "best candidate scored 0.75, below the requested minimum of 0.80", result.TrackResults[1].Error)
+
+	req.MinScore = 1.5
+	_, err = svc.MigratePlaylist(context.Background(), req)
+	assert.EqualError(t, err, "min score 1.50 is not between 0 and 1")
+}
+
+func TestMigratePlaylist_ReportsRunnerUps(t *testing.T) {
+	source := &mockProvider{name: "source", tracks: []domain.Track{
+		{Name: "Close", Artists: []string{"A"}},
+		{Name: "Loose", Artists: []string{"A"}},
+		{Name: "Missing", Artists: []string{"A"}},
+	}}
+	runnerUps := []domain.TrackCandidate{
+		{Track: domain.Track{ExternalID: "r1"}, ConfidenceScore: 0.7},
+		{Track: domain.Track{ExternalID: "r2"}, ConfidenceScore: 0.6},
+		{Track: domain.Track{ExternalID: "r3"}, ConfidenceScore: 0.5},
+		{Track: domain.Track{ExternalID: "r4"}, ConfidenceScore: 0.4},
+	}
+	dest := &mockProvider{name: "dest", createdID: "new", searchResults: map[string]*searchResult{
+		"Close|A": {track: &domain.Track{ExternalID: "d1"}, score: 0.9, runnerUps: runnerUps},
+		"Loose|A": {track: &domain.Track{ExternalID: "d2"}, score: 0.2, runnerUps: runnerUps[3:]},
+	}}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+	svc := newService(registry, 2)
+
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider:   "source",
+		SourceToken:      "t1",
+		DestProvider:     "dest",
+		DestToken:        "t2",
+		PlaylistID:       "pl-1",
+		DryRun:           true,
+		MatchingStrategy: domain.MatchingStrict,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, runnerUps[:3], result.TrackResults[0].Candidates, "runner-ups are capped")
+	assert.Equal(t, domain.TrackStatusNotFound, result.TrackResults[1].Status)
+	assert.Equal(t, runnerUps[3:], result.TrackResults[1].Candidates, "rejected matches keep their runner-ups")
+	assert.Empty(t, result.TrackResults[2].Candidates)
+}
+
+func TestMigratePlaylist_AnyRerecordings(t *testing.T) {
+	source := &mockProvider{name: "source", tracks: []domain.Track{
+		{Name: "Love Story", Artists: []string{"Taylor Swift"}, ReleaseDate: "2008-11-11"},
+	}}
+	dest := &mockProvider{name: "dest", createdID: "new", searchResults: map[string]*searchResult{
+		"Love Story|Taylor Swift": {track: &domain.Track{ExternalID: "d1", ReleaseDate: "2021-04-09"}, score: 0.95},
+	}}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+	svc := newService(registry, 1)
+
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		Rerecordings:   domain.RerecordingsAny,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.RerecordingsAny, result.Rerecordings)
+	assert.Equal(t, domain.RerecordingsAny, dest.lastMatchOpts.Rerecordings)
+	assert.Equal(t, "2021-04-09", result.TrackResults[0].MatchedTrack.ReleaseDate)
+}
+
+func TestMigratePlaylist_Rerecordings(t *testing.T) {
+	source := &mockProvider{name: "source", tracks: []domain.Track{
+		{Name: "Love Story", Artists: []string{"Taylor Swift"}},
+	}}
+	dest := &mockProvider{name: "dest", createdID: "new", searchResults: map[string]*searchResult{
+		"Love Story|Taylor Swift": {track: &domain.Track{ExternalID: "d1"}, score: 0.95},
+	}}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+	svc := newService(registry, 1)
+
+	req := domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		Rerecordings:   domain.RerecordingsAvoid,
+	}
+	result, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.RerecordingsAvoid, result.Rerecordings)
+	assert.Equal(t, domain.RerecordingsAvoid, dest.lastMatchOpts.Rerecordings)
+
+	req.Rerecordings = "sometimes"
+	_, err = svc.MigratePlaylist(context.Background(), req)
+	assert.EqualError(t, err, `unknown rerecording preference "sometimes"`)
+}
+
+func TestMigratePlaylist_ISRCOnlyMarksUnconfirmedForReview(t *testing.T) {
+	source := &mockProvider{name: "source", tracks: []domain.Track{
+		{Name: "Confirmed", Artists: []string{"A"}, ISRC: "USRC17607839"},
+		{Name: "Fuzzy", Artists: []string{"A"}, ISRC: "GBUM71029604"},
+		{Name: "No ISRC", Artists: []string{"A"}},
+	}}
+	dest := &mockProvider{name: "dest", createdID: "new", searchResults: map[string]*searchResult{
+		"Confirmed|A": {track: &domain.Track{ExternalID: "d1", ISRC: "usrc17607839"}, score: 1},
+		"Fuzzy|A":     {track: &domain.Track{ExternalID: "d2", ISRC: "GBUM71029605"}, score: 0.9},
+		"No ISRC|A":   {track: &domain.Track{ExternalID: "d3"}, score: 0.9},
+	}}
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	result, err := newService(registry, 2).MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider:   "source",
+		SourceToken:      "t1",
+		DestProvider:     "dest",
+		DestToken:        "t2",
+		PlaylistID:       "pl-1",
+		MatchingStrategy: domain.MatchingISRCOnly,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.MatchedTracks)
+	assert.Equal(t, []string{"d1"}, dest.addedTracks)
+	for _, tr := range result.TrackResults[1:] {
+		assert.Equal(t, domain.TrackStatusNeedsReview, tr.Status)
+		require.NotNil(t, tr.MatchedTrack, "the candidate is kept as a suggestion")
+		assert.Nil(t, tr.DestPosition)
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// defaultNamePattern names destination playlists of requests without a
+// name pattern.
+const defaultNamePattern = "Migrated from {source}"
+
+// playlistName expands the name pattern of the request of run. The source
+// playlist is only fetched if the pattern uses its name. A pattern that
+// expands to blanks falls back to the default.
+func (s *Service) playlistName(ctx context.Context, run *migrationRun) (string, error) {
+	req := run.req
+	pattern := req.NamePattern
+	if pattern == "" {
+		pattern = defaultNamePattern
+	}
+
+	var sourceName string
+	if namesSourcePlaylist(req) {
+		var playlist *domain.Playlist
+		err := s.runStage(ctx, domain.StageFetch, func(ctx context.Context) error {
+			var err error
+			playlist, err = run.source.GetPlaylist(ctx, req.SourceToken, req.PlaylistID)
+			return err
+		})
+		s.chargeSourceQuota(run, 1)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch source playlist: %w", err)
+		}
+		sourceName = playlist.Name
+	}
+
+	name := expandNamePattern(pattern, req, sourceName, time.Now().UTC())
+	if strings.TrimSpace(name) == "" {
+		name = expandNamePattern(defaultNamePattern, req, sourceName, time.Now().UTC())
+	}
+	return name, nil
+}
+
+// namesSourcePlaylist reports whether the name pattern of req contains the
+// name of the source playlist.
+func namesSourcePlaylist(req domain.MigrationRequest) bool {
+	return strings.Contains(req.NamePattern, "{playlist}")
+}
+
+// expandNamePattern replaces the placeholders of pattern.
+func expandNamePattern(pattern string, req domain.MigrationRequest, sourceName string, now time.Time) string {
+	return strings.NewReplacer(
+		"{source}", req.SourceProvider,
+		"{dest}", req.DestProvider,
+		"{playlist}", sourceName,
+		"{date}", now.Format(time.DateOnly),
+	).Replace(pattern)
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandNamePattern(t *testing.T) {
+	req := domain.MigrationRequest{SourceProvider: "spotify", DestProvider: "youtube"}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "Migrated from spotify", expandNamePattern(defaultNamePattern, req, "", now))
+	assert.Equal(t, "Road trip (spotify → youtube, 2026-03-01)",
+		expandNamePattern("{playlist} ({source} → {dest}, {date})", req, "Road trip", now))
+}
+
+func TestPlaylistName(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	source := &mockProvider{name: "source", playlists: []domain.Playlist{{ID: "pl-1", Name: "Road trip"}}}
+
+	run := newRun(source, &mockProvider{name: "dest"}, domain.MigrationRequest{PlaylistID: "pl-1", NamePattern: "{playlist} on {dest}"})
+	name, err := svc.playlistName(context.Background(), run)
+	require.NoError(t, err)
+	assert.Equal(t, "Road trip on dest", name)
+
+	// A pattern that expands to nothing falls back to the default.
+	source.playlists[0].Name = ""
+	run = newRun(source, &mockProvider{name: "dest"}, domain.MigrationRequest{PlaylistID: "pl-1", NamePattern: " {playlist} "})
+	name, err = svc.playlistName(context.Background(), run)
+	require.NoError(t, err)
+	assert.Equal(t, "Migrated from source", name)
+
+	run = newRun(source, &mockProvider{name: "dest"}, domain.MigrationRequest{PlaylistID: "missing", NamePattern: "{playlist}"})
+	_, err = svc.playlistName(context.Background(), run)
+	assert.ErrorIs(t, err, domain.ErrPlaylistNotFound)
+}
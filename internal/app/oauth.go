@@ -0,0 +1,124 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// authorizationStateTTL is how long a user has to grant access on the
+// provider's consent page.
+const authorizationStateTTL = 10 * time.Minute
+
+// OAuthService implements ports.OAuthService. The state sent through the
+// provider's consent page names the account and provider and is signed, so
+// the callback needs neither an API key nor a session on this instance.
+type OAuthService struct {
+	registry *adapters.ProviderRegistry
+	tokens   ports.TokenVault
+	key      []byte
+	now      func() time.Time
+}
+
+// NewOAuthService creates an OAuth service that stores the tokens issued by
+// the providers of registry in tokens. States are signed with a key derived
+// from secret, such as the token encryption key.
+func NewOAuthService(registry *adapters.ProviderRegistry, tokens ports.TokenVault, secret []byte) *OAuthService {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("oauth state"))
+	return &OAuthService{registry: registry, tokens: tokens, key: mac.Sum(nil), now: time.Now}
+}
+
+// AuthorizeURL returns the consent page of provider with a state bound to
+// the caller's account.
+func (s *OAuthService) AuthorizeURL(ctx context.Context, provider string) (string, error) {
+	accountID, err := requireAccount(ctx)
+	if err != nil {
+		return "", err
+	}
+	authorizer, err := s.authorizer(provider)
+	if err != nil {
+		return "", err
+	}
+	return authorizer.AuthorizationURL(s.sign(accountID, provider))
+}
+
+// CompleteAuthorization stores the token issued for code under the account
+// named by state.
+func (s *OAuthService) CompleteAuthorization(ctx context.Context, provider, state, code string) error {
+	accountID, err := s.verify(state, provider)
+	if err != nil {
+		return err
+	}
+	authorizer, err := s.authorizer(provider)
+	if err != nil {
+		return err
+	}
+
+	token, err := authorizer.ExchangeCode(ctx, code)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrAuthorizationFailed, err)
+	}
+	ctx = domain.ContextWithAccount(ctx, &domain.Account{ID: accountID})
+	return s.tokens.StoreToken(ctx, provider, *token)
+}
+
+func (s *OAuthService) authorizer(provider string) (ports.TokenAuthorizer, error) {
+	p, err := s.registry.Get(provider)
+	if err != nil {
+		return nil, err
+	}
+	authorizer, ok := p.(ports.TokenAuthorizer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrAuthorizationUnavailable, provider)
+	}
+	return authorizer, nil
+}
+
+// sign encodes the account, provider and expiry followed by their MAC.
+func (s *OAuthService) sign(accountID, provider string) string {
+	expires := s.now().Add(authorizationStateTTL).Unix()
+	payload := base64.RawURLEncoding.EncodeToString([]byte(accountID + "\n" + provider + "\n" + strconv.FormatInt(expires, 10)))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.mac(payload))
+}
+
+// verify returns the account of a state signed for provider that has not
+// expired.
+func (s *OAuthService) verify(state, provider string) (string, error) {
+	payload, sig, ok := strings.Cut(state, ".")
+	if !ok {
+		return "", domain.ErrInvalidAuthorizationState
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.mac(payload)) {
+		return "", domain.ErrInvalidAuthorizationState
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", domain.ErrInvalidAuthorizationState
+	}
+	fields := strings.Split(string(decoded), "\n")
+	if len(fields) != 3 || fields[1] != provider {
+		return "", domain.ErrInvalidAuthorizationState
+	}
+	expires, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil || !s.now().Before(time.Unix(expires, 0)) {
+		return "", domain.ErrInvalidAuthorizationState
+	}
+	return fields[0], nil
+}
+
+func (s *OAuthService) mac(payload string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// authorizingProvider is a mockProvider that issues a token for the code
+// "code-1".
+type authorizingProvider struct {
+	*mockProvider
+}
+
+func (p *authorizingProvider) AuthorizationURL(state string) (string, error) {
+	return "https://accounts.example.com/authorize?state=" + url.QueryEscape(state), nil
+}
+
+func (p *authorizingProvider) ExchangeCode(_ context.Context, code string) (*domain.ProviderToken, error) {
+	if code != "code-1" {
+		return nil, errors.New("invalid_grant")
+	}
+	return &domain.ProviderToken{AccessToken: "access-1", RefreshToken: "refresh-1"}, nil
+}
+
+func newOAuthService(t *testing.T) (*OAuthService, *TokenVault) {
+	t.Helper()
+	registry := adapters.NewProviderRegistry()
+	registry.Register(&authorizingProvider{mockProvider: &mockProvider{name: "spotify"}})
+	registry.Register(&mockProvider{name: "lastfm"})
+
+	vault, err := NewTokenVault(memory.NewTokenStore(), testKey)
+	require.NoError(t, err)
+	return NewOAuthService(registry, vault, testKey), vault
+}
+
+// authorizationState returns the state of the consent page URL issued to
+// accountID.
+func authorizationState(t *testing.T, svc *OAuthService, accountID string) string {
+	t.Helper()
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: accountID})
+	authURL, err := svc.AuthorizeURL(ctx, "spotify")
+	require.NoError(t, err)
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	return u.Query().Get("state")
+}
+
+func TestOAuthService_StoresTokenForStateAccount(t *testing.T) {
+	svc, vault := newOAuthService(t)
+	state := authorizationState(t, svc, "acc-1")
+
+	require.NoError(t, svc.CompleteAuthorization(context.Background(), "spotify", state, "code-1"))
+
+	token, err := vault.GetToken(domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-1"}), "spotify")
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token.AccessToken)
+	assert.Equal(t, "refresh-1", token.RefreshToken)
+
+	err = svc.CompleteAuthorization(context.Background(), "spotify", state, "used")
+	assert.ErrorIs(t, err, domain.ErrAuthorizationFailed)
+}
+
+func TestOAuthService_RejectsInvalidState(t *testing.T) {
+	svc, _ := newOAuthService(t)
+	state := authorizationState(t, svc, "acc-1")
+	payload, sig, _ := strings.Cut(state, ".")
+	forged := authorizationState(t, svc, "acc-2")
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	for name, s := range map[string]string{
+		"empty":         "",
+		"unsigned":      payload,
+		"swapped":       forgedPayload + "." + sig,
+		"bad signature": payload + ".AAAA",
+	} {
+		err := svc.CompleteAuthorization(context.Background(), "spotify", s, "code-1")
+		assert.ErrorIs(t, err, domain.ErrInvalidAuthorizationState, name)
+	}
+
+	err := svc.CompleteAuthorization(context.Background(), "youtube", state, "code-1")
+	assert.ErrorIs(t, err, domain.ErrInvalidAuthorizationState, "state issued for another provider")
+
+	svc.now = func() time.Time { return time.Now().Add(authorizationStateTTL) }
+	err = svc.CompleteAuthorization(context.Background(), "spotify", state, "code-1")
+	assert.ErrorIs(t, err, domain.ErrInvalidAuthorizationState, "expired state")
+}
+
+func TestOAuthService_ProviderWithoutAuthorization(t *testing.T) {
+	svc, _ := newOAuthService(t)
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-1"})
+
+	_, err := svc.AuthorizeURL(ctx, "lastfm")
+	assert.ErrorIs(t, err, domain.ErrAuthorizationUnavailable)
+
+	_, err = svc.AuthorizeURL(context.Background(), "spotify")
+	assert.Error(t, err, "linking requires an account")
+}
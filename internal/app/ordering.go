@@ -0,0 +1,98 @@
+package app
+
+import "github.com/jpp0ca/MusicMigration-API/internal/domain"
+
+// isPlaced reports whether a result's track is, or is about to be, in the
+// destination playlist.
+func isPlaced(tr domain.TrackResult) bool {
+	return tr.Status == domain.TrackStatusMatched && tr.MatchedTrack != nil
+}
+
+// assignPositions sets the source position of every result and the
+// destination position of every matched one, assuming matched tracks are in
+// the destination playlist in source order. Matches for review are numbered
+// in the review playlist. It returns the source positions without a
+// destination track.
+func assignPositions(results []domain.TrackResult) (gaps []int) {
+	dest, review := 0, 0
+	for i := range results {
+		results[i].SourcePosition = i
+		results[i].DestPosition = nil
+		if !isPlaced(results[i]) {
+			gaps = append(gaps, i)
+			continue
+		}
+		next := &dest
+		if results[i].Review {
+			next = &review
+		}
+		pos := *next
+		results[i].DestPosition = &pos
+		*next++
+	}
+	return gaps
+}
+
+// placeAfterExisting corrects the destination positions of results added
+// to a reused playlist that already held count tracks: tracks it held keep
+// their position in it, given by existing, and the others follow in order.
+func placeAfterExisting(results []domain.TrackResult, existing map[string]int, count int) {
+	next := count
+	for i := range results {
+		if results[i].DestPosition == nil || results[i].Review {
+			continue
+		}
+		pos := next
+		if results[i].Existing {
+			pos = existing[results[i].MatchedTrack.ExternalID]
+		} else {
+			next++
+		}
+		results[i].DestPosition = &pos
+	}
+}
+
+// insertionRun is a contiguous block of tracks to insert into the
+// destination playlist.
+type insertionRun struct {
+	position int
+	trackIDs []string
+}
+
+// insertionRuns re-assigns positions after late matches (results whose index
+// is in added) and groups those matches into contiguous runs. Inserting the
+// runs in order, each at its position, leaves the destination playlist in
+// source order.
+func insertionRuns(results []domain.TrackResult, added map[int]bool) (runs []insertionRun, gaps []int) {
+	gaps = assignPositions(results)
+	for i, tr := range results {
+		if !added[i] || tr.DestPosition == nil {
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1].position+len(runs[n-1].trackIDs) == *tr.DestPosition {
+			runs[n-1].trackIDs = append(runs[n-1].trackIDs, tr.MatchedTrack.ExternalID)
+			continue
+		}
+		runs = append(runs, insertionRun{position: *tr.DestPosition, trackIDs: []string{tr.MatchedTrack.ExternalID}})
+	}
+	return runs, gaps
+}
+
+// appendPositions gives late matches (results whose index is in added) the
+// destination positions after every track already placed in the same
+// playlist, the main or the review playlist, in source order.
+func appendPositions(results []domain.TrackResult, added map[int]bool, review bool) {
+	next := 0
+	for i, tr := range results {
+		if !added[i] && tr.DestPosition != nil && tr.Review == review {
+			next = max(next, *tr.DestPosition+1)
+		}
+	}
+	for i := range results {
+		if added[i] && isPlaced(results[i]) {
+			pos := next
+			results[i].DestPosition = &pos
+			next++
+		}
+	}
+}
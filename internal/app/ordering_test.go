@@ -0,0 +1,190 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// positionalProvider adds ports.PositionalAdder to a mockProvider.
+type positionalProvider struct {
+	*mockProvider
+	inserts []insertionRun
+}
+
+func (p *positionalProvider) InsertTracksAt(_ context.Context, _ string, _ string, position int, trackIDs []string) ([]domain.AddOutcome, error) {
+	p.inserts = append(p.inserts, insertionRun{position: position, trackIDs: trackIDs})
+	outcomes := make([]domain.AddOutcome, 0, len(trackIDs))
+	for _, id := range trackIDs {
+		if p.rejectAdd[id] {
+			outcomes = append(outcomes, domain.AddOutcome{TrackID: id, Error: "rejected"})
+			continue
+		}
+		outcomes = append(outcomes, domain.AddOutcome{TrackID: id, Added: true})
+	}
+	return outcomes, nil
+}
+
+func matchedResult(id string) domain.TrackResult {
+	return domain.TrackResult{Status: domain.TrackStatusMatched, MatchedTrack: &domain.Track{ExternalID: id}}
+}
+
+func destPositions(results []domain.TrackResult) []any {
+	positions := make([]any, len(results))
+	for i, tr := range results {
+		if tr.DestPosition != nil {
+			positions[i] = *tr.DestPosition
+		}
+	}
+	return positions
+}
+
+func TestAssignPositions(t *testing.T) {
+	results := []domain.TrackResult{
+		matchedResult("a"),
+		{Status: domain.TrackStatusNotFound},
+		matchedResult("c"),
+	}
+
+	gaps := assignPositions(results)
+
+	assert.Equal(t, []int{1}, gaps)
+	assert.Equal(t, []any{0, nil, 1}, destPositions(results))
+	assert.Equal(t, 2, results[2].SourcePosition)
+}
+
+func TestInsertionRuns(t *testing.T) {
+	results := []domain.TrackResult{
+		matchedResult("a"),
+		matchedResult("b"), // late
+		matchedResult("c"), // late
+		matchedResult("d"),
+		{Status: domain.TrackStatusNotFound},
+		matchedResult("f"), // late
+	}
+
+	runs, gaps := insertionRuns(results, map[int]bool{1: true, 2: true, 5: true})
+
+	assert.Equal(t, []int{4}, gaps)
+	assert.Equal(t, []insertionRun{
+		{position: 1, trackIDs: []string{"b", "c"}},
+		{position: 4, trackIDs: []string{"f"}},
+	}, runs)
+}
+
+func TestAppendPositions(t *testing.T) {
+	// b is a late match; a and c were added by the original migration.
+	results := []domain.TrackResult{matchedResult("a"), matchedResult("b"), matchedResult("c")}
+	first, second := 0, 1
+	results[0].DestPosition = &first
+	results[2].DestPosition = &second
+
+	appendPositions(results, map[int]bool{1: true}, false)
+
+	assert.Equal(t, []any{0, 2, 1}, destPositions(results))
+}
+
+func TestRetryFailedTracks_PreserveOrder(t *testing.T) {
+	source := &mockProvider{
+		name: "source",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}},
+			{Name: "Track B", Artists: []string{"Artist B"}},
+			{Name: "Track C", Artists: []string{"Artist C"}},
+		},
+	}
+	dest := &positionalProvider{mockProvider: &mockProvider{
+		name:      "dest",
+		createdID: "dest-pl",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {track: &domain.Track{ExternalID: "vid-a"}, score: 0.9},
+			"Track B|Artist B": {err: fmt.Errorf("temporary failure")},
+			"Track C|Artist C": {track: &domain.Track{ExternalID: "vid-c"}, score: 0.9},
+		},
+	}}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1)
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		PreserveOrder:  true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, result.Gaps)
+	assert.Equal(t, []any{0, nil, 1}, destPositions(result.TrackResults))
+
+	dest.searchResults["Track B|Artist B"] = &searchResult{track: &domain.Track{ExternalID: "vid-b"}, score: 0.8}
+
+	retried, err := svc.RetryFailedTracks(context.Background(), result.ID, "t2")
+	require.NoError(t, err)
+	assert.Empty(t, retried.Gaps)
+	assert.Equal(t, []any{0, 1, 2}, destPositions(retried.TrackResults))
+	assert.Equal(t, 1, retried.TrackResults[1].SourcePosition)
+	assert.Equal(t, []insertionRun{{position: 1, trackIDs: []string{"vid-b"}}}, dest.inserts)
+	assert.Equal(t, []string{"vid-a", "vid-c"}, dest.addedTracks, "late matches must not be appended")
+}
+
+func TestRetryFailedTracks_PreserveOrderInsertFails(t *testing.T) {
+	source := &mockProvider{
+		name: "source",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}},
+			{Name: "Track B", Artists: []string{"Artist B"}},
+			{Name: "Track C", Artists: []string{"Artist C"}},
+			{Name: "Track D", Artists: []string{"Artist D"}},
+		},
+	}
+	dest := &positionalProvider{mockProvider: &mockProvider{
+		name:      "dest",
+		createdID: "dest-pl",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {track: &domain.Track{ExternalID: "vid-a"}, score: 0.9},
+			"Track C|Artist C": {track: &domain.Track{ExternalID: "vid-c"}, score: 0.9},
+		},
+		rejectAdd: map[string]bool{"vid-b": true},
+	}}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1)
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		PreserveOrder:  true,
+	})
+	require.NoError(t, err)
+
+	dest.searchResults["Track B|Artist B"] = &searchResult{track: &domain.Track{ExternalID: "vid-b"}, score: 0.8}
+	dest.searchResults["Track D|Artist D"] = &searchResult{track: &domain.Track{ExternalID: "vid-d"}, score: 0.8}
+
+	retried, err := svc.RetryFailedTracks(context.Background(), result.ID, "t2")
+	require.NoError(t, err)
+
+	// vid-b is rejected, so vid-d moves up into the position it would have taken.
+	assert.Equal(t, []insertionRun{
+		{position: 1, trackIDs: []string{"vid-b"}},
+		{position: 2, trackIDs: []string{"vid-d"}},
+	}, dest.inserts)
+	assert.Equal(t, domain.TrackStatusAddFailed, retried.TrackResults[1].Status)
+	assert.Equal(t, []int{1}, retried.Gaps)
+	assert.Equal(t, []any{0, nil, 1, 2}, destPositions(retried.TrackResults))
+	assert.Equal(t, 3, retried.MatchedTracks)
+	assert.Equal(t, 1, retried.FailedTracks)
+}
@@ -0,0 +1,795 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// migrationRun is the state of one migration as it passes through the
+// pipeline stages. Each stage reads what earlier stages left and adds its
+// own part.
+type migrationRun struct {
+	req    domain.MigrationRequest
+	opts   migrateOptions
+	source ports.MusicProvider
+	dest   ports.MusicProvider
+
+	// limits are the limits of the account running the migration, if any.
+	limits *domain.AccountLimits
+
+	// filter is the request's exclusion and genre filter, if any. excluded
+	// holds the reason it excludes each track, or is nil if it excludes none.
+	filter   *trackFilter
+	excluded []string
+
+	// tracks are the source playlist's tracks and results their outcome, in
+	// the same order. pending indexes the tracks still to be searched.
+	tracks  []domain.Track
+	results []domain.TrackResult
+	pending []int
+
+	// name is the name the destination playlist is created under. reuse is
+	// the existing destination playlist the tracks are added to instead,
+	// under the "reuse" conflict policy; it held held tracks, and existing
+	// maps their IDs to their positions.
+	name     string
+	reuse    *domain.Playlist
+	held     int
+	existing map[string]int
+
+	destPlaylistIDs  []string
+	destName         string
+	destDescription  string
+	reviewPlaylistID string
+
+	concurrency *domain.ConcurrencyStats
+	timing      *domain.MigrationTiming
+	warnings    []string
+
+	// quotaUsed and sourceQuotaUsed count the units spent on the
+	// destination and the source; destination units are drawn from
+	// quota, if the match stage reserved any.
+	quotaUsed       int
+	sourceQuotaUsed int
+	quota           *QuotaReservation
+}
+
+// migrationStage is one step of the migration pipeline. A stage that fails
+// aborts the migration.
+type migrationStage func(ctx context.Context, run *migrationRun) error
+
+// pipeline returns the stages of run in the order they run: resolving
+// conflicts with existing destination playlists, fetching the source
+// tracks, enriching them with already known matches, matching the rest on
+// the destination, retrying the tracks not found by their audio fingerprint
+// and writing the destination playlists. If the source tracks can be
+// streamed, one stage fetches, enriches and matches them.
+func (s *Service) pipeline(run *migrationRun) []migrationStage {
+	if s.canStream(run) {
+		return []migrationStage{s.conflictStage, s.streamStage, s.fingerprintStage, s.writeStage}
+	}
+	return []migrationStage{s.conflictStage, s.fetchStage, s.enrichStage, s.matchStage, s.fingerprintStage, s.writeStage}
+}
+
+// runPipeline passes run through stages in order, stopping at the first
+// stage that fails.
+func (s *Service) runPipeline(ctx context.Context, run *migrationRun, stages []migrationStage) error {
+	for _, stage := range stages {
+		if err := stage(ctx, run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warn records a warning on the migration result and logs it.
+func (run *migrationRun) warn(warning string) {
+	log.Printf("[migration] %s", warning)
+	run.warnings = append(run.warnings, warning)
+}
+
+// chargeQuota adds the cost of n op calls on the destination to the run and
+// to the daily quota usage.
+func (s *Service) chargeQuota(run *migrationRun, op domain.QuotaOperation, n int) {
+	cost := quotaCost(run.dest, op, n)
+	run.quotaUsed += cost
+	s.spendQuota(run.quota, run.req.DestProvider, cost)
+}
+
+// chargeSourceQuota adds the cost of n reads of the source to the run and
+// to the daily quota usage.
+func (s *Service) chargeSourceQuota(run *migrationRun, n int) {
+	cost := quotaCost(run.source, domain.QuotaOpFetch, n)
+	run.sourceQuotaUsed += cost
+	s.recordQuota(run.req.SourceProvider, cost)
+}
+
+// releaseQuota returns the destination units reserved for run but not
+// spent.
+func (run *migrationRun) releaseQuota() {
+	if run.quota != nil {
+		run.quota.Release()
+	}
+}
+
+// matchedIndices returns the indices of the results that have a match to
+// add to the destination.
+func (run *migrationRun) matchedIndices() []int {
+	var indices []int
+	for i := range run.results {
+		if isPlaced(run.results[i]) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// conflictStage names the destination playlist after the request's name
+// pattern and, if the request sets a
+// conflict policy, looks for a destination playlist the migration would
+// duplicate: the one an earlier migration of the same source playlist
+// created, if it still exists, or else, if the name pattern names the
+// source playlist, an owned playlist with the same name. Other patterns
+// such as the default one give every playlist of a provider the same name,
+// so the name tells nothing about its source. If there is one, the policy
+// decides whether the migration fails, reuses it or picks a free name;
+// suffix picks a free name whenever the name is taken.
+func (s *Service) conflictStage(ctx context.Context, run *migrationRun) error {
+	req := run.req
+	name, err := s.playlistName(ctx, run)
+	if err != nil {
+		return err
+	}
+	run.name = name
+	if req.ConflictPolicy == domain.ConflictIgnore {
+		return nil
+	}
+
+	var playlists []domain.Playlist
+	err = s.runStage(ctx, domain.StageFetch, func(ctx context.Context) error {
+		var err error
+		playlists, err = run.dest.GetPlaylists(ctx, req.DestToken)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list destination playlists: %w", err)
+	}
+
+	taken := make(map[string]bool, len(playlists))
+	for _, p := range playlists {
+		if p.IsOwner {
+			taken[p.Name] = true
+		}
+	}
+	title, _ := sanitizeText(run.dest, run.name, "")
+	existing := s.previousPlaylist(ctx, req, playlists)
+	if existing == nil && taken[title] && namesSourcePlaylist(req) {
+		for i := range playlists {
+			if playlists[i].IsOwner && playlists[i].Name == title {
+				existing = &playlists[i]
+				break
+			}
+		}
+	}
+	if existing == nil && (req.ConflictPolicy != domain.ConflictSuffix || !taken[title]) {
+		return nil
+	}
+
+	switch req.ConflictPolicy {
+	case domain.ConflictSkip:
+		return fmt.Errorf("%w: %s playlist %q (%s)", domain.ErrPlaylistExists, req.DestProvider, existing.Name, existing.ID)
+	case domain.ConflictReuse:
+		log.Printf("[migration] reusing %s playlist %s", req.DestProvider, existing.ID)
+		run.reuse = existing
+	case domain.ConflictSuffix:
+		for n := 2; taken[title]; n++ {
+			run.name = fmt.Sprintf("%s (%d)", name, n)
+			title, _ = sanitizeText(run.dest, run.name, "")
+		}
+	}
+	return nil
+}
+
+// previousPlaylist returns the destination playlist the latest earlier
+// migration of the same source playlist created, if it is still among
+// playlists. Of a split migration, the last part is returned.
+func (s *Service) previousPlaylist(ctx context.Context, req domain.MigrationRequest, playlists []domain.Playlist) *domain.Playlist {
+	previous, err := s.store.List(ctx, domain.AccountIDFromContext(ctx))
+	if err != nil {
+		log.Printf("[migration] failed to list earlier migrations: %v", err)
+		return nil
+	}
+
+	var latest *domain.MigrationResult
+	for i := range previous {
+		m := &previous[i]
+		if m.SourceProvider != req.SourceProvider || m.SourcePlaylist != req.PlaylistID ||
+			m.DestProvider != req.DestProvider || m.DryRun || m.RolledBack || m.DestPlaylistID == "" {
+			continue
+		}
+		if latest == nil || m.CreatedAt.After(latest.CreatedAt) {
+			latest = m
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	ids := destPlaylists(latest)
+	for i := range playlists {
+		if playlists[i].ID == ids[len(ids)-1] {
+			return &playlists[i]
+		}
+	}
+	return nil
+}
+
+// fetchStage loads the tracks of the source playlist and, if the request
+// asks for it, drops repeated tracks and marks the tracks its filter
+// excludes. Filters by genre first look up the genres of the tracks if the
+// source is a ports.GenreTagger.
+func (s *Service) fetchStage(ctx context.Context, run *migrationRun) error {
+	log.Printf("[migration] fetching tracks from %s playlist %s", run.req.SourceProvider, run.req.PlaylistID)
+	stageStart := time.Now()
+	err := s.runStage(ctx, domain.StageFetch, func(ctx context.Context) error {
+		var err error
+		run.tracks, err = run.source.GetPlaylistTracks(ctx, run.req.SourceToken, run.req.PlaylistID)
+		return err
+	})
+	run.timing.FetchMS = msSince(stageStart)
+	if err != nil {
+		return fmt.Errorf("failed to fetch source tracks: %w", err)
+	}
+	s.chargeSourceQuota(run, fetchCalls(run.source, len(run.tracks)))
+
+	if len(run.tracks) == 0 {
+		return fmt.Errorf("source playlist is empty")
+	}
+	if run.req.Dedupe {
+		var removed int
+		if run.tracks, removed = dedupeTracks(run.tracks); removed > 0 {
+			run.warn(fmt.Sprintf("skipped %d duplicate tracks of the source playlist", removed))
+		}
+	}
+	if tagger, ok := run.source.(ports.GenreTagger); ok && run.filter.needsGenres() {
+		tagStart := time.Now()
+		err := s.runStage(ctx, domain.StageFetch, func(ctx context.Context) error {
+			return tagger.TagGenres(ctx, run.req.SourceToken, run.tracks)
+		})
+		run.timing.FetchMS += msSince(tagStart)
+		if err != nil {
+			return fmt.Errorf("failed to fetch genres of source tracks: %w", err)
+		}
+	}
+	var excluded int
+	if run.excluded, excluded = filterTracks(run.filter, run.tracks); excluded > 0 {
+		if excluded == len(run.tracks) {
+			return fmt.Errorf("%w: it excludes all %d tracks of the source playlist", domain.ErrInvalidFilter, excluded)
+		}
+		log.Printf("[migration] filter excludes %d tracks", excluded)
+	}
+	if err := checkTrackLimit(run.limits, len(run.tracks)-excluded); err != nil {
+		return err
+	}
+
+	log.Printf("[migration] found %d tracks, starting migration to %s", len(run.tracks), run.req.DestProvider)
+	return nil
+}
+
+// canStream reports whether run can match the source tracks while they are
+// still being fetched: the source must be a ports.TrackStreamer, and a
+// destination with a daily quota is checked against the cost of the whole
+// playlist before the first search, so migrations to it fetch first.
+func (s *Service) canStream(run *migrationRun) bool {
+	if _, ok := run.source.(ports.TrackStreamer); !ok {
+		return false
+	}
+	return s.quota == nil || quotaCost(run.dest, domain.QuotaOpSearch, 1) == 0
+}
+
+// streamStage does the work of fetchStage, enrichStage and matchStage at
+// once: each page of source tracks is deduplicated, filtered and enriched
+// as it arrives, and its pending tracks go straight to the search worker
+// pool, so matching starts while later pages are still being fetched.
+// Fetching and searching overlap, so their timings add up to more than the
+// stage took.
+func (s *Service) streamStage(ctx context.Context, run *migrationRun) error {
+	streamer := run.source.(ports.TrackStreamer)
+	tagger, _ := run.source.(ports.GenreTagger)
+	tagGenres := tagger != nil && run.filter.needsGenres()
+	log.Printf("[migration] streaming tracks from %s playlist %s", run.req.SourceProvider, run.req.PlaylistID)
+
+	searchCtx, cancelSearch := context.WithCancel(ctx)
+	defer cancelSearch()
+	feed := make(chan []domain.Track)
+	searchDone := make(chan struct{})
+	var searched []domain.TrackResult
+	stageStart := time.Now()
+	go func() {
+		defer close(searchDone)
+		searched, run.concurrency = s.searchTrackStream(searchCtx, run.dest, run.req.DestToken, feed)
+	}()
+
+	run.tracks, run.excluded, run.results, run.pending = nil, nil, nil, nil
+	seen := make(deduper)
+	var removed, excluded int
+	var pageErr error
+	err := s.runStage(ctx, domain.StageFetch, func(fetchCtx context.Context) error {
+		return streamer.StreamPlaylistTracks(fetchCtx, run.req.SourceToken, run.req.PlaylistID, func(page []domain.Track) error {
+			s.chargeSourceQuota(run, 1)
+			if run.req.Dedupe {
+				var n int
+				page, n = seen.dedupe(page)
+				removed += n
+			}
+			if tagGenres {
+				if err := tagger.TagGenres(fetchCtx, run.req.SourceToken, page); err != nil {
+					pageErr = fmt.Errorf("failed to fetch genres of source tracks: %w", err)
+					return pageErr
+				}
+			}
+			reasons, n := filterTracks(run.filter, page)
+			excluded += n
+			if reasons != nil && run.excluded == nil {
+				run.excluded = make([]string, len(run.tracks))
+			}
+			if run.excluded != nil {
+				if reasons == nil {
+					reasons = make([]string, len(page))
+				}
+				run.excluded = append(run.excluded, reasons...)
+			}
+			from := len(run.tracks)
+			run.tracks = append(run.tracks, page...)
+			if pageErr = checkTrackLimit(run.limits, len(run.tracks)-excluded); pageErr != nil {
+				return pageErr
+			}
+
+			pendingFrom := len(run.pending)
+			s.enrichTracks(ctx, run, from)
+			tracks := make([]domain.Track, 0, len(run.pending)-pendingFrom)
+			for _, idx := range run.pending[pendingFrom:] {
+				tracks = append(tracks, run.tracks[idx])
+			}
+			if len(tracks) == 0 {
+				return nil
+			}
+			select {
+			case feed <- tracks:
+				return nil
+			case <-fetchCtx.Done():
+				return fetchCtx.Err()
+			}
+		})
+	})
+	run.timing.FetchMS = msSince(stageStart)
+	if err != nil {
+		cancelSearch()
+	}
+	close(feed)
+	<-searchDone
+	run.timing.SearchMS = msSince(stageStart)
+
+	switch {
+	case pageErr != nil:
+		return pageErr
+	case err != nil:
+		return fmt.Errorf("failed to fetch source tracks: %w", err)
+	case len(run.tracks) == 0:
+		return fmt.Errorf("source playlist is empty")
+	case excluded == len(run.tracks):
+		return fmt.Errorf("%w: it excludes all %d tracks of the source playlist", domain.ErrInvalidFilter, excluded)
+	}
+	if removed > 0 {
+		run.warn(fmt.Sprintf("skipped %d duplicate tracks of the source playlist", removed))
+	}
+	if excluded > 0 {
+		log.Printf("[migration] filter excludes %d tracks", excluded)
+	}
+	log.Printf("[migration] streamed %d tracks to %s, reusing %d known matches",
+		len(run.tracks), run.req.DestProvider, len(run.tracks)-len(run.pending)-excluded)
+	s.recordSearches(ctx, run, searched)
+	return nil
+}
+
+// enrichStage fills in the results of tracks the filter excludes and of
+// tracks whose destination counterpart is already known, so they skip the
+// search, and leaves the others pending, with their missing metadata
+// filled in when metadata enrichment is enabled.
+// Known matches the matching strategy or the requested minimum score would
+// not accept are searched again.
+//
+// If the destination can look up tracks by ID, tracks of a migration within
+// one provider are looked up by their own ID, and known matches are checked
+// for playability when a market is requested, in a few batch requests
+// instead of one search per track.
+func (s *Service) enrichStage(ctx context.Context, run *migrationRun) error {
+	run.results = nil
+	run.pending = nil
+	s.enrichTracks(ctx, run, 0)
+	if reused := len(run.tracks) - len(run.pending); reused > 0 {
+		log.Printf("[migration] reusing %d known matches", reused)
+	}
+	return nil
+}
+
+// enrichTracks does the work of enrichStage for the tracks from index from
+// on, which have no results yet.
+func (s *Service) enrichTracks(ctx context.Context, run *migrationRun, from int) {
+	run.results = append(run.results, make([]domain.TrackResult, len(run.tracks)-from)...)
+	pendingFrom := len(run.pending)
+	strategy := run.req.MatchingStrategy
+	minScore := run.req.MatchOptions().Threshold()
+	lookup, _ := run.dest.(ports.TrackLookup)
+	sameProvider := run.req.SourceProvider == run.req.DestProvider
+
+	var lookupIndices []int
+	var lookupIDs []string
+	for i := from; i < len(run.tracks); i++ {
+		track := run.tracks[i]
+		if run.excluded != nil && run.excluded[i] != "" {
+			run.results[i] = domain.TrackResult{SourceTrack: track, Status: domain.TrackStatusFiltered, Error: run.excluded[i]}
+			continue
+		}
+		if match, ok := s.knownMatch(ctx, run.req.SourceProvider, run.req.DestProvider, track, run.opts.known); ok &&
+			match.score >= minScore && strategy.Confirms(track, match.track) {
+			matched := match.track
+			run.results[i] = domain.TrackResult{
+				SourceTrack:     track,
+				MatchedTrack:    &matched,
+				Status:          domain.TrackStatusMatched,
+				ConfidenceScore: match.score,
+			}
+			if lookup != nil && run.req.Market != "" && !track.IsEpisode() {
+				lookupIndices = append(lookupIndices, i)
+				lookupIDs = append(lookupIDs, matched.ExternalID)
+			}
+			continue
+		}
+		if lookup != nil && sameProvider && !track.IsEpisode() && track.ExternalID != "" {
+			lookupIndices = append(lookupIndices, i)
+			lookupIDs = append(lookupIDs, track.ExternalID)
+			continue
+		}
+		run.pending = append(run.pending, i)
+	}
+	if len(lookupIDs) > 0 {
+		s.lookupTracks(ctx, run, lookup, lookupIndices, lookupIDs)
+		slices.Sort(run.pending[pendingFrom:])
+	}
+	s.enrichMetadata(ctx, run, run.pending[pendingFrom:])
+}
+
+// lookupTracks resolves the tracks at indices by their destination ids in
+// one batch. Tracks the lookup does not confirm are left to the search; so
+// are all of them if the lookup fails.
+func (s *Service) lookupTracks(ctx context.Context, run *migrationRun, lookup ports.TrackLookup, indices []int, ids []string) {
+	var found []*domain.Track
+	err := s.runStage(ctx, domain.StageSearch, func(ctx context.Context) error {
+		var err error
+		found, err = lookup.LookupTracks(ctx, run.req.DestToken, ids)
+		return err
+	})
+	if err != nil {
+		log.Printf("[migration] %s track lookup failed, searching instead: %v", run.req.DestProvider, err)
+	}
+
+	resolved := 0
+	for j, i := range indices {
+		track := run.tracks[i]
+		if err != nil || j >= len(found) || found[j] == nil || !run.req.MatchingStrategy.Confirms(track, *found[j]) {
+			run.results[i] = domain.TrackResult{}
+			run.pending = append(run.pending, i)
+			continue
+		}
+		score := run.results[i].ConfidenceScore
+		if run.results[i].MatchedTrack == nil {
+			score = 1 // the source track itself
+		}
+		run.results[i] = domain.TrackResult{
+			SourceTrack:     track,
+			MatchedTrack:    found[j],
+			Status:          domain.TrackStatusMatched,
+			ConfidenceScore: score,
+		}
+		resolved++
+	}
+	log.Printf("[migration] looked up %d of %d tracks by ID", resolved, len(ids))
+}
+
+// matchStage searches the destination for the pending tracks. The
+// destination quota is reserved first: every pending track is searched and,
+// in the worst case, inserted.
+func (s *Service) matchStage(ctx context.Context, run *migrationRun) error {
+	estimate := quotaCost(run.dest, domain.QuotaOpSearch, len(run.pending))
+	if !run.req.DryRun {
+		playlists := len(playlistParts(run.dest, len(run.tracks)))
+		if run.req.ReviewThreshold > 0 {
+			playlists++
+		}
+		estimate += quotaCost(run.dest, domain.QuotaOpCreatePlaylist, playlists) +
+			quotaCost(run.dest, domain.QuotaOpAddTrack, len(run.tracks))
+	}
+	if s.quota != nil && estimate > 0 {
+		reservation, warning, err := s.quota.Reserve(run.req.DestProvider, estimate)
+		if err != nil {
+			return err
+		}
+		run.quota = reservation
+		if warning != "" {
+			log.Printf("[migration] quota warning: %s", warning)
+			run.warnings = append(run.warnings, warning)
+		}
+	}
+
+	tracks := make([]domain.Track, len(run.pending))
+	for i, idx := range run.pending {
+		tracks[i] = run.tracks[idx]
+	}
+
+	stageStart := time.Now()
+	searched, concurrency := s.searchTracksParallel(ctx, run.dest, run.req.DestToken, tracks)
+	run.timing.SearchMS = msSince(stageStart)
+	run.concurrency = concurrency
+	s.recordSearches(ctx, run, searched)
+	return nil
+}
+
+// recordSearches stores the results of searching the pending tracks, in the
+// order of run.pending, as adjusted by match feedback, and saves their
+// matches as mappings.
+func (s *Service) recordSearches(ctx context.Context, run *migrationRun, searched []domain.TrackResult) {
+	s.applyFeedback(ctx, run.req.SourceProvider, run.req.DestProvider, searched)
+	for i, tr := range searched {
+		run.results[run.pending[i]] = tr
+	}
+	run.pending = nil
+	s.saveMappings(ctx, run.req.SourceProvider, run.req.DestProvider, searched)
+	s.chargeQuota(run, domain.QuotaOpSearch, uncachedSearches(searched))
+
+	matched := len(run.matchedIndices())
+	log.Printf("[migration] search complete: %d matched, %d failed", matched, len(run.results)-matched)
+}
+
+// writeStage creates the destination playlist, or one per part if the
+// matched tracks do not fit into one, and adds the matched tracks. Matches
+// below the review threshold go into the review playlist instead. Tracks
+// that could not be added are reported as add_failed; the playlists are
+// kept so they can be retried. Dry runs write nothing.
+func (s *Service) writeStage(ctx context.Context, run *migrationRun) error {
+	req := run.req
+	indices, review := splitReview(run.results, run.matchedIndices(), req.ReviewThreshold)
+	if run.reuse != nil {
+		reused, err := s.writeReused(ctx, run, indices)
+		if err != nil {
+			return err
+		}
+		if reused {
+			s.writeReview(ctx, run, run.reuse.Name, review)
+			return nil
+		}
+	}
+	parts := playlistParts(run.dest, len(indices))
+	if len(parts) > 1 {
+		run.warn(fmt.Sprintf("%d matched tracks exceed the %s limit of %d per playlist; they are split into %d playlists",
+			len(indices), req.DestProvider, parts[0].end, len(parts)))
+	}
+	if req.DryRun {
+		log.Printf("[migration] dry run: skipping destination playlist creation")
+		return nil
+	}
+
+	name := run.name
+	if name == "" {
+		name = expandNamePattern(defaultNamePattern, req, "", time.Now())
+	}
+	description := fmt.Sprintf("Migrated %d/%d tracks", len(indices), len(run.tracks))
+	stageStart := time.Now()
+	for i := range parts {
+		title, text := sanitizeText(run.dest, partName(name, i, len(parts)), description)
+		var playlistID string
+		err := s.runStage(ctx, domain.StageCreate, func(ctx context.Context) error {
+			var err error
+			playlistID, err = s.createPlaylist(ctx, run.dest, req.DestToken, title, text)
+			return err
+		})
+		if err != nil {
+			s.deletePlaylists(ctx, run.dest, req.DestToken, run.destPlaylistIDs)
+			return fmt.Errorf("failed to create destination playlist: %w", err)
+		}
+		if i == 0 {
+			run.destName, run.destDescription = title, text
+		}
+		run.destPlaylistIDs = append(run.destPlaylistIDs, playlistID)
+		s.chargeQuota(run, domain.QuotaOpCreatePlaylist, 1)
+
+		log.Printf("[migration] created destination playlist: %s", playlistID)
+	}
+	run.timing.CreateMS = msSince(stageStart)
+
+	if req.CopySharing {
+		s.chargeSourceQuota(run, 1)
+		if warning := s.copySharing(ctx, run.source, run.dest, req, run.destPlaylistIDs); warning != "" {
+			run.warn(warning)
+		}
+	}
+
+	stageStart = time.Now()
+	for i, part := range parts {
+		if part.start == part.end {
+			continue
+		}
+		partIndices := indices[part.start:part.end]
+		partIDs := make([]string, len(partIndices))
+		for j, idx := range partIndices {
+			partIDs[j] = run.results[idx].MatchedTrack.ExternalID
+		}
+		var outcomes []domain.AddOutcome
+		err := s.runStage(ctx, domain.StageAdd, func(ctx context.Context) error {
+			var err error
+			outcomes, err = s.addTracks(ctx, run.dest, req.DestToken, run.destPlaylistIDs[i], partIDs)
+			return err
+		})
+		if err != nil {
+			run.warn(fmt.Sprintf("failed to add tracks to destination playlist: %v", err))
+		}
+		applyAddOutcomes(run.results, partIndices, outcomes, err)
+		s.chargeQuota(run, domain.QuotaOpAddTrack, len(partIDs))
+	}
+	if len(indices) > 0 {
+		run.timing.AddMS = msSince(stageStart)
+	}
+	s.writeReview(ctx, run, name, review)
+	return nil
+}
+
+// reviewSuffix is appended to the name of the destination playlist to name
+// its review playlist.
+const reviewSuffix = " (Review)"
+
+// splitReview returns the matched results at indices that score at least
+// threshold, and the others, which it marks for review. A threshold of 0
+// keeps every match.
+func splitReview(results []domain.TrackResult, indices []int, threshold float64) (keep, review []int) {
+	if threshold == 0 {
+		return indices, nil
+	}
+	for _, i := range indices {
+		if results[i].ConfidenceScore < threshold {
+			results[i].Review = true
+			review = append(review, i)
+			continue
+		}
+		keep = append(keep, i)
+	}
+	return keep, review
+}
+
+// writeReview creates the review playlist of the destination playlist named
+// name and adds the matches at indices to it. Failures do not fail the
+// migration, whose main playlist is written already: they are reported as
+// warnings and the tracks as add_failed, so they can be retried. Dry runs
+// and migrations without matches for review write nothing.
+func (s *Service) writeReview(ctx context.Context, run *migrationRun, name string, indices []int) {
+	if len(indices) == 0 {
+		return
+	}
+	log.Printf("[migration] %d matches scored below the review threshold of %.2f", len(indices), run.req.ReviewThreshold)
+	if run.req.DryRun {
+		return
+	}
+
+	ids := make([]string, len(indices))
+	for j, idx := range indices {
+		ids[j] = run.results[idx].MatchedTrack.ExternalID
+	}
+	playlistID, err := s.createReviewPlaylist(ctx, run.dest, run.req.DestToken, name, len(ids))
+	if err != nil {
+		run.warn(fmt.Sprintf("failed to create review playlist: %v", err))
+		applyAddOutcomes(run.results, indices, nil, err)
+		return
+	}
+	run.reviewPlaylistID = playlistID
+	s.chargeQuota(run, domain.QuotaOpCreatePlaylist, 1)
+
+	var outcomes []domain.AddOutcome
+	err = s.runStage(ctx, domain.StageAdd, func(ctx context.Context) error {
+		var err error
+		outcomes, err = s.addTracks(ctx, run.dest, run.req.DestToken, playlistID, ids)
+		return err
+	})
+	if err != nil {
+		run.warn(fmt.Sprintf("failed to add tracks to review playlist: %v", err))
+	}
+	applyAddOutcomes(run.results, indices, outcomes, err)
+	s.chargeQuota(run, domain.QuotaOpAddTrack, len(ids))
+}
+
+// createReviewPlaylist creates the review playlist of the destination
+// playlist named name, for n matches.
+func (s *Service) createReviewPlaylist(ctx context.Context, dest ports.MusicProvider, token string, name string, n int) (string, error) {
+	title, text := sanitizeText(dest, name+reviewSuffix, fmt.Sprintf("%d low-confidence matches of %s to review", n, name))
+	var playlistID string
+	err := s.runStage(ctx, domain.StageCreate, func(ctx context.Context) error {
+		var err error
+		playlistID, err = s.createPlaylist(ctx, dest, token, title, text)
+		return err
+	})
+	if err == nil {
+		log.Printf("[migration] created review playlist: %s", playlistID)
+	}
+	return playlistID, err
+}
+
+// writeReused adds the matched tracks at indices that the reused destination
+// playlist does not hold yet to it, and marks the others as existing. If
+// they would overflow the playlist, nothing is written and reused is false,
+// so new playlists are created instead.
+func (s *Service) writeReused(ctx context.Context, run *migrationRun, indices []int) (reused bool, err error) {
+	req := run.req
+	var held []domain.Track
+	err = s.runStage(ctx, domain.StageFetch, func(ctx context.Context) error {
+		var err error
+		held, err = run.dest.GetPlaylistTracks(ctx, req.DestToken, run.reuse.ID)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch reused destination playlist: %w", err)
+	}
+
+	existing := make(map[string]int, len(held))
+	for i, track := range held {
+		if _, ok := existing[track.ExternalID]; !ok {
+			existing[track.ExternalID] = i
+		}
+	}
+	var missing []int
+	for _, i := range indices {
+		if _, ok := existing[run.results[i].MatchedTrack.ExternalID]; !ok {
+			missing = append(missing, i)
+		}
+	}
+	if parts := playlistParts(run.dest, len(held)+len(missing)); len(parts) > 1 {
+		run.warn(fmt.Sprintf("%s playlist %s cannot hold %d more tracks; creating a new playlist instead",
+			req.DestProvider, run.reuse.ID, len(missing)))
+		run.reuse = nil
+		return false, nil
+	}
+
+	for _, i := range indices {
+		if _, ok := existing[run.results[i].MatchedTrack.ExternalID]; ok {
+			run.results[i].Existing = true
+		}
+	}
+	run.held, run.existing = len(held), existing
+	run.destPlaylistIDs = []string{run.reuse.ID}
+	run.destName, run.destDescription = run.reuse.Name, run.reuse.Description
+	log.Printf("[migration] %d of %d matched tracks are already in %s", len(indices)-len(missing), len(indices), run.reuse.ID)
+	if req.DryRun || len(missing) == 0 {
+		return true, nil
+	}
+
+	ids := make([]string, len(missing))
+	for j, idx := range missing {
+		ids[j] = run.results[idx].MatchedTrack.ExternalID
+	}
+	var outcomes []domain.AddOutcome
+	stageStart := time.Now()
+	err = s.runStage(ctx, domain.StageAdd, func(ctx context.Context) error {
+		var err error
+		outcomes, err = s.addTracks(ctx, run.dest, req.DestToken, run.reuse.ID, ids)
+		return err
+	})
+	run.timing.AddMS = msSince(stageStart)
+	if err != nil {
+		run.warn(fmt.Sprintf("failed to add tracks to destination playlist: %v", err))
+	}
+	applyAddOutcomes(run.results, missing, outcomes, err)
+	s.chargeQuota(run, domain.QuotaOpAddTrack, len(ids))
+	return true, nil
+}
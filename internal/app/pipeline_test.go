@@ -0,0 +1,435 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRun(source, dest *mockProvider, req domain.MigrationRequest) *migrationRun {
+	req.SourceProvider, req.DestProvider = source.name, dest.name
+	return &migrationRun{req: req, source: source, dest: dest, timing: &domain.MigrationTiming{}}
+}
+
+func TestConflictStage_Policies(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	source := &mockProvider{name: "source", playlists: []domain.Playlist{{ID: "pl-1", Name: "Road trip"}}}
+	dest := &mockProvider{name: "dest", playlists: []domain.Playlist{
+		{ID: "p1", Name: "Road trip", IsOwner: true},
+		{ID: "p2", Name: "Road trip (2)", IsOwner: true},
+	}}
+	request := func(policy domain.ConflictPolicy) domain.MigrationRequest {
+		return domain.MigrationRequest{PlaylistID: "pl-1", NamePattern: "{playlist}", ConflictPolicy: policy}
+	}
+
+	run := newRun(source, dest, request(domain.ConflictIgnore))
+	require.NoError(t, svc.conflictStage(context.Background(), run))
+	assert.Equal(t, "Road trip", run.name)
+
+	run = newRun(source, dest, request(domain.ConflictSkip))
+	err := svc.conflictStage(context.Background(), run)
+	assert.ErrorIs(t, err, domain.ErrPlaylistExists)
+	assert.ErrorContains(t, err, "p1")
+
+	run = newRun(source, dest, request(domain.ConflictSuffix))
+	require.NoError(t, svc.conflictStage(context.Background(), run))
+	assert.Equal(t, "Road trip (3)", run.name)
+
+	run = newRun(source, dest, request(domain.ConflictReuse))
+	require.NoError(t, svc.conflictStage(context.Background(), run))
+	require.NotNil(t, run.reuse)
+	assert.Equal(t, "p1", run.reuse.ID)
+
+	// Playlists the user does not own are no conflict.
+	dest.playlists = []domain.Playlist{{ID: "p1", Name: "Road trip"}}
+	run = newRun(source, dest, request(domain.ConflictSkip))
+	assert.NoError(t, svc.conflictStage(context.Background(), run))
+}
+
+func TestConflictStage_DefaultNameIsNoConflict(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	dest := &mockProvider{name: "dest", playlists: []domain.Playlist{{ID: "p1", Name: "Migrated from source", IsOwner: true}}}
+
+	// The default name is shared by every playlist migrated from source.
+	for _, policy := range []domain.ConflictPolicy{domain.ConflictSkip, domain.ConflictReuse} {
+		run := newRun(&mockProvider{name: "source"}, dest, domain.MigrationRequest{PlaylistID: "pl-1", ConflictPolicy: policy})
+		require.NoError(t, svc.conflictStage(context.Background(), run))
+		assert.Nil(t, run.reuse)
+	}
+
+	run := newRun(&mockProvider{name: "source"}, dest, domain.MigrationRequest{PlaylistID: "pl-1", ConflictPolicy: domain.ConflictSuffix})
+	require.NoError(t, svc.conflictStage(context.Background(), run))
+	assert.Equal(t, "Migrated from source (2)", run.name)
+}
+
+func TestConflictStage_SuffixesNamePattern(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	dest := &mockProvider{name: "dest", playlists: []domain.Playlist{{ID: "p1", Name: "Weekly mix", IsOwner: true}}}
+
+	run := newRun(&mockProvider{name: "source"}, dest, domain.MigrationRequest{NamePattern: "Weekly mix", ConflictPolicy: domain.ConflictSuffix})
+	require.NoError(t, svc.conflictStage(context.Background(), run))
+	assert.Equal(t, "Weekly mix (2)", run.name)
+}
+
+func TestConflictStage_FindsEarlierMigration(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	require.NoError(t, svc.store.Save(context.Background(), &domain.MigrationResult{
+		ID: "m1", SourceProvider: "source", SourcePlaylist: "pl-1", DestProvider: "dest", DestPlaylistID: "renamed",
+	}))
+	dest := &mockProvider{name: "dest", playlists: []domain.Playlist{{ID: "renamed", Name: "Road trip", IsOwner: true}}}
+
+	run := newRun(&mockProvider{name: "source"}, dest, domain.MigrationRequest{PlaylistID: "pl-1", ConflictPolicy: domain.ConflictReuse})
+	require.NoError(t, svc.conflictStage(context.Background(), run))
+	require.NotNil(t, run.reuse)
+	assert.Equal(t, "renamed", run.reuse.ID)
+
+	// Once deleted on the destination, the earlier playlist is no conflict.
+	dest.playlists = nil
+	run = newRun(&mockProvider{name: "source"}, dest, domain.MigrationRequest{PlaylistID: "pl-1", ConflictPolicy: domain.ConflictReuse})
+	require.NoError(t, svc.conflictStage(context.Background(), run))
+	assert.Nil(t, run.reuse)
+}
+
+func TestFetchStage_EmptyPlaylist(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	run := newRun(&mockProvider{name: "source"}, &mockProvider{name: "dest"}, domain.MigrationRequest{})
+
+	err := svc.fetchStage(context.Background(), run)
+	assert.EqualError(t, err, "source playlist is empty")
+}
+
+func TestFetchStage_Dedupe(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	source := &mockProvider{name: "source", tracks: []domain.Track{{ExternalID: "s1"}, {ExternalID: "s2"}, {ExternalID: "s1"}}}
+	run := newRun(source, &mockProvider{name: "dest"}, domain.MigrationRequest{Dedupe: true})
+
+	require.NoError(t, svc.fetchStage(context.Background(), run))
+	assert.Len(t, run.tracks, 2)
+	assert.Equal(t, []string{"skipped 1 duplicate tracks of the source playlist"}, run.warnings)
+}
+
+func TestEnrichStage_SkipsKnownMatches(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	run := newRun(&mockProvider{name: "source"}, &mockProvider{name: "dest"}, domain.MigrationRequest{})
+	run.tracks = []domain.Track{{ExternalID: "s1", Name: "Known"}, {ExternalID: "s2", Name: "New"}}
+	run.opts.known = map[string]knownMatch{"s1": {track: domain.Track{ExternalID: "d1"}, score: 1}}
+
+	require.NoError(t, svc.enrichStage(context.Background(), run))
+	assert.Equal(t, []int{1}, run.pending)
+	assert.Equal(t, domain.TrackStatusMatched, run.results[0].Status)
+	assert.Equal(t, "d1", run.results[0].MatchedTrack.ExternalID)
+}
+
+// lookupProvider is a mockProvider that looks up the tracks in catalog by ID.
+type lookupProvider struct {
+	*mockProvider
+	catalog map[string]domain.Track
+	err     error
+	lookups [][]string
+}
+
+func (p *lookupProvider) LookupTracks(_ context.Context, _ string, ids []string) ([]*domain.Track, error) {
+	p.lookups = append(p.lookups, ids)
+	if p.err != nil {
+		return nil, p.err
+	}
+	found := make([]*domain.Track, len(ids))
+	for i, id := range ids {
+		if track, ok := p.catalog[id]; ok {
+			found[i] = &track
+		}
+	}
+	return found, nil
+}
+
+func TestEnrichStage_LooksUpSameProviderTracks(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	provider := &lookupProvider{
+		mockProvider: &mockProvider{name: "spotify"},
+		catalog:      map[string]domain.Track{"a": {ExternalID: "a", Name: "A"}},
+	}
+	run := &migrationRun{
+		req:    domain.MigrationRequest{SourceProvider: "spotify", DestProvider: "spotify"},
+		source: provider, dest: provider, timing: &domain.MigrationTiming{},
+		tracks: []domain.Track{
+			{ExternalID: "a", Name: "A"},
+			{ExternalID: "gone", Name: "Gone"},
+			{ExternalID: "spotify:episode:e", Name: "Episode", Type: domain.ItemTypeEpisode},
+		},
+	}
+
+	require.NoError(t, svc.enrichStage(context.Background(), run))
+	assert.Equal(t, [][]string{{"a", "gone"}}, provider.lookups)
+	assert.Equal(t, []int{1, 2}, run.pending)
+	assert.Equal(t, domain.TrackStatusMatched, run.results[0].Status)
+	assert.Equal(t, 1.0, run.results[0].ConfidenceScore)
+	assert.Equal(t, "a", run.results[0].MatchedTrack.ExternalID)
+	assert.Empty(t, run.results[1].Status)
+}
+
+func TestEnrichStage_ChecksKnownMatchesInMarket(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	dest := &lookupProvider{
+		mockProvider: &mockProvider{name: "dest"},
+		catalog:      map[string]domain.Track{"d1": {ExternalID: "d1"}},
+	}
+	run := &migrationRun{
+		req:    domain.MigrationRequest{SourceProvider: "source", DestProvider: "dest", Market: "BR"},
+		source: &mockProvider{name: "source"}, dest: dest, timing: &domain.MigrationTiming{},
+		tracks: []domain.Track{{ExternalID: "s1"}, {ExternalID: "s2"}},
+		opts: migrateOptions{known: map[string]knownMatch{
+			"s1": {track: domain.Track{ExternalID: "d1"}, score: 0.9},
+			"s2": {track: domain.Track{ExternalID: "region-locked"}, score: 0.9},
+		}},
+	}
+
+	require.NoError(t, svc.enrichStage(context.Background(), run))
+	assert.Equal(t, [][]string{{"d1", "region-locked"}}, dest.lookups)
+	assert.Equal(t, []int{1}, run.pending)
+	assert.Equal(t, 0.9, run.results[0].ConfidenceScore)
+
+	// Without a market, known matches are trusted as they are.
+	dest.lookups = nil
+	run.req.Market = ""
+	require.NoError(t, svc.enrichStage(context.Background(), run))
+	assert.Empty(t, dest.lookups)
+	assert.Empty(t, run.pending)
+}
+
+func TestEnrichStage_LookupFailureFallsBackToSearch(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	provider := &lookupProvider{mockProvider: &mockProvider{name: "spotify"}, err: errors.New("boom")}
+	run := &migrationRun{
+		req:    domain.MigrationRequest{SourceProvider: "spotify", DestProvider: "spotify"},
+		source: provider, dest: provider, timing: &domain.MigrationTiming{},
+		tracks: []domain.Track{{ExternalID: "a"}, {ExternalID: "b"}},
+	}
+
+	require.NoError(t, svc.enrichStage(context.Background(), run))
+	assert.Equal(t, []int{0, 1}, run.pending)
+}
+
+func TestMatchStage_SearchesPendingTracks(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 2)
+	dest := &mockProvider{name: "dest", searchResults: map[string]*searchResult{
+		"New|Artist": {track: &domain.Track{ExternalID: "d2"}, score: 0.9},
+	}}
+	run := newRun(&mockProvider{name: "source"}, dest, domain.MigrationRequest{})
+	run.tracks = []domain.Track{{Name: "Known", Artists: []string{"Artist"}}, {Name: "New", Artists: []string{"Artist"}}}
+	run.results = []domain.TrackResult{{Status: domain.TrackStatusMatched, MatchedTrack: &domain.Track{ExternalID: "d1"}}, {}}
+	run.pending = []int{1}
+
+	require.NoError(t, svc.matchStage(context.Background(), run))
+	assert.Equal(t, 1, dest.searchCallCount)
+	assert.Empty(t, run.pending)
+	assert.Equal(t, "d2", run.results[1].MatchedTrack.ExternalID)
+	assert.Equal(t, []int{0, 1}, run.matchedIndices())
+	assert.NotNil(t, run.concurrency)
+}
+
+// streamingProvider is a mockProvider that streams pages of tracks, waiting
+// for a signal on next before each page after the first.
+type streamingProvider struct {
+	*mockProvider
+	pages [][]domain.Track
+	next  <-chan struct{}
+}
+
+func (p *streamingProvider) StreamPlaylistTracks(ctx context.Context, _ string, _ string, page func([]domain.Track) error) error {
+	for i, tracks := range p.pages {
+		if i > 0 && p.next != nil {
+			select {
+			case <-p.next:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if err := page(tracks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// signalingProvider is a mockProvider that signals each search it answers.
+type signalingProvider struct {
+	*mockProvider
+	searched chan<- struct{}
+}
+
+func (p *signalingProvider) SearchTrack(ctx context.Context, token string, track domain.Track) (*domain.Track, float64, error) {
+	matched, score, err := p.mockProvider.SearchTrack(ctx, token, track)
+	p.searched <- struct{}{}
+	return matched, score, err
+}
+
+func TestStreamStage_MatchesWhileFetching(t *testing.T) {
+	searched := make(chan struct{}, 10)
+	source := &streamingProvider{
+		mockProvider: &mockProvider{name: "source"},
+		pages: [][]domain.Track{
+			{
+				{Name: "One", Artists: []string{"A"}, ExternalID: "s1"},
+				{Name: "One", Artists: []string{"A"}, ExternalID: "s1"},
+				{Name: "Skipped", Artists: []string{"Nobody"}, ExternalID: "s2"},
+			},
+			{
+				{Name: "Known", Artists: []string{"A"}, ExternalID: "s3"},
+				{Name: "Two", Artists: []string{"A"}, ExternalID: "s4"},
+			},
+		},
+		// The second page is only fetched once a track of the first one
+		// was searched.
+		next: searched,
+	}
+	dest := &signalingProvider{
+		mockProvider: &mockProvider{name: "dest", searchResults: map[string]*searchResult{
+			"One|A": {track: &domain.Track{ExternalID: "d1"}, score: 0.9},
+			"Two|A": {track: &domain.Track{ExternalID: "d4"}, score: 0.8},
+		}},
+		searched: searched,
+	}
+	filter, err := compileFilter(&domain.TrackFilter{Artists: []string{"Nobody"}}, nil)
+	require.NoError(t, err)
+
+	var totals []int
+	svc := newService(adapters.NewProviderRegistry(), 2, WithProgress(func(_, total int) { totals = append(totals, total) }))
+	run := &migrationRun{
+		req:    domain.MigrationRequest{SourceProvider: "source", DestProvider: "dest", Dedupe: true},
+		opts:   migrateOptions{known: map[string]knownMatch{"s3": {track: domain.Track{ExternalID: "d3"}, score: 1}}},
+		source: source,
+		dest:   dest,
+		filter: filter,
+		timing: &domain.MigrationTiming{},
+	}
+	require.True(t, svc.canStream(run))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, svc.streamStage(ctx, run))
+
+	require.Len(t, run.results, 4)
+	assert.Equal(t, "d1", run.results[0].MatchedTrack.ExternalID)
+	assert.Equal(t, domain.TrackStatusFiltered, run.results[1].Status)
+	assert.Equal(t, "d3", run.results[2].MatchedTrack.ExternalID)
+	assert.Equal(t, "d4", run.results[3].MatchedTrack.ExternalID)
+	assert.Equal(t, 2, dest.searchCallCount)
+	assert.Empty(t, run.pending)
+	assert.Equal(t, []string{"skipped 1 duplicate tracks of the source playlist"}, run.warnings)
+	assert.NotNil(t, run.concurrency)
+	assert.Equal(t, 2, totals[len(totals)-1])
+}
+
+func TestStreamStage_Failures(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 2)
+	dest := &mockProvider{name: "dest"}
+	pages := [][]domain.Track{{{Name: "One", ExternalID: "s1"}}, {{Name: "Two", ExternalID: "s2"}}}
+	newStreamRun := func(pages [][]domain.Track) *migrationRun {
+		source := &streamingProvider{mockProvider: &mockProvider{name: "source"}, pages: pages}
+		return &migrationRun{req: domain.MigrationRequest{SourceProvider: "source", DestProvider: "dest"}, source: source, dest: dest, timing: &domain.MigrationTiming{}}
+	}
+
+	run := newStreamRun(nil)
+	assert.EqualError(t, svc.streamStage(context.Background(), run), "source playlist is empty")
+
+	run = newStreamRun(pages)
+	run.limits = &domain.AccountLimits{MaxTracksPerMigration: 1}
+	var limitErr *domain.LimitError
+	assert.ErrorAs(t, svc.streamStage(context.Background(), run), &limitErr)
+
+	run = newStreamRun(pages)
+	run.filter, _ = compileFilter(&domain.TrackFilter{TitlePatterns: []string{"."}}, nil)
+	assert.ErrorIs(t, svc.streamStage(context.Background(), run), domain.ErrInvalidFilter)
+}
+
+func TestCanStream_QuotaDestinationFetchesFirst(t *testing.T) {
+	source := &streamingProvider{mockProvider: &mockProvider{name: "source"}}
+	run := &migrationRun{source: source, dest: &quotaProvider{&mockProvider{name: "youtube"}}}
+
+	assert.True(t, newService(adapters.NewProviderRegistry(), 1).canStream(run))
+	svc := newService(adapters.NewProviderRegistry(), 1, WithQuotaTracker(NewQuotaTracker(map[string]int{"youtube": 10000}, false)))
+	assert.False(t, svc.canStream(run))
+	assert.Len(t, svc.pipeline(run), 6)
+
+	run.dest = &mockProvider{name: "dest"}
+	assert.True(t, svc.canStream(run))
+	assert.Len(t, svc.pipeline(run), 4)
+	run.source = &mockProvider{name: "source"}
+	assert.False(t, svc.canStream(run))
+}
+
+func TestWriteStage_DryRunWritesNothing(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	dest := &mockProvider{name: "dest", createdID: "new-playlist"}
+	run := newRun(&mockProvider{name: "source"}, dest, domain.MigrationRequest{DryRun: true})
+	run.tracks = []domain.Track{{Name: "Song"}}
+	run.results = []domain.TrackResult{{Status: domain.TrackStatusMatched, MatchedTrack: &domain.Track{ExternalID: "d1"}}}
+
+	require.NoError(t, svc.writeStage(context.Background(), run))
+	assert.Empty(t, run.destPlaylistIDs)
+	assert.Empty(t, dest.addedTracks)
+}
+
+func TestWriteStage_AddsMatchedTracks(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	dest := &mockProvider{name: "dest", createdID: "new-playlist", rejectAdd: map[string]bool{"d2": true}}
+	run := newRun(&mockProvider{name: "source"}, dest, domain.MigrationRequest{})
+	run.tracks = []domain.Track{{Name: "One"}, {Name: "Two"}, {Name: "Three"}}
+	run.results = []domain.TrackResult{
+		{Status: domain.TrackStatusMatched, MatchedTrack: &domain.Track{ExternalID: "d1"}},
+		{Status: domain.TrackStatusMatched, MatchedTrack: &domain.Track{ExternalID: "d2"}},
+		{Status: domain.TrackStatusNotFound},
+	}
+
+	require.NoError(t, svc.writeStage(context.Background(), run))
+	assert.Equal(t, []string{"new-playlist"}, run.destPlaylistIDs)
+	assert.Equal(t, "Migrated 2/3 tracks", run.destDescription)
+	assert.Equal(t, []string{"d1"}, dest.addedTracks)
+	assert.Equal(t, domain.TrackStatusAddFailed, run.results[1].Status)
+}
+
+func TestWriteStage_ReusedPlaylist(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	dest := &mockProvider{name: "dest", createdID: "new-playlist", tracks: []domain.Track{{ExternalID: "other"}, {ExternalID: "d1"}}}
+	run := newRun(&mockProvider{name: "source"}, dest, domain.MigrationRequest{})
+	run.reuse = &domain.Playlist{ID: "p1", Name: "Road trip"}
+	run.tracks = []domain.Track{{Name: "One"}, {Name: "Two"}}
+	run.results = []domain.TrackResult{
+		{Status: domain.TrackStatusMatched, MatchedTrack: &domain.Track{ExternalID: "d1"}},
+		{Status: domain.TrackStatusMatched, MatchedTrack: &domain.Track{ExternalID: "d2"}},
+	}
+
+	require.NoError(t, svc.writeStage(context.Background(), run))
+	assert.Equal(t, []string{"p1"}, run.destPlaylistIDs)
+	assert.Equal(t, "Road trip", run.destName)
+	assert.Equal(t, []string{"d2"}, dest.addedTracks)
+	assert.True(t, run.results[0].Existing)
+	assert.False(t, run.results[1].Existing)
+
+	assignPositions(run.results)
+	placeAfterExisting(run.results, run.existing, run.held)
+	assert.Equal(t, 1, *run.results[0].DestPosition)
+	assert.Equal(t, 2, *run.results[1].DestPosition)
+}
+
+func TestRunPipeline_StopsAtFailingStage(t *testing.T) {
+	svc := newService(adapters.NewProviderRegistry(), 1)
+	run := newRun(&mockProvider{name: "source"}, &mockProvider{name: "dest"}, domain.MigrationRequest{})
+	run.tracks = []domain.Track{{Name: "Song"}}
+
+	var ran []string
+	stages := []migrationStage{
+		func(context.Context, *migrationRun) error { ran = append(ran, "first"); return nil },
+		func(context.Context, *migrationRun) error { ran = append(ran, "second"); return errors.New("boom") },
+		func(context.Context, *migrationRun) error { ran = append(ran, "third"); return nil },
+	}
+	err := svc.runPipeline(context.Background(), run, stages)
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, []string{"first", "second"}, ran)
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// Typical latencies of provider calls, used to project the duration of a
+// migration before it runs.
+const (
+	estimatedSearchLatency = 400 * time.Millisecond
+	estimatedWriteLatency  = 300 * time.Millisecond
+
+	// estimatedAddBatch is how many tracks a single add call is assumed to
+	// insert.
+	estimatedAddBatch = 100
+)
+
+// PreviewMigration fetches the source tracks of req and estimates what
+// migrating them would take. Tokens are resolved and checked as for a
+// migration, but nothing is searched, created or locked.
+func (s *Service) PreviewMigration(ctx context.Context, req domain.MigrationRequest) (*domain.MigrationPreview, error) {
+	started := time.Now()
+
+	source, err := s.registry.Get(req.SourceProvider)
+	if err != nil {
+		return nil, fmt.Errorf("source provider error: %w", err)
+	}
+
+	dest, err := s.registry.Get(req.DestProvider)
+	if err != nil {
+		return nil, fmt.Errorf("destination provider error: %w", err)
+	}
+	if _, ok := dest.(ports.SourceOnly); ok {
+		return nil, fmt.Errorf("destination provider error: %s: %w", req.DestProvider, domain.ErrSourceOnlyProvider)
+	}
+
+	if req.SourceToken, err = s.resolveToken(ctx, req.SourceProvider, req.SourceToken); err != nil {
+		return nil, err
+	}
+	if req.DestToken, err = s.resolveToken(ctx, req.DestProvider, req.DestToken); err != nil {
+		return nil, err
+	}
+	if err := checkToken(ctx, source, req.SourceToken, false); err != nil {
+		return nil, fmt.Errorf("source provider error: %w", err)
+	}
+	if err := checkToken(ctx, dest, req.DestToken, !req.DryRun); err != nil {
+		return nil, fmt.Errorf("destination provider error: %w", err)
+	}
+
+	var tracks []domain.Track
+	err = s.runStage(ctx, domain.StageFetch, func(ctx context.Context) error {
+		var err error
+		tracks, err = source.GetPlaylistTracks(ctx, req.SourceToken, req.PlaylistID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch source tracks: %w", err)
+	}
+	// The migration reads the source as often as the preview just did.
+	fetchUnits := quotaCost(source, domain.QuotaOpFetch, fetchCalls(source, len(tracks)))
+	s.recordQuota(req.SourceProvider, fetchUnits)
+
+	preview := &domain.MigrationPreview{
+		SourceProvider: req.SourceProvider,
+		DestProvider:   req.DestProvider,
+		PlaylistID:     req.PlaylistID,
+		TotalTracks:    len(tracks),
+	}
+	for _, track := range tracks {
+		if track.IsEpisode() {
+			preview.TotalEpisodes++
+		}
+		if track.ISRC != "" {
+			preview.TracksWithISRC++
+		}
+		if _, ok := s.knownMatch(ctx, req.SourceProvider, req.DestProvider, track, nil); ok {
+			preview.KnownMatches++
+		}
+	}
+	searches := len(tracks) - preview.KnownMatches
+
+	// Searches run in rounds of the destination's current concurrency
+	// limit; writes run one call after another.
+	limit := s.limiterFor(req.DestProvider).Limit()
+	estimate := time.Since(started) + time.Duration((searches+limit-1)/limit)*estimatedSearchLatency
+	units := quotaCost(dest, domain.QuotaOpSearch, searches)
+	if !req.DryRun && len(tracks) > 0 {
+		creates := len(playlistParts(dest, len(tracks)))
+		writes := creates + (len(tracks)+estimatedAddBatch-1)/estimatedAddBatch
+		estimate += time.Duration(writes) * estimatedWriteLatency
+		units += quotaCost(dest, domain.QuotaOpCreatePlaylist, creates) + quotaCost(dest, domain.QuotaOpAddTrack, len(tracks))
+	}
+	preview.EstimatedDurationMS = estimate.Milliseconds()
+
+	if fetchUnits > 0 {
+		preview.QuotaUnits = map[string]int{req.SourceProvider: fetchUnits}
+	}
+	if units > 0 {
+		if preview.QuotaUnits == nil {
+			preview.QuotaUnits = make(map[string]int)
+		}
+		preview.QuotaUnits[req.DestProvider] += units
+		if s.quota != nil {
+			// In enforce mode the migration would be rejected; a preview
+			// reports that as a warning too.
+			warning, err := s.quota.Check(req.DestProvider, units)
+			if err != nil {
+				warning = err.Error()
+			}
+			if warning != "" {
+				preview.Warnings = append(preview.Warnings, warning)
+			}
+		}
+	}
+	if parts := playlistParts(dest, len(tracks)); len(parts) > 1 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf(
+			"%d tracks exceed the %s limit of %d per playlist; they would be split into %d playlists",
+			len(tracks), req.DestProvider, parts[0].end, len(parts)))
+	}
+	if len(tracks) == 0 {
+		preview.Warnings = append(preview.Warnings, "source playlist is empty")
+	}
+
+	return preview, nil
+}
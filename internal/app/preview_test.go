@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewMigration(t *testing.T) {
+	source := &mockProvider{
+		name: "source",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}, ISRC: "USRC17607839", ExternalID: "sp-a"},
+			{Name: "Track B", Artists: []string{"Artist B"}, ExternalID: "sp-b"},
+			{Name: "Episode C", Type: domain.ItemTypeEpisode, ExternalID: "sp-c"},
+		},
+	}
+	dest := &quotaProvider{&mockProvider{name: "youtube"}}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	mappings := memory.NewTrackMappingStore()
+	require.NoError(t, mappings.Save(context.Background(), &domain.TrackMapping{
+		ProviderA: "source",
+		TrackA:    domain.Track{ExternalID: "sp-a"},
+		ProviderB: "youtube",
+		TrackB:    domain.Track{ExternalID: "vid-a"},
+		Score:     1,
+	}))
+	svc := newService(registry, 2,
+		WithTrackMappings(mappings),
+		WithQuotaTracker(NewQuotaTracker(map[string]int{"youtube": 300}, true)))
+
+	preview, err := svc.PreviewMigration(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "youtube",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, preview.TotalTracks)
+	assert.Equal(t, 1, preview.TotalEpisodes)
+	assert.Equal(t, 1, preview.TracksWithISRC)
+	assert.Equal(t, 1, preview.KnownMatches)
+	// 2 searches, 1 playlist and 3 inserts.
+	assert.Equal(t, map[string]int{"youtube": 2*100 + 50 + 3*50}, preview.QuotaUnits)
+	require.Len(t, preview.Warnings, 1)
+	assert.Contains(t, preview.Warnings[0], "estimated 400 quota units")
+	// One round of 2 searches and 2 writes.
+	assert.GreaterOrEqual(t, preview.EstimatedDurationMS, (estimatedSearchLatency + 2*estimatedWriteLatency).Milliseconds())
+
+	assert.Zero(t, dest.searchCallCount)
+	assert.Empty(t, dest.addedTracks)
+}
+
+func TestPreviewMigration_DryRunSkipsWrites(t *testing.T) {
+	source := &mockProvider{name: "source", tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}}}
+	dest := &quotaProvider{&mockProvider{name: "youtube"}}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1)
+	preview, err := svc.PreviewMigration(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "youtube",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		DryRun:         true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"youtube": 100}, preview.QuotaUnits)
+	assert.Less(t, preview.EstimatedDurationMS, (estimatedSearchLatency + estimatedWriteLatency).Milliseconds())
+	assert.Empty(t, preview.Warnings)
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// ProfileService implements ports.ProfileService. Migrations with a profile
+// are run by the migration service like any other request.
+type ProfileService struct {
+	store      ports.ProfileStore
+	migrations ports.MigrationService
+}
+
+// NewProfileService creates a profile service that keeps profiles in store
+// and runs their migrations with migrations.
+func NewProfileService(store ports.ProfileStore, migrations ports.MigrationService) *ProfileService {
+	return &ProfileService{store: store, migrations: migrations}
+}
+
+func (s *ProfileService) CreateProfile(ctx context.Context, profile domain.MigrationProfile) (*domain.MigrationProfile, error) {
+	if err := checkProfile(&profile); err != nil {
+		return nil, err
+	}
+	profile.ID = newID()
+	profile.AccountID = domain.AccountIDFromContext(ctx)
+	profile.CreatedAt = time.Now().UTC()
+	profile.UpdatedAt = profile.CreatedAt
+	if err := s.store.Save(ctx, &profile); err != nil {
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+	return &profile, nil
+}
+
+func (s *ProfileService) GetProfile(ctx context.Context, id string) (*domain.MigrationProfile, error) {
+	profile, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if profile.AccountID != domain.AccountIDFromContext(ctx) {
+		return nil, domain.ErrProfileNotFound
+	}
+	return profile, nil
+}
+
+func (s *ProfileService) ListProfiles(ctx context.Context) ([]domain.MigrationProfile, error) {
+	return s.store.List(ctx, domain.AccountIDFromContext(ctx))
+}
+
+// UpdateProfile replaces every setting of the profile with those of
+// profile; settings left out are reset to their defaults.
+func (s *ProfileService) UpdateProfile(ctx context.Context, id string, profile domain.MigrationProfile) (*domain.MigrationProfile, error) {
+	existing, err := s.GetProfile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkProfile(&profile); err != nil {
+		return nil, err
+	}
+	profile.ID, profile.AccountID, profile.CreatedAt = existing.ID, existing.AccountID, existing.CreatedAt
+	profile.UpdatedAt = time.Now().UTC()
+	if err := s.store.Save(ctx, &profile); err != nil {
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+	return &profile, nil
+}
+
+func (s *ProfileService) DeleteProfile(ctx context.Context, id string) error {
+	if _, err := s.GetProfile(ctx, id); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, id)
+}
+
+func (s *ProfileService) MigrateWithProfile(ctx context.Context, id string, req domain.ProfileMigrationRequest) (*domain.MigrationResult, error) {
+	profile, err := s.GetProfile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.migrations.MigratePlaylist(ctx, profile.Request(req))
+}
+
+// checkProfile trims the name of profile and rejects settings a migration
+// would reject.
+func checkProfile(profile *domain.MigrationProfile) error {
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		return fmt.Errorf("%w: name is required", domain.ErrInvalidProfile)
+	}
+	if !profile.MatchingStrategy.Valid() {
+		return fmt.Errorf("%w: unknown matching strategy %q", domain.ErrInvalidProfile, profile.MatchingStrategy)
+	}
+	if !profile.ConflictPolicy.Valid() {
+		return fmt.Errorf("%w: unknown conflict policy %q", domain.ErrInvalidProfile, profile.ConflictPolicy)
+	}
+	if profile.MinScore < 0 || profile.MinScore > 1 {
+		return fmt.Errorf("%w: min score %.2f is not between 0 and 1", domain.ErrInvalidProfile, profile.MinScore)
+	}
+	if profile.ReviewThreshold < 0 || profile.ReviewThreshold > 1 {
+		return fmt.Errorf("%w: review threshold %.2f is not between 0 and 1", domain.ErrInvalidProfile, profile.ReviewThreshold)
+	}
+	if !profile.Rerecordings.Valid() {
+		return fmt.Errorf("%w: unknown rerecording preference %q", domain.ErrInvalidProfile, profile.Rerecordings)
+	}
+	if _, err := compileFilter(profile.Exclude, profile.Genres); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidProfile, err)
+	}
+	profile.Market = strings.ToUpper(profile.Market)
+	return nil
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMigrations records the request of MigratePlaylist.
+type recordingMigrations struct {
+	ports.MigrationService
+	req domain.MigrationRequest
+}
+
+func (m *recordingMigrations) MigratePlaylist(_ context.Context, req domain.MigrationRequest) (*domain.MigrationResult, error) {
+	m.req = req
+	return &domain.MigrationResult{ID: "m1"}, nil
+}
+
+func TestProfileService_CRUD(t *testing.T) {
+	svc := NewProfileService(memory.NewProfileStore(), &recordingMigrations{})
+	alice := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "alice"})
+	bob := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "bob"})
+
+	created, err := svc.CreateProfile(alice, domain.MigrationProfile{
+		Name: " weekly ", SourceProvider: "spotify", DestProvider: "youtube", Market: "de",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "alice", created.AccountID)
+	assert.Equal(t, "weekly", created.Name)
+	assert.Equal(t, "DE", created.Market)
+
+	_, err = svc.GetProfile(bob, created.ID)
+	assert.ErrorIs(t, err, domain.ErrProfileNotFound, "profiles of other accounts are hidden")
+	list, err := svc.ListProfiles(bob)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	updated, err := svc.UpdateProfile(alice, created.ID, domain.MigrationProfile{
+		Name: "weekly", SourceProvider: "spotify", DestProvider: "tidal", Dedupe: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, updated.ID)
+	assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+	assert.Equal(t, "tidal", updated.DestProvider)
+	assert.Empty(t, updated.Market, "updates replace every setting")
+
+	_, err = svc.UpdateProfile(alice, created.ID, domain.MigrationProfile{Name: "  "})
+	assert.ErrorIs(t, err, domain.ErrInvalidProfile)
+	_, err = svc.CreateProfile(alice, domain.MigrationProfile{Name: "x", MinScore: 2})
+	assert.ErrorIs(t, err, domain.ErrInvalidProfile)
+	_, err = svc.CreateProfile(alice, domain.MigrationProfile{Name: "x", Rerecordings: "sometimes"})
+	assert.ErrorIs(t, err, domain.ErrInvalidProfile)
+	_, err = svc.CreateProfile(alice, domain.MigrationProfile{Name: "x", Exclude: &domain.TrackFilter{TitlePatterns: []string{"("}}})
+	assert.ErrorIs(t, err, domain.ErrInvalidProfile)
+
+	assert.ErrorIs(t, svc.DeleteProfile(bob, created.ID), domain.ErrProfileNotFound)
+	require.NoError(t, svc.DeleteProfile(alice, created.ID))
+	_, err = svc.GetProfile(alice, created.ID)
+	assert.ErrorIs(t, err, domain.ErrProfileNotFound)
+}
+
+func TestProfileService_MigrateWithProfile(t *testing.T) {
+	migrations := &recordingMigrations{}
+	svc := NewProfileService(memory.NewProfileStore(), migrations)
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "alice"})
+
+	profile, err := svc.CreateProfile(ctx, domain.MigrationProfile{
+		Name:             "strict",
+		SourceProvider:   "spotify",
+		DestProvider:     "youtube",
+		MatchingStrategy: domain.MatchingStrict,
+		MinScore:         0.8,
+		Dedupe:           true,
+		NamePattern:      "{playlist} (copy)",
+		ConflictPolicy:   domain.ConflictReuse,
+	})
+	require.NoError(t, err)
+
+	result, err := svc.MigrateWithProfile(ctx, profile.ID, domain.ProfileMigrationRequest{
+		PlaylistID: "pl-1", DestToken: "tok", DryRun: true, IdempotencyKey: "k1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "m1", result.ID)
+	assert.Equal(t, domain.MigrationRequest{
+		SourceProvider:   "spotify",
+		DestProvider:     "youtube",
+		DestToken:        "tok",
+		PlaylistID:       "pl-1",
+		DryRun:           true,
+		IdempotencyKey:   "k1",
+		MatchingStrategy: domain.MatchingStrict,
+		MinScore:         0.8,
+		Dedupe:           true,
+		NamePattern:      "{playlist} (copy)",
+		ConflictPolicy:   domain.ConflictReuse,
+	}, migrations.req)
+
+	_, err = svc.MigrateWithProfile(ctx, "missing", domain.ProfileMigrationRequest{PlaylistID: "pl-1"})
+	assert.ErrorIs(t, err, domain.ErrProfileNotFound)
+}
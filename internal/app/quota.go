@@ -0,0 +1,175 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// quotaResetLocation is where provider quotas roll over; the YouTube Data API
+// resets daily quotas at midnight Pacific Time.
+var quotaResetLocation = loadLocation("America/Los_Angeles")
+
+func loadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// QuotaTracker keeps a running total of quota units consumed per provider
+// for the current day and checks migrations against a daily budget. Units
+// reserved by running migrations count against the budget until they are
+// spent or released. It is safe for concurrent use.
+type QuotaTracker struct {
+	mu       sync.Mutex
+	budgets  map[string]int
+	enforce  bool
+	day      string
+	used     map[string]int
+	reserved map[string]int
+	now      func() time.Time
+}
+
+// NewQuotaTracker creates a tracker with daily unit budgets keyed by provider
+// name. When enforce is true, migrations that would exceed a budget are
+// rejected; otherwise they proceed with a warning.
+func NewQuotaTracker(budgets map[string]int, enforce bool) *QuotaTracker {
+	return &QuotaTracker{
+		budgets:  budgets,
+		enforce:  enforce,
+		used:     make(map[string]int),
+		reserved: make(map[string]int),
+		now:      time.Now,
+	}
+}
+
+// Check verifies that spending units on provider fits in today's budget. It
+// returns a warning when the budget would be exceeded in warn mode, or an
+// error wrapping domain.ErrQuotaExceeded in enforce mode.
+func (t *QuotaTracker) Check(provider string, units int) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.check(provider, units)
+}
+
+// Reserve checks like Check and, unless the budget is enforced and would be
+// exceeded, holds units for the caller in the same step, so concurrent
+// migrations cannot all pass the check on the same remaining budget. The
+// caller must release the reservation when done.
+func (t *QuotaTracker) Reserve(provider string, units int) (*QuotaReservation, string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	warning, err := t.check(provider, units)
+	if err != nil {
+		return nil, "", err
+	}
+	t.reserved[provider] += units
+	return &QuotaReservation{tracker: t, provider: provider, left: units}, warning, nil
+}
+
+// check implements Check. Callers must hold t.mu.
+func (t *QuotaTracker) check(provider string, units int) (string, error) {
+	t.rollover()
+
+	budget, ok := t.budgets[provider]
+	if !ok || budget <= 0 {
+		return "", nil
+	}
+
+	used := t.used[provider] + t.reserved[provider]
+	if used+units <= budget {
+		return "", nil
+	}
+
+	msg := fmt.Sprintf("%s: estimated %d quota units but only %d of %d remain today",
+		provider, units, max(budget-used, 0), budget)
+	if t.enforce {
+		return "", fmt.Errorf("%w: %s", domain.ErrQuotaExceeded, msg)
+	}
+	return msg, nil
+}
+
+// Record adds units to today's consumption for provider.
+func (t *QuotaTracker) Record(provider string, units int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover()
+	t.used[provider] += units
+}
+
+// QuotaReservation holds quota units of a provider for one migration.
+type QuotaReservation struct {
+	tracker  *QuotaTracker
+	provider string
+	left     int
+}
+
+// Spend records units as consumed, drawing on the reservation first.
+func (r *QuotaReservation) Spend(units int) {
+	t := r.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover()
+	held := min(units, r.left)
+	r.left -= held
+	t.reserved[r.provider] -= held
+	t.used[r.provider] += units
+}
+
+// Release returns the units that were not spent to the budget.
+func (r *QuotaReservation) Release() {
+	t := r.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reserved[r.provider] -= r.left
+	r.left = 0
+}
+
+// rollover resets usage when the quota day changes; reservations carry over.
+// Callers must hold t.mu.
+func (t *QuotaTracker) rollover() {
+	day := t.now().In(quotaResetLocation).Format("2006-01-02")
+	if day != t.day {
+		t.day = day
+		t.used = make(map[string]int)
+	}
+}
+
+// quotaCost returns the units provider charges for n calls of op, or 0 if
+// the provider has no unit-based quota.
+func quotaCost(provider ports.MusicProvider, op domain.QuotaOperation, n int) int {
+	coster, ok := provider.(ports.QuotaCoster)
+	if !ok {
+		return 0
+	}
+	return coster.QuotaCost(op) * n
+}
+
+// fetchCalls returns the number of requests provider takes to read a
+// playlist of n tracks: one per page for a ports.TrackPager, else one.
+func fetchCalls(provider ports.MusicProvider, n int) int {
+	pager, ok := provider.(ports.TrackPager)
+	if !ok || pager.TrackPageSize() <= 0 {
+		return 1
+	}
+	return max((n+pager.TrackPageSize()-1)/pager.TrackPageSize(), 1)
+}
+
+// uncachedSearches counts the results whose search reached the provider's
+// API rather than its search cache. Results that were never searched, such
+// as episodes the provider cannot search or tracks cancelled before their
+// search, made no request.
+func uncachedSearches(results []domain.TrackResult) int {
+	n := 0
+	for _, tr := range results {
+		if tr.Attempts > 0 && !tr.Cached {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,262 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quotaProvider is a mockProvider that charges YouTube-like quota costs.
+type quotaProvider struct {
+	*mockProvider
+}
+
+func (q *quotaProvider) QuotaCost(op domain.QuotaOperation) int {
+	if op == domain.QuotaOpSearch {
+		return 100
+	}
+	return 50
+}
+
+// pagedProvider is a mockProvider that returns tracks in pages of 50.
+type pagedProvider struct {
+	*mockProvider
+}
+
+func (p *pagedProvider) TrackPageSize() int { return 50 }
+
+func TestQuotaTracker_WarnAndEnforce(t *testing.T) {
+	warn := NewQuotaTracker(map[string]int{"youtube": 1000}, false)
+	warn.Record("youtube", 900)
+
+	msg, err := warn.Check("youtube", 200)
+	require.NoError(t, err)
+	assert.Contains(t, msg, "100 of 1000 remain")
+
+	enforce := NewQuotaTracker(map[string]int{"youtube": 1000}, true)
+	enforce.Record("youtube", 900)
+
+	_, err = enforce.Check("youtube", 200)
+	require.ErrorIs(t, err, domain.ErrQuotaExceeded)
+
+	// Providers without a budget are never limited.
+	msg, err = enforce.Check("spotify", 1_000_000)
+	require.NoError(t, err)
+	assert.Empty(t, msg)
+}
+
+func TestQuotaTracker_Reserve(t *testing.T) {
+	tracker := NewQuotaTracker(map[string]int{"youtube": 1000}, true)
+
+	first, _, err := tracker.Reserve("youtube", 600)
+	require.NoError(t, err)
+	_, _, err = tracker.Reserve("youtube", 600)
+	require.ErrorIs(t, err, domain.ErrQuotaExceeded, "reserved units count against the budget")
+
+	first.Spend(200)
+	first.Release()
+	_, err = tracker.Check("youtube", 800)
+	require.NoError(t, err)
+	_, err = tracker.Check("youtube", 801)
+	require.ErrorIs(t, err, domain.ErrQuotaExceeded, "spent units stay used after the release")
+
+	second, _, err := tracker.Reserve("youtube", 100)
+	require.NoError(t, err)
+	second.Spend(300)
+	second.Release()
+	_, err = tracker.Check("youtube", 500)
+	require.NoError(t, err)
+	_, err = tracker.Check("youtube", 501)
+	require.ErrorIs(t, err, domain.ErrQuotaExceeded, "spending past a reservation is recorded in full")
+}
+
+func TestFetchCalls(t *testing.T) {
+	assert.Equal(t, 1, fetchCalls(&mockProvider{}, 500))
+	paged := &pagedProvider{&mockProvider{}}
+	assert.Equal(t, 1, fetchCalls(paged, 0))
+	assert.Equal(t, 1, fetchCalls(paged, 50))
+	assert.Equal(t, 3, fetchCalls(paged, 101))
+}
+
+func TestQuotaTracker_ResetsDaily(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, quotaResetLocation)
+	tracker := NewQuotaTracker(map[string]int{"youtube": 100}, true)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Record("youtube", 100)
+	_, err := tracker.Check("youtube", 1)
+	require.Error(t, err)
+
+	now = now.Add(24 * time.Hour)
+	_, err = tracker.Check("youtube", 100)
+	require.NoError(t, err)
+}
+
+func TestMigratePlaylist_ReportsQuotaUsage(t *testing.T) {
+	source := &mockProvider{
+		name: "source",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}},
+			{Name: "Track B", Artists: []string{"Artist B"}},
+		},
+	}
+	dest := &quotaProvider{&mockProvider{
+		name:      "youtube",
+		createdID: "yt-pl",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {
+				track: &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+				score: 0.9,
+			},
+		},
+	}}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	tracker := NewQuotaTracker(map[string]int{"youtube": 10000}, true)
+	svc := newService(registry, 1, WithQuotaTracker(tracker))
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "youtube",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+
+	require.NoError(t, err)
+	// 2 searches + 1 playlist insert + 1 item insert
+	assert.Equal(t, 300, result.QuotaUnitsUsed["youtube"])
+
+	// The reservation for the unmatched track's insert was released.
+	_, err = tracker.Check("youtube", 9700)
+	require.NoError(t, err)
+	_, err = tracker.Check("youtube", 9701)
+	require.ErrorIs(t, err, domain.ErrQuotaExceeded)
+}
+
+func TestMigratePlaylist_ChargesSourceFetches(t *testing.T) {
+	source := &quotaProvider{&mockProvider{
+		name:   "youtube",
+		tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+	}}
+	dest := &mockProvider{name: "dest"}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1, WithQuotaTracker(NewQuotaTracker(map[string]int{"youtube": 10000}, true)))
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "youtube",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		DryRun:         true,
+	})
+
+	require.NoError(t, err)
+	// One read of the source tracks.
+	assert.Equal(t, map[string]int{"youtube": 50}, result.QuotaUnitsUsed)
+}
+
+func TestMigratePlaylist_CachedSearchesCostNoQuota(t *testing.T) {
+	source := &mockProvider{
+		name: "source",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}},
+			{Name: "Track B", Artists: []string{"Artist B"}},
+		},
+	}
+	dest := &quotaProvider{&mockProvider{
+		name: "youtube",
+		searchResults: map[string]*searchResult{
+			"Track A|Artist A": {
+				track:  &domain.Track{Name: "Track A", Artists: []string{"Artist A"}, ExternalID: "vid-a"},
+				score:  0.9,
+				cached: true,
+			},
+		},
+	}}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1, WithQuotaTracker(NewQuotaTracker(map[string]int{"youtube": 10000}, true)))
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "youtube",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		DryRun:         true,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.TrackResults[0].Cached)
+	assert.False(t, result.TrackResults[1].Cached)
+	// Only the search of Track B reached the API.
+	assert.Equal(t, 100, result.QuotaUnitsUsed["youtube"])
+}
+
+func TestMigratePlaylist_UnsearchedTracksCostNoQuota(t *testing.T) {
+	source := &mockProvider{
+		name: "source",
+		tracks: []domain.Track{
+			{Name: "Track A", Artists: []string{"Artist A"}},
+			{Name: "Episode B", Artists: []string{"Show B"}, Type: domain.ItemTypeEpisode},
+		},
+	}
+	dest := &quotaProvider{&mockProvider{name: "youtube"}}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1, WithQuotaTracker(NewQuotaTracker(map[string]int{"youtube": 10000}, true)))
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "youtube",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+		DryRun:         true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.TrackStatusUnsupported, result.TrackResults[1].Status)
+	// The episode the destination cannot search made no request.
+	assert.Equal(t, 100, result.QuotaUnitsUsed["youtube"])
+}
+
+func TestMigratePlaylist_RejectsOverBudget(t *testing.T) {
+	source := &mockProvider{
+		name:   "source",
+		tracks: []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+	}
+	dest := &quotaProvider{&mockProvider{name: "youtube"}}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+
+	svc := newService(registry, 1, WithQuotaTracker(NewQuotaTracker(map[string]int{"youtube": 100}, true)))
+	_, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "youtube",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+
+	require.ErrorIs(t, err, domain.ErrQuotaExceeded)
+	assert.Equal(t, 0, dest.searchCallCount)
+}
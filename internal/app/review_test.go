@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigratePlaylist_ReviewPlaylist(t *testing.T) {
+	svc, dest := newCappedMigration(5, 100)
+	dest.searchResults["Track 1|Artist"].score = 0.5
+	dest.searchResults["Track 3|Artist"].score = 0.6
+	delete(dest.searchResults, "Track 4|Artist")
+
+	req := domain.MigrationRequest{
+		SourceProvider:  "source",
+		SourceToken:     "t1",
+		DestProvider:    "dest",
+		DestToken:       "t2",
+		PlaylistID:      "pl-1",
+		ReviewThreshold: 0.8,
+	}
+	result, err := svc.MigratePlaylist(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Migrated from source", "Migrated from source (Review)"}, dest.names)
+	assert.Equal(t, "part-1", result.DestPlaylistID)
+	assert.Equal(t, "part-2", result.ReviewPlaylistID)
+	assert.Equal(t, map[string][]string{"part-1": {"t0", "t2"}, "part-2": {"t1", "t3"}}, dest.added)
+	assert.Equal(t, 4, result.MatchedTracks)
+	assert.Equal(t, 2, result.ReviewTracks)
+	assert.Equal(t, 0.8, result.ReviewThreshold)
+
+	positions := make([]int, 4)
+	for i := range positions {
+		require.NotNil(t, result.TrackResults[i].DestPosition)
+		positions[i] = *result.TrackResults[i].DestPosition
+	}
+	assert.Equal(t, []int{0, 0, 1, 1}, positions, "review matches are numbered in the review playlist")
+	assert.True(t, result.TrackResults[1].Review)
+	assert.False(t, result.TrackResults[2].Review)
+
+	// A late match below the threshold joins the review playlist.
+	dest.searchResults["Track 4|Artist"] = &searchResult{track: &domain.Track{ExternalID: "t4"}, score: 0.4}
+	retried, err := svc.RetryFailedTracks(context.Background(), result.ID, "t2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t3", "t4"}, dest.added["part-2"])
+	assert.Equal(t, 5, retried.MatchedTracks)
+	assert.Equal(t, 3, retried.ReviewTracks)
+	assert.Equal(t, 2, *retried.TrackResults[4].DestPosition)
+
+	_, err = svc.RollbackMigration(context.Background(), result.ID, "t2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"part-1", "part-2"}, dest.deletedIDs)
+}
+
+func TestMigratePlaylist_ReviewPlaylistDryRun(t *testing.T) {
+	svc, dest := newCappedMigration(2, 100)
+	dest.searchResults["Track 1|Artist"].score = 0.5
+
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider:  "source",
+		SourceToken:     "t1",
+		DestProvider:    "dest",
+		DestToken:       "t2",
+		PlaylistID:      "pl-1",
+		DryRun:          true,
+		ReviewThreshold: 0.8,
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, dest.names)
+	assert.Empty(t, result.ReviewPlaylistID)
+	assert.Equal(t, 1, result.ReviewTracks, "dry runs report the matches that would go to review")
+	assert.True(t, result.TrackResults[1].Review)
+}
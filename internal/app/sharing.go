@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// checkCollaborative rejects making a playlist collaborative on a provider
+// without collaborative playlists, which would silently ignore it.
+func checkCollaborative(provider ports.MusicProvider, update domain.PlaylistUpdate) error {
+	if update.Collaborative == nil || !*update.Collaborative {
+		return nil
+	}
+	if _, ok := provider.(ports.CollaborativePlaylists); !ok {
+		return fmt.Errorf("%s: %w", provider.Name(), domain.ErrCollaborativeUnsupported)
+	}
+	return nil
+}
+
+// copySharing makes the newly created, private destination playlists public
+// or collaborative if the source playlist is. It returns a warning if the
+// setting could not be copied; the tracks are migrated either way.
+func (s *Service) copySharing(ctx context.Context, source, dest ports.MusicProvider, req domain.MigrationRequest, destPlaylistIDs []string) string {
+	var playlist *domain.Playlist
+	err := s.runStage(ctx, domain.StageFetch, func(ctx context.Context) error {
+		var err error
+		playlist, err = source.GetPlaylist(ctx, req.SourceToken, req.PlaylistID)
+		return err
+	})
+	if err != nil {
+		return fmt.Sprintf("failed to read sharing settings of the source playlist: %v", err)
+	}
+
+	var update domain.PlaylistUpdate
+	switch {
+	case playlist.IsCollaborative:
+		update.Collaborative = &playlist.IsCollaborative
+		if err := checkCollaborative(dest, update); err != nil {
+			return fmt.Sprintf("source playlist is collaborative but %v; the destination playlist is private", err)
+		}
+	case playlist.IsPublic:
+		update.Public = &playlist.IsPublic
+	default:
+		return ""
+	}
+
+	for _, id := range destPlaylistIDs {
+		err = s.runStage(ctx, domain.StageCreate, func(ctx context.Context) error {
+			return s.updatePlaylist(ctx, dest, req.DestToken, id, update)
+		})
+		if err != nil {
+			return fmt.Sprintf("failed to copy sharing settings to the destination playlist: %v", err)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updateRecorder is a mockProvider that records playlist updates.
+type updateRecorder struct {
+	*mockProvider
+	updates []domain.PlaylistUpdate
+}
+
+func (u *updateRecorder) UpdatePlaylistDetails(_ context.Context, _ string, _ string, update domain.PlaylistUpdate) error {
+	u.updates = append(u.updates, update)
+	return nil
+}
+
+// collaborativeProvider is an updateRecorder with collaborative playlists.
+type collaborativeProvider struct {
+	*updateRecorder
+}
+
+func (c *collaborativeProvider) CollaborativePlaylists() {}
+
+func migrateSharing(t *testing.T, source domain.Playlist, destCollaborative, copySharing bool) (*domain.MigrationResult, []domain.PlaylistUpdate) {
+	t.Helper()
+	src := &mockProvider{
+		name:      "source",
+		playlists: []domain.Playlist{source},
+		tracks:    []domain.Track{{Name: "Track A", Artists: []string{"Artist A"}}},
+	}
+	recorder := &updateRecorder{mockProvider: &mockProvider{name: "dest", createdID: "new-pl"}}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(src)
+	if destCollaborative {
+		registry.Register(&collaborativeProvider{recorder})
+	} else {
+		registry.Register(recorder)
+	}
+
+	result, err := newService(registry, 1).MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     source.ID,
+		CopySharing:    copySharing,
+	})
+	require.NoError(t, err)
+	return result, recorder.updates
+}
+
+func TestMigratePlaylist_CopySharing(t *testing.T) {
+	collaborative := true
+	public := true
+
+	t.Run("collaborative", func(t *testing.T) {
+		result, updates := migrateSharing(t, domain.Playlist{ID: "pl-1", IsCollaborative: true}, true, true)
+		assert.Equal(t, []domain.PlaylistUpdate{{Collaborative: &collaborative}}, updates)
+		assert.Empty(t, result.Warnings)
+		assert.True(t, result.CopySharing)
+	})
+
+	t.Run("collaborative unsupported", func(t *testing.T) {
+		result, updates := migrateSharing(t, domain.Playlist{ID: "pl-1", IsCollaborative: true}, false, true)
+		assert.Empty(t, updates)
+		require.Len(t, result.Warnings, 1)
+		assert.Contains(t, result.Warnings[0], "does not support collaborative playlists")
+	})
+
+	t.Run("public", func(t *testing.T) {
+		_, updates := migrateSharing(t, domain.Playlist{ID: "pl-1", IsPublic: true}, false, true)
+		assert.Equal(t, []domain.PlaylistUpdate{{Public: &public}}, updates)
+	})
+
+	t.Run("private", func(t *testing.T) {
+		_, updates := migrateSharing(t, domain.Playlist{ID: "pl-1"}, false, true)
+		assert.Empty(t, updates)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, updates := migrateSharing(t, domain.Playlist{ID: "pl-1", IsPublic: true}, false, false)
+		assert.Empty(t, updates)
+	})
+}
+
+func TestUpdatePlaylist_CollaborativeUnsupported(t *testing.T) {
+	registry := adapters.NewProviderRegistry()
+	registry.Register(&mockProvider{name: "youtube"})
+	registry.Register(&collaborativeProvider{&updateRecorder{mockProvider: &mockProvider{name: "spotify"}}})
+	svc := newService(registry, 1)
+
+	on, off := true, false
+	err := svc.UpdatePlaylist(context.Background(), "youtube", "token", "pl-1", domain.PlaylistUpdate{Collaborative: &on})
+	assert.ErrorIs(t, err, domain.ErrCollaborativeUnsupported)
+
+	assert.NoError(t, svc.UpdatePlaylist(context.Background(), "youtube", "token", "pl-1", domain.PlaylistUpdate{Collaborative: &off}))
+	assert.NoError(t, svc.UpdatePlaylist(context.Background(), "spotify", "token", "pl-1", domain.PlaylistUpdate{Collaborative: &on}))
+}
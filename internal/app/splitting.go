@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// playlistPart is the range [start, end) of matched tracks that go into one
+// destination playlist.
+type playlistPart struct {
+	start, end int
+}
+
+// playlistParts splits n matched tracks into the parts that fit into the
+// playlists of provider. There is always at least one part.
+func playlistParts(provider ports.MusicProvider, n int) []playlistPart {
+	limit := n
+	if capacity := playlistCapacity(provider); capacity > 0 {
+		limit = min(limit, capacity)
+	}
+	if limit == 0 {
+		return []playlistPart{{}}
+	}
+	var parts []playlistPart
+	for start := 0; start < n; start += limit {
+		parts = append(parts, playlistPart{start: start, end: min(start+limit, n)})
+	}
+	return parts
+}
+
+// appendedParts splits n tracks appended to playlists destination
+// playlists of provider, which hold held tracks in all, into the room left
+// in the last playlist and the parts of the new playlists the others need.
+// Earlier playlists are assumed to be full, as the parts were filled in
+// order. The first part is the one for the last playlist and may be empty.
+func appendedParts(provider ports.MusicProvider, playlists, held, n int) []playlistPart {
+	capacity := playlistCapacity(provider)
+	if capacity == 0 {
+		return []playlistPart{{start: 0, end: n}}
+	}
+	room := min(n, max(capacity*playlists-held, 0))
+	parts := []playlistPart{{start: 0, end: room}}
+	for start := room; start < n; start += capacity {
+		parts = append(parts, playlistPart{start: start, end: min(start+capacity, n)})
+	}
+	return parts
+}
+
+// playlistCapacity returns the most tracks a playlist of provider holds, or
+// 0 if it does not say.
+func playlistCapacity(provider ports.MusicProvider) int {
+	if capacity, ok := provider.(ports.PlaylistCapacity); ok {
+		return max(capacity.MaxPlaylistItems(), 0)
+	}
+	return 0
+}
+
+// partName numbers name as part i (0-based) of a playlist split into parts
+// playlists.
+func partName(name string, i, parts int) string {
+	if parts == 1 {
+		return name
+	}
+	return fmt.Sprintf("%s (%d/%d)", name, i+1, parts)
+}
+
+// partBaseName returns the name the parts of the destination playlist of
+// result are numbered from.
+func partBaseName(result *domain.MigrationResult) string {
+	name := result.DestPlaylistName
+	if name == "" {
+		name = result.DestPlaylistID
+	}
+	if parts := len(destPlaylists(result)); parts > 1 {
+		name = strings.TrimSuffix(name, fmt.Sprintf(" (1/%d)", parts))
+	}
+	return name
+}
+
+// openParts creates count more parts of the destination playlist of result,
+// records them in result and renumbers the existing parts to the new
+// total. Failing to rename a part is only a warning, since its tracks are
+// in place either way. It returns the quota units used and the error of
+// the first part that could not be created; the parts created before it
+// are kept.
+func (s *Service) openParts(ctx context.Context, dest ports.MusicProvider, token string, result *domain.MigrationResult, count int) (int, error) {
+	playlists := destPlaylists(result)
+	total := len(playlists) + count
+	base := partBaseName(result)
+	quotaUsed := 0
+
+	var created []string
+	var err error
+	for i := len(playlists); i < total; i++ {
+		title, text := sanitizeText(dest, partName(base, i, total), result.DestPlaylistDescription)
+		var playlistID string
+		err = s.runStage(ctx, domain.StageCreate, func(ctx context.Context) error {
+			var err error
+			playlistID, err = s.createPlaylist(ctx, dest, token, title, text)
+			return err
+		})
+		if err != nil {
+			break
+		}
+		created = append(created, playlistID)
+		quotaUsed += quotaCost(dest, domain.QuotaOpCreatePlaylist, 1)
+		log.Printf("[migration] created destination playlist part: %s", playlistID)
+	}
+	if len(created) == 0 {
+		return quotaUsed, err
+	}
+	total = len(playlists) + len(created)
+
+	for i, id := range playlists {
+		title, _ := sanitizeText(dest, partName(base, i, total), "")
+		if err := s.updatePlaylist(ctx, dest, token, id, domain.PlaylistUpdate{Name: &title}); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to rename destination playlist %s to %q: %v", id, title, err))
+			continue
+		}
+		if i == 0 {
+			result.DestPlaylistName = title
+		}
+	}
+	result.DestPlaylistIDs = append(slices.Clone(playlists), created...)
+	return quotaUsed, err
+}
+
+// destPlaylists returns every destination playlist of a migration.
+func destPlaylists(result *domain.MigrationResult) []string {
+	if len(result.DestPlaylistIDs) > 0 {
+		return result.DestPlaylistIDs
+	}
+	return []string{result.DestPlaylistID}
+}
+
+// deletePlaylists removes the parts of a split playlist created before one
+// of them failed, so a failed migration leaves nothing behind.
+func (s *Service) deletePlaylists(ctx context.Context, provider ports.MusicProvider, token string, playlistIDs []string) {
+	for _, id := range playlistIDs {
+		if err := s.deletePlaylist(context.WithoutCancel(ctx), provider, token, id); err != nil {
+			log.Printf("[migration] failed to delete %s playlist %s: %v", provider.Name(), id, err)
+		}
+	}
+}
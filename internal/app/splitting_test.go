@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cappedProvider is a mockProvider whose playlists hold at most capacity
+// tracks. It records the playlists created and the tracks added to each.
+type cappedProvider struct {
+	*mockProvider
+	capacity  int
+	failAfter int
+	names     []string
+	renamed   map[string]string
+	added     map[string][]string
+}
+
+func (p *cappedProvider) MaxPlaylistItems() int { return p.capacity }
+
+func (p *cappedProvider) CreatePlaylist(_ context.Context, _ string, name string, _ string) (string, error) {
+	if p.failAfter > 0 && len(p.names) == p.failAfter {
+		return "", errors.New("create failed")
+	}
+	p.names = append(p.names, name)
+	return fmt.Sprintf("part-%d", len(p.names)), nil
+}
+
+func (p *cappedProvider) UpdatePlaylistDetails(_ context.Context, _ string, playlistID string, update domain.PlaylistUpdate) error {
+	if p.renamed == nil {
+		p.renamed = make(map[string]string)
+	}
+	p.renamed[playlistID] = *update.Name
+	return nil
+}
+
+func (p *cappedProvider) AddTracksToPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) ([]domain.AddOutcome, error) {
+	if p.added == nil {
+		p.added = make(map[string][]string)
+	}
+	p.added[playlistID] = append(p.added[playlistID], trackIDs...)
+	return p.mockProvider.AddTracksToPlaylist(ctx, token, playlistID, trackIDs)
+}
+
+func newCappedMigration(n, capacity int) (*Service, *cappedProvider) {
+	source := &mockProvider{name: "source"}
+	dest := &cappedProvider{mockProvider: &mockProvider{name: "dest", searchResults: map[string]*searchResult{}}, capacity: capacity}
+	for i := range n {
+		name := fmt.Sprintf("Track %d", i)
+		source.tracks = append(source.tracks, domain.Track{Name: name, Artists: []string{"Artist"}})
+		dest.searchResults[name+"|Artist"] = &searchResult{track: &domain.Track{ExternalID: fmt.Sprintf("t%d", i)}, score: 0.9}
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+	return newService(registry, 2), dest
+}
+
+func TestPlaylistParts(t *testing.T) {
+	capped := &cappedProvider{mockProvider: &mockProvider{}, capacity: 2}
+	assert.Equal(t, []playlistPart{{0, 2}, {2, 4}, {4, 5}}, playlistParts(capped, 5))
+	assert.Equal(t, []playlistPart{{0, 2}}, playlistParts(capped, 2))
+	assert.Equal(t, []playlistPart{{}}, playlistParts(capped, 0))
+	assert.Equal(t, []playlistPart{{0, 5}}, playlistParts(&mockProvider{}, 5))
+}
+
+func TestAppendedParts(t *testing.T) {
+	capped := &cappedProvider{mockProvider: &mockProvider{}, capacity: 2}
+	assert.Equal(t, []playlistPart{{0, 1}}, appendedParts(capped, 1, 1, 1))
+	assert.Equal(t, []playlistPart{{0, 1}, {1, 3}, {3, 4}}, appendedParts(capped, 2, 3, 4))
+	assert.Equal(t, []playlistPart{{0, 0}, {0, 1}}, appendedParts(capped, 1, 2, 1))
+	assert.Equal(t, []playlistPart{{0, 5}}, appendedParts(&mockProvider{}, 1, 10, 5))
+}
+
+func TestMigratePlaylist_SplitsLargePlaylists(t *testing.T) {
+	svc, dest := newCappedMigration(5, 2)
+
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Migrated from source (1/3)", "Migrated from source (2/3)", "Migrated from source (3/3)"}, dest.names)
+	assert.Equal(t, "part-1", result.DestPlaylistID)
+	assert.Equal(t, []string{"part-1", "part-2", "part-3"}, result.DestPlaylistIDs)
+	assert.Equal(t, map[string][]string{
+		"part-1": {"t0", "t1"},
+		"part-2": {"t2", "t3"},
+		"part-3": {"t4"},
+	}, dest.added)
+	assert.Equal(t, 5, result.MatchedTracks)
+	require.NotNil(t, result.TrackResults[4].DestPosition)
+	assert.Equal(t, 4, *result.TrackResults[4].DestPosition)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "split into 3 playlists")
+
+	_, err = svc.ReverseMigration(context.Background(), result.ID, domain.ReverseMigrationRequest{})
+	assert.ErrorContains(t, err, "cannot be reversed")
+
+	_, err = svc.RollbackMigration(context.Background(), result.ID, "t")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"part-1", "part-2", "part-3"}, dest.deletedIDs)
+}
+
+func TestMigratePlaylist_FitsIntoOnePlaylist(t *testing.T) {
+	svc, dest := newCappedMigration(2, 2)
+
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Migrated from source"}, dest.names)
+	assert.Equal(t, "part-1", result.DestPlaylistID)
+	assert.Nil(t, result.DestPlaylistIDs)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestMigratePlaylist_SplitCreateFailureDeletesParts(t *testing.T) {
+	svc, dest := newCappedMigration(5, 2)
+	dest.failAfter = 2
+
+	_, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+	require.ErrorContains(t, err, "failed to create destination playlist")
+	assert.Equal(t, []string{"part-1", "part-2"}, dest.deletedIDs)
+	assert.Empty(t, dest.added)
+}
+
+func TestRetryFailedTracks_OpensNewPart(t *testing.T) {
+	svc, dest := newCappedMigration(3, 2)
+	delete(dest.searchResults, "Track 2|Artist")
+
+	result, err := svc.MigratePlaylist(context.Background(), domain.MigrationRequest{
+		SourceProvider: "source",
+		SourceToken:    "t1",
+		DestProvider:   "dest",
+		DestToken:      "t2",
+		PlaylistID:     "pl-1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, result.MatchedTracks)
+	assert.Nil(t, result.DestPlaylistIDs)
+
+	dest.searchResults["Track 2|Artist"] = &searchResult{track: &domain.Track{ExternalID: "t2"}, score: 0.9}
+	result, err = svc.RetryFailedTracks(context.Background(), result.ID, "t2")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Migrated from source", "Migrated from source (2/2)"}, dest.names)
+	assert.Equal(t, map[string]string{"part-1": "Migrated from source (1/2)"}, dest.renamed)
+	assert.Equal(t, "Migrated from source (1/2)", result.DestPlaylistName)
+	assert.Equal(t, []string{"part-1", "part-2"}, result.DestPlaylistIDs)
+	assert.Equal(t, map[string][]string{
+		"part-1": {"t0", "t1"},
+		"part-2": {"t2"},
+	}, dest.added)
+	assert.Equal(t, 3, result.MatchedTracks)
+	require.NotNil(t, result.TrackResults[2].DestPosition)
+	assert.Equal(t, 2, *result.TrackResults[2].DestPosition)
+	assert.Contains(t, result.Warnings[len(result.Warnings)-1], "1 of them go into new parts")
+
+	_, err = svc.RollbackMigration(context.Background(), result.ID, "t2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"part-1", "part-2"}, dest.deletedIDs)
+}
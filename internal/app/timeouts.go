@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+)
+
+// Timeouts bounds the provider calls made by a migration. Search, Fetch,
+// Create and Add apply to each single call of their stage; Migration bounds
+// the whole migration or retry. Zero means no timeout.
+type Timeouts struct {
+	Search    time.Duration
+	Fetch     time.Duration
+	Create    time.Duration
+	Add       time.Duration
+	Migration time.Duration
+}
+
+func (t Timeouts) forStage(stage domain.Stage) time.Duration {
+	switch stage {
+	case domain.StageSearch:
+		return t.Search
+	case domain.StageFetch:
+		return t.Fetch
+	case domain.StageCreate:
+		return t.Create
+	case domain.StageAdd:
+		return t.Add
+	case domain.StageMigration:
+		return t.Migration
+	default:
+		return 0
+	}
+}
+
+// WithTimeouts sets per-stage and overall migration timeouts.
+func WithTimeouts(t Timeouts) Option {
+	return func(s *Service) {
+		s.timeouts = t
+	}
+}
+
+// withDeadline bounds ctx by the overall migration timeout, if one is set.
+func (s *Service) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeouts.Migration <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.timeouts.Migration)
+}
+
+// runStage calls fn with ctx bounded by the timeout of stage. If the call
+// fails because the stage timeout or the overall migration deadline expired,
+// the error is replaced by a domain.StageTimeoutError naming the stage that
+// ran out of time.
+func (s *Service) runStage(ctx context.Context, stage domain.Stage, fn func(ctx context.Context) error) error {
+	stageCtx := ctx
+	if d := s.timeouts.forStage(stage); d > 0 {
+		var cancel context.CancelFunc
+		stageCtx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	err := fn(stageCtx)
+	if err == nil {
+		return nil
+	}
+	if timeoutErr := s.timeoutError(ctx); timeoutErr != nil {
+		return timeoutErr
+	}
+	if errors.Is(stageCtx.Err(), context.DeadlineExceeded) {
+		return &domain.StageTimeoutError{Stage: stage, Timeout: s.timeouts.forStage(stage)}
+	}
+	return err
+}
+
+// timeoutError returns a StageMigration timeout error if the overall
+// migration deadline of ctx has expired, or nil otherwise.
+func (s *Service) timeoutError(ctx context.Context) error {
+	if s.timeouts.Migration > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &domain.StageTimeoutError{Stage: domain.StageMigration, Timeout: s.timeouts.Migration}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowProvider is a mockProvider whose searches for slowTrack and, with
+// slowCreate, playlist creation block until their context is done.
+type slowProvider struct {
+	*mockProvider
+	slowTrack  string
+	slowCreate bool
+}
+
+func (p *slowProvider) SearchTrack(ctx context.Context, token string, track domain.Track) (*domain.Track, float64, error) {
+	if track.Name == p.slowTrack {
+		<-ctx.Done()
+		return nil, 0, ctx.Err()
+	}
+	return p.mockProvider.SearchTrack(ctx, token, track)
+}
+
+func (p *slowProvider) CreatePlaylist(ctx context.Context, token string, name string, description string) (string, error) {
+	if p.slowCreate {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	return p.mockProvider.CreatePlaylist(ctx, token, name, description)
+}
+
+func newTimeoutFixture(dest *slowProvider) *adapters.ProviderRegistry {
+	source := &mockProvider{
+		name: "source",
+		tracks: []domain.Track{
+			{Name: "Fast", Artists: []string{"A"}},
+			{Name: "Slow", Artists: []string{"B"}},
+		},
+	}
+	dest.name = "dest"
+	dest.createdID = "new-playlist"
+	dest.searchResults = map[string]*searchResult{
+		"Fast|A": {track: &domain.Track{Name: "Fast", ExternalID: "fast-1"}, score: 0.9},
+	}
+
+	registry := adapters.NewProviderRegistry()
+	registry.Register(source)
+	registry.Register(dest)
+	return registry
+}
+
+var timeoutRequest = domain.MigrationRequest{
+	SourceProvider: "source",
+	SourceToken:    "token-source",
+	DestProvider:   "dest",
+	DestToken:      "token-dest",
+	PlaylistID:     "playlist-1",
+}
+
+func TestMigratePlaylist_SearchTimeout(t *testing.T) {
+	dest := &slowProvider{mockProvider: &mockProvider{}, slowTrack: "Slow"}
+	svc := newService(newTimeoutFixture(dest), 2, WithTimeouts(Timeouts{Search: 20 * time.Millisecond}))
+
+	result, err := svc.MigratePlaylist(context.Background(), timeoutRequest)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.MatchedTracks)
+	assert.Equal(t, domain.TrackStatusError, result.TrackResults[1].Status)
+	assert.Equal(t, "search timed out after 20ms", result.TrackResults[1].Error)
+	assert.Equal(t, domain.ErrorCodeTimeout, result.TrackResults[1].ErrorCode)
+	assert.True(t, result.TrackResults[1].Retryable)
+	assert.Equal(t, []string{"fast-1"}, dest.addedTracks)
+
+	// The slow search bounds the search pass.
+	slow := result.TrackResults[1]
+	assert.Equal(t, 1, slow.Attempts)
+	assert.GreaterOrEqual(t, slow.LatencyMS, int64(20))
+	assert.GreaterOrEqual(t, slow.SearchMS, slow.LatencyMS)
+	require.NotNil(t, result.Timing)
+	assert.GreaterOrEqual(t, result.Timing.SearchMS, slow.SearchMS)
+	assert.GreaterOrEqual(t, result.Timing.TotalMS, result.Timing.SearchMS)
+}
+
+func TestMigratePlaylist_CreateTimeout(t *testing.T) {
+	dest := &slowProvider{mockProvider: &mockProvider{}, slowCreate: true}
+	svc := newService(newTimeoutFixture(dest), 2, WithTimeouts(Timeouts{Create: 20 * time.Millisecond}))
+
+	_, err := svc.MigratePlaylist(context.Background(), timeoutRequest)
+
+	require.ErrorIs(t, err, domain.ErrTimeout)
+	var timeoutErr *domain.StageTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, domain.StageCreate, timeoutErr.Stage)
+}
+
+func TestMigratePlaylist_MigrationDeadline(t *testing.T) {
+	dest := &slowProvider{mockProvider: &mockProvider{}, slowTrack: "Slow", slowCreate: true}
+	svc := newService(newTimeoutFixture(dest), 2, WithTimeouts(Timeouts{
+		Search:    time.Second,
+		Create:    time.Second,
+		Migration: 30 * time.Millisecond,
+	}))
+
+	_, err := svc.MigratePlaylist(context.Background(), timeoutRequest)
+
+	var timeoutErr *domain.StageTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, domain.StageMigration, timeoutErr.Stage)
+	assert.ErrorContains(t, err, "migration timed out after 30ms")
+}
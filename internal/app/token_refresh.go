@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// tokenRefreshMargin is how long before its expiry a stored token is
+// refreshed, so it does not expire while a migration uses it.
+const tokenRefreshMargin = 5 * time.Minute
+
+// refreshToken renews an expired stored token with the provider and stores
+// the new one in the vault. Tokens that cannot be refreshed, because the
+// provider does not support it or no refresh token was stored, are returned
+// as they are and left for the provider to reject.
+func (s *Service) refreshToken(ctx context.Context, provider string, stored *domain.ProviderToken) (string, error) {
+	p, err := s.registry.Get(provider)
+	if err != nil {
+		return "", err
+	}
+	refresher, ok := p.(ports.TokenRefresher)
+	if !ok || stored.RefreshToken == "" {
+		return stored.AccessToken, nil
+	}
+
+	fresh, err := refresher.RefreshToken(ctx, stored.RefreshToken)
+	if err != nil {
+		return "", fmt.Errorf("stored %s token expired and could not be refreshed (%v): %w", provider, err, domain.ErrInvalidToken)
+	}
+	if fresh.RefreshToken == "" {
+		fresh.RefreshToken = stored.RefreshToken
+	}
+	if len(fresh.Scopes) == 0 {
+		fresh.Scopes = stored.Scopes
+	}
+	if err := s.tokens.StoreToken(ctx, provider, *fresh); err != nil {
+		// The new token still works for this request.
+		log.Printf("[tokens] failed to store refreshed %s token: %v", provider, err)
+	}
+	log.Printf("[tokens] refreshed %s token", provider)
+	return fresh.AccessToken, nil
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refreshingProvider is a mockProvider that can refresh tokens.
+type refreshingProvider struct {
+	*mockProvider
+	err       error
+	refreshed []string
+}
+
+func (p *refreshingProvider) RefreshToken(_ context.Context, refreshToken string) (*domain.ProviderToken, error) {
+	p.refreshed = append(p.refreshed, refreshToken)
+	if p.err != nil {
+		return nil, p.err
+	}
+	expires := time.Now().Add(time.Hour)
+	return &domain.ProviderToken{AccessToken: "fresh-token", ExpiresAt: &expires}, nil
+}
+
+func newRefreshService(t *testing.T, provider *refreshingProvider, stored domain.ProviderToken) (*Service, *TokenVault, context.Context) {
+	t.Helper()
+	registry := adapters.NewProviderRegistry()
+	registry.Register(provider)
+
+	vault, err := NewTokenVault(memory.NewTokenStore(), testKey)
+	require.NoError(t, err)
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-1"})
+	require.NoError(t, vault.StoreToken(ctx, "test", stored))
+
+	return newService(registry, 1, WithTokenVault(vault)), vault, ctx
+}
+
+func TestResolveToken_RefreshesExpiredToken(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	provider := &refreshingProvider{mockProvider: &mockProvider{name: "test"}}
+	svc, vault, ctx := newRefreshService(t, provider, domain.ProviderToken{
+		AccessToken: "old-token", RefreshToken: "refresh-1", ExpiresAt: &expired, Scopes: []string{"read"},
+	})
+
+	_, err := svc.ListPlaylists(ctx, "test", "")
+	require.NoError(t, err)
+	assert.Equal(t, "fresh-token", provider.lastToken)
+	assert.Equal(t, []string{"refresh-1"}, provider.refreshed)
+
+	// The new token is stored and keeps the refresh token and scopes.
+	stored, err := vault.GetToken(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "fresh-token", stored.AccessToken)
+	assert.Equal(t, "refresh-1", stored.RefreshToken)
+	assert.Equal(t, []string{"read"}, stored.Scopes)
+
+	_, err = svc.ListPlaylists(ctx, "test", "")
+	require.NoError(t, err)
+	assert.Len(t, provider.refreshed, 1)
+}
+
+func TestResolveToken_KeepsValidToken(t *testing.T) {
+	valid := time.Now().Add(time.Hour)
+	provider := &refreshingProvider{mockProvider: &mockProvider{name: "test"}}
+	svc, _, ctx := newRefreshService(t, provider, domain.ProviderToken{
+		AccessToken: "stored-token", RefreshToken: "refresh-1", ExpiresAt: &valid,
+	})
+
+	_, err := svc.ListPlaylists(ctx, "test", "")
+	require.NoError(t, err)
+	assert.Equal(t, "stored-token", provider.lastToken)
+	assert.Empty(t, provider.refreshed)
+}
+
+func TestResolveToken_RefreshFails(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	provider := &refreshingProvider{mockProvider: &mockProvider{name: "test"}, err: errors.New("invalid_grant")}
+	svc, _, ctx := newRefreshService(t, provider, domain.ProviderToken{
+		AccessToken: "old-token", RefreshToken: "revoked", ExpiresAt: &expired,
+	})
+
+	_, err := svc.ListPlaylists(ctx, "test", "")
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	assert.ErrorContains(t, err, "invalid_grant")
+}
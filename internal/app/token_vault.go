@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// TokenVault implements ports.TokenVault. Tokens are serialized and sealed
+// with AES-GCM before reaching the store; the account ID and provider name
+// are bound as additional data so a ciphertext cannot be replayed under a
+// different account or provider.
+type TokenVault struct {
+	store ports.TokenStore
+	aead  cipher.AEAD
+}
+
+// NewTokenVault creates a vault that encrypts tokens with the given AES key,
+// which must be 16, 24 or 32 bytes long.
+func NewTokenVault(store ports.TokenStore, key []byte) (*TokenVault, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AES-GCM: %w", err)
+	}
+	return &TokenVault{store: store, aead: aead}, nil
+}
+
+func (v *TokenVault) StoreToken(ctx context.Context, provider string, token domain.ProviderToken) error {
+	accountID, err := requireAccount(ctx)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	return v.store.Put(ctx, accountID, provider, v.seal(plaintext, additionalData(accountID, provider)))
+}
+
+func (v *TokenVault) GetToken(ctx context.Context, provider string) (*domain.ProviderToken, error) {
+	accountID, err := requireAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := v.store.Get(ctx, accountID, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := v.open(ciphertext, additionalData(accountID, provider))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token for %s: %w", provider, err)
+	}
+
+	var token domain.ProviderToken
+	if err := json.Unmarshal(plaintext, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token for %s: %w", provider, err)
+	}
+	return &token, nil
+}
+
+func (v *TokenVault) DeleteToken(ctx context.Context, provider string) error {
+	accountID, err := requireAccount(ctx)
+	if err != nil {
+		return err
+	}
+	return v.store.Delete(ctx, accountID, provider)
+}
+
+func (v *TokenVault) ListProviders(ctx context.Context) ([]string, error) {
+	accountID, err := requireAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return v.store.List(ctx, accountID)
+}
+
+// seal encrypts plaintext bound to additional data ad, prefixing the
+// ciphertext with its random nonce.
+func (v *TokenVault) seal(plaintext []byte, ad []byte) []byte {
+	nonce := make([]byte, v.aead.NonceSize())
+	_, _ = rand.Read(nonce) // crypto/rand.Read never returns an error
+	return v.aead.Seal(nonce, nonce, plaintext, ad)
+}
+
+// open decrypts a ciphertext returned by seal with the same additional data.
+func (v *TokenVault) open(ciphertext []byte, ad []byte) ([]byte, error) {
+	nonceSize := v.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("ciphertext is corrupt")
+	}
+	return v.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], ad)
+}
+
+// requireAccount returns the account ID from ctx. Stored tokens are always
+// tied to an account, so unauthenticated requests cannot use the vault.
+func requireAccount(ctx context.Context) (string, error) {
+	accountID := domain.AccountIDFromContext(ctx)
+	if accountID == "" {
+		return "", fmt.Errorf("token vault requires an authenticated account")
+	}
+	return accountID, nil
+}
+
+func additionalData(accountID, provider string) []byte {
+	return []byte(accountID + "/" + provider)
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = bytes.Repeat([]byte{0x42}, 32)
+
+func TestTokenVault_RoundTrip(t *testing.T) {
+	store := memory.NewTokenStore()
+	vault, err := NewTokenVault(store, testKey)
+	require.NoError(t, err)
+
+	ctx := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "acc-1"})
+	err = vault.StoreToken(ctx, "spotify", domain.ProviderToken{AccessToken: "secret-access", RefreshToken: "secret-refresh"})
+	require.NoError(t, err)
+
+	ciphertext, err := store.Get(ctx, "acc-1", "spotify")
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "secret-access")
+
+	token, err := vault.GetToken(ctx, "spotify")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-access", token.AccessToken)
+	assert.Equal(t, "secret-refresh", token.RefreshToken)
+
+	require.NoError(t, vault.DeleteToken(ctx, "spotify"))
+	_, err = vault.GetToken(ctx, "spotify")
+	require.ErrorIs(t, err, domain.ErrTokenNotFound)
+}
+
+func TestTokenVault_CiphertextBoundToAccount(t *testing.T) {
+	store := memory.NewTokenStore()
+	vault, err := NewTokenVault(store, testKey)
+	require.NoError(t, err)
+
+	alice := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "alice"})
+	bob := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "bob"})
+	require.NoError(t, vault.StoreToken(alice, "spotify", domain.ProviderToken{AccessToken: "alice-token"}))
+
+	// Copy Alice's ciphertext into Bob's slot; decryption must fail.
+	ciphertext, err := store.Get(alice, "alice", "spotify")
+	require.NoError(t, err)
+	require.NoError(t, store.Put(bob, "bob", "spotify", ciphertext))
+
+	_, err = vault.GetToken(bob, "spotify")
+	require.Error(t, err)
+}
+
+func TestTokenVault_RequiresAccount(t *testing.T) {
+	vault, err := NewTokenVault(memory.NewTokenStore(), testKey)
+	require.NoError(t, err)
+
+	err = vault.StoreToken(context.Background(), "spotify", domain.ProviderToken{AccessToken: "x"})
+	require.Error(t, err)
+}
+
+func TestNewTokenVault_InvalidKey(t *testing.T) {
+	_, err := NewTokenVault(memory.NewTokenStore(), []byte("short"))
+	require.Error(t, err)
+}
+
+func TestTokenVault_ListProviders(t *testing.T) {
+	vault, err := NewTokenVault(memory.NewTokenStore(), testKey)
+	require.NoError(t, err)
+
+	alice := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "alice"})
+	bob := domain.ContextWithAccount(context.Background(), &domain.Account{ID: "bob"})
+	require.NoError(t, vault.StoreToken(alice, "youtube", domain.ProviderToken{AccessToken: "a"}))
+	require.NoError(t, vault.StoreToken(alice, "spotify", domain.ProviderToken{AccessToken: "b"}))
+	require.NoError(t, vault.StoreToken(bob, "deezer", domain.ProviderToken{AccessToken: "c"}))
+
+	providers, err := vault.ListProviders(alice)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spotify", "youtube"}, providers)
+}
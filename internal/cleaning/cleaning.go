@@ -0,0 +1,145 @@
+// Package cleaning normalizes track titles scraped from video platforms by
+// stripping noise such as "(Official Video)" and separating featured artists.
+// Rules are regular expressions and can be extended or replaced from a JSON
+// file.
+package cleaning
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Rule removes or rewrites parts of a title. Every match of Pattern is
+// replaced with Replace, which may reference capture groups ($1).
+type Rule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	Replace string `json:"replace"`
+}
+
+// RuleFile is the JSON format accepted by LoadFile.
+type RuleFile struct {
+	// ReplaceDefaults discards the built-in rules instead of appending to them.
+	ReplaceDefaults bool   `json:"replace_defaults"`
+	Rules           []Rule `json:"rules"`
+}
+
+// DefaultRules strip common upload markers in several languages.
+var DefaultRules = []Rule{
+	{
+		Name:    "official-markers",
+		Pattern: `(?i)[\(\[]\s*(official\s*)?(music\s*)?(video|audio|visuali[sz]er|lyric\s*video|lyrics?)(\s*video)?\s*[\)\]]`,
+	},
+	{
+		Name:    "quality-markers",
+		Pattern: `(?i)[\(\[]\s*(hd|hq|4k|1080p|720p|remastered(\s*\d{4})?)\s*[\)\]]`,
+	},
+	{
+		Name:    "edits",
+		Pattern: `(?i)[\(\[]\s*(slowed(\s*(\+|&|and)\s*reverb)?|sped\s*up|nightcore|reverb)\s*[\)\]]`,
+	},
+	{
+		Name:    "non-english-markers",
+		Pattern: `(?i)[\(\[]\s*(video\s*oficial|clipe\s*oficial|audio\s*oficial|letra|legendado|clip\s*officiel|paroles|offizielles\s*video|m/?v)\s*[\)\]]`,
+	},
+	{
+		Name:    "trailing-pipe-sections",
+		Pattern: `\s+\|.*$`,
+	},
+}
+
+// featPattern matches "feat. X", "ft. X" or "featuring X", optionally
+// wrapped in brackets, up to the closing bracket or a " - " separator.
+var featPattern = regexp.MustCompile(`(?i)\s*[\(\[]?\s*\b(?:feat\.?|ft\.?|featuring)\s+([^\)\]]+?)\s*(?:[\)\]]|$|\s-\s)`)
+
+// artistSeparator splits a list of featured artists.
+var artistSeparator = regexp.MustCompile(`\s*(?:,|&|\band\b)\s*`)
+
+type compiledRule struct {
+	re      *regexp.Regexp
+	replace string
+}
+
+// Cleaner applies an ordered list of rules to titles. It is safe for
+// concurrent use.
+type Cleaner struct {
+	rules []compiledRule
+}
+
+// New compiles rules into a Cleaner.
+func New(rules []Rule) (*Cleaner, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, r := range rules {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("cleaning: invalid pattern for rule %q: %w", r.Name, err)
+		}
+		compiled = append(compiled, compiledRule{re: re, replace: r.Replace})
+	}
+	return &Cleaner{rules: compiled}, nil
+}
+
+// Default returns a Cleaner with DefaultRules.
+func Default() *Cleaner {
+	c, err := New(DefaultRules)
+	if err != nil {
+		panic(err) // built-in rules are covered by tests
+	}
+	return c
+}
+
+// LoadFile builds a Cleaner from a JSON RuleFile. Unless the file sets
+// replace_defaults, its rules run after DefaultRules.
+func LoadFile(path string) (*Cleaner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cleaning: failed to read rules file: %w", err)
+	}
+
+	var file RuleFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("cleaning: failed to parse rules file: %w", err)
+	}
+
+	rules := file.Rules
+	if !file.ReplaceDefaults {
+		rules = append(append([]Rule(nil), DefaultRules...), file.Rules...)
+	}
+	return New(rules)
+}
+
+// Clean applies every rule to title and collapses leftover whitespace.
+func (c *Cleaner) Clean(title string) string {
+	for _, r := range c.rules {
+		title = r.re.ReplaceAllString(title, r.replace)
+	}
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// SplitFeaturing removes a "feat. X" clause from s and returns the remaining
+// text along with the featured artists.
+func SplitFeaturing(s string) (string, []string) {
+	m := featPattern.FindStringSubmatchIndex(s)
+	if m == nil {
+		return s, nil
+	}
+
+	var featured []string
+	for _, name := range artistSeparator.Split(s[m[2]:m[3]], -1) {
+		if name = strings.TrimSpace(name); name != "" {
+			featured = append(featured, name)
+		}
+	}
+
+	rest := s[:m[0]]
+	// Keep a " - " separator that the clause consumed.
+	if strings.HasSuffix(s[m[0]:m[1]], " - ") {
+		rest += " - "
+	}
+	rest += s[m[1]:]
+
+	return strings.Join(strings.Fields(rest), " "), featured
+}
@@ -0,0 +1,67 @@
+package cleaning
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClean_DefaultRules(t *testing.T) {
+	c := Default()
+
+	cases := map[string]string{
+		"Queen - Bohemian Rhapsody (Official Video)":             "Queen - Bohemian Rhapsody",
+		"Daft Punk - Get Lucky [Official Audio]":                 "Daft Punk - Get Lucky",
+		"The Weeknd - Blinding Lights (Visualizer)":              "The Weeknd - Blinding Lights",
+		"SZA - Kill Bill (slowed + reverb)":                      "SZA - Kill Bill",
+		"Anitta - Envolver (Video Oficial)":                      "Anitta - Envolver",
+		"Stromae - Alors on danse (Clip Officiel)":               "Stromae - Alors on danse",
+		"Adele - Hello (Lyric Video) [HD]":                       "Adele - Hello",
+		"Artist - Song | Live at Wembley | Remastered":           "Artist - Song",
+		"Nothing to clean here":                                  "Nothing to clean here",
+		"Radiohead - Creep (Official Music Video) (4K)":          "Radiohead - Creep",
+		"Linkin Park - Numb (Official Music Video) [4K UPGRADE]": "Linkin Park - Numb [4K UPGRADE]",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, c.Clean(in), in)
+	}
+}
+
+func TestSplitFeaturing(t *testing.T) {
+	rest, featured := SplitFeaturing("Calvin Harris - This Is What You Came For (feat. Rihanna)")
+	assert.Equal(t, "Calvin Harris - This Is What You Came For", rest)
+	assert.Equal(t, []string{"Rihanna"}, featured)
+
+	rest, featured = SplitFeaturing("DJ Khaled ft. Drake & Lil Wayne - No New Friends")
+	assert.Equal(t, "DJ Khaled - No New Friends", rest)
+	assert.Equal(t, []string{"Drake", "Lil Wayne"}, featured)
+
+	rest, featured = SplitFeaturing("Daft Punk - Get Lucky")
+	assert.Equal(t, "Daft Punk - Get Lucky", rest)
+	assert.Nil(t, featured)
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	err := os.WriteFile(path, []byte(`{"rules":[{"name":"karaoke","pattern":"(?i)\\s*\\(karaoke\\)"}]}`), 0o600)
+	require.NoError(t, err)
+
+	c, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Song", c.Clean("Song (Karaoke) (Official Video)"))
+
+	err = os.WriteFile(path, []byte(`{"replace_defaults":true,"rules":[{"name":"karaoke","pattern":"(?i)\\s*\\(karaoke\\)"}]}`), 0o600)
+	require.NoError(t, err)
+
+	c, err = LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Song (Official Video)", c.Clean("Song (Karaoke) (Official Video)"))
+}
+
+func TestNew_InvalidPattern(t *testing.T) {
+	_, err := New([]Rule{{Name: "broken", Pattern: "("}})
+	require.Error(t, err)
+}
@@ -1,41 +1,528 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
-// Config holds all application configuration loaded from environment variables.
+// Config holds all application configuration, loaded from an optional
+// configuration file and environment variables.
 type Config struct {
 	Port             string
 	MigrationWorkers int
-	LogLevel         string
+
+	// JobWorkers is the number of queued migrations run concurrently by
+	// this instance; 0 queues jobs without running them here.
+	JobWorkers  int
+	LogLevel    string
+	AuthEnabled bool
+
+	// RateLimitRPS is the sustained number of requests per second allowed per
+	// client on /api/v1 routes; 0 disables rate limiting.
+	RateLimitRPS   float64
+	RateLimitBurst int
+
+	// YouTubeDailyQuota is the daily YouTube Data API unit budget. When
+	// QuotaEnforce is true, migrations that would exceed it are rejected;
+	// otherwise they run with a warning.
+	YouTubeDailyQuota int
+	QuotaEnforce      bool
+
+	// AccountMigrationsPerDay, AccountMaxTracks and AccountConcurrentJobs
+	// are the limits of accounts that administrators have not given limits
+	// of their own; 0 means unlimited.
+	AccountMigrationsPerDay int
+	AccountMaxTracks        int
+	AccountConcurrentJobs   int
+
+	// YouTubeSearchCacheTTL is how long YouTube search responses are kept in
+	// the storage backend and reused for searches that normalize alike; 0
+	// disables the cache. YouTubeVerifyCachedSearches checks the videos of
+	// cached responses with the quota-free oEmbed endpoint before use.
+	YouTubeSearchCacheTTL       time.Duration
+	YouTubeVerifyCachedSearches bool
+
+	// YouTubeBlocklist lists terms marking YouTube search candidates as
+	// unwanted uploads, such as nightcore or karaoke versions; empty uses
+	// the built-in list. YouTubeBlocklistMode is "penalize", "reject" or
+	// "off".
+	YouTubeBlocklist     []string
+	YouTubeBlocklistMode string
+
+	// SearchTimeout, FetchTimeout, CreateTimeout and AddTimeout bound each
+	// provider call of that migration stage; MigrationTimeout bounds a whole
+	// migration or retry. Zero disables a timeout.
+	SearchTimeout    time.Duration
+	FetchTimeout     time.Duration
+	CreateTimeout    time.Duration
+	AddTimeout       time.Duration
+	MigrationTimeout time.Duration
+
+	// StorageDriver selects where migrations, accounts and tokens are kept:
+	// "memory" (default), "sqlite" (requires building with -tags sqlite) or
+	// "postgres" (requires building with -tags postgres).
+	StorageDriver string
+
+	// SQLitePath is the database file used when StorageDriver is "sqlite".
+	SQLitePath string
+
+	// PostgresDSN is the connection string used when StorageDriver is
+	// "postgres".
+	PostgresDSN string
+
+	// TrackMappings caches every match as a two-way mapping between provider
+	// track IDs in the storage backend, so later migrations reuse it
+	// instead of searching.
+	TrackMappings bool
+
+	// TrackMappingQuorum is the number of accounts that must have given a
+	// verdict on a match before the verdict of most of them saves it as a
+	// track mapping.
+	TrackMappingQuorum int
+
+	// Plugins lists provider plugin executables to start and register at
+	// boot (comma-separated PLUGINS).
+	Plugins []string
+
+	// EnabledProviders restricts the providers registered at boot to the
+	// named ones (comma-separated ENABLED_PROVIDERS). Empty registers every
+	// configured provider.
+	EnabledProviders []string
+
+	// SandboxProvider registers the built-in "sandbox" provider, which serves
+	// deterministic in-memory playlists for end-to-end testing.
+	// SandboxFailureRate is the fraction (0.0 to 1.0) of its track searches
+	// that fail.
+	SandboxProvider    bool
+	SandboxFailureRate float64
+
+	// LocalLibraryDir registers the source-only "localfiles" provider, which
+	// reads the MP3 and FLAC files below this directory.
+	LocalLibraryDir string
+
+	// M3UDir makes the .m3u and .m3u8 files in this directory available
+	// through the source-only "m3u" provider, by file name. Uploaded files
+	// are accepted either way.
+	M3UDir string
+
+	// LastFMAPIKey registers the source-only "lastfm" provider, which
+	// exposes a user's loved and top tracks as playlists.
+	LastFMAPIKey string
+
+	// GzipResponses compresses responses for clients that accept gzip.
+	GzipResponses bool
+
+	// HealthCheckProviders makes /health ping each provider's API and report
+	// per-provider status and latency.
+	HealthCheckProviders bool
+
+	// TitleRulesFile optionally points to a JSON file of title-cleaning rules
+	// used when parsing and matching YouTube video titles.
+	TitleRulesFile string
+
+	// MetadataSources lists the catalogs migrations consult, in order and
+	// after the source provider, to fill in the ISRC, duration and album of
+	// source tracks lacking them before matching: "musicbrainz" and
+	// "deezer" (comma-separated METADATA_SOURCES). Empty disables
+	// enrichment. MetadataTimeout bounds each lookup; MetadataCacheTTL is
+	// how long answers are kept in the storage backend, 0 disables caching.
+	MetadataSources  []string
+	MetadataTimeout  time.Duration
+	MetadataCacheTTL time.Duration
+
+	// AcoustIDAPIKey enables the fingerprint fallback: source tracks not
+	// found by their metadata are identified through AcoustID by the
+	// fingerprint of their audio (local files, Spotify previews) and
+	// searched again. FpcalcPath is Chromaprint's fpcalc executable that
+	// computes the fingerprints.
+	AcoustIDAPIKey string
+	FpcalcPath     string
+
+	// Post-migration hooks, each enabled by its setting. HookWebhookURL
+	// receives every completed migration as JSON, signed with
+	// HookWebhookSecret if set; HookNotifyURL is a Slack- or
+	// Discord-compatible incoming webhook that receives a summary;
+	// HookSlackURL and HookDiscordURL receive a formatted summary linking
+	// to the unmatched tracks under PublicURL; HookListenBrainzToken
+	// submits matched tracks as imported listens.
+	HookWebhookURL        string
+	HookWebhookSecret     string
+	HookNotifyURL         string
+	HookSlackURL          string
+	HookDiscordURL        string
+	HookListenBrainzToken string
+
+	// PublicURL is the URL clients reach the API at, for links in
+	// notifications such as to a migration's report.
+	PublicURL string
+
+	// SMTPHost enables emailing a summary of each queued migration that
+	// finished or failed to the notify_email of its request, through the
+	// server at SMTPHost:SMTPPort as SMTPFrom. SMTPUsername and
+	// SMTPPassword authenticate with the server when set. Only addresses in
+	// SMTPAllowedDomains are emailed.
+	SMTPHost           string
+	SMTPPort           int
+	SMTPUsername       string
+	SMTPPassword       string
+	SMTPFrom           string
+	SMTPAllowedDomains []string
+
+	// SpotifyClientID and SpotifyClientSecret, when both set, let Spotify
+	// searches use an app token from the client-credentials flow instead of
+	// the user's token, and let expired user tokens in the vault be
+	// refreshed.
+	SpotifyClientID     string
+	SpotifyClientSecret string
+
+	// SpotifyRedirectURL and GoogleRedirectURL are the callback URLs
+	// registered with the OAuth clients. When set, accounts can link their
+	// provider through /api/v1/oauth/{provider}/authorize, and the provider
+	// redirects back to them with the authorization code.
+	SpotifyRedirectURL string
+	GoogleRedirectURL  string
+
+	// SpotifyPageConcurrency is how many pages of a playlist's tracks are
+	// fetched from Spotify at once.
+	SpotifyPageConcurrency int
+
+	// SpotifyAddInterval and YouTubeAddInterval are the pauses between the
+	// requests that add tracks to a playlist of that provider; 0 sends them
+	// back to back.
+	SpotifyAddInterval time.Duration
+	YouTubeAddInterval time.Duration
+
+	// GoogleClientID and GoogleClientSecret identify the Google OAuth client
+	// YouTube tokens were issued to; when both set, expired tokens in the
+	// vault are refreshed.
+	GoogleClientID     string
+	GoogleClientSecret string
+
+	// AdminAPIKey enables the /admin endpoints for managing providers at
+	// runtime; requests must send it in the X-Admin-Key header.
+	AdminAPIKey string
+
+	// TokenEncryptionKey is a base64-encoded AES key (16, 24 or 32 bytes).
+	// When set together with AuthEnabled, provider tokens can be stored
+	// server-side in an encrypted vault.
+	TokenEncryptionKey string
+
+	// HTTPClient configures the HTTP clients that talk to provider APIs.
+	// ProviderHTTPClients overrides its non-zero settings for the providers
+	// named by its keys; it can only be set in the configuration file.
+	HTTPClient          HTTPClient
+	ProviderHTTPClients map[string]HTTPClient
+}
+
+// HTTPClient holds the connection settings of an outbound HTTP client.
+type HTTPClient struct {
+	Timeout             time.Duration `yaml:"timeout"`
+	DialTimeout         time.Duration `yaml:"dial_timeout"`
+	IdleConnTimeout     time.Duration `yaml:"idle_conn_timeout"`
+	MaxIdleConnsPerHost int           `yaml:"max_idle_conns_per_host"`
+	MaxConnsPerHost     int           `yaml:"max_conns_per_host"`
+
+	// MaxResponseBytes caps the size of a provider response body.
+	MaxResponseBytes int `yaml:"max_response_bytes"`
+
+	// Proxy is the URL of an HTTP proxy for HTTP and HTTPS requests, which
+	// the comma-separated hosts and domains of NoProxy bypass. Empty uses
+	// the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	Proxy   string `yaml:"proxy"`
+	NoProxy string `yaml:"no_proxy"`
+
+	// CABundle is a PEM file of root certificates trusted in addition to
+	// the system ones, such as the CA of a TLS-intercepting proxy.
+	// InsecureSkipVerify accepts any certificate, for test setups only.
+	CABundle           string `yaml:"ca_bundle"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
 }
 
-// Load reads configuration from .env file (if present) and environment variables.
-func Load() *Config {
+// Load reads configuration from the file named by CONFIG_FILE (if set), then
+// from the .env file (if present) and environment variables, which override
+// the file.
+func Load() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using environment variables")
 	}
 
-	workers, err := strconv.Atoi(getEnv("MIGRATION_WORKERS", "5"))
-	if err != nil {
-		workers = 5
+	cfg := defaults()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+		log.Printf("Loaded configuration from %s", path)
+	}
+	cfg.loadEnv()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks that provider OAuth credentials are complete, as a client
+// ID without its secret, or the reverse, is a configuration mistake, that
+// redirect URLs are absolute and belong to a client, that email
+// notifications have a sender, and that enumerated settings hold one of
+// their values.
+func (cfg *Config) Validate() error {
+	for _, c := range []struct {
+		provider, id, secret, redirect string
+	}{
+		{"SPOTIFY", cfg.SpotifyClientID, cfg.SpotifyClientSecret, cfg.SpotifyRedirectURL},
+		{"GOOGLE", cfg.GoogleClientID, cfg.GoogleClientSecret, cfg.GoogleRedirectURL},
+	} {
+		if (c.id == "") != (c.secret == "") {
+			return fmt.Errorf("config: %s_CLIENT_ID and %s_CLIENT_SECRET must be set together", c.provider, c.provider)
+		}
+		if c.redirect == "" {
+			continue
+		}
+		if c.id == "" {
+			return fmt.Errorf("config: %s_REDIRECT_URL requires %s_CLIENT_ID", c.provider, c.provider)
+		}
+		if u, err := url.Parse(c.redirect); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("config: %s_REDIRECT_URL must be an absolute http or https URL, not %q", c.provider, c.redirect)
+		}
+	}
+	if cfg.SMTPHost != "" && cfg.SMTPFrom == "" {
+		return fmt.Errorf("config: SMTP_FROM must be set when SMTP_HOST is")
+	}
+	if cfg.SMTPHost != "" && len(cfg.SMTPAllowedDomains) == 0 {
+		return fmt.Errorf("config: SMTP_ALLOWED_DOMAINS must be set when SMTP_HOST is")
+	}
+	for _, source := range cfg.MetadataSources {
+		switch strings.ToLower(source) {
+		case "musicbrainz", "deezer":
+		default:
+			return fmt.Errorf("config: METADATA_SOURCES may list musicbrainz and deezer, not %q", source)
+		}
+	}
+	switch cfg.YouTubeBlocklistMode {
+	case "penalize", "reject", "off":
+	default:
+		return fmt.Errorf("config: YOUTUBE_BLOCKLIST_MODE must be penalize, reject or off, not %q", cfg.YouTubeBlocklistMode)
+	}
+	return nil
+}
+
+// ProviderEnabled reports whether the provider called name may be
+// registered.
+func (cfg *Config) ProviderEnabled(name string) bool {
+	if len(cfg.EnabledProviders) == 0 {
+		return true
 	}
+	for _, enabled := range cfg.EnabledProviders {
+		if strings.EqualFold(enabled, name) {
+			return true
+		}
+	}
+	return false
+}
 
+// defaults returns the configuration used when neither a file nor the
+// environment sets a value.
+func defaults() *Config {
 	return &Config{
-		Port:             getEnv("PORT", "8080"),
-		MigrationWorkers: workers,
-		LogLevel:         getEnv("LOG_LEVEL", "info"),
+		Port:             "8080",
+		MigrationWorkers: 5,
+		JobWorkers:       2,
+		LogLevel:         "info",
+
+		RateLimitBurst: 10,
+
+		YouTubeDailyQuota: 10000,
+
+		SpotifyPageConcurrency: 4,
+		SpotifyAddInterval:     50 * time.Millisecond,
+		YouTubeAddInterval:     100 * time.Millisecond,
+
+		SearchTimeout:    10 * time.Second,
+		FetchTimeout:     time.Minute,
+		CreateTimeout:    15 * time.Second,
+		AddTimeout:       time.Minute,
+		MigrationTimeout: 10 * time.Minute,
+
+		StorageDriver:      "memory",
+		SQLitePath:         "musicmigration.db",
+		TrackMappings:      true,
+		TrackMappingQuorum: 3,
+
+		YouTubeSearchCacheTTL: 24 * time.Hour,
+		YouTubeBlocklistMode:  "penalize",
+
+		GzipResponses: true,
+
+		MetadataTimeout:  5 * time.Second,
+		MetadataCacheTTL: 7 * 24 * time.Hour,
+
+		FpcalcPath: "fpcalc",
+
+		SMTPPort: 587,
+
+		HTTPClient: HTTPClient{
+			Timeout:             30 * time.Second,
+			DialTimeout:         10 * time.Second,
+			IdleConnTimeout:     90 * time.Second,
+			MaxIdleConnsPerHost: 32,
+			MaxResponseBytes:    10 << 20,
+		},
 	}
 }
 
+// loadEnv overrides cfg with the environment variables that are set.
+func (cfg *Config) loadEnv() {
+	cfg.Port = getEnv("PORT", cfg.Port)
+	cfg.MigrationWorkers = getEnvInt("MIGRATION_WORKERS", cfg.MigrationWorkers)
+	cfg.JobWorkers = getEnvInt("JOB_WORKERS", cfg.JobWorkers)
+	cfg.LogLevel = getEnv("LOG_LEVEL", cfg.LogLevel)
+	cfg.AuthEnabled = getEnvBool("AUTH_ENABLED", cfg.AuthEnabled)
+
+	cfg.RateLimitRPS = getEnvFloat("RATE_LIMIT_RPS", cfg.RateLimitRPS)
+	cfg.RateLimitBurst = getEnvInt("RATE_LIMIT_BURST", cfg.RateLimitBurst)
+
+	cfg.YouTubeDailyQuota = getEnvInt("YOUTUBE_DAILY_QUOTA", cfg.YouTubeDailyQuota)
+	cfg.QuotaEnforce = getEnvBool("QUOTA_ENFORCE", cfg.QuotaEnforce)
+	cfg.AccountMigrationsPerDay = getEnvInt("ACCOUNT_MIGRATIONS_PER_DAY", cfg.AccountMigrationsPerDay)
+	cfg.AccountMaxTracks = getEnvInt("ACCOUNT_MAX_TRACKS", cfg.AccountMaxTracks)
+	cfg.AccountConcurrentJobs = getEnvInt("ACCOUNT_CONCURRENT_JOBS", cfg.AccountConcurrentJobs)
+	cfg.YouTubeSearchCacheTTL = getEnvDuration("YOUTUBE_SEARCH_CACHE_TTL", cfg.YouTubeSearchCacheTTL)
+	cfg.YouTubeVerifyCachedSearches = getEnvBool("YOUTUBE_VERIFY_CACHED_SEARCHES", cfg.YouTubeVerifyCachedSearches)
+	cfg.YouTubeBlocklist = getEnvList("YOUTUBE_BLOCKLIST", cfg.YouTubeBlocklist)
+	cfg.YouTubeBlocklistMode = getEnv("YOUTUBE_BLOCKLIST_MODE", cfg.YouTubeBlocklistMode)
+
+	cfg.SearchTimeout = getEnvDuration("SEARCH_TIMEOUT", cfg.SearchTimeout)
+	cfg.FetchTimeout = getEnvDuration("FETCH_TIMEOUT", cfg.FetchTimeout)
+	cfg.CreateTimeout = getEnvDuration("CREATE_TIMEOUT", cfg.CreateTimeout)
+	cfg.AddTimeout = getEnvDuration("ADD_TIMEOUT", cfg.AddTimeout)
+	cfg.MigrationTimeout = getEnvDuration("MIGRATION_TIMEOUT", cfg.MigrationTimeout)
+
+	cfg.StorageDriver = getEnv("STORAGE_DRIVER", cfg.StorageDriver)
+	cfg.SQLitePath = getEnv("SQLITE_PATH", cfg.SQLitePath)
+	cfg.PostgresDSN = getEnv("POSTGRES_DSN", cfg.PostgresDSN)
+	cfg.TrackMappings = getEnvBool("TRACK_MAPPINGS", cfg.TrackMappings)
+	cfg.TrackMappingQuorum = getEnvInt("TRACK_MAPPING_QUORUM", cfg.TrackMappingQuorum)
+
+	cfg.Plugins = getEnvList("PLUGINS", cfg.Plugins)
+	cfg.EnabledProviders = getEnvList("ENABLED_PROVIDERS", cfg.EnabledProviders)
+	cfg.MetadataSources = getEnvList("METADATA_SOURCES", cfg.MetadataSources)
+	cfg.MetadataTimeout = getEnvDuration("METADATA_TIMEOUT", cfg.MetadataTimeout)
+	cfg.MetadataCacheTTL = getEnvDuration("METADATA_CACHE_TTL", cfg.MetadataCacheTTL)
+	cfg.AcoustIDAPIKey = getEnv("ACOUSTID_API_KEY", cfg.AcoustIDAPIKey)
+	cfg.FpcalcPath = getEnv("FPCALC_PATH", cfg.FpcalcPath)
+
+	cfg.SandboxProvider = getEnvBool("SANDBOX_PROVIDER", cfg.SandboxProvider)
+	cfg.SandboxFailureRate = getEnvFloat("SANDBOX_FAILURE_RATE", cfg.SandboxFailureRate)
+
+	cfg.LocalLibraryDir = getEnv("LOCAL_LIBRARY_DIR", cfg.LocalLibraryDir)
+	cfg.M3UDir = getEnv("M3U_DIR", cfg.M3UDir)
+	cfg.LastFMAPIKey = getEnv("LASTFM_API_KEY", cfg.LastFMAPIKey)
+
+	cfg.GzipResponses = getEnvBool("GZIP_RESPONSES", cfg.GzipResponses)
+
+	cfg.HealthCheckProviders = getEnvBool("HEALTH_CHECK_PROVIDERS", cfg.HealthCheckProviders)
+
+	cfg.TitleRulesFile = getEnv("TITLE_RULES_FILE", cfg.TitleRulesFile)
+
+	cfg.HookWebhookURL = getEnv("HOOK_WEBHOOK_URL", cfg.HookWebhookURL)
+	cfg.HookWebhookSecret = getEnv("HOOK_WEBHOOK_SECRET", cfg.HookWebhookSecret)
+	cfg.HookNotifyURL = getEnv("HOOK_NOTIFY_URL", cfg.HookNotifyURL)
+	cfg.HookSlackURL = getEnv("HOOK_SLACK_URL", cfg.HookSlackURL)
+	cfg.HookDiscordURL = getEnv("HOOK_DISCORD_URL", cfg.HookDiscordURL)
+	cfg.HookListenBrainzToken = getEnv("HOOK_LISTENBRAINZ_TOKEN", cfg.HookListenBrainzToken)
+
+	cfg.PublicURL = getEnv("PUBLIC_URL", cfg.PublicURL)
+	cfg.SMTPHost = getEnv("SMTP_HOST", cfg.SMTPHost)
+	cfg.SMTPPort = getEnvInt("SMTP_PORT", cfg.SMTPPort)
+	cfg.SMTPUsername = getEnv("SMTP_USERNAME", cfg.SMTPUsername)
+	cfg.SMTPPassword = getEnv("SMTP_PASSWORD", cfg.SMTPPassword)
+	cfg.SMTPFrom = getEnv("SMTP_FROM", cfg.SMTPFrom)
+	cfg.SMTPAllowedDomains = getEnvList("SMTP_ALLOWED_DOMAINS", cfg.SMTPAllowedDomains)
+
+	cfg.SpotifyClientID = getEnv("SPOTIFY_CLIENT_ID", cfg.SpotifyClientID)
+	cfg.SpotifyClientSecret = getEnv("SPOTIFY_CLIENT_SECRET", cfg.SpotifyClientSecret)
+	cfg.SpotifyRedirectURL = getEnv("SPOTIFY_REDIRECT_URL", cfg.SpotifyRedirectURL)
+	cfg.SpotifyPageConcurrency = getEnvInt("SPOTIFY_PAGE_CONCURRENCY", cfg.SpotifyPageConcurrency)
+	cfg.SpotifyAddInterval = getEnvDuration("SPOTIFY_ADD_INTERVAL", cfg.SpotifyAddInterval)
+	cfg.YouTubeAddInterval = getEnvDuration("YOUTUBE_ADD_INTERVAL", cfg.YouTubeAddInterval)
+	cfg.GoogleClientID = getEnv("GOOGLE_CLIENT_ID", cfg.GoogleClientID)
+	cfg.GoogleClientSecret = getEnv("GOOGLE_CLIENT_SECRET", cfg.GoogleClientSecret)
+	cfg.GoogleRedirectURL = getEnv("GOOGLE_REDIRECT_URL", cfg.GoogleRedirectURL)
+
+	cfg.AdminAPIKey = getEnv("ADMIN_API_KEY", cfg.AdminAPIKey)
+
+	cfg.TokenEncryptionKey = getEnv("TOKEN_ENCRYPTION_KEY", cfg.TokenEncryptionKey)
+
+	cfg.HTTPClient.Timeout = getEnvDuration("HTTP_TIMEOUT", cfg.HTTPClient.Timeout)
+	cfg.HTTPClient.DialTimeout = getEnvDuration("HTTP_DIAL_TIMEOUT", cfg.HTTPClient.DialTimeout)
+	cfg.HTTPClient.IdleConnTimeout = getEnvDuration("HTTP_IDLE_CONN_TIMEOUT", cfg.HTTPClient.IdleConnTimeout)
+	cfg.HTTPClient.MaxIdleConnsPerHost = getEnvInt("HTTP_MAX_IDLE_CONNS_PER_HOST", cfg.HTTPClient.MaxIdleConnsPerHost)
+	cfg.HTTPClient.MaxConnsPerHost = getEnvInt("HTTP_MAX_CONNS_PER_HOST", cfg.HTTPClient.MaxConnsPerHost)
+	cfg.HTTPClient.MaxResponseBytes = getEnvInt("MAX_PROVIDER_RESPONSE_BYTES", cfg.HTTPClient.MaxResponseBytes)
+	cfg.HTTPClient.Proxy = getEnv("HTTP_CLIENT_PROXY", cfg.HTTPClient.Proxy)
+	cfg.HTTPClient.NoProxy = getEnv("HTTP_CLIENT_NO_PROXY", cfg.HTTPClient.NoProxy)
+	cfg.HTTPClient.CABundle = getEnv("HTTP_CA_BUNDLE", cfg.HTTPClient.CABundle)
+	cfg.HTTPClient.InsecureSkipVerify = getEnvBool("HTTP_INSECURE_SKIP_VERIFY", cfg.HTTPClient.InsecureSkipVerify)
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
 	}
 	return fallback
 }
+
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(getEnv(key, strconv.FormatBool(fallback)))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(getEnv(key, strconv.Itoa(fallback)))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, strconv.FormatFloat(fallback, 'f', -1, 64)), 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, fallback.String()))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvList splits a comma-separated variable into its trimmed, non-empty
+// entries, or returns fallback if the variable is not set.
+func getEnvList(key string, fallback []string) []string {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_FileWithEnvOverrides(t *testing.T) {
+	t.Setenv("CONFIG_FILE", writeConfig(t, "config.yaml", `
+server:
+  port: "9090"
+workers:
+  migration: 8
+timeouts:
+  search: 3s
+account_limits:
+  migrations_per_day: 20
+cache:
+  track_mappings: false
+  track_mapping_quorum: 5
+providers:
+  spotify:
+    client_id: file-id
+    client_secret: file-secret
+    add_interval: 0s
+  youtube:
+    add_interval: 1s
+  plugins: [./plugin-a]
+http_client:
+  timeout: 1m
+  ca_bundle: /etc/ssl/corp-ca.pem
+  providers:
+    youtube:
+      timeout: 2m
+      proxy: http://proxy.internal:3128
+`))
+	t.Setenv("MIGRATION_WORKERS", "12")
+	t.Setenv("HTTP_MAX_IDLE_CONNS_PER_HOST", "64")
+	t.Setenv("HTTP_CLIENT_NO_PROXY", "localhost,.corp.internal")
+	t.Setenv("YOUTUBE_ADD_INTERVAL", "250ms")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "9090", cfg.Port)
+	assert.Equal(t, 12, cfg.MigrationWorkers)
+	assert.Equal(t, 3*time.Second, cfg.SearchTimeout)
+	assert.Equal(t, 20, cfg.AccountMigrationsPerDay)
+	assert.False(t, cfg.TrackMappings)
+	assert.Equal(t, 5, cfg.TrackMappingQuorum)
+	assert.Equal(t, "file-id", cfg.SpotifyClientID)
+	assert.Zero(t, cfg.SpotifyAddInterval)
+	assert.Equal(t, 250*time.Millisecond, cfg.YouTubeAddInterval)
+	assert.Equal(t, []string{"./plugin-a"}, cfg.Plugins)
+	assert.Equal(t, time.Minute, cfg.HTTPClient.Timeout)
+	assert.Equal(t, 64, cfg.HTTPClient.MaxIdleConnsPerHost)
+	assert.Equal(t, "localhost,.corp.internal", cfg.HTTPClient.NoProxy)
+	assert.Equal(t, "/etc/ssl/corp-ca.pem", cfg.HTTPClient.CABundle)
+	assert.False(t, cfg.HTTPClient.InsecureSkipVerify)
+	assert.Equal(t, map[string]HTTPClient{
+		"youtube": {Timeout: 2 * time.Minute, Proxy: "http://proxy.internal:3128"},
+	}, cfg.ProviderHTTPClients)
+
+	// Settings the file leaves out keep their defaults.
+	assert.Equal(t, 2, cfg.JobWorkers)
+	assert.Equal(t, time.Minute, cfg.FetchTimeout)
+	assert.Equal(t, "memory", cfg.StorageDriver)
+}
+
+func TestLoad_JSONFile(t *testing.T) {
+	t.Setenv("CONFIG_FILE", writeConfig(t, "config.json",
+		`{"storage": {"driver": "sqlite", "sqlite_path": "/data/db", "postgres_dsn": "postgres://db/mm"}}`))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "sqlite", cfg.StorageDriver)
+	assert.Equal(t, "/data/db", cfg.SQLitePath)
+	assert.Equal(t, "postgres://db/mm", cfg.PostgresDSN)
+}
+
+func TestLoad_InvalidFile(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown key":  "server:\n  prot: 8080\n",
+		"wrong type":   "workers:\n  migration: many\n",
+		"bad duration": "timeouts:\n  search: soon\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("CONFIG_FILE", writeConfig(t, "config.yaml", content))
+			_, err := Load()
+			assert.Error(t, err)
+		})
+	}
+
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	_, err := Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_ExampleFile(t *testing.T) {
+	t.Setenv("CONFIG_FILE", filepath.Join("..", "..", "config.example.yaml"))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Plugins)
+	assert.Empty(t, cfg.EnabledProviders)
+	assert.Empty(t, cfg.ProviderHTTPClients)
+	assert.Empty(t, cfg.MetadataSources)
+	cfg.Plugins, cfg.EnabledProviders, cfg.ProviderHTTPClients, cfg.MetadataSources = nil, nil, nil, nil
+	assert.Equal(t, *defaults(), *cfg)
+}
+
+func TestLoad_IncompleteCredentials(t *testing.T) {
+	t.Setenv("GOOGLE_CLIENT_ID", "client-id")
+	_, err := Load()
+	assert.ErrorContains(t, err, "GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET must be set together")
+
+	t.Setenv("GOOGLE_CLIENT_SECRET", "client-secret")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "client-id", cfg.GoogleClientID)
+}
+
+func TestLoad_RedirectURL(t *testing.T) {
+	t.Setenv("SPOTIFY_REDIRECT_URL", "https://api.example.com/api/v1/oauth/spotify/callback")
+	_, err := Load()
+	assert.ErrorContains(t, err, "SPOTIFY_REDIRECT_URL requires SPOTIFY_CLIENT_ID")
+
+	t.Setenv("SPOTIFY_CLIENT_ID", "client-id")
+	t.Setenv("SPOTIFY_CLIENT_SECRET", "client-secret")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/api/v1/oauth/spotify/callback", cfg.SpotifyRedirectURL)
+
+	t.Setenv("SPOTIFY_REDIRECT_URL", "/api/v1/oauth/spotify/callback")
+	_, err = Load()
+	assert.ErrorContains(t, err, "SPOTIFY_REDIRECT_URL must be an absolute http or https URL")
+}
+
+func TestLoad_YouTubeBlocklist(t *testing.T) {
+	t.Setenv("YOUTUBE_BLOCKLIST", "Nightcore, Karaoke")
+	t.Setenv("YOUTUBE_BLOCKLIST_MODE", "reject")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Nightcore", "Karaoke"}, cfg.YouTubeBlocklist)
+	assert.Equal(t, "reject", cfg.YouTubeBlocklistMode)
+
+	t.Setenv("YOUTUBE_BLOCKLIST_MODE", "drop")
+	_, err = Load()
+	assert.ErrorContains(t, err, "YOUTUBE_BLOCKLIST_MODE must be penalize, reject or off")
+}
+
+func TestLoad_SMTP(t *testing.T) {
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	_, err := Load()
+	assert.ErrorContains(t, err, "SMTP_FROM must be set when SMTP_HOST is")
+
+	t.Setenv("SMTP_FROM", "migrations@example.com")
+	_, err = Load()
+	assert.ErrorContains(t, err, "SMTP_ALLOWED_DOMAINS must be set when SMTP_HOST is")
+
+	t.Setenv("SMTP_ALLOWED_DOMAINS", "example.com, example.org")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 587, cfg.SMTPPort)
+	assert.Equal(t, "migrations@example.com", cfg.SMTPFrom)
+	assert.Equal(t, []string{"example.com", "example.org"}, cfg.SMTPAllowedDomains)
+}
+
+func TestLoad_MetadataSources(t *testing.T) {
+	t.Setenv("METADATA_SOURCES", "musicbrainz, Deezer")
+	t.Setenv("METADATA_TIMEOUT", "2s")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"musicbrainz", "Deezer"}, cfg.MetadataSources)
+	assert.Equal(t, 2*time.Second, cfg.MetadataTimeout)
+
+	t.Setenv("METADATA_SOURCES", "musicbrainz,discogs")
+	_, err = Load()
+	assert.ErrorContains(t, err, `not "discogs"`)
+}
+
+func TestLoad_Fingerprinting(t *testing.T) {
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.AcoustIDAPIKey)
+	assert.Equal(t, "fpcalc", cfg.FpcalcPath)
+
+	t.Setenv("ACOUSTID_API_KEY", "key")
+	t.Setenv("FPCALC_PATH", "/usr/local/bin/fpcalc")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "key", cfg.AcoustIDAPIKey)
+	assert.Equal(t, "/usr/local/bin/fpcalc", cfg.FpcalcPath)
+}
+
+func TestConfig_ProviderEnabled(t *testing.T) {
+	cfg := defaults()
+	assert.True(t, cfg.ProviderEnabled("youtube"))
+
+	t.Setenv("ENABLED_PROVIDERS", "spotify, M3U")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.ProviderEnabled("spotify"))
+	assert.True(t, cfg.ProviderEnabled("m3u"))
+	assert.False(t, cfg.ProviderEnabled("youtube"))
+}
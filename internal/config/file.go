@@ -0,0 +1,273 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the layout of a configuration file. Every setting is
+// optional; settings left out keep their defaults. JSON files use the same
+// keys, since JSON is valid YAML.
+type fileConfig struct {
+	Server struct {
+		Port                 *string `yaml:"port"`
+		LogLevel             *string `yaml:"log_level"`
+		AuthEnabled          *bool   `yaml:"auth_enabled"`
+		AdminAPIKey          *string `yaml:"admin_api_key"`
+		TokenEncryptionKey   *string `yaml:"token_encryption_key"`
+		GzipResponses        *bool   `yaml:"gzip_responses"`
+		HealthCheckProviders *bool   `yaml:"health_check_providers"`
+		PublicURL            *string `yaml:"public_url"`
+	} `yaml:"server"`
+
+	Workers struct {
+		Migration *int `yaml:"migration"`
+		Jobs      *int `yaml:"jobs"`
+	} `yaml:"workers"`
+
+	Timeouts struct {
+		Search    *time.Duration `yaml:"search"`
+		Fetch     *time.Duration `yaml:"fetch"`
+		Create    *time.Duration `yaml:"create"`
+		Add       *time.Duration `yaml:"add"`
+		Migration *time.Duration `yaml:"migration"`
+	} `yaml:"timeouts"`
+
+	RateLimit struct {
+		RPS   *float64 `yaml:"rps"`
+		Burst *int     `yaml:"burst"`
+	} `yaml:"rate_limit"`
+
+	AccountLimits struct {
+		MigrationsPerDay *int `yaml:"migrations_per_day"`
+		MaxTracks        *int `yaml:"max_tracks"`
+		ConcurrentJobs   *int `yaml:"concurrent_jobs"`
+	} `yaml:"account_limits"`
+
+	Storage struct {
+		Driver      *string `yaml:"driver"`
+		SQLitePath  *string `yaml:"sqlite_path"`
+		PostgresDSN *string `yaml:"postgres_dsn"`
+	} `yaml:"storage"`
+
+	Cache struct {
+		TrackMappings      *bool `yaml:"track_mappings"`
+		TrackMappingQuorum *int  `yaml:"track_mapping_quorum"`
+	} `yaml:"cache"`
+
+	Providers struct {
+		Spotify struct {
+			ClientID        *string `yaml:"client_id"`
+			ClientSecret    *string `yaml:"client_secret"`
+			RedirectURL     *string `yaml:"redirect_url"`
+			PageConcurrency *int    `yaml:"page_concurrency"`
+
+			AddInterval *time.Duration `yaml:"add_interval"`
+		} `yaml:"spotify"`
+		YouTube struct {
+			ClientID       *string `yaml:"client_id"`
+			ClientSecret   *string `yaml:"client_secret"`
+			RedirectURL    *string `yaml:"redirect_url"`
+			DailyQuota     *int    `yaml:"daily_quota"`
+			QuotaEnforce   *bool   `yaml:"quota_enforce"`
+			TitleRulesFile *string `yaml:"title_rules_file"`
+
+			AddInterval *time.Duration `yaml:"add_interval"`
+
+			SearchCacheTTL       *time.Duration `yaml:"search_cache_ttl"`
+			VerifyCachedSearches *bool          `yaml:"verify_cached_searches"`
+
+			Blocklist     []string `yaml:"blocklist"`
+			BlocklistMode *string  `yaml:"blocklist_mode"`
+		} `yaml:"youtube"`
+		LastFM struct {
+			APIKey *string `yaml:"api_key"`
+		} `yaml:"lastfm"`
+		LocalFiles struct {
+			Dir *string `yaml:"dir"`
+		} `yaml:"localfiles"`
+		M3U struct {
+			Dir *string `yaml:"dir"`
+		} `yaml:"m3u"`
+		Sandbox struct {
+			Enabled     *bool    `yaml:"enabled"`
+			FailureRate *float64 `yaml:"failure_rate"`
+		} `yaml:"sandbox"`
+		Plugins []string `yaml:"plugins"`
+		Enabled []string `yaml:"enabled"`
+	} `yaml:"providers"`
+
+	Metadata struct {
+		Sources  []string       `yaml:"sources"`
+		Timeout  *time.Duration `yaml:"timeout"`
+		CacheTTL *time.Duration `yaml:"cache_ttl"`
+	} `yaml:"metadata"`
+
+	Fingerprinting struct {
+		AcoustIDAPIKey *string `yaml:"acoustid_api_key"`
+		FpcalcPath     *string `yaml:"fpcalc_path"`
+	} `yaml:"fingerprinting"`
+
+	HTTPClient struct {
+		Timeout             *time.Duration        `yaml:"timeout"`
+		DialTimeout         *time.Duration        `yaml:"dial_timeout"`
+		IdleConnTimeout     *time.Duration        `yaml:"idle_conn_timeout"`
+		MaxIdleConnsPerHost *int                  `yaml:"max_idle_conns_per_host"`
+		MaxConnsPerHost     *int                  `yaml:"max_conns_per_host"`
+		MaxResponseBytes    *int                  `yaml:"max_response_bytes"`
+		Proxy               *string               `yaml:"proxy"`
+		NoProxy             *string               `yaml:"no_proxy"`
+		CABundle            *string               `yaml:"ca_bundle"`
+		InsecureSkipVerify  *bool                 `yaml:"insecure_skip_verify"`
+		Providers           map[string]HTTPClient `yaml:"providers"`
+	} `yaml:"http_client"`
+
+	Hooks struct {
+		WebhookURL        *string `yaml:"webhook_url"`
+		WebhookSecret     *string `yaml:"webhook_secret"`
+		NotifyURL         *string `yaml:"notify_url"`
+		SlackURL          *string `yaml:"slack_url"`
+		DiscordURL        *string `yaml:"discord_url"`
+		ListenBrainzToken *string `yaml:"listenbrainz_token"`
+	} `yaml:"hooks"`
+
+	SMTP struct {
+		Host     *string `yaml:"host"`
+		Port     *int    `yaml:"port"`
+		Username *string `yaml:"username"`
+		Password *string `yaml:"password"`
+		From     *string `yaml:"from"`
+
+		AllowedDomains []string `yaml:"allowed_domains"`
+	} `yaml:"smtp"`
+}
+
+// loadFile applies the settings of the YAML or JSON file at path to cfg.
+// Unknown keys are rejected so that typos do not go unnoticed.
+func (cfg *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	var f fileConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config: parse %s: %w", path, err)
+	}
+
+	set(&cfg.Port, f.Server.Port)
+	set(&cfg.LogLevel, f.Server.LogLevel)
+	set(&cfg.AuthEnabled, f.Server.AuthEnabled)
+	set(&cfg.AdminAPIKey, f.Server.AdminAPIKey)
+	set(&cfg.TokenEncryptionKey, f.Server.TokenEncryptionKey)
+	set(&cfg.GzipResponses, f.Server.GzipResponses)
+	set(&cfg.HealthCheckProviders, f.Server.HealthCheckProviders)
+	set(&cfg.PublicURL, f.Server.PublicURL)
+
+	set(&cfg.MigrationWorkers, f.Workers.Migration)
+	set(&cfg.JobWorkers, f.Workers.Jobs)
+
+	set(&cfg.SearchTimeout, f.Timeouts.Search)
+	set(&cfg.FetchTimeout, f.Timeouts.Fetch)
+	set(&cfg.CreateTimeout, f.Timeouts.Create)
+	set(&cfg.AddTimeout, f.Timeouts.Add)
+	set(&cfg.MigrationTimeout, f.Timeouts.Migration)
+
+	set(&cfg.RateLimitRPS, f.RateLimit.RPS)
+	set(&cfg.RateLimitBurst, f.RateLimit.Burst)
+
+	set(&cfg.AccountMigrationsPerDay, f.AccountLimits.MigrationsPerDay)
+	set(&cfg.AccountMaxTracks, f.AccountLimits.MaxTracks)
+	set(&cfg.AccountConcurrentJobs, f.AccountLimits.ConcurrentJobs)
+
+	set(&cfg.StorageDriver, f.Storage.Driver)
+	set(&cfg.SQLitePath, f.Storage.SQLitePath)
+	set(&cfg.PostgresDSN, f.Storage.PostgresDSN)
+
+	set(&cfg.TrackMappings, f.Cache.TrackMappings)
+	set(&cfg.TrackMappingQuorum, f.Cache.TrackMappingQuorum)
+
+	set(&cfg.SpotifyClientID, f.Providers.Spotify.ClientID)
+	set(&cfg.SpotifyClientSecret, f.Providers.Spotify.ClientSecret)
+	set(&cfg.SpotifyRedirectURL, f.Providers.Spotify.RedirectURL)
+	set(&cfg.SpotifyPageConcurrency, f.Providers.Spotify.PageConcurrency)
+	set(&cfg.SpotifyAddInterval, f.Providers.Spotify.AddInterval)
+	set(&cfg.GoogleClientID, f.Providers.YouTube.ClientID)
+	set(&cfg.GoogleClientSecret, f.Providers.YouTube.ClientSecret)
+	set(&cfg.GoogleRedirectURL, f.Providers.YouTube.RedirectURL)
+	set(&cfg.YouTubeDailyQuota, f.Providers.YouTube.DailyQuota)
+	set(&cfg.QuotaEnforce, f.Providers.YouTube.QuotaEnforce)
+	set(&cfg.YouTubeAddInterval, f.Providers.YouTube.AddInterval)
+	set(&cfg.TitleRulesFile, f.Providers.YouTube.TitleRulesFile)
+	set(&cfg.YouTubeSearchCacheTTL, f.Providers.YouTube.SearchCacheTTL)
+	set(&cfg.YouTubeVerifyCachedSearches, f.Providers.YouTube.VerifyCachedSearches)
+	if f.Providers.YouTube.Blocklist != nil {
+		cfg.YouTubeBlocklist = f.Providers.YouTube.Blocklist
+	}
+	set(&cfg.YouTubeBlocklistMode, f.Providers.YouTube.BlocklistMode)
+	set(&cfg.LastFMAPIKey, f.Providers.LastFM.APIKey)
+	set(&cfg.LocalLibraryDir, f.Providers.LocalFiles.Dir)
+	set(&cfg.M3UDir, f.Providers.M3U.Dir)
+	set(&cfg.SandboxProvider, f.Providers.Sandbox.Enabled)
+	set(&cfg.SandboxFailureRate, f.Providers.Sandbox.FailureRate)
+	if f.Providers.Plugins != nil {
+		cfg.Plugins = f.Providers.Plugins
+	}
+	if f.Providers.Enabled != nil {
+		cfg.EnabledProviders = f.Providers.Enabled
+	}
+
+	if f.Metadata.Sources != nil {
+		cfg.MetadataSources = f.Metadata.Sources
+	}
+	set(&cfg.MetadataTimeout, f.Metadata.Timeout)
+	set(&cfg.MetadataCacheTTL, f.Metadata.CacheTTL)
+	set(&cfg.AcoustIDAPIKey, f.Fingerprinting.AcoustIDAPIKey)
+	set(&cfg.FpcalcPath, f.Fingerprinting.FpcalcPath)
+
+	set(&cfg.HookWebhookURL, f.Hooks.WebhookURL)
+	set(&cfg.HookWebhookSecret, f.Hooks.WebhookSecret)
+	set(&cfg.HookNotifyURL, f.Hooks.NotifyURL)
+	set(&cfg.HookSlackURL, f.Hooks.SlackURL)
+	set(&cfg.HookDiscordURL, f.Hooks.DiscordURL)
+	set(&cfg.HookListenBrainzToken, f.Hooks.ListenBrainzToken)
+
+	set(&cfg.SMTPHost, f.SMTP.Host)
+	set(&cfg.SMTPPort, f.SMTP.Port)
+	set(&cfg.SMTPUsername, f.SMTP.Username)
+	set(&cfg.SMTPPassword, f.SMTP.Password)
+	set(&cfg.SMTPFrom, f.SMTP.From)
+	if f.SMTP.AllowedDomains != nil {
+		cfg.SMTPAllowedDomains = f.SMTP.AllowedDomains
+	}
+
+	set(&cfg.HTTPClient.Timeout, f.HTTPClient.Timeout)
+	set(&cfg.HTTPClient.DialTimeout, f.HTTPClient.DialTimeout)
+	set(&cfg.HTTPClient.IdleConnTimeout, f.HTTPClient.IdleConnTimeout)
+	set(&cfg.HTTPClient.MaxIdleConnsPerHost, f.HTTPClient.MaxIdleConnsPerHost)
+	set(&cfg.HTTPClient.MaxConnsPerHost, f.HTTPClient.MaxConnsPerHost)
+	set(&cfg.HTTPClient.MaxResponseBytes, f.HTTPClient.MaxResponseBytes)
+	set(&cfg.HTTPClient.Proxy, f.HTTPClient.Proxy)
+	set(&cfg.HTTPClient.NoProxy, f.HTTPClient.NoProxy)
+	set(&cfg.HTTPClient.CABundle, f.HTTPClient.CABundle)
+	set(&cfg.HTTPClient.InsecureSkipVerify, f.HTTPClient.InsecureSkipVerify)
+	if f.HTTPClient.Providers != nil {
+		cfg.ProviderHTTPClients = f.HTTPClient.Providers
+	}
+	return nil
+}
+
+// set overwrites *dst with *value if the file sets it.
+func set[T any](dst *T, value *T) {
+	if value != nil {
+		*dst = *value
+	}
+}
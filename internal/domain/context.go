@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+type accountKey struct{}
+
+// ContextWithAccount returns a copy of ctx carrying the authenticated account.
+func ContextWithAccount(ctx context.Context, account *Account) context.Context {
+	return context.WithValue(ctx, accountKey{}, account)
+}
+
+// AccountFromContext returns the authenticated account stored in ctx, if any.
+func AccountFromContext(ctx context.Context) (*Account, bool) {
+	account, ok := ctx.Value(accountKey{}).(*Account)
+	return account, ok && account != nil
+}
+
+// AccountIDFromContext returns the ID of the authenticated account stored in
+// ctx, or an empty string when the request is unauthenticated.
+func AccountIDFromContext(ctx context.Context) string {
+	if account, ok := AccountFromContext(ctx); ok {
+		return account.ID
+	}
+	return ""
+}
+
+type marketKey struct{}
+
+// ContextWithMarket returns a copy of ctx carrying the market (ISO 3166-1
+// alpha-2 country code) providers should search in.
+func ContextWithMarket(ctx context.Context, market string) context.Context {
+	return context.WithValue(ctx, marketKey{}, market)
+}
+
+// MarketFromContext returns the market stored in ctx, or an empty string
+// when none was requested.
+func MarketFromContext(ctx context.Context) string {
+	market, _ := ctx.Value(marketKey{}).(string)
+	return market
+}
+
+type matchOptionsKey struct{}
+
+// MatchOptions tune how providers score search results for a migration.
+type MatchOptions struct {
+	// Classical compares classical works structurally (composer, work,
+	// movement) and weights performers less.
+	Classical bool
+
+	// StrictVersions rejects candidates whose version qualifiers (live,
+	// remix, acoustic, cover) differ from the source track instead of only
+	// penalizing them.
+	StrictVersions bool
+
+	// Strategy selects the scorer configuration and the minimum confidence
+	// of an accepted match.
+	Strategy MatchingStrategy
+
+	// MinScore raises the confidence of an accepted match above the
+	// strategy's minimum.
+	MinScore float64
+
+	// Rerecordings picks between the original recording of a track and a
+	// re-recording of it.
+	Rerecordings RerecordingPreference
+}
+
+// Threshold returns the confidence an accepted match needs: the minimum
+// of the strategy or MinScore, whichever is higher.
+func (o MatchOptions) Threshold() float64 {
+	return max(o.Strategy.MinScore(), o.MinScore)
+}
+
+// ContextWithMatchOptions returns a copy of ctx carrying match options.
+func ContextWithMatchOptions(ctx context.Context, opts MatchOptions) context.Context {
+	return context.WithValue(ctx, matchOptionsKey{}, opts)
+}
+
+// MatchOptionsFromContext returns the match options stored in ctx, or the
+// zero value when none were set.
+func MatchOptionsFromContext(ctx context.Context) MatchOptions {
+	opts, _ := ctx.Value(matchOptionsKey{}).(MatchOptions)
+	return opts
+}
+
+type cacheReportKey struct{}
+
+// ContextWithCacheReport returns a copy of ctx in which a provider can
+// report, with ReportCacheHit, that it answered a call from its cache
+// without spending API quota, and a function that tells whether it did.
+func ContextWithCacheReport(ctx context.Context) (context.Context, func() bool) {
+	hit := new(atomic.Bool)
+	return context.WithValue(ctx, cacheReportKey{}, hit), hit.Load
+}
+
+// ReportCacheHit records that the call ctx was passed to was answered from
+// a cache. It does nothing unless ctx comes from ContextWithCacheReport.
+func ReportCacheHit(ctx context.Context) {
+	if hit, ok := ctx.Value(cacheReportKey{}).(*atomic.Bool); ok {
+		hit.Store(true)
+	}
+}
+
+// maxRunnerUps bounds the runner-up candidates reported for a search.
+const maxRunnerUps = 3
+
+type candidateReportKey struct{}
+
+// ContextWithCandidateReport returns a copy of ctx in which a provider can
+// report, with ReportRunnerUps, the candidates a search ranked below its
+// match, and a function that returns them.
+func ContextWithCandidateReport(ctx context.Context) (context.Context, func() []TrackCandidate) {
+	report := new(atomic.Pointer[[]TrackCandidate])
+	return context.WithValue(ctx, candidateReportKey{}, report), func() []TrackCandidate {
+		if runnerUps := report.Load(); runnerUps != nil {
+			return *runnerUps
+		}
+		return nil
+	}
+}
+
+// ReportRunnerUps records the candidates, ranked best first, that the
+// search ctx was passed to did not pick, keeping the first maxRunnerUps. It
+// does nothing unless ctx comes from ContextWithCandidateReport.
+func ReportRunnerUps(ctx context.Context, candidates []TrackCandidate) {
+	report, ok := ctx.Value(candidateReportKey{}).(*atomic.Pointer[[]TrackCandidate])
+	if !ok || len(candidates) == 0 {
+		return
+	}
+	runnerUps := slices.Clone(candidates[:min(len(candidates), maxRunnerUps)])
+	report.Store(&runnerUps)
+}
+
+// maxDebugExchanges bounds the exchanges a DebugCapture keeps, so a large
+// playlist cannot blow up the job record.
+const maxDebugExchanges = 200
+
+type debugCaptureKey struct{}
+
+// DebugCapture collects the provider HTTP exchanges of a job in debug mode.
+// It is safe for concurrent use.
+type DebugCapture struct {
+	mu  sync.Mutex
+	log DebugLog
+}
+
+// ContextWithDebugCapture returns a copy of ctx in which provider HTTP
+// exchanges are recorded, with RecordProviderExchange, into the returned
+// capture.
+func ContextWithDebugCapture(ctx context.Context) (context.Context, *DebugCapture) {
+	capture := &DebugCapture{}
+	return context.WithValue(ctx, debugCaptureKey{}, capture), capture
+}
+
+// DebugCaptureEnabled reports whether exchanges made with ctx are recorded.
+func DebugCaptureEnabled(ctx context.Context) bool {
+	_, ok := ctx.Value(debugCaptureKey{}).(*DebugCapture)
+	return ok
+}
+
+// RecordProviderExchange records an exchange made with ctx. It does nothing
+// unless ctx comes from ContextWithDebugCapture.
+func RecordProviderExchange(ctx context.Context, exchange ProviderExchange) {
+	capture, ok := ctx.Value(debugCaptureKey{}).(*DebugCapture)
+	if !ok {
+		return
+	}
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	if len(capture.log.Exchanges) >= maxDebugExchanges {
+		capture.log.Dropped++
+		return
+	}
+	capture.log.Exchanges = append(capture.log.Exchanges, exchange)
+}
+
+// Log returns the exchanges recorded so far.
+func (c *DebugCapture) Log() *DebugLog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	log := c.log
+	log.Exchanges = append([]ProviderExchange{}, c.log.Exchanges...)
+	return &log
+}
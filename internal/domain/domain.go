@@ -1,12 +1,467 @@
 package domain
 
-// Track represents a music track with metadata used for cross-platform matching.
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMigrationNotFound is returned when a stored migration cannot be located.
+	ErrMigrationNotFound = errors.New("migration not found")
+
+	// ErrAlreadyRolledBack is returned when a migration whose playlist was
+	// deleted by a rollback is rolled back, retried or reversed.
+	ErrAlreadyRolledBack = errors.New("migration has been rolled back")
+
+	// ErrPlaylistNotFound is returned when a provider has no playlist with the requested ID.
+	ErrPlaylistNotFound = errors.New("playlist not found")
+
+	// ErrAccountNotFound is returned when no account matches the given ID or API key.
+	ErrAccountNotFound = errors.New("account not found")
+
+	// ErrInvalidAccountName is returned when an account is registered with
+	// a blank name.
+	ErrInvalidAccountName = errors.New("account name is required")
+
+	// ErrUnavailableInMarket is returned when a track exists on a provider but
+	// cannot be played or added in the requested market.
+	ErrUnavailableInMarket = errors.New("track unavailable in market")
+
+	// ErrUnavailable is returned when a track exists on a provider but
+	// cannot be played in any market, such as a greyed-out track whose
+	// rights have lapsed.
+	ErrUnavailable = errors.New("track unavailable")
+
+	// ErrRateLimited is matched by provider errors caused by rate limiting
+	// (HTTP 429 or a provider's quota error), so callers can back off.
+	ErrRateLimited = errors.New("provider rate limit exceeded")
+
+	// ErrInvalidToken is matched by provider errors caused by a missing,
+	// expired or revoked access token (HTTP 401).
+	ErrInvalidToken = errors.New("provider rejected the access token")
+
+	// ErrInsufficientScope is matched by provider errors caused by a token
+	// that lacks a permission the request needs (HTTP 403).
+	ErrInsufficientScope = errors.New("provider token lacks a required scope")
+
+	// ErrCollaborativeUnsupported is returned when a playlist is made
+	// collaborative on a provider without collaborative playlists.
+	ErrCollaborativeUnsupported = errors.New("provider does not support collaborative playlists")
+
+	// ErrQuotaExceeded is returned when a migration would exceed a provider's
+	// daily API quota budget.
+	ErrQuotaExceeded = errors.New("provider quota budget exceeded")
+
+	// ErrLimitExceeded is matched by errors reporting that a request would
+	// exceed a limit of the caller's account; see LimitError.
+	ErrLimitExceeded = errors.New("account limit exceeded")
+
+	// ErrTokenNotFound is returned when an account has no stored token for a provider.
+	ErrTokenNotFound = errors.New("provider token not found")
+
+	// ErrMissingToken is returned when a request carries no provider token
+	// and none is stored in the token vault for the caller's account.
+	ErrMissingToken = errors.New("provider token missing")
+
+	// ErrMigrationInProgress is returned when a migration with the same
+	// idempotency key is still running.
+	ErrMigrationInProgress = errors.New("migration with this idempotency key is in progress")
+
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent
+	// again with a different migration request.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different migration")
+
+	// ErrInvalidCursor is returned when a page is requested with a cursor the
+	// provider did not issue.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrProviderNotFound is returned when no provider is registered under a name.
+	ErrProviderNotFound = errors.New("unknown provider")
+
+	// ErrProviderDisabled is returned when a registered provider has been
+	// disabled at runtime by an administrator.
+	ErrProviderDisabled = errors.New("provider temporarily disabled")
+
+	// ErrMappingNotFound is returned when no cross-provider mapping is known
+	// for a track.
+	ErrMappingNotFound = errors.New("track mapping not found")
+
+	// ErrInvalidFeedback is returned when match feedback does not identify
+	// both tracks of the match.
+	ErrInvalidFeedback = errors.New("invalid match feedback")
+
+	// ErrCacheMiss is returned when a cache holds no live entry for a key.
+	ErrCacheMiss = errors.New("cache miss")
+
+	// ErrSourceOnlyProvider is returned when a provider that can only be
+	// migrated from, such as a local file library, is asked to search or
+	// write.
+	ErrSourceOnlyProvider = errors.New("provider can only be used as a migration source")
+
+	// ErrInvalidPlaylistFile is returned when an uploaded playlist file cannot
+	// be parsed or has no entries.
+	ErrInvalidPlaylistFile = errors.New("invalid playlist file")
+
+	// ErrPlaylistLocked is returned when the same playlist is already being
+	// migrated to the same destination provider, possibly by another API
+	// instance.
+	ErrPlaylistLocked = errors.New("a migration of this playlist to this provider is already running")
+
+	// ErrPlaylistExists is returned when a migration with the "skip"
+	// conflict policy finds its destination playlist already exists.
+	ErrPlaylistExists = errors.New("destination playlist already exists")
+
+	// ErrProfileNotFound is returned when a migration profile does not exist
+	// or belongs to another account.
+	ErrProfileNotFound = errors.New("migration profile not found")
+
+	// ErrInvalidProfile is returned when a migration profile has settings
+	// a migration would reject.
+	ErrInvalidProfile = errors.New("invalid migration profile")
+
+	// ErrRevocationFailed is returned when a provider fails to revoke a
+	// stored token.
+	ErrRevocationFailed = errors.New("provider failed to revoke the token")
+
+	// ErrAuthorizationUnavailable is returned when linking a provider
+	// through OAuth, but the provider does not support it or has no
+	// redirect URL configured.
+	ErrAuthorizationUnavailable = errors.New("provider cannot be linked through OAuth")
+
+	// ErrInvalidAuthorizationState is returned when an OAuth callback
+	// carries a state that was not issued for the provider, was tampered
+	// with or expired.
+	ErrInvalidAuthorizationState = errors.New("invalid or expired authorization state")
+
+	// ErrAuthorizationFailed is returned when a provider rejects the
+	// authorization code of an OAuth callback, or the user denied access.
+	ErrAuthorizationFailed = errors.New("provider authorization failed")
+
+	// ErrJobNotFound is returned when a queued migration job does not exist.
+	ErrJobNotFound = errors.New("job not found")
+
+	// ErrJobFinished is returned when canceling a job that already succeeded,
+	// failed or was canceled.
+	ErrJobFinished = errors.New("job already finished")
+
+	// ErrJobLeaseLost is returned when a worker updates a job whose lease
+	// expired and was taken over by another worker.
+	ErrJobLeaseLost = errors.New("job lease lost")
+
+	// ErrJobExists is returned by a job queue when the account already has
+	// a job with the same idempotency key.
+	ErrJobExists = errors.New("job with this idempotency key already exists")
+
+	// ErrNoDebugLog is returned when reading the debug log of a job that
+	// was not queued in debug mode.
+	ErrNoDebugLog = errors.New("job has no debug log")
+
+	// ErrInvalidFilter is returned when a migration's track filter cannot be
+	// applied, e.g. because a title pattern is not a valid regular expression.
+	ErrInvalidFilter = errors.New("invalid track filter")
+
+	// ErrTimeout is matched by StageTimeoutError.
+	ErrTimeout = errors.New("timed out")
+
+	// ErrNoAudio is returned when a provider has no audio of a track to
+	// fingerprint, e.g. a Spotify track without a preview.
+	ErrNoAudio = errors.New("no audio available for track")
+
+	// ErrMigrationsNotComparable is returned when comparing migrations of
+	// different source playlists or to different destination providers.
+	ErrMigrationsNotComparable = errors.New("migrations are not of the same playlist")
+
+	// ErrGoldenMatchNotFound is returned when a golden dataset entry does
+	// not exist.
+	ErrGoldenMatchNotFound = errors.New("golden match not found")
+
+	// ErrInvalidGoldenMatch is returned when a golden dataset entry does not
+	// identify its tracks.
+	ErrInvalidGoldenMatch = errors.New("invalid golden match")
+)
+
+// Stage identifies a step of a migration that runs under its own timeout.
+type Stage string
+
+const (
+	StageFetch     Stage = "fetch"
+	StageSearch    Stage = "search"
+	StageCreate    Stage = "create"
+	StageAdd       Stage = "add"
+	StageMigration Stage = "migration"
+)
+
+// StageTimeoutError reports that a migration stage did not finish within its
+// timeout. StageMigration means the overall migration deadline expired.
+type StageTimeoutError struct {
+	Stage   Stage
+	Timeout time.Duration
+}
+
+func (e *StageTimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s", e.Stage, e.Timeout)
+}
+
+// Is reports StageTimeoutError as ErrTimeout.
+func (e *StageTimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// MissingScopeError reports that a provider token was not granted a scope
+// a migration needs. It matches ErrInsufficientScope.
+type MissingScopeError struct {
+	Provider string
+	Scope    string
+}
+
+func (e *MissingScopeError) Error() string {
+	return fmt.Sprintf("%s token is missing required scope %s", e.Provider, e.Scope)
+}
+
+// Is reports MissingScopeError as ErrInsufficientScope.
+func (e *MissingScopeError) Is(target error) bool {
+	return target == ErrInsufficientScope
+}
+
+// LimitError reports that a request would exceed a limit of the caller's
+// account. ResetsAt is when the limit next allows the request, if that is
+// known: the end of the day for the daily migration limit. It matches
+// ErrLimitExceeded.
+type LimitError struct {
+	Limit    string
+	Max      int
+	ResetsAt time.Time
+}
+
+func (e *LimitError) Error() string {
+	switch e.Limit {
+	case LimitMigrationsPerDay:
+		return fmt.Sprintf("account limit of %d migrations per day reached; it resets at %s",
+			e.Max, e.ResetsAt.Format(time.RFC3339))
+	case LimitMaxTracksPerMigration:
+		return fmt.Sprintf("playlist exceeds the account limit of %d tracks per migration", e.Max)
+	case LimitConcurrentJobs:
+		return fmt.Sprintf("account limit of %d queued or running jobs reached; it frees up when one of them finishes", e.Max)
+	}
+	return fmt.Sprintf("account limit %s of %d reached", e.Limit, e.Max)
+}
+
+// Is reports LimitError as ErrLimitExceeded.
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// Names of account limits, as reported by LimitError.
+const (
+	LimitMigrationsPerDay      = "migrations_per_day"
+	LimitMaxTracksPerMigration = "max_tracks_per_migration"
+	LimitConcurrentJobs        = "concurrent_jobs"
+)
+
+// AccountLimits caps what an account can do. Zero means unlimited.
+// MigrationsPerDay counts the account's stored migrations, other than dry
+// runs, since midnight UTC; ConcurrentJobs counts its queued and running
+// jobs.
+type AccountLimits struct {
+	MigrationsPerDay      int `json:"migrations_per_day" binding:"min=0"`
+	MaxTracksPerMigration int `json:"max_tracks_per_migration" binding:"min=0"`
+	ConcurrentJobs        int `json:"concurrent_jobs" binding:"min=0"`
+}
+
+// Account represents an API consumer. Migrations and stored provider tokens
+// are scoped to the account that created them.
+type Account struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+	APIKeyHash string    `json:"-"`
+
+	// Limits are set by administrators for this account; nil means the
+	// configured defaults apply.
+	Limits *AccountLimits `json:"limits,omitempty"`
+}
+
+// RegisterAccountRequest contains the information needed to create an account.
+type RegisterAccountRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// RegisterAccountResponse is returned once on registration. The API key is
+// not stored in plain text and cannot be retrieved again.
+type RegisterAccountResponse struct {
+	Account Account `json:"account"`
+	APIKey  string  `json:"api_key"`
+}
+
+// ItemType distinguishes music tracks from podcast episodes in a playlist.
+type ItemType string
+
+const (
+	ItemTypeTrack   ItemType = "track"
+	ItemTypeEpisode ItemType = "episode"
+)
+
+// Show is the podcast a playlist episode belongs to.
+type Show struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Publisher string `json:"publisher,omitempty"`
+}
+
+// Track represents a playlist item with metadata used for cross-platform
+// matching. Most items are music tracks; podcast episodes have Type set to
+// ItemTypeEpisode and carry their Show instead of artists.
 type Track struct {
-	Name       string `json:"name"`
-	Artist     string `json:"artist"`
-	Album      string `json:"album"`
-	ISRC       string `json:"isrc,omitempty"`
-	ExternalID string `json:"external_id,omitempty"`
+	Name       string   `json:"name"`
+	Artists    []string `json:"artists"`
+	Album      string   `json:"album"`
+	ISRC       string   `json:"isrc,omitempty"`
+	ExternalID string   `json:"external_id,omitempty"`
+	Type       ItemType `json:"type,omitempty"`
+	Show       *Show    `json:"show,omitempty"`
+
+	// DurationMS is the length of the track, or 0 if the provider does not
+	// report it.
+	DurationMS int `json:"duration_ms,omitempty"`
+
+	// ReleaseDate is the release date of the track's album as the provider
+	// reports it: YYYY, YYYY-MM or YYYY-MM-DD. Popularity is the provider's
+	// rating of how much the track is played, from 0 to 100. Providers leave
+	// them empty when unavailable.
+	ReleaseDate string `json:"release_date,omitempty"`
+	Popularity  int    `json:"popularity,omitempty"`
+
+	// Explicit is true if the provider marks the track as explicit.
+	// Providers that do not rate content leave it false.
+	Explicit bool `json:"explicit,omitempty"`
+
+	// Genres are the genres of the track as the provider reports them: of
+	// its artists on Spotify, from the file tags of local files. Spotify
+	// tracks only carry them in migrations that filter by genre.
+	Genres []string `json:"genres,omitempty"`
+
+	// MusicBrainzID is the MusicBrainz recording ID, when the provider
+	// knows it.
+	MusicBrainzID string `json:"musicbrainz_id,omitempty"`
+
+	// AlbumArtURL and PreviewURL let clients show artwork and play a short
+	// audio preview, e.g. when reviewing low-confidence matches. Providers
+	// leave them empty when unavailable.
+	AlbumArtURL string `json:"album_art_url,omitempty"`
+	PreviewURL  string `json:"preview_url,omitempty"`
+}
+
+// IsEpisode reports whether the item is a podcast episode.
+func (t Track) IsEpisode() bool {
+	return t.Type == ItemTypeEpisode
+}
+
+// ReleaseYear returns the year of the track's release date, or 0 if it is
+// unknown.
+func (t Track) ReleaseYear() int {
+	if len(t.ReleaseDate) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(t.ReleaseDate[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
+
+// TrackMetadata is what a metadata source knows about a track, for filling
+// in what its provider left out. Empty fields are unknown.
+type TrackMetadata struct {
+	ISRC          string `json:"isrc,omitempty"`
+	DurationMS    int    `json:"duration_ms,omitempty"`
+	Album         string `json:"album,omitempty"`
+	MusicBrainzID string `json:"musicbrainz_id,omitempty"`
+}
+
+// MissingMetadata reports whether the track lacks an ISRC, duration or
+// album, which metadata sources can fill in.
+func (t Track) MissingMetadata() bool {
+	return t.ISRC == "" || t.DurationMS == 0 || t.Album == ""
+}
+
+// FillMetadata sets the fields of the track that are empty to those of m,
+// keeping the ones the track already has, and reports whether it set any.
+func (t *Track) FillMetadata(m TrackMetadata) bool {
+	filled := false
+	if t.ISRC == "" && m.ISRC != "" {
+		t.ISRC, filled = m.ISRC, true
+	}
+	if t.DurationMS == 0 && m.DurationMS > 0 {
+		t.DurationMS, filled = m.DurationMS, true
+	}
+	if t.Album == "" && m.Album != "" {
+		t.Album, filled = m.Album, true
+	}
+	if t.MusicBrainzID == "" && m.MusicBrainzID != "" {
+		t.MusicBrainzID, filled = m.MusicBrainzID, true
+	}
+	return filled
+}
+
+// Artist returns the track's artists joined with ", ", for display and for
+// providers whose search only accepts a single artist string.
+func (t Track) Artist() string {
+	return strings.Join(t.Artists, ", ")
+}
+
+// trackFields has the fields of Track without its JSON methods.
+type trackFields Track
+
+// trackJSON is the JSON form of Track. Besides the list of artists it
+// carries artist, the artists joined by Track.Artist, which clients written
+// before tracks had several artists read and send.
+type trackJSON struct {
+	trackFields
+	Artist string `json:"artist"`
+}
+
+// MarshalJSON encodes the track with both artists and artist.
+func (t Track) MarshalJSON() ([]byte, error) {
+	return json.Marshal(trackJSON{trackFields: trackFields(t), Artist: t.Artist()})
+}
+
+// UnmarshalJSON decodes a track. If it has no artists but an artist, the
+// artist is taken as one name: commas are part of names such as "Earth,
+// Wind & Fire", so they cannot tell artists apart.
+func (t *Track) UnmarshalJSON(data []byte) error {
+	var decoded trackJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*t = Track(decoded.trackFields)
+	if len(t.Artists) == 0 && strings.TrimSpace(decoded.Artist) != "" {
+		t.Artists = []string{strings.TrimSpace(decoded.Artist)}
+	}
+	return nil
+}
+
+// TrackCandidate is a possible match for a track together with its
+// confidence score (0.0 to 1.0).
+type TrackCandidate struct {
+	Track           Track   `json:"track"`
+	ConfidenceScore float64 `json:"confidence_score"`
+}
+
+// RankCandidates orders candidates by confidence score, best first. Equal
+// scores keep their order, so the provider's own ranking breaks ties.
+func RankCandidates(candidates []TrackCandidate) {
+	slices.SortStableFunc(candidates, func(a, b TrackCandidate) int {
+		return cmp.Compare(b.ConfidenceScore, a.ConfidenceScore)
+	})
 }
 
 // Playlist represents a collection of tracks from a streaming provider.
@@ -17,27 +472,701 @@ type Playlist struct {
 	OwnerName   string  `json:"owner_name,omitempty"`
 	TrackCount  int     `json:"track_count"`
 	Tracks      []Track `json:"tracks,omitempty"`
+
+	// IsOwner is false for playlists the user follows or collaborates on
+	// but does not own. IsCollaborative playlists can be edited by every
+	// follower the owner invited.
+	IsOwner         bool `json:"is_owner"`
+	IsCollaborative bool `json:"is_collaborative"`
+	IsPublic        bool `json:"is_public"`
+}
+
+// PageRequest selects a page of results. Cursor is an opaque, provider-specific
+// value taken from a previous page's NextCursor; empty means the first page.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+}
+
+// PlaylistPage is a single page of a user's playlists.
+type PlaylistPage struct {
+	Items      []Playlist `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	Total      int        `json:"total,omitempty"`
+}
+
+// Album is an album saved to a user's library.
+type Album struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Artists     []string `json:"artists"`
+	ReleaseDate string   `json:"release_date,omitempty"`
+	TrackCount  int      `json:"track_count,omitempty"`
+}
+
+// Artist is an artist a user follows.
+type Artist struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AccountExport is a provider-neutral backup of a user's account on one
+// provider: every playlist with its tracks and, for providers whose users
+// keep a library outside playlists, their liked songs, saved albums and
+// shows, and followed artists. Sections the provider does not have are
+// empty.
+type AccountExport struct {
+	Provider        string     `json:"provider"`
+	ExportedAt      time.Time  `json:"exported_at"`
+	Playlists       []Playlist `json:"playlists"`
+	LikedTracks     []Track    `json:"liked_tracks"`
+	SavedAlbums     []Album    `json:"saved_albums"`
+	SavedShows      []Show     `json:"saved_shows"`
+	FollowedArtists []Artist   `json:"followed_artists"`
+}
+
+// ProviderToken holds the OAuth credentials of an account for a single
+// streaming provider.
+type ProviderToken struct {
+	AccessToken  string     `json:"access_token" binding:"required"`
+	RefreshToken string     `json:"refresh_token,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+
+	// Scopes lists the OAuth scopes granted to the token, as reported by
+	// the provider's token response.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// ProviderConnection describes a provider token stored in the vault. The
+// token itself is never reported.
+type ProviderConnection struct {
+	Provider  string     `json:"provider"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Expired   bool       `json:"expired"`
+
+	// Refreshable is true if a refresh token is stored and the provider can
+	// renew expired tokens with it, so an expired token still works.
+	Refreshable bool     `json:"refreshable"`
+	Scopes      []string `json:"scopes,omitempty"`
+}
+
+// PlaylistUpdate describes changes to a playlist's details. Nil fields are
+// left unchanged.
+type PlaylistUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Public      *bool   `json:"public,omitempty"`
+
+	// Collaborative lets followers the owner invited edit the playlist. Only
+	// providers implementing ports.CollaborativePlaylists support it;
+	// collaborative playlists are always private.
+	Collaborative *bool `json:"collaborative,omitempty"`
+}
+
+// RemoveTracksRequest lists the tracks (by their external IDs) to remove
+// from a playlist.
+type RemoveTracksRequest struct {
+	TrackIDs []string `json:"track_ids" binding:"required,min=1"`
 }
 
 // MigrationRequest contains all information needed to migrate a playlist
-// from one streaming provider to another.
+// from one streaming provider to another. Tokens may be omitted when the
+// account has stored them in the token vault.
 type MigrationRequest struct {
 	SourceProvider string `json:"source_provider" binding:"required"`
-	SourceToken    string `json:"source_token" binding:"required"`
+	SourceToken    string `json:"source_token"`
 	DestProvider   string `json:"dest_provider" binding:"required"`
-	DestToken      string `json:"dest_token" binding:"required"`
+	DestToken      string `json:"dest_token"`
 	PlaylistID     string `json:"playlist_id" binding:"required"`
+
+	// DryRun matches tracks without creating or modifying the destination playlist.
+	DryRun bool `json:"dry_run"`
+
+	// Market is an ISO 3166-1 alpha-2 country code used when searching the
+	// destination provider. Empty uses the provider's default for the token.
+	Market string `json:"market,omitempty" binding:"omitempty,len=2"`
+
+	// IdempotencyKey, taken from the Idempotency-Key header, makes repeated
+	// requests with the same key return the first migration instead of
+	// running it again.
+	IdempotencyKey string `json:"-"`
+
+	// Debug, set by administrators when queuing a job, records the
+	// provider HTTP traffic of the job; see Job.
+	Debug bool `json:"-"`
+
+	// Priority, set when queuing a job, orders it among the queued jobs;
+	// see Job.
+	Priority JobPriority `json:"-"`
+
+	// NotifyEmail is sent a summary of a queued migration once its job
+	// finished or failed, if the server has email notifications enabled
+	// and the address is in one of its allowed domains.
+	NotifyEmail string `json:"notify_email,omitempty" binding:"omitempty,email"`
+
+	// PreserveOrder reports source positions left without a destination
+	// track as gaps, and makes retries insert late matches at their original
+	// relative position instead of appending them.
+	PreserveOrder bool `json:"preserve_order"`
+
+	// Classical matches classical works by composer, work and movement
+	// rather than by literal title, and weights performers less.
+	Classical bool `json:"classical"`
+
+	// StrictVersions refuses to match a track to a different version of
+	// it, such as a studio track to a live recording or a remix. By default
+	// such candidates are only penalized.
+	StrictVersions bool `json:"strict_versions"`
+
+	// MatchingStrategy trades match quality against coverage; see
+	// MatchingStrategy. Empty uses the default scoring.
+	MatchingStrategy MatchingStrategy `json:"matching_strategy,omitempty" binding:"omitempty,oneof=isrc_only strict relaxed duration_weighted"`
+
+	// Rerecordings picks between the original recording of a track and a
+	// re-recording of it when the destination has both; see
+	// RerecordingPreference.
+	Rerecordings RerecordingPreference `json:"rerecordings,omitempty" binding:"omitempty,oneof=any prefer avoid"`
+
+	// CopySharing makes the destination playlist public or collaborative
+	// when the source playlist is, as far as the destination supports it.
+	// By default migrated playlists are private.
+	CopySharing bool `json:"copy_sharing"`
+
+	// ConflictPolicy decides what happens when the destination already has
+	// the playlist; see ConflictPolicy. Empty always creates a new one.
+	ConflictPolicy ConflictPolicy `json:"conflict_policy,omitempty" binding:"omitempty,oneof=reuse skip suffix"`
+
+	// MinScore is the confidence a match needs, if it is higher than the
+	// minimum of the matching strategy.
+	MinScore float64 `json:"min_score,omitempty" binding:"omitempty,min=0,max=1"`
+
+	// ReviewThreshold, if set, splits the matches by confidence: matches
+	// scoring below it go into a second destination playlist named
+	// "<name> (Review)" instead of the main one, so they can be checked by
+	// hand without mixing doubtful tracks into the migrated playlist.
+	ReviewThreshold float64 `json:"review_threshold,omitempty" binding:"omitempty,min=0,max=1"`
+
+	// Dedupe migrates tracks that appear more than once in the source
+	// playlist only once.
+	Dedupe bool `json:"dedupe"`
+
+	// NamePattern is the name of the destination playlist. The placeholders
+	// {source}, {dest}, {playlist} (the source playlist's name) and {date}
+	// (YYYY-MM-DD) are replaced. Empty uses "Migrated from {source}".
+	NamePattern string `json:"name_pattern,omitempty" binding:"omitempty,max=200"`
+
+	// Exclude skips the source tracks it matches; they are reported with
+	// TrackStatusFiltered and not searched for.
+	Exclude *TrackFilter `json:"exclude,omitempty"`
+
+	// Genres, if set, migrates only the source tracks of any of these
+	// genres, e.g. ["jazz"] to take the jazz tracks out of a mixed
+	// playlist. A genre matches whole words of a track's genres, so "jazz"
+	// matches "vocal jazz". Other tracks, and tracks without a known genre,
+	// are reported with TrackStatusFiltered.
+	Genres []string `json:"genres,omitempty"`
+}
+
+// TrackFilter selects source tracks to leave out of a migration. A track is
+// excluded if any of its rules matches.
+type TrackFilter struct {
+	// Artists excludes the tracks of any of these artists, compared without
+	// regard to case.
+	Artists []string `json:"artists,omitempty"`
+
+	// TitlePatterns excludes tracks whose title matches any of these
+	// regular expressions (RE2 syntax), without regard to case, e.g.
+	// "sped up" or "^interlude".
+	TitlePatterns []string `json:"title_patterns,omitempty"`
+
+	// Explicit excludes tracks the source provider marks as explicit.
+	Explicit bool `json:"explicit,omitempty"`
+
+	// Genres excludes tracks of any of these genres, matched like
+	// MigrationRequest.Genres.
+	Genres []string `json:"genres,omitempty"`
+}
+
+// MatchOptions returns the match options the request asks for.
+func (r MigrationRequest) MatchOptions() MatchOptions {
+	return MatchOptions{
+		Classical:      r.Classical,
+		StrictVersions: r.StrictVersions,
+		Strategy:       r.MatchingStrategy,
+		MinScore:       r.MinScore,
+		Rerecordings:   r.Rerecordings,
+	}
+}
+
+// MergeRequest asks to merge several source playlists, possibly of
+// different providers, into one new destination playlist. Tracks found in
+// more than one source are added once: tracks sharing an ID or ISRC before
+// they are searched, and tracks matched to the same destination track after.
+type MergeRequest struct {
+	Sources []MergeSource `json:"sources" binding:"required,min=2,max=20,dive"`
+
+	// SourceTokens holds a token per source provider. Providers without
+	// one use the token vault.
+	SourceTokens map[string]string `json:"source_tokens,omitempty"`
+
+	DestProvider string `json:"dest_provider" binding:"required"`
+	DestToken    string `json:"dest_token"`
+
+	// Name is the name of the merged playlist. Empty uses "Merged
+	// playlist".
+	Name string `json:"name,omitempty" binding:"omitempty,max=200"`
+
+	// Order is the order of the merged tracks; see MergeOrder.
+	Order MergeOrder `json:"order,omitempty" binding:"omitempty,oneof=by_source interleave release_date"`
+
+	DryRun           bool             `json:"dry_run"`
+	Market           string           `json:"market,omitempty" binding:"omitempty,len=2"`
+	MatchingStrategy MatchingStrategy `json:"matching_strategy,omitempty" binding:"omitempty,oneof=isrc_only strict relaxed duration_weighted"`
+	MinScore         float64          `json:"min_score,omitempty" binding:"omitempty,min=0,max=1"`
+}
+
+// MergeSource is one playlist of a merge.
+type MergeSource struct {
+	Provider   string `json:"provider" binding:"required"`
+	PlaylistID string `json:"playlist_id" binding:"required"`
+}
+
+// MergeOrder is the order of the tracks of a merged playlist.
+type MergeOrder string
+
+const (
+	// MergeBySource keeps the tracks of each source together, in the order
+	// the sources are given.
+	MergeBySource MergeOrder = "by_source"
+
+	// MergeInterleave takes one track of each source in turn.
+	MergeInterleave MergeOrder = "interleave"
+
+	// MergeReleaseDate orders the tracks by release date, oldest first.
+	// Tracks without one come last, in source order.
+	MergeReleaseDate MergeOrder = "release_date"
+)
+
+// Valid reports whether o is a known merge order. Empty means
+// MergeBySource.
+func (o MergeOrder) Valid() bool {
+	switch o {
+	case "", MergeBySource, MergeInterleave, MergeReleaseDate:
+		return true
+	default:
+		return false
+	}
+}
+
+// MigrationProfile is a saved set of migration options. Migrating a
+// playlist with a profile only takes the playlist ID and, unless they are
+// in the token vault, the tokens.
+type MigrationProfile struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id,omitempty"`
+	Name      string `json:"name" binding:"required,max=100"`
+
+	SourceProvider   string                `json:"source_provider" binding:"required"`
+	DestProvider     string                `json:"dest_provider" binding:"required"`
+	Market           string                `json:"market,omitempty" binding:"omitempty,len=2"`
+	PreserveOrder    bool                  `json:"preserve_order"`
+	Classical        bool                  `json:"classical"`
+	StrictVersions   bool                  `json:"strict_versions"`
+	MatchingStrategy MatchingStrategy      `json:"matching_strategy,omitempty" binding:"omitempty,oneof=isrc_only strict relaxed duration_weighted"`
+	Rerecordings     RerecordingPreference `json:"rerecordings,omitempty" binding:"omitempty,oneof=any prefer avoid"`
+	MinScore         float64               `json:"min_score,omitempty" binding:"omitempty,min=0,max=1"`
+	ReviewThreshold  float64               `json:"review_threshold,omitempty" binding:"omitempty,min=0,max=1"`
+	Dedupe           bool                  `json:"dedupe"`
+	NamePattern      string                `json:"name_pattern,omitempty" binding:"omitempty,max=200"`
+	ConflictPolicy   ConflictPolicy        `json:"conflict_policy,omitempty" binding:"omitempty,oneof=reuse skip suffix"`
+	CopySharing      bool                  `json:"copy_sharing"`
+	Exclude          *TrackFilter          `json:"exclude,omitempty"`
+	Genres           []string              `json:"genres,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Request returns the migration request that run makes with the options of
+// the profile.
+func (p *MigrationProfile) Request(run ProfileMigrationRequest) MigrationRequest {
+	return MigrationRequest{
+		SourceProvider:   p.SourceProvider,
+		SourceToken:      run.SourceToken,
+		DestProvider:     p.DestProvider,
+		DestToken:        run.DestToken,
+		PlaylistID:       run.PlaylistID,
+		DryRun:           run.DryRun,
+		Market:           p.Market,
+		IdempotencyKey:   run.IdempotencyKey,
+		PreserveOrder:    p.PreserveOrder,
+		Classical:        p.Classical,
+		StrictVersions:   p.StrictVersions,
+		MatchingStrategy: p.MatchingStrategy,
+		Rerecordings:     p.Rerecordings,
+		CopySharing:      p.CopySharing,
+		ConflictPolicy:   p.ConflictPolicy,
+		MinScore:         p.MinScore,
+		ReviewThreshold:  p.ReviewThreshold,
+		Dedupe:           p.Dedupe,
+		NamePattern:      p.NamePattern,
+		Exclude:          p.Exclude,
+		Genres:           p.Genres,
+	}
+}
+
+// ProfileMigrationRequest migrates a playlist with the options of a saved
+// migration profile.
+type ProfileMigrationRequest struct {
+	PlaylistID  string `json:"playlist_id" binding:"required"`
+	SourceToken string `json:"source_token"`
+	DestToken   string `json:"dest_token"`
+	DryRun      bool   `json:"dry_run"`
+
+	// IdempotencyKey is taken from the Idempotency-Key header; see
+	// MigrationRequest.
+	IdempotencyKey string `json:"-"`
+}
+
+// ConflictPolicy decides what a migration does when the destination already
+// has its playlist: the playlist an earlier migration of the same source
+// playlist created, or, if the name pattern contains {playlist}, an owned
+// playlist with the same name.
+type ConflictPolicy string
+
+const (
+	// ConflictIgnore creates a new playlist regardless.
+	ConflictIgnore ConflictPolicy = ""
+
+	// ConflictReuse adds the matched tracks to the existing playlist,
+	// leaving out those it already holds.
+	ConflictReuse ConflictPolicy = "reuse"
+
+	// ConflictSkip fails the migration with ErrPlaylistExists before any
+	// track is searched.
+	ConflictSkip ConflictPolicy = "skip"
+
+	// ConflictSuffix creates a new playlist under the first free name of
+	// the form "<name> (2)", "<name> (3)" and so on.
+	ConflictSuffix ConflictPolicy = "suffix"
+)
+
+// Valid reports whether p is a known conflict policy.
+func (p ConflictPolicy) Valid() bool {
+	switch p {
+	case ConflictIgnore, ConflictReuse, ConflictSkip, ConflictSuffix:
+		return true
+	default:
+		return false
+	}
+}
+
+// RerecordingPreference decides which recording of a track a migration
+// picks when the destination has both the original and a re-recording,
+// such as "Love Story" and "Love Story (Taylor's Version)". Re-recordings
+// are told apart by title markers, or by a later release under another
+// ISRC.
+type RerecordingPreference string
+
+const (
+	// RerecordingsDefault leaves the choice to the score, in which the
+	// release year breaks ties, so that a re-recording released years later
+	// does not replace the original.
+	RerecordingsDefault RerecordingPreference = ""
+
+	// RerecordingsAny leaves the choice to the score alone, without the
+	// release year tie-breaker.
+	RerecordingsAny RerecordingPreference = "any"
+
+	// RerecordingsPrefer picks the re-recording.
+	RerecordingsPrefer RerecordingPreference = "prefer"
+
+	// RerecordingsAvoid picks the original recording.
+	RerecordingsAvoid RerecordingPreference = "avoid"
+)
+
+// Valid reports whether p is a known re-recording preference.
+func (p RerecordingPreference) Valid() bool {
+	switch p {
+	case RerecordingsDefault, RerecordingsAny, RerecordingsPrefer, RerecordingsAvoid:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProviderStatus describes a registered provider and whether it accepts
+// requests.
+type ProviderStatus struct {
+	Name           string `json:"name"`
+	Enabled        bool   `json:"enabled"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
+}
+
+// TrackMapping records that two tracks on different providers are the same
+// recording, as confirmed by a migration match. Mappings are symmetric.
+type TrackMapping struct {
+	ProviderA string  `json:"provider_a"`
+	TrackA    Track   `json:"track_a"`
+	ProviderB string  `json:"provider_b"`
+	TrackB    Track   `json:"track_b"`
+	Score     float64 `json:"score"`
+}
+
+// MatchVerdict is a user's judgement of a match.
+type MatchVerdict string
+
+const (
+	MatchConfirmed MatchVerdict = "confirmed"
+	MatchRejected  MatchVerdict = "rejected"
+)
+
+// MatchFeedback is a user's verdict on matching SourceTrack on
+// SourceProvider to MatchedTrack on DestProvider, identified by their
+// external IDs. Feedback is shared by all accounts of the instance and, like
+// track mappings, is symmetric: it applies to migrations in either
+// direction. Each account has one verdict per match; a new one replaces it.
+type MatchFeedback struct {
+	AccountID      string       `json:"account_id,omitempty"`
+	SourceProvider string       `json:"source_provider" binding:"required"`
+	SourceTrack    Track        `json:"source_track"`
+	DestProvider   string       `json:"dest_provider" binding:"required"`
+	MatchedTrack   Track        `json:"matched_track"`
+	Verdict        MatchVerdict `json:"verdict" binding:"required,oneof=confirmed rejected"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// MatchFeedbackTally counts the verdicts of all accounts on one match.
+type MatchFeedbackTally struct {
+	Confirmations int `json:"confirmations"`
+	Rejections    int `json:"rejections"`
+}
+
+// Accounts returns the number of accounts that gave a verdict.
+func (t MatchFeedbackTally) Accounts() int {
+	return t.Confirmations + t.Rejections
+}
+
+// Verdict returns the verdict held by most accounts, or "" on a tie.
+// Confirmed matches are reused by migrations without searching; rejected
+// ones are no longer accepted from a search.
+func (t MatchFeedbackTally) Verdict() MatchVerdict {
+	switch {
+	case t.Confirmations > t.Rejections:
+		return MatchConfirmed
+	case t.Rejections > t.Confirmations:
+		return MatchRejected
+	default:
+		return ""
+	}
+}
+
+// GoldenMatch is an entry of the golden dataset, the labeled matches the
+// matching engine is measured against: Expected is the track on
+// DestProvider that SourceTrack on SourceProvider is known to match, or
+// nil if none of the known candidates does, and Rejected lists candidates
+// known to be wrong. There is one entry per source track and destination
+// provider, identified by GoldenMatchID.
+//
+// Entries are drawn from match feedback, following the verdict of most
+// accounts, until an administrator curates them; curated entries are no
+// longer changed by feedback.
+type GoldenMatch struct {
+	ID             string    `json:"id"`
+	SourceProvider string    `json:"source_provider" binding:"required"`
+	SourceTrack    Track     `json:"source_track"`
+	DestProvider   string    `json:"dest_provider" binding:"required"`
+	Expected       *Track    `json:"expected,omitempty"`
+	Rejected       []Track   `json:"rejected,omitempty"`
+	Curated        bool      `json:"curated"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// GoldenMatchID returns the ID of the golden dataset entry of the track
+// sourceID on sourceProvider for destProvider.
+func GoldenMatchID(sourceProvider, sourceID, destProvider string) string {
+	sum := sha256.Sum256([]byte(sourceProvider + "\x00" + sourceID + "\x00" + destProvider))
+	return hex.EncodeToString(sum[:16])
+}
+
+// GoldenMatchFilter narrows a listing of the golden dataset. Empty fields
+// match every entry.
+type GoldenMatchFilter struct {
+	SourceProvider string
+	DestProvider   string
+}
+
+// Matches reports whether m passes the filter.
+func (f GoldenMatchFilter) Matches(m GoldenMatch) bool {
+	return (f.SourceProvider == "" || f.SourceProvider == m.SourceProvider) &&
+		(f.DestProvider == "" || f.DestProvider == m.DestProvider)
+}
+
+// ReverseMigrationRequest carries the tokens for reversing a stored
+// migration. SourceToken is for the original destination provider, which
+// becomes the source, and DestToken for the original source provider. Both
+// may be omitted when stored in the token vault.
+type ReverseMigrationRequest struct {
+	SourceToken string `json:"source_token"`
+	DestToken   string `json:"dest_token"`
+
+	// DryRun matches tracks without creating the playlist.
+	DryRun bool `json:"dry_run"`
+}
+
+// MatchingStrategy selects how search results are scored and how confident
+// a match must be to be accepted. Candidates scoring below the strategy's
+// minimum are reported as not found.
+type MatchingStrategy string
+
+const (
+	// MatchingDefault penalizes different versions of a track (live,
+	// remix, ...) and accepts the best candidate.
+	MatchingDefault MatchingStrategy = ""
+
+	// MatchingISRCOnly only accepts candidates that share the source
+	// track's ISRC, for archival-quality migrations. The best candidate of
+	// every other track is reported for review instead of being added.
+	MatchingISRCOnly MatchingStrategy = "isrc_only"
+
+	// MatchingStrict refuses different versions of a track and candidates
+	// scoring below 0.7.
+	MatchingStrict MatchingStrategy = "strict"
+
+	// MatchingRelaxed does not penalize different versions, so a live or
+	// remixed version is preferred to no match at all.
+	MatchingRelaxed MatchingStrategy = "relaxed"
+
+	// MatchingDurationWeighted additionally penalizes candidates whose
+	// length differs from the source track, where both lengths are known.
+	MatchingDurationWeighted MatchingStrategy = "duration_weighted"
+)
+
+// Valid reports whether s is a known strategy.
+func (s MatchingStrategy) Valid() bool {
+	switch s {
+	case MatchingDefault, MatchingISRCOnly, MatchingStrict, MatchingRelaxed, MatchingDurationWeighted:
+		return true
+	}
+	return false
+}
+
+// MinScore returns the confidence a match needs under the strategy.
+func (s MatchingStrategy) MinScore() float64 {
+	switch s {
+	case MatchingStrict:
+		return 0.7
+	default:
+		return 0
+	}
+}
+
+// Confirms reports whether the strategy accepts matched as the counterpart
+// of source without review: under MatchingISRCOnly both must carry the same
+// ISRC, other strategies accept any candidate.
+func (s MatchingStrategy) Confirms(source, matched Track) bool {
+	if s != MatchingISRCOnly {
+		return true
+	}
+	return source.ISRC != "" && strings.EqualFold(source.ISRC, matched.ISRC)
 }
 
 // TrackStatus describes the result of attempting to match a single track.
 type TrackStatus string
 
 const (
-	TrackStatusMatched  TrackStatus = "matched"
-	TrackStatusNotFound TrackStatus = "not_found"
-	TrackStatusError    TrackStatus = "error"
+	TrackStatusMatched             TrackStatus = "matched"
+	TrackStatusNotFound            TrackStatus = "not_found"
+	TrackStatusError               TrackStatus = "error"
+	TrackStatusUnavailableInMarket TrackStatus = "unavailable_in_market"
+	TrackStatusUnsupported         TrackStatus = "unsupported"
+	TrackStatusAddFailed           TrackStatus = "add_failed"
+
+	// TrackStatusUnavailable marks a track the destination has but cannot
+	// play in any market, unlike TrackStatusUnavailableInMarket, which
+	// another market may play, and TrackStatusNotFound, which the
+	// destination does not have. MatchedTrack is the unavailable track.
+	TrackStatusUnavailable TrackStatus = "unavailable"
+
+	// TrackStatusNeedsReview marks a track whose best candidate was not
+	// confirmed by ISRC under the isrc_only strategy. The candidate is kept
+	// as a suggestion but not added to the destination playlist.
+	TrackStatusNeedsReview TrackStatus = "needs_review"
+
+	// TrackStatusFiltered marks a track the request's exclusion filter left
+	// out; Error names the rule that matched. It is neither searched for
+	// nor counted as failed.
+	TrackStatusFiltered TrackStatus = "filtered"
+)
+
+// TrackErrorCode classifies why a track could not be searched or added.
+type TrackErrorCode string
+
+const (
+	// Transient failures, which may succeed when retried. An invalid token
+	// succeeds with a new one; an exceeded quota once it resets.
+	ErrorCodeRateLimited   TrackErrorCode = "rate_limited"
+	ErrorCodeTimeout       TrackErrorCode = "timeout"
+	ErrorCodeCancelled     TrackErrorCode = "cancelled"
+	ErrorCodeQuotaExceeded TrackErrorCode = "quota_exceeded"
+	ErrorCodeInvalidToken  TrackErrorCode = "invalid_token"
+	ErrorCodeProviderError TrackErrorCode = "provider_error"
+
+	// Permanent failures, which fail again when retried. ErrorCodeRejected
+	// means the destination refused the track itself, e.g. because the
+	// video was deleted.
+	ErrorCodeUnavailableInMarket TrackErrorCode = "unavailable_in_market"
+	ErrorCodeUnavailable         TrackErrorCode = "unavailable"
+	ErrorCodeUnsupported         TrackErrorCode = "unsupported"
+	ErrorCodeRejected            TrackErrorCode = "rejected"
 )
 
+// Retryable reports whether a failure with this code may succeed when
+// retried.
+func (c TrackErrorCode) Retryable() bool {
+	switch c {
+	case ErrorCodeUnavailableInMarket, ErrorCodeUnavailable, ErrorCodeUnsupported, ErrorCodeRejected:
+		return false
+	default:
+		return true
+	}
+}
+
+// RetryAfter returns how long the provider asked to wait before retrying the
+// call that failed with err, if its error carries a Retry-After hint.
+func RetryAfter(err error) (time.Duration, bool) {
+	var hint interface{ RetryAfter() time.Duration }
+	if errors.As(err, &hint) && hint.RetryAfter() > 0 {
+		return hint.RetryAfter(), true
+	}
+	return 0, false
+}
+
+// ErrorCodeOf classifies err, as returned by a provider call. Errors it does
+// not recognize are reported as ErrorCodeProviderError.
+func ErrorCodeOf(err error) TrackErrorCode {
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		return ErrorCodeRateLimited
+	case errors.Is(err, ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return ErrorCodeTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCodeCancelled
+	case errors.Is(err, ErrQuotaExceeded):
+		return ErrorCodeQuotaExceeded
+	case errors.Is(err, ErrInvalidToken), errors.Is(err, ErrInsufficientScope):
+		return ErrorCodeInvalidToken
+	case errors.Is(err, ErrUnavailableInMarket):
+		return ErrorCodeUnavailableInMarket
+	case errors.Is(err, ErrUnavailable):
+		return ErrorCodeUnavailable
+	case errors.Is(err, ErrSourceOnlyProvider):
+		return ErrorCodeUnsupported
+	default:
+		return ErrorCodeProviderError
+	}
+}
+
 // TrackResult holds the outcome of migrating a single track, including
 // the confidence score of the match (0.0 to 1.0).
 type TrackResult struct {
@@ -46,14 +1175,659 @@ type TrackResult struct {
 	Status          TrackStatus `json:"status"`
 	ConfidenceScore float64     `json:"confidence_score"`
 	Error           string      `json:"error,omitempty"`
+
+	// Candidates are the best search results the destination ranked below
+	// the match, best first, for picking another one by hand.
+	Candidates []TrackCandidate `json:"candidates,omitempty"`
+
+	// ErrorCode classifies the failure of a track with status error,
+	// add_failed, unavailable_in_market, unavailable or unsupported.
+	ErrorCode TrackErrorCode `json:"error_code,omitempty"`
+
+	// Retryable tells whether retrying the failure may succeed; it is false
+	// for tracks that did not fail. Retrying failed tracks skips those that
+	// are not retryable.
+	Retryable bool `json:"retryable"`
+
+	// SourcePosition is the 0-based index of the track in the source
+	// playlist; DestPosition is its index in the destination playlist, or nil
+	// if it was not added. In dry runs DestPosition is where it would go.
+	SourcePosition int  `json:"source_position"`
+	DestPosition   *int `json:"dest_position,omitempty"`
+
+	// SearchMS is how long the track was searched for, including retries
+	// and the waits between them; LatencyMS is the duration of the last
+	// provider call. Attempts counts the searches made: searches rate
+	// limited by the provider are retried, and Retries counts those.
+	// Searches still rate limited after their retries are put back into the
+	// migration's retry queue until the provider's Retry-After window has
+	// passed; Deferrals counts those. All are zero for tracks that were not
+	// searched.
+	SearchMS  int64 `json:"search_ms,omitempty"`
+	LatencyMS int64 `json:"latency_ms,omitempty"`
+	Attempts  int   `json:"attempts,omitempty"`
+	Retries   int   `json:"retries,omitempty"`
+	Deferrals int   `json:"deferrals,omitempty"`
+
+	// Cached is true if the destination answered the search from its search
+	// cache, so it cost no API quota.
+	Cached bool `json:"cached,omitempty"`
+	// Existing is true if the track was already in the reused destination
+	// playlist, so it was not added again.
+	Existing bool `json:"existing,omitempty"`
+	// Review is true if the match scored below the review threshold and
+	// went into the review playlist; DestPosition is then its index there.
+	Review bool `json:"review,omitempty"`
+	// Fingerprinted is true if the track was matched by the recording the
+	// acoustic fingerprint of its audio identified, after a search by its
+	// own metadata found nothing.
+	Fingerprinted bool `json:"fingerprinted,omitempty"`
+}
+
+// Fail records a failure classified by code, with message as its error if
+// it is not empty.
+func (tr *TrackResult) Fail(code TrackErrorCode, message string) {
+	if message != "" {
+		tr.Error = message
+	}
+	tr.ErrorCode = code
+	tr.Retryable = code.Retryable()
+}
+
+// RetryWorthy reports whether the failure of tr may succeed when retried.
+// Results stored before failures were classified carry no code and count
+// as retryable.
+func (tr *TrackResult) RetryWorthy() bool {
+	return tr.ErrorCode == "" || tr.Retryable
+}
+
+// AddOutcome reports whether a single track was added to a playlist.
+type AddOutcome struct {
+	TrackID string `json:"track_id"`
+	Added   bool   `json:"added"`
+	Error   string `json:"error,omitempty"`
 }
 
+// NotAdded extends outcomes with a failed outcome carrying err for every
+// track of trackIDs past those already reported. Providers use it when a
+// call is aborted part way through.
+func NotAdded(outcomes []AddOutcome, trackIDs []string, err error) []AddOutcome {
+	for _, id := range trackIDs[min(len(outcomes), len(trackIDs)):] {
+		outcomes = append(outcomes, AddOutcome{TrackID: id, Error: err.Error()})
+	}
+	return outcomes
+}
+
+// QuotaOperation identifies a provider API call that consumes quota units.
+type QuotaOperation string
+
+const (
+	QuotaOpSearch         QuotaOperation = "search"
+	QuotaOpCreatePlaylist QuotaOperation = "create_playlist"
+	QuotaOpAddTrack       QuotaOperation = "add_track"
+
+	// QuotaOpFetch reads a playlist or one page of its tracks.
+	QuotaOpFetch QuotaOperation = "fetch"
+)
+
 // MigrationResult summarizes the outcome of a full playlist migration.
 type MigrationResult struct {
-	SourcePlaylist string        `json:"source_playlist"`
-	DestPlaylistID string        `json:"dest_playlist_id"`
-	TotalTracks    int           `json:"total_tracks"`
-	MatchedTracks  int           `json:"matched_tracks"`
-	FailedTracks   int           `json:"failed_tracks"`
-	TrackResults   []TrackResult `json:"track_results"`
+	ID             string `json:"id"`
+	AccountID      string `json:"account_id,omitempty"`
+	SourceProvider string `json:"source_provider"`
+	DestProvider   string `json:"dest_provider"`
+	SourcePlaylist string `json:"source_playlist"`
+	DestPlaylistID string `json:"dest_playlist_id"`
+	TotalTracks    int    `json:"total_tracks"`
+	TotalEpisodes  int    `json:"total_episodes,omitempty"`
+	MatchedTracks  int    `json:"matched_tracks"`
+	FailedTracks   int    `json:"failed_tracks"`
+	FilteredTracks int    `json:"filtered_tracks,omitempty"`
+	DryRun         bool   `json:"dry_run"`
+	Market         string `json:"market,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	PreserveOrder  bool   `json:"preserve_order,omitempty"`
+	Classical      bool   `json:"classical,omitempty"`
+	StrictVersions bool   `json:"strict_versions,omitempty"`
+	// MatchingStrategy is the strategy the tracks were matched with, and
+	// MinScore the minimum confidence the request asked for.
+	MatchingStrategy MatchingStrategy      `json:"matching_strategy,omitempty"`
+	MinScore         float64               `json:"min_score,omitempty"`
+	Rerecordings     RerecordingPreference `json:"rerecordings,omitempty"`
+	CopySharing      bool                  `json:"copy_sharing,omitempty"`
+	ReversedFrom     string                `json:"reversed_from,omitempty"`
+	RolledBack       bool                  `json:"rolled_back"`
+	CreatedAt        time.Time             `json:"created_at"`
+	TrackResults     []TrackResult         `json:"track_results"`
+
+	// ErrorBreakdown counts the failed tracks by cause: the error code of
+	// classified failures, such as rate_limited or unavailable_in_market,
+	// or else their status, such as not_found. Its counts add up to
+	// FailedTracks.
+	ErrorBreakdown map[string]int `json:"error_breakdown,omitempty"`
+
+	// QuotaUnitsUsed reports API quota units consumed per provider, for
+	// providers with unit-based quotas (e.g. YouTube).
+	QuotaUnitsUsed map[string]int `json:"quota_units_used,omitempty"`
+	Warnings       []string       `json:"warnings,omitempty"`
+
+	// Gaps lists the source positions that have no track in the destination
+	// playlist. It is only reported when PreserveOrder is set.
+	Gaps []int `json:"gaps,omitempty"`
+
+	// Concurrency reports how search parallelism adapted to rate limiting
+	// during the latest search pass.
+	Concurrency *ConcurrencyStats `json:"concurrency,omitempty"`
+
+	// Timing reports how long the latest run, the migration or a retry,
+	// spent in each stage.
+	Timing *MigrationTiming `json:"timing,omitempty"`
+
+	// DestPlaylistName and DestPlaylistDescription are the texts the
+	// destination playlist was created with, after sanitizing them for the
+	// destination provider.
+	DestPlaylistName        string `json:"dest_playlist_name,omitempty"`
+	DestPlaylistDescription string `json:"dest_playlist_description,omitempty"`
+
+	// DestPlaylistIDs lists every destination playlist, in order, when the
+	// matched tracks exceeded the destination's playlist size limit and were
+	// split into parts named "<name> (1/3)" and so on. DestPlaylistID is the
+	// first part; DestPosition of a track counts across all parts.
+	DestPlaylistIDs []string `json:"dest_playlist_ids,omitempty"`
+
+	// ReusedPlaylist is true if the tracks were added to a destination
+	// playlist that already existed, under the "reuse" conflict policy.
+	// Rolling back such a migration removes the added tracks instead of
+	// deleting the playlist.
+	ReusedPlaylist bool `json:"reused_playlist,omitempty"`
+
+	// ReviewThreshold is the review threshold the request asked for.
+	// ReviewPlaylistID is the review playlist holding the ReviewTracks
+	// matches that scored below it, if there were any; those matches are
+	// counted in MatchedTracks too.
+	ReviewThreshold  float64 `json:"review_threshold,omitempty"`
+	ReviewPlaylistID string  `json:"review_playlist_id,omitempty"`
+	ReviewTracks     int     `json:"review_tracks,omitempty"`
+
+	// MergedFrom lists the source playlists of a merge, in request order.
+	// SourceProvider and SourcePlaylist are empty for merges.
+	MergedFrom []MergeSource `json:"merged_from,omitempty"`
+}
+
+// MigrationTiming holds the durations of a migration run in milliseconds.
+// Search is the wall time of the whole search pass, not the sum of the
+// track searches run in parallel. Stages that did not run are zero.
+type MigrationTiming struct {
+	TotalMS  int64 `json:"total_ms"`
+	FetchMS  int64 `json:"fetch_ms"`
+	SearchMS int64 `json:"search_ms"`
+	CreateMS int64 `json:"create_ms"`
+	AddMS    int64 `json:"add_ms"`
+}
+
+// MigrationComparison reports how the results of two migrations of the same
+// source playlist to the same destination provider differ, for example
+// before and after a change to matching. A is the earlier result the
+// changes are relative to, B the later one.
+type MigrationComparison struct {
+	A              string `json:"a"`
+	B              string `json:"b"`
+	SourceProvider string `json:"source_provider"`
+	DestProvider   string `json:"dest_provider"`
+	SourcePlaylist string `json:"source_playlist"`
+
+	// Unchanged counts the tracks with the same status and matched track in
+	// both results, and Summary counts the changes of each kind.
+	Unchanged int                     `json:"unchanged"`
+	Summary   map[TrackChangeKind]int `json:"summary"`
+	Changes   []TrackChange           `json:"changes"`
+}
+
+// TrackChangeKind classifies how the result of a source track differs
+// between two compared migrations.
+type TrackChangeKind string
+
+const (
+	// TrackChangeMatched is a track matched only in B.
+	TrackChangeMatched TrackChangeKind = "matched"
+	// TrackChangeUnmatched is a track matched only in A.
+	TrackChangeUnmatched TrackChangeKind = "unmatched"
+	// TrackChangeRematched is a track matched to different tracks.
+	TrackChangeRematched TrackChangeKind = "rematched"
+	// TrackChangeStatus is a track matched in neither, with another status.
+	TrackChangeStatus TrackChangeKind = "status_changed"
+	// TrackChangeAdded and TrackChangeRemoved are tracks the source
+	// playlist only had when B, respectively A, ran.
+	TrackChangeAdded   TrackChangeKind = "added"
+	TrackChangeRemoved TrackChangeKind = "removed"
+)
+
+// TrackChange is a source track whose result differs between two compared
+// migrations. A or B is nil if the track is not in that migration.
+type TrackChange struct {
+	Kind        TrackChangeKind `json:"kind"`
+	SourceTrack Track           `json:"source"`
+	A           *TrackResult    `json:"a,omitempty"`
+	B           *TrackResult    `json:"b,omitempty"`
+}
+
+// MigrationPreview estimates what a migration of a playlist would do and
+// cost, from the source tracks alone: nothing is searched or written.
+type MigrationPreview struct {
+	SourceProvider string `json:"source_provider"`
+	DestProvider   string `json:"dest_provider"`
+	PlaylistID     string `json:"playlist_id"`
+	TotalTracks    int    `json:"total_tracks"`
+	TotalEpisodes  int    `json:"total_episodes,omitempty"`
+
+	// TracksWithISRC counts tracks that carry an ISRC, which usually match
+	// reliably. KnownMatches counts tracks with a known destination
+	// counterpart that would not be searched.
+	TracksWithISRC int `json:"tracks_with_isrc"`
+	KnownMatches   int `json:"known_matches"`
+
+	// EstimatedDurationMS projects the run time of the migration from the
+	// measured fetch, the search concurrency and typical call latencies.
+	EstimatedDurationMS int64 `json:"estimated_duration_ms"`
+
+	// QuotaUnits estimates the API quota units the migration would consume
+	// per provider, for providers with unit-based quotas (e.g. YouTube).
+	QuotaUnits map[string]int `json:"quota_units,omitempty"`
+	Warnings   []string       `json:"warnings,omitempty"`
+}
+
+// ConcurrencyStats describes the effective search concurrency of a migration.
+// Max is the configured worker count; Min and Final are the lowest and last
+// limits reached after the provider rate limited searches. Deferred counts
+// the searches put into the retry queue because they stayed rate limited.
+type ConcurrencyStats struct {
+	Max         int `json:"max"`
+	Min         int `json:"min"`
+	Final       int `json:"final"`
+	RateLimited int `json:"rate_limited"`
+	Deferred    int `json:"deferred"`
+}
+
+// JobStatus is the state of a queued migration job.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+	JobCanceled  JobStatus = "canceled"
+)
+
+// Finished reports whether a job in status s will not change any more.
+func (s JobStatus) Finished() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCanceled
+}
+
+// JobPriority orders queued jobs. Interactive jobs, such as a single
+// playlist a user waits for, are leased before bulk jobs, such as one of a
+// batch of playlists.
+type JobPriority string
+
+const (
+	JobInteractive JobPriority = "interactive"
+	JobBulk        JobPriority = "bulk"
+)
+
+// Valid reports whether p is a known priority. The empty priority counts as
+// JobInteractive.
+func (p JobPriority) Valid() bool {
+	return p == "" || p == JobInteractive || p == JobBulk
+}
+
+// Rank returns the order in which jobs of priority p are leased: jobs of a
+// lower rank go first.
+func (p JobPriority) Rank() int {
+	if p == JobBulk {
+		return 1
+	}
+	return 0
+}
+
+// JobKind tells what a job migrates.
+type JobKind string
+
+const (
+	// JobPlaylist migrates the playlist of the job's request.
+	JobPlaylist JobKind = "playlist"
+
+	// JobAccount migrates the library, saved albums, followed artists and
+	// every playlist of the user on the source provider of the job's
+	// request, in that order; see AccountStage.
+	JobAccount JobKind = "account"
+)
+
+// Job is a migration queued to run in the background. Jobs are leased by one
+// worker at a time; a job whose worker stops renewing its lease, for
+// example because the process restarted, is picked up again by another.
+type Job struct {
+	ID        string    `json:"id"`
+	AccountID string    `json:"account_id,omitempty"`
+	Kind      JobKind   `json:"kind"`
+	Status    JobStatus `json:"status"`
+
+	// Request is the migration to run. It is never returned to clients.
+	// Its provider tokens are moved to Tokens before the job is queued.
+	// Account jobs apply its options to every playlist and leave its
+	// playlist ID empty.
+	Request MigrationRequest `json:"-"`
+
+	// Tokens holds the provider tokens of Request, sealed by the job
+	// service, until the job finished or was canceled.
+	Tokens []byte `json:"-"`
+
+	// Priority orders the job among the queued jobs. Among jobs of the
+	// same priority, the queue takes turns between accounts: the next job
+	// is the oldest of the account with the fewest running jobs, ties going
+	// to the account whose last job was leased longest ago, so a batch of
+	// one account does not starve the jobs of others.
+	Priority JobPriority `json:"priority"`
+
+	// Attempts counts how many times the job was leased.
+	Attempts int `json:"attempts"`
+
+	// MigrationID is the ID of the migration result once a playlist job
+	// succeeded. Account jobs list the migrations of their playlists in
+	// the progress of their playlists stage.
+	MigrationID string `json:"migration_id,omitempty"`
+	Error       string `json:"error,omitempty"`
+
+	// Progress reports the search pass of the job's migration, or the
+	// stages of an account job, once it started. It is updated while the
+	// job runs and kept when it finished.
+	Progress *JobProgress `json:"progress,omitempty"`
+
+	// Debug records the provider HTTP traffic of the job's migration in
+	// DebugLog once it finished. Only administrators can queue debug jobs
+	// and read their logs, so the log is never returned with the job.
+	Debug    bool      `json:"debug,omitempty"`
+	DebugLog *DebugLog `json:"-"`
+
+	LeaseOwner     string    `json:"-"`
+	LeaseExpiresAt time.Time `json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AccountMigrationRequest asks to migrate the library, saved albums,
+// followed artists and every playlist of the user on the source provider to
+// the destination provider, as one job. Tokens may be omitted when the
+// account has stored them in the token vault.
+type AccountMigrationRequest struct {
+	SourceProvider string `json:"source_provider" binding:"required"`
+	SourceToken    string `json:"source_token"`
+	DestProvider   string `json:"dest_provider" binding:"required"`
+	DestToken      string `json:"dest_token"`
+
+	// The options below apply to every stage that searches tracks, and the
+	// ones about playlists to the migration of every playlist; see
+	// MigrationRequest.
+	DryRun           bool                  `json:"dry_run"`
+	Market           string                `json:"market,omitempty" binding:"omitempty,len=2"`
+	NotifyEmail      string                `json:"notify_email,omitempty" binding:"omitempty,email"`
+	PreserveOrder    bool                  `json:"preserve_order"`
+	Classical        bool                  `json:"classical"`
+	StrictVersions   bool                  `json:"strict_versions"`
+	MatchingStrategy MatchingStrategy      `json:"matching_strategy,omitempty" binding:"omitempty,oneof=isrc_only strict relaxed duration_weighted"`
+	Rerecordings     RerecordingPreference `json:"rerecordings,omitempty" binding:"omitempty,oneof=any prefer avoid"`
+	CopySharing      bool                  `json:"copy_sharing"`
+	ConflictPolicy   ConflictPolicy        `json:"conflict_policy,omitempty" binding:"omitempty,oneof=reuse skip suffix"`
+	MinScore         float64               `json:"min_score,omitempty" binding:"omitempty,min=0,max=1"`
+	ReviewThreshold  float64               `json:"review_threshold,omitempty" binding:"omitempty,min=0,max=1"`
+	Dedupe           bool                  `json:"dedupe"`
+	NamePattern      string                `json:"name_pattern,omitempty" binding:"omitempty,max=200"`
+	Exclude          *TrackFilter          `json:"exclude,omitempty"`
+	Genres           []string              `json:"genres,omitempty"`
+
+	// IdempotencyKey, taken from the Idempotency-Key header, makes a
+	// repeated request return the job the first one queued.
+	IdempotencyKey string `json:"-"`
+}
+
+// MigrationRequest returns the request of the job migrating r, queued with
+// bulk priority. It has no playlist ID; the job sets it for each playlist.
+func (r AccountMigrationRequest) MigrationRequest() MigrationRequest {
+	return MigrationRequest{
+		SourceProvider:   r.SourceProvider,
+		SourceToken:      r.SourceToken,
+		DestProvider:     r.DestProvider,
+		DestToken:        r.DestToken,
+		DryRun:           r.DryRun,
+		Market:           r.Market,
+		IdempotencyKey:   r.IdempotencyKey,
+		Priority:         JobBulk,
+		NotifyEmail:      r.NotifyEmail,
+		PreserveOrder:    r.PreserveOrder,
+		Classical:        r.Classical,
+		StrictVersions:   r.StrictVersions,
+		MatchingStrategy: r.MatchingStrategy,
+		Rerecordings:     r.Rerecordings,
+		CopySharing:      r.CopySharing,
+		ConflictPolicy:   r.ConflictPolicy,
+		MinScore:         r.MinScore,
+		ReviewThreshold:  r.ReviewThreshold,
+		Dedupe:           r.Dedupe,
+		NamePattern:      r.NamePattern,
+		Exclude:          r.Exclude,
+		Genres:           r.Genres,
+	}
+}
+
+// AccountStage is a stage of an account job. The stages run in the order
+// listed here.
+type AccountStage string
+
+const (
+	// AccountStageLibrary adds the liked songs of the source to those of
+	// the destination.
+	AccountStageLibrary AccountStage = "library"
+
+	// AccountStageAlbums saves the saved albums of the source on the
+	// destination.
+	AccountStageAlbums AccountStage = "albums"
+
+	// AccountStageArtists follows the followed artists of the source on the
+	// destination.
+	AccountStageArtists AccountStage = "artists"
+
+	// AccountStagePlaylists migrates every playlist of the source, one
+	// after the other.
+	AccountStagePlaylists AccountStage = "playlists"
+)
+
+// AccountStages lists the stages of an account job in the order they run.
+var AccountStages = []AccountStage{AccountStageLibrary, AccountStageAlbums, AccountStageArtists, AccountStagePlaylists}
+
+// AccountStageStatus is the state of a stage of an account job.
+type AccountStageStatus string
+
+const (
+	AccountStagePending   AccountStageStatus = "pending"
+	AccountStageRunning   AccountStageStatus = "running"
+	AccountStageSucceeded AccountStageStatus = "succeeded"
+	AccountStageFailed    AccountStageStatus = "failed"
+
+	// AccountStageSkipped is the status of a stage one of the providers
+	// does not support, such as the library stages for providers without a
+	// library.
+	AccountStageSkipped AccountStageStatus = "skipped"
+)
+
+// AccountStageProgress reports a stage of an account job.
+type AccountStageProgress struct {
+	Stage  AccountStage       `json:"stage"`
+	Status AccountStageStatus `json:"status"`
+
+	// Processed counts the items of the stage done so far out of Total:
+	// liked songs, albums, artists or playlists. Matched counts those found
+	// on the destination, and written to it unless the job is a dry run;
+	// Failed counts the others.
+	Processed int `json:"processed"`
+	Total     int `json:"total"`
+	Matched   int `json:"matched"`
+	Failed    int `json:"failed"`
+
+	// MigrationIDs are the migrations of the playlists the playlists stage
+	// migrated, in the order they ran.
+	MigrationIDs []string `json:"migration_ids,omitempty"`
+
+	// Error tells why the stage failed or was skipped.
+	Error string `json:"error,omitempty"`
+}
+
+// DebugLog is the provider HTTP traffic recorded by a job in debug mode.
+type DebugLog struct {
+	Exchanges []ProviderExchange `json:"exchanges"`
+
+	// Dropped counts the exchanges left out once the log was full.
+	Dropped int `json:"dropped,omitempty"`
+}
+
+// ProviderExchange is a provider HTTP request and its response. Credentials
+// are removed from the URL and the response body, which is cut short.
+type ProviderExchange struct {
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	URL        string    `json:"url"`
+	Status     int       `json:"status,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	Body       string    `json:"body,omitempty"`
+	Truncated  bool      `json:"truncated,omitempty"`
+
+	// Error is set instead of Status if no response was received.
+	Error string `json:"error,omitempty"`
+}
+
+// JobEventType identifies the kind of a JobEvent.
+type JobEventType string
+
+const (
+	// JobEventStatus carries the job after its status changed.
+	JobEventStatus JobEventType = "status"
+
+	// JobEventTrack carries the result of one track search and the
+	// progress of the search pass.
+	JobEventTrack JobEventType = "track"
+
+	// JobEventProgress carries the progress of an account job after an
+	// item of one of its stages was done.
+	JobEventProgress JobEventType = "progress"
+
+	// JobEventError reports a client command that could not be carried out.
+	JobEventError JobEventType = "error"
+)
+
+// JobEvent is pushed to clients watching a job.
+type JobEvent struct {
+	Type     JobEventType `json:"type"`
+	Job      *Job         `json:"job,omitempty"`
+	Track    *TrackResult `json:"track,omitempty"`
+	Progress *JobProgress `json:"progress,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// JobProgress counts the tracks searched so far by a running job. Tracks
+// resolved from known matches are not searched and not counted.
+type JobProgress struct {
+	Processed int     `json:"processed"`
+	Total     int     `json:"total"`
+	Matched   int     `json:"matched"`
+	Failed    int     `json:"failed"`
+	Percent   float64 `json:"percent"`
+
+	// TracksPerSecond is the throughput of the search workers since the
+	// search pass started.
+	TracksPerSecond float64 `json:"tracks_per_second"`
+
+	// EstimatedCompletion projects when searching ends from the throughput
+	// so far. It is unset until the first track was searched.
+	EstimatedCompletion *time.Time `json:"estimated_completion,omitempty"`
+
+	// Stages reports each stage of an account job. The counts above then
+	// add up the items of every stage.
+	Stages []AccountStageProgress `json:"stages,omitempty"`
+}
+
+// HealthStatus is the readiness state of the API or of a single provider.
+type HealthStatus string
+
+const (
+	HealthOK       HealthStatus = "ok"
+	HealthDegraded HealthStatus = "degraded"
+	HealthDown     HealthStatus = "down"
+)
+
+// ProviderHealth reports connectivity to a single provider's API.
+type ProviderHealth struct {
+	Name      string       `json:"name"`
+	Status    HealthStatus `json:"status"`
+	LatencyMS int64        `json:"latency_ms"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// HealthReport is the overall readiness of the API: ok when every provider
+// is reachable, degraded when some are and down when none are.
+type HealthReport struct {
+	Status    HealthStatus     `json:"status"`
+	Providers []ProviderHealth `json:"providers,omitempty"`
+}
+
+// AuditAction names a kind of playlist write recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditCreatePlaylist AuditAction = "create_playlist"
+	AuditAddTracks      AuditAction = "add_tracks"
+	AuditRemoveTracks   AuditAction = "remove_tracks"
+	AuditUpdatePlaylist AuditAction = "update_playlist"
+	AuditDeletePlaylist AuditAction = "delete_playlist"
+)
+
+// Valid reports whether a is a known audit action.
+func (a AuditAction) Valid() bool {
+	switch a {
+	case AuditCreatePlaylist, AuditAddTracks, AuditRemoveTracks, AuditUpdatePlaylist, AuditDeletePlaylist:
+		return true
+	}
+	return false
+}
+
+// AuditEntry records a write made to a provider's playlists on behalf of an
+// account, whether the provider accepted it or not.
+type AuditEntry struct {
+	ID        string      `json:"id"`
+	AccountID string      `json:"account_id,omitempty"`
+	Provider  string      `json:"provider"`
+	Action    AuditAction `json:"action"`
+
+	// PlaylistID is the playlist written to; for AuditCreatePlaylist it is
+	// empty if the playlist could not be created. TrackIDs are the tracks
+	// added or removed.
+	PlaylistID string   `json:"playlist_id,omitempty"`
+	TrackIDs   []string `json:"track_ids,omitempty"`
+
+	// Error is set if the write failed.
+	Error string `json:"error,omitempty"`
+
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// AuditQuery selects audit log entries. Empty fields match every entry;
+// Since and Until bound StartedAt, inclusive and exclusive respectively.
+type AuditQuery struct {
+	AccountID string
+	Provider  string
+	Action    AuditAction
+	Since     time.Time
+	Until     time.Time
+
+	// Limit caps the number of entries returned; zero means no cap.
+	Limit int
 }
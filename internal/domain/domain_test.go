@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrack_Artist(t *testing.T) {
+	track := Track{Artists: []string{"Calvin Harris", "Rihanna"}}
+	assert.Equal(t, "Calvin Harris, Rihanna", track.Artist())
+	assert.Equal(t, "", Track{}.Artist())
+}
+
+func TestTrack_ReleaseYear(t *testing.T) {
+	assert.Equal(t, 2008, Track{ReleaseDate: "2008-11-11"}.ReleaseYear())
+	assert.Equal(t, 1983, Track{ReleaseDate: "1983"}.ReleaseYear())
+	assert.Equal(t, 0, Track{}.ReleaseYear())
+	assert.Equal(t, 0, Track{ReleaseDate: "n/a"}.ReleaseYear())
+}
+
+func TestTrack_FillMetadata(t *testing.T) {
+	track := Track{Album: "Discovery"}
+	assert.True(t, track.MissingMetadata())
+
+	assert.True(t, track.FillMetadata(TrackMetadata{ISRC: "GBDUW0000059", DurationMS: 320000, Album: "Other"}))
+	assert.Equal(t, Track{Album: "Discovery", ISRC: "GBDUW0000059", DurationMS: 320000}, track, "known fields are kept")
+	assert.False(t, track.MissingMetadata())
+	assert.False(t, track.FillMetadata(TrackMetadata{Album: "Other"}))
+}
+
+func TestTrack_JSON(t *testing.T) {
+	data, err := json.Marshal(Track{Name: "Umbrella", Artists: []string{"Rihanna", "JAY-Z"}})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"artists":["Rihanna","JAY-Z"]`)
+	assert.Contains(t, string(data), `"artist":"Rihanna, JAY-Z"`)
+
+	var track Track
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"September","artist":"Earth, Wind & Fire"}`), &track))
+	assert.Equal(t, []string{"Earth, Wind & Fire"}, track.Artists, "artist is one name")
+
+	track = Track{}
+	require.NoError(t, json.Unmarshal([]byte(`{"artists":["Tyler, The Creator"],"artist":"ignored"}`), &track))
+	assert.Equal(t, []string{"Tyler, The Creator"}, track.Artists)
+}
+
+func TestMatchingStrategy_Confirms(t *testing.T) {
+	source := Track{ISRC: "USRC17607839"}
+	assert.True(t, MatchingISRCOnly.Confirms(source, Track{ISRC: "usrc17607839"}))
+	assert.False(t, MatchingISRCOnly.Confirms(source, Track{}))
+	assert.False(t, MatchingISRCOnly.Confirms(Track{}, Track{}))
+	assert.True(t, MatchingStrict.Confirms(source, Track{}))
+}
+
+func TestErrorCodeOf(t *testing.T) {
+	tests := []struct {
+		err       error
+		want      TrackErrorCode
+		retryable bool
+	}{
+		{fmt.Errorf("spotify: %w", ErrRateLimited), ErrorCodeRateLimited, true},
+		{&StageTimeoutError{Stage: StageSearch, Timeout: time.Second}, ErrorCodeTimeout, true},
+		{context.Canceled, ErrorCodeCancelled, true},
+		{fmt.Errorf("youtube: %w", ErrInvalidToken), ErrorCodeInvalidToken, true},
+		{ErrUnavailableInMarket, ErrorCodeUnavailableInMarket, false},
+		{fmt.Errorf("spotify: %w", ErrUnavailable), ErrorCodeUnavailable, false},
+		{ErrSourceOnlyProvider, ErrorCodeUnsupported, false},
+		{errors.New("status 500"), ErrorCodeProviderError, true},
+	}
+	for _, tt := range tests {
+		code := ErrorCodeOf(tt.err)
+		assert.Equal(t, tt.want, code, tt.err.Error())
+		assert.Equal(t, tt.retryable, code.Retryable(), tt.err.Error())
+	}
+}
+
+func TestTrackResult_RetryableJSON(t *testing.T) {
+	tr := TrackResult{Status: "error"}
+	tr.Fail(ErrorCodeUnavailableInMarket, "")
+	data, err := json.Marshal(tr)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"retryable":false`, "permanent failures say so explicitly")
+
+	tr.Fail(ErrorCodeRateLimited, "")
+	data, err = json.Marshal(tr)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"retryable":true`)
+}
+
+func TestDebugCapture(t *testing.T) {
+	RecordProviderExchange(context.Background(), ProviderExchange{URL: "ignored"})
+	assert.False(t, DebugCaptureEnabled(context.Background()))
+
+	ctx, capture := ContextWithDebugCapture(context.Background())
+	assert.True(t, DebugCaptureEnabled(ctx))
+	for i := range maxDebugExchanges + 3 {
+		RecordProviderExchange(ctx, ProviderExchange{URL: fmt.Sprint(i)})
+	}
+
+	log := capture.Log()
+	assert.Len(t, log.Exchanges, maxDebugExchanges)
+	assert.Equal(t, "0", log.Exchanges[0].URL)
+	assert.Equal(t, 3, log.Dropped)
+}
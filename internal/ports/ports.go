@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/jpp0ca/MusicMigration-API/internal/domain"
 )
@@ -12,6 +14,14 @@ type MusicProvider interface {
 	// GetPlaylists returns all playlists accessible by the authenticated user.
 	GetPlaylists(ctx context.Context, token string) ([]domain.Playlist, error)
 
+	// GetPlaylistsPage returns a single page of the authenticated user's
+	// playlists. NextCursor is empty on the last page.
+	GetPlaylistsPage(ctx context.Context, token string, page domain.PageRequest) (*domain.PlaylistPage, error)
+
+	// GetPlaylist returns the metadata of a single playlist (without tracks),
+	// or domain.ErrPlaylistNotFound if it does not exist.
+	GetPlaylist(ctx context.Context, token string, playlistID string) (*domain.Playlist, error)
+
 	// GetPlaylistTracks returns all tracks in a specific playlist, handling
 	// pagination internally.
 	GetPlaylistTracks(ctx context.Context, token string, playlistID string) ([]domain.Track, error)
@@ -23,19 +33,718 @@ type MusicProvider interface {
 	// CreatePlaylist creates a new playlist and returns its ID.
 	CreatePlaylist(ctx context.Context, token string, name string, description string) (string, error)
 
-	// AddTracksToPlaylist adds the given tracks (by their external IDs) to a playlist.
-	AddTracksToPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) error
+	// AddTracksToPlaylist adds the given tracks (by their external IDs) to a
+	// playlist and reports one outcome per track, in order. Tracks the
+	// provider rejects individually are reported as not added while the rest
+	// are still added. A non-nil error means the call was aborted, e.g.
+	// because the token is invalid; the outcomes then still report which
+	// tracks were added before that.
+	AddTracksToPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) ([]domain.AddOutcome, error)
+
+	// RemoveTracksFromPlaylist removes the given tracks (by their external IDs)
+	// from a playlist.
+	RemoveTracksFromPlaylist(ctx context.Context, token string, playlistID string, trackIDs []string) error
+
+	// UpdatePlaylistDetails changes a playlist's name, description or visibility.
+	UpdatePlaylistDetails(ctx context.Context, token string, playlistID string, update domain.PlaylistUpdate) error
+
+	// DeletePlaylist removes a playlist from the authenticated user's library.
+	DeletePlaylist(ctx context.Context, token string, playlistID string) error
 
 	// Name returns the provider identifier (e.g., "spotify", "youtube").
 	Name() string
 }
 
+// CandidateSearcher is implemented by providers that can return every search
+// hit for a track rather than only the best one.
+type CandidateSearcher interface {
+	// SearchTrackCandidates returns scored candidates for track, best-scored
+	// first.
+	SearchTrackCandidates(ctx context.Context, token string, track domain.Track) ([]domain.TrackCandidate, error)
+}
+
+// EpisodeSearcher is implemented by providers that can match podcast
+// episodes. Episodes migrated to a provider without it are reported as
+// unsupported.
+type EpisodeSearcher interface {
+	SearchEpisode(ctx context.Context, token string, episode domain.Track) (*domain.Track, float64, error)
+}
+
+// TrackLookup is implemented by providers that can fetch several tracks by
+// ID in one request. Migrations use it instead of searching when the
+// destination ID of a track is already known, such as between two accounts
+// of the same provider. The result holds one entry per ID, nil where the ID
+// does not exist or, when a market is in ctx, is not playable there.
+type TrackLookup interface {
+	LookupTracks(ctx context.Context, token string, ids []string) ([]*domain.Track, error)
+}
+
+// PositionalAdder is implemented by providers that can insert tracks at a
+// given 0-based position of a playlist instead of appending them. Retries of
+// migrations that preserve order use it to put late matches in place. Tracks
+// that are not added do not take up a position, and outcomes are reported as
+// by MusicProvider.AddTracksToPlaylist.
+type PositionalAdder interface {
+	InsertTracksAt(ctx context.Context, token string, playlistID string, position int, trackIDs []string) ([]domain.AddOutcome, error)
+}
+
+// SourceOnly is implemented by providers that can only be migrated from,
+// such as a local file library. Migrations to them are rejected up front;
+// their search and write methods return domain.ErrSourceOnlyProvider.
+type SourceOnly interface {
+	SourceOnly()
+}
+
+// CollaborativePlaylists is implemented by providers whose playlists can be
+// made collaborative through domain.PlaylistUpdate.Collaborative. Others
+// ignore the field; the service rejects enabling it for them.
+type CollaborativePlaylists interface {
+	CollaborativePlaylists()
+}
+
+// TextSanitizer is implemented by providers that reject or silently alter
+// some playlist names and descriptions. The text is sanitized before a
+// playlist is created or updated.
+type TextSanitizer interface {
+	SanitizePlaylistText(name, description string) (string, string)
+}
+
+// PlaylistCapacity is implemented by providers that limit how many tracks a
+// playlist can hold. Migrations with more matched tracks are split into
+// several destination playlists.
+type PlaylistCapacity interface {
+	MaxPlaylistItems() int
+}
+
+// LibraryReader is implemented by providers whose users keep a library
+// outside playlists. Account exports include it.
+type LibraryReader interface {
+	// GetLikedTracks returns the user's liked songs, most recently liked
+	// first.
+	GetLikedTracks(ctx context.Context, token string) ([]domain.Track, error)
+
+	// GetSavedAlbums returns the albums saved to the user's library.
+	GetSavedAlbums(ctx context.Context, token string) ([]domain.Album, error)
+
+	// GetSavedShows returns the podcasts the user follows.
+	GetSavedShows(ctx context.Context, token string) ([]domain.Show, error)
+
+	// GetFollowedArtists returns the artists the user follows.
+	GetFollowedArtists(ctx context.Context, token string) ([]domain.Artist, error)
+}
+
+// LibraryWriter is implemented by providers whose library can be written
+// to. Account migrations copy the library of a LibraryReader to them.
+// Searches return nil and no error if nothing matches.
+type LibraryWriter interface {
+	// SaveTracks adds tracks, by provider ID, to the user's liked songs.
+	SaveTracks(ctx context.Context, token string, trackIDs []string) error
+
+	// SearchAlbum finds an album of another provider in the catalog.
+	SearchAlbum(ctx context.Context, token string, album domain.Album) (*domain.Album, error)
+
+	// SaveAlbums saves albums, by provider ID, to the user's library.
+	SaveAlbums(ctx context.Context, token string, albumIDs []string) error
+
+	// SearchArtist finds an artist of another provider in the catalog.
+	SearchArtist(ctx context.Context, token string, artist domain.Artist) (*domain.Artist, error)
+
+	// FollowArtists follows artists, by provider ID, for the user.
+	FollowArtists(ctx context.Context, token string, artistIDs []string) error
+}
+
+// TrackStreamer is implemented by providers that can hand out the tracks of
+// a playlist page by page while later pages are still being fetched.
+// Migrations from them start matching before the whole playlist is read.
+type TrackStreamer interface {
+	// StreamPlaylistTracks calls page with each page of the playlist's
+	// tracks, in playlist order, as GetPlaylistTracks would return them. It
+	// stops with the first error page returns.
+	StreamPlaylistTracks(ctx context.Context, token string, playlistID string, page func([]domain.Track) error) error
+}
+
+// GenreTagger is implemented by providers that can look up the genres of
+// their tracks. Migrations filtering by genre use it to fill in the Genres
+// of the source tracks.
+type GenreTagger interface {
+	// TagGenres sets the Genres of each of tracks that the provider knows
+	// genres for, in place.
+	TagGenres(ctx context.Context, token string, tracks []domain.Track) error
+}
+
+// MetadataSource looks up the metadata of tracks in a catalog, such as
+// MusicBrainz, so migrations can fill in the ISRC, duration and album their
+// source provider left out before matching.
+type MetadataSource interface {
+	// Name identifies the source in logs and cache keys.
+	Name() string
+
+	// LookupMetadata returns what the source knows about track, or nil if
+	// it does not know the track.
+	LookupMetadata(ctx context.Context, track domain.Track) (*domain.TrackMetadata, error)
+}
+
+// AudioSource is implemented by providers that can supply the audio of
+// their tracks, or a preview of it, so tracks whose search found nothing can
+// be identified by their acoustic fingerprint.
+type AudioSource interface {
+	// OpenAudio returns the audio of track, or an error matching
+	// domain.ErrNoAudio if the provider has none.
+	OpenAudio(ctx context.Context, token string, track domain.Track) (io.ReadCloser, error)
+}
+
+// Fingerprinter identifies recordings by the acoustic fingerprint of their
+// audio, such as through AcoustID.
+type Fingerprinter interface {
+	// IdentifyAudio returns the metadata of the recording audio holds and
+	// the confidence of the identification, from 0 to 1, or nil if the
+	// recording is unknown.
+	IdentifyAudio(ctx context.Context, audio io.Reader) (*domain.Track, float64, error)
+}
+
+// Pinger is implemented by providers that can check connectivity to their
+// API without a user token.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// TokenChecker is implemented by providers that can validate a user token
+// with a lightweight call. Migrations check both tokens before doing any
+// work.
+type TokenChecker interface {
+	// CheckToken returns an error matching domain.ErrInvalidToken if token
+	// is rejected, or a *domain.MissingScopeError if it lacks a scope needed
+	// to read playlists, or also to write them if write is set.
+	CheckToken(ctx context.Context, token string, write bool) error
+}
+
+// TokenRefresher is implemented by providers that can renew an expired
+// access token with the refresh token issued alongside it, using the
+// server's OAuth client credentials. Expired tokens in the vault are
+// refreshed before use.
+type TokenRefresher interface {
+	// RefreshToken returns a new token. Its RefreshToken is empty if the
+	// provider keeps the old one.
+	RefreshToken(ctx context.Context, refreshToken string) (*domain.ProviderToken, error)
+}
+
+// TokenAuthorizer is implemented by providers that can issue user tokens
+// through the OAuth authorization-code flow, with the server's OAuth client
+// and redirect URL.
+type TokenAuthorizer interface {
+	// AuthorizationURL returns the provider's consent page, which sends the
+	// user back to the redirect URL with state and an authorization code.
+	// It returns an error matching domain.ErrAuthorizationUnavailable if no
+	// OAuth client or redirect URL is configured.
+	AuthorizationURL(state string) (string, error)
+
+	// ExchangeCode trades an authorization code for a token.
+	ExchangeCode(ctx context.Context, code string) (*domain.ProviderToken, error)
+}
+
+// TokenRevoker is implemented by providers that let an application revoke
+// the access a user granted it. Revoking a connection revokes its token
+// with the provider before removing it from the vault.
+type TokenRevoker interface {
+	// RevokeToken revokes token, an access or refresh token, and the grant
+	// it belongs to.
+	RevokeToken(ctx context.Context, token string) error
+}
+
+// QuotaCoster is implemented by providers whose API enforces a unit-based
+// daily quota, such as the YouTube Data API.
+type QuotaCoster interface {
+	// QuotaCost returns the units consumed by a single call of op.
+	QuotaCost(op domain.QuotaOperation) int
+}
+
+// TrackPager is implemented by providers that return the tracks of a
+// playlist in pages, so the requests a read takes can be charged to a quota.
+type TrackPager interface {
+	// TrackPageSize returns the number of tracks per page.
+	TrackPageSize() int
+}
+
 // MigrationService defines the driving port for the core migration use case.
 type MigrationService interface {
 	// MigratePlaylist orchestrates the full migration of a playlist from one
 	// provider to another, using concurrent workers for track matching.
 	MigratePlaylist(ctx context.Context, req domain.MigrationRequest) (*domain.MigrationResult, error)
 
+	// MergePlaylists merges several source playlists into one new playlist
+	// on the destination, adding tracks found in more than one source once.
+	MergePlaylists(ctx context.Context, req domain.MergeRequest) (*domain.MigrationResult, error)
+
+	// PreviewMigration fetches the source playlist of req and estimates the
+	// migration's duration and quota cost without searching or writing.
+	PreviewMigration(ctx context.Context, req domain.MigrationRequest) (*domain.MigrationPreview, error)
+
 	// ListPlaylists returns playlists from a given provider for the authenticated user.
 	ListPlaylists(ctx context.Context, provider string, token string) ([]domain.Playlist, error)
+
+	// ListPlaylistsPage returns a single page of playlists from a given provider.
+	ListPlaylistsPage(ctx context.Context, provider string, token string, page domain.PageRequest) (*domain.PlaylistPage, error)
+
+	// SearchTracks returns scored match candidates for a track on the given provider.
+	SearchTracks(ctx context.Context, provider string, token string, track domain.Track) ([]domain.TrackCandidate, error)
+
+	// GetPlaylist returns a single playlist from a given provider, including its tracks.
+	GetPlaylist(ctx context.Context, provider string, token string, playlistID string) (*domain.Playlist, error)
+
+	// UpdatePlaylist changes the details of a playlist on the given provider.
+	UpdatePlaylist(ctx context.Context, provider string, token string, playlistID string, update domain.PlaylistUpdate) error
+
+	// RemoveTracks removes tracks (by their external IDs) from a playlist on the given provider.
+	RemoveTracks(ctx context.Context, provider string, token string, playlistID string, trackIDs []string) error
+
+	// DeletePlaylist deletes a playlist on the given provider.
+	DeletePlaylist(ctx context.Context, provider string, token string, playlistID string) error
+
+	// ExportAccount returns every playlist, with its tracks, and the library
+	// of the authenticated user on the given provider.
+	ExportAccount(ctx context.Context, provider string, token string) (*domain.AccountExport, error)
+
+	// GetMigration returns a stored migration result owned by the caller's account.
+	GetMigration(ctx context.Context, id string) (*domain.MigrationResult, error)
+
+	// ListMigrations returns the migration history of the caller's account.
+	ListMigrations(ctx context.Context) ([]domain.MigrationResult, error)
+
+	// CompareMigrations lists the tracks whose status or matched track
+	// differ between two stored migrations of the same playlist.
+	CompareMigrations(ctx context.Context, a, b string) (*domain.MigrationComparison, error)
+
+	// RetryFailedTracks searches again for tracks of a stored migration that
+	// were not found or failed, and appends new matches to its destination playlist.
+	RetryFailedTracks(ctx context.Context, id string, token string) (*domain.MigrationResult, error)
+
+	// ReverseMigration migrates the destination playlist of a stored migration
+	// back to its source provider, reusing the known matches instead of
+	// searching for them again.
+	ReverseMigration(ctx context.Context, id string, req domain.ReverseMigrationRequest) (*domain.MigrationResult, error)
+
+	// RollbackMigration undoes a previous migration by deleting the playlist it
+	// created on the destination provider.
+	RollbackMigration(ctx context.Context, id string, token string) (*domain.MigrationResult, error)
+}
+
+// MigrationStore persists migration results so they can be inspected or
+// rolled back after the originating request has completed.
+type MigrationStore interface {
+	// Save stores a migration result, replacing any existing entry with the same ID.
+	Save(ctx context.Context, result *domain.MigrationResult) error
+
+	// Get returns the migration result with the given ID, or
+	// domain.ErrMigrationNotFound if it does not exist.
+	Get(ctx context.Context, id string) (*domain.MigrationResult, error)
+
+	// List returns all migration results belonging to the given account.
+	List(ctx context.Context, accountID string) ([]domain.MigrationResult, error)
+
+	// GetByIdempotencyKey returns the account's migration created with the
+	// given idempotency key, or domain.ErrMigrationNotFound if there is none.
+	GetByIdempotencyKey(ctx context.Context, accountID string, key string) (*domain.MigrationResult, error)
+}
+
+// AccountStore persists API consumer accounts.
+type AccountStore interface {
+	// Create stores a new account.
+	Create(ctx context.Context, account *domain.Account) error
+
+	// GetByAPIKeyHash returns the account owning the given API key hash, or
+	// domain.ErrAccountNotFound if none does.
+	GetByAPIKeyHash(ctx context.Context, hash string) (*domain.Account, error)
+
+	// Get returns the account with the given ID, or
+	// domain.ErrAccountNotFound if none exists.
+	Get(ctx context.Context, id string) (*domain.Account, error)
+
+	// SetLimits replaces the limits of the account with the given ID; nil
+	// removes them. It returns domain.ErrAccountNotFound if none exists.
+	SetLimits(ctx context.Context, id string, limits *domain.AccountLimits) error
+}
+
+// AccountService defines the driving port for account registration and
+// API key authentication.
+type AccountService interface {
+	// Register creates a new account and returns it along with its plain-text
+	// API key. The key is only available at registration time.
+	Register(ctx context.Context, name string) (*domain.Account, string, error)
+
+	// Authenticate resolves an API key to its account.
+	Authenticate(ctx context.Context, apiKey string) (*domain.Account, error)
+}
+
+// LimitService manages the limits of accounts.
+type LimitService interface {
+	// GetLimits returns the limits that apply to an account: its own or
+	// the defaults.
+	GetLimits(ctx context.Context, accountID string) (*domain.AccountLimits, error)
+
+	// SetLimits gives an account its own limits; nil makes the defaults
+	// apply again. It returns the limits that apply afterwards.
+	SetLimits(ctx context.Context, accountID string, limits *domain.AccountLimits) (*domain.AccountLimits, error)
+}
+
+// ProfileStore persists saved migration profiles.
+type ProfileStore interface {
+	// Save stores a profile, replacing any existing entry with the same ID.
+	Save(ctx context.Context, profile *domain.MigrationProfile) error
+
+	// Get returns the profile with the given ID, or
+	// domain.ErrProfileNotFound if it does not exist.
+	Get(ctx context.Context, id string) (*domain.MigrationProfile, error)
+
+	// List returns all profiles belonging to the given account.
+	List(ctx context.Context, accountID string) ([]domain.MigrationProfile, error)
+
+	// Delete removes the profile with the given ID, or returns
+	// domain.ErrProfileNotFound if it does not exist.
+	Delete(ctx context.Context, id string) error
+}
+
+// ProfileService defines the driving port for saved migration profiles.
+// Profiles belong to the caller's account; those of other accounts are
+// reported as not found.
+type ProfileService interface {
+	// CreateProfile saves a new profile for the caller's account.
+	CreateProfile(ctx context.Context, profile domain.MigrationProfile) (*domain.MigrationProfile, error)
+
+	// GetProfile returns one of the caller's profiles.
+	GetProfile(ctx context.Context, id string) (*domain.MigrationProfile, error)
+
+	// ListProfiles returns the caller's profiles.
+	ListProfiles(ctx context.Context) ([]domain.MigrationProfile, error)
+
+	// UpdateProfile replaces the settings of one of the caller's profiles.
+	UpdateProfile(ctx context.Context, id string, profile domain.MigrationProfile) (*domain.MigrationProfile, error)
+
+	// DeleteProfile deletes one of the caller's profiles.
+	DeleteProfile(ctx context.Context, id string) error
+
+	// MigrateWithProfile migrates a playlist with the settings of one of the
+	// caller's profiles.
+	MigrateWithProfile(ctx context.Context, id string, req domain.ProfileMigrationRequest) (*domain.MigrationResult, error)
+}
+
+// TokenStore persists encrypted provider tokens. Implementations only ever
+// see ciphertext.
+type TokenStore interface {
+	// Put stores the encrypted token of an account for a provider, replacing
+	// any existing value.
+	Put(ctx context.Context, accountID string, provider string, ciphertext []byte) error
+
+	// Get returns the encrypted token of an account for a provider, or
+	// domain.ErrTokenNotFound if none is stored.
+	Get(ctx context.Context, accountID string, provider string) ([]byte, error)
+
+	// List returns the providers an account has stored tokens for, sorted
+	// by name.
+	List(ctx context.Context, accountID string) ([]string, error)
+
+	// Delete removes the stored token of an account for a provider.
+	Delete(ctx context.Context, accountID string, provider string) error
+}
+
+// ProviderAdmin manages registered providers at runtime.
+type ProviderAdmin interface {
+	// Statuses reports every registered provider and whether it is enabled.
+	Statuses() []domain.ProviderStatus
+
+	// Disable makes requests to the named provider fail with
+	// domain.ErrProviderDisabled until it is enabled again. It returns
+	// domain.ErrProviderNotFound for unknown names.
+	Disable(name string, reason string) error
+
+	// Enable re-enables a disabled provider.
+	Enable(name string) error
+}
+
+// TrackMappingStore persists cross-provider track mappings, shared by all
+// accounts, so repeat migrations can skip searching.
+type TrackMappingStore interface {
+	// Save records a mapping in both directions, replacing any existing
+	// mapping of either track to the other provider.
+	Save(ctx context.Context, mapping *domain.TrackMapping) error
+
+	// Find returns the counterpart on toProvider of the track with ID fromID
+	// on fromProvider, and the score of the match, or
+	// domain.ErrMappingNotFound if none is known.
+	Find(ctx context.Context, fromProvider string, fromID string, toProvider string) (*domain.Track, float64, error)
+
+	// Delete removes the mapping between the track with ID idA on providerA
+	// and the track with ID idB on providerB, in both directions. Mappings
+	// of either track to other tracks are left alone.
+	Delete(ctx context.Context, providerA string, idA string, providerB string, idB string) error
+}
+
+// MatchFeedbackStore persists users' verdicts on matches, shared by all
+// accounts. A match is identified by both of its tracks, in either order.
+type MatchFeedbackStore interface {
+	// Save records a verdict, replacing any earlier verdict of the same
+	// account on the same match.
+	Save(ctx context.Context, feedback *domain.MatchFeedback) error
+
+	// Tally counts the verdicts on the match of the track fromID on
+	// fromProvider to the track toID on toProvider.
+	Tally(ctx context.Context, fromProvider string, fromID string, toProvider string, toID string) (domain.MatchFeedbackTally, error)
+}
+
+// MatchFeedbackService defines the driving port for feedback on matches.
+type MatchFeedbackService interface {
+	// SubmitFeedback records the caller's verdict on a match and returns
+	// the verdicts of all accounts on it.
+	SubmitFeedback(ctx context.Context, feedback domain.MatchFeedback) (*domain.MatchFeedbackTally, error)
+}
+
+// GoldenMatchStore persists the golden dataset of labeled matches.
+type GoldenMatchStore interface {
+	// Save stores an entry, replacing any existing entry with the same ID.
+	Save(ctx context.Context, match *domain.GoldenMatch) error
+
+	// Get returns the entry with the given ID, or
+	// domain.ErrGoldenMatchNotFound if it does not exist.
+	Get(ctx context.Context, id string) (*domain.GoldenMatch, error)
+
+	// List returns the entries passing filter, oldest update first.
+	List(ctx context.Context, filter domain.GoldenMatchFilter) ([]domain.GoldenMatch, error)
+
+	// Delete removes the entry with the given ID, or returns
+	// domain.ErrGoldenMatchNotFound if it does not exist.
+	Delete(ctx context.Context, id string) error
+}
+
+// GoldenDatasetService defines the driving port for curating the golden
+// dataset.
+type GoldenDatasetService interface {
+	// CurateMatch saves an entry as curated, replacing the entry of the same
+	// source track and destination provider.
+	CurateMatch(ctx context.Context, match domain.GoldenMatch) (*domain.GoldenMatch, error)
+
+	// ListMatches returns the entries passing filter.
+	ListMatches(ctx context.Context, filter domain.GoldenMatchFilter) ([]domain.GoldenMatch, error)
+
+	// DeleteMatch removes an entry.
+	DeleteMatch(ctx context.Context, id string) error
+}
+
+// SearchCache persists provider search responses by query, so providers
+// whose searches are expensive can answer repeated ones without calling
+// their API. Entries are opaque to the cache and expire after the TTL they
+// were stored with.
+type SearchCache interface {
+	// Get returns the entry stored under key, or domain.ErrCacheMiss if
+	// there is none or it expired.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put stores data under key for ttl, replacing any existing entry.
+	Put(ctx context.Context, key string, data []byte, ttl time.Duration) error
+
+	// Delete removes the entry stored under key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// TokenVault defines the driving port for storing provider tokens on behalf
+// of the account in the request context.
+type TokenVault interface {
+	// StoreToken encrypts and stores a provider token for the caller's account.
+	StoreToken(ctx context.Context, provider string, token domain.ProviderToken) error
+
+	// GetToken returns the decrypted provider token of the caller's account.
+	GetToken(ctx context.Context, provider string) (*domain.ProviderToken, error)
+
+	// DeleteToken removes the caller's token for a provider.
+	DeleteToken(ctx context.Context, provider string) error
+
+	// ListProviders returns the providers the caller has stored tokens for.
+	ListProviders(ctx context.Context) ([]string, error)
+}
+
+// ConnectionService defines the driving port for inspecting and revoking
+// the provider accounts linked to the caller's account through the token
+// vault.
+type ConnectionService interface {
+	// ListConnections describes the caller's stored provider tokens.
+	ListConnections(ctx context.Context) ([]domain.ProviderConnection, error)
+
+	// RevokeConnection revokes the caller's token for a provider with the
+	// provider, where it supports that, and removes it from the vault. It
+	// returns domain.ErrTokenNotFound if no token is stored.
+	RevokeConnection(ctx context.Context, provider string) error
+}
+
+// OAuthService defines the driving port for linking provider accounts
+// through the OAuth authorization-code flow, storing the issued tokens in
+// the token vault.
+type OAuthService interface {
+	// AuthorizeURL returns the provider's consent page for the caller's
+	// account.
+	AuthorizeURL(ctx context.Context, provider string) (string, error)
+
+	// CompleteAuthorization exchanges the code of a provider callback and
+	// stores the token for the account state was issued to. It returns
+	// domain.ErrInvalidAuthorizationState if state is not valid for
+	// provider.
+	CompleteAuthorization(ctx context.Context, provider, state, code string) error
+}
+
+// MigrationHook is an action run after a migration completes, such as a
+// webhook, a notification or submitting the matched tracks to a listening
+// history service.
+type MigrationHook interface {
+	// Name identifies the hook in logs.
+	Name() string
+
+	// Run is called once for every completed migration that was not a dry
+	// run. It must not modify result.
+	Run(ctx context.Context, result *domain.MigrationResult) error
+}
+
+// JobNotifier tells the requester of a queued migration that its job is
+// over, such as by email.
+type JobNotifier interface {
+	// Name identifies the notifier in logs.
+	Name() string
+
+	// NotifyJob is called once for every job that succeeded or failed, but
+	// not for canceled jobs. result is the migration of a succeeded playlist
+	// job and nil otherwise; account jobs report their stages in the job's
+	// progress. It must not modify job or result.
+	NotifyJob(ctx context.Context, job *domain.Job, result *domain.MigrationResult) error
+}
+
+// PlaylistImporter turns an uploaded playlist file into a playlist that
+// can be used as a migration source.
+type PlaylistImporter interface {
+	// ImportPlaylist parses the file read from r and stores it for the
+	// caller's account. name is used if the file does not name the playlist.
+	// It returns domain.ErrInvalidPlaylistFile for unusable files.
+	ImportPlaylist(ctx context.Context, name string, r io.Reader) (*domain.Playlist, error)
+}
+
+// MigrationStartStore records the migrations accounts start, so the daily
+// migration limit counts migrations still running and concurrent starts
+// cannot exceed it.
+type MigrationStartStore interface {
+	// Reserve records the start of the migration id of the account at at,
+	// unless the account started max or more migrations at or after since.
+	// Counting and recording are atomic. It returns the migrations the
+	// account started since then, including id if it was recorded, and
+	// whether it was.
+	Reserve(ctx context.Context, accountID string, id string, at time.Time, since time.Time, max int) (int, bool, error)
+
+	// Count returns the migrations the account started at or after since.
+	Count(ctx context.Context, accountID string, since time.Time) (int, error)
+
+	// Cancel removes the start of the migration id of the account, for a
+	// migration that failed. Canceling an unknown start is a no-op.
+	Cancel(ctx context.Context, accountID string, id string) error
+}
+
+// Locker provides named locks shared by every API instance using the same
+// backend. Locks expire after their TTL unless refreshed, so a lock held by
+// a crashed instance is eventually freed.
+type Locker interface {
+	// Acquire takes the lock key for owner until ttl elapses. It returns
+	// false if another owner holds an unexpired lock on key.
+	Acquire(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error)
+
+	// Refresh extends a lock held by owner. It returns false if owner no
+	// longer holds it.
+	Refresh(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error)
+
+	// Release frees a lock held by owner. Releasing a lock owner does not
+	// hold is a no-op.
+	Release(ctx context.Context, key string, owner string) error
+}
+
+// JobQueue durably stores queued migration jobs and hands them out to
+// workers under a time-limited lease, so jobs survive a restart and can be
+// shared by several API instances.
+type JobQueue interface {
+	// Enqueue stores a new job in the queued state. It returns
+	// domain.ErrJobExists if the account already has a job with the
+	// idempotency key of job.Request.
+	Enqueue(ctx context.Context, job *domain.Job) error
+
+	// Lease claims the next queued job, or running job whose lease has
+	// expired, by priority and in turns between accounts as described on
+	// domain.Job, for owner until lease elapses, and increments its
+	// attempts. It returns nil and no error when no job is available.
+	Lease(ctx context.Context, owner string, lease time.Duration) (*domain.Job, error)
+
+	// Renew extends the lease owner holds on a job. It returns
+	// domain.ErrJobLeaseLost if owner no longer holds it.
+	Renew(ctx context.Context, id string, owner string, lease time.Duration) error
+
+	// SaveProgress stores the search progress of a job leased by owner. It
+	// returns domain.ErrJobLeaseLost if owner no longer holds the lease.
+	SaveProgress(ctx context.Context, id string, owner string, progress domain.JobProgress) error
+
+	// Finish stores the final status, migration ID, error and debug log of
+	// a job leased by job.LeaseOwner, releases the lease and clears the
+	// job's tokens. It returns domain.ErrJobLeaseLost if the lease was
+	// taken over.
+	Finish(ctx context.Context, job *domain.Job) error
+
+	// Get returns the job with the given ID, or domain.ErrJobNotFound.
+	Get(ctx context.Context, id string) (*domain.Job, error)
+
+	// GetByIdempotencyKey returns the account's job queued with the given
+	// idempotency key, or domain.ErrJobNotFound.
+	GetByIdempotencyKey(ctx context.Context, accountID string, key string) (*domain.Job, error)
+
+	// CountActive returns the number of queued and running jobs of an
+	// account.
+	CountActive(ctx context.Context, accountID string) (int, error)
+
+	// Cancel marks a queued or running job canceled, releases its lease and
+	// clears its tokens, so the worker running it loses the lease at its
+	// next renewal. It returns domain.ErrJobFinished if the job already
+	// finished.
+	Cancel(ctx context.Context, id string) error
+}
+
+// JobService defines the driving port for running migrations in the
+// background.
+type JobService interface {
+	// EnqueueMigration validates req and queues it for the caller's account.
+	EnqueueMigration(ctx context.Context, req domain.MigrationRequest) (*domain.Job, error)
+
+	// EnqueueAccountMigration validates req and queues one job for the
+	// caller's account migrating the library, saved albums, followed
+	// artists and every playlist the caller has on the source provider.
+	EnqueueAccountMigration(ctx context.Context, req domain.AccountMigrationRequest) (*domain.Job, error)
+
+	// GetJob returns a job owned by the caller's account.
+	GetJob(ctx context.Context, id string) (*domain.Job, error)
+
+	// CancelJob cancels a queued or running job owned by the caller's
+	// account and returns it.
+	CancelJob(ctx context.Context, id string) (*domain.Job, error)
+
+	// GetJobDebugLog returns the provider traffic recorded by a job queued
+	// in debug mode, or domain.ErrNoDebugLog. It is meant for
+	// administrators and not restricted to the caller's account.
+	GetJobDebugLog(ctx context.Context, id string) (*domain.DebugLog, error)
+
+	// WatchJob streams events of a job owned by the caller's account,
+	// starting with its current status. The channel is closed once the job
+	// finished or ctx is done.
+	WatchJob(ctx context.Context, id string) (<-chan domain.JobEvent, error)
+}
+
+// AuditLog is an append-only record of the writes made to provider
+// playlists. Entries cannot be changed or removed once appended.
+type AuditLog interface {
+	// Append records entry.
+	Append(ctx context.Context, entry *domain.AuditEntry) error
+
+	// Query returns the entries matching q, most recent first.
+	Query(ctx context.Context, q domain.AuditQuery) ([]domain.AuditEntry, error)
+}
+
+// HealthChecker reports the readiness of the API and its providers.
+type HealthChecker interface {
+	Check(ctx context.Context) *domain.HealthReport
 }
@@ -0,0 +1,308 @@
+package matching
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Work identifies a piece of classical music parsed from a track title such
+// as "Symphony No. 9 in D minor, Op. 125: II. Molto vivace". Empty fields
+// and zero numbers were not found in the title.
+type Work struct {
+	// Composer is taken from a "Composer: Work" prefix, as used in video
+	// titles. Catalogs usually list the composer as an artist instead.
+	Composer string
+
+	// Form is the English name of the genre, e.g. "symphony", and Number its
+	// number within the composer's works of that form.
+	Form   string
+	Number int
+
+	// Key is normalized to e.g. "d minor" or "e flat major".
+	Key string
+
+	// Catalog is the catalog or opus number, normalized to e.g. "op 125",
+	// "bwv 1007" or "k 550", including the number within an opus
+	// ("op 27 2").
+	Catalog string
+
+	// Movement is the movement number and MovementName its title or tempo
+	// marking.
+	Movement     int
+	MovementName string
+}
+
+// formNames lists the English name of each classical form with the
+// (lowercase) spellings found in titles, including common German, French and
+// Italian ones.
+var formNames = map[string][]string{
+	"symphony":        {"symphony", "sinfonie", "symphonie", "sinfonia"},
+	"concerto":        {"concerto", "konzert", "concert"},
+	"piano concerto":  {"piano concerto", "klavierkonzert"},
+	"violin concerto": {"violin concerto", "violinkonzert"},
+	"cello concerto":  {"cello concerto"},
+	"sonata":          {"sonata", "sonate"},
+	"piano sonata":    {"piano sonata", "klaviersonate"},
+	"violin sonata":   {"violin sonata"},
+	"string quartet":  {"string quartet", "streichquartett"},
+	"quartet":         {"quartet", "quartett"},
+	"quintet":         {"quintet"},
+	"trio":            {"trio"},
+	"piano trio":      {"piano trio"},
+	"suite":           {"suite"},
+	"cello suite":     {"cello suite"},
+	"partita":         {"partita"},
+	"prelude":         {"prelude", "prélude"},
+	"fugue":           {"fugue"},
+	"etude":           {"etude", "étude"},
+	"nocturne":        {"nocturne"},
+	"mazurka":         {"mazurka"},
+	"waltz":           {"waltz", "valse"},
+	"ballade":         {"ballade"},
+	"scherzo":         {"scherzo"},
+	"impromptu":       {"impromptu"},
+	"polonaise":       {"polonaise"},
+	"rhapsody":        {"rhapsody"},
+	"mass":            {"mass", "messe"},
+	"cantata":         {"cantata", "kantate"},
+	"serenade":        {"serenade"},
+	"divertimento":    {"divertimento"},
+	"overture":        {"overture", "ouvertüre"},
+}
+
+// forms maps every spelling in formNames to its English name.
+var forms = func() map[string]string {
+	m := make(map[string]string)
+	for name, spellings := range formNames {
+		for _, spelling := range spellings {
+			m[spelling] = name
+		}
+	}
+	return m
+}()
+
+var (
+	// formPattern matches "<form> No. <n>", e.g. "Piano Sonata No. 14".
+	formPattern = regexp.MustCompile(`(?i)\b(` + formAlternatives() + `)\s+(?:(?:no|nr|n°|nº)\.?\s*|#\s*)(\d+)\b`)
+
+	keyPattern = regexp.MustCompile(`(?i)\bin\s+([a-g])(?:[\s-]?(flat|sharp|♭|♯|b|#|-flat|-sharp))?[\s-]+(major|minor|dur|moll)\b`)
+
+	// catalogPattern matches catalog numbers such as "Op. 27 No. 2",
+	// "BWV 1007", "K. 550" or "Hob. XVI:52". Only Hoboken numbers use
+	// Roman numerals.
+	catalogPattern = regexp.MustCompile(`(?i)\b(?:(hob)\.?\s*([ivxlc]+[a-z]?(?::\s*\d+)?)|(op|opus|bwv|kv|k|d|rv|hwv|twv|woo|s|l|sz|bb)\.?\s*(\d+[a-z]?))\b(?:,?\s*(?:no|nr|n°|nº)\.?\s*(\d+))?`)
+
+	// movementPattern matches a leading movement number, "II. Molto vivace"
+	// or "2. Molto vivace".
+	movementPattern = regexp.MustCompile(`^([IVXLC]+|\d{1,2})[.:)]\s+(.*)$`)
+)
+
+func formAlternatives() string {
+	names := make([]string, 0, len(forms))
+	for name := range forms {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	// Longer names first, so "piano concerto" wins over "concerto".
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	return strings.Join(names, "|")
+}
+
+// ParseWork parses a classical work from a track title. It reports false if
+// the title names neither a numbered form nor a catalog number.
+func ParseWork(title string) (Work, bool) {
+	var w Work
+	work, movement := splitMovement(title)
+
+	// "Beethoven: Symphony No. 9" - a prefix that is not itself part of the
+	// work names the composer.
+	if prefix, rest, ok := strings.Cut(work, ": "); ok &&
+		!formPattern.MatchString(prefix) && !catalogPattern.MatchString(prefix) {
+		w.Composer = strings.TrimSpace(prefix)
+		work = rest
+	}
+
+	if m := formPattern.FindStringSubmatch(work); m != nil {
+		w.Form = forms[strings.ToLower(m[1])]
+		w.Number, _ = strconv.Atoi(m[2])
+	}
+	if m := keyPattern.FindStringSubmatch(work); m != nil {
+		w.Key = normalizeKey(m[1], m[2], m[3])
+	}
+	if m := catalogPattern.FindStringSubmatch(work); m != nil {
+		prefix, number := m[1]+m[3], m[2]+m[4]
+		switch prefix = strings.ToLower(prefix); prefix {
+		case "opus":
+			prefix = "op"
+		case "kv":
+			prefix = "k"
+		}
+		w.Catalog = prefix + " " + strings.ToLower(strings.ReplaceAll(number, " ", ""))
+		if m[5] != "" {
+			w.Catalog += " " + m[5]
+		}
+	}
+	if w.Form == "" && w.Catalog == "" {
+		return Work{}, false
+	}
+
+	if m := movementPattern.FindStringSubmatch(strings.TrimSpace(movement)); m != nil {
+		w.Movement = parseNumeral(m[1])
+		w.MovementName = strings.TrimSpace(m[2])
+	} else if movement != "" {
+		w.MovementName = strings.TrimSpace(movement)
+	}
+	return w, true
+}
+
+// splitMovement splits a title into the work and the movement, which follow
+// the first ": " after the work or a " - " separator.
+func splitMovement(title string) (work, movement string) {
+	// Find the last separator followed by a movement number, so composer
+	// prefixes ("Bach: Cello Suite...") stay part of the work.
+	for _, sep := range []string{": ", " - ", " – ", " / "} {
+		idx := strings.LastIndex(title, sep)
+		if idx < 0 {
+			continue
+		}
+		if rest := strings.TrimSpace(title[idx+len(sep):]); movementPattern.MatchString(rest) {
+			return title[:idx], rest
+		}
+	}
+	return title, ""
+}
+
+func normalizeKey(note, accidental, mode string) string {
+	key := strings.ToLower(note)
+	switch strings.ToLower(strings.Trim(accidental, "-")) {
+	case "flat", "♭", "b":
+		key += " flat"
+	case "sharp", "♯", "#":
+		key += " sharp"
+	}
+	switch strings.ToLower(mode) {
+	case "major", "dur":
+		key += " major"
+	default:
+		key += " minor"
+	}
+	return key
+}
+
+// parseNumeral parses an Arabic or Roman movement number.
+func parseNumeral(s string) int {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	values := map[byte]int{'I': 1, 'V': 5, 'X': 10, 'L': 50, 'C': 100}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		v := values[s[i]]
+		if i+1 < len(s) && values[s[i+1]] > v {
+			n -= v
+		} else {
+			n += v
+		}
+	}
+	return n
+}
+
+// Query returns search terms identifying the work and movement, without the
+// punctuation and wording that varies between catalogs.
+func (w Work) Query() string {
+	var parts []string
+	if w.Composer != "" {
+		parts = append(parts, w.Composer)
+	}
+	if w.Form != "" {
+		parts = append(parts, w.Form, strconv.Itoa(w.Number))
+	}
+	if w.Catalog != "" {
+		parts = append(parts, w.Catalog)
+	}
+	if w.MovementName != "" {
+		parts = append(parts, w.MovementName)
+	}
+	return strings.Join(parts, " ")
+}
+
+// WithClassical returns a scorer for classical music. When both titles parse
+// as classical works, it compares them structurally: the work (catalog
+// number, or form, number and key) counts 0.5, the movement 0.3, the
+// composer 0.1 and the performers only 0.1, since recordings of the same
+// work by different performers are usually acceptable. A shared ISRC is
+// still a certain match. Other tracks are scored by base.
+func WithClassical(base Scorer) Scorer {
+	return ScorerFunc(func(source, candidate Track) float64 {
+		if source.ISRC != "" && strings.EqualFold(source.ISRC, candidate.ISRC) {
+			return 1.0
+		}
+		sw, ok := ParseWork(source.Name)
+		if !ok {
+			return base.Score(source, candidate)
+		}
+		cw, ok := ParseWork(candidate.Name)
+		if !ok {
+			return base.Score(source, candidate)
+		}
+		return classical(source, sw, candidate, cw)
+	})
+}
+
+// Classical is WithClassical(Catalog).
+var Classical = WithClassical(Catalog)
+
+func classical(source Track, sw Work, candidate Track, cw Work) float64 {
+	score := 0.0
+
+	// Work. Conflicting identifiers mean a different work altogether.
+	switch {
+	case sw.Catalog != "" && cw.Catalog != "":
+		if sw.Catalog != cw.Catalog {
+			return 0
+		}
+		score += 0.5
+	case sw.Form != "" && sw.Form == cw.Form && sw.Number == cw.Number:
+		score += 0.4
+		if sw.Key == "" || cw.Key == "" || sw.Key == cw.Key {
+			score += 0.1
+		}
+	default:
+		return 0
+	}
+	if sw.Key != "" && cw.Key != "" && sw.Key != cw.Key {
+		return 0
+	}
+
+	// Movement.
+	switch {
+	case sw.Movement != 0 && cw.Movement != 0:
+		if sw.Movement != cw.Movement {
+			return 0.1 * score // same work, wrong movement
+		}
+		score += 0.3
+	case sw.Movement == 0 && cw.Movement == 0:
+		if sw.MovementName == "" || Normalize(sw.MovementName) == Normalize(cw.MovementName) {
+			score += 0.3
+		} else if containsWords(Normalize(cw.MovementName), Normalize(sw.MovementName)) {
+			score += 0.2
+		}
+	default:
+		score += 0.1 // a movement against a whole work or an unnumbered one
+	}
+
+	// Composer: the surname of a source artist or title composer found in
+	// the candidate's title or artists.
+	candidateText := Normalize(candidate.Name + " " + strings.Join(candidate.Artists, " ") + " " + cw.Composer)
+	for _, name := range append([]string{sw.Composer}, source.Artists...) {
+		words := strings.Fields(Normalize(name))
+		if len(words) > 0 && containsWords(candidateText, words[len(words)-1]) {
+			score += 0.1
+			break
+		}
+	}
+
+	score += 0.1 * ArtistOverlap(source.Artists, candidate.Artists)
+	return min(score, 1.0)
+}
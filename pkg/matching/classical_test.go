@@ -0,0 +1,87 @@
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWork(t *testing.T) {
+	tests := []struct {
+		title string
+		want  Work
+	}{
+		{
+			"Symphony No. 9 in D minor, Op. 125: II. Molto vivace",
+			Work{Form: "symphony", Number: 9, Key: "d minor", Catalog: "op 125", Movement: 2, MovementName: "Molto vivace"},
+		},
+		{
+			"Beethoven: Symphony No. 9 in D Minor, Op. 125 - II. Molto Vivace (Berliner Philharmoniker)",
+			Work{Composer: "Beethoven", Form: "symphony", Number: 9, Key: "d minor", Catalog: "op 125", Movement: 2, MovementName: "Molto Vivace (Berliner Philharmoniker)"},
+		},
+		{
+			"Cello Suite No. 1 in G Major, BWV 1007: I. Prélude",
+			Work{Form: "cello suite", Number: 1, Key: "g major", Catalog: "bwv 1007", Movement: 1, MovementName: "Prélude"},
+		},
+		{
+			"Piano Sonata No. 14 in C-Sharp Minor, Op. 27 No. 2 \"Moonlight\": 3. Presto agitato",
+			Work{Form: "piano sonata", Number: 14, Key: "c sharp minor", Catalog: "op 27 2", Movement: 3, MovementName: "Presto agitato"},
+		},
+		{
+			"Sinfonie Nr. 5 c-Moll, op. 67",
+			Work{Form: "symphony", Number: 5, Catalog: "op 67"},
+		},
+		{
+			"Piano Sonata in C Major, Hob. XVI:50",
+			Work{Key: "c major", Catalog: "hob xvi:50"},
+		},
+		{
+			"Requiem in D Minor, K. 626: Lacrimosa",
+			Work{Key: "d minor", Catalog: "k 626"},
+		},
+	}
+	for _, tt := range tests {
+		got, ok := ParseWork(tt.title)
+		require.True(t, ok, tt.title)
+		assert.Equal(t, tt.want, got, tt.title)
+	}
+
+	for _, title := range []string{"Bohemian Rhapsody", "Get Lucky", "Track 5", "Symphony of Destruction"} {
+		_, ok := ParseWork(title)
+		assert.False(t, ok, title)
+	}
+}
+
+func TestClassical(t *testing.T) {
+	source := Track{
+		Name:    "Symphony No. 9 in D minor, Op. 125: II. Molto vivace",
+		Artists: []string{"Ludwig van Beethoven", "Berliner Philharmoniker", "Herbert von Karajan"},
+	}
+
+	// Same movement, other wording, other performers.
+	other := Track{
+		Name:    "Beethoven: Symphony No. 9 in D Minor, Op. 125 \"Choral\" - II. Molto vivace. Presto",
+		Artists: []string{"Wiener Philharmoniker"},
+	}
+	assert.InDelta(t, 0.9, Classical.Score(source, other), 1e-9)
+	assert.Less(t, Catalog.Score(source, other), 0.5, "the catalog scorer misses it")
+
+	sameRecording := Track{Name: "Symphony No. 9 in D Minor, Op. 125: II. Molto vivace", Artists: source.Artists}
+	assert.Equal(t, 1.0, Classical.Score(source, sameRecording))
+
+	wrongMovement := Track{Name: "Symphony No. 9 in D minor, Op. 125: IV. Presto", Artists: source.Artists}
+	assert.Less(t, Classical.Score(source, wrongMovement), 0.1)
+
+	wrongWork := Track{Name: "Symphony No. 5 in C minor, Op. 67: II. Andante con moto", Artists: source.Artists}
+	assert.Equal(t, 0.0, Classical.Score(source, wrongWork))
+
+	// Non-classical tracks fall back to the base scorer.
+	pop := Track{Name: "Get Lucky", Artists: []string{"Daft Punk"}}
+	assert.Equal(t, Catalog.Score(pop, pop), Classical.Score(pop, pop))
+}
+
+func TestWorkQuery(t *testing.T) {
+	w, _ := ParseWork("Beethoven: Symphony No. 9 in D minor, Op. 125 - II. Molto vivace")
+	assert.Equal(t, "Beethoven symphony 9 op 125 Molto vivace", w.Query())
+}
@@ -0,0 +1,31 @@
+package matching
+
+import "time"
+
+const (
+	// durationTolerance is the difference in length still considered the
+	// same recording, covering silence trimmed by services and rounding.
+	durationTolerance = 3 * time.Second
+
+	// durationFalloff is the difference beyond the tolerance at which a
+	// candidate's score is halved.
+	durationFalloff = 30 * time.Second
+)
+
+// WithDuration wraps base so that a candidate whose length differs from the
+// source loses up to half of what base gives it: differences within 3
+// seconds cost nothing, and the penalty grows linearly until 30 seconds
+// beyond that. Tracks of unknown length are scored by base alone.
+func WithDuration(base Scorer) Scorer {
+	return ScorerFunc(func(source, candidate Track) float64 {
+		score := base.Score(source, candidate)
+		if source.Duration <= 0 || candidate.Duration <= 0 {
+			return score
+		}
+		diff := (source.Duration - candidate.Duration).Abs() - durationTolerance
+		if diff <= 0 {
+			return score
+		}
+		return score * (1 - 0.5*min(1, float64(diff)/float64(durationFalloff)))
+	})
+}
@@ -0,0 +1,22 @@
+package matching
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDuration(t *testing.T) {
+	scorer := WithDuration(Catalog)
+	source := Track{Name: "Song", Artists: []string{"Artist"}, Duration: 200 * time.Second}
+	candidate := func(d time.Duration) Track {
+		return Track{Name: "Song", Artists: []string{"Artist"}, Duration: d}
+	}
+
+	base := Catalog.Score(source, candidate(0))
+	assert.Equal(t, base, scorer.Score(source, candidate(0)), "unknown length")
+	assert.Equal(t, base, scorer.Score(source, candidate(202*time.Second)), "within tolerance")
+	assert.InDelta(t, 0.75*base, scorer.Score(source, candidate(218*time.Second)), 0.001)
+	assert.InDelta(t, 0.5*base, scorer.Score(source, candidate(400*time.Second)), 0.001)
+}
@@ -0,0 +1,281 @@
+// Package matching scores how well a track found on one music service
+// matches a track from another. Its only dependency outside the standard
+// library is golang.org/x/text, so other projects can reuse it.
+//
+// A Scorer compares a source track with a candidate and returns a confidence
+// from 0 (unrelated) to 1 (certain match). Catalog suits services with
+// structured metadata (separate title, artists, album and ISRC); Title suits
+// services where the candidate is a free-text title, such as video
+// platforms. Custom scorers implement Scorer or wrap a function in
+// ScorerFunc:
+//
+//	score := matching.Score(source, candidate)
+//	i, score := matching.BestCandidate(matching.Title, source, candidates)
+//
+// Text is compared after Normalize, which folds Unicode compatibility forms
+// and diacritics. WithTransliteration additionally compares romanized
+// Cyrillic, Greek, Japanese kana and Korean titles with Latin ones.
+package matching
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Track is the metadata compared by scorers. Empty fields are unknown.
+type Track struct {
+	Name    string
+	Artists []string
+	Album   string
+	ISRC    string
+
+	// Show is the name of the podcast of an episode. For video candidates
+	// scored by Title or TitleEpisode it holds the channel name.
+	Show string
+
+	// Duration is the length of the recording, or 0 if unknown.
+	Duration time.Duration
+
+	// ReleaseYear is the year the recording was released, or 0 if unknown.
+	ReleaseYear int
+}
+
+// Scorer rates how well candidate matches source, from 0 to 1.
+type Scorer interface {
+	Score(source, candidate Track) float64
+}
+
+// ScorerFunc adapts a function to the Scorer interface.
+type ScorerFunc func(source, candidate Track) float64
+
+func (f ScorerFunc) Score(source, candidate Track) float64 {
+	return f(source, candidate)
+}
+
+// Built-in scorers.
+var (
+	// Catalog scores candidates from a structured music catalog. A shared
+	// ISRC is a certain match; otherwise the name counts 0.5, the artists
+	// 0.35 and the album 0.15.
+	Catalog Scorer = ScorerFunc(catalog)
+
+	// Title scores candidates whose name is a free-text title that may
+	// contain the artist, and whose only artist is the uploader. The name
+	// counts 0.5 (partially by word overlap), each source artist found in
+	// the title or uploader up to 0.4, and an exact title 0.1.
+	Title Scorer = ScorerFunc(title)
+
+	// CatalogEpisode scores podcast episodes from a structured catalog by
+	// name and show. Without a show on both sides the score is capped at 0.9.
+	CatalogEpisode Scorer = ScorerFunc(catalogEpisode)
+
+	// TitleEpisode scores videos against podcast episodes: the episode name
+	// should appear in the title (0.6) and the show in the title or channel
+	// (0.4).
+	TitleEpisode Scorer = ScorerFunc(titleEpisode)
+)
+
+// WithISRCOnly wraps base so that only a candidate sharing the source's
+// ISRC scores; every other candidate, and every candidate of a source
+// without an ISRC, scores 0.
+func WithISRCOnly(base Scorer) Scorer {
+	return ScorerFunc(func(source, candidate Track) float64 {
+		if source.ISRC == "" || !strings.EqualFold(source.ISRC, candidate.ISRC) {
+			return 0
+		}
+		return base.Score(source, candidate)
+	})
+}
+
+// Score rates candidate against source with the Catalog scorer.
+func Score(source, candidate Track) float64 {
+	return Catalog.Score(source, candidate)
+}
+
+// BestCandidate returns the index and score of the candidate scorer rates
+// highest. Ties go to the earlier candidate, so the order of search results
+// breaks them. It returns -1 and 0 if there are no candidates.
+func BestCandidate(scorer Scorer, source Track, candidates []Track) (int, float64) {
+	best, bestScore := -1, 0.0
+	for i, c := range candidates {
+		if score := scorer.Score(source, c); best < 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best, bestScore
+}
+
+// Normalize prepares text for comparison: it applies NFKC folding, so
+// full-width and ligature forms become their plain equivalents, strips
+// diacritics from Latin, Greek and Cyrillic letters, lowercases the result,
+// turns every run of characters other than letters and digits into a single
+// space, and trims it. "AC/DC" and "ac-dc" both become "ac dc", and
+// "Sigur Rós" becomes "sigur ros". Other scripts are kept as they are, since
+// their marks (such as Japanese dakuten) change the letter.
+func Normalize(s string) string {
+	s = norm.NFKC.String(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			space = true
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		r = unicode.ToLower(r)
+		if folded, ok := letterFolds[r]; ok {
+			b.WriteString(folded)
+		} else if unicode.In(r, unicode.Latin, unicode.Greek, unicode.Cyrillic) {
+			b.WriteRune(baseRune(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// letterFolds spells out Latin letters that have no decomposition.
+var letterFolds = map[rune]string{
+	'ø': "o", 'æ': "ae", 'œ': "oe", 'ß': "ss", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+}
+
+// ArtistOverlap returns the fraction of source artists that also appear in
+// candidate, compared after normalization and regardless of order.
+func ArtistOverlap(source, candidate []string) float64 {
+	if len(source) == 0 {
+		return 0
+	}
+	set := make(map[string]struct{}, len(candidate))
+	for _, a := range candidate {
+		set[Normalize(a)] = struct{}{}
+	}
+	found := 0
+	for _, a := range source {
+		if _, ok := set[Normalize(a)]; ok {
+			found++
+		}
+	}
+	return float64(found) / float64(len(source))
+}
+
+// SameArtists reports whether a and b contain the same artists, ignoring
+// case, punctuation and order.
+func SameArtists(a, b []string) bool {
+	return len(a) == len(b) && ArtistOverlap(a, b) == 1 && ArtistOverlap(b, a) == 1
+}
+
+// -- Built-in scorers --------------------------------------------------------
+
+func catalog(source, candidate Track) float64 {
+	if source.ISRC != "" && strings.EqualFold(source.ISRC, candidate.ISRC) {
+		return 1.0
+	}
+
+	score := 0.0
+	name, candidateName := Normalize(source.Name), Normalize(candidate.Name)
+	if name == candidateName {
+		score += 0.5
+	} else if containsWords(candidateName, name) {
+		score += 0.3
+	}
+
+	// Artists compare as sets, so "A, B" and "B, A" are an exact match.
+	if SameArtists(source.Artists, candidate.Artists) {
+		score += 0.35
+	} else {
+		score += 0.2 * ArtistOverlap(source.Artists, candidate.Artists)
+	}
+
+	if source.Album != "" && Normalize(source.Album) == Normalize(candidate.Album) {
+		score += 0.15
+	}
+
+	return min(score, 1.0)
+}
+
+func title(source, candidate Track) float64 {
+	score := 0.0
+	name, candidateTitle := Normalize(source.Name), Normalize(candidate.Name)
+
+	if containsWords(candidateTitle, name) {
+		score += 0.5
+	} else if words := strings.Fields(name); len(words) > 0 {
+		// Partial credit for the significant words found in the title.
+		found := 0
+		for _, w := range words {
+			if len(w) > 2 && strings.Contains(candidateTitle, w) {
+				found++
+			}
+		}
+		score += 0.5 * float64(found) / float64(len(words))
+	}
+
+	if len(source.Artists) > 0 {
+		uploader := Normalize(strings.Join(candidate.Artists, " "))
+		found := 0
+		for _, a := range source.Artists {
+			a = Normalize(a)
+			if a != "" && (containsWords(uploader, a) || containsWords(candidateTitle, a)) {
+				found++
+			}
+		}
+		score += 0.4 * float64(found) / float64(len(source.Artists))
+	}
+
+	if name == candidateTitle {
+		score += 0.1
+	}
+
+	return min(score, 1.0)
+}
+
+func catalogEpisode(source, candidate Track) float64 {
+	name := 0.0
+	sourceName, candidateName := Normalize(source.Name), Normalize(candidate.Name)
+	if sourceName == candidateName {
+		name = 1.0
+	} else if containsWords(candidateName, sourceName) {
+		name = 0.7
+	}
+
+	if source.Show == "" || candidate.Show == "" {
+		return 0.9 * name
+	}
+
+	score := 0.7 * name
+	if Normalize(source.Show) == Normalize(candidate.Show) {
+		score += 0.3
+	}
+	return score
+}
+
+func titleEpisode(source, candidate Track) float64 {
+	score := 0.0
+	candidateTitle := Normalize(candidate.Name)
+
+	if name := Normalize(source.Name); name != "" && containsWords(candidateTitle, name) {
+		score += 0.6
+	}
+	if show := Normalize(source.Show); show != "" &&
+		(containsWords(candidateTitle, show) || containsWords(Normalize(candidate.Show), show)) {
+		score += 0.4
+	}
+	return score
+}
+
+// containsWords reports whether the normalized text s contains the
+// normalized phrase sub on word boundaries, so "love" is not found in
+// "glove". An empty phrase is never contained.
+func containsWords(s, sub string) bool {
+	if sub == "" {
+		return false
+	}
+	return strings.Contains(" "+s+" ", " "+sub+" ")
+}
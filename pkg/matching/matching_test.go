@@ -0,0 +1,103 @@
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "ac dc", Normalize("AC/DC"))
+	assert.Equal(t, "ac dc", Normalize("  ac-dc "))
+	assert.Equal(t, "don t stop me now", Normalize("Don't Stop Me Now!"))
+	assert.Equal(t, "sigur ros", Normalize("Sigur Rós"))
+	assert.Equal(t, "", Normalize(" - "))
+	assert.Equal(t, "beyonce", Normalize("BEYONCÉ"))
+	assert.Equal(t, "abc 123", Normalize("ＡＢＣ　１２３"))
+	assert.Equal(t, "motorhead", Normalize("Motörhead"))
+	assert.Equal(t, "oe", Normalize("Œ"))
+	assert.Equal(t, "がくせい", Normalize("がくせい"))
+}
+
+func TestArtistOverlap(t *testing.T) {
+	assert.Equal(t, 1.0, ArtistOverlap([]string{"A", "B"}, []string{"b", "a"}))
+	assert.Equal(t, 0.5, ArtistOverlap([]string{"A", "B"}, []string{"A"}))
+	assert.Equal(t, 0.0, ArtistOverlap(nil, []string{"A"}))
+	assert.Equal(t, 1.0, ArtistOverlap([]string{"AC/DC"}, []string{"ac-dc"}))
+}
+
+func TestSameArtists(t *testing.T) {
+	assert.True(t, SameArtists([]string{"Daft Punk", "Pharrell Williams"}, []string{"pharrell williams", "Daft Punk"}))
+	assert.False(t, SameArtists([]string{"Daft Punk"}, []string{"Daft Punk", "Pharrell Williams"}))
+}
+
+func TestCatalog(t *testing.T) {
+	source := Track{Name: "Get Lucky", Artists: []string{"Daft Punk", "Pharrell Williams"}, Album: "Random Access Memories", ISRC: "USQX91300108"}
+
+	assert.Equal(t, 1.0, Score(source, Track{Name: "Something else", ISRC: "usqx91300108"}))
+	assert.Equal(t, 1.0, Score(source, Track{Name: "get lucky", Artists: []string{"Pharrell Williams", "Daft Punk"}, Album: "Random Access Memories"}))
+	assert.InDelta(t, 0.6, Score(source, Track{Name: "Get Lucky", Artists: []string{"Daft Punk"}}), 1e-9)
+	assert.InDelta(t, 0.65, Score(source, Track{Name: "Get Lucky (Radio Edit)", Artists: []string{"Daft Punk", "Pharrell Williams"}}), 1e-9)
+	// "Lucky" is not found inside another word.
+	assert.Equal(t, 0.0, Score(Track{Name: "Lucky"}, Track{Name: "Unlucky"}))
+	assert.Equal(t, 0.0, Score(Track{}, Track{Name: "Anything"}))
+}
+
+func TestTitle(t *testing.T) {
+	source := Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}}
+
+	assert.Equal(t, 0.9, Title.Score(source, Track{Name: "Queen - Bohemian Rhapsody", Artists: []string{"Queen Official"}}))
+	assert.Equal(t, 1.0, Title.Score(source, Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}}))
+	assert.Equal(t, 0.25, Title.Score(source, Track{Name: "Rhapsody in Blue", Artists: []string{"Gershwin"}}))
+}
+
+func TestEpisodeScorers(t *testing.T) {
+	source := Track{Name: "Episode 12", Show: "The Daily"}
+
+	assert.Equal(t, 1.0, CatalogEpisode.Score(source, Track{Name: "episode 12", Show: "The Daily"}))
+	assert.Equal(t, 0.9, CatalogEpisode.Score(source, Track{Name: "Episode 12"}))
+	assert.InDelta(t, 0.49, CatalogEpisode.Score(source, Track{Name: "Episode 12 - Part 2", Show: "Other"}), 1e-9)
+
+	assert.Equal(t, 1.0, TitleEpisode.Score(source, Track{Name: "Episode 12 | Full", Show: "The Daily"}))
+	assert.Equal(t, 0.6, TitleEpisode.Score(source, Track{Name: "Episode 12", Show: "Reuploads"}))
+}
+
+func TestBestCandidate(t *testing.T) {
+	source := Track{Name: "Creep", Artists: []string{"Radiohead"}}
+	candidates := []Track{
+		{Name: "Creep", Artists: []string{"Stone Temple Pilots"}},
+		{Name: "Creep", Artists: []string{"Radiohead"}},
+		{Name: "Creep", Artists: []string{"radiohead"}},
+	}
+
+	i, score := BestCandidate(Catalog, source, candidates)
+	assert.Equal(t, 1, i, "ties go to the earlier candidate")
+	assert.InDelta(t, 0.85, score, 1e-9)
+
+	i, score = BestCandidate(Catalog, source, nil)
+	assert.Equal(t, -1, i)
+	assert.Equal(t, 0.0, score)
+}
+
+func TestScorerFunc(t *testing.T) {
+	isrcOnly := ScorerFunc(func(source, candidate Track) float64 {
+		if source.ISRC != "" && source.ISRC == candidate.ISRC {
+			return 1
+		}
+		return 0
+	})
+
+	i, _ := BestCandidate(isrcOnly, Track{ISRC: "X"}, []Track{{ISRC: "Y"}, {ISRC: "X"}})
+	assert.Equal(t, 1, i)
+}
+
+func TestWithISRCOnly(t *testing.T) {
+	scorer := WithISRCOnly(Catalog)
+	source := Track{Name: "Song", Artists: []string{"Artist"}, ISRC: "USRC17607839"}
+
+	assert.Equal(t, 1.0, scorer.Score(source, Track{Name: "Song (Remaster)", ISRC: "usrc17607839"}))
+	assert.Equal(t, 0.0, scorer.Score(source, Track{Name: "Song", Artists: []string{"Artist"}}))
+
+	source.ISRC = ""
+	assert.Equal(t, 0.0, scorer.Score(source, Track{Name: "Song", Artists: []string{"Artist"}}))
+}
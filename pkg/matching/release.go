@@ -0,0 +1,36 @@
+package matching
+
+import "strings"
+
+const (
+	// releaseYearPenalty is the share of its score a candidate loses for
+	// every year between its release and the source's.
+	releaseYearPenalty = 0.005
+
+	// maxReleaseYearPenalty caps the loss, so the release year only breaks
+	// ties between candidates that otherwise match about equally well.
+	maxReleaseYearPenalty = 0.05
+)
+
+// WithReleaseYear wraps base so that a candidate released in another year
+// than the source loses 0.5% of what base gives it per year apart, at most
+// 5%. Among equally named candidates the one released closest to the source
+// then wins, so a re-recording such as "Love Story (Taylor's Version)" does
+// not replace the 2008 original. A shared ISRC identifies the same recording
+// and is never penalized, nor are tracks of unknown release year.
+func WithReleaseYear(base Scorer) Scorer {
+	return ScorerFunc(func(source, candidate Track) float64 {
+		score := base.Score(source, candidate)
+		if source.ReleaseYear <= 0 || candidate.ReleaseYear <= 0 {
+			return score
+		}
+		if source.ISRC != "" && strings.EqualFold(source.ISRC, candidate.ISRC) {
+			return score
+		}
+		years := source.ReleaseYear - candidate.ReleaseYear
+		if years < 0 {
+			years = -years
+		}
+		return score * (1 - min(maxReleaseYearPenalty, releaseYearPenalty*float64(years)))
+	})
+}
@@ -0,0 +1,39 @@
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithReleaseYear(t *testing.T) {
+	scorer := WithReleaseYear(Catalog)
+	source := Track{Name: "Rock of Ages", Artists: []string{"Def Leppard"}, ReleaseYear: 1983}
+	candidate := func(year int) Track {
+		return Track{Name: "Rock of Ages", Artists: []string{"Def Leppard"}, ReleaseYear: year}
+	}
+
+	base := Catalog.Score(source, candidate(0))
+	assert.Equal(t, base, scorer.Score(source, candidate(0)), "unknown year")
+	assert.Equal(t, base, scorer.Score(source, candidate(1983)), "same year")
+	assert.InDelta(t, 0.99*base, scorer.Score(source, candidate(1985)), 0.001)
+	assert.InDelta(t, 0.95*base, scorer.Score(source, candidate(2012)), 0.001, "capped")
+
+	sameRecording := candidate(2012)
+	source.ISRC, sameRecording.ISRC = "GBF088390010", "GBF088390010"
+	assert.Equal(t, Catalog.Score(source, sameRecording), scorer.Score(source, sameRecording), "shared ISRC")
+}
+
+func TestWithReleaseYear_PrefersOriginal(t *testing.T) {
+	source := Track{Name: "Rock of Ages", Artists: []string{"Def Leppard"}, Album: "Pyromania", ReleaseYear: 1983}
+	candidates := []Track{
+		{Name: "Rock of Ages", Artists: []string{"Def Leppard"}, Album: "Rock of Ages", ReleaseYear: 2012},
+		{Name: "Rock of Ages", Artists: []string{"Def Leppard"}, Album: "Rock of Ages", ReleaseYear: 1983},
+	}
+
+	i, _ := BestCandidate(Catalog, source, candidates)
+	assert.Equal(t, 0, i, "without the tie-breaker the first candidate wins")
+
+	i, _ = BestCandidate(WithReleaseYear(Catalog), source, candidates)
+	assert.Equal(t, 1, i)
+}
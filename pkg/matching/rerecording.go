@@ -0,0 +1,86 @@
+package matching
+
+import (
+	"regexp"
+	"strings"
+)
+
+// rerecordingPattern matches a normalized qualifier that marks a
+// re-recording: "Taylor's Version" (normalized to "taylor s version"),
+// "Re-Recorded", "Rerecorded 2012", "New Recording" and similar.
+var rerecordingPattern = regexp.MustCompile(`^(?:\S+ s version|(?:.* )?(?:re ?record(?:ed|ing)?|new recording)(?: .*)?)$`)
+
+// rerecordingPenalty multiplies the score of a candidate of the kind the
+// preference does not ask for. It is mild, so that such a candidate still
+// matches when the destination has no other recording of the track.
+const rerecordingPenalty = 0.9
+
+// IsRerecording reports whether a title marks its track as a re-recording
+// of an earlier release, in a bracketed part such as "(Taylor's Version)"
+// or "[Re-Recorded]" or after a trailing " - ".
+func IsRerecording(title string) bool {
+	for _, m := range bracketPattern.FindAllStringSubmatch(title, -1) {
+		if rerecordingPattern.MatchString(Normalize(m[1])) {
+			return true
+		}
+	}
+	if m := dashPattern.FindStringSubmatch(title); m != nil {
+		return rerecordingPattern.MatchString(Normalize(m[1]))
+	}
+	return false
+}
+
+// stripRerecording removes the re-recording markers from title.
+func stripRerecording(title string) string {
+	title = bracketPattern.ReplaceAllStringFunc(title, func(part string) string {
+		if rerecordingPattern.MatchString(Normalize(part)) {
+			return ""
+		}
+		return part
+	})
+	if m := dashPattern.FindStringSubmatchIndex(title); m != nil && rerecordingPattern.MatchString(Normalize(title[m[2]:m[3]])) {
+		title = title[:m[0]]
+	}
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// rerecordingOf reports whether candidate is a re-recording of the track of
+// source rather than its original recording: its title carries a
+// re-recording marker, or it was released in a later year than source under
+// another ISRC. A candidate sharing the source's ISRC is the same recording,
+// so it is a re-recording only if the source is one.
+func rerecordingOf(source, candidate Track) bool {
+	if IsRerecording(candidate.Name) {
+		return true
+	}
+	if source.ISRC == "" || candidate.ISRC == "" {
+		return false
+	}
+	if strings.EqualFold(source.ISRC, candidate.ISRC) {
+		return IsRerecording(source.Name)
+	}
+	return source.ReleaseYear > 0 && candidate.ReleaseYear > source.ReleaseYear
+}
+
+// WithRerecordings wraps base to choose between the original recording of a
+// track and a re-recording of it, such as "Love Story" and "Love Story
+// (Taylor's Version)". Names are compared without their re-recording
+// markers, and candidates of the kind not preferred score 90% of what base
+// gives them, so that the preferred kind wins when the destination has
+// both. With prefer re-recordings win, otherwise originals do.
+func WithRerecordings(base Scorer, prefer bool) Scorer {
+	return ScorerFunc(func(source, candidate Track) float64 {
+		rerecording := rerecordingOf(source, candidate)
+		if prefer && !IsRerecording(source.Name) {
+			// The ISRC of an original source identifies the recording not
+			// preferred, which would otherwise be a certain match.
+			source.ISRC = ""
+		}
+		source.Name, candidate.Name = stripRerecording(source.Name), stripRerecording(candidate.Name)
+		score := base.Score(source, candidate)
+		if rerecording != prefer {
+			return score * rerecordingPenalty
+		}
+		return score
+	})
+}
@@ -0,0 +1,64 @@
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRerecording(t *testing.T) {
+	rerecordings := []string{
+		"Love Story (Taylor's Version)",
+		"Love Story (Taylor’s Version)",
+		"All Too Well (10 Minute Version) (Taylor's Version) (From The Vault)",
+		"Rock of Ages [Re-Recorded]",
+		"Rock of Ages (Rerecorded 2012)",
+		"Tainted Love - New Recording",
+	}
+	for _, title := range rerecordings {
+		assert.True(t, IsRerecording(title), title)
+	}
+
+	originals := []string{
+		"Love Story",
+		"All Too Well (10 Minute Version)",
+		"Bohemian Rhapsody (Live Aid)",
+		"Record Year",
+		"Girls Just Want to Have Fun - Single Version",
+	}
+	for _, title := range originals {
+		assert.False(t, IsRerecording(title), title)
+	}
+}
+
+func TestStripRerecording(t *testing.T) {
+	assert.Equal(t, "Love Story", stripRerecording("Love Story (Taylor's Version)"))
+	assert.Equal(t, "Tainted Love", stripRerecording("Tainted Love - New Recording"))
+	assert.Equal(t, "Bohemian Rhapsody (Live Aid)", stripRerecording("Bohemian Rhapsody (Live Aid)"))
+}
+
+func TestWithRerecordings(t *testing.T) {
+	source := Track{Name: "Love Story", Artists: []string{"Taylor Swift"}, ISRC: "USCJY0803275", ReleaseYear: 2008}
+	original := Track{Name: "Love Story", Artists: []string{"Taylor Swift"}, ISRC: "USCJY0803275", ReleaseYear: 2008}
+	marked := Track{Name: "Love Story (Taylor's Version)", Artists: []string{"Taylor Swift"}, ISRC: "USUG12100342", ReleaseYear: 2021}
+	unmarked := Track{Name: "Love Story", Artists: []string{"Taylor Swift"}, ISRC: "USUG12100342", ReleaseYear: 2021}
+	candidates := []Track{marked, original}
+
+	avoid := WithRerecordings(Catalog, false)
+	i, _ := BestCandidate(avoid, source, candidates)
+	assert.Equal(t, 1, i)
+	assert.Less(t, avoid.Score(source, unmarked), avoid.Score(source, original), "later release under another ISRC")
+
+	prefer := WithRerecordings(Catalog, true)
+	i, _ = BestCandidate(prefer, source, candidates)
+	assert.Equal(t, 0, i)
+
+	// A re-recording source keeps its ISRC.
+	i, _ = BestCandidate(prefer, marked, candidates)
+	assert.Equal(t, 0, i)
+
+	// Markers do not count against the name.
+	noISRC := Track{Name: "Love Story", Artists: []string{"Taylor Swift"}}
+	marked.ISRC = ""
+	assert.Equal(t, Catalog.Score(noISRC, noISRC), prefer.Score(noISRC, marked))
+}
@@ -0,0 +1,226 @@
+package matching
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Transliterate romanizes Cyrillic, Greek, Japanese kana and Korean Hangul
+// in s and returns it in lowercase. Other characters, including Chinese
+// characters and Japanese kanji, which need a dictionary to romanize, are
+// kept. Romanization follows common practice (BGN/PCGN-like Cyrillic,
+// Hepburn kana, Revised Romanization of Korean) rather than any single
+// standard, since music services are not consistent either.
+func Transliterate(s string) string {
+	runes := []rune(norm.NFKC.String(s))
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r >= hangulBase && r <= hangulLast:
+			b.WriteString(romanizeHangul(r))
+		case isKana(r):
+			n := romanizeKana(&b, runes[i:])
+			i += n - 1
+		default:
+			if latin, ok := cyrillicGreek[unicode.ToLower(r)]; ok {
+				b.WriteString(latin)
+			} else if base := baseRune(r); base != r {
+				if latin, ok := cyrillicGreek[unicode.ToLower(base)]; ok {
+					b.WriteString(latin)
+					continue
+				}
+				b.WriteRune(unicode.ToLower(r))
+			} else {
+				b.WriteRune(unicode.ToLower(r))
+			}
+		}
+	}
+	return b.String()
+}
+
+// WithTransliteration wraps base so that a source and candidate written in
+// different scripts are also compared in romanized form: when either track
+// has letters outside the Latin script, the names, artists, album and show
+// of both are transliterated, long vowels folded, and the higher of the two
+// scores returned. Tracks written only in Latin letters are scored by base
+// alone.
+func WithTransliteration(base Scorer) Scorer {
+	return ScorerFunc(func(source, candidate Track) float64 {
+		score := base.Score(source, candidate)
+		if !hasNonLatin(source) && !hasNonLatin(candidate) {
+			return score
+		}
+		return max(score, base.Score(romanize(source), romanize(candidate)))
+	})
+}
+
+// romanize returns t with its text fields transliterated.
+func romanize(t Track) Track {
+	r := func(s string) string {
+		return foldRomanization(Normalize(Transliterate(s)))
+	}
+	artists := make([]string, len(t.Artists))
+	for i, a := range t.Artists {
+		artists[i] = r(a)
+	}
+	t.Name, t.Album, t.Show, t.Artists = r(t.Name), r(t.Album), r(t.Show), artists
+	return t
+}
+
+func hasNonLatin(t Track) bool {
+	for _, s := range append([]string{t.Name, t.Album, t.Show}, t.Artists...) {
+		for _, r := range s {
+			if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// baseRune returns r without its diacritics, or r itself if it has none.
+func baseRune(r rune) rune {
+	d := []rune(norm.NFD.String(string(r)))
+	if len(d) == 0 {
+		return r
+	}
+	return d[0]
+}
+
+// foldRomanization removes differences between romanizations of the same
+// word: doubled vowels and "ou" for long vowels ("toukyou", "tookyoo" and
+// "tokyo" all become "tokyo").
+func foldRomanization(s string) string {
+	return romanizationFolds.Replace(s)
+}
+
+var romanizationFolds = strings.NewReplacer("ou", "o", "oo", "o", "uu", "u", "aa", "a", "ii", "i", "ee", "e")
+
+// -- Cyrillic and Greek ------------------------------------------------------
+
+var cyrillicGreek = map[rune]string{
+	// Russian
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+	// Ukrainian, Belarusian, Serbian, Macedonian
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u", 'ђ': "dj", 'ј': "j", 'љ': "lj",
+	'њ': "nj", 'ћ': "c", 'џ': "dz", 'ѓ': "gj", 'ќ': "kj", 'ѕ': "dz",
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+}
+
+// -- Korean ------------------------------------------------------------------
+
+const (
+	hangulBase = 0xAC00
+	hangulLast = 0xD7A3
+)
+
+var (
+	hangulInitials = []string{"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h"}
+	hangulMedials  = []string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+	hangulFinals   = []string{"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l", "p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t"}
+)
+
+// romanizeHangul romanizes a precomposed Hangul syllable.
+func romanizeHangul(r rune) string {
+	i := int(r - hangulBase)
+	return hangulInitials[i/(21*28)] + hangulMedials[i%(21*28)/28] + hangulFinals[i%28]
+}
+
+// -- Japanese kana -----------------------------------------------------------
+
+func isKana(r rune) bool {
+	return (r >= 0x3041 && r <= 0x3096) || (r >= 0x30A1 && r <= 0x30FA) || r == 'ー'
+}
+
+// toHiragana maps katakana to the matching hiragana.
+func toHiragana(r rune) rune {
+	if r >= 0x30A1 && r <= 0x30F6 {
+		return r - 0x60
+	}
+	return r
+}
+
+var kana = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n", 'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa",
+}
+
+// romanizeKana writes the romanization of the kana at the start of runes
+// and returns how many runes it consumed.
+func romanizeKana(b *strings.Builder, runes []rune) int {
+	r := toHiragana(runes[0])
+	switch r {
+	case 'っ':
+		// A small tsu doubles the next consonant.
+		if len(runes) > 1 && isKana(runes[1]) {
+			var next strings.Builder
+			n := romanizeKana(&next, runes[1:])
+			if s := next.String(); s != "" && !strings.ContainsRune("aiueon", rune(s[0])) {
+				if strings.HasPrefix(s, "ch") {
+					b.WriteByte('t')
+				} else {
+					b.WriteByte(s[0])
+				}
+			}
+			b.WriteString(next.String())
+			return 1 + n
+		}
+		return 1
+	case 'ー':
+		// A long vowel mark repeats the preceding vowel.
+		if out := b.String(); out != "" && strings.ContainsRune("aiueo", rune(out[len(out)-1])) {
+			b.WriteByte(out[len(out)-1])
+		}
+		return 1
+	}
+
+	syllable, ok := kana[r]
+	if !ok {
+		b.WriteRune(runes[0])
+		return 1
+	}
+
+	// Small ya/yu/yo combine with the preceding i-kana: きゃ is "kya",
+	// しゃ is "sha" and ちゃ is "cha".
+	if len(runes) > 1 && strings.HasSuffix(syllable, "i") && len(syllable) > 1 {
+		if small := toHiragana(runes[1]); small == 'ゃ' || small == 'ゅ' || small == 'ょ' {
+			stem := strings.TrimSuffix(syllable, "i")
+			vowel := kana[small][1:]
+			if stem == "sh" || stem == "ch" || stem == "j" {
+				b.WriteString(stem + vowel)
+			} else {
+				b.WriteString(stem + "y" + vowel)
+			}
+			return 2
+		}
+	}
+	b.WriteString(syllable)
+	return 1
+}
@@ -0,0 +1,62 @@
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransliterate(t *testing.T) {
+	tests := map[string]string{
+		"Кино":             "kino",
+		"Виктор Цой":       "viktor tsoy",
+		"Щедрин":           "shchedrin",
+		"Μίκης Θεοδωράκης": "mikis theodorakis",
+		"さくら":              "sakura",
+		"トウキョウ":            "toukyou",
+		"きっと":              "kitto",
+		"マッチ":              "matchi",
+		"しゃしん":             "shashin",
+		"きょう":              "kyou",
+		"ラーメン":             "raamen",
+		"강남스타일":            "gangnamseutail",
+		"방탄소년단":            "bangtansonyeondan",
+		"東京 Tower":         "東京 tower",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, Transliterate(in), in)
+	}
+}
+
+func TestWithTransliteration(t *testing.T) {
+	scorer := WithTransliteration(Catalog)
+
+	t.Run("cyrillic against romanized", func(t *testing.T) {
+		source := Track{Name: "Группа крови", Artists: []string{"Кино"}}
+		candidate := Track{Name: "Gruppa krovi", Artists: []string{"Kino"}}
+
+		assert.Less(t, Catalog.Score(source, candidate), 0.5)
+		assert.InDelta(t, 0.85, scorer.Score(source, candidate), 0.001)
+	})
+
+	t.Run("kana against romanization with long vowels", func(t *testing.T) {
+		source := Track{Name: "トウキョウ", Artists: []string{"Artist"}}
+		candidate := Track{Name: "Tokyo", Artists: []string{"Artist"}}
+
+		assert.InDelta(t, 0.85, scorer.Score(source, candidate), 0.001)
+	})
+
+	t.Run("latin only uses base", func(t *testing.T) {
+		source := Track{Name: "Kino", Artists: []string{"A"}}
+		candidate := Track{Name: "Kino", Artists: []string{"B"}}
+
+		assert.Equal(t, Catalog.Score(source, candidate), scorer.Score(source, candidate))
+	})
+
+	t.Run("unrelated titles stay low", func(t *testing.T) {
+		source := Track{Name: "Кино", Artists: []string{"Кино"}}
+		candidate := Track{Name: "Cinema", Artists: []string{"Someone"}}
+
+		assert.Less(t, scorer.Score(source, candidate), 0.5)
+	})
+}
@@ -0,0 +1,135 @@
+package matching
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Version is the set of qualifiers that mark a recording as something other
+// than the original studio version. The zero value, VersionStudio, has
+// none.
+type Version uint8
+
+// Version qualifiers. A title can carry several, e.g. a live acoustic
+// performance.
+const (
+	VersionLive Version = 1 << iota
+	VersionRemix
+	VersionAcoustic
+	VersionCover
+
+	VersionStudio Version = 0
+)
+
+var versionNames = []struct {
+	v    Version
+	name string
+}{
+	{VersionLive, "live"},
+	{VersionRemix, "remix"},
+	{VersionAcoustic, "acoustic"},
+	{VersionCover, "cover"},
+}
+
+// String returns the qualifiers separated by spaces, e.g. "live acoustic",
+// or "studio".
+func (v Version) String() string {
+	var names []string
+	for _, n := range versionNames {
+		if v&n.v != 0 {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return "studio"
+	}
+	return strings.Join(names, " ")
+}
+
+// versionWords maps words found in a bracketed qualifier to the version
+// they mark.
+var versionWords = map[string]Version{
+	"live":      VersionLive,
+	"remix":     VersionRemix,
+	"remixed":   VersionRemix,
+	"rmx":       VersionRemix,
+	"mix":       VersionRemix,
+	"acoustic":  VersionAcoustic,
+	"unplugged": VersionAcoustic,
+	"cover":     VersionCover,
+}
+
+var (
+	// bracketPattern matches a parenthesized or bracketed part of a title.
+	bracketPattern = regexp.MustCompile(`[\(\[]([^\)\]]*)[\)\]]`)
+
+	// dashPattern matches the part of a title after its last " - ".
+	dashPattern = regexp.MustCompile(`\s[-–—]\s+([^-–—]+)$`)
+
+	// dashQualifierPattern matches the part after a final " - " when it is
+	// only a qualifier, so that "Live at Wembley" counts but a title such
+	// as "Oasis - Live Forever" does not.
+	dashQualifierPattern = regexp.MustCompile(`^(?:(?:live|acoustic|unplugged|cover)(?: (?:version|session|recording|at|from|in|on)(?: .*)?)?|(?:.* )?(?:remix|rmx|mix)|.* (?:remix|cover)(?: version)?)$`)
+)
+
+// ParseVersion detects version qualifiers in a track title: "(Live)",
+// "[Remix]", "(Acoustic Version)", "(Cover)" and similar bracketed parts,
+// or a trailing " - Live at Wembley" or " - Skrillex Remix". Remastered and
+// edited versions count as studio recordings, and so does "(Original Mix)".
+func ParseVersion(title string) Version {
+	var v Version
+	for _, m := range bracketPattern.FindAllStringSubmatch(title, -1) {
+		v |= qualifierVersion(Normalize(m[1]))
+	}
+	if m := dashPattern.FindStringSubmatch(title); m != nil {
+		if q := Normalize(bracketPattern.ReplaceAllString(m[1], "")); dashQualifierPattern.MatchString(q) {
+			v |= qualifierVersion(q)
+		}
+	}
+	return v
+}
+
+// qualifierVersion returns the version marked by the words of a normalized
+// qualifier.
+func qualifierVersion(q string) Version {
+	if q == "original mix" {
+		return VersionStudio
+	}
+	var v Version
+	for _, word := range strings.Fields(q) {
+		v |= versionWords[word]
+	}
+	return v
+}
+
+// versionPenalty multiplies the score of a candidate whose version differs
+// from the source.
+const versionPenalty = 0.3
+
+// WithVersions wraps base so that a candidate whose version qualifiers
+// differ from the source, such as a live recording for a studio track,
+// scores only 30% of what base gives it. A shared ISRC identifies the same
+// recording and is never penalized.
+func WithVersions(base Scorer) Scorer {
+	return versionScorer(base, versionPenalty)
+}
+
+// WithStrictVersions is like WithVersions but scores a candidate with
+// different version qualifiers 0, so a studio track is never matched to a
+// live recording, remix, acoustic version or cover.
+func WithStrictVersions(base Scorer) Scorer {
+	return versionScorer(base, 0)
+}
+
+func versionScorer(base Scorer, penalty float64) Scorer {
+	return ScorerFunc(func(source, candidate Track) float64 {
+		score := base.Score(source, candidate)
+		if source.ISRC != "" && strings.EqualFold(source.ISRC, candidate.ISRC) {
+			return score
+		}
+		if ParseVersion(source.Name) != ParseVersion(candidate.Name) {
+			return score * penalty
+		}
+		return score
+	})
+}
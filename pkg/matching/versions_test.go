@@ -0,0 +1,61 @@
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := map[string]Version{
+		"Bohemian Rhapsody":                        VersionStudio,
+		"Bohemian Rhapsody (Live Aid)":             VersionLive,
+		"Bohemian Rhapsody - Live at Wembley 1986": VersionLive,
+		"Hurt [Cover]":                             VersionCover,
+		"Layla (Acoustic Version)":                 VersionAcoustic,
+		"Layla - Unplugged":                        VersionAcoustic,
+		"Levels (Skrillex Remix)":                  VersionRemix,
+		"Levels - Skrillex Remix":                  VersionRemix,
+		"Levels (Original Mix)":                    VersionStudio,
+		"Heroes - 2017 Remaster":                   VersionStudio,
+		"Oasis - Live Forever":                     VersionStudio,
+		"Oasis - Live Forever (Live)":              VersionLive,
+		"Wonderwall (Live Acoustic)":               VersionLive | VersionAcoustic,
+		"Mix Tape":                                 VersionStudio,
+		"Song (feat. Someone) [Live from Berlin]":  VersionLive,
+		"Song – Live":                              VersionLive,
+	}
+	for title, want := range tests {
+		assert.Equal(t, want, ParseVersion(title), title)
+	}
+}
+
+func TestVersion_String(t *testing.T) {
+	assert.Equal(t, "studio", VersionStudio.String())
+	assert.Equal(t, "live acoustic", (VersionLive | VersionAcoustic).String())
+}
+
+func TestWithVersions(t *testing.T) {
+	source := Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}}
+	live := Track{Name: "Bohemian Rhapsody (Live Aid)", Artists: []string{"Queen"}}
+	studio := Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, Album: "A Night at the Opera"}
+
+	t.Run("penalizes mismatched versions", func(t *testing.T) {
+		scorer := WithVersions(Catalog)
+
+		assert.InDelta(t, 0.3*Catalog.Score(source, live), scorer.Score(source, live), 0.001)
+		assert.Equal(t, Catalog.Score(source, studio), scorer.Score(source, studio))
+		assert.Equal(t, Catalog.Score(live, live), scorer.Score(live, live))
+	})
+
+	t.Run("strict refuses mismatched versions", func(t *testing.T) {
+		assert.Equal(t, 0.0, WithStrictVersions(Catalog).Score(source, live))
+	})
+
+	t.Run("shared ISRC is not penalized", func(t *testing.T) {
+		source := Track{Name: "Song", ISRC: "X"}
+		candidate := Track{Name: "Song (Live)", ISRC: "x"}
+
+		assert.Equal(t, 1.0, WithStrictVersions(Catalog).Score(source, candidate))
+	})
+}
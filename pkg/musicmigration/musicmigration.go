@@ -0,0 +1,147 @@
+// Package musicmigration embeds playlist migrations in other Go programs
+// without running the HTTP server. It exposes the migration Service, the
+// provider Registry and the built-in providers of this module; the types are
+// aliases of the internal ones, so values pass between them unchanged.
+//
+// A program registers the providers it uses, creates a Service and calls it
+// with provider access tokens it obtained itself:
+//
+//	registry := musicmigration.NewRegistry()
+//	registry.Register(musicmigration.NewSpotifyProvider(http.DefaultClient))
+//	registry.Register(musicmigration.NewYouTubeProvider(http.DefaultClient))
+//
+//	svc := musicmigration.NewService(registry, 4,
+//		musicmigration.WithTimeouts(musicmigration.Timeouts{Migration: 10 * time.Minute}))
+//	result, err := svc.MigratePlaylist(ctx, musicmigration.MigrationRequest{
+//		SourceProvider: "spotify",
+//		SourceToken:    spotifyToken,
+//		PlaylistID:     "37i9dQZF1DXcBWIGoYBM5M",
+//		DestProvider:   "youtube",
+//		DestToken:      youtubeToken,
+//	})
+//
+// Custom providers implement Provider and are registered the same way.
+// Migration results are kept in memory unless WithMigrationStore sets
+// another store.
+package musicmigration
+
+import (
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/memory"
+	"github.com/jpp0ca/MusicMigration-API/internal/app"
+	"github.com/jpp0ca/MusicMigration-API/internal/domain"
+	"github.com/jpp0ca/MusicMigration-API/internal/ports"
+)
+
+// Service runs migrations between the providers of its Registry. Its
+// methods are safe for concurrent use.
+type Service = app.Service
+
+// Registry holds the providers a Service can migrate between, by name.
+type Registry = adapters.ProviderRegistry
+
+// Provider is a music service a Service can read playlists from and write
+// playlists to.
+type Provider = ports.MusicProvider
+
+// Stores and hooks a Service can be configured with.
+type (
+	MigrationStore    = ports.MigrationStore
+	TrackMappingStore = ports.TrackMappingStore
+	MigrationHook     = ports.MigrationHook
+)
+
+// Models passed to and returned by a Service.
+type (
+	Track                   = domain.Track
+	Playlist                = domain.Playlist
+	PageRequest             = domain.PageRequest
+	PlaylistPage            = domain.PlaylistPage
+	PlaylistUpdate          = domain.PlaylistUpdate
+	AddOutcome              = domain.AddOutcome
+	MigrationRequest        = domain.MigrationRequest
+	MigrationResult         = domain.MigrationResult
+	MigrationPreview        = domain.MigrationPreview
+	TrackResult             = domain.TrackResult
+	TrackFilter             = domain.TrackFilter
+	MatchingStrategy        = domain.MatchingStrategy
+	MergeRequest            = domain.MergeRequest
+	MergeSource             = domain.MergeSource
+	MergeOrder              = domain.MergeOrder
+	ReverseMigrationRequest = domain.ReverseMigrationRequest
+	AccountExport           = domain.AccountExport
+)
+
+// Errors a Service and the providers report, to be checked with errors.Is.
+var (
+	ErrProviderNotFound    = domain.ErrProviderNotFound
+	ErrPlaylistNotFound    = domain.ErrPlaylistNotFound
+	ErrMigrationNotFound   = domain.ErrMigrationNotFound
+	ErrInvalidToken        = domain.ErrInvalidToken
+	ErrInsufficientScope   = domain.ErrInsufficientScope
+	ErrRateLimited         = domain.ErrRateLimited
+	ErrQuotaExceeded       = domain.ErrQuotaExceeded
+	ErrSourceOnlyProvider  = domain.ErrSourceOnlyProvider
+	ErrPlaylistLocked      = domain.ErrPlaylistLocked
+	ErrPlaylistExists      = domain.ErrPlaylistExists
+	ErrUnavailableInMarket = domain.ErrUnavailableInMarket
+	ErrUnavailable         = domain.ErrUnavailable
+	ErrTimeout             = domain.ErrTimeout
+)
+
+// Option configures optional dependencies of a Service.
+type Option = app.Option
+
+// ProgressFunc is called each time a track search finishes, with the number
+// of tracks processed so far and the total number of tracks.
+type ProgressFunc = app.ProgressFunc
+
+// Timeouts bounds the provider calls made by a migration. Zero means no
+// timeout.
+type Timeouts = app.Timeouts
+
+// NewRegistry returns an empty provider registry.
+func NewRegistry() *Registry {
+	return adapters.NewProviderRegistry()
+}
+
+// NewService creates a migration service for the providers of registry that
+// matches tracks with the given number of concurrent workers.
+func NewService(registry *Registry, workers int, opts ...Option) *Service {
+	defaults := []Option{app.WithMigrationStore(memory.NewMigrationStore()), app.WithLocker(memory.NewLocker())}
+	return app.NewService(registry, workers, append(defaults, opts...)...)
+}
+
+// WithProgress registers a callback that reports track matching progress.
+func WithProgress(fn ProgressFunc) Option {
+	return app.WithProgress(fn)
+}
+
+// WithMigrationStore sets the store used to persist migration results.
+// Defaults to an in-memory store.
+func WithMigrationStore(store MigrationStore) Option {
+	return app.WithMigrationStore(store)
+}
+
+// WithTrackMappings makes migrations record every match as a cross-provider
+// track mapping and reuse known mappings instead of searching.
+func WithTrackMappings(mappings TrackMappingStore) Option {
+	return app.WithTrackMappings(mappings)
+}
+
+// WithTimeouts sets per-stage and overall migration timeouts.
+func WithTimeouts(t Timeouts) Option {
+	return app.WithTimeouts(t)
+}
+
+// NewTrackMappingStore creates an empty in-memory track mapping store for
+// WithTrackMappings.
+func NewTrackMappingStore() TrackMappingStore {
+	return memory.NewTrackMappingStore()
+}
+
+// WithHooks registers actions to run after each completed migration. Hooks
+// run in the background, so they never delay or fail the migration.
+func WithHooks(hooks ...MigrationHook) Option {
+	return app.WithHooks(hooks...)
+}
@@ -0,0 +1,104 @@
+package musicmigration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpp0ca/MusicMigration-API/pkg/musicmigration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crate is a read-only provider written against the exported types only, as
+// a program embedding the package would write it.
+type crate struct {
+	tracks []musicmigration.Track
+}
+
+func (c *crate) Name() string { return "crate" }
+
+func (c *crate) GetPlaylists(context.Context, string) ([]musicmigration.Playlist, error) {
+	return []musicmigration.Playlist{{ID: "crate-1", Name: "Crate", TrackCount: len(c.tracks)}}, nil
+}
+
+func (c *crate) GetPlaylistsPage(ctx context.Context, token string, _ musicmigration.PageRequest) (*musicmigration.PlaylistPage, error) {
+	playlists, err := c.GetPlaylists(ctx, token)
+	return &musicmigration.PlaylistPage{Items: playlists}, err
+}
+
+func (c *crate) GetPlaylist(_ context.Context, _ string, playlistID string) (*musicmigration.Playlist, error) {
+	if playlistID != "crate-1" {
+		return nil, musicmigration.ErrPlaylistNotFound
+	}
+	return &musicmigration.Playlist{ID: playlistID, Name: "Crate", TrackCount: len(c.tracks)}, nil
+}
+
+func (c *crate) GetPlaylistTracks(context.Context, string, string) ([]musicmigration.Track, error) {
+	return c.tracks, nil
+}
+
+func (c *crate) SearchTrack(context.Context, string, musicmigration.Track) (*musicmigration.Track, float64, error) {
+	return nil, 0, nil
+}
+
+func (c *crate) CreatePlaylist(context.Context, string, string, string) (string, error) {
+	return "", musicmigration.ErrSourceOnlyProvider
+}
+
+func (c *crate) AddTracksToPlaylist(context.Context, string, string, []string) ([]musicmigration.AddOutcome, error) {
+	return nil, musicmigration.ErrSourceOnlyProvider
+}
+
+func (c *crate) RemoveTracksFromPlaylist(context.Context, string, string, []string) error {
+	return musicmigration.ErrSourceOnlyProvider
+}
+
+func (c *crate) UpdatePlaylistDetails(context.Context, string, string, musicmigration.PlaylistUpdate) error {
+	return musicmigration.ErrSourceOnlyProvider
+}
+
+func (c *crate) DeletePlaylist(context.Context, string, string) error {
+	return musicmigration.ErrSourceOnlyProvider
+}
+
+func TestEmbeddedMigration(t *testing.T) {
+	registry := musicmigration.NewRegistry()
+	registry.Register(&crate{tracks: []musicmigration.Track{
+		{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, ExternalID: "c1"},
+		{Name: "Unknown Demo", Artists: []string{"Nobody"}, ExternalID: "c2"},
+	}})
+	registry.Register(musicmigration.NewSandboxProvider())
+
+	var done, total int
+	svc := musicmigration.NewService(registry, 2,
+		musicmigration.WithProgress(func(d, t int) { done, total = d, t }),
+		musicmigration.WithTrackMappings(musicmigration.NewTrackMappingStore()),
+	)
+
+	result, err := svc.MigratePlaylist(context.Background(), musicmigration.MigrationRequest{
+		SourceProvider: "crate",
+		SourceToken:    "unused",
+		PlaylistID:     "crate-1",
+		DestProvider:   "sandbox",
+		DestToken:      "sandbox-token",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.TotalTracks)
+	assert.Equal(t, 2, result.MatchedTracks)
+	assert.NotEmpty(t, result.DestPlaylistID)
+	assert.Equal(t, 2, done)
+	assert.Equal(t, 2, total)
+
+	playlist, err := svc.GetPlaylist(context.Background(), "sandbox", "sandbox-token", result.DestPlaylistID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, playlist.TrackCount)
+
+	_, err = svc.MigratePlaylist(context.Background(), musicmigration.MigrationRequest{
+		SourceProvider: "sandbox",
+		SourceToken:    "sandbox-token",
+		PlaylistID:     "sandbox-rock",
+		DestProvider:   "deezer",
+		DestToken:      "token",
+	})
+	assert.ErrorIs(t, err, musicmigration.ErrProviderNotFound)
+}
@@ -0,0 +1,70 @@
+package musicmigration
+
+import (
+	"net/http"
+
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/lastfm"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/localfiles"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/m3u"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/sandbox"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/spotify"
+	"github.com/jpp0ca/MusicMigration-API/internal/adapters/youtube"
+)
+
+// SpotifyOption configures optional behavior of the Spotify provider.
+type SpotifyOption = spotify.Option
+
+// WithSpotifyClientCredentials makes searches use an app-level token of the
+// given Spotify app instead of the user's token. The credentials are also
+// used to refresh user tokens.
+func WithSpotifyClientCredentials(clientID, clientSecret string) SpotifyOption {
+	return spotify.WithClientCredentials(clientID, clientSecret)
+}
+
+// NewSpotifyProvider creates the "spotify" provider. If client is nil,
+// http.DefaultClient is used.
+func NewSpotifyProvider(client *http.Client, opts ...SpotifyOption) Provider {
+	return spotify.NewProvider(client, opts...)
+}
+
+// YouTubeOption configures optional behavior of the YouTube provider.
+type YouTubeOption = youtube.Option
+
+// WithYouTubeOAuthClient sets the Google OAuth client the users' tokens
+// were issued to, which is needed to refresh them.
+func WithYouTubeOAuthClient(clientID, clientSecret string) YouTubeOption {
+	return youtube.WithOAuthClient(clientID, clientSecret)
+}
+
+// NewYouTubeProvider creates the "youtube" provider. If client is nil,
+// http.DefaultClient is used.
+func NewYouTubeProvider(client *http.Client, opts ...YouTubeOption) Provider {
+	return youtube.NewProvider(client, opts...)
+}
+
+// NewLastFMProvider creates the "lastfm" provider, which reads the loved
+// and top tracks of the Last.fm user passed as token. It can only be a
+// migration source. If client is nil, http.DefaultClient is used.
+func NewLastFMProvider(client *http.Client, apiKey string) Provider {
+	return lastfm.NewProvider(client, apiKey)
+}
+
+// NewLocalFilesProvider creates the "localfiles" provider, which serves the
+// MP3 and FLAC files below root as playlists. It can only be a migration
+// source.
+func NewLocalFilesProvider(root string) Provider {
+	return localfiles.NewProvider(root)
+}
+
+// NewM3UProvider creates the "m3u" provider, which serves the M3U and M3U8
+// playlist files in dir. It can only be a migration source.
+func NewM3UProvider(dir string) Provider {
+	return m3u.NewProvider(m3u.WithDirectory(dir))
+}
+
+// NewSandboxProvider creates the "sandbox" provider, which keeps sample
+// playlists in memory and accepts any non-empty token, for trying out
+// migrations without accounts.
+func NewSandboxProvider() Provider {
+	return sandbox.NewProvider()
+}